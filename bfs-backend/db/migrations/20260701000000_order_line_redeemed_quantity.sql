@@ -0,0 +1,6 @@
+ALTER TABLE order_line ADD COLUMN redeemed_quantity INTEGER NOT NULL DEFAULT 0;
+
+UPDATE order_line ol
+SET redeemed_quantity = ol.quantity
+FROM order_line_redemption r
+WHERE r.order_line_id = ol.id;
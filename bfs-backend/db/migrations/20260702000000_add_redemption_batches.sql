@@ -0,0 +1,25 @@
+-- Station redemptions are recorded per request so a wrong scan can be undone.
+-- The undo columns form the audit trail (who undid it from which device, and why).
+
+CREATE TABLE redemption_batch (
+    id                  VARCHAR(36) PRIMARY KEY,
+    order_id            VARCHAR(36) NOT NULL REFERENCES "order" (id) ON DELETE CASCADE,
+    station_device_id   VARCHAR(36) NULL REFERENCES device (id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    undone_at           TIMESTAMPTZ NULL,
+    undone_by_device_id VARCHAR(36) NULL REFERENCES device (id) ON DELETE SET NULL,
+    undone_by_user_id   TEXT NULL REFERENCES "user" (id) ON DELETE SET NULL,
+    undo_reason         VARCHAR(200) NULL
+);
+
+CREATE INDEX idx_redemption_batch_order_id ON redemption_batch (order_id);
+CREATE INDEX idx_redemption_batch_undone_at ON redemption_batch (undone_at);
+
+CREATE TABLE redemption_batch_item (
+    id            VARCHAR(36) PRIMARY KEY,
+    batch_id      VARCHAR(36) NOT NULL REFERENCES redemption_batch (id) ON DELETE CASCADE,
+    order_line_id VARCHAR(36) NOT NULL REFERENCES order_line (id) ON DELETE CASCADE,
+    quantity      INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX idx_redemption_batch_item_batch_id ON redemption_batch_item (batch_id);
@@ -0,0 +1,3 @@
+-- Stations keep accepting unsigned QR codes (raw order IDs, CAMP:<token>)
+-- until an admin switches this off once all printed codes have been replaced.
+ALTER TABLE settings ADD COLUMN accept_legacy_qr BOOLEAN NOT NULL DEFAULT true;
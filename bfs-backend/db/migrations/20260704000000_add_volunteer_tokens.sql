@@ -0,0 +1,22 @@
+-- Personal staff-meal QR codes issued from an imported volunteer roster.
+-- Each token has its own redemption limit; redemptions through it do not count
+-- towards the campaign's shared QR limit.
+CREATE TABLE volunteer_token (
+    id               VARCHAR(36) PRIMARY KEY,
+    campaign_id      VARCHAR(36) NOT NULL REFERENCES volunteer_campaign (id) ON DELETE CASCADE,
+    name             VARCHAR(100) NOT NULL,
+    email            VARCHAR(255) NULL,
+    token            VARCHAR(36) NOT NULL UNIQUE,
+    max_redemptions  INTEGER NOT NULL DEFAULT 1,
+    redemption_count INTEGER NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT volunteer_token_redeem_cap_ck CHECK (redemption_count <= max_redemptions),
+    CONSTRAINT volunteer_token_max_redemptions_positive_ck CHECK (max_redemptions > 0)
+);
+
+CREATE UNIQUE INDEX idx_volunteer_token_campaign_name ON volunteer_token (campaign_id, name);
+
+ALTER TABLE volunteer_redemption
+    ADD COLUMN volunteer_token_id VARCHAR(36) NULL REFERENCES volunteer_token (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_volunteer_redemption_volunteer_token_id ON volunteer_redemption (volunteer_token_id);
@@ -0,0 +1,33 @@
+-- Recurring daily meal windows (Europe/Zurich) and per-day / per-window limits
+-- for staff meal campaigns. Limits apply to the shared QR as a whole and to
+-- each personal token separately; NULL means unlimited.
+ALTER TABLE volunteer_campaign
+    ADD COLUMN max_redemptions_per_day INTEGER NULL,
+    ADD COLUMN max_per_person_per_day  INTEGER NULL,
+    ADD CONSTRAINT volunteer_campaign_max_per_day_positive_ck
+        CHECK (max_redemptions_per_day IS NULL OR max_redemptions_per_day > 0),
+    ADD CONSTRAINT volunteer_campaign_max_per_person_per_day_positive_ck
+        CHECK (max_per_person_per_day IS NULL OR max_per_person_per_day > 0);
+
+CREATE TABLE volunteer_campaign_window (
+    id              VARCHAR(36) PRIMARY KEY,
+    campaign_id     VARCHAR(36) NOT NULL REFERENCES volunteer_campaign (id) ON DELETE CASCADE,
+    label           VARCHAR(50) NOT NULL DEFAULT '',
+    weekdays        INTEGER NOT NULL DEFAULT 127,
+    start_minute    INTEGER NOT NULL,
+    end_minute      INTEGER NOT NULL,
+    max_redemptions INTEGER NULL,
+    max_per_person  INTEGER NULL,
+    CONSTRAINT volunteer_campaign_window_weekdays_ck CHECK (weekdays > 0 AND weekdays < 128),
+    CONSTRAINT volunteer_campaign_window_range_ck
+        CHECK (start_minute >= 0 AND end_minute <= 1440 AND start_minute < end_minute),
+    CONSTRAINT volunteer_campaign_window_max_positive_ck
+        CHECK (max_redemptions IS NULL OR max_redemptions > 0),
+    CONSTRAINT volunteer_campaign_window_max_per_person_positive_ck
+        CHECK (max_per_person IS NULL OR max_per_person > 0)
+);
+
+CREATE INDEX idx_volunteer_campaign_window_campaign_id ON volunteer_campaign_window (campaign_id);
+
+-- Window and day limits count redemptions by time.
+CREATE INDEX idx_volunteer_redemption_campaign_created_at ON volunteer_redemption (campaign_id, created_at);
@@ -0,0 +1,19 @@
+-- Choice groups for staff meal campaigns: "one main from these 3, one drink
+-- from these 5". The station picks one option per group at redemption time.
+CREATE TABLE volunteer_campaign_choice_group (
+    id          VARCHAR(36) PRIMARY KEY,
+    campaign_id VARCHAR(36) NOT NULL REFERENCES volunteer_campaign (id) ON DELETE CASCADE,
+    name        VARCHAR(50) NOT NULL,
+    quantity    INTEGER NOT NULL DEFAULT 1,
+    position    INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT volunteer_campaign_choice_group_quantity_positive_ck CHECK (quantity > 0)
+);
+
+CREATE INDEX idx_volunteer_campaign_choice_group_campaign_id ON volunteer_campaign_choice_group (campaign_id);
+
+CREATE TABLE volunteer_campaign_choice_option (
+    group_id   VARCHAR(36) NOT NULL REFERENCES volunteer_campaign_choice_group (id) ON DELETE CASCADE,
+    product_id VARCHAR(36) NOT NULL REFERENCES product (id) ON DELETE RESTRICT,
+    position   INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (group_id, product_id)
+);
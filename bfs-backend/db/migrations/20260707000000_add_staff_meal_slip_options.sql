@@ -0,0 +1,10 @@
+-- Printed staff meal slips: an optional campaign logo and numbered single-use
+-- slips. A numbered slip is a volunteer token with a serial instead of a
+-- roster entry, so each printed bon can be redeemed exactly once.
+ALTER TABLE volunteer_campaign
+    ADD COLUMN logo_url VARCHAR(1024);
+
+ALTER TABLE volunteer_token
+    ADD COLUMN serial INTEGER;
+
+CREATE UNIQUE INDEX idx_volunteer_token_campaign_serial ON volunteer_token (campaign_id, serial);
@@ -0,0 +1,33 @@
+-- Local Club100 member directory, synced from the Elvanto group on a schedule
+-- so POS lookups no longer call Elvanto (which only returned the first 100
+-- members). Members who leave the group stay with active = false.
+CREATE TABLE club100_member (
+    id                VARCHAR(36) PRIMARY KEY,
+    elvanto_person_id VARCHAR(50) NOT NULL,
+    first_name        VARCHAR(100) NOT NULL,
+    last_name         VARCHAR(100) NOT NULL,
+    preferred_name    VARCHAR(100),
+    active            BOOLEAN NOT NULL DEFAULT TRUE,
+    synced_at         TIMESTAMPTZ NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_club100_member_elvanto_person_id ON club100_member (elvanto_person_id);
+CREATE INDEX idx_club100_member_active ON club100_member (active);
+
+CREATE TYPE club100_sync_trigger AS ENUM ('scheduled', 'manual');
+CREATE TYPE club100_sync_status AS ENUM ('running', 'succeeded', 'failed');
+
+CREATE TABLE club100_sync_run (
+    id            VARCHAR(36) PRIMARY KEY,
+    trigger       club100_sync_trigger NOT NULL,
+    status        club100_sync_status NOT NULL DEFAULT 'running',
+    member_count  INTEGER NOT NULL DEFAULT 0,
+    added_count   INTEGER NOT NULL DEFAULT 0,
+    removed_count INTEGER NOT NULL DEFAULT 0,
+    error         VARCHAR(1000),
+    started_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at   TIMESTAMPTZ
+);
+
+CREATE INDEX idx_club100_sync_run_started_at ON club100_sync_run (started_at);
@@ -0,0 +1,17 @@
+-- Club100 entitlement periods (per event, per calendar year, ...). Remaining
+-- free products are counted within the period active at the time; without an
+-- active period the allowance keeps resetting every Europe/Zurich day.
+CREATE TABLE club100_period (
+    id         VARCHAR(36) PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT club100_period_range CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_club100_period_starts_at ON club100_period (starts_at);
+
+-- Per-period reports scan redemptions by time.
+CREATE INDEX idx_club100_redemption_created_at ON club100_redemption (created_at);
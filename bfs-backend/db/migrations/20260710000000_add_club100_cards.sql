@@ -0,0 +1,14 @@
+-- Printed Club100 member cards. The QR code carries the signed token; the
+-- POS resolves it to the member instead of scrolling the name list. At most
+-- one card per member is not revoked.
+CREATE TABLE club100_card (
+    id         VARCHAR(36) PRIMARY KEY,
+    member_id  VARCHAR(36) NOT NULL REFERENCES club100_member (id) ON DELETE CASCADE,
+    token      VARCHAR(36) NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_club100_card_token ON club100_card (token);
+CREATE INDEX idx_club100_card_member_id ON club100_card (member_id);
+CREATE UNIQUE INDEX idx_club100_card_member_active ON club100_card (member_id) WHERE revoked_at IS NULL;
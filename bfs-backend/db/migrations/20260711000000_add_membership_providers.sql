@@ -0,0 +1,14 @@
+-- Club100 members can come from other directories than Elvanto
+-- (MEMBERSHIP_PROVIDER). Sync runs record which one they read, and member
+-- lists uploaded for the CSV provider are stored here.
+ALTER TABLE club100_sync_run ADD COLUMN provider VARCHAR(20) NOT NULL DEFAULT 'elvanto';
+
+CREATE TABLE membership_upload (
+    id           VARCHAR(36) PRIMARY KEY,
+    filename     VARCHAR(255) NOT NULL,
+    content      TEXT NOT NULL,
+    member_count INTEGER NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_membership_upload_created_at ON membership_upload (created_at);
@@ -0,0 +1,34 @@
+-- Opening hours that open and close the food system automatically, evaluated
+-- in Europe/Zurich time. system_enabled stays the manual switch: off always
+-- closes, system_open_until opens outside the opening hours.
+ALTER TABLE settings
+    ADD COLUMN opening_hours_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN system_open_until     TIMESTAMPTZ NULL,
+    ADD COLUMN closed_message        VARCHAR(500) NOT NULL DEFAULT '';
+
+CREATE TABLE opening_hours_window (
+    id           VARCHAR(36) PRIMARY KEY,
+    label        VARCHAR(50) NOT NULL DEFAULT '',
+    weekdays     INTEGER NOT NULL DEFAULT 127,
+    start_minute INTEGER NOT NULL,
+    end_minute   INTEGER NOT NULL,
+    CONSTRAINT opening_hours_window_weekdays_ck CHECK (weekdays > 0 AND weekdays < 128),
+    CONSTRAINT opening_hours_window_range_ck
+        CHECK (start_minute >= 0 AND end_minute <= 1440 AND start_minute < end_minute)
+);
+
+-- Rows for a date replace the weekly windows on it; a row without minutes
+-- closes the date.
+CREATE TABLE opening_hours_exception (
+    id           VARCHAR(36) PRIMARY KEY,
+    date         VARCHAR(10) NOT NULL,
+    label        VARCHAR(50) NOT NULL DEFAULT '',
+    start_minute INTEGER NULL,
+    end_minute   INTEGER NULL,
+    CONSTRAINT opening_hours_exception_range_ck CHECK (
+        (start_minute IS NULL AND end_minute IS NULL)
+        OR (start_minute >= 0 AND end_minute <= 1440 AND start_minute < end_minute)
+    )
+);
+
+CREATE INDEX idx_opening_hours_exception_date ON opening_hours_exception (date);
@@ -0,0 +1,43 @@
+-- Events (summer festival, Christmas market, ...) with their own menu and
+-- prices. At most one event is active; new orders, inventory ledger entries,
+-- staff meal campaigns and Club100 redemptions are tagged with it. Rows from
+-- before events existed stay untagged.
+CREATE TABLE event (
+    id         VARCHAR(36) PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    active     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT event_range CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_event_starts_at ON event (starts_at);
+CREATE UNIQUE INDEX idx_event_single_active ON event (active) WHERE active;
+
+-- Per-event product overrides; products without a row keep the base catalog.
+CREATE TABLE event_product (
+    id          VARCHAR(36) PRIMARY KEY,
+    event_id    VARCHAR(36) NOT NULL REFERENCES event (id) ON DELETE CASCADE,
+    product_id  VARCHAR(36) NOT NULL REFERENCES product (id) ON DELETE CASCADE,
+    available   BOOLEAN NOT NULL DEFAULT TRUE,
+    price_cents BIGINT NULL,
+    CONSTRAINT event_product_price_ck CHECK (price_cents IS NULL OR price_cents >= 0)
+);
+
+CREATE UNIQUE INDEX idx_event_product_event_product ON event_product (event_id, product_id);
+
+ALTER TABLE "order"
+    ADD COLUMN event_id VARCHAR(36) NULL REFERENCES event (id) ON DELETE RESTRICT;
+ALTER TABLE inventory_ledger
+    ADD COLUMN event_id VARCHAR(36) NULL REFERENCES event (id) ON DELETE RESTRICT;
+ALTER TABLE volunteer_campaign
+    ADD COLUMN event_id VARCHAR(36) NULL REFERENCES event (id) ON DELETE SET NULL;
+ALTER TABLE club100_redemption
+    ADD COLUMN event_id VARCHAR(36) NULL REFERENCES event (id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_order_event_id ON "order" (event_id);
+CREATE INDEX idx_inventory_ledger_event_id ON inventory_ledger (event_id);
+CREATE INDEX idx_volunteer_campaign_event_id ON volunteer_campaign (event_id);
+CREATE INDEX idx_club100_redemption_event_id ON club100_redemption (event_id);
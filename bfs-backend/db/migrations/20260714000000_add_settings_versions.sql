@@ -0,0 +1,15 @@
+-- Snapshot of the settings and jetons after every change, with the acting
+-- user. Rollbacks restore a snapshot and append a new version.
+CREATE TABLE settings_version (
+    id               VARCHAR(36) PRIMARY KEY,
+    version          INTEGER NOT NULL,
+    change           VARCHAR(50) NOT NULL,
+    snapshot         JSONB NOT NULL,
+    created_by       TEXT NULL,
+    created_by_name  VARCHAR(255) NULL,
+    rolled_back_from INTEGER NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT settings_version_version_ck CHECK (version > 0)
+);
+
+CREATE UNIQUE INDEX settings_version_version_key ON settings_version (version);
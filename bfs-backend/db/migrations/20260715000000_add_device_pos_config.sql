@@ -0,0 +1,6 @@
+-- Per-device POS configuration overrides; NULL inherits the global settings.
+ALTER TABLE device
+    ADD COLUMN pos_mode             pos_fulfillment_mode NULL,
+    ADD COLUMN payment_methods      JSONB NULL,
+    ADD COLUMN visible_category_ids JSONB NULL,
+    ADD COLUMN gratis_types         JSONB NULL;
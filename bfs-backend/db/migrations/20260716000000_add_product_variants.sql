@@ -0,0 +1,36 @@
+-- Product variants (3dl/5dl, small/large) with their own price, stock, jeton
+-- and station routing. Order lines and inventory ledger entries of a variant
+-- reference it; the product's stock stays the sum over all its entries.
+CREATE TABLE product_variant (
+    id          VARCHAR(36) PRIMARY KEY,
+    product_id  VARCHAR(36) NOT NULL REFERENCES product (id) ON DELETE CASCADE,
+    name        VARCHAR(20) NOT NULL,
+    price_cents BIGINT NOT NULL DEFAULT 0,
+    jeton_id    VARCHAR(36) NULL REFERENCES jeton (id) ON DELETE SET NULL,
+    position    INTEGER NOT NULL DEFAULT 0,
+    is_active   BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT product_variant_price_ck CHECK (price_cents >= 0)
+);
+
+CREATE UNIQUE INDEX idx_product_variant_product_name ON product_variant (product_id, name);
+CREATE INDEX idx_product_variant_jeton_id ON product_variant (jeton_id);
+
+-- Stations serving a variant; variants without rows follow device_product.
+CREATE TABLE device_variant (
+    device_id  VARCHAR(36) NOT NULL REFERENCES device (id) ON DELETE RESTRICT,
+    variant_id VARCHAR(36) NOT NULL REFERENCES product_variant (id) ON DELETE CASCADE,
+    PRIMARY KEY (device_id, variant_id)
+);
+
+CREATE INDEX idx_device_variant_variant_id ON device_variant (variant_id);
+
+ALTER TABLE order_line
+    ADD COLUMN variant_id VARCHAR(36) NULL REFERENCES product_variant (id) ON DELETE RESTRICT,
+    ADD COLUMN variant_name VARCHAR(20) NULL;
+ALTER TABLE inventory_ledger
+    ADD COLUMN variant_id VARCHAR(36) NULL REFERENCES product_variant (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_order_line_variant_id ON order_line (variant_id);
+CREATE INDEX idx_inventory_ledger_variant_id ON inventory_ledger (variant_id);
@@ -0,0 +1,33 @@
+-- Time-based price rules (happy hour, end-of-day clearance) for a product or
+-- a whole category. Order lines keep the rule that priced them and the list
+-- price it discounted, for reporting.
+CREATE TYPE price_rule_kind AS ENUM ('percent', 'amount');
+
+CREATE TABLE price_rule (
+    id           VARCHAR(36) PRIMARY KEY,
+    name         VARCHAR(100) NOT NULL,
+    product_id   VARCHAR(36) NULL REFERENCES product (id) ON DELETE CASCADE,
+    category_id  VARCHAR(36) NULL REFERENCES category (id) ON DELETE CASCADE,
+    weekdays     INTEGER NOT NULL DEFAULT 127,
+    start_minute INTEGER NOT NULL,
+    end_minute   INTEGER NOT NULL,
+    kind         price_rule_kind NOT NULL,
+    value        BIGINT NOT NULL,
+    active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT price_rule_scope_ck CHECK (num_nonnulls(product_id, category_id) = 1),
+    CONSTRAINT price_rule_weekdays_ck CHECK (weekdays > 0 AND weekdays < 128),
+    CONSTRAINT price_rule_range_ck
+        CHECK (start_minute >= 0 AND end_minute <= 1440 AND start_minute < end_minute),
+    CONSTRAINT price_rule_value_ck CHECK (value > 0 AND (kind <> 'percent' OR value <= 100))
+);
+
+CREATE INDEX idx_price_rule_product_id ON price_rule (product_id);
+CREATE INDEX idx_price_rule_category_id ON price_rule (category_id);
+
+ALTER TABLE order_line
+    ADD COLUMN price_rule_id VARCHAR(36) NULL REFERENCES price_rule (id) ON DELETE RESTRICT,
+    ADD COLUMN list_price_cents BIGINT NULL;
+
+CREATE INDEX idx_order_line_price_rule_id ON order_line (price_rule_id);
@@ -0,0 +1,20 @@
+-- Windows in which a product, or every product in a category, can be
+-- ordered (breakfast until 11:00, grill from 17:00). Without windows a
+-- product is always orderable. Dated windows apply on that day only and
+-- replace the weekly windows there.
+CREATE TABLE availability_window (
+    id           VARCHAR(36) PRIMARY KEY,
+    product_id   VARCHAR(36) NULL REFERENCES product (id) ON DELETE CASCADE,
+    category_id  VARCHAR(36) NULL REFERENCES category (id) ON DELETE CASCADE,
+    weekdays     INTEGER NOT NULL DEFAULT 127,
+    date         VARCHAR(10) NULL,
+    start_minute INTEGER NOT NULL,
+    end_minute   INTEGER NOT NULL,
+    CONSTRAINT availability_window_scope_ck CHECK (num_nonnulls(product_id, category_id) = 1),
+    CONSTRAINT availability_window_weekdays_ck CHECK (weekdays > 0 AND weekdays < 128),
+    CONSTRAINT availability_window_range_ck
+        CHECK (start_minute >= 0 AND end_minute <= 1440 AND start_minute < end_minute)
+);
+
+CREATE INDEX idx_availability_window_product_id ON availability_window (product_id);
+CREATE INDEX idx_availability_window_category_id ON availability_window (category_id);
@@ -0,0 +1,9 @@
+-- Allergen flags (the 14 declared under EU and Swiss food law) and dietary
+-- tags of products. Order lines keep the allergens at the time of sale for
+-- station displays and receipts.
+ALTER TABLE product
+    ADD COLUMN allergens    JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ADD COLUMN dietary_tags JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE order_line
+    ADD COLUMN allergens JSONB NULL;
@@ -0,0 +1,17 @@
+-- French and English catalog texts, keyed by locale: {"fr": {"name": …,
+-- "description": …}}. The German texts stay in the name and description
+-- columns. Orders and admin invites remember the language of their emails.
+ALTER TABLE product
+    ADD COLUMN translations JSONB NULL;
+
+ALTER TABLE category
+    ADD COLUMN translations JSONB NULL;
+
+ALTER TABLE menu_slot
+    ADD COLUMN translations JSONB NULL;
+
+ALTER TABLE "order"
+    ADD COLUMN locale VARCHAR(5) NULL;
+
+ALTER TABLE admin_invite
+    ADD COLUMN locale VARCHAR(5) NULL;
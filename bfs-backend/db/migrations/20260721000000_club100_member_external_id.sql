@@ -0,0 +1,11 @@
+-- Club100 members come from whichever directory provider is configured, not
+-- only Elvanto: keep the provider's person ID in a neutral column and record
+-- which provider it belongs to. Existing rows were all synced from Elvanto.
+ALTER TABLE club100_member
+    RENAME COLUMN elvanto_person_id TO external_person_id;
+
+ALTER TABLE club100_member
+    ADD COLUMN provider VARCHAR(20) NOT NULL DEFAULT 'elvanto';
+
+ALTER INDEX idx_club100_member_elvanto_person_id
+    RENAME TO idx_club100_member_external_person_id;
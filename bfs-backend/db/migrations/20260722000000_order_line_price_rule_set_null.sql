@@ -0,0 +1,7 @@
+-- Price rules cascade away with their product or category; that must not be
+-- blocked by the order lines they priced. The lines keep their list price,
+-- only the link to the deleted rule goes.
+ALTER TABLE order_line
+    DROP CONSTRAINT order_line_price_rule_id_fkey,
+    ADD CONSTRAINT order_line_price_rule_id_fkey
+        FOREIGN KEY (price_rule_id) REFERENCES price_rule (id) ON DELETE SET NULL;
@@ -0,0 +1,22 @@
+-- A person ID is only unique within its membership provider: after switching
+-- providers a new person may carry an old member's ID. Key members on the
+-- provider and the ID, and record on each redemption which provider's person
+-- it belongs to, so a new member never inherits someone else's history.
+DROP INDEX idx_club100_member_external_person_id;
+
+CREATE UNIQUE INDEX idx_club100_member_provider_external_person_id
+    ON club100_member (provider, external_person_id);
+
+ALTER TABLE club100_redemption
+    RENAME COLUMN elvanto_person_id TO external_person_id;
+
+ALTER TABLE club100_redemption
+    RENAME COLUMN elvanto_person_name TO person_name;
+
+ALTER TABLE club100_redemption
+    ADD COLUMN provider VARCHAR(20) NOT NULL DEFAULT 'elvanto';
+
+DROP INDEX idx_club100_elvanto_person;
+
+CREATE INDEX idx_club100_redemption_provider_person
+    ON club100_redemption (provider, external_person_id);
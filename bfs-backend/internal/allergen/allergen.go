@@ -0,0 +1,171 @@
+// Package allergen defines the allergen flags and dietary tags of catalog
+// products. Allergens are the 14 the EU and Swiss food law require to be
+// declared (Regulation (EU) 1169/2011, Annex II; LIV Annex 6).
+package allergen
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"backend/internal/i18n"
+)
+
+var (
+	ErrUnknownAllergen = errors.New("allergen_unknown")
+	ErrUnknownDietary  = errors.New("dietary_tag_unknown")
+)
+
+// Allergens in the order of the regulation, with their German labels.
+var Allergens = []Code{
+	{"gluten", "Gluten"},
+	{"crustaceans", "Krebstiere"},
+	{"eggs", "Eier"},
+	{"fish", "Fisch"},
+	{"peanuts", "Erdnüsse"},
+	{"soybeans", "Soja"},
+	{"milk", "Milch"},
+	{"nuts", "Schalenfrüchte"},
+	{"celery", "Sellerie"},
+	{"mustard", "Senf"},
+	{"sesame", "Sesam"},
+	{"sulphites", "Sulfite"},
+	{"lupin", "Lupinen"},
+	{"molluscs", "Weichtiere"},
+}
+
+// DietaryTags with their German labels.
+var DietaryTags = []Code{
+	{"vegetarian", "Vegetarisch"},
+	{"vegan", "Vegan"},
+	{"halal", "Halal"},
+	{"kosher", "Koscher"},
+	{"gluten_free", "Glutenfrei"},
+	{"lactose_free", "Laktosefrei"},
+}
+
+type Code struct {
+	Key   string
+	Label string
+}
+
+// NormalizeAllergens lowercases and deduplicates keys and puts them in the
+// order of Allergens. Unknown keys are an error.
+func NormalizeAllergens(keys []string) ([]string, error) {
+	return normalize(keys, Allergens, ErrUnknownAllergen)
+}
+
+// NormalizeDietary does the same for dietary tags.
+func NormalizeDietary(keys []string) ([]string, error) {
+	return normalize(keys, DietaryTags, ErrUnknownDietary)
+}
+
+func normalize(keys []string, codes []Code, unknown error) ([]string, error) {
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			continue
+		}
+		if !slices.ContainsFunc(codes, func(c Code) bool { return c.Key == k }) {
+			return nil, fmt.Errorf("%w: %q", unknown, k)
+		}
+		seen[k] = true
+	}
+	out := make([]string, 0, len(seen))
+	for _, c := range codes {
+		if seen[c.Key] {
+			out = append(out, c.Key)
+		}
+	}
+	return out, nil
+}
+
+// Union merges allergen lists, e.g. a menu's own with those of the options
+// picked for its slots. The result is in the order of Allergens.
+func Union(lists ...[]string) []string {
+	var all []string
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+	out, _ := NormalizeAllergens(all)
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// Intersects reports whether a and b share a key.
+func Intersects(a, b []string) bool {
+	for _, k := range a {
+		if slices.Contains(b, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll reports whether have includes every key of want.
+func ContainsAll(have, want []string) bool {
+	for _, k := range want {
+		if !slices.Contains(have, k) {
+			return false
+		}
+	}
+	return true
+}
+
+// LabelsIn returns the labels of allergen keys in l, for customer receipts.
+// Keys without a label in l get the German one; unknown keys are passed
+// through.
+func LabelsIn(l i18n.Locale, keys []string) []string {
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		label := k
+		if i := slices.IndexFunc(Allergens, func(c Code) bool { return c.Key == k }); i >= 0 {
+			label = Allergens[i].Label
+		}
+		if t, ok := translatedLabels[l][k]; ok {
+			label = t
+		}
+		out = append(out, label)
+	}
+	return out
+}
+
+// translatedLabels of the allergens in the languages other than German.
+var translatedLabels = map[i18n.Locale]map[string]string{
+	i18n.French: {
+		"gluten":      "Gluten",
+		"crustaceans": "Crustacés",
+		"eggs":        "Œufs",
+		"fish":        "Poisson",
+		"peanuts":     "Arachides",
+		"soybeans":    "Soja",
+		"milk":        "Lait",
+		"nuts":        "Fruits à coque",
+		"celery":      "Céleri",
+		"mustard":     "Moutarde",
+		"sesame":      "Sésame",
+		"sulphites":   "Sulfites",
+		"lupin":       "Lupin",
+		"molluscs":    "Mollusques",
+	},
+	i18n.English: {
+		"gluten":      "Gluten",
+		"crustaceans": "Crustaceans",
+		"eggs":        "Eggs",
+		"fish":        "Fish",
+		"peanuts":     "Peanuts",
+		"soybeans":    "Soy",
+		"milk":        "Milk",
+		"nuts":        "Tree nuts",
+		"celery":      "Celery",
+		"mustard":     "Mustard",
+		"sesame":      "Sesame",
+		"sulphites":   "Sulphites",
+		"lupin":       "Lupin",
+		"molluscs":    "Molluscs",
+	},
+}
@@ -0,0 +1,59 @@
+package allergen
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"backend/internal/i18n"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := NormalizeAllergens([]string{" Milk", "gluten", "milk", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"gluten", "milk"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err := NormalizeAllergens([]string{"pollen"}); !errors.Is(err, ErrUnknownAllergen) {
+		t.Fatalf("unknown allergen: got %v", err)
+	}
+	if _, err := NormalizeDietary([]string{"vegan", "paleo"}); !errors.Is(err, ErrUnknownDietary) {
+		t.Fatalf("unknown dietary tag: got %v", err)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	got := Union([]string{"milk"}, nil, []string{"sesame", "gluten", "milk"})
+	if want := []string{"gluten", "milk", "sesame"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if Union(nil, []string{}) != nil {
+		t.Fatal("union of nothing should be nil")
+	}
+}
+
+func TestSets(t *testing.T) {
+	if !Intersects([]string{"eggs", "milk"}, []string{"milk"}) || Intersects([]string{"eggs"}, []string{"milk"}) {
+		t.Fatal("Intersects")
+	}
+	if !ContainsAll([]string{"vegan", "vegetarian"}, []string{"vegan"}) || ContainsAll([]string{"vegetarian"}, []string{"vegan"}) {
+		t.Fatal("ContainsAll")
+	}
+	if !ContainsAll(nil, nil) {
+		t.Fatal("ContainsAll of nothing")
+	}
+}
+
+func TestLabelsIn(t *testing.T) {
+	if got := LabelsIn(i18n.German, []string{"nuts", "x"}); !slices.Equal(got, []string{"Schalenfrüchte", "x"}) {
+		t.Fatalf("got %v", got)
+	}
+	if got := LabelsIn(i18n.French, []string{"eggs", "x"}); !slices.Equal(got, []string{"Œufs", "x"}) {
+		t.Fatalf("got %v", got)
+	}
+	if got := LabelsIn(i18n.English, []string{"nuts"}); !slices.Equal(got, []string{"Tree nuts"}) {
+		t.Fatalf("got %v", got)
+	}
+}
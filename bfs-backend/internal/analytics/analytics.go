@@ -0,0 +1,219 @@
+// Package analytics computes station throughput and wait-time figures from
+// payment and redemption timestamps. It is pure computation; loading the data
+// is the service layer's job.
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// BucketSize is the width of the redemption histogram buckets.
+const BucketSize = 15 * time.Minute
+
+// Redemption is a quantity of one order's items handed out at a point in time.
+type Redemption struct {
+	OrderID    string
+	Quantity   int
+	PaidAt     time.Time
+	RedeemedAt time.Time
+}
+
+// Demand is a quantity of one order's items that became due at payment time.
+type Demand struct {
+	OrderID  string
+	Quantity int
+	PaidAt   time.Time
+}
+
+// DepthUnit selects what the queue depth counts.
+type DepthUnit int
+
+const (
+	// DepthOrders counts orders waiting (what a kitchen screen shows).
+	DepthOrders DepthUnit = iota
+	// DepthItems counts individual units waiting.
+	DepthItems
+)
+
+// Bucket is the number of units redeemed in one BucketSize slot.
+type Bucket struct {
+	Start            time.Time `json:"start"`
+	RedeemedQuantity int       `json:"redeemedQuantity"`
+}
+
+// Metrics summarises one station or product for a time window.
+type Metrics struct {
+	RedeemedQuantity  int        `json:"redeemedQuantity"`
+	MedianWaitSeconds *float64   `json:"medianWaitSeconds,omitempty"`
+	P90WaitSeconds    *float64   `json:"p90WaitSeconds,omitempty"`
+	Buckets           []Bucket   `json:"buckets"`
+	PeakQueueDepth    int        `json:"peakQueueDepth"`
+	PeakQueueAt       *time.Time `json:"peakQueueAt,omitempty"`
+}
+
+// Compute derives the metrics for one station or product. Buckets are aligned
+// to quarter hours in loc.
+func Compute(redemptions []Redemption, demand []Demand, unit DepthUnit, loc *time.Location) Metrics {
+	m := Metrics{Buckets: []Bucket{}}
+
+	waits := make([]weighted, 0, len(redemptions))
+	buckets := make(map[time.Time]int)
+	for _, r := range redemptions {
+		if r.Quantity <= 0 {
+			continue
+		}
+		m.RedeemedQuantity += r.Quantity
+		wait := r.RedeemedAt.Sub(r.PaidAt).Seconds()
+		if wait < 0 {
+			wait = 0
+		}
+		waits = append(waits, weighted{value: wait, weight: r.Quantity})
+		buckets[BucketStart(r.RedeemedAt, loc)] += r.Quantity
+	}
+
+	if len(waits) > 0 {
+		sort.Slice(waits, func(i, j int) bool { return waits[i].value < waits[j].value })
+		median := weightedPercentile(waits, 0.5)
+		p90 := weightedPercentile(waits, 0.9)
+		m.MedianWaitSeconds = &median
+		m.P90WaitSeconds = &p90
+	}
+
+	for start, qty := range buckets {
+		m.Buckets = append(m.Buckets, Bucket{Start: start, RedeemedQuantity: qty})
+	}
+	sort.Slice(m.Buckets, func(i, j int) bool { return m.Buckets[i].Start.Before(m.Buckets[j].Start) })
+
+	m.PeakQueueDepth, m.PeakQueueAt = peakDepth(redemptions, demand, unit)
+	return m
+}
+
+// BucketStart truncates t to the start of its quarter hour in loc. Quarter
+// hours are aligned in local time so half-hour offset zones still line up.
+func BucketStart(t time.Time, loc *time.Location) time.Time {
+	lt := t.In(loc)
+	minute := lt.Minute() - lt.Minute()%int(BucketSize/time.Minute)
+	return time.Date(lt.Year(), lt.Month(), lt.Day(), lt.Hour(), minute, 0, 0, loc)
+}
+
+type weighted struct {
+	value  float64
+	weight int
+}
+
+// weightedPercentile returns the nearest-rank percentile p (0..1) of values
+// sorted ascending, where each value counts weight times.
+func weightedPercentile(sorted []weighted, p float64) float64 {
+	total := 0
+	for _, w := range sorted {
+		total += w.weight
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+	cum := 0
+	for _, w := range sorted {
+		cum += w.weight
+		if cum >= rank {
+			return w.value
+		}
+	}
+	return sorted[len(sorted)-1].value
+}
+
+type depthChange struct {
+	at    time.Time
+	delta int
+}
+
+// peakDepth sweeps arrivals (payments) and departures (redemptions) to find
+// the largest backlog. With DepthOrders an order leaves once all its demanded
+// units were redeemed; redemptions of orders without demand in the window are
+// ignored so the depth never goes negative.
+func peakDepth(redemptions []Redemption, demand []Demand, unit DepthUnit) (int, *time.Time) {
+	type orderState struct {
+		paidAt   time.Time
+		demanded int
+	}
+	orders := make(map[string]*orderState)
+	for _, d := range demand {
+		if d.Quantity <= 0 {
+			continue
+		}
+		st, ok := orders[d.OrderID]
+		if !ok {
+			st = &orderState{paidAt: d.PaidAt}
+			orders[d.OrderID] = st
+		}
+		if d.PaidAt.Before(st.paidAt) {
+			st.paidAt = d.PaidAt
+		}
+		st.demanded += d.Quantity
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	changes := make([]depthChange, 0, len(orders)*2)
+	for _, st := range orders {
+		delta := 1
+		if unit == DepthItems {
+			delta = st.demanded
+		}
+		changes = append(changes, depthChange{at: st.paidAt, delta: delta})
+	}
+
+	sorted := make([]Redemption, len(redemptions))
+	copy(sorted, redemptions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RedeemedAt.Before(sorted[j].RedeemedAt) })
+
+	remaining := make(map[string]int, len(orders))
+	for id, st := range orders {
+		remaining[id] = st.demanded
+	}
+	for _, r := range sorted {
+		left, ok := remaining[r.OrderID]
+		if !ok || left <= 0 || r.Quantity <= 0 {
+			continue
+		}
+		take := min(r.Quantity, left)
+		remaining[r.OrderID] = left - take
+		switch unit {
+		case DepthItems:
+			changes = append(changes, depthChange{at: r.RedeemedAt, delta: -take})
+		case DepthOrders:
+			if left-take == 0 {
+				changes = append(changes, depthChange{at: r.RedeemedAt, delta: -1})
+			}
+		}
+	}
+
+	// Arrivals before departures at the same instant, so a walk-up order that
+	// is served immediately still counts as having been in the queue.
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].at.Equal(changes[j].at) {
+			return changes[i].delta > changes[j].delta
+		}
+		return changes[i].at.Before(changes[j].at)
+	})
+
+	depth, peak := 0, 0
+	var peakAt time.Time
+	for _, c := range changes {
+		depth += c.delta
+		if depth > peak {
+			peak = depth
+			peakAt = c.at
+		}
+	}
+	if peak == 0 {
+		return 0, nil
+	}
+	return peak, &peakAt
+}
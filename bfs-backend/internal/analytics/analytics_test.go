@@ -0,0 +1,92 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zurich(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	return loc
+}
+
+func TestWeightedPercentile(t *testing.T) {
+	values := []weighted{
+		{value: 10, weight: 1},
+		{value: 20, weight: 3},
+		{value: 90, weight: 1},
+	}
+	assert.Equal(t, 20.0, weightedPercentile(values, 0.5))
+	assert.Equal(t, 90.0, weightedPercentile(values, 0.9))
+	assert.Equal(t, 10.0, weightedPercentile(values, 0))
+	assert.Equal(t, 0.0, weightedPercentile(nil, 0.5))
+}
+
+func TestBucketStart(t *testing.T) {
+	loc := zurich(t)
+	ts := time.Date(2026, 6, 20, 18, 44, 59, 0, loc)
+	assert.Equal(t, time.Date(2026, 6, 20, 18, 30, 0, 0, loc), BucketStart(ts, loc))
+
+	// UTC input is bucketed in local time.
+	assert.Equal(t, time.Date(2026, 6, 20, 19, 0, 0, 0, loc), BucketStart(time.Date(2026, 6, 20, 17, 0, 1, 0, time.UTC), loc))
+}
+
+func TestCompute(t *testing.T) {
+	loc := zurich(t)
+	at := func(h, m int) time.Time { return time.Date(2026, 6, 20, h, m, 0, 0, loc) }
+
+	demand := []Demand{
+		{OrderID: "a", Quantity: 2, PaidAt: at(18, 0)},
+		{OrderID: "b", Quantity: 1, PaidAt: at(18, 5)},
+		{OrderID: "c", Quantity: 1, PaidAt: at(18, 10)},
+	}
+	redemptions := []Redemption{
+		// a is served in two steps; it only leaves the queue after the second.
+		{OrderID: "a", Quantity: 1, PaidAt: at(18, 0), RedeemedAt: at(18, 12)},
+		{OrderID: "b", Quantity: 1, PaidAt: at(18, 5), RedeemedAt: at(18, 14)},
+		{OrderID: "a", Quantity: 1, PaidAt: at(18, 0), RedeemedAt: at(18, 20)},
+		{OrderID: "c", Quantity: 1, PaidAt: at(18, 10), RedeemedAt: at(18, 40)},
+	}
+
+	t.Run("orders", func(t *testing.T) {
+		m := Compute(redemptions, demand, DepthOrders, loc)
+		assert.Equal(t, 4, m.RedeemedQuantity)
+		require.NotNil(t, m.MedianWaitSeconds)
+		require.NotNil(t, m.P90WaitSeconds)
+		// waits: 9m, 12m, 20m, 30m
+		assert.Equal(t, (12 * time.Minute).Seconds(), *m.MedianWaitSeconds)
+		assert.Equal(t, (30 * time.Minute).Seconds(), *m.P90WaitSeconds)
+		assert.Equal(t, []Bucket{
+			{Start: at(18, 0), RedeemedQuantity: 2},
+			{Start: at(18, 15), RedeemedQuantity: 1},
+			{Start: at(18, 30), RedeemedQuantity: 1},
+		}, m.Buckets)
+		assert.Equal(t, 3, m.PeakQueueDepth)
+		require.NotNil(t, m.PeakQueueAt)
+		assert.True(t, at(18, 10).Equal(*m.PeakQueueAt))
+	})
+
+	t.Run("items", func(t *testing.T) {
+		m := Compute(redemptions, demand, DepthItems, loc)
+		assert.Equal(t, 4, m.PeakQueueDepth)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		m := Compute(nil, nil, DepthOrders, loc)
+		assert.Zero(t, m.RedeemedQuantity)
+		assert.Nil(t, m.MedianWaitSeconds)
+		assert.Empty(t, m.Buckets)
+		assert.Zero(t, m.PeakQueueDepth)
+		assert.Nil(t, m.PeakQueueAt)
+	})
+
+	t.Run("redemption without demand does not go negative", func(t *testing.T) {
+		m := Compute([]Redemption{{OrderID: "x", Quantity: 1, PaidAt: at(17, 0), RedeemedAt: at(18, 1)}}, demand, DepthOrders, loc)
+		assert.Equal(t, 3, m.PeakQueueDepth)
+	})
+}
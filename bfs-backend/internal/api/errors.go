@@ -6,6 +6,7 @@ import (
 
 	"backend/internal/generated/api/generated"
 	"backend/internal/generated/ent"
+	"backend/internal/i18n"
 	"backend/internal/repository"
 	"backend/internal/response"
 )
@@ -25,6 +26,7 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 //   - ent.IsNotFound / repository.ErrNotFound   -> 404 Not Found
 //   - ent.IsConstraintError / repository.ErrConflict -> 409 Conflict
 //   - ent.IsValidationError                      -> 400 Bad Request
+//   - i18n.ErrInvalidTranslations                -> 400 Bad Request
 //   - everything else                            -> 500 Internal Server Error
 func writeEntError(w http.ResponseWriter, err error) {
 	switch {
@@ -37,6 +39,9 @@ func writeEntError(w http.ResponseWriter, err error) {
 	case ent.IsValidationError(err):
 		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
 
+	case errors.Is(err, i18n.ErrInvalidTranslations):
+		writeError(w, http.StatusBadRequest, "invalid_translations", err.Error())
+
 	default:
 		writeError(w, http.StatusInternalServerError, "internal_error", "An unexpected error occurred.")
 	}
@@ -7,6 +7,7 @@ import (
 	"backend/internal/inventory"
 	"backend/internal/repository"
 	"backend/internal/service"
+	"backend/internal/stationqueue"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -18,22 +19,31 @@ type Handlers struct {
 
 	categories    service.CategoryService
 	products      service.ProductService
+	catalog       service.CatalogService
 	orders        service.OrderService
 	payments      service.PaymentService
 	pos           service.POSService
 	settings      service.SettingsService
+	openingHours  service.OpeningHoursService
+	events        service.EventService
+	priceRules    service.PriceRuleService
 	stations      service.StationService
 	invites       service.AdminInviteService
 	email         service.EmailService
 	users         service.UserService
 	devices       service.DeviceService
 	club100       service.Club100Service
+	club100Cards  service.Club100CardService
 	volunteers    service.VolunteerService
 	androidUpdate service.AndroidUpdateService
+	analytics     service.AnalyticsService
+	qr            service.QRService
+	tickets       service.TicketService
 	verification  repository.VerificationRepository
 	idempotency   repository.IdempotencyRepository
 	blobStore     *blobstore.Client
 	inventoryHub  *inventory.Hub
+	queueFeed     *stationQueueFeed
 
 	payrexxWebhookSecret string
 	logger               *zap.Logger
@@ -48,22 +58,31 @@ type HandlersDeps struct {
 	Config        config.Config
 	Categories    service.CategoryService
 	Products      service.ProductService
+	Catalog       service.CatalogService
 	Orders        service.OrderService
 	Payments      service.PaymentService
 	POS           service.POSService
 	Settings      service.SettingsService
+	OpeningHours  service.OpeningHoursService
+	Events        service.EventService
+	PriceRules    service.PriceRuleService
 	Stations      service.StationService
 	Invites       service.AdminInviteService
 	Email         service.EmailService
 	Users         service.UserService
 	Devices       service.DeviceService
 	Club100       service.Club100Service
+	Club100Cards  service.Club100CardService
 	Volunteers    service.VolunteerService
 	AndroidUpdate service.AndroidUpdateService
+	Analytics     service.AnalyticsService
+	QR            service.QRService
+	Tickets       service.TicketService
 	Verification  repository.VerificationRepository
 	Idempotency   repository.IdempotencyRepository
 	BlobStore     *blobstore.Client `optional:"true"`
 	InventoryHub  *inventory.Hub
+	QueueHub      *stationqueue.Hub
 	Logger        *zap.Logger
 }
 
@@ -72,22 +91,31 @@ func NewHandlers(deps HandlersDeps) *Handlers {
 	return &Handlers{
 		categories:           deps.Categories,
 		products:             deps.Products,
+		catalog:              deps.Catalog,
 		orders:               deps.Orders,
 		payments:             deps.Payments,
 		pos:                  deps.POS,
 		settings:             deps.Settings,
+		openingHours:         deps.OpeningHours,
+		events:               deps.Events,
+		priceRules:           deps.PriceRules,
 		stations:             deps.Stations,
 		invites:              deps.Invites,
 		email:                deps.Email,
 		users:                deps.Users,
 		devices:              deps.Devices,
 		club100:              deps.Club100,
+		club100Cards:         deps.Club100Cards,
 		volunteers:           deps.Volunteers,
 		androidUpdate:        deps.AndroidUpdate,
+		analytics:            deps.Analytics,
+		qr:                   deps.QR,
+		tickets:              deps.Tickets,
 		verification:         deps.Verification,
 		idempotency:          deps.Idempotency,
 		blobStore:            deps.BlobStore,
 		inventoryHub:         deps.InventoryHub,
+		queueFeed:            newStationQueueFeed(deps.QueueHub, deps.Stations, deps.Logger),
 		payrexxWebhookSecret: deps.Config.Payrexx.WebhookSecret,
 		logger:               deps.Logger,
 	}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/internal/analytics"
+	"backend/internal/response"
+	"backend/internal/service"
+
+	"go.uber.org/zap"
+)
+
+type stationAnalyticsResponse struct {
+	StationID   *string `json:"stationId"`
+	StationName string  `json:"stationName"`
+	analytics.Metrics
+}
+
+type productAnalyticsResponse struct {
+	ProductID   string `json:"productId"`
+	ProductName string `json:"productName"`
+	analytics.Metrics
+}
+
+// GetStationAnalytics reports wait times, throughput and peak queue depth per
+// station for one event day, optionally only for the orders of one event.
+// Redemptions without a known station are reported in a last entry with a
+// null stationId and no queue depth.
+// GET /v1/analytics/stations?date=YYYY-MM-DD&event_id=
+func (h *Handlers) GetStationAnalytics(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.analytics.StationStats(r.Context(), r.URL.Query().Get("date"), eventIDQuery(r))
+	if err != nil {
+		h.writeAnalyticsError(w, err)
+		return
+	}
+	items := make([]stationAnalyticsResponse, 0, len(stats))
+	for _, s := range stats {
+		var stationID *string
+		if s.StationID != "" {
+			stationID = &s.StationID
+		}
+		items = append(items, stationAnalyticsResponse{
+			StationID:   stationID,
+			StationName: s.StationName,
+			Metrics:     s.Metrics,
+		})
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// GetProductAnalytics reports wait times, throughput and peak queue depth per
+// product for one event day, optionally only for the orders of one event.
+// GET /v1/analytics/products?date=YYYY-MM-DD&event_id=
+func (h *Handlers) GetProductAnalytics(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.analytics.ProductStats(r.Context(), r.URL.Query().Get("date"), eventIDQuery(r))
+	if err != nil {
+		h.writeAnalyticsError(w, err)
+		return
+	}
+	items := make([]productAnalyticsResponse, 0, len(stats))
+	for _, s := range stats {
+		items = append(items, productAnalyticsResponse{
+			ProductID:   s.ProductID,
+			ProductName: s.ProductName,
+			Metrics:     s.Metrics,
+		})
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handlers) writeAnalyticsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAnalyticsInvalidDate):
+		writeError(w, http.StatusBadRequest, "invalid_date", "date must be formatted as YYYY-MM-DD")
+	default:
+		h.logger.Error("analytics error", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
@@ -58,7 +58,7 @@ func (h *Handlers) SendOtpEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.email.SendOTPEmail(r.Context(), email, otp, otpType); err != nil {
+	if err := h.email.SendOTPEmail(r.Context(), email, otp, otpType, bodyLocale(r, body.Locale)); err != nil {
 		h.logger.Error("failed to send OTP email",
 			zap.String("to", email),
 			zap.Error(err),
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/response"
+	"backend/internal/schedule"
+	"backend/internal/service"
+)
+
+// availabilityWindowPayload is a window in Europe/Zurich wall-clock time. A
+// window with a date ("2006-01-02") applies on that day only and replaces
+// the weekly windows there; otherwise it repeats on the ISO weekdays
+// (1 = Monday … 7 = Sunday), empty meaning every day.
+type availabilityWindowPayload struct {
+	Weekdays []int   `json:"weekdays,omitempty"`
+	Date     *string `json:"date"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+}
+
+// availabilityRequest replaces all windows; an empty list makes the product
+// or category orderable at any time again.
+type availabilityRequest struct {
+	Windows []availabilityWindowPayload `json:"windows"`
+}
+
+// GetProductAvailability (GET /v1/products/{productId}/availability)
+func (h *Handlers) GetProductAvailability(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "productId")
+	if !ok {
+		return
+	}
+	windows, err := h.products.ProductAvailability(r.Context(), id)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	writeAvailability(w, windows)
+}
+
+// SetProductAvailability (PUT /v1/products/{productId}/availability)
+func (h *Handlers) SetProductAvailability(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "productId")
+	if !ok {
+		return
+	}
+	in, ok := decodeAvailability(w, r)
+	if !ok {
+		return
+	}
+	windows, err := h.products.SetProductAvailability(r.Context(), id, in)
+	if err != nil {
+		writeAvailabilityError(w, err)
+		return
+	}
+	writeAvailability(w, windows)
+}
+
+// GetCategoryAvailability (GET /v1/categories/{categoryId}/availability)
+func (h *Handlers) GetCategoryAvailability(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "categoryId")
+	if !ok {
+		return
+	}
+	windows, err := h.products.CategoryAvailability(r.Context(), id)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	writeAvailability(w, windows)
+}
+
+// SetCategoryAvailability (PUT /v1/categories/{categoryId}/availability)
+func (h *Handlers) SetCategoryAvailability(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "categoryId")
+	if !ok {
+		return
+	}
+	in, ok := decodeAvailability(w, r)
+	if !ok {
+		return
+	}
+	windows, err := h.products.SetCategoryAvailability(r.Context(), id, in)
+	if err != nil {
+		writeAvailabilityError(w, err)
+		return
+	}
+	writeAvailability(w, windows)
+}
+
+func decodeAvailability(w http.ResponseWriter, r *http.Request) ([]repository.AvailabilityWindowInput, bool) {
+	var req availabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return nil, false
+	}
+	in := make([]repository.AvailabilityWindowInput, 0, len(req.Windows))
+	for _, win := range req.Windows {
+		weekdays := schedule.AllWeekdays
+		if len(win.Weekdays) > 0 {
+			var err error
+			if weekdays, err = schedule.WeekdaysFromISO(win.Weekdays); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_availability", "weekdays must be between 1 (Monday) and 7 (Sunday)")
+				return nil, false
+			}
+		}
+		start, err1 := schedule.ParseClock(win.Start)
+		end, err2 := schedule.ParseClock(win.End)
+		if err1 != nil || err2 != nil {
+			writeError(w, http.StatusBadRequest, "invalid_availability", "start and end must be HH:MM")
+			return nil, false
+		}
+		// As with opening hours, an end of "00:00" means midnight at the end
+		// of the day.
+		if end == 0 {
+			end = schedule.MinutesPerDay
+		}
+		date := win.Date
+		if date != nil && *date == "" {
+			date = nil
+		}
+		in = append(in, repository.AvailabilityWindowInput{
+			Weekdays:    int(weekdays),
+			Date:        date,
+			StartMinute: start,
+			EndMinute:   end,
+		})
+	}
+	return in, true
+}
+
+func writeAvailability(w http.ResponseWriter, windows []*ent.AvailabilityWindow) {
+	items := make([]availabilityWindowPayload, 0, len(windows))
+	for _, win := range windows {
+		p := availabilityWindowPayload{
+			Date:  win.Date,
+			Start: schedule.FormatClock(win.StartMinute),
+			End:   schedule.FormatClock(win.EndMinute),
+		}
+		if win.Date == nil {
+			p.Weekdays = schedule.Weekdays(win.Weekdays).ISO()
+		}
+		items = append(items, p)
+	}
+	response.WriteJSON(w, http.StatusOK, availabilityRequest{Windows: items})
+}
+
+func writeAvailabilityError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrAvailabilityInvalid) {
+		writeError(w, http.StatusBadRequest, "invalid_availability", err.Error())
+		return
+	}
+	writeEntError(w, err)
+}
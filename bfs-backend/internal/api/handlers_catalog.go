@@ -0,0 +1,159 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"backend/internal/catalogfile"
+	"backend/internal/response"
+	"backend/internal/service"
+)
+
+const catalogImportMaxBytes = 5 << 20 // 5 MB
+
+type catalogCountsResponse struct {
+	Jetons     int `json:"jetons"`
+	Categories int `json:"categories"`
+	Products   int `json:"products"`
+	Variants   int `json:"variants"`
+	Slots      int `json:"slots"`
+}
+
+type catalogImportResponse struct {
+	DryRun   bool                  `json:"dryRun"`
+	Applied  bool                  `json:"applied"`
+	Created  catalogCountsResponse `json:"created"`
+	Updated  catalogCountsResponse `json:"updated"`
+	Problems []catalogfile.Problem `json:"problems"`
+}
+
+func catalogCountsToResponse(c service.CatalogCounts) catalogCountsResponse {
+	return catalogCountsResponse(c)
+}
+
+// ExportCatalog downloads the catalog as JSON (default) or, with
+// ?format=csv, as a CSV file that opens in a spreadsheet.
+// GET /v1/catalog/export
+func (h *Handlers) ExportCatalog(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, "invalid_format", "format must be json or csv")
+		return
+	}
+
+	c, err := h.catalog.Export(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	var buf bytes.Buffer
+	contentType := "application/json"
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+		err = catalogfile.WriteCSV(&buf, c)
+	} else {
+		err = catalogfile.WriteJSON(&buf, c)
+	}
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="catalog.`+format+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// ImportCatalog creates and updates catalog records from an export. The file
+// is either the raw request body (application/json or text/csv) or the
+// "file" field of a multipart form, whose format follows the file name.
+// ?dryRun=true only reports what would change. Records with problems stop
+// the whole import.
+// POST /v1/catalog/import
+func (h *Handlers) ImportCatalog(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_dry_run", "dryRun must be true or false")
+			return
+		}
+		dryRun = b
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, catalogImportMaxBytes)
+	var src io.Reader = r.Body
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	isCSV := mt == "text/csv"
+	if mt == "multipart/form-data" {
+		if err := r.ParseMultipartForm(catalogImportMaxBytes); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", "File too large or invalid multipart form")
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", "Missing file field")
+			return
+		}
+		defer func() { _ = file.Close() }()
+		src = file
+		isCSV = strings.EqualFold(path.Ext(header.Filename), ".csv")
+	}
+
+	var c *catalogfile.Catalog
+	var problems []catalogfile.Problem
+	var err error
+	if isCSV {
+		c, problems, err = catalogfile.ParseCSV(src)
+	} else {
+		c, err = catalogfile.ParseJSON(src)
+	}
+	if err != nil {
+		writeCatalogParseError(w, err)
+		return
+	}
+
+	// A file with unreadable rows is still checked, so all problems are
+	// reported at once, but never applied.
+	result, err := h.catalog.Import(r.Context(), c, dryRun || len(problems) > 0)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	problems = append(problems, result.Problems...)
+	if problems == nil {
+		problems = []catalogfile.Problem{}
+	}
+	response.WriteJSON(w, http.StatusOK, catalogImportResponse{
+		DryRun:   dryRun,
+		Applied:  result.Applied,
+		Created:  catalogCountsToResponse(result.Created),
+		Updated:  catalogCountsToResponse(result.Updated),
+		Problems: problems,
+	})
+}
+
+func writeCatalogParseError(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytes):
+		writeError(w, http.StatusRequestEntityTooLarge, "catalog_too_large", "The file is larger than 5 MB")
+	case errors.Is(err, catalogfile.ErrEmpty):
+		writeError(w, http.StatusBadRequest, catalogfile.ErrEmpty.Error(), "The file contains no records")
+	case errors.Is(err, catalogfile.ErrUnsupportedVersion):
+		writeError(w, http.StatusBadRequest, catalogfile.ErrUnsupportedVersion.Error(), err.Error())
+	case errors.Is(err, catalogfile.ErrMissingHeader):
+		writeError(w, http.StatusBadRequest, catalogfile.ErrMissingHeader.Error(), "The CSV file needs a header row with record and name columns")
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_catalog", err.Error())
+	}
+}
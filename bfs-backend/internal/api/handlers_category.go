@@ -34,12 +34,22 @@ func (h *Handlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	if body.Position != nil {
 		position = *body.Position
 	}
+	translations, ok := translationsBody(w, body.Translations)
+	if !ok {
+		return
+	}
 
 	cat, err := h.categories.Create(r.Context(), body.Name, position)
 	if err != nil {
 		writeEntError(w, err)
 		return
 	}
+	if translations != nil {
+		if cat, err = h.categories.UpdateTranslations(r.Context(), cat.ID, translations); err != nil {
+			writeEntError(w, err)
+			return
+		}
+	}
 	response.WriteJSON(w, http.StatusCreated, toAPICategory(cat))
 }
 
@@ -82,12 +92,22 @@ func (h *Handlers) UpdateCategory(w http.ResponseWriter, r *http.Request, catego
 	if body.IsActive != nil {
 		isActive = *body.IsActive
 	}
+	translations, ok := translationsBody(w, body.Translations)
+	if !ok {
+		return
+	}
 
 	cat, err := h.categories.Update(r.Context(), categoryId, name, position, isActive)
 	if err != nil {
 		writeEntError(w, err)
 		return
 	}
+	if translations != nil {
+		if cat, err = h.categories.UpdateTranslations(r.Context(), categoryId, translations); err != nil {
+			writeEntError(w, err)
+			return
+		}
+	}
 	response.WriteJSON(w, http.StatusOK, toAPICategory(cat))
 }
 
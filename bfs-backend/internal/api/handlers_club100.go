@@ -1,20 +1,34 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"time"
 
 	"backend/internal/generated/api/generated"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/club100syncrun"
+	"backend/internal/membership"
 	"backend/internal/response"
+	"backend/internal/service"
 
 	"go.uber.org/zap"
 )
 
-// ListClub100People returns all 100 Club members with their redemption status.
+// ListClub100People returns the 100 Club members from the local directory
+// with their redemption status, optionally filtered by a fuzzy name search.
 // (GET /club100/people)
-func (h *Handlers) ListClub100People(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) ListClub100People(w http.ResponseWriter, r *http.Request, params generated.ListClub100PeopleParams) {
 	ctx := r.Context()
 
-	people, err := h.club100.GetPeopleWithRedemptions(ctx)
+	query := ""
+	if params.Q != nil {
+		query = *params.Q
+	}
+	people, err := h.club100.GetPeopleWithRedemptions(ctx, query)
 	if err != nil {
 		h.logger.Error("club100 GetPeopleWithRedemptions failed", zap.Error(err))
 		writeError(w, http.StatusInternalServerError, "club100_error", err.Error())
@@ -44,10 +58,257 @@ func (h *Handlers) GetClub100Remaining(w http.ResponseWriter, r *http.Request, e
 		writeError(w, http.StatusInternalServerError, "club100_error", err.Error())
 		return
 	}
+	window, err := h.club100.GetCurrentWindow(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "club100_error", err.Error())
+		return
+	}
 
-	response.WriteJSON(w, http.StatusOK, generated.Club100Remaining{
+	resp := generated.Club100Remaining{
 		ElvantoPersonId: elvantoPersonId,
 		Remaining:       remaining,
 		Max:             max,
+		ResetsAt:        &window.End,
+	}
+	if window.Period != nil {
+		resp.PeriodName = &window.Period.Name
+	}
+	response.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetClub100SyncStatus reports the local directory and its latest syncs.
+// (GET /club100/sync)
+func (h *Handlers) GetClub100SyncStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.club100.GetSyncStatus(r.Context())
+	if err != nil {
+		h.logger.Error("club100 GetSyncStatus failed", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "club100_error", err.Error())
+		return
+	}
+	resp := generated.Club100SyncStatus{
+		Provider:        status.Provider,
+		Configured:      status.Configured,
+		Running:         status.Running,
+		MemberCount:     status.MemberCount,
+		IntervalSeconds: int(status.Interval.Seconds()),
+		LastRun:         club100SyncRunToResponse(status.LastRun),
+		LastSuccess:     club100SyncRunToResponse(status.LastSuccess),
+		Groups:          status.Filter.Groups,
+		Tags:            status.Filter.Tags,
+	}
+	if resp.Groups == nil {
+		resp.Groups = []string{}
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if u := status.LastUpload; u != nil {
+		resp.LastUpload = &generated.MembershipUpload{
+			Filename:    u.Filename,
+			MemberCount: u.MemberCount,
+			UploadedAt:  u.CreatedAt,
+		}
+	}
+	response.WriteJSON(w, http.StatusOK, resp)
+}
+
+// SyncClub100Members resyncs the directory from the membership provider
+// right away.
+// (POST /club100/sync)
+func (h *Handlers) SyncClub100Members(w http.ResponseWriter, r *http.Request) {
+	run, err := h.club100.SyncMembers(r.Context(), club100syncrun.TriggerManual)
+	switch {
+	case errors.Is(err, service.ErrClub100SyncRunning):
+		writeError(w, http.StatusConflict, "sync_running", "A sync is already running")
+		return
+	case errors.Is(err, membership.ErrNotConfigured):
+		writeError(w, http.StatusServiceUnavailable, "not_configured", "The membership provider is not configured")
+		return
+	case err != nil:
+		h.logger.Error("club100 SyncMembers failed", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "club100_error", err.Error())
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, club100SyncRunToResponse(run))
+}
+
+const club100MemberListMaxBytes = 2 << 20 // 2 MB
+
+// ImportClub100Members replaces the member list of the CSV membership
+// provider and syncs it into the directory. The CSV is either the raw request
+// body (text/csv) or the "file" field of a multipart form.
+// POST /v1/club100/members/import
+func (h *Handlers) ImportClub100Members(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, club100MemberListMaxBytes)
+	filename := "upload.csv"
+	var src io.Reader = r.Body
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "multipart/form-data" {
+		if err := r.ParseMultipartForm(club100MemberListMaxBytes); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", "File too large or invalid multipart form")
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", "Missing file field")
+			return
+		}
+		defer func() { _ = file.Close() }()
+		src = file
+		filename = header.Filename
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "File too large or unreadable")
+		return
+	}
+
+	run, err := h.club100.UploadMembers(r.Context(), filename, data)
+	switch {
+	case errors.Is(err, service.ErrClub100UploadUnsupported):
+		writeError(w, http.StatusConflict, "upload_unsupported", "Member lists can only be uploaded with MEMBERSHIP_PROVIDER=csv")
+		return
+	case errors.Is(err, membership.ErrInvalidCSV):
+		writeError(w, http.StatusBadRequest, "invalid_csv", err.Error())
+		return
+	case errors.Is(err, service.ErrClub100SyncRunning):
+		writeError(w, http.StatusConflict, "sync_running", "A sync is already running")
+		return
+	case err != nil:
+		h.logger.Error("club100 UploadMembers failed", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "club100_error", err.Error())
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, club100SyncRunToResponse(run))
+}
+
+func club100SyncRunToResponse(run *ent.Club100SyncRun) *generated.Club100SyncRun {
+	if run == nil {
+		return nil
+	}
+	return &generated.Club100SyncRun{
+		Id:           run.ID,
+		Trigger:      generated.Club100SyncRunTrigger(run.Trigger),
+		Provider:     run.Provider,
+		Status:       generated.Club100SyncRunStatus(run.Status),
+		MemberCount:  run.MemberCount,
+		AddedCount:   run.AddedCount,
+		RemovedCount: run.RemovedCount,
+		Error:        run.Error,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+	}
+}
+
+// ListClub100Periods returns all entitlement periods, latest first.
+// (GET /club100/periods)
+func (h *Handlers) ListClub100Periods(w http.ResponseWriter, r *http.Request) {
+	periods, err := h.club100.ListPeriods(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	now := time.Now()
+	items := make([]generated.Club100Period, 0, len(periods))
+	for _, p := range periods {
+		items = append(items, club100PeriodToResponse(p, now))
+	}
+	response.WriteJSON(w, http.StatusOK, generated.Club100PeriodList{Items: items})
+}
+
+// CreateClub100Period adds an entitlement period.
+// (POST /club100/periods)
+func (h *Handlers) CreateClub100Period(w http.ResponseWriter, r *http.Request) {
+	var body generated.Club100PeriodCreate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	period, err := h.club100.CreatePeriod(r.Context(), service.Club100PeriodInput{
+		Name:     body.Name,
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+	})
+	if err != nil {
+		writeClub100PeriodError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusCreated, club100PeriodToResponse(period, time.Now()))
+}
+
+// UpdateClub100Period renames or moves an entitlement period.
+// (PATCH /club100/periods/{periodId})
+func (h *Handlers) UpdateClub100Period(w http.ResponseWriter, r *http.Request, periodId string) {
+	var body generated.Club100PeriodUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	period, err := h.club100.UpdatePeriod(r.Context(), periodId, service.Club100PeriodPatch{
+		Name:     body.Name,
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+	})
+	if err != nil {
+		writeClub100PeriodError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, club100PeriodToResponse(period, time.Now()))
+}
+
+// DeleteClub100Period removes an entitlement period; its redemptions stay.
+// (DELETE /club100/periods/{periodId})
+func (h *Handlers) DeleteClub100Period(w http.ResponseWriter, r *http.Request, periodId string) {
+	if err := h.club100.DeletePeriod(r.Context(), periodId); err != nil {
+		writeClub100PeriodError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetClub100PeriodReport sums a period's redemptions per member.
+// (GET /club100/periods/{periodId}/report)
+func (h *Handlers) GetClub100PeriodReport(w http.ResponseWriter, r *http.Request, periodId string) {
+	report, err := h.club100.GetPeriodReport(r.Context(), periodId)
+	if err != nil {
+		writeClub100PeriodError(w, err)
+		return
+	}
+	items := make([]generated.Club100PeriodMemberTotal, 0, len(report.Members))
+	for _, m := range report.Members {
+		items = append(items, generated.Club100PeriodMemberTotal{
+			ElvantoPersonId:   m.ExternalPersonID,
+			ElvantoPersonName: m.PersonName,
+			Quantity:          m.Quantity,
+			Orders:            m.Orders,
+		})
+	}
+	response.WriteJSON(w, http.StatusOK, generated.Club100PeriodReport{
+		Period:        club100PeriodToResponse(report.Period, time.Now()),
+		Max:           report.Max,
+		TotalQuantity: report.TotalQuantity,
+		Items:         items,
 	})
 }
+
+func writeClub100PeriodError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrClub100PeriodNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Period not found")
+	case errors.Is(err, service.ErrClub100PeriodInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_period", err.Error())
+	case errors.Is(err, service.ErrClub100PeriodOverlap):
+		writeError(w, http.StatusConflict, "period_overlap", "Der Zeitraum überschneidet sich mit einer anderen Periode.")
+	default:
+		writeEntError(w, err)
+	}
+}
+
+func club100PeriodToResponse(p *ent.Club100Period, now time.Time) generated.Club100Period {
+	return generated.Club100Period{
+		Id:       p.ID,
+		Name:     p.Name,
+		StartsAt: p.StartsAt,
+		EndsAt:   p.EndsAt,
+		Active:   !now.Before(p.StartsAt) && now.Before(p.EndsAt),
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"backend/internal/generated/api/generated"
+	"backend/internal/i18n"
+	"backend/internal/pdf"
+	"backend/internal/response"
+	"backend/internal/service"
+	"backend/internal/tz"
+
+	"go.uber.org/zap"
+)
+
+// ListClub100Cards returns every active member with their current card.
+// (GET /club100/cards)
+func (h *Handlers) ListClub100Cards(w http.ResponseWriter, r *http.Request) {
+	members, err := h.club100Cards.ListMembers(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	items := make([]generated.Club100MemberCard, 0, len(members))
+	for _, m := range members {
+		items = append(items, club100MemberCardToResponse(m))
+	}
+	response.WriteJSON(w, http.StatusOK, generated.Club100MemberCardList{
+		Items:          items,
+		SigningEnabled: h.qr.SigningEnabled(),
+	})
+}
+
+// IssueClub100Cards issues a card to every active member without one.
+// (POST /club100/cards)
+func (h *Handlers) IssueClub100Cards(w http.ResponseWriter, r *http.Request) {
+	issued, err := h.club100Cards.IssueMissingCards(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, generated.Club100CardIssueResult{Issued: issued})
+}
+
+// IssueClub100Card issues or reissues a member's card.
+// (POST /club100/cards/{elvantoPersonId})
+func (h *Handlers) IssueClub100Card(w http.ResponseWriter, r *http.Request, elvantoPersonId string) {
+	issued, err := h.club100Cards.IssueCard(r.Context(), elvantoPersonId)
+	if err != nil {
+		writeClub100CardError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, club100MemberCardToResponse(*issued))
+}
+
+// RevokeClub100Card revokes a member's current card.
+// (DELETE /club100/cards/{elvantoPersonId})
+func (h *Handlers) RevokeClub100Card(w http.ResponseWriter, r *http.Request, elvantoPersonId string) {
+	if err := h.club100Cards.RevokeCard(r.Context(), elvantoPersonId); err != nil {
+		writeClub100CardError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LookupClub100Card resolves a scanned member card at the POS.
+// (POST /club100/cards/lookup)
+func (h *Handlers) LookupClub100Card(w http.ResponseWriter, r *http.Request) {
+	var body generated.Club100CardLookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	lookup, err := h.club100Cards.Lookup(r.Context(), body.Qr)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrClub100CardNotFound),
+			errors.Is(err, service.ErrClub100CardRevoked),
+			errors.Is(err, service.ErrClub100MemberInactive):
+			writeClub100CardError(w, err)
+		default:
+			h.writeQRError(w, err)
+		}
+		return
+	}
+
+	resp := generated.Club100CardLookup{
+		Person: generated.Club100Person{
+			Id:        lookup.Person.ID,
+			FirstName: lookup.Person.FirstName,
+			LastName:  lookup.Person.LastName,
+			Remaining: lookup.Person.Remaining,
+			Max:       lookup.Person.Max,
+		},
+		FreeProductIds: lookup.FreeProductIDs,
+		ResetsAt:       lookup.Window.End,
+	}
+	if lookup.Window.Period != nil {
+		resp.PeriodName = &lookup.Window.Period.Name
+	}
+	response.WriteJSON(w, http.StatusOK, resp)
+}
+
+// PrintClub100Cards renders the current cards as a PDF, ten per A4 sheet.
+// ?ids=a,b limits it to those members (Elvanto person IDs); ?lang=fr|en
+// prints the card texts in that language.
+// (GET /club100/cards/print.pdf)
+func (h *Handlers) PrintClub100Cards(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	cards, err := h.club100Cards.PrintableCards(r.Context(), ids)
+	if errors.Is(err, service.ErrQRSigningOff) {
+		writeError(w, http.StatusServiceUnavailable, "qr_signing_disabled", "Member cards need QR_SIGNING_SECRET to be set")
+		return
+	}
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	if len(cards) == 0 {
+		writeError(w, http.StatusNotFound, "no_cards", "No cards to print; issue cards first")
+		return
+	}
+
+	locale := printLocale(r)
+	in := pdf.MemberCardInput{Title: "100 Club", Cards: make([]pdf.MemberCard, 0, len(cards)), Locale: locale}
+	loc := tz.Zurich()
+	for _, c := range cards {
+		in.Cards = append(in.Cards, pdf.MemberCard{
+			Name:      strings.TrimSpace(c.Member.FirstName + " " + c.Member.LastName),
+			QRPayload: c.QRPayload,
+			Note:      fmt.Sprintf(cardIssuedText[locale], c.Card.CreatedAt.In(loc).Format("02.01.2006")),
+		})
+	}
+	body, err := pdf.RenderMemberCards(in)
+	if err != nil {
+		h.logger.Error("render club100 cards", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "render_failed", "Could not render the cards")
+		return
+	}
+	writePDF(w, body, "100-club-karten.pdf")
+}
+
+// cardIssuedText is the issue-date note of a member card.
+var cardIssuedText = map[i18n.Locale]string{
+	i18n.German:  "Ausgestellt %s",
+	i18n.French:  "Émise le %s",
+	i18n.English: "Issued %s",
+}
+
+func writeClub100CardError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrClub100MemberNotFound):
+		writeError(w, http.StatusNotFound, "member_not_found", "Member not found")
+	case errors.Is(err, service.ErrClub100CardNotFound):
+		writeError(w, http.StatusNotFound, "card_not_found", "Unknown member card")
+	case errors.Is(err, service.ErrClub100CardRevoked):
+		writeError(w, http.StatusConflict, "card_revoked", "This member card has been revoked")
+	case errors.Is(err, service.ErrClub100MemberInactive):
+		writeError(w, http.StatusConflict, "member_inactive", "This person is no longer a 100 Club member")
+	default:
+		writeEntError(w, err)
+	}
+}
+
+func club100MemberCardToResponse(m service.Club100MemberCard) generated.Club100MemberCard {
+	resp := generated.Club100MemberCard{
+		ElvantoPersonId: m.Member.ExternalPersonID,
+		FirstName:       m.Member.FirstName,
+		LastName:        m.Member.LastName,
+	}
+	if m.Card != nil {
+		resp.CardId = &m.Card.ID
+		resp.CardIssuedAt = &m.Card.CreatedAt
+	}
+	return resp
+}
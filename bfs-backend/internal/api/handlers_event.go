@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/internal/generated/ent"
+	nanoid "backend/internal/id"
+	"backend/internal/repository"
+	"backend/internal/response"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type eventResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type createEventRequest struct {
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	Active   bool      `json:"active"`
+}
+
+type updateEventRequest struct {
+	Name     *string    `json:"name,omitempty"`
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty"`
+	Active   *bool      `json:"active,omitempty"`
+}
+
+// eventProductPayload overrides one product for an event; a null price keeps
+// the base price.
+type eventProductPayload struct {
+	ProductID  string `json:"productId"`
+	Available  bool   `json:"available"`
+	PriceCents *int64 `json:"priceCents"`
+}
+
+// ListEvents (GET /v1/events)
+func (h *Handlers) ListEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := h.events.List(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	items := make([]eventResponse, 0, len(events))
+	for _, e := range events {
+		items = append(items, eventToResponse(e))
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// CreateEvent (POST /v1/events)
+func (h *Handlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
+	var req createEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	created, err := h.events.Create(r.Context(), service.EventInput(req))
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusCreated, eventToResponse(created))
+}
+
+// GetEvent (GET /v1/events/{eventId})
+func (h *Handlers) GetEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	e, err := h.events.Get(r.Context(), id)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, eventToResponse(e))
+}
+
+// UpdateEvent changes the given fields; setting active replaces the
+// active event.
+// PATCH /v1/events/{eventId}
+func (h *Handlers) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	var req updateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	updated, err := h.events.Update(r.Context(), id, service.EventPatch(req))
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, eventToResponse(updated))
+}
+
+// DeleteEvent (DELETE /v1/events/{eventId})
+func (h *Handlers) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	if err := h.events.Delete(r.Context(), id); err != nil {
+		writeEventError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetEventProducts (GET /v1/events/{eventId}/products)
+func (h *Handlers) GetEventProducts(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	rows, err := h.events.ListProducts(r.Context(), id)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": eventProductsToPayload(rows)})
+}
+
+// PutEventProducts replaces the event's product overrides.
+// PUT /v1/events/{eventId}/products
+func (h *Handlers) PutEventProducts(w http.ResponseWriter, r *http.Request) {
+	id, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Items []eventProductPayload `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	in := make([]repository.EventProductInput, 0, len(req.Items))
+	for _, p := range req.Items {
+		in = append(in, repository.EventProductInput(p))
+	}
+	rows, err := h.events.SetProducts(r.Context(), id, in)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": eventProductsToPayload(rows)})
+}
+
+func eventIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "eventId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return "", false
+	}
+	return id, true
+}
+
+// eventIDQuery reads the optional event_id filter of admin lists and reports.
+func eventIDQuery(r *http.Request) *string {
+	if id := r.URL.Query().Get("event_id"); id != "" {
+		return &id
+	}
+	return nil
+}
+
+func writeEventError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrEventNotFound):
+		writeError(w, http.StatusNotFound, "event_not_found", "The event does not exist.")
+	case errors.Is(err, service.ErrEventInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_event", err.Error())
+	case errors.Is(err, service.ErrEventInUse):
+		writeError(w, http.StatusConflict, "event_in_use", "Zu diesem Anlass gibt es bereits Bestellungen.")
+	default:
+		writeEntError(w, err)
+	}
+}
+
+func eventToResponse(e *ent.Event) eventResponse {
+	return eventResponse{
+		ID:        e.ID,
+		Name:      e.Name,
+		StartsAt:  e.StartsAt,
+		EndsAt:    e.EndsAt,
+		Active:    e.Active,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+func eventProductsToPayload(rows []*ent.EventProduct) []eventProductPayload {
+	out := make([]eventProductPayload, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, eventProductPayload{
+			ProductID:  row.ProductID,
+			Available:  row.Available,
+			PriceCents: row.PriceCents,
+		})
+	}
+	return out
+}
@@ -35,10 +35,27 @@ func (h *Handlers) StreamInventory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	snapshot := make(map[string]int, len(stocks))
+	// Variant stock is keyed by variant ID next to the products' totals.
+	var variantIDs []string
+	for _, p := range products {
+		for _, v := range p.Edges.Variants {
+			variantIDs = append(variantIDs, v.ID)
+		}
+	}
+	variantStocks, err := h.products.GetVariantStockBatch(ctx, variantIDs)
+	if err != nil {
+		_, _ = w.Write([]byte("event: error\ndata: {\"error\":\"failed to load stock\"}\n\n"))
+		_ = rc.Flush()
+		return
+	}
+
+	snapshot := make(map[string]int, len(stocks)+len(variantStocks))
 	for id, stock := range stocks {
 		snapshot[id] = stock
 	}
+	for id, stock := range variantStocks {
+		snapshot[id] = stock
+	}
 
 	data, _ := json.Marshal(snapshot)
 	_, _ = w.Write(append(append([]byte("event: inventory-snapshot\ndata: "), data...), '\n', '\n'))
@@ -47,7 +47,7 @@ func (h *Handlers) CreateInvite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	invite, err := h.invites.Create(ctx, userID, string(body.Email), nil)
+	invite, err := h.invites.Create(ctx, userID, string(body.Email), nil, bodyLocale(r, body.Locale))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invite_failed", err.Error())
 		return
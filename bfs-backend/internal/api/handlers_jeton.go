@@ -71,7 +71,7 @@ func (h *Handlers) DeleteJeton(w http.ResponseWriter, r *http.Request, jetonId s
 			details := map[string]any{"usage": inUse.Count}
 			response.WriteJSON(w, http.StatusConflict, generated.Error{
 				Code:    "jeton_in_use",
-				Message: "Dieser Jeton ist noch Produkten zugewiesen. Bitte entferne zuerst die Zuweisungen.",
+				Message: "Dieser Jeton ist noch Produkten oder Varianten zugewiesen. Bitte entferne zuerst die Zuweisungen.",
 				Details: &details,
 			})
 			return
@@ -9,11 +9,12 @@ import (
 	"backend/internal/generated/ent/product"
 	nanoid "backend/internal/id"
 	"backend/internal/response"
+	"backend/internal/service"
 )
 
 // ListMenus returns all menu-type products with their slots and options.
 // (GET /menus)
-func (h *Handlers) ListMenus(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) ListMenus(w http.ResponseWriter, r *http.Request, params generated.ListMenusParams) {
 	ctx := r.Context()
 
 	menus, err := h.products.GetMenus(ctx)
@@ -22,13 +23,46 @@ func (h *Handlers) ListMenus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	avail, err := h.products.Availability(ctx)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+
+	filter := newDietaryFilter(params.AllergenFree, params.Dietary)
 	items := make([]generated.Menu, 0, len(menus))
 	for _, m := range menus {
-		items = append(items, entProductToAPIMenu(m))
+		if filter.active() && !filter.admits(m) {
+			continue
+		}
+		item := entProductToAPIMenu(m)
+		if filter.active() && item.Slots != nil {
+			dropRejectedSlotOptions(*item.Slots, filter.rejectedOptions(m))
+		}
+		ok, next := avail.Check(m)
+		item.AvailableNow, item.NextAvailableAt = &ok, next
+		items = append(items, item)
 	}
 	response.WriteJSON(w, http.StatusOK, generated.MenuList{Items: items})
 }
 
+// dropRejectedSlotOptions removes the options whose product is in rejected.
+func dropRejectedSlotOptions(slots []generated.MenuSlot, rejected map[string]bool) {
+	for i := range slots {
+		if slots[i].Options == nil {
+			continue
+		}
+		opts := *slots[i].Options
+		kept := opts[:0]
+		for _, o := range opts {
+			if !rejected[o.OptionProductId] {
+				kept = append(kept, o)
+			}
+		}
+		slots[i].Options = &kept
+	}
+}
+
 // CreateMenu creates a new menu (product with type=menu).
 // (POST /menus)
 func (h *Handlers) CreateMenu(w http.ResponseWriter, r *http.Request) {
@@ -58,6 +92,7 @@ func (h *Handlers) CreateMenu(w http.ResponseWriter, r *http.Request) {
 		body.Image,
 		description,
 		nil, // no jeton for menus
+		service.ProductDetails{},
 	)
 	if err != nil {
 		writeEntError(w, err)
@@ -131,6 +166,7 @@ func (h *Handlers) UpdateMenu(w http.ResponseWriter, r *http.Request, menuId str
 		image,
 		description,
 		nil, // no jeton for menus
+		service.ProductDetails{},
 	)
 	if err != nil {
 		writeEntError(w, err)
@@ -158,11 +194,22 @@ func (h *Handlers) CreateMenuSlot(w http.ResponseWriter, r *http.Request, menuId
 		return
 	}
 
+	translations, ok := translationsBody(w, body.Translations)
+	if !ok {
+		return
+	}
+
 	slot, err := h.products.CreateMenuSlot(r.Context(), menuId, body.Name)
 	if err != nil {
 		writeEntError(w, err)
 		return
 	}
+	if translations != nil {
+		if slot, err = h.products.UpdateMenuSlotTranslations(r.Context(), menuId, slot.ID, translations); err != nil {
+			writeEntError(w, err)
+			return
+		}
+	}
 	response.WriteJSON(w, http.StatusCreated, toAPIMenuSlot(slot))
 }
 
@@ -179,12 +226,22 @@ func (h *Handlers) UpdateMenuSlot(w http.ResponseWriter, r *http.Request, menuId
 	if body.Name != nil {
 		name = *body.Name
 	}
+	translations, ok := translationsBody(w, body.Translations)
+	if !ok {
+		return
+	}
 
 	slot, err := h.products.UpdateMenuSlot(r.Context(), menuId, slotId, name)
 	if err != nil {
 		writeEntError(w, err)
 		return
 	}
+	if translations != nil {
+		if slot, err = h.products.UpdateMenuSlotTranslations(r.Context(), menuId, slotId, translations); err != nil {
+			writeEntError(w, err)
+			return
+		}
+	}
 	response.WriteJSON(w, http.StatusOK, toAPIMenuSlot(slot))
 }
 
@@ -253,16 +310,20 @@ func (h *Handlers) RemoveSlotOption(w http.ResponseWriter, r *http.Request, menu
 // entProductToAPIMenu converts an ent.Product (type=menu) into a generated.Menu.
 func entProductToAPIMenu(e *ent.Product) generated.Menu {
 	m := generated.Menu{
-		Id:          e.ID,
-		CategoryId:  e.CategoryID,
-		Name:        e.Name,
-		Image:       e.Image,
-		Description: e.Description,
-		PriceCents:  e.PriceCents,
-		IsActive:    e.IsActive,
-		CreatedAt:   ptr(e.CreatedAt),
-		UpdatedAt:   ptr(e.UpdatedAt),
+		Id:           e.ID,
+		CategoryId:   e.CategoryID,
+		Name:         e.Name,
+		Image:        e.Image,
+		Description:  e.Description,
+		PriceCents:   e.PriceCents,
+		IsActive:     e.IsActive,
+		Allergens:    toAPIAllergens(catalogAllergens(e)),
+		DietaryTags:  toAPIDietaryTags(e.DietaryTags),
+		Translations: toAPITranslations(e.Translations),
+		CreatedAt:    ptr(e.CreatedAt),
+		UpdatedAt:    ptr(e.UpdatedAt),
 	}
+	m.DeclaredAllergens = toAPIAllergens(e.Allergens)
 
 	// Map MenuSlots edge if loaded.
 	if slots, err := e.Edges.MenuSlotsOrErr(); err == nil && len(slots) > 0 {
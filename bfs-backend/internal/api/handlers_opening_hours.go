@@ -0,0 +1,183 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/internal/response"
+	"backend/internal/schedule"
+	"backend/internal/service"
+)
+
+// openingWindowPayload is an opening window in Europe/Zurich wall-clock
+// time. Weekdays are ISO numbers (1 = Monday … 7 = Sunday); empty means every
+// day. Exception windows ignore them.
+type openingWindowPayload struct {
+	Label    string `json:"label,omitempty"`
+	Weekdays []int  `json:"weekdays,omitempty"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+}
+
+// openingExceptionPayload replaces the weekly windows on Date; no windows
+// means closed all day.
+type openingExceptionPayload struct {
+	Date    string                 `json:"date"`
+	Label   string                 `json:"label"`
+	Windows []openingWindowPayload `json:"windows"`
+}
+
+type openingHoursPayload struct {
+	Enabled       bool                      `json:"enabled"`
+	ClosedMessage string                    `json:"closedMessage"`
+	Windows       []openingWindowPayload    `json:"windows"`
+	Exceptions    []openingExceptionPayload `json:"exceptions"`
+}
+
+type systemStatusPayload struct {
+	Enabled       bool       `json:"enabled"`
+	Source        string     `json:"source"`
+	ClosedMessage string     `json:"closedMessage,omitempty"`
+	OpensAt       *time.Time `json:"opensAt,omitempty"`
+	ClosesAt      *time.Time `json:"closesAt,omitempty"`
+	OpenUntil     *time.Time `json:"openUntil,omitempty"`
+}
+
+// GetOpeningHours (GET /v1/settings/opening-hours)
+func (h *Handlers) GetOpeningHours(w http.ResponseWriter, r *http.Request) {
+	hours, err := h.openingHours.GetOpeningHours(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, openingHoursToPayload(hours))
+}
+
+// PutOpeningHours replaces the opening hours and exceptions.
+// PUT /v1/settings/opening-hours
+func (h *Handlers) PutOpeningHours(w http.ResponseWriter, r *http.Request) {
+	var req openingHoursPayload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+
+	in := service.OpeningHours{
+		Enabled:       req.Enabled,
+		ClosedMessage: req.ClosedMessage,
+		Windows:       make([]service.OpeningWindow, 0, len(req.Windows)),
+		Exceptions:    make([]service.OpeningException, 0, len(req.Exceptions)),
+	}
+	for i, p := range req.Windows {
+		win, err := openingWindowFromPayload(p)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_opening_hours", fmt.Sprintf("window %d: %v", i+1, err))
+			return
+		}
+		in.Windows = append(in.Windows, win)
+	}
+	for _, e := range req.Exceptions {
+		ex := service.OpeningException{Date: e.Date, Label: e.Label}
+		for _, p := range e.Windows {
+			win, err := openingWindowFromPayload(p)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_opening_hours", fmt.Sprintf("exception %s: %v", e.Date, err))
+				return
+			}
+			ex.Windows = append(ex.Windows, win)
+		}
+		in.Exceptions = append(in.Exceptions, ex)
+	}
+
+	hours, err := h.openingHours.SetOpeningHours(r.Context(), in)
+	if err != nil {
+		if errors.Is(err, service.ErrOpeningHoursInvalid) {
+			writeError(w, http.StatusBadRequest, "invalid_opening_hours", err.Error())
+			return
+		}
+		writeEntError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, openingHoursToPayload(hours))
+}
+
+// PutSystemOpenUntil opens the system outside its opening hours until the
+// given time; a null time ends the override.
+// PUT /v1/settings/open-until
+func (h *Handlers) PutSystemOpenUntil(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Until *time.Time `json:"until"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	if err := h.openingHours.SetOpenUntil(r.Context(), req.Until); err != nil {
+		if errors.Is(err, service.ErrOpenUntilInPast) {
+			writeError(w, http.StatusBadRequest, "open_until_in_past", "Der Zeitpunkt muss in der Zukunft liegen.")
+			return
+		}
+		writeEntError(w, err)
+		return
+	}
+	h.GetSystemStatus(w, r)
+}
+
+func openingWindowFromPayload(p openingWindowPayload) (service.OpeningWindow, error) {
+	weekdays := schedule.AllWeekdays
+	if len(p.Weekdays) > 0 {
+		var err error
+		if weekdays, err = schedule.WeekdaysFromISO(p.Weekdays); err != nil {
+			return service.OpeningWindow{}, fmt.Errorf("weekdays must be between 1 (Monday) and 7 (Sunday)")
+		}
+	}
+	start, err := schedule.ParseClock(p.Start)
+	if err != nil {
+		return service.OpeningWindow{}, fmt.Errorf("start must be HH:MM")
+	}
+	end, err := schedule.ParseClock(p.End)
+	if err != nil {
+		return service.OpeningWindow{}, fmt.Errorf("end must be HH:MM")
+	}
+	// Time inputs cannot express 24:00; closing "at 00:00" means midnight.
+	if end == 0 {
+		end = schedule.MinutesPerDay
+	}
+	return service.OpeningWindow{
+		Label:       p.Label,
+		Weekdays:    weekdays,
+		StartMinute: start,
+		EndMinute:   end,
+	}, nil
+}
+
+func openingHoursToPayload(hours *service.OpeningHours) openingHoursPayload {
+	out := openingHoursPayload{
+		Enabled:       hours.Enabled,
+		ClosedMessage: hours.ClosedMessage,
+		Windows:       make([]openingWindowPayload, 0, len(hours.Windows)),
+		Exceptions:    make([]openingExceptionPayload, 0, len(hours.Exceptions)),
+	}
+	for _, w := range hours.Windows {
+		out.Windows = append(out.Windows, openingWindowPayload{
+			Label:    w.Label,
+			Weekdays: w.Weekdays.ISO(),
+			Start:    schedule.FormatClock(w.StartMinute),
+			End:      schedule.FormatClock(w.EndMinute),
+		})
+	}
+	for _, e := range hours.Exceptions {
+		ex := openingExceptionPayload{Date: e.Date, Label: e.Label, Windows: make([]openingWindowPayload, 0, len(e.Windows))}
+		for _, w := range e.Windows {
+			ex.Windows = append(ex.Windows, openingWindowPayload{
+				Start: schedule.FormatClock(w.StartMinute),
+				End:   schedule.FormatClock(w.EndMinute),
+			})
+		}
+		out.Exceptions = append(out.Exceptions, ex)
+	}
+	return out
+}
@@ -10,6 +10,7 @@ import (
 	"backend/internal/auth"
 	"backend/internal/generated/api/generated"
 	"backend/internal/generated/ent/order"
+	"backend/internal/i18n"
 	"backend/internal/repository"
 	"backend/internal/response"
 	"backend/internal/service"
@@ -51,7 +52,7 @@ func (h *Handlers) ListOrders(w http.ResponseWriter, r *http.Request, params gen
 		return
 	}
 
-	listParams := service.OrderListParams{}
+	listParams := service.OrderListParams{EventID: params.EventId}
 	if params.Status != nil {
 		s := order.Status(*params.Status)
 		listParams.Status = &s
@@ -127,6 +128,7 @@ func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request, params ge
 	for _, item := range body.Items {
 		ci := service.CheckoutItemInput{
 			ProductID: item.ProductId,
+			VariantID: item.VariantId,
 			Quantity:  item.Quantity,
 		}
 		if item.MenuSelections != nil {
@@ -153,6 +155,7 @@ func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request, params ge
 		Items:         checkoutItems,
 		CustomerEmail: customerEmail,
 		Origin:        origin,
+		Locale:        i18n.FromRequest(r),
 	}
 
 	var userID *string
@@ -167,6 +170,22 @@ func (h *Handlers) CreateOrder(w http.ResponseWriter, r *http.Request, params ge
 		if claimedID != nil {
 			_ = h.idempotency.Discard(ctx, *claimedID)
 		}
+		if errors.Is(err, service.ErrProductNotAtEvent) {
+			writeError(w, http.StatusConflict, "product_not_at_event", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrProductUnavailable) {
+			writeError(w, http.StatusConflict, "product_unavailable", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrVariantRequired) {
+			writeError(w, http.StatusBadRequest, "variant_required", err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrVariantInvalid) {
+			writeError(w, http.StatusBadRequest, "invalid_variant", err.Error())
+			return
+		}
 		writeError(w, http.StatusBadRequest, "order_failed", err.Error())
 		return
 	}
@@ -197,7 +216,11 @@ func (h *Handlers) GetOrder(w http.ResponseWriter, r *http.Request, orderId stri
 		writeEntError(w, err)
 		return
 	}
-	response.WriteJSON(w, http.StatusOK, toAPIOrder(o))
+	apiOrder := toAPIOrder(o)
+	if payload := h.qr.OrderPayload(o); payload != "" {
+		apiOrder.QrPayload = &payload
+	}
+	response.WriteJSON(w, http.StatusOK, apiOrder)
 }
 
 // UpdateOrderStatus updates the status of an order.
@@ -313,13 +336,8 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 		if did, ok := auth.GetDeviceID(ctx); ok {
 			deviceID = &did
 		}
-		if body.Club100 != nil {
-			if err := h.club100.RecordRedemption(ctx, body.Club100.ElvantoPersonId, body.Club100.ElvantoPersonName, id, body.Club100.FreeQuantity); err != nil {
-				h.logger.Warn("failed to record club100 redemption for cash payment", zap.Error(err))
-			}
-		}
-		if err := h.pos.PayCash(ctx, id, deviceID); err != nil {
-			writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+		if err := h.pos.PayCash(ctx, id, deviceID, club100Redemption(body.Club100)); err != nil {
+			writePaymentError(w, err)
 			return
 		}
 		resp := map[string]any{"orderId": id, "method": "cash"}
@@ -334,11 +352,6 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 		if did, ok := auth.GetDeviceID(ctx); ok {
 			deviceID = &did
 		}
-		if body.Club100 != nil {
-			if err := h.club100.RecordRedemption(ctx, body.Club100.ElvantoPersonId, body.Club100.ElvantoPersonName, id, body.Club100.FreeQuantity); err != nil {
-				h.logger.Warn("failed to record club100 redemption for card payment", zap.Error(err))
-			}
-		}
 		var card *repository.CardMeta
 		if body.Card != nil {
 			card = &repository.CardMeta{
@@ -348,8 +361,8 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 				TransactionID: body.Card.TransactionId,
 			}
 		}
-		if err := h.pos.PayCard(ctx, id, deviceID, card); err != nil {
-			writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+		if err := h.pos.PayCard(ctx, id, deviceID, card, club100Redemption(body.Club100)); err != nil {
+			writePaymentError(w, err)
 			return
 		}
 		resp := map[string]any{"orderId": id, "method": "card"}
@@ -365,13 +378,8 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 			if did, ok := auth.GetDeviceID(ctx); ok {
 				deviceID = &did
 			}
-			if body.Club100 != nil {
-				if err := h.club100.RecordRedemption(ctx, body.Club100.ElvantoPersonId, body.Club100.ElvantoPersonName, id, body.Club100.FreeQuantity); err != nil {
-					h.logger.Warn("failed to record club100 redemption for twint payment", zap.Error(err))
-				}
-			}
-			if err := h.pos.PayTwint(ctx, id, deviceID); err != nil {
-				writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+			if err := h.pos.PayTwint(ctx, id, deviceID, club100Redemption(body.Club100)); err != nil {
+				writePaymentError(w, err)
 				return
 			}
 			resp := map[string]any{"orderId": id, "method": "twint", "channel": "pos"}
@@ -441,7 +449,7 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 			deviceID = &did
 		}
 		if err := h.pos.PayGratisGuest(ctx, id, deviceID); err != nil {
-			writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+			writePaymentError(w, err)
 			return
 		}
 		resp := map[string]any{"orderId": id, "method": "gratis_guest"}
@@ -454,7 +462,7 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 			deviceID = &did
 		}
 		if err := h.pos.PayGratisVIP(ctx, id, deviceID); err != nil {
-			writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+			writePaymentError(w, err)
 			return
 		}
 		resp := map[string]any{"orderId": id, "method": "gratis_vip"}
@@ -467,7 +475,7 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 			deviceID = &did
 		}
 		if err := h.pos.PayGratisStaff(ctx, id, deviceID); err != nil {
-			writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+			writePaymentError(w, err)
 			return
 		}
 		resp := map[string]any{"orderId": id, "method": "gratis_staff"}
@@ -483,12 +491,8 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 		if did, ok := auth.GetDeviceID(ctx); ok {
 			deviceID = &did
 		}
-		if err := h.pos.PayGratis100Club(ctx, id, deviceID, body.Club100.ElvantoPersonId, body.Club100.ElvantoPersonName, body.Club100.FreeQuantity); err != nil {
-			if errors.Is(err, service.ErrProductNotFreeForClub100) {
-				writeError(w, http.StatusBadRequest, "product_not_free", "Eines oder mehrere Produkte in dieser Bestellung sind nicht als Gratis-Produkt für 100 Club konfiguriert.")
-				return
-			}
-			writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+		if err := h.pos.PayGratis100Club(ctx, id, deviceID, *club100Redemption(body.Club100)); err != nil {
+			writePaymentError(w, err)
 			return
 		}
 		resp := map[string]any{"orderId": id, "method": "gratis_100club", "elvantoPersonId": body.Club100.ElvantoPersonId}
@@ -500,23 +504,58 @@ func (h *Handlers) CreateOrderPayment(w http.ResponseWriter, r *http.Request, or
 	}
 }
 
-// ListEvents returns days with paid orders for admin dashboard navigation.
-// (GET /events)
-func (h *Handlers) ListEvents(w http.ResponseWriter, r *http.Request) {
-	events, err := h.orders.ListEvents(r.Context())
+// club100Redemption converts the optional Club100 part of a payment request.
+func club100Redemption(in *generated.Club100PaymentInfo) *service.Club100RedemptionInput {
+	if in == nil {
+		return nil
+	}
+	return &service.Club100RedemptionInput{
+		ExternalPersonID: in.ElvantoPersonId,
+		PersonName:       in.ElvantoPersonName,
+		FreeQuantity:     in.FreeQuantity,
+	}
+}
+
+// writePaymentError maps POS payment failures. Club100 and device restriction
+// errors carry codes the POS offline queue treats as permanent, so it stops
+// retrying them.
+func writePaymentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFreeForClub100):
+		writeError(w, http.StatusBadRequest, "product_not_free", "Eines oder mehrere Produkte in dieser Bestellung sind nicht als Gratis-Produkt für 100 Club konfiguriert.")
+	case errors.Is(err, service.ErrClub100InsufficientRedemptions):
+		writeError(w, http.StatusConflict, "insufficient_remaining_redemptions", "Dieses 100 Club Mitglied hat nicht mehr genügend Gratis-Produkte übrig.")
+	case errors.Is(err, service.ErrPaymentMethodNotAllowed):
+		writeError(w, http.StatusForbidden, "payment_method_not_allowed", "Diese Zahlungsart ist auf diesem Gerät nicht freigegeben.")
+	case errors.Is(err, service.ErrCategoryNotOnDevice):
+		writeError(w, http.StatusForbidden, "category_not_on_device", "Eines oder mehrere Produkte dieser Bestellung sind auf diesem Gerät nicht freigegeben.")
+	case errors.Is(err, service.ErrClub100MemberNotFound):
+		writeError(w, http.StatusBadRequest, "club100_member_not_found", "Dieses 100 Club Mitglied ist nicht im Mitgliederverzeichnis.")
+	case errors.Is(err, repository.ErrOrderNotPending):
+		writeError(w, http.StatusConflict, "not_pending", "Diese Bestellung ist bereits bezahlt oder storniert.")
+	default:
+		writeError(w, http.StatusBadRequest, "payment_failed", err.Error())
+	}
+}
+
+// ListEventDays returns days with paid orders for admin dashboard navigation,
+// optionally only those of one event.
+// (GET /events/days)
+func (h *Handlers) ListEventDays(w http.ResponseWriter, r *http.Request, params generated.ListEventDaysParams) {
+	days, err := h.orders.ListEventDays(r.Context(), params.EventId)
 	if err != nil {
 		writeEntError(w, err)
 		return
 	}
 
-	items := make([]generated.Event, 0, len(events))
-	for _, e := range events {
-		items = append(items, generated.Event{
-			Year:       e.Year,
-			Month:      e.Month,
-			Day:        e.Day,
-			OrderCount: e.OrderCount,
+	items := make([]generated.EventDay, 0, len(days))
+	for _, d := range days {
+		items = append(items, generated.EventDay{
+			Year:       d.Year,
+			Month:      d.Month,
+			Day:        d.Day,
+			OrderCount: d.OrderCount,
 		})
 	}
-	response.WriteJSON(w, http.StatusOK, generated.EventList{Items: items})
+	response.WriteJSON(w, http.StatusOK, generated.EventDayList{Items: items})
 }
@@ -1,11 +1,18 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	"backend/internal/auth"
 	"backend/internal/generated/api/generated"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/device"
+	nanoid "backend/internal/id"
+	"backend/internal/repository"
 	"backend/internal/response"
+	"backend/internal/service"
 )
 
 // GetCurrentPos returns the POS device information for the current device auth context.
@@ -19,30 +26,18 @@ func (h *Handlers) GetCurrentPos(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	device, err := h.pos.GetDeviceByID(ctx, deviceID)
+	d, err := h.pos.GetDeviceByID(ctx, deviceID)
 	if err != nil {
 		writeEntError(w, err)
 		return
 	}
 
-	apiDevice := toAPIDevice(device)
-	posDevice := generated.POSDevice{
-		Id:        apiDevice.Id,
-		Name:      apiDevice.Name,
-		Model:     apiDevice.Model,
-		Os:        apiDevice.Os,
-		Status:    apiDevice.Status,
-		CreatedAt: apiDevice.CreatedAt,
-	}
-
-	settingsData, err := h.settings.GetSettingsWithProducts(ctx)
-	if err == nil && settingsData != nil {
-		missingJetons, _ := h.products.CountActiveWithoutJeton(ctx)
-		apiSettings := toAPISettings(settingsData, int(missingJetons))
-		posDevice.Settings = &apiSettings
+	settingsData, _ := h.settings.GetSettingsWithProducts(ctx)
+	var missingJetons int64
+	if settingsData != nil {
+		missingJetons, _ = h.products.CountActiveWithoutJeton(ctx)
 	}
-
-	response.WriteJSON(w, http.StatusOK, posDevice)
+	response.WriteJSON(w, http.StatusOK, toAPIPOSDevice(d, settingsData, int(missingJetons)))
 }
 
 // ListPosDevices returns all POS-type devices with settings.
@@ -58,27 +53,117 @@ func (h *Handlers) ListPosDevices(w http.ResponseWriter, r *http.Request) {
 	}
 
 	settingsData, _ := h.settings.GetSettingsWithProducts(ctx)
-	var apiSettings *generated.Settings
+	var missingJetons int64
 	if settingsData != nil {
-		missingJetons, _ := h.products.CountActiveWithoutJeton(ctx)
-		s := toAPISettings(settingsData, int(missingJetons))
-		apiSettings = &s
+		missingJetons, _ = h.products.CountActiveWithoutJeton(ctx)
 	}
 
 	items := make([]generated.POSDevice, 0, len(devices))
 	for _, d := range devices {
-		apiDevice := toAPIDevice(d)
-		posDevice := generated.POSDevice{
-			Id:        apiDevice.Id,
-			Name:      apiDevice.Name,
-			Model:     apiDevice.Model,
-			Os:        apiDevice.Os,
-			Status:    apiDevice.Status,
-			CreatedAt: apiDevice.CreatedAt,
-			Settings:  apiSettings,
-		}
-		items = append(items, posDevice)
+		items = append(items, toAPIPOSDevice(d, settingsData, int(missingJetons)))
 	}
 
 	response.WriteJSON(w, http.StatusOK, generated.POSDeviceList{Items: items})
 }
+
+// UpdatePosDeviceConfig replaces a POS device's configuration overrides.
+// (PUT /pos/devices/{deviceId}/config)
+func (h *Handlers) UpdatePosDeviceConfig(w http.ResponseWriter, r *http.Request, deviceId string) {
+	ctx := r.Context()
+	if !nanoid.Valid(deviceId) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+
+	var body generated.POSConfigOverrides
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	overrides := repository.DevicePosConfig{}
+	if body.PosMode != nil {
+		mode := device.PosMode(*body.PosMode)
+		overrides.PosMode = &mode
+	}
+	if body.PaymentMethods != nil {
+		overrides.PaymentMethods = *body.PaymentMethods
+	}
+	if body.CategoryIds != nil {
+		overrides.VisibleCategoryIDs = *body.CategoryIds
+	}
+	if body.GratisTypes != nil {
+		overrides.GratisTypes = *body.GratisTypes
+	}
+
+	updated, err := h.pos.SetConfig(ctx, deviceId, overrides)
+	if err != nil {
+		var missingErr service.MissingJetonForActiveProductsError
+		switch {
+		case errors.Is(err, service.ErrPosConfigInvalid):
+			writeError(w, http.StatusBadRequest, "invalid_pos_config", err.Error())
+		case errors.As(err, &missingErr):
+			response.WriteJSON(w, http.StatusBadRequest, map[string]any{
+				"error":   "missing_jetons",
+				"message": "Active products missing jeton",
+				"missing": missingErr.Count,
+			})
+		default:
+			writeEntError(w, err)
+		}
+		return
+	}
+
+	settingsData, _ := h.settings.GetSettingsWithProducts(ctx)
+	var missingJetons int64
+	if settingsData != nil {
+		missingJetons, _ = h.products.CountActiveWithoutJeton(ctx)
+	}
+	response.WriteJSON(w, http.StatusOK, toAPIPOSDevice(updated, settingsData, int(missingJetons)))
+}
+
+// toAPIPOSDevice maps a POS device with its overrides merged over the global
+// settings; the returned settings carry the device's effective POS mode.
+func toAPIPOSDevice(d *ent.Device, settingsData *ent.Settings, missingJetons int) generated.POSDevice {
+	apiDevice := toAPIDevice(d)
+	posDevice := generated.POSDevice{
+		Id:        apiDevice.Id,
+		Name:      apiDevice.Name,
+		Model:     apiDevice.Model,
+		Os:        apiDevice.Os,
+		Status:    apiDevice.Status,
+		CreatedAt: apiDevice.CreatedAt,
+	}
+	if settingsData == nil {
+		return posDevice
+	}
+	cfg := service.MergePosConfig(settingsData, d)
+	apiSettings := toAPISettings(settingsData, missingJetons)
+	apiSettings.PosMode = generated.PosFulfillmentMode(cfg.PosMode)
+	posDevice.Settings = &apiSettings
+
+	apiConfig := generated.POSConfig{
+		PosMode:        generated.PosFulfillmentMode(cfg.PosMode),
+		PaymentMethods: cfg.PaymentMethods,
+		GratisTypes:    cfg.GratisTypes,
+		CategoryIds:    optionalSlice(cfg.CategoryIDs),
+		Overrides: generated.POSConfigOverrides{
+			PaymentMethods: optionalSlice(cfg.Overrides.PaymentMethods),
+			CategoryIds:    optionalSlice(cfg.Overrides.VisibleCategoryIDs),
+			GratisTypes:    optionalSlice(cfg.Overrides.GratisTypes),
+		},
+	}
+	if cfg.Overrides.PosMode != nil {
+		mode := generated.POSConfigOverridesPosMode(*cfg.Overrides.PosMode)
+		apiConfig.Overrides.PosMode = &mode
+	}
+	posDevice.Config = &apiConfig
+	return posDevice
+}
+
+// optionalSlice maps nil (inherit/all) to a JSON null.
+func optionalSlice(s []string) *[]string {
+	if s == nil {
+		return nil
+	}
+	return &s
+}
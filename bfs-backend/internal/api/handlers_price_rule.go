@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/internal/generated/api/generated"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/pricerule"
+	"backend/internal/repository"
+	"backend/internal/response"
+	"backend/internal/schedule"
+	"backend/internal/service"
+)
+
+// priceRulePayload is a price rule with its window in Europe/Zurich
+// wall-clock time. Weekdays are ISO numbers (1 = Monday … 7 = Sunday); empty
+// means every day. Kind "percent" takes value percent off, "amount" value
+// cents off.
+type priceRulePayload struct {
+	ID         string    `json:"id,omitempty"`
+	Name       string    `json:"name"`
+	ProductID  *string   `json:"productId"`
+	CategoryID *string   `json:"categoryId"`
+	Weekdays   []int     `json:"weekdays,omitempty"`
+	Start      string    `json:"start"`
+	End        string    `json:"end"`
+	Kind       string    `json:"kind"`
+	Value      int64     `json:"value"`
+	Active     *bool     `json:"active,omitempty"`
+	CreatedAt  time.Time `json:"createdAt,omitzero"`
+	UpdatedAt  time.Time `json:"updatedAt,omitzero"`
+}
+
+// updatePriceRuleRequest changes the given fields. Setting productId scopes
+// the rule to the product instead of its category, and vice versa.
+type updatePriceRuleRequest struct {
+	Name       *string `json:"name,omitempty"`
+	ProductID  *string `json:"productId,omitempty"`
+	CategoryID *string `json:"categoryId,omitempty"`
+	Weekdays   *[]int  `json:"weekdays,omitempty"`
+	Start      *string `json:"start,omitempty"`
+	End        *string `json:"end,omitempty"`
+	Kind       *string `json:"kind,omitempty"`
+	Value      *int64  `json:"value,omitempty"`
+	Active     *bool   `json:"active,omitempty"`
+}
+
+type priceRuleUsagePayload struct {
+	PriceRuleID   string `json:"priceRuleId"`
+	Quantity      int    `json:"quantity"`
+	RevenueCents  int64  `json:"revenueCents"`
+	DiscountCents int64  `json:"discountCents"`
+}
+
+// ListPriceRules (GET /v1/price-rules)
+func (h *Handlers) ListPriceRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.priceRules.List(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	items := make([]priceRulePayload, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, priceRuleToPayload(rule))
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// CreatePriceRule (POST /v1/price-rules)
+func (h *Handlers) CreatePriceRule(w http.ResponseWriter, r *http.Request) {
+	var req priceRulePayload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	weekdays, ok := priceRuleWeekdays(w, req.Weekdays)
+	if !ok {
+		return
+	}
+	start, ok := priceRuleClock(w, req.Start, false)
+	if !ok {
+		return
+	}
+	end, ok := priceRuleClock(w, req.End, true)
+	if !ok {
+		return
+	}
+	created, err := h.priceRules.Create(r.Context(), repository.PriceRuleInput{
+		Name:        req.Name,
+		ProductID:   req.ProductID,
+		CategoryID:  req.CategoryID,
+		Weekdays:    weekdays,
+		StartMinute: start,
+		EndMinute:   end,
+		Kind:        pricerule.Kind(req.Kind),
+		Value:       req.Value,
+		Active:      req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		writePriceRuleError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusCreated, priceRuleToPayload(created))
+}
+
+// UpdatePriceRule (PATCH /v1/price-rules/{priceRuleId})
+func (h *Handlers) UpdatePriceRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "priceRuleId")
+	if !ok {
+		return
+	}
+	var req updatePriceRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	patch := service.PriceRulePatch{
+		Name:       req.Name,
+		ProductID:  req.ProductID,
+		CategoryID: req.CategoryID,
+		Value:      req.Value,
+		Active:     req.Active,
+	}
+	if req.Weekdays != nil {
+		weekdays, ok := priceRuleWeekdays(w, *req.Weekdays)
+		if !ok {
+			return
+		}
+		patch.Weekdays = &weekdays
+	}
+	if req.Start != nil {
+		start, ok := priceRuleClock(w, *req.Start, false)
+		if !ok {
+			return
+		}
+		patch.StartMinute = &start
+	}
+	if req.End != nil {
+		end, ok := priceRuleClock(w, *req.End, true)
+		if !ok {
+			return
+		}
+		patch.EndMinute = &end
+	}
+	if req.Kind != nil {
+		kind := pricerule.Kind(*req.Kind)
+		patch.Kind = &kind
+	}
+	updated, err := h.priceRules.Update(r.Context(), id, patch)
+	if err != nil {
+		writePriceRuleError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, priceRuleToPayload(updated))
+}
+
+// DeletePriceRule (DELETE /v1/price-rules/{priceRuleId})
+func (h *Handlers) DeletePriceRule(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(w, r, "priceRuleId")
+	if !ok {
+		return
+	}
+	if err := h.priceRules.Delete(r.Context(), id); err != nil {
+		writePriceRuleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPriceRuleReport sums the paid order lines each rule priced, optionally
+// of one event.
+// GET /v1/price-rules/report
+func (h *Handlers) GetPriceRuleReport(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.priceRules.Usage(r.Context(), eventIDQuery(r))
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	items := make([]priceRuleUsagePayload, 0, len(usage))
+	for _, u := range usage {
+		items = append(items, priceRuleUsagePayload(u))
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// applyPriceRules lowers the prices of p and its variants by the rules in
+// effect, the same way checkout does.
+func applyPriceRules(ap *generated.Product, p *ent.Product, rules *service.PriceRules) {
+	if price, rule := rules.Apply(p, ap.PriceCents); rule != nil {
+		list := ap.PriceCents
+		ap.PriceCents, ap.ListPriceCents, ap.PriceRuleId = price, &list, &rule.ID
+	}
+	if ap.Variants == nil {
+		return
+	}
+	for i := range *ap.Variants {
+		v := &(*ap.Variants)[i]
+		if price, rule := rules.Apply(p, v.PriceCents); rule != nil {
+			list := v.PriceCents
+			v.PriceCents, v.ListPriceCents, v.PriceRuleId = price, &list, &rule.ID
+		}
+	}
+}
+
+func priceRuleWeekdays(w http.ResponseWriter, days []int) (int, bool) {
+	if len(days) == 0 {
+		return int(schedule.AllWeekdays), true
+	}
+	weekdays, err := schedule.WeekdaysFromISO(days)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_price_rule", "weekdays must be between 1 (Monday) and 7 (Sunday)")
+		return 0, false
+	}
+	return int(weekdays), true
+}
+
+// priceRuleClock parses "HH:MM". As with opening hours, an end of "00:00"
+// means midnight at the end of the day.
+func priceRuleClock(w http.ResponseWriter, s string, end bool) (int, bool) {
+	minute, err := schedule.ParseClock(s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_price_rule", "start and end must be HH:MM")
+		return 0, false
+	}
+	if end && minute == 0 {
+		minute = schedule.MinutesPerDay
+	}
+	return minute, true
+}
+
+func writePriceRuleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrPriceRuleNotFound):
+		writeError(w, http.StatusNotFound, "price_rule_not_found", "The price rule does not exist.")
+	case errors.Is(err, service.ErrPriceRuleInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_price_rule", err.Error())
+	case errors.Is(err, service.ErrPriceRuleInUse):
+		writeError(w, http.StatusConflict, "price_rule_in_use", "Diese Preisregel wurde bereits verrechnet.")
+	default:
+		writeEntError(w, err)
+	}
+}
+
+func priceRuleToPayload(rule *ent.PriceRule) priceRulePayload {
+	active := rule.Active
+	return priceRulePayload{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		ProductID:  rule.ProductID,
+		CategoryID: rule.CategoryID,
+		Weekdays:   schedule.Weekdays(rule.Weekdays).ISO(),
+		Start:      schedule.FormatClock(rule.StartMinute),
+		End:        schedule.FormatClock(rule.EndMinute),
+		Kind:       string(rule.Kind),
+		Value:      rule.Value,
+		Active:     &active,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
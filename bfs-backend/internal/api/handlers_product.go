@@ -5,12 +5,17 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"slices"
+	"time"
 
+	"backend/internal/allergen"
 	"backend/internal/generated/api/generated"
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/product"
+	"backend/internal/i18n"
 	nanoid "backend/internal/id"
 	"backend/internal/response"
+	"backend/internal/service"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -34,7 +39,80 @@ func (h *Handlers) ListProducts(w http.ResponseWriter, r *http.Request, params g
 		return
 	}
 
+	avail, err := h.products.Availability(ctx)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+
+	// Customers and POS see the active event's menu and prices, lowered by
+	// the price rules in effect.
+	var (
+		catalog *service.EventCatalog
+		rules   *service.PriceRules
+	)
+	if params.Catalog == nil || *params.Catalog == generated.Event {
+		if catalog, err = h.events.Current(ctx); err != nil {
+			writeEntError(w, err)
+			return
+		}
+		if rules, err = h.priceRules.At(ctx, time.Now()); err != nil {
+			writeEntError(w, err)
+			return
+		}
+		available := make([]*ent.Product, 0, len(products))
+		for _, p := range products {
+			if catalog.Available(p.ID) {
+				available = append(available, p)
+			}
+		}
+		products = available
+	}
+
+	filter := newDietaryFilter(params.AllergenFree, params.Dietary)
+	if filter.active() {
+		matching := make([]*ent.Product, 0, len(products))
+		for _, p := range products {
+			if filter.admits(p) {
+				matching = append(matching, p)
+			}
+		}
+		products = matching
+	}
+
+	// The event catalog is shown to customers in their language; the base
+	// catalog stays German for editing.
+	locale := i18n.German
+	if catalog != nil {
+		locale = i18n.FromRequest(r)
+		w.Header().Set("Content-Language", string(locale))
+		w.Header().Add("Vary", "Accept-Language")
+	}
+
 	apiProducts := toAPIProducts(products)
+	for i, p := range products {
+		localizeProduct(&apiProducts[i], p, locale)
+		apiProducts[i].PriceCents = catalog.PriceCents(p)
+		if apiProducts[i].Variants != nil {
+			for j := range *apiProducts[i].Variants {
+				v := &(*apiProducts[i].Variants)[j]
+				v.PriceCents = catalog.VariantPriceCents(p, v.PriceCents)
+			}
+		}
+		if catalog != nil && apiProducts[i].MenuSlots != nil {
+			dropUnavailableOptions(*apiProducts[i].MenuSlots, catalog)
+			dropOutOfWindowOptions(*apiProducts[i].MenuSlots, p, avail)
+		}
+		if filter.active() && apiProducts[i].MenuSlots != nil {
+			rejected := filter.rejectedOptions(p)
+			filterOptions(*apiProducts[i].MenuSlots, func(id string) bool { return !rejected[id] })
+		}
+		if catalog != nil {
+			dropInactiveVariants(&apiProducts[i])
+		}
+		applyPriceRules(&apiProducts[i], p, rules)
+		setAvailability(&apiProducts[i], p, avail)
+	}
 
 	// Enrich with inventory stock levels.
 	ids := make([]string, len(products))
@@ -48,6 +126,7 @@ func (h *Handlers) ListProducts(w http.ResponseWriter, r *http.Request, params g
 			}
 		}
 	}
+	h.enrichVariantStock(ctx, apiProducts)
 
 	response.WriteJSON(w, http.StatusOK, generated.ProductList{
 		Items: apiProducts,
@@ -79,6 +158,15 @@ func (h *Handlers) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		description = nil
 	}
 
+	allergens, dietaryTags, ok := dietaryLists(w, body.Allergens, body.DietaryTags)
+	if !ok {
+		return
+	}
+	translations, ok := translationsBody(w, body.Translations)
+	if !ok {
+		return
+	}
+
 	prod, err := h.products.Create(
 		r.Context(),
 		body.CategoryId,
@@ -89,6 +177,7 @@ func (h *Handlers) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		body.Image,
 		description,
 		jetonID,
+		service.ProductDetails{Allergens: allergens, DietaryTags: dietaryTags, Translations: translations},
 	)
 	if err != nil {
 		writeEntError(w, err)
@@ -102,7 +191,7 @@ func (h *Handlers) CreateProduct(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) GetProduct(w http.ResponseWriter, r *http.Request, productId string) {
 	ctx := r.Context()
 	id := productId
-	prod, err := h.products.GetByID(ctx, id)
+	prod, err := h.products.GetByIDWithRelations(ctx, id)
 	if err != nil {
 		writeEntError(w, err)
 		return
@@ -112,6 +201,7 @@ func (h *Handlers) GetProduct(w http.ResponseWriter, r *http.Request, productId
 		s := int(stock)
 		ap.Stock = &s
 	}
+	h.enrichVariantStock(ctx, []generated.Product{ap})
 	response.WriteJSON(w, http.StatusOK, ap)
 }
 
@@ -166,6 +256,14 @@ func (h *Handlers) UpdateProduct(w http.ResponseWriter, r *http.Request, product
 		id := *body.JetonId
 		jetonID = &id
 	}
+	allergens, dietaryTags, ok := dietaryLists(w, body.Allergens, body.DietaryTags)
+	if !ok {
+		return
+	}
+	translations, ok := translationsBody(w, body.Translations)
+	if !ok {
+		return
+	}
 
 	prod, err := h.products.Update(
 		ctx,
@@ -178,6 +276,7 @@ func (h *Handlers) UpdateProduct(w http.ResponseWriter, r *http.Request, product
 		image,
 		description,
 		jetonID,
+		service.ProductDetails{Allergens: allergens, DietaryTags: dietaryTags, Translations: translations},
 	)
 	if err != nil {
 		writeEntError(w, err)
@@ -326,6 +425,7 @@ func (h *Handlers) SetProductActive(w http.ResponseWriter, r *http.Request) {
 		existing.Image,
 		existing.Description,
 		existing.JetonID,
+		service.ProductDetails{},
 	)
 	if err != nil {
 		writeEntError(w, err)
@@ -402,7 +502,7 @@ func (h *Handlers) UploadProductImage(w http.ResponseWriter, r *http.Request, pr
 		return
 	}
 
-	_, err = h.products.Update(ctx, id, existing.CategoryID, existing.Type, existing.Name, existing.PriceCents, existing.IsActive, &url, existing.Description, existing.JetonID)
+	_, err = h.products.Update(ctx, id, existing.CategoryID, existing.Type, existing.Name, existing.PriceCents, existing.IsActive, &url, existing.Description, existing.JetonID, service.ProductDetails{})
 	if err != nil {
 		writeEntError(w, err)
 		return
@@ -427,7 +527,7 @@ func (h *Handlers) DeleteProductImage(w http.ResponseWriter, r *http.Request, pr
 		}
 	}
 
-	_, err = h.products.Update(ctx, id, existing.CategoryID, existing.Type, existing.Name, existing.PriceCents, existing.IsActive, nil, existing.Description, existing.JetonID)
+	_, err = h.products.Update(ctx, id, existing.CategoryID, existing.Type, existing.Name, existing.PriceCents, existing.IsActive, nil, existing.Description, existing.JetonID, service.ProductDetails{})
 	if err != nil {
 		writeEntError(w, err)
 		return
@@ -435,3 +535,197 @@ func (h *Handlers) DeleteProductImage(w http.ResponseWriter, r *http.Request, pr
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// dropUnavailableOptions removes the menu options the event does not sell.
+func dropUnavailableOptions(slots []generated.MenuSlotSummary, catalog *service.EventCatalog) {
+	filterOptions(slots, catalog.Available)
+}
+
+// dropOutOfWindowOptions removes the options of menu p that are outside
+// their availability windows now; checkout would reject them.
+func dropOutOfWindowOptions(slots []generated.MenuSlotSummary, p *ent.Product, avail *service.CatalogAvailability) {
+	unavailable := make(map[string]bool)
+	for _, slot := range p.Edges.MenuSlots {
+		for _, o := range slot.Edges.Options {
+			if op := o.Edges.OptionProduct; op != nil && !avail.Available(op) {
+				unavailable[op.ID] = true
+			}
+		}
+	}
+	if len(unavailable) == 0 {
+		return
+	}
+	filterOptions(slots, func(id string) bool { return !unavailable[id] })
+}
+
+func filterOptions(slots []generated.MenuSlotSummary, keep func(productID string) bool) {
+	for i := range slots {
+		if slots[i].Options == nil {
+			continue
+		}
+		opts := *slots[i].Options
+		kept := opts[:0]
+		for _, o := range opts {
+			if o.ProductId == nil || keep(*o.ProductId) {
+				kept = append(kept, o)
+			}
+		}
+		slots[i].Options = &kept
+	}
+}
+
+// setAvailability reports whether p can be ordered now and, if not, when
+// it next can.
+func setAvailability(ap *generated.Product, p *ent.Product, avail *service.CatalogAvailability) {
+	ok, next := avail.Check(p)
+	ap.AvailableNow, ap.NextAvailableAt = &ok, next
+}
+
+// translationsBody validates the translations of a catalog body; it returns
+// nil when the body leaves them out.
+func translationsBody(w http.ResponseWriter, t *generated.Translations) (i18n.Translations, bool) {
+	if t == nil {
+		return nil, true
+	}
+	out, err := fromAPITranslations(*t).Normalize(20)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_translations", err.Error())
+		return nil, false
+	}
+	return out, true
+}
+
+// localizeProduct puts the names and descriptions of a catalog product, its
+// category, slots and slot options in l. Untranslated texts stay German.
+func localizeProduct(ap *generated.Product, e *ent.Product, l i18n.Locale) {
+	if l == i18n.German {
+		return
+	}
+	ap.Name = e.Translations.Name(l, e.Name)
+	ap.Description = e.Translations.Description(l, e.Description)
+	if c := e.Edges.Category; c != nil && ap.Category != nil {
+		ap.Category.Name = ptr(c.Translations.Name(l, c.Name))
+	}
+	if ap.MenuSlots == nil {
+		return
+	}
+	slots := make(map[string]*ent.MenuSlot)
+	options := make(map[string]*ent.Product)
+	for _, s := range e.Edges.MenuSlots {
+		slots[s.ID] = s
+		for _, o := range s.Edges.Options {
+			if o.Edges.OptionProduct != nil {
+				options[o.OptionProductID] = o.Edges.OptionProduct
+			}
+		}
+	}
+	for i := range *ap.MenuSlots {
+		ms := &(*ap.MenuSlots)[i]
+		if s, ok := slots[derefStr(ms.Id)]; ok {
+			ms.Name = ptr(s.Translations.Name(l, s.Name))
+		}
+		if ms.Options == nil {
+			continue
+		}
+		for j := range *ms.Options {
+			o := &(*ms.Options)[j]
+			if op, ok := options[derefStr(o.ProductId)]; ok {
+				o.Name = ptr(op.Translations.Name(l, op.Name))
+				o.Description = op.Translations.Description(l, op.Description)
+			}
+		}
+	}
+}
+
+// dietaryLists validates the allergens and dietary tags of a product body;
+// a list left out of the body stays nil. The enums are not checked when the
+// body is decoded.
+func dietaryLists(w http.ResponseWriter, allergens *[]generated.Allergen, tags *[]generated.DietaryTag) ([]string, []string, bool) {
+	var a, t []string
+	var err error
+	if allergens != nil {
+		keys := make([]string, 0, len(*allergens))
+		for _, k := range *allergens {
+			keys = append(keys, string(k))
+		}
+		if a, err = allergen.NormalizeAllergens(keys); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_allergen", err.Error())
+			return nil, nil, false
+		}
+	}
+	if tags != nil {
+		keys := make([]string, 0, len(*tags))
+		for _, k := range *tags {
+			keys = append(keys, string(k))
+		}
+		if t, err = allergen.NormalizeDietary(keys); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_dietary_tag", err.Error())
+			return nil, nil, false
+		}
+	}
+	return a, t, true
+}
+
+// dietaryFilter is the allergen_free and dietary query of the catalog
+// lists. Simple products pass when they contain none of the allergens and
+// carry all of the tags. A menu passes when its own allergens are clear and
+// every slot still offers a passing option; the rest of its options are
+// dropped.
+type dietaryFilter struct {
+	free     []string
+	required []string
+}
+
+func newDietaryFilter(free *[]generated.Allergen, dietary *[]generated.DietaryTag) dietaryFilter {
+	var f dietaryFilter
+	if free != nil {
+		for _, a := range *free {
+			f.free = append(f.free, string(a))
+		}
+	}
+	if dietary != nil {
+		for _, t := range *dietary {
+			f.required = append(f.required, string(t))
+		}
+	}
+	return f
+}
+
+func (f dietaryFilter) active() bool {
+	return len(f.free) > 0 || len(f.required) > 0
+}
+
+func (f dietaryFilter) matches(p *ent.Product) bool {
+	return !allergen.Intersects(p.Allergens, f.free) && allergen.ContainsAll(p.DietaryTags, f.required)
+}
+
+func (f dietaryFilter) admits(p *ent.Product) bool {
+	if p.Type != product.TypeMenu {
+		return f.matches(p)
+	}
+	if allergen.Intersects(p.Allergens, f.free) {
+		return false
+	}
+	for _, slot := range p.Edges.MenuSlots {
+		if !slices.ContainsFunc(slot.Edges.Options, func(o *ent.MenuSlotOption) bool {
+			return o.Edges.OptionProduct != nil && f.matches(o.Edges.OptionProduct)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// rejectedOptions are the IDs of the option products of menu p that do not
+// pass.
+func (f dietaryFilter) rejectedOptions(p *ent.Product) map[string]bool {
+	rejected := make(map[string]bool)
+	for _, slot := range p.Edges.MenuSlots {
+		for _, o := range slot.Edges.Options {
+			if op := o.Edges.OptionProduct; op != nil && !f.matches(op) {
+				rejected[op.ID] = true
+			}
+		}
+	}
+	return rejected
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"backend/internal/generated/api/generated"
+	"backend/internal/generated/ent"
+	nanoid "backend/internal/id"
+	"backend/internal/repository"
+	"backend/internal/response"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type createVariantRequest struct {
+	Name       string   `json:"name"`
+	PriceCents int64    `json:"priceCents"`
+	JetonID    *string  `json:"jetonId"`
+	Position   int      `json:"position"`
+	IsActive   *bool    `json:"isActive"`
+	StationIDs []string `json:"stationIds"`
+}
+
+// updateVariantRequest changes the given fields. An empty jetonId falls back
+// to the product's jeton; an empty stationIds routes the variant with its
+// product.
+type updateVariantRequest struct {
+	Name       *string   `json:"name,omitempty"`
+	PriceCents *int64    `json:"priceCents,omitempty"`
+	JetonID    *string   `json:"jetonId,omitempty"`
+	Position   *int      `json:"position,omitempty"`
+	IsActive   *bool     `json:"isActive,omitempty"`
+	StationIDs *[]string `json:"stationIds,omitempty"`
+}
+
+// CreateProductVariant (POST /v1/products/{productId}/variants)
+func (h *Handlers) CreateProductVariant(w http.ResponseWriter, r *http.Request) {
+	productID, ok := idParam(w, r, "productId")
+	if !ok {
+		return
+	}
+	var req createVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	in := repository.ProductVariantInput{
+		Name:       req.Name,
+		PriceCents: req.PriceCents,
+		JetonID:    emptyToNil(req.JetonID),
+		Position:   req.Position,
+		IsActive:   req.IsActive == nil || *req.IsActive,
+		StationIDs: req.StationIDs,
+	}
+	created, err := h.products.CreateVariant(r.Context(), productID, in)
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusCreated, toAPIProductVariant(created))
+}
+
+// UpdateProductVariant (PATCH /v1/products/{productId}/variants/{variantId})
+func (h *Handlers) UpdateProductVariant(w http.ResponseWriter, r *http.Request) {
+	productID, ok := idParam(w, r, "productId")
+	if !ok {
+		return
+	}
+	variantID, ok := idParam(w, r, "variantId")
+	if !ok {
+		return
+	}
+	var req updateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	ctx := r.Context()
+	current, err := h.products.GetVariant(ctx, productID, variantID)
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	in := variantInput(current)
+	if req.Name != nil {
+		in.Name = *req.Name
+	}
+	if req.PriceCents != nil {
+		in.PriceCents = *req.PriceCents
+	}
+	if req.JetonID != nil {
+		in.JetonID = emptyToNil(req.JetonID)
+	}
+	if req.Position != nil {
+		in.Position = *req.Position
+	}
+	if req.IsActive != nil {
+		in.IsActive = *req.IsActive
+	}
+	if req.StationIDs != nil {
+		in.StationIDs = *req.StationIDs
+	}
+	updated, err := h.products.UpdateVariant(ctx, productID, variantID, in)
+	if err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, toAPIProductVariant(updated))
+}
+
+// DeleteProductVariant (DELETE /v1/products/{productId}/variants/{variantId})
+func (h *Handlers) DeleteProductVariant(w http.ResponseWriter, r *http.Request) {
+	productID, ok := idParam(w, r, "productId")
+	if !ok {
+		return
+	}
+	variantID, ok := idParam(w, r, "variantId")
+	if !ok {
+		return
+	}
+	if err := h.products.DeleteVariant(r.Context(), productID, variantID); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			writeError(w, http.StatusConflict, "variant_in_use", "Die Variante wurde bereits verkauft. Deaktiviere sie stattdessen.")
+			return
+		}
+		writeVariantError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdjustProductVariantInventory adjusts the inventory of one variant.
+// PATCH /v1/products/{productId}/variants/{variantId}/inventory
+func (h *Handlers) AdjustProductVariantInventory(w http.ResponseWriter, r *http.Request) {
+	productID, ok := idParam(w, r, "productId")
+	if !ok {
+		return
+	}
+	variantID, ok := idParam(w, r, "variantId")
+	if !ok {
+		return
+	}
+	var body generated.InventoryAdjustment
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	ctx := r.Context()
+	if err := h.products.AdjustVariantStock(ctx, productID, variantID, int64(body.Delta), string(body.Reason)); err != nil {
+		writeVariantError(w, err)
+		return
+	}
+	stocks, err := h.products.GetVariantStockBatch(ctx, []string{variantID})
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{
+		"productId": productID,
+		"variantId": variantID,
+		"quantity":  stocks[variantID],
+	})
+}
+
+// enrichVariantStock sets the stock of every variant in products.
+func (h *Handlers) enrichVariantStock(ctx context.Context, products []generated.Product) {
+	var ids []string
+	for _, p := range products {
+		if p.Variants == nil {
+			continue
+		}
+		for _, v := range *p.Variants {
+			ids = append(ids, v.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	stocks, err := h.products.GetVariantStockBatch(ctx, ids)
+	if err != nil {
+		return
+	}
+	for _, p := range products {
+		if p.Variants == nil {
+			continue
+		}
+		for i := range *p.Variants {
+			s := stocks[(*p.Variants)[i].Id]
+			(*p.Variants)[i].Stock = &s
+		}
+	}
+}
+
+// dropInactiveVariants removes variants that cannot be ordered from a
+// customer or POS listing.
+func dropInactiveVariants(p *generated.Product) {
+	if p.Variants == nil {
+		return
+	}
+	active := make([]generated.ProductVariant, 0, len(*p.Variants))
+	for _, v := range *p.Variants {
+		if v.IsActive {
+			active = append(active, v)
+		}
+	}
+	if len(active) == 0 {
+		p.Variants = nil
+		return
+	}
+	p.Variants = &active
+}
+
+func variantInput(v *ent.ProductVariant) repository.ProductVariantInput {
+	in := repository.ProductVariantInput{
+		Name:       v.Name,
+		PriceCents: v.PriceCents,
+		JetonID:    v.JetonID,
+		Position:   v.Position,
+		IsActive:   v.IsActive,
+		StationIDs: []string{},
+	}
+	for _, l := range v.Edges.DeviceVariants {
+		in.StationIDs = append(in.StationIDs, l.DeviceID)
+	}
+	return in
+}
+
+func writeVariantError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrVariantInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_variant", err.Error())
+	case errors.Is(err, repository.ErrConflict):
+		writeError(w, http.StatusConflict, "conflict", "Eine Variante mit diesem Namen existiert bereits oder die Station bzw. der Jeton ist unbekannt.")
+	default:
+		writeEntError(w, err)
+	}
+}
+
+func idParam(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	id := chi.URLParam(r, name)
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return "", false
+	}
+	return id, true
+}
+
+func emptyToNil(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/internal/auth"
+	"backend/internal/generated/ent"
+	"backend/internal/response"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type undoRedemptionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type redemptionBatchItemResponse struct {
+	OrderLineID string `json:"orderLineId"`
+	Quantity    int    `json:"quantity"`
+}
+
+type redemptionBatchResponse struct {
+	ID               string                        `json:"id"`
+	OrderID          string                        `json:"orderId"`
+	StationDeviceID  *string                       `json:"stationDeviceId,omitempty"`
+	CreatedAt        time.Time                     `json:"createdAt"`
+	UndoneAt         *time.Time                    `json:"undoneAt,omitempty"`
+	UndoneByDeviceID *string                       `json:"undoneByDeviceId,omitempty"`
+	UndoneByUserID   *string                       `json:"undoneByUserId,omitempty"`
+	UndoReason       *string                       `json:"undoReason,omitempty"`
+	Items            []redemptionBatchItemResponse `json:"items"`
+}
+
+// UndoStationRedemption reverts a redemption batch made by the current station,
+// as long as it is still inside the undo window.
+// POST /v1/stations/redemptions/{batchId}/undo
+func (h *Handlers) UndoStationRedemption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deviceID, ok := auth.GetDeviceID(ctx)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Device authentication required")
+		return
+	}
+
+	var body undoRedemptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+
+	batch, err := h.stations.UndoRedemption(ctx, chi.URLParam(r, "batchId"), service.RedemptionUndoActor{
+		DeviceID: &deviceID,
+	}, body.Reason)
+	if err != nil {
+		h.writeRedemptionUndoError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, toRedemptionBatchResponse(batch))
+}
+
+// UndoRedemption lets an admin revert any redemption batch, regardless of
+// station or undo window.
+// POST /v1/redemptions/{batchId}/undo
+func (h *Handlers) UndoRedemption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body undoRedemptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+
+	actor := service.RedemptionUndoActor{Admin: true}
+	if userID, ok := auth.GetUserID(ctx); ok {
+		actor.UserID = &userID
+	}
+
+	batch, err := h.stations.UndoRedemption(ctx, chi.URLParam(r, "batchId"), actor, body.Reason)
+	if err != nil {
+		h.writeRedemptionUndoError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, toRedemptionBatchResponse(batch))
+}
+
+// ListRedemptionUndos returns the audit trail of undone redemption batches, newest first.
+// GET /v1/redemptions/undos
+func (h *Handlers) ListRedemptionUndos(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.stations.ListRedemptionUndos(r.Context())
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	items := make([]redemptionBatchResponse, 0, len(batches))
+	for _, b := range batches {
+		items = append(items, toRedemptionBatchResponse(b))
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handlers) writeRedemptionUndoError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrRedemptionBatchNotFound):
+		writeError(w, http.StatusNotFound, "redemption_batch_not_found", "The redemption does not exist.")
+	case errors.Is(err, service.ErrRedemptionAlreadyUndone):
+		writeError(w, http.StatusConflict, "redemption_already_undone", "This redemption has already been undone.")
+	case errors.Is(err, service.ErrRedemptionUndoWrongStation):
+		writeError(w, http.StatusForbidden, "redemption_undo_wrong_station", "Only the station that redeemed the items can undo it.")
+	case errors.Is(err, service.ErrRedemptionUndoWindowExpired):
+		writeError(w, http.StatusConflict, "redemption_undo_window_expired", "The undo window for this redemption has passed.")
+	case errors.Is(err, service.ErrRedemptionUndoReasonRequired):
+		writeError(w, http.StatusBadRequest, "reason_required", "A reason is required to undo a redemption.")
+	default:
+		h.logger.Error("redemption undo error", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+func toRedemptionBatchResponse(b *ent.RedemptionBatch) redemptionBatchResponse {
+	items := make([]redemptionBatchItemResponse, 0, len(b.Edges.Items))
+	for _, it := range b.Edges.Items {
+		items = append(items, redemptionBatchItemResponse{
+			OrderLineID: it.OrderLineID,
+			Quantity:    it.Quantity,
+		})
+	}
+	return redemptionBatchResponse{
+		ID:               b.ID,
+		OrderID:          b.OrderID,
+		StationDeviceID:  b.StationDeviceID,
+		CreatedAt:        b.CreatedAt,
+		UndoneAt:         b.UndoneAt,
+		UndoneByDeviceID: b.UndoneByDeviceID,
+		UndoneByUserID:   b.UndoneByUserID,
+		UndoReason:       b.UndoReason,
+		Items:            items,
+	}
+}
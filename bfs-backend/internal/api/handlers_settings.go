@@ -63,6 +63,13 @@ func (h *Handlers) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if body.AcceptLegacyQr != nil {
+		if err := h.settings.SetAcceptLegacyQR(ctx, *body.AcceptLegacyQr); err != nil {
+			writeError(w, http.StatusInternalServerError, "update_failed", err.Error())
+			return
+		}
+	}
+
 	if body.Club100FreeProductIds != nil || body.Club100MaxRedemptions != nil {
 		var productIDs []string
 		if body.Club100FreeProductIds != nil {
@@ -90,6 +97,7 @@ func toAPISettings(e *ent.Settings, missingJetons int) generated.Settings {
 		SystemEnabled:         e.SystemEnabled,
 		PosMode:               generated.PosFulfillmentMode(e.PosMode),
 		Club100MaxRedemptions: e.Club100MaxRedemptions,
+		AcceptLegacyQr:        ptr(e.AcceptLegacyQr),
 		UpdatedAt:             ptr(e.UpdatedAt),
 	}
 
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/response"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	settingsHistoryDefaultLimit = 50
+	settingsHistoryMaxLimit     = 200
+)
+
+type settingsVersionResponse struct {
+	Version        int                      `json:"version"`
+	Change         string                   `json:"change"`
+	CreatedBy      *string                  `json:"createdBy"`
+	CreatedByName  *string                  `json:"createdByName"`
+	RolledBackFrom *int                     `json:"rolledBackFrom,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	Settings       service.SettingsSnapshot `json:"settings"`
+	Changes        []service.SettingsChange `json:"changes"`
+}
+
+// GetSettingsHistory lists settings versions newest first; pass the last
+// version seen as before to page further back.
+// GET /v1/settings/history
+func (h *Handlers) GetSettingsHistory(w http.ResponseWriter, r *http.Request) {
+	limit := settingsHistoryDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > settingsHistoryMaxLimit {
+			writeError(w, http.StatusBadRequest, "invalid_limit", "limit must be between 1 and 200")
+			return
+		}
+		limit = n
+	}
+	before := 0
+	if v := r.URL.Query().Get("before"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_before", "before must be a positive version")
+			return
+		}
+		before = n
+	}
+
+	entries, err := h.settings.History(r.Context(), limit, before)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	items := make([]settingsVersionResponse, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, settingsVersionToResponse(e))
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// RollbackSettings restores a previous settings version.
+// POST /v1/settings/history/{version}/rollback
+func (h *Handlers) RollbackSettings(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		writeError(w, http.StatusBadRequest, "invalid_version", "Invalid version")
+		return
+	}
+	entry, err := h.settings.Rollback(r.Context(), version)
+	if err != nil {
+		var missingErr service.MissingJetonForActiveProductsError
+		switch {
+		case errors.Is(err, service.ErrSettingsVersionNotFound):
+			writeError(w, http.StatusNotFound, "settings_version_not_found", "The settings version does not exist.")
+		case errors.As(err, &missingErr):
+			response.WriteJSON(w, http.StatusConflict, map[string]any{
+				"error":   "missing_jetons",
+				"message": "Active products missing jeton",
+				"missing": missingErr.Count,
+			})
+		default:
+			writeEntError(w, err)
+		}
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, settingsVersionToResponse(*entry))
+}
+
+func settingsVersionToResponse(e service.SettingsVersionEntry) settingsVersionResponse {
+	changes := e.Changes
+	if changes == nil {
+		changes = []service.SettingsChange{}
+	}
+	return settingsVersionResponse{
+		Version:        e.Version,
+		Change:         e.Change,
+		CreatedBy:      e.CreatedBy,
+		CreatedByName:  e.CreatedByName,
+		RolledBackFrom: e.RolledBackFrom,
+		CreatedAt:      e.CreatedAt,
+		Settings:       e.Snapshot,
+		Changes:        changes,
+	}
+}
@@ -1,12 +1,19 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"backend/internal/i18n"
 	nanoid "backend/internal/id"
 	"backend/internal/pdf"
+	"backend/internal/response"
 	"backend/internal/service"
 
 	"github.com/go-chi/chi/v5"
@@ -17,9 +24,21 @@ const (
 	printSlipDefaultCount = 30
 	printSlipMinCount     = 1
 	printSlipMaxCount     = 500
+
+	// maxSlipLogoSize keeps logos small enough to embed in every slip PDF.
+	maxSlipLogoSize = 1 << 20 // 1 MB
 )
 
+// The PDF library embeds PNG and JPEG only.
+var allowedSlipLogoTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
 // PrintStaffMealSlips streams a PDF of printable QR slips for a campaign.
+// ?numbered=1&start=N prints sequential numbers from N (default 1); the
+// slips still share the campaign QR. See parseSlipLayout for the format and
+// printLocale for ?lang.
 // GET /v1/staff-meals/{campaignId}/print.pdf?count=30
 func (h *Handlers) PrintStaffMealSlips(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "campaignId")
@@ -27,9 +46,10 @@ func (h *Handlers) PrintStaffMealSlips(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
 		return
 	}
+	q := r.URL.Query()
 
 	count := printSlipDefaultCount
-	if v := r.URL.Query().Get("count"); v != "" {
+	if v := q.Get("count"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil || n < printSlipMinCount || n > printSlipMaxCount {
 			writeError(w, http.StatusBadRequest, "invalid_count",
@@ -38,35 +58,344 @@ func (h *Handlers) PrintStaffMealSlips(w http.ResponseWriter, r *http.Request) {
 		}
 		count = n
 	}
-
-	detail, err := h.volunteers.GetCampaign(r.Context(), id)
+	firstNumber := 0
+	if q.Get("numbered") == "1" || q.Get("numbered") == "true" {
+		firstNumber = 1
+		if v := q.Get("start"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				writeError(w, http.StatusBadRequest, "invalid_start", "start must be a positive number")
+				return
+			}
+			firstNumber = n
+		}
+	}
+	layout, err := parseSlipLayout(r)
 	if err != nil {
-		h.writeVolunteerError(w, err)
+		writeError(w, http.StatusBadRequest, "invalid_layout", err.Error())
 		return
 	}
 
-	products := make([]pdf.SlipProduct, 0, len(detail.Products))
-	for _, p := range detail.Products {
-		products = append(products, pdf.SlipProduct{
-			Name:     p.ProductName,
-			Quantity: p.Quantity,
-		})
+	content, err := h.volunteers.GetSlipContent(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
 	}
 
 	body, err := pdf.RenderStaffMealSlips(pdf.SlipInput{
-		CampaignName: detail.Campaign.Name,
-		QRPayload:    service.BuildQRPayload(detail.Campaign.ClaimToken),
-		Products:     products,
+		CampaignName: content.Campaign.Name,
+		QRPayload:    h.qr.CampaignPayload(content.Campaign),
+		Products:     slipProducts(content),
 		Count:        count,
+		FirstNumber:  firstNumber,
+		Style:        h.slipStyle(r.Context(), content, layout, printLocale(r)),
+	})
+	if err != nil {
+		h.writeSlipRenderError(w, err)
+		return
+	}
+	writePDF(w, body, fmt.Sprintf("staff-meal-%s.pdf", content.Campaign.ID))
+}
+
+// GetStaffMealSlipSummary counts the numbered single-use slips.
+// GET /v1/staff-meals/{campaignId}/slips
+func (h *Handlers) GetStaffMealSlipSummary(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	summary, err := h.volunteers.GetSlipSummary(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, slipSummaryToResponse(summary))
+}
+
+// IssueStaffMealSlips creates numbered single-use slips, each with its own
+// QR code, and answers with the new serial range to print.
+// POST /v1/staff-meals/{campaignId}/slips {"count": 100}
+func (h *Handlers) IssueStaffMealSlips(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	var req struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	tokens, err := h.volunteers.IssueNumberedSlips(r.Context(), id, req.Count)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	summary, err := h.volunteers.GetSlipSummary(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	resp := struct {
+		From int `json:"from"`
+		To   int `json:"to"`
+		staffMealSlipSummaryResponse
+	}{staffMealSlipSummaryResponse: slipSummaryToResponse(summary)}
+	if n := len(tokens); n > 0 {
+		resp.From, resp.To = *tokens[0].Serial, *tokens[n-1].Serial
+	}
+	response.WriteJSON(w, http.StatusCreated, resp)
+}
+
+// PrintNumberedStaffMealSlips streams the numbered single-use slips from..to
+// (both optional, inclusive), with ?lang and the format as above.
+// GET /v1/staff-meals/{campaignId}/slips/print.pdf?from=1&to=100
+func (h *Handlers) PrintNumberedStaffMealSlips(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	q := r.URL.Query()
+	var bounds [2]int
+	for i, key := range []string{"from", "to"} {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				writeError(w, http.StatusBadRequest, "invalid_range", key+" must be a positive number")
+				return
+			}
+			bounds[i] = n
+		}
+	}
+	layout, err := parseSlipLayout(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_layout", err.Error())
+		return
+	}
+
+	content, err := h.volunteers.GetSlipContent(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	tokens, err := h.volunteers.ListNumberedSlips(r.Context(), id, bounds[0], bounds[1])
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	if len(tokens) == 0 {
+		writeError(w, http.StatusBadRequest, "no_slips", "Issue numbered slips first.")
+		return
+	}
+	if len(tokens) > printSlipMaxCount {
+		writeError(w, http.StatusBadRequest, "invalid_range",
+			fmt.Sprintf("at most %d slips per PDF", printSlipMaxCount))
+		return
+	}
+
+	slips := make([]pdf.PersonalSlip, 0, len(tokens))
+	for _, t := range tokens {
+		slips = append(slips, pdf.PersonalSlip{
+			QRPayload:      h.qr.VolunteerPayload(t, content.Campaign),
+			MaxRedemptions: t.MaxRedemptions,
+			Number:         *t.Serial,
+		})
+	}
+	body, err := pdf.RenderPersonalStaffMealSlips(pdf.PersonalSlipInput{
+		CampaignName: content.Campaign.Name,
+		Products:     slipProducts(content),
+		Slips:        slips,
+		Style:        h.slipStyle(r.Context(), content, layout, printLocale(r)),
 	})
 	if err != nil {
-		h.logger.Error("render staff meal slips", zap.Error(err))
-		writeError(w, http.StatusInternalServerError, "render_failed", err.Error())
+		h.writeSlipRenderError(w, err)
 		return
 	}
+	writePDF(w, body, fmt.Sprintf("staff-meal-%s-numbered.pdf", content.Campaign.ID))
+}
+
+// UploadStaffMealLogo sets the logo printed on the campaign's slips.
+// POST /v1/staff-meals/{campaignId}/logo (multipart "file")
+func (h *Handlers) UploadStaffMealLogo(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	if h.blobStore == nil {
+		writeError(w, http.StatusNotImplemented, "not_configured", "Image uploads not configured")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxSlipLogoSize); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "File too large or invalid multipart form")
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Missing file field")
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if header.Size > maxSlipLogoSize {
+		writeError(w, http.StatusBadRequest, "file_too_large", "Logo must be at most 1 MB")
+		return
+	}
+
+	buf := make([]byte, 512)
+	n, err := io.ReadAtLeast(file, buf, 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "Could not read file")
+		return
+	}
+	contentType := http.DetectContentType(buf[:n])
+	if !allowedSlipLogoTypes[contentType] {
+		writeError(w, http.StatusBadRequest, "invalid_file_type", "Only JPEG and PNG images are allowed")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", "An unexpected error occurred.")
+		return
+	}
+
+	ctx := r.Context()
+	url, err := h.blobStore.Upload(ctx, file, contentType)
+	if err != nil {
+		h.logger.Error("failed to upload slip logo", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "upload_failed", "Failed to upload image")
+		return
+	}
+	previous, err := h.volunteers.SetLogo(ctx, id, &url)
+	if err != nil {
+		_ = h.blobStore.Delete(ctx, url)
+		h.writeVolunteerError(w, err)
+		return
+	}
+	if previous != nil && *previous != "" {
+		_ = h.blobStore.Delete(ctx, *previous)
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]string{"logoUrl": url})
+}
+
+// DeleteStaffMealLogo removes the slip logo.
+// DELETE /v1/staff-meals/{campaignId}/logo
+func (h *Handlers) DeleteStaffMealLogo(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	ctx := r.Context()
+	previous, err := h.volunteers.SetLogo(ctx, id, nil)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	if previous != nil && *previous != "" && h.blobStore != nil {
+		_ = h.blobStore.Delete(ctx, *previous)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type staffMealSlipSummaryResponse struct {
+	Issued     int `json:"issued"`
+	Redeemed   int `json:"redeemed"`
+	LastSerial int `json:"lastSerial"`
+}
+
+func slipSummaryToResponse(s *service.VolunteerSlipSummary) staffMealSlipSummaryResponse {
+	return staffMealSlipSummaryResponse{Issued: s.Issued, Redeemed: s.Redeemed, LastSerial: s.LastSerial}
+}
+
+// parseSlipLayout reads ?format=a4|62mm|102mm and the optional ?columns= and
+// ?rows= overrides, e.g. a 3×8 grid for A4 label sheets.
+func parseSlipLayout(r *http.Request) (pdf.Layout, error) {
+	q := r.URL.Query()
+	layout, ok := pdf.LayoutByName(strings.ToLower(q.Get("format")))
+	if !ok {
+		return pdf.Layout{}, errors.New("format must be a4, 62mm or 102mm")
+	}
+	if v := q.Get("columns"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > pdf.MaxLayoutColumns {
+			return pdf.Layout{}, fmt.Errorf("columns must be between 1 and %d", pdf.MaxLayoutColumns)
+		}
+		layout.Columns = n
+	}
+	if v := q.Get("rows"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > pdf.MaxLayoutRows {
+			return pdf.Layout{}, fmt.Errorf("rows must be between 0 and %d", pdf.MaxLayoutRows)
+		}
+		layout.Rows = n
+	}
+	return layout, nil
+}
+
+// printLocale is the language of the fixed texts on printouts: the lang query
+// parameter, else German. Printouts are made by admins for guests, so the
+// admin's Accept-Language does not count.
+func printLocale(r *http.Request) i18n.Locale {
+	if l, ok := i18n.Parse(r.URL.Query().Get("lang")); ok {
+		return l
+	}
+	return i18n.German
+}
+
+// slipStyle loads the campaign logo for the slips. A logo that cannot be
+// loaded is logged and left out rather than failing the print.
+func (h *Handlers) slipStyle(ctx context.Context, content *service.VolunteerSlipContent, layout pdf.Layout, locale i18n.Locale) pdf.SlipStyle {
+	style := pdf.SlipStyle{Layout: layout, Validity: content.Validity, Locale: locale}
+	if content.Campaign.LogoURL == nil || h.blobStore == nil {
+		return style
+	}
+	logo, err := h.blobStore.Download(ctx, *content.Campaign.LogoURL, maxSlipLogoSize)
+	if err != nil {
+		h.logger.Warn("load staff meal slip logo", zap.String("campaign_id", content.Campaign.ID), zap.Error(err))
+		return style
+	}
+	style.Logo = logo
+	return style
+}
+
+// slipProducts lists the fixed products, then one line per choice group
+// naming its options: "1× Hauptgang: Burger / Veggie".
+func slipProducts(content *service.VolunteerSlipContent) []pdf.SlipProduct {
+	out := make([]pdf.SlipProduct, 0, len(content.Products)+len(content.ChoiceGroups))
+	for _, p := range content.Products {
+		out = append(out, pdf.SlipProduct{Name: p.ProductName, Quantity: p.Quantity})
+	}
+	for _, g := range content.ChoiceGroups {
+		names := make([]string, 0, len(g.Options))
+		for _, o := range g.Options {
+			names = append(names, o.ProductName)
+		}
+		out = append(out, pdf.SlipProduct{
+			Name:     g.Name + ": " + strings.Join(names, " / "),
+			Quantity: g.Quantity,
+		})
+	}
+	return out
+}
+
+// writeSlipRenderError answers layout problems (e.g. too many rows for the
+// page) with 400 and anything else with 500.
+func (h *Handlers) writeSlipRenderError(w http.ResponseWriter, err error) {
+	if errors.Is(err, pdf.ErrInvalidLayout) {
+		writeError(w, http.StatusBadRequest, "invalid_layout", err.Error())
+		return
+	}
+	h.logger.Error("render staff meal slips", zap.Error(err))
+	writeError(w, http.StatusInternalServerError, "render_failed", err.Error())
+}
 
+func writePDF(w http.ResponseWriter, body []byte, filename string) {
 	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="staff-meal-%s.pdf"`, detail.Campaign.ID))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
 	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
 	_, _ = w.Write(body)
 }
@@ -2,12 +2,18 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"backend/internal/auth"
 	"backend/internal/generated/api/generated"
+	"backend/internal/qrpayload"
+	"backend/internal/repository"
 	"backend/internal/response"
+	"backend/internal/service"
+
+	"go.uber.org/zap"
 )
 
 // ListStations returns all station-type devices, optionally filtered by status.
@@ -127,7 +133,8 @@ func (h *Handlers) RemoveStationProduct(w http.ResponseWriter, r *http.Request,
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// RedeemAtStation redeems order items at the current station.
+// RedeemAtStation redeems order items at the current station, either all open
+// items or the selected lines and quantities.
 // (POST /stations/redeem)
 func (h *Handlers) RedeemAtStation(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -144,45 +151,72 @@ func (h *Handlers) RedeemAtStation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orderID := body.OrderId
+	orderID, err := h.qr.Resolve(ctx, qrpayload.TypeOrder, derefStr(body.QrPayload), derefStr(body.OrderId))
+	if err != nil {
+		h.writeQRError(w, err)
+		return
+	}
+
+	var selections []service.RedeemSelection
+	if body.Items != nil {
+		selections = make([]service.RedeemSelection, 0, len(*body.Items))
+		for _, item := range *body.Items {
+			selections = append(selections, service.RedeemSelection{
+				OrderLineID: item.OrderLineId,
+				Quantity:    item.Quantity,
+			})
+		}
+	}
 
 	// Use the Idempotency-Key header if present.
 	idemKey := r.Header.Get("Idempotency-Key")
 
-	result, err := h.stations.RedeemAssigned(ctx, deviceID, orderID, idemKey)
+	result, err := h.stations.RedeemSelected(ctx, deviceID, orderID, selections, idemKey)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "redeem_failed", err.Error())
+		switch {
+		case errors.Is(err, service.ErrStationLineNotAssigned):
+			writeError(w, http.StatusBadRequest, "line_not_assigned", "Order line is not redeemable at this station")
+		case errors.Is(err, service.ErrStationInvalidRedeemQuantity):
+			writeError(w, http.StatusBadRequest, "invalid_quantity", "Quantity must be at least 1")
+		case errors.Is(err, service.ErrStationQuantityExceedsRemaining):
+			writeError(w, http.StatusConflict, "quantity_exceeds_remaining", "Quantity exceeds the units still open on this line")
+		case errors.Is(err, repository.ErrConflict):
+			writeError(w, http.StatusConflict, "redeem_conflict", "The order was redeemed at the same time, please scan again")
+		default:
+			writeError(w, http.StatusBadRequest, "redeem_failed", err.Error())
+		}
 		return
 	}
 
-	// Map the result to the RedemptionResult API type.
-	matched := 0
-	redeemed := 0
-	if v, ok := result["matched"].(int); ok {
-		matched = v
-	}
-	if v, ok := result["redeemed"].(int64); ok {
-		redeemed = int(v)
-	}
-
+	// Map the result to the RedemptionResult API type. Replayed idempotent
+	// responses come back from JSON, so numbers may be float64.
 	resp := generated.RedemptionResult{
-		Matched:  matched,
-		Redeemed: redeemed,
+		Matched:  resultInt(result["matched"]),
+		Redeemed: resultInt(result["redeemed"]),
+	}
+	redeemedQty := resultInt(result["redeemedQuantity"])
+	resp.RedeemedQuantity = &redeemedQty
+	remainingQty := resultInt(result["remainingQuantity"])
+	resp.RemainingQuantity = &remainingQty
+	if batchID, ok := result["batchId"].(string); ok {
+		resp.BatchId = &batchID
 	}
 
-	if items, ok := result["items"].([]map[string]any); ok {
+	if items := resultMaps(result["items"]); items != nil {
 		apiItems := make([]struct {
-			Id         *string    `json:"id,omitempty"`
-			Quantity   *int       `json:"quantity,omitempty"`
-			RedeemedAt *time.Time `json:"redeemedAt,omitempty"`
-			Title      *string    `json:"title,omitempty"`
+			Id               *string    `json:"id,omitempty"`
+			Quantity         *int       `json:"quantity,omitempty"`
+			RedeemedAt       *time.Time `json:"redeemedAt,omitempty"`
+			RedeemedQuantity *int       `json:"redeemedQuantity,omitempty"`
+			Title            *string    `json:"title,omitempty"`
 		}, 0, len(items))
 		for _, item := range items {
 			entry := struct {
-				Id         *string    `json:"id,omitempty"`
-				Quantity   *int       `json:"quantity,omitempty"`
-				RedeemedAt *time.Time `json:"redeemedAt,omitempty"`
-				Title      *string    `json:"title,omitempty"`
+				Id               *string    `json:"id,omitempty"`
+				Quantity         *int       `json:"quantity,omitempty"`
+				RedeemedAt       *time.Time `json:"redeemedAt,omitempty"`
+				RedeemedQuantity *int       `json:"redeemedQuantity,omitempty"`
+				Title            *string    `json:"title,omitempty"`
 			}{}
 			if idStr, ok := item["id"].(string); ok {
 				apiID := idStr
@@ -191,17 +225,64 @@ func (h *Handlers) RedeemAtStation(w http.ResponseWriter, r *http.Request) {
 			if title, ok := item["title"].(string); ok {
 				entry.Title = &title
 			}
-			if qty, ok := item["quantity"].(int); ok {
-				entry.Quantity = &qty
-			}
+			qty := resultInt(item["quantity"])
+			entry.Quantity = &qty
+			redeemed := resultInt(item["redeemedQuantity"])
+			entry.RedeemedQuantity = &redeemed
 			apiItems = append(apiItems, entry)
 		}
 		resp.Items = &apiItems
 	}
 
+	remaining := make([]generated.RedemptionRemainingItem, 0)
+	for _, item := range resultMaps(result["remaining"]) {
+		id, _ := item["id"].(string)
+		title, _ := item["title"].(string)
+		remaining = append(remaining, generated.RedemptionRemainingItem{
+			Id:                id,
+			Title:             title,
+			Quantity:          resultInt(item["quantity"]),
+			RemainingQuantity: resultInt(item["remainingQuantity"]),
+		})
+	}
+	resp.Remaining = &remaining
+
 	response.WriteJSON(w, http.StatusOK, resp)
 }
 
+// resultInt reads a numeric value from a service result map, which holds Go
+// ints when fresh and float64 when replayed from an idempotency record.
+func resultInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// resultMaps reads a list of objects from a service result map, fresh or replayed.
+func resultMaps(v any) []map[string]any {
+	switch items := v.(type) {
+	case []map[string]any:
+		return items
+	case []any:
+		out := make([]map[string]any, 0, len(items))
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				out = append(out, m)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
 // AddStationProduct adds a single product assignment to a station.
 // (POST /stations/{stationId}/products/{productId})
 func (h *Handlers) AddStationProduct(w http.ResponseWriter, r *http.Request, stationId string, productId string) {
@@ -244,3 +325,21 @@ func (h *Handlers) RevokeStation(w http.ResponseWriter, r *http.Request, station
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handlers) writeQRError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrQRRequired):
+		writeError(w, http.StatusBadRequest, "qr_required", "A scanned QR code is required")
+	case errors.Is(err, service.ErrQRInvalid):
+		writeError(w, http.StatusBadRequest, "qr_invalid", "QR code is not valid")
+	case errors.Is(err, service.ErrQRExpired):
+		writeError(w, http.StatusBadRequest, "qr_expired", "QR code has expired")
+	case errors.Is(err, service.ErrQRWrongType):
+		writeError(w, http.StatusBadRequest, "qr_wrong_type", "QR code cannot be redeemed here")
+	case errors.Is(err, service.ErrQRLegacyDisabled):
+		writeError(w, http.StatusBadRequest, "legacy_qr_disabled", "Old QR codes are no longer accepted")
+	default:
+		h.logger.Error("qr resolve error", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
@@ -7,15 +7,24 @@ import (
 
 	"backend/internal/auth"
 	nanoid "backend/internal/id"
+	"backend/internal/qrpayload"
 	"backend/internal/response"
 	"backend/internal/service"
 )
 
 type redeemCampaignRequest struct {
+	QRPayload  string `json:"qrPayload"`
 	ClaimToken string `json:"claimToken"`
+	// Choices maps choice group IDs to the picked product IDs.
+	Choices map[string]string `json:"choices,omitempty"`
 }
 
-// RedeemCampaignAtStation redeems a shared campaign QR at the current station.
+// RedeemCampaignAtStation redeems a shared campaign QR or a volunteer's
+// personal QR at the current station. Both use the same payload format; the
+// token is looked up as a claim token first. The scanned code is sent as
+// qrPayload; claimToken is the legacy CAMP: form. When the campaign has choice
+// groups the station serves several options of, the first call answers 409
+// choice_required with those options and the station resubmits with choices.
 // POST /v1/stations/redeem-campaign
 func (h *Handlers) RedeemCampaignAtStation(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -31,21 +40,48 @@ func (h *Handlers) RedeemCampaignAtStation(w http.ResponseWriter, r *http.Reques
 		writeError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
 		return
 	}
-	token := body.ClaimToken
+	token, err := h.qr.Resolve(ctx, qrpayload.TypeCampaign, body.QRPayload, body.ClaimToken)
+	if err != nil {
+		h.writeQRError(w, err)
+		return
+	}
 	if !nanoid.Valid(token) {
 		writeError(w, http.StatusBadRequest, "invalid_claim_token", "Invalid claim token")
 		return
 	}
 
+	for groupID, productID := range body.Choices {
+		if !nanoid.Valid(groupID) || !nanoid.Valid(productID) {
+			writeError(w, http.StatusBadRequest, "invalid_choice", "Invalid choice")
+			return
+		}
+	}
+
 	idemKey := r.Header.Get("Idempotency-Key")
 
-	result, err := h.volunteers.RedeemSharedQR(ctx, token, deviceID, idemKey)
+	result, err := h.volunteers.RedeemSharedQR(ctx, token, deviceID, idemKey, body.Choices)
+	if errors.Is(err, service.ErrVolunteerCampaignNotFound) {
+		result, err = h.volunteers.RedeemPersonalQR(ctx, token, deviceID, idemKey, body.Choices)
+		if errors.Is(err, service.ErrVolunteerTokenNotFound) {
+			err = service.ErrVolunteerCampaignNotFound
+		}
+	}
 	if err != nil {
+		var choiceErr service.VolunteerChoiceRequiredError
+		if errors.As(err, &choiceErr) {
+			writeChoiceRequired(w, choiceErr)
+			return
+		}
 		if errors.Is(err, service.ErrVolunteerCampaignNotFound) ||
 			errors.Is(err, service.ErrVolunteerCampaignInactive) ||
 			errors.Is(err, service.ErrVolunteerCampaignOutsideValid) ||
 			errors.Is(err, service.ErrVolunteerMaxRedemptionsReached) ||
+			errors.Is(err, service.ErrVolunteerPersonalLimitReached) ||
+			errors.Is(err, service.ErrVolunteerCampaignOutsideWindow) ||
+			errors.Is(err, service.ErrVolunteerWindowLimitReached) ||
+			errors.Is(err, service.ErrVolunteerDailyLimitReached) ||
 			errors.Is(err, service.ErrVolunteerCampaignHasNoProducts) ||
+			errors.Is(err, service.ErrVolunteerChoiceInvalid) ||
 			errors.Is(err, service.ErrVolunteerStationNoMatchingProducts) {
 			h.writeVolunteerError(w, err)
 			return
@@ -54,10 +90,14 @@ func (h *Handlers) RedeemCampaignAtStation(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	response.WriteJSON(w, http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"orderId":         result.OrderID,
 		"redemptionCount": result.RedemptionCount,
 		"maxRedemptions":  result.MaxRedemptions,
 		"station":         result.StationResult,
-	})
+	}
+	if result.HolderName != "" {
+		resp["holderName"] = result.HolderName
+	}
+	response.WriteJSON(w, http.StatusOK, resp)
 }
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"backend/internal/auth"
+	"backend/internal/generated/ent"
+	nanoid "backend/internal/id"
+	"backend/internal/pubsub"
+	"backend/internal/response"
+	"backend/internal/service"
+	"backend/internal/stationqueue"
+
+	"go.uber.org/zap"
+)
+
+type stationQueueItem struct {
+	ID                string   `json:"id"`
+	ProductID         string   `json:"productId"`
+	Title             string   `json:"title"`
+	Quantity          int      `json:"quantity"`
+	RemainingQuantity int      `json:"remainingQuantity"`
+	ParentItemID      *string  `json:"parentItemId,omitempty"`
+	MenuSlotID        *string  `json:"menuSlotId,omitempty"`
+	MenuSlotName      *string  `json:"menuSlotName,omitempty"`
+	VariantID         *string  `json:"variantId,omitempty"`
+	VariantName       *string  `json:"variantName,omitempty"`
+	Allergens         []string `json:"allergens,omitempty"`
+}
+
+type stationQueueEntry struct {
+	OrderID   string             `json:"orderId"`
+	Origin    string             `json:"origin"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Items     []stationQueueItem `json:"items"`
+}
+
+type stationQueueList struct {
+	Items []stationQueueEntry `json:"items"`
+}
+
+type stationQueueUpdate struct {
+	Type  stationqueue.EventType `json:"type"`
+	Entry stationQueueEntry      `json:"entry"`
+}
+
+// GetStationQueue lists paid orders still waiting at the current station, oldest first.
+// GET /v1/stations/queue
+func (h *Handlers) GetStationQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deviceID, ok := auth.GetDeviceID(ctx)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Device authentication required")
+		return
+	}
+
+	entries, err := h.stations.Queue(ctx, deviceID)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, toStationQueueList(entries))
+}
+
+// StreamStationQueue pushes the station queue as server-sent events: a full
+// snapshot on connect, then one queue-update per paid or redeemed order that
+// touches this station. An update with no items means the order left the queue.
+// GET /v1/stations/queue/stream
+func (h *Handlers) StreamStationQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deviceID, ok := auth.GetDeviceID(ctx)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Device authentication required")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	rc := http.NewResponseController(w)
+
+	// Subscribe before loading the snapshot so nothing paid in between is lost.
+	subID := nanoid.New()
+	ch := h.queueFeed.subscribe(deviceID, subID)
+	defer h.queueFeed.unsubscribe(deviceID, subID)
+
+	entries, err := h.stations.Queue(ctx, deviceID)
+	if err != nil {
+		_, _ = w.Write([]byte("event: error\ndata: {\"error\":\"failed to load queue\"}\n\n"))
+		_ = rc.Flush()
+		return
+	}
+
+	data, _ := json.Marshal(toStationQueueList(entries))
+	_, _ = w.Write(append(append([]byte("event: queue-snapshot\ndata: "), data...), '\n', '\n'))
+	_ = rc.Flush()
+
+	for {
+		select {
+		case data, ok := <-ch:
+			if !ok {
+				return
+			}
+			_, _ = w.Write(append(append([]byte("event: queue-update\ndata: "), data...), '\n', '\n'))
+			_ = rc.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stationQueueFeed turns queue events into queue-update messages. Each event
+// is resolved once per station with an open stream and the encoded update is
+// shared by all of that station's streams, so several screens on one station
+// do not each query the order.
+type stationQueueFeed struct {
+	hub      *stationqueue.Hub
+	stations service.StationService
+	logger   *zap.Logger
+
+	start   sync.Once
+	mu      sync.Mutex
+	streams map[string]*pubsub.Hub[[]byte] // by station ID
+}
+
+func newStationQueueFeed(hub *stationqueue.Hub, stations service.StationService, logger *zap.Logger) *stationQueueFeed {
+	return &stationQueueFeed{
+		hub:      hub,
+		stations: stations,
+		logger:   logger,
+		streams:  make(map[string]*pubsub.Hub[[]byte]),
+	}
+}
+
+// subscribe returns the encoded updates for stationID. The feed listens to
+// the queue hub from the first subscription on.
+func (f *stationQueueFeed) subscribe(stationID, subID string) <-chan []byte {
+	f.start.Do(func() {
+		go f.run(f.hub.Subscribe(nanoid.New()))
+	})
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	streams, ok := f.streams[stationID]
+	if !ok {
+		streams = pubsub.NewHub[[]byte]()
+		f.streams[stationID] = streams
+	}
+	return streams.Subscribe(subID)
+}
+
+func (f *stationQueueFeed) unsubscribe(stationID, subID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	streams, ok := f.streams[stationID]
+	if !ok {
+		return
+	}
+	streams.Unsubscribe(subID)
+	if streams.SubscriberCount() == 0 {
+		delete(f.streams, stationID)
+	}
+}
+
+func (f *stationQueueFeed) run(events <-chan stationqueue.Event) {
+	for event := range events {
+		f.mu.Lock()
+		targets := make(map[string]*pubsub.Hub[[]byte], len(f.streams))
+		maps.Copy(targets, f.streams)
+		f.mu.Unlock()
+
+		for stationID, streams := range targets {
+			if data, ok := f.update(stationID, event); ok {
+				streams.Publish(data)
+			}
+		}
+	}
+}
+
+// update encodes the station's view of the event's order; ok is false when
+// the order has nothing for the station.
+func (f *stationQueueFeed) update(stationID string, event stationqueue.Event) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	entry, err := f.stations.QueueEntryForOrder(ctx, stationID, event.OrderID)
+	if err != nil {
+		f.logger.Warn("station queue update failed", zap.String("orderId", event.OrderID), zap.Error(err))
+		return nil, false
+	}
+	if entry == nil || (event.Type == stationqueue.EventOrderPaid && len(entry.Items) == 0) {
+		return nil, false
+	}
+	data, err := json.Marshal(stationQueueUpdate{
+		Type:  event.Type,
+		Entry: toStationQueueEntry(*entry),
+	})
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func toStationQueueList(entries []service.StationQueueEntry) stationQueueList {
+	out := stationQueueList{Items: make([]stationQueueEntry, 0, len(entries))}
+	for _, e := range entries {
+		out.Items = append(out.Items, toStationQueueEntry(e))
+	}
+	return out
+}
+
+func toStationQueueEntry(e service.StationQueueEntry) stationQueueEntry {
+	return stationQueueEntry{
+		OrderID:   e.OrderID,
+		Origin:    string(e.Origin),
+		CreatedAt: e.CreatedAt,
+		Items:     toStationQueueItems(e.Items),
+	}
+}
+
+func toStationQueueItems(lines []*ent.OrderLine) []stationQueueItem {
+	out := make([]stationQueueItem, 0, len(lines))
+	for _, line := range lines {
+		out = append(out, stationQueueItem{
+			ID:                line.ID,
+			ProductID:         line.ProductID,
+			Title:             line.Title,
+			Quantity:          line.Quantity,
+			RemainingQuantity: line.Quantity - line.RedeemedQuantity,
+			ParentItemID:      line.ParentLineID,
+			MenuSlotID:        line.MenuSlotID,
+			MenuSlotName:      line.MenuSlotName,
+			VariantID:         line.VariantID,
+			VariantName:       line.VariantName,
+			Allergens:         line.Allergens,
+		})
+	}
+	return out
+}
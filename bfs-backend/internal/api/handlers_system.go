@@ -4,14 +4,23 @@ import (
 	"net/http"
 
 	"backend/internal/response"
+	"backend/internal/service"
 )
 
+// GetSystemStatus tells customers whether they can order now and, while
+// closed by the opening hours, when the system opens again. It fails open
+// like RequireEnabled.
 func (h *Handlers) GetSystemStatus(w http.ResponseWriter, r *http.Request) {
-	enabled, err := h.settings.IsSystemEnabled(r.Context())
+	status, err := h.openingHours.Status(r.Context())
 	if err != nil {
-		enabled = true
+		status = &service.SystemStatus{Open: true, Source: service.SystemSourceManual}
 	}
-	response.WriteJSON(w, http.StatusOK, map[string]bool{
-		"enabled": enabled,
+	response.WriteJSON(w, http.StatusOK, systemStatusPayload{
+		Enabled:       status.Open,
+		Source:        status.Source,
+		ClosedMessage: status.ClosedMessage,
+		OpensAt:       status.OpensAt,
+		ClosesAt:      status.ClosesAt,
+		OpenUntil:     status.OpenUntil,
 	})
 }
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/auth"
+	"backend/internal/escpos"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetOrderTicket returns the ESC/POS byte stream for an order ticket.
+// GET /v1/pos/orders/{orderId}/ticket?paper=58|80
+// GET /v1/stations/orders/{orderId}/ticket?paper=58|80
+func (h *Handlers) GetOrderTicket(w http.ResponseWriter, r *http.Request) {
+	paper, err := escpos.ParsePaper(r.URL.Query().Get("paper"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_paper", "paper must be 58 or 80")
+		return
+	}
+	out, err := h.tickets.OrderTicket(r.Context(), chi.URLParam(r, "orderId"), paper)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	writeEscPos(w, out)
+}
+
+// GetStationSlip returns the ESC/POS kitchen slip with the items of an order
+// still open at the current station.
+// GET /v1/stations/orders/{orderId}/slip?paper=58|80
+func (h *Handlers) GetStationSlip(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deviceID, ok := auth.GetDeviceID(ctx)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Device authentication required")
+		return
+	}
+	paper, err := escpos.ParsePaper(r.URL.Query().Get("paper"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_paper", "paper must be 58 or 80")
+		return
+	}
+	out, err := h.tickets.StationSlip(ctx, deviceID, chi.URLParam(r, "orderId"), paper)
+	if err != nil {
+		writeEntError(w, err)
+		return
+	}
+	writeEscPos(w, out)
+}
+
+func writeEscPos(w http.ResponseWriter, out []byte) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(out)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(out)
+}
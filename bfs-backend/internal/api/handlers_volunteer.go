@@ -28,9 +28,11 @@ type volunteerCampaignProductPayload struct {
 
 type createVolunteerCampaignRequest struct {
 	Name           string                            `json:"name"`
+	EventID        *string                           `json:"eventId,omitempty"`
 	ValidFrom      *time.Time                        `json:"validFrom,omitempty"`
 	ValidUntil     *time.Time                        `json:"validUntil,omitempty"`
 	Products       []volunteerCampaignProductPayload `json:"products"`
+	ChoiceGroups   []volunteerChoiceGroupRequest     `json:"choiceGroups,omitempty"`
 	MaxRedemptions int                               `json:"maxRedemptions"`
 }
 
@@ -54,9 +56,11 @@ type adminCampaignResponse struct {
 	AccessCode      string     `json:"accessCode"`
 	ValidFrom       *time.Time `json:"validFrom,omitempty"`
 	ValidUntil      *time.Time `json:"validUntil,omitempty"`
+	EventID         *string    `json:"eventId,omitempty"`
 	Status          string     `json:"status"`
 	MaxRedemptions  int        `json:"maxRedemptions"`
 	RedemptionCount int        `json:"redemptionCount"`
+	LogoURL         *string    `json:"logoUrl,omitempty"`
 	CreatedAt       time.Time  `json:"createdAt"`
 	UpdatedAt       time.Time  `json:"updatedAt"`
 }
@@ -88,9 +92,12 @@ type claimCampaignPublic struct {
 }
 
 type claimCampaignResponse struct {
-	Campaign  claimCampaignPublic        `json:"campaign"`
-	Products  []adminCampaignProductItem `json:"products"`
-	QRPayload string                     `json:"qrPayload"`
+	Campaign     claimCampaignPublic           `json:"campaign"`
+	Products     []adminCampaignProductItem    `json:"products"`
+	ChoiceGroups []volunteerChoiceGroupPayload `json:"choiceGroups"`
+	QRPayload    string                        `json:"qrPayload"`
+	Windows      []volunteerWindowPayload      `json:"windows"`
+	NextWindow   *claimWindowOccurrence        `json:"nextWindow,omitempty"`
 }
 
 // CreateVolunteerCampaign (POST /v1/staff-meals)
@@ -107,11 +114,18 @@ func (h *Handlers) CreateVolunteerCampaign(w http.ResponseWriter, r *http.Reques
 			Quantity:  p.Quantity,
 		})
 	}
+	groups, err := choiceGroupsFromRequest(req.ChoiceGroups)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_choice_groups", err.Error())
+		return
+	}
 	campaign, err := h.volunteers.CreateCampaign(r.Context(), service.CreateVolunteerCampaignInput{
 		Name:           req.Name,
+		EventID:        req.EventID,
 		ValidFrom:      req.ValidFrom,
 		ValidUntil:     req.ValidUntil,
 		Products:       products,
+		ChoiceGroups:   groups,
 		MaxRedemptions: req.MaxRedemptions,
 	})
 	if err != nil {
@@ -121,9 +135,9 @@ func (h *Handlers) CreateVolunteerCampaign(w http.ResponseWriter, r *http.Reques
 	response.WriteJSON(w, http.StatusCreated, campaignToAdminResponse(campaign))
 }
 
-// ListVolunteerCampaigns (GET /v1/staff-meals)
+// ListVolunteerCampaigns (GET /v1/staff-meals?event_id=)
 func (h *Handlers) ListVolunteerCampaigns(w http.ResponseWriter, r *http.Request) {
-	summaries, err := h.volunteers.ListCampaigns(r.Context())
+	summaries, err := h.volunteers.ListCampaigns(r.Context(), eventIDQuery(r))
 	if err != nil {
 		h.logger.Error("list volunteer campaigns", zap.Error(err))
 		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
@@ -294,6 +308,10 @@ func (h *Handlers) GetClaimCampaign(w http.ResponseWriter, r *http.Request) {
 			Quantity:     p.Quantity,
 		})
 	}
+	var next *claimWindowOccurrence
+	if nw := view.NextWindow; nw != nil {
+		next = &claimWindowOccurrence{Label: nw.Label, Start: nw.Start, End: nw.End, Active: nw.Active}
+	}
 	response.WriteJSON(w, http.StatusOK, claimCampaignResponse{
 		Campaign: claimCampaignPublic{
 			Name:       view.Campaign.Name,
@@ -301,8 +319,11 @@ func (h *Handlers) GetClaimCampaign(w http.ResponseWriter, r *http.Request) {
 			ValidUntil: view.Campaign.ValidUntil,
 			Status:     string(view.Campaign.Status),
 		},
-		Products:  products,
-		QRPayload: view.QRPayload,
+		Products:     products,
+		ChoiceGroups: choiceGroupsToPayload(view.ChoiceGroups),
+		QRPayload:    view.QRPayload,
+		Windows:      windowsToPayload(view.Windows),
+		NextWindow:   next,
 	})
 }
 
@@ -310,6 +331,8 @@ func (h *Handlers) writeVolunteerError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, service.ErrVolunteerCampaignNotFound):
 		writeError(w, http.StatusNotFound, "campaign_not_found", "The volunteer campaign does not exist.")
+	case errors.Is(err, service.ErrEventNotFound):
+		writeError(w, http.StatusBadRequest, "event_not_found", "The event does not exist.")
 	case errors.Is(err, service.ErrVolunteerCampaignInactive):
 		writeError(w, http.StatusGone, "campaign_inactive", "This volunteer campaign is no longer active.")
 	case errors.Is(err, service.ErrVolunteerCampaignOutsideValid):
@@ -319,13 +342,35 @@ func (h *Handlers) writeVolunteerError(w http.ResponseWriter, err error) {
 	case errors.Is(err, service.ErrVolunteerCampaignInvalidAccess):
 		writeError(w, http.StatusBadRequest, "invalid_access_code_format", "Access code must be 4 digits.")
 	case errors.Is(err, service.ErrVolunteerCampaignHasNoProducts):
-		writeError(w, http.StatusBadRequest, "no_products", "At least one product is required.")
+		writeError(w, http.StatusBadRequest, "no_products", "At least one product or choice group is required.")
 	case errors.Is(err, service.ErrVolunteerMaxRedemptionsReached):
 		writeError(w, http.StatusConflict, "max_redemptions_reached", "This campaign has reached its maximum number of redemptions.")
 	case errors.Is(err, service.ErrVolunteerMaxBelowCount):
 		writeError(w, http.StatusConflict, "max_redemptions_below_count", "New maximum cannot be lower than the current redemption count.")
 	case errors.Is(err, service.ErrVolunteerStationNoMatchingProducts):
 		writeError(w, http.StatusConflict, "station_no_matching_products", "Diese Station bietet keine Artikel aus diesem Helfer-Essen an.")
+	case errors.Is(err, service.ErrVolunteerTokenNotFound):
+		writeError(w, http.StatusNotFound, "volunteer_not_found", "The volunteer is not on this campaign's roster.")
+	case errors.Is(err, service.ErrVolunteerPersonalLimitReached):
+		writeError(w, http.StatusConflict, "personal_limit_reached", "Dieser persönliche QR-Code wurde bereits vollständig eingelöst.")
+	case errors.Is(err, service.ErrVolunteerRosterEmpty):
+		writeError(w, http.StatusBadRequest, "roster_empty", "The roster contains no volunteers.")
+	case errors.Is(err, service.ErrVolunteerRosterInvalid):
+		writeError(w, http.StatusBadRequest, "roster_invalid", err.Error())
+	case errors.Is(err, service.ErrVolunteerCampaignOutsideWindow):
+		writeError(w, http.StatusConflict, "outside_window", "Ausserhalb der Essenszeiten dieses Helfer-Essens.")
+	case errors.Is(err, service.ErrVolunteerWindowLimitReached):
+		writeError(w, http.StatusConflict, "window_limit_reached", "Für dieses Zeitfenster wurde das Limit bereits erreicht.")
+	case errors.Is(err, service.ErrVolunteerDailyLimitReached):
+		writeError(w, http.StatusConflict, "daily_limit_reached", "Für heute wurde das Limit bereits erreicht.")
+	case errors.Is(err, service.ErrVolunteerScheduleInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_schedule", err.Error())
+	case errors.Is(err, service.ErrVolunteerChoiceGroupsInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_choice_groups", err.Error())
+	case errors.Is(err, service.ErrVolunteerChoiceInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_choice", "Diese Auswahl ist für dieses Helfer-Essen an dieser Station nicht möglich.")
+	case errors.Is(err, service.ErrVolunteerSlipCountInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_count", err.Error())
 	default:
 		h.logger.Error("volunteer error", zap.Error(err))
 		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
@@ -340,9 +385,11 @@ func campaignToAdminResponse(c *ent.VolunteerCampaign) adminCampaignResponse {
 		AccessCode:      c.AccessCode,
 		ValidFrom:       c.ValidFrom,
 		ValidUntil:      c.ValidUntil,
+		EventID:         c.EventID,
 		Status:          string(c.Status),
 		MaxRedemptions:  c.MaxRedemptions,
 		RedemptionCount: c.RedemptionCount,
+		LogoURL:         c.LogoURL,
 		CreatedAt:       c.CreatedAt,
 		UpdatedAt:       c.UpdatedAt,
 	}
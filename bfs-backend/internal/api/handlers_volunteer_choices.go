@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"backend/internal/generated/api/generated"
+	nanoid "backend/internal/id"
+	"backend/internal/repository"
+	"backend/internal/response"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// volunteerChoiceGroupRequest defines a "pick one" group: each redemption
+// gets quantity of the product the station picks out of productIds.
+type volunteerChoiceGroupRequest struct {
+	Name       string   `json:"name"`
+	Quantity   int      `json:"quantity"`
+	ProductIDs []string `json:"productIds"`
+}
+
+type volunteerChoiceGroupPayload struct {
+	ID       string                         `json:"id"`
+	Name     string                         `json:"name"`
+	Quantity int                            `json:"quantity"`
+	Options  []volunteerChoiceOptionPayload `json:"options"`
+}
+
+type volunteerChoiceOptionPayload struct {
+	ProductID    string  `json:"productId"`
+	ProductName  string  `json:"productName"`
+	ProductImage *string `json:"productImage,omitempty"`
+}
+
+// GetVolunteerChoiceGroups (GET /v1/staff-meals/{campaignId}/choices)
+func (h *Handlers) GetVolunteerChoiceGroups(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	groups, err := h.volunteers.GetChoiceGroups(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": choiceGroupsToPayload(groups)})
+}
+
+// PutVolunteerChoiceGroups replaces the campaign's choice groups.
+// PUT /v1/staff-meals/{campaignId}/choices
+func (h *Handlers) PutVolunteerChoiceGroups(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	var req struct {
+		Items []volunteerChoiceGroupRequest `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	in, err := choiceGroupsFromRequest(req.Items)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_choice_groups", err.Error())
+		return
+	}
+	groups, err := h.volunteers.SetChoiceGroups(r.Context(), id, in)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": choiceGroupsToPayload(groups)})
+}
+
+func choiceGroupsFromRequest(items []volunteerChoiceGroupRequest) ([]repository.VolunteerChoiceGroupInput, error) {
+	out := make([]repository.VolunteerChoiceGroupInput, 0, len(items))
+	for i, g := range items {
+		for _, pid := range g.ProductIDs {
+			if !nanoid.Valid(pid) {
+				return nil, fmt.Errorf("group %d: invalid product id", i+1)
+			}
+		}
+		out = append(out, repository.VolunteerChoiceGroupInput{
+			Name:       g.Name,
+			Quantity:   g.Quantity,
+			ProductIDs: g.ProductIDs,
+		})
+	}
+	return out, nil
+}
+
+func choiceGroupsToPayload(groups []service.VolunteerChoiceGroup) []volunteerChoiceGroupPayload {
+	out := make([]volunteerChoiceGroupPayload, 0, len(groups))
+	for _, g := range groups {
+		options := make([]volunteerChoiceOptionPayload, 0, len(g.Options))
+		for _, o := range g.Options {
+			options = append(options, volunteerChoiceOptionPayload{
+				ProductID:    o.ProductID,
+				ProductName:  o.ProductName,
+				ProductImage: o.ProductImage,
+			})
+		}
+		out = append(out, volunteerChoiceGroupPayload{
+			ID:       g.ID,
+			Name:     g.Name,
+			Quantity: g.Quantity,
+			Options:  options,
+		})
+	}
+	return out
+}
+
+// writeChoiceRequired answers a redemption that still needs picks with 409
+// and the open groups, so the station can ask and resubmit.
+func writeChoiceRequired(w http.ResponseWriter, e service.VolunteerChoiceRequiredError) {
+	details := map[string]any{"groups": choiceGroupsToPayload(e.Groups)}
+	response.WriteJSON(w, http.StatusConflict, generated.Error{
+		Code:    "choice_required",
+		Message: "Bitte Auswahl treffen.",
+		Details: &details,
+	})
+}
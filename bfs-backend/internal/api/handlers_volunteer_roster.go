@@ -0,0 +1,205 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/generated/ent"
+	nanoid "backend/internal/id"
+	"backend/internal/pdf"
+	"backend/internal/response"
+	"backend/internal/roster"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const volunteerRosterMaxBytes = 1 << 20 // 1 MB
+
+type volunteerTokenResponse struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Email           *string   `json:"email,omitempty"`
+	Token           string    `json:"token"`
+	MaxRedemptions  int       `json:"maxRedemptions"`
+	RedemptionCount int       `json:"redemptionCount"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+type volunteerImportResponse struct {
+	Imported []volunteerTokenResponse `json:"imported"`
+	Skipped  int                      `json:"skipped"`
+	Errors   []roster.LineError       `json:"errors"`
+}
+
+// ImportVolunteerRoster issues personal tokens from a CSV roster. The CSV is
+// either the raw request body (text/csv) or the "file" field of a multipart
+// form. ?defaultMax sets the limit for rows without one.
+// POST /v1/staff-meals/{campaignId}/volunteers/import
+func (h *Handlers) ImportVolunteerRoster(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+
+	defaultMax := 1
+	if v := r.URL.Query().Get("defaultMax"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_default_max", "defaultMax must be a positive number")
+			return
+		}
+		defaultMax = n
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, volunteerRosterMaxBytes)
+	var src io.Reader = r.Body
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "multipart/form-data" {
+		if err := r.ParseMultipartForm(volunteerRosterMaxBytes); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", "File too large or invalid multipart form")
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", "Missing file field")
+			return
+		}
+		defer func() { _ = file.Close() }()
+		src = file
+	}
+
+	result, err := h.volunteers.ImportVolunteers(r.Context(), id, src, defaultMax)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+
+	resp := volunteerImportResponse{
+		Imported: make([]volunteerTokenResponse, 0, len(result.Imported)),
+		Skipped:  result.Skipped,
+		Errors:   result.Errors,
+	}
+	if resp.Errors == nil {
+		resp.Errors = []roster.LineError{}
+	}
+	for _, t := range result.Imported {
+		resp.Imported = append(resp.Imported, volunteerTokenToResponse(t))
+	}
+	response.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListVolunteerTokens (GET /v1/staff-meals/{campaignId}/volunteers)
+func (h *Handlers) ListVolunteerTokens(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	tokens, err := h.volunteers.ListVolunteers(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	items := make([]volunteerTokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		items = append(items, volunteerTokenToResponse(t))
+	}
+	response.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// DeleteVolunteerToken revokes a volunteer's personal QR code. Past
+// redemptions are kept.
+// DELETE /v1/staff-meals/{campaignId}/volunteers/{volunteerId}
+func (h *Handlers) DeleteVolunteerToken(w http.ResponseWriter, r *http.Request) {
+	campaignID := chi.URLParam(r, "campaignId")
+	volunteerID := chi.URLParam(r, "volunteerId")
+	if !nanoid.Valid(campaignID) || !nanoid.Valid(volunteerID) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	if err := h.volunteers.DeleteVolunteer(r.Context(), campaignID, volunteerID); err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PrintPersonalStaffMealSlips streams a PDF with one slip per volunteer.
+// ?ids=a,b limits it to some volunteers, e.g. to reprint a lost slip. See
+// parseSlipLayout for the format and printLocale for ?lang.
+// GET /v1/staff-meals/{campaignId}/volunteers/print.pdf
+func (h *Handlers) PrintPersonalStaffMealSlips(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	var only map[string]bool
+	if v := r.URL.Query().Get("ids"); v != "" {
+		only = make(map[string]bool)
+		for _, vid := range strings.Split(v, ",") {
+			only[strings.TrimSpace(vid)] = true
+		}
+	}
+	layout, err := parseSlipLayout(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_layout", err.Error())
+		return
+	}
+
+	content, err := h.volunteers.GetSlipContent(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	tokens, err := h.volunteers.ListVolunteers(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+
+	slips := make([]pdf.PersonalSlip, 0, len(tokens))
+	for _, t := range tokens {
+		if only != nil && !only[t.ID] {
+			continue
+		}
+		slips = append(slips, pdf.PersonalSlip{
+			Holder:         t.Name,
+			QRPayload:      h.qr.VolunteerPayload(t, content.Campaign),
+			MaxRedemptions: t.MaxRedemptions,
+		})
+	}
+	if len(slips) == 0 {
+		writeError(w, http.StatusBadRequest, "no_volunteers", "Import a volunteer roster first.")
+		return
+	}
+
+	body, err := pdf.RenderPersonalStaffMealSlips(pdf.PersonalSlipInput{
+		CampaignName: content.Campaign.Name,
+		Products:     slipProducts(content),
+		Slips:        slips,
+		Style:        h.slipStyle(r.Context(), content, layout, printLocale(r)),
+	})
+	if err != nil {
+		h.writeSlipRenderError(w, err)
+		return
+	}
+	writePDF(w, body, fmt.Sprintf("staff-meal-%s-personal.pdf", content.Campaign.ID))
+}
+
+func volunteerTokenToResponse(t *ent.VolunteerToken) volunteerTokenResponse {
+	return volunteerTokenResponse{
+		ID:              t.ID,
+		Name:            t.Name,
+		Email:           t.Email,
+		Token:           t.Token,
+		MaxRedemptions:  t.MaxRedemptions,
+		RedemptionCount: t.RedemptionCount,
+		CreatedAt:       t.CreatedAt,
+	}
+}
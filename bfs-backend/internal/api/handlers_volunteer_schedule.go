@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	nanoid "backend/internal/id"
+	"backend/internal/response"
+	"backend/internal/schedule"
+	"backend/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// volunteerWindowPayload is a meal window in Europe/Zurich wall-clock time.
+// Weekdays are ISO numbers (1 = Monday … 7 = Sunday); empty means every day.
+type volunteerWindowPayload struct {
+	ID             string `json:"id,omitempty"`
+	Label          string `json:"label"`
+	Weekdays       []int  `json:"weekdays"`
+	Start          string `json:"start"`
+	End            string `json:"end"`
+	MaxRedemptions *int   `json:"maxRedemptions,omitempty"`
+	MaxPerPerson   *int   `json:"maxPerPerson,omitempty"`
+}
+
+type volunteerSchedulePayload struct {
+	MaxRedemptionsPerDay *int                     `json:"maxRedemptionsPerDay,omitempty"`
+	MaxPerPersonPerDay   *int                     `json:"maxPerPersonPerDay,omitempty"`
+	Windows              []volunteerWindowPayload `json:"windows"`
+}
+
+type claimWindowOccurrence struct {
+	Label  string    `json:"label"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Active bool      `json:"active"`
+}
+
+// GetVolunteerSchedule (GET /v1/staff-meals/{campaignId}/schedule)
+func (h *Handlers) GetVolunteerSchedule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	sched, err := h.volunteers.GetSchedule(r.Context(), id)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, scheduleToPayload(sched))
+}
+
+// PutVolunteerSchedule replaces the meal windows and per-day limits.
+// PUT /v1/staff-meals/{campaignId}/schedule
+func (h *Handlers) PutVolunteerSchedule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "campaignId")
+	if !nanoid.Valid(id) {
+		writeError(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return
+	}
+	var req volunteerSchedulePayload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+
+	in := service.VolunteerSchedule{
+		MaxRedemptionsPerDay: req.MaxRedemptionsPerDay,
+		MaxPerPersonPerDay:   req.MaxPerPersonPerDay,
+		Windows:              make([]service.VolunteerWindow, 0, len(req.Windows)),
+	}
+	for i, p := range req.Windows {
+		win, err := windowFromPayload(p)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_schedule", fmt.Sprintf("window %d: %v", i+1, err))
+			return
+		}
+		in.Windows = append(in.Windows, win)
+	}
+
+	sched, err := h.volunteers.SetSchedule(r.Context(), id, in)
+	if err != nil {
+		h.writeVolunteerError(w, err)
+		return
+	}
+	response.WriteJSON(w, http.StatusOK, scheduleToPayload(sched))
+}
+
+func windowFromPayload(p volunteerWindowPayload) (service.VolunteerWindow, error) {
+	weekdays := schedule.AllWeekdays
+	if len(p.Weekdays) > 0 {
+		var err error
+		if weekdays, err = schedule.WeekdaysFromISO(p.Weekdays); err != nil {
+			return service.VolunteerWindow{}, fmt.Errorf("weekdays must be between 1 (Monday) and 7 (Sunday)")
+		}
+	}
+	start, err := schedule.ParseClock(p.Start)
+	if err != nil {
+		return service.VolunteerWindow{}, fmt.Errorf("start must be HH:MM")
+	}
+	end, err := schedule.ParseClock(p.End)
+	if err != nil {
+		return service.VolunteerWindow{}, fmt.Errorf("end must be HH:MM")
+	}
+	return service.VolunteerWindow{
+		Label:          p.Label,
+		Weekdays:       weekdays,
+		StartMinute:    start,
+		EndMinute:      end,
+		MaxRedemptions: p.MaxRedemptions,
+		MaxPerPerson:   p.MaxPerPerson,
+	}, nil
+}
+
+func scheduleToPayload(s *service.VolunteerSchedule) volunteerSchedulePayload {
+	return volunteerSchedulePayload{
+		MaxRedemptionsPerDay: s.MaxRedemptionsPerDay,
+		MaxPerPersonPerDay:   s.MaxPerPersonPerDay,
+		Windows:              windowsToPayload(s.Windows),
+	}
+}
+
+func windowsToPayload(windows []service.VolunteerWindow) []volunteerWindowPayload {
+	out := make([]volunteerWindowPayload, 0, len(windows))
+	for _, w := range windows {
+		out = append(out, volunteerWindowPayload{
+			ID:             w.ID,
+			Label:          w.Label,
+			Weekdays:       w.Weekdays.ISO(),
+			Start:          schedule.FormatClock(w.StartMinute),
+			End:            schedule.FormatClock(w.EndMinute),
+			MaxRedemptions: w.MaxRedemptions,
+			MaxPerPerson:   w.MaxPerPerson,
+		})
+	}
+	return out
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"net/http"
 	"time"
 
+	"backend/internal/allergen"
 	"backend/internal/generated/api/generated"
 	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/product"
+	"backend/internal/i18n"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
@@ -30,12 +34,13 @@ func derefStr(s *string) string {
 
 func toAPICategory(e *ent.Category) generated.Category {
 	return generated.Category{
-		Id:        e.ID,
-		Name:      e.Name,
-		IsActive:  e.IsActive,
-		Position:  e.Position,
-		CreatedAt: ptr(e.CreatedAt),
-		UpdatedAt: ptr(e.UpdatedAt),
+		Id:           e.ID,
+		Name:         e.Name,
+		IsActive:     e.IsActive,
+		Position:     e.Position,
+		Translations: toAPITranslations(e.Translations),
+		CreatedAt:    ptr(e.CreatedAt),
+		UpdatedAt:    ptr(e.UpdatedAt),
 	}
 }
 
@@ -61,17 +66,24 @@ func toAPICategorySummary(e *ent.Category) generated.CategorySummary {
 
 func toAPIProduct(e *ent.Product) generated.Product {
 	p := generated.Product{
-		Id:          e.ID,
-		CategoryId:  e.CategoryID,
-		Type:        generated.ProductType(e.Type),
-		Name:        e.Name,
-		Image:       e.Image,
-		Description: e.Description,
-		PriceCents:  e.PriceCents,
-		JetonId:     (*string)(e.JetonID),
-		IsActive:    e.IsActive,
-		CreatedAt:   ptr(e.CreatedAt),
-		UpdatedAt:   ptr(e.UpdatedAt),
+		Id:           e.ID,
+		CategoryId:   e.CategoryID,
+		Type:         generated.ProductType(e.Type),
+		Name:         e.Name,
+		Image:        e.Image,
+		Description:  e.Description,
+		PriceCents:   e.PriceCents,
+		JetonId:      (*string)(e.JetonID),
+		IsActive:     e.IsActive,
+		Allergens:    toAPIAllergens(catalogAllergens(e)),
+		DietaryTags:  toAPIDietaryTags(e.DietaryTags),
+		Translations: toAPITranslations(e.Translations),
+		CreatedAt:    ptr(e.CreatedAt),
+		UpdatedAt:    ptr(e.UpdatedAt),
+	}
+
+	if e.Type == product.TypeMenu {
+		p.DeclaredAllergens = toAPIAllergens(e.Allergens)
 	}
 
 	// Map Category edge if loaded.
@@ -95,9 +107,114 @@ func toAPIProduct(e *ent.Product) generated.Product {
 		p.MenuSlots = &summaries
 	}
 
+	// Map Variants edge if loaded.
+	if variants, err := e.Edges.VariantsOrErr(); err == nil && len(variants) > 0 {
+		vs := make([]generated.ProductVariant, 0, len(variants))
+		for _, v := range variants {
+			vs = append(vs, toAPIProductVariant(v))
+		}
+		p.Variants = &vs
+	}
+
 	return p
 }
 
+// catalogAllergens are the allergens a product is listed with: a menu's own
+// together with those of every slot option, when the slots are loaded.
+func catalogAllergens(e *ent.Product) []string {
+	lists := [][]string{e.Allergens}
+	for _, s := range e.Edges.MenuSlots {
+		for _, o := range s.Edges.Options {
+			if o.Edges.OptionProduct != nil {
+				lists = append(lists, o.Edges.OptionProduct.Allergens)
+			}
+		}
+	}
+	return allergen.Union(lists...)
+}
+
+func toAPIAllergens(keys []string) *[]generated.Allergen {
+	out := make([]generated.Allergen, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, generated.Allergen(k))
+	}
+	return &out
+}
+
+func toAPIDietaryTags(keys []string) *[]generated.DietaryTag {
+	out := make([]generated.DietaryTag, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, generated.DietaryTag(k))
+	}
+	return &out
+}
+
+func toAPITranslations(t i18n.Translations) *generated.Translations {
+	out := generated.Translations{}
+	if tx, ok := t[i18n.French]; ok {
+		out.Fr = toAPITranslatedText(tx)
+	}
+	if tx, ok := t[i18n.English]; ok {
+		out.En = toAPITranslatedText(tx)
+	}
+	return &out
+}
+
+func toAPITranslatedText(tx i18n.Text) *generated.TranslatedText {
+	out := generated.TranslatedText{Description: tx.Description}
+	if tx.Name != "" {
+		out.Name = ptr(tx.Name)
+	}
+	return &out
+}
+
+// bodyLocale is the locale a request body names, or else the one the request
+// asks for.
+func bodyLocale(r *http.Request, l *generated.Locale) i18n.Locale {
+	if l != nil {
+		if parsed, ok := i18n.Parse(string(*l)); ok {
+			return parsed
+		}
+	}
+	return i18n.FromRequest(r)
+}
+
+// fromAPITranslations converts a request body's translations; texts left out
+// are dropped.
+func fromAPITranslations(t generated.Translations) i18n.Translations {
+	out := make(i18n.Translations)
+	for l, tx := range map[i18n.Locale]*generated.TranslatedText{i18n.French: t.Fr, i18n.English: t.En} {
+		if tx != nil {
+			out[l] = i18n.Text{Name: derefStr(tx.Name), Description: tx.Description}
+		}
+	}
+	return out
+}
+
+func toAPIProductVariant(e *ent.ProductVariant) generated.ProductVariant {
+	v := generated.ProductVariant{
+		Id:         e.ID,
+		ProductId:  e.ProductID,
+		Name:       e.Name,
+		PriceCents: e.PriceCents,
+		JetonId:    e.JetonID,
+		Position:   e.Position,
+		IsActive:   e.IsActive,
+	}
+	if e.Edges.Jeton != nil {
+		js := toAPIJetonSummary(e.Edges.Jeton)
+		v.Jeton = &js
+	}
+	if links, err := e.Edges.DeviceVariantsOrErr(); err == nil {
+		stationIDs := make([]string, 0, len(links))
+		for _, l := range links {
+			stationIDs = append(stationIDs, l.DeviceID)
+		}
+		v.StationIds = &stationIDs
+	}
+	return v
+}
+
 func toAPIProducts(rows []*ent.Product) []generated.Product {
 	out := make([]generated.Product, 0, len(rows))
 	for _, r := range rows {
@@ -144,6 +261,7 @@ func toAPIOrder(e *ent.Order) generated.Order {
 	o := generated.Order{
 		Id:                   e.ID,
 		CustomerId:           e.CustomerID,
+		EventId:              e.EventID,
 		TotalCents:           e.TotalCents,
 		Status:               generated.OrderStatus(e.Status),
 		Origin:               generated.OrderOrigin(e.Origin),
@@ -196,6 +314,17 @@ func toAPIOrderLine(e *ent.OrderLine) generated.OrderLine {
 		ParentLineId:   (*string)(e.ParentLineID),
 		MenuSlotId:     (*string)(e.MenuSlotID),
 		MenuSlotName:   e.MenuSlotName,
+		VariantId:      e.VariantID,
+		VariantName:    e.VariantName,
+		PriceRuleId:    e.PriceRuleID,
+		ListPriceCents: e.ListPriceCents,
+	}
+	if len(e.Allergens) > 0 {
+		ol.Allergens = toAPIAllergens(e.Allergens)
+	}
+	if e.RedeemedQuantity > 0 {
+		redeemed := e.RedeemedQuantity
+		ol.RedeemedQuantity = &redeemed
 	}
 
 	if e.Edges.Product != nil {
@@ -365,6 +494,7 @@ func toAPIMenuSlot(e *ent.MenuSlot) generated.MenuSlot {
 		MenuProductId: e.MenuProductID,
 		Name:          e.Name,
 		Sequence:      e.Sequence,
+		Translations:  toAPITranslations(e.Translations),
 	}
 
 	// Map Options edge if loaded.
@@ -381,15 +511,18 @@ func toAPIMenuSlot(e *ent.MenuSlot) generated.MenuSlot {
 
 func toAPIMenuSlotSummary(e *ent.MenuSlot) generated.MenuSlotSummary {
 	ms := generated.MenuSlotSummary{
-		Id:       ptr(e.ID),
-		Name:     ptr(e.Name),
-		Sequence: ptr(e.Sequence),
+		Id:           ptr(e.ID),
+		Name:         ptr(e.Name),
+		Sequence:     ptr(e.Sequence),
+		Translations: toAPITranslations(e.Translations),
 	}
 
 	// Map Options edge if loaded, producing inline option summaries.
 	if opts, err := e.Edges.OptionsOrErr(); err == nil {
 		type optEntry = struct {
+			Allergens   *[]generated.Allergen   `json:"allergens,omitempty"`
 			Description *string                 `json:"description"`
+			DietaryTags *[]generated.DietaryTag `json:"dietaryTags,omitempty"`
 			Image       *string                 `json:"image"`
 			Jeton       *generated.JetonSummary `json:"jeton,omitempty"`
 			Name        *string                 `json:"name,omitempty"`
@@ -406,6 +539,8 @@ func toAPIMenuSlotSummary(e *ent.MenuSlot) generated.MenuSlotSummary {
 				entry.PriceCents = ptr(o.Edges.OptionProduct.PriceCents)
 				entry.Image = o.Edges.OptionProduct.Image
 				entry.Description = o.Edges.OptionProduct.Description
+				entry.Allergens = toAPIAllergens(o.Edges.OptionProduct.Allergens)
+				entry.DietaryTags = toAPIDietaryTags(o.Edges.OptionProduct.DietaryTags)
 				if o.Edges.OptionProduct.Edges.Jeton != nil {
 					js := toAPIJetonSummary(o.Edges.OptionProduct.Edges.Jeton)
 					entry.Jeton = &js
@@ -516,6 +651,7 @@ func toAPIInventoryLedgerEntry(e *ent.InventoryLedger) generated.InventoryLedger
 	if e.DeviceID != nil {
 		entry.DeviceId = (*string)(e.DeviceID)
 	}
+	entry.VariantId = e.VariantID
 	return entry
 }
 
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/generated/ent/club100syncrun"
+	"backend/internal/membership"
+	"backend/internal/service"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// club100SyncStartDelay lets the server come up before the first sync.
+const club100SyncStartDelay = 10 * time.Second
+
+// StartClub100Sync refreshes the Club100 member directory from the
+// membership provider on start and then every MEMBERSHIP_SYNC_INTERVAL. CSV
+// lists only change on upload, which syncs by itself.
+func StartClub100Sync(lc fx.Lifecycle, cfg config.Config, directory membership.Provider, club100 service.Club100Service, logger *zap.Logger) {
+	if !directory.IsConfigured() {
+		logger.Warn("membership provider not configured, club100 member sync disabled", zap.String("provider", directory.Name()))
+		return
+	}
+	if directory.Name() == membership.ProviderCSV {
+		return
+	}
+	interval := cfg.Membership.SyncInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				timer := time.NewTimer(club100SyncStartDelay)
+				defer timer.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-timer.C:
+					}
+					if _, err := club100.SyncMembers(ctx, club100syncrun.TriggerScheduled); err != nil &&
+						!errors.Is(err, service.ErrClub100SyncRunning) {
+						logger.Error("scheduled club100 member sync failed", zap.Error(err))
+					}
+					timer.Reset(interval)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
@@ -21,8 +21,8 @@ func NewHandlers() fx.Option {
 	)
 }
 
-func NewSystemDisableMiddleware(settings service.SettingsService, sessionMw *auth.SessionMiddleware) *middleware.SystemDisableMiddleware {
-	return middleware.NewSystemDisableMiddleware(settings, sessionMw)
+func NewSystemDisableMiddleware(openingHours service.OpeningHoursService, sessionMw *auth.SessionMiddleware) *middleware.SystemDisableMiddleware {
+	return middleware.NewSystemDisableMiddleware(openingHours, sessionMw)
 }
 
 func NewSecurityMiddleware(cfg config.Config) *middleware.SecurityMiddleware {
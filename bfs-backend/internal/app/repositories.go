@@ -20,16 +20,24 @@ func NewRepositories() fx.Option {
 		fx.Provide(
 			repository.NewCategoryRepository,
 			repository.NewProductRepository,
+			repository.NewProductVariantRepository,
 			repository.NewJetonRepository,
 			repository.NewMenuSlotRepository,
 			repository.NewMenuSlotOptionRepository,
 			repository.NewDeviceRepository,
 			repository.NewDeviceProductRepository,
 			repository.NewSettingsRepository,
+			repository.NewSettingsVersionRepository,
+			repository.NewOpeningHoursRepository,
+			repository.NewEventRepository,
+			repository.NewPriceRuleRepository,
+			repository.NewAvailabilityRepository,
 			repository.NewOrderRepository,
 			repository.NewOrderPaymentRepository,
 			repository.NewOrderLineRepository,
 			repository.NewOrderLineRedemptionRepository,
+			repository.NewRedemptionBatchRepository,
+			repository.NewAnalyticsRepository,
 			repository.NewInventoryLedgerRepository,
 			repository.NewAdminInviteRepository,
 			repository.NewUserRepository,
@@ -37,8 +45,13 @@ func NewRepositories() fx.Option {
 			newCachedSessionRepository,
 			newCachedDeviceBindingRepository,
 			repository.NewClub100RedemptionRepository,
+			repository.NewClub100MemberRepository,
+			repository.NewClub100PeriodRepository,
+			repository.NewClub100CardRepository,
+			repository.NewMembershipUploadRepository,
 			repository.NewVolunteerCampaignRepository,
 			repository.NewVolunteerRedemptionRepository,
+			repository.NewVolunteerTokenRepository,
 		),
 	)
 }
@@ -4,6 +4,7 @@ import (
 	"backend/internal/inventory"
 	"backend/internal/repository"
 	"backend/internal/service"
+	"backend/internal/stationqueue"
 
 	"go.uber.org/fx"
 )
@@ -13,8 +14,12 @@ func NewServices() fx.Option {
 		fx.Provide(
 			service.NewPaymentService,
 			service.NewSettingsService,
+			service.NewOpeningHoursService,
+			service.NewEventService,
+			service.NewPriceRuleService,
 			service.NewProductService,
 			service.NewCategoryService,
+			service.NewCatalogService,
 			service.NewOrderService,
 			service.NewStationService,
 			service.NewPOSService,
@@ -24,10 +29,16 @@ func NewServices() fx.Option {
 			service.NewUserService,
 			service.NewDeviceService,
 			inventory.NewHub,
-			service.NewElvantoService,
+			stationqueue.NewHub,
+			service.NewMembershipProvider,
 			service.NewClub100Service,
+			service.NewClub100CardService,
 			service.NewVolunteerService,
 			service.NewAndroidUpdateService,
+			service.NewAnalyticsService,
+			service.NewQRService,
+			service.NewTicketService,
 		),
+		fx.Invoke(StartClub100Sync),
 	)
 }
@@ -2,6 +2,7 @@ package blobstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -60,12 +61,38 @@ func (c *Client) Upload(ctx context.Context, data io.ReadSeeker, contentType str
 	return url, nil
 }
 
+// ErrForeignBlob is returned by Download for URLs outside our container.
+var ErrForeignBlob = errors.New("blobstore: not a blob of this container")
+
+// Download reads a blob uploaded through this client, e.g. a logo to embed
+// in a PDF. Blobs larger than maxBytes are rejected.
+func (c *Client) Download(ctx context.Context, blobURL string, maxBytes int64) ([]byte, error) {
+	blobName, ok := c.blobName(blobURL)
+	if !ok {
+		return nil, ErrForeignBlob
+	}
+
+	resp, err := c.client.DownloadStream(ctx, c.containerName, blobName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: download failed: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("blobstore: blob larger than %d bytes", maxBytes)
+	}
+	return data, nil
+}
+
 func (c *Client) Delete(ctx context.Context, blobURL string) error {
-	prefix := fmt.Sprintf("%s/%s/", c.blobEndpoint, c.containerName)
-	if !strings.HasPrefix(blobURL, prefix) {
+	blobName, ok := c.blobName(blobURL)
+	if !ok {
 		return nil
 	}
-	blobName := strings.TrimPrefix(blobURL, prefix)
 
 	_, err := c.client.DeleteBlob(ctx, c.containerName, blobName, nil)
 	if err != nil {
@@ -74,6 +101,14 @@ func (c *Client) Delete(ctx context.Context, blobURL string) error {
 	return nil
 }
 
+func (c *Client) blobName(blobURL string) (string, bool) {
+	prefix := fmt.Sprintf("%s/%s/", c.blobEndpoint, c.containerName)
+	if !strings.HasPrefix(blobURL, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(blobURL, prefix), true
+}
+
 func (c *Client) EnsureContainer(ctx context.Context) error {
 	access := container.PublicAccessTypeBlob
 	_, err := c.client.CreateContainer(ctx, c.containerName, &azblob.CreateContainerOptions{
@@ -0,0 +1,156 @@
+// Package catalogfile reads and writes catalog exports: jetons, categories
+// and products with their variants, station routing and menu slots. Records
+// refer to each other by name instead of by id, so a file can be imported
+// into another installation and edited in a spreadsheet.
+//
+// Lists and texts left out of a record (null in JSON, a missing column in
+// CSV) keep their current value on import; an empty list or text clears
+// them. The texts are a product's description, image and jeton and a
+// variant's jeton.
+package catalogfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"backend/internal/i18n"
+)
+
+// Version of the file format written by WriteJSON and WriteCSV.
+const Version = 1
+
+var (
+	ErrEmpty              = errors.New("catalog_empty")
+	ErrUnsupportedVersion = errors.New("catalog_version_unsupported")
+)
+
+type Catalog struct {
+	Version    int        `json:"version"`
+	Jetons     []Jeton    `json:"jetons"`
+	Categories []Category `json:"categories"`
+	Products   []Product  `json:"products"`
+}
+
+type Jeton struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Line  int    `json:"-"`
+}
+
+type Category struct {
+	Name         string            `json:"name"`
+	Position     int               `json:"position"`
+	IsActive     *bool             `json:"isActive,omitempty"`
+	Translations i18n.Translations `json:"translations"`
+	Line         int               `json:"-"`
+}
+
+// Product is a simple product or a menu. Only simple products have variants
+// and only menus have slots. Stations are station device names.
+type Product struct {
+	Name         string            `json:"name"`
+	Category     string            `json:"category"`
+	Type         string            `json:"type"`
+	PriceCents   int64             `json:"priceCents"`
+	IsActive     *bool             `json:"isActive,omitempty"`
+	Description  *string           `json:"description,omitempty"`
+	Image        *string           `json:"image,omitempty"`
+	Jeton        *string           `json:"jeton,omitempty"`
+	Allergens    []string          `json:"allergens"`
+	DietaryTags  []string          `json:"dietaryTags"`
+	Translations i18n.Translations `json:"translations"`
+	Stations     []string          `json:"stations"`
+	Variants     []Variant         `json:"variants,omitempty"`
+	Slots        []Slot            `json:"slots,omitempty"`
+	Line         int               `json:"-"`
+}
+
+// Variant of a simple product. A nil Jeton uses the product's; no Stations
+// route the variant with its product.
+type Variant struct {
+	Name       string   `json:"name"`
+	PriceCents int64    `json:"priceCents"`
+	Jeton      *string  `json:"jeton,omitempty"`
+	Position   int      `json:"position"`
+	IsActive   *bool    `json:"isActive,omitempty"`
+	Stations   []string `json:"stations"`
+	Line       int      `json:"-"`
+}
+
+// Slot of a menu. Options are product names.
+type Slot struct {
+	Name         string            `json:"name"`
+	Sequence     int               `json:"sequence"`
+	Translations i18n.Translations `json:"translations"`
+	Options      []string          `json:"options"`
+	Line         int               `json:"-"`
+}
+
+// Active reports whether the record is active; records that leave it out are.
+func Active(v *bool) bool {
+	return v == nil || *v
+}
+
+// Problem is a record that cannot be imported. Line is its line in a CSV
+// file and zero for JSON.
+type Problem struct {
+	Line    int    `json:"line,omitempty"`
+	Record  string `json:"record"`
+	Message string `json:"message"`
+}
+
+// Key is how records are matched: names compared without case and
+// surrounding space.
+func Key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func (j Jeton) Ref() string    { return fmt.Sprintf("jeton %q", j.Name) }
+func (c Category) Ref() string { return fmt.Sprintf("category %q", c.Name) }
+func (p Product) Ref() string  { return fmt.Sprintf("product %q", p.Name) }
+
+func (v Variant) Ref(product string) string {
+	return fmt.Sprintf("variant %q of %q", v.Name, product)
+}
+
+func (s Slot) Ref(menu string) string {
+	return fmt.Sprintf("slot %q of %q", s.Name, menu)
+}
+
+// WriteJSON writes c indented, for review and diffs.
+func WriteJSON(w io.Writer, c *Catalog) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// ParseJSON reads a catalog written by WriteJSON. Unknown fields are an
+// error so typos in hand-edited files do not go unnoticed.
+func ParseJSON(r io.Reader) (*Catalog, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var c Catalog
+	if err := dec.Decode(&c); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmpty
+		}
+		return nil, err
+	}
+	if err := c.checkVersion(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// checkVersion accepts the current version; files without one are taken to
+// be current.
+func (c *Catalog) checkVersion() error {
+	if c.Version != 0 && c.Version != Version {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, c.Version)
+	}
+	c.Version = Version
+	return nil
+}
@@ -0,0 +1,177 @@
+package catalogfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"backend/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func sample() *Catalog {
+	return &Catalog{
+		Version: Version,
+		Jetons:  []Jeton{{Name: "Grill", Color: "#FF0000"}},
+		Categories: []Category{{
+			Name:         "Essen",
+			Position:     1,
+			IsActive:     ptr(true),
+			Translations: i18n.Translations{i18n.French: {Name: "Repas"}},
+		}},
+		Products: []Product{
+			{
+				Name:         "Bratwurst",
+				Category:     "Essen",
+				Type:         "simple",
+				PriceCents:   750,
+				IsActive:     ptr(true),
+				Description:  ptr("Mit Senf, dazu Brot"),
+				Jeton:        ptr("Grill"),
+				Allergens:    []string{"gluten", "mustard"},
+				DietaryTags:  []string{},
+				Translations: i18n.Translations{i18n.English: {Name: "Sausage", Description: ptr("With mustard")}},
+				Stations:     []string{"Grill 1", "Grill 2"},
+				Variants: []Variant{
+					{Name: "Doppelt", PriceCents: 1200, Position: 1, IsActive: ptr(false), Stations: []string{}},
+				},
+			},
+			{
+				Name:         "Grillmenü",
+				Category:     "Essen",
+				Type:         "menu",
+				PriceCents:   1500,
+				IsActive:     ptr(true),
+				Allergens:    []string{},
+				DietaryTags:  []string{"halal"},
+				Translations: i18n.Translations{},
+				Stations:     []string{},
+				Slots: []Slot{
+					{Name: "Hauptgang", Sequence: 0, Translations: i18n.Translations{}, Options: []string{"Bratwurst"}},
+				},
+			},
+		},
+	}
+}
+
+// stripLines drops the source lines ParseCSV records, for comparing with
+// the catalog that was written.
+func stripLines(c *Catalog) {
+	for i := range c.Jetons {
+		c.Jetons[i].Line = 0
+	}
+	for i := range c.Categories {
+		c.Categories[i].Line = 0
+	}
+	for i := range c.Products {
+		c.Products[i].Line = 0
+		for j := range c.Products[i].Variants {
+			c.Products[i].Variants[j].Line = 0
+		}
+		for j := range c.Products[i].Slots {
+			c.Products[i].Slots[j].Line = 0
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, sample()))
+
+	got, problems, err := ParseCSV(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	stripLines(got)
+
+	// Texts the export leaves empty read back as empty, which clears them on
+	// import just as they are unset in the export.
+	want := sample()
+	want.Products[0].Image = ptr("")
+	want.Products[0].Variants[0].Jeton = ptr("")
+	want.Products[1].Description, want.Products[1].Image, want.Products[1].Jeton = ptr(""), ptr(""), ptr("")
+	assert.Equal(t, want, got)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, sample()))
+
+	got, err := ParseJSON(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, sample(), got)
+}
+
+func TestParseCSVExcelWithFewColumns(t *testing.T) {
+	in := "\xef\xbb\xbfRecord;Name;Parent;Price_Cents;Active\r\n" +
+		"slot;Beilage;Menü;;\r\n" +
+		"product;Menü;;1200;nein\r\n" +
+		"variant;Gross;menü;500;\r\n"
+	c, problems, err := ParseCSV(strings.NewReader(in))
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	require.Len(t, c.Products, 1)
+
+	p := c.Products[0]
+	assert.Equal(t, int64(1200), p.PriceCents)
+	assert.False(t, Active(p.IsActive))
+	assert.Equal(t, 3, p.Line)
+	// Lists, texts and translations without a column are left unchanged on
+	// import.
+	assert.Nil(t, p.Stations)
+	assert.Nil(t, p.Image)
+	assert.Nil(t, p.Jeton)
+	assert.Nil(t, p.Allergens)
+	assert.Nil(t, p.Translations)
+	require.Len(t, p.Slots, 1)
+	assert.Nil(t, p.Slots[0].Options)
+	require.Len(t, p.Variants, 1)
+	assert.True(t, Active(p.Variants[0].IsActive))
+}
+
+func TestParseCSVReportsProblems(t *testing.T) {
+	in := "record,name,parent,price_cents,active\n" +
+		"product,Cola,,zwei,true\n" +
+		"drink,Fanta,,,\n" +
+		"variant,Gross,Bier,500,\n" +
+		"product,Wasser,,300,maybe\n"
+	c, problems, err := ParseCSV(strings.NewReader(in))
+	require.NoError(t, err)
+	assert.Len(t, c.Products, 2)
+	assert.Equal(t, []Problem{
+		{Line: 2, Record: `product "Cola"`, Message: "price_cents must be a whole number"},
+		{Line: 3, Record: `drink "Fanta"`, Message: `unknown record "drink"; use jeton, category, product, variant or slot`},
+		{Line: 5, Record: `product "Wasser"`, Message: "active must be true or false"},
+		{Line: 4, Record: `variant "Gross" of "Bier"`, Message: "parent product is not in the file"},
+	}, problems)
+}
+
+func TestParseCSVHeaderAndEmpty(t *testing.T) {
+	_, _, err := ParseCSV(strings.NewReader("Cola,product\n"))
+	assert.ErrorIs(t, err, ErrMissingHeader)
+
+	for _, in := range []string{"", "\xef\xbb\xbf", " \n", "record,name\n"} {
+		_, _, err := ParseCSV(strings.NewReader(in))
+		assert.ErrorIs(t, err, ErrEmpty, "input %q", in)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	_, err := ParseJSON(strings.NewReader(`{"version": 2, "products": []}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = ParseJSON(strings.NewReader(`{"products": [{"name": "Cola", "prize": 300}]}`))
+	assert.ErrorContains(t, err, "prize")
+
+	_, err = ParseJSON(strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	c, err := ParseJSON(strings.NewReader(`{"products": [{"name": "Cola", "category": "Getränke", "type": "simple"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, Version, c.Version)
+	assert.True(t, Active(c.Products[0].IsActive))
+	assert.Nil(t, c.Products[0].Stations)
+}
@@ -0,0 +1,434 @@
+package catalogfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"backend/internal/i18n"
+)
+
+// The CSV has one record per row. The record column says what the row is; a
+// variant or slot row names its product in parent. Lists are separated by
+// listSeparator because ',' and ';' delimit the columns.
+const (
+	recordJeton    = "jeton"
+	recordCategory = "category"
+	recordProduct  = "product"
+	recordVariant  = "variant"
+	recordSlot     = "slot"
+
+	listSeparator = "|"
+)
+
+const (
+	colRecord        = "record"
+	colName          = "name"
+	colParent        = "parent"
+	colCategory      = "category"
+	colType          = "type"
+	colPriceCents    = "price_cents"
+	colPosition      = "position"
+	colActive        = "active"
+	colJeton         = "jeton"
+	colColor         = "color"
+	colStations      = "stations"
+	colOptions       = "options"
+	colAllergens     = "allergens"
+	colDietaryTags   = "dietary_tags"
+	colImage         = "image"
+	colDescription   = "description"
+	colNameFr        = "name_fr"
+	colNameEn        = "name_en"
+	colDescriptionFr = "description_fr"
+	colDescriptionEn = "description_en"
+)
+
+// csvColumns in the order WriteCSV writes them. The position column is the
+// sort position of categories and variants and the sequence of slots.
+var csvColumns = []string{
+	colRecord, colName, colParent, colCategory, colType, colPriceCents, colPosition, colActive,
+	colJeton, colColor, colStations, colOptions, colAllergens, colDietaryTags, colImage, colDescription,
+	colNameFr, colNameEn, colDescriptionFr, colDescriptionEn,
+}
+
+var ErrMissingHeader = errors.New("catalog_header_missing")
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// WriteCSV writes c with a header row: jetons, categories, then each product
+// followed by its variants and slots.
+func WriteCSV(w io.Writer, c *Catalog) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+	write := func(fields map[string]string) error {
+		rec := make([]string, len(csvColumns))
+		for i, col := range csvColumns {
+			rec[i] = fields[col]
+		}
+		return cw.Write(rec)
+	}
+	for _, j := range c.Jetons {
+		if err := write(map[string]string{colRecord: recordJeton, colName: j.Name, colColor: j.Color}); err != nil {
+			return err
+		}
+	}
+	for _, cat := range c.Categories {
+		fields := map[string]string{
+			colRecord:   recordCategory,
+			colName:     cat.Name,
+			colPosition: strconv.Itoa(cat.Position),
+			colActive:   strconv.FormatBool(Active(cat.IsActive)),
+		}
+		writeTranslations(fields, cat.Translations)
+		if err := write(fields); err != nil {
+			return err
+		}
+	}
+	for _, p := range c.Products {
+		fields := map[string]string{
+			colRecord:      recordProduct,
+			colName:        p.Name,
+			colCategory:    p.Category,
+			colType:        p.Type,
+			colPriceCents:  strconv.FormatInt(p.PriceCents, 10),
+			colActive:      strconv.FormatBool(Active(p.IsActive)),
+			colJeton:       deref(p.Jeton),
+			colStations:    strings.Join(p.Stations, listSeparator),
+			colAllergens:   strings.Join(p.Allergens, listSeparator),
+			colDietaryTags: strings.Join(p.DietaryTags, listSeparator),
+			colImage:       deref(p.Image),
+			colDescription: deref(p.Description),
+		}
+		writeTranslations(fields, p.Translations)
+		if err := write(fields); err != nil {
+			return err
+		}
+		for _, v := range p.Variants {
+			if err := write(map[string]string{
+				colRecord:     recordVariant,
+				colName:       v.Name,
+				colParent:     p.Name,
+				colPriceCents: strconv.FormatInt(v.PriceCents, 10),
+				colPosition:   strconv.Itoa(v.Position),
+				colActive:     strconv.FormatBool(Active(v.IsActive)),
+				colJeton:      deref(v.Jeton),
+				colStations:   strings.Join(v.Stations, listSeparator),
+			}); err != nil {
+				return err
+			}
+		}
+		for _, s := range p.Slots {
+			fields := map[string]string{
+				colRecord:   recordSlot,
+				colName:     s.Name,
+				colParent:   p.Name,
+				colPosition: strconv.Itoa(s.Sequence),
+				colOptions:  strings.Join(s.Options, listSeparator),
+			}
+			writeTranslations(fields, s.Translations)
+			if err := write(fields); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTranslations(fields map[string]string, t i18n.Translations) {
+	fields[colNameFr] = t[i18n.French].Name
+	fields[colNameEn] = t[i18n.English].Name
+	fields[colDescriptionFr] = deref(t[i18n.French].Description)
+	fields[colDescriptionEn] = deref(t[i18n.English].Description)
+}
+
+// ParseCSV reads a catalog written by WriteCSV or edited from one. The
+// header row is required; columns may be in any order and left out. Excel in
+// Swiss locale saves with ';', so the delimiter is detected from the header.
+// Rows that cannot be read are returned as problems.
+func ParseCSV(r io.Reader) (*Catalog, []Problem, error) {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(len(utf8BOM)); bytes.Equal(bom, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	sample, err := br.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, nil, err
+	}
+	if len(bytes.TrimSpace(sample)) == 0 {
+		return nil, nil, ErrEmpty
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = detectDelimiter(sample)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := cols[colRecord]; !ok {
+		return nil, nil, ErrMissingHeader
+	}
+	if _, ok := cols[colName]; !ok {
+		return nil, nil, ErrMissingHeader
+	}
+
+	c := &Catalog{Version: Version}
+	var problems []Problem
+	type child struct {
+		parent  string
+		line    int
+		variant *Variant
+		slot    *Slot
+	}
+	var children []child
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				problems = append(problems, Problem{Line: pe.Line, Record: "row", Message: pe.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if isBlank(rec) {
+			continue
+		}
+		row := csvRow{cols: cols, rec: rec, line: line}
+		switch kind := strings.ToLower(row.get(colRecord)); kind {
+		case recordJeton:
+			c.Jetons = append(c.Jetons, Jeton{Name: row.get(colName), Color: row.get(colColor), Line: line})
+		case recordCategory:
+			cat := Category{Name: row.get(colName), Line: line, Translations: row.translations()}
+			cat.Position = row.int(colPosition)
+			cat.IsActive = row.bool(colActive)
+			c.Categories = append(c.Categories, cat)
+		case recordProduct:
+			p := Product{
+				Name:         row.get(colName),
+				Category:     row.get(colCategory),
+				Type:         strings.ToLower(row.get(colType)),
+				IsActive:     row.bool(colActive),
+				Description:  row.text(colDescription),
+				Image:        row.text(colImage),
+				Jeton:        row.text(colJeton),
+				Allergens:    row.list(colAllergens),
+				DietaryTags:  row.list(colDietaryTags),
+				Translations: row.translations(),
+				Stations:     row.list(colStations),
+				Line:         line,
+			}
+			p.PriceCents = row.int64(colPriceCents)
+			c.Products = append(c.Products, p)
+		case recordVariant:
+			v := Variant{
+				Name:     row.get(colName),
+				Jeton:    row.text(colJeton),
+				IsActive: row.bool(colActive),
+				Stations: row.list(colStations),
+				Line:     line,
+			}
+			v.PriceCents = row.int64(colPriceCents)
+			v.Position = row.int(colPosition)
+			children = append(children, child{parent: row.get(colParent), line: line, variant: &v})
+		case recordSlot:
+			s := Slot{
+				Name:         row.get(colName),
+				Translations: row.translations(),
+				Options:      row.list(colOptions),
+				Line:         line,
+			}
+			s.Sequence = row.int(colPosition)
+			children = append(children, child{parent: row.get(colParent), line: line, slot: &s})
+		default:
+			row.fail(fmt.Sprintf("unknown record %q; use jeton, category, product, variant or slot", kind))
+		}
+		problems = append(problems, row.problems...)
+	}
+
+	// Variants and slots may come before their product.
+	products := make(map[string]int, len(c.Products))
+	for i, p := range c.Products {
+		if _, dup := products[Key(p.Name)]; !dup {
+			products[Key(p.Name)] = i
+		}
+	}
+	for _, ch := range children {
+		i, ok := products[Key(ch.parent)]
+		switch {
+		case !ok && ch.variant != nil:
+			problems = append(problems, Problem{Line: ch.line, Record: ch.variant.Ref(ch.parent), Message: "parent product is not in the file"})
+		case !ok:
+			problems = append(problems, Problem{Line: ch.line, Record: ch.slot.Ref(ch.parent), Message: "parent menu is not in the file"})
+		case ch.variant != nil:
+			c.Products[i].Variants = append(c.Products[i].Variants, *ch.variant)
+		default:
+			c.Products[i].Slots = append(c.Products[i].Slots, *ch.slot)
+		}
+	}
+	if len(c.Jetons)+len(c.Categories)+len(c.Products) == 0 && len(problems) == 0 {
+		return nil, nil, ErrEmpty
+	}
+	return c, problems, nil
+}
+
+// csvRow reads the fields of one row by column name and collects the
+// problems with them.
+type csvRow struct {
+	cols     map[string]int
+	rec      []string
+	line     int
+	problems []Problem
+}
+
+func (r *csvRow) has(col string) bool {
+	_, ok := r.cols[col]
+	return ok
+}
+
+func (r *csvRow) get(col string) string {
+	i, ok := r.cols[col]
+	if !ok || i >= len(r.rec) {
+		return ""
+	}
+	return strings.TrimSpace(r.rec[i])
+}
+
+func (r *csvRow) fail(msg string) {
+	r.problems = append(r.problems, Problem{Line: r.line, Record: r.get(colRecord) + " " + strconv.Quote(r.get(colName)), Message: msg})
+}
+
+func (r *csvRow) optional(col string) *string {
+	if v := r.get(col); v != "" {
+		return &v
+	}
+	return nil
+}
+
+// text reads a product or variant text. Without the column it is nil, which
+// keeps the stored value on import; an empty cell clears it.
+func (r *csvRow) text(col string) *string {
+	if !r.has(col) {
+		return nil
+	}
+	v := r.get(col)
+	return &v
+}
+
+func (r *csvRow) int(col string) int {
+	v := r.get(col)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s must be a whole number", col))
+	}
+	return n
+}
+
+func (r *csvRow) int64(col string) int64 {
+	v := r.get(col)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s must be a whole number", col))
+	}
+	return n
+}
+
+// bool reads true/false, 1/0, yes/no or ja/nein; empty is nil.
+func (r *csvRow) bool(col string) *bool {
+	var b bool
+	switch strings.ToLower(r.get(col)) {
+	case "":
+		return nil
+	case "true", "1", "yes", "ja":
+		b = true
+	case "false", "0", "no", "nein":
+		b = false
+	default:
+		r.fail(fmt.Sprintf("%s must be true or false", col))
+		return nil
+	}
+	return &b
+}
+
+// list splits a list column. A missing column is nil, an empty cell an empty
+// list.
+func (r *csvRow) list(col string) []string {
+	if !r.has(col) {
+		return nil
+	}
+	out := []string{}
+	for _, v := range strings.Split(r.get(col), listSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// translations reads the name_fr … description_en columns; nil when the file
+// has none of them.
+func (r *csvRow) translations() i18n.Translations {
+	if !r.has(colNameFr) && !r.has(colNameEn) && !r.has(colDescriptionFr) && !r.has(colDescriptionEn) {
+		return nil
+	}
+	t := i18n.Translations{}
+	for l, cols := range map[i18n.Locale][2]string{
+		i18n.French:  {colNameFr, colDescriptionFr},
+		i18n.English: {colNameEn, colDescriptionEn},
+	} {
+		name, desc := r.get(cols[0]), r.optional(cols[1])
+		if name != "" || desc != nil {
+			t[l] = i18n.Text{Name: name, Description: desc}
+		}
+	}
+	return t
+}
+
+func detectDelimiter(sample []byte) rune {
+	line, _, _ := bytes.Cut(sample, []byte("\n"))
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -20,8 +20,11 @@ type Config struct {
 	Plunk       PlunkConfig
 	BlobStorage BlobStorageConfig
 	Elvanto     ElvantoConfig
+	Membership  MembershipConfig
 	Sentry      SentryConfig
 	Android     AndroidConfig
+	Station     StationConfig
+	QR          QRConfig
 }
 
 type SentryConfig struct {
@@ -32,13 +35,31 @@ type SentryConfig struct {
 
 type ElvantoConfig struct {
 	APIKey  string
-	GroupID string
+	GroupID string // ELVANTO_GROUP_ID - searched when MEMBERSHIP_GROUPS is empty
+}
+
+// MembershipConfig selects where Club100 members come from and which of them
+// count.
+type MembershipConfig struct {
+	Provider     string        // MEMBERSHIP_PROVIDER - elvanto (default), csv or fake
+	Groups       []string      // MEMBERSHIP_GROUPS - comma-separated; members must be in one of them
+	Tags         []string      // MEMBERSHIP_TAGS - comma-separated; members must carry one of them
+	SyncInterval time.Duration // MEMBERSHIP_SYNC_INTERVAL - how often the member directory is refreshed
 }
 
 type AndroidConfig struct {
 	GitHubRepo string
 }
 
+type StationConfig struct {
+	RedemptionUndoWindow time.Duration // STATION_REDEMPTION_UNDO_WINDOW - how long a station may undo its own redemption
+}
+
+type QRConfig struct {
+	SigningSecret string        // QR_SIGNING_SECRET - HMAC key for QR payloads; unsigned legacy codes only when empty
+	PayloadTTL    time.Duration // QR_PAYLOAD_TTL - lifetime of an order QR code, counted from order creation
+}
+
 type AppConfig struct {
 	AppEnv        string
 	AppPort       string
@@ -145,6 +166,14 @@ func Load() Config {
 			APIKey:  getEnvOptional("ELVANTO_API_KEY"),
 			GroupID: getEnvWithDefault("ELVANTO_GROUP_ID", "fc939b75-cda0-4e37-b728-a61e943d66ad"),
 		},
+		Membership: MembershipConfig{
+			Provider: getEnvWithDefault("MEMBERSHIP_PROVIDER", "elvanto"),
+			Groups:   getEnvAsList("MEMBERSHIP_GROUPS"),
+			Tags:     getEnvAsList("MEMBERSHIP_TAGS"),
+			// ELVANTO_SYNC_INTERVAL is the name from before other providers existed.
+			SyncInterval: getEnvAsDurationWithDefault("MEMBERSHIP_SYNC_INTERVAL",
+				getEnvAsDurationWithDefault("ELVANTO_SYNC_INTERVAL", time.Hour)),
+		},
 		Sentry: SentryConfig{
 			DSN:         getEnvOptional("SENTRY_DSN"),
 			Environment: getEnvOptional("SENTRY_ENVIRONMENT"),
@@ -153,6 +182,13 @@ func Load() Config {
 		Android: AndroidConfig{
 			GitHubRepo: getEnvWithDefault("ANDROID_GITHUB_REPO", "ly-schneider/bless2n-food-system"),
 		},
+		Station: StationConfig{
+			RedemptionUndoWindow: getEnvAsDurationWithDefault("STATION_REDEMPTION_UNDO_WINDOW", 2*time.Minute),
+		},
+		QR: QRConfig{
+			SigningSecret: getEnvOptional("QR_SIGNING_SECRET"),
+			PayloadTTL:    getEnvAsDurationWithDefault("QR_PAYLOAD_TTL", 30*24*time.Hour),
+		},
 	}
 
 	return cfg
@@ -209,6 +245,18 @@ func getTrustedOrigins(key string) []string {
 	return trimmedOrigins
 }
 
+// getEnvAsList splits a comma-separated environment variable, dropping empty
+// entries; nil when unset
+func getEnvAsList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // getEnvOptional gets an environment variable or returns empty string
 func getEnvOptional(key string) string {
 	return os.Getenv(key)
@@ -221,3 +269,14 @@ func getEnvWithDefault(key, def string) string {
 	}
 	return def
 }
+
+// getEnvAsDurationWithDefault parses a Go duration (e.g. "90s", "5m") or returns the default
+func getEnvAsDurationWithDefault(key string, def time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("Warning: invalid duration for %s: %q, using %s", key, value, def)
+	}
+	return def
+}
@@ -0,0 +1,250 @@
+// Package escpos renders receipts for ESC/POS thermal printers. The output is
+// a raw byte stream that devices forward to the printer unchanged, so ticket
+// layouts live here instead of in each app.
+package escpos
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Paper is the roll width. Columns assume the printer's default font A.
+type Paper int
+
+const (
+	Paper58 Paper = 58
+	Paper80 Paper = 80
+)
+
+// ParsePaper accepts "58", "80", "58mm" or "80mm". Empty means 58mm, the
+// printers used at the stands.
+func ParsePaper(s string) (Paper, error) {
+	switch strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "mm") {
+	case "", "58":
+		return Paper58, nil
+	case "80":
+		return Paper80, nil
+	}
+	return 0, fmt.Errorf("unsupported paper width %q", s)
+}
+
+// Columns is the number of normal-width characters per line.
+func (p Paper) Columns() int {
+	if p == Paper80 {
+		return 48
+	}
+	return 32
+}
+
+type Align byte
+
+const (
+	AlignLeft   Align = 0
+	AlignCenter Align = 1
+	AlignRight  Align = 2
+)
+
+const (
+	esc = 0x1B
+	gs  = 0x1D
+	lf  = 0x0A
+
+	// codePagePC858 is PC850 with the euro sign, supported by every printer
+	// model we have.
+	codePagePC858 = 19
+)
+
+// Builder accumulates ESC/POS commands. Text is transcoded to PC858.
+type Builder struct {
+	buf   bytes.Buffer
+	paper Paper
+	width int // current character width multiplier
+}
+
+// NewBuilder starts a receipt: printer reset and code page selection.
+func NewBuilder(p Paper) *Builder {
+	b := &Builder{paper: p, width: 1}
+	b.buf.Write([]byte{esc, '@', esc, 't', codePagePC858})
+	return b
+}
+
+// Columns is the number of characters that fit at the current text size.
+func (b *Builder) Columns() int {
+	return b.paper.Columns() / b.width
+}
+
+func (b *Builder) Align(a Align) *Builder {
+	b.buf.Write([]byte{esc, 'a', byte(a)})
+	return b
+}
+
+func (b *Builder) Bold(on bool) *Builder {
+	var n byte
+	if on {
+		n = 1
+	}
+	b.buf.Write([]byte{esc, 'E', n})
+	return b
+}
+
+// Size sets the character magnification (1..8 in each direction).
+func (b *Builder) Size(width, height int) *Builder {
+	width = min(max(width, 1), 8)
+	height = min(max(height, 1), 8)
+	b.width = width
+	b.buf.Write([]byte{gs, '!', byte((width-1)<<4 | (height - 1))})
+	return b
+}
+
+// Line prints s, wrapped at word boundaries to the current column count.
+func (b *Builder) Line(s string) *Builder {
+	for _, l := range Wrap(s, b.Columns()) {
+		b.buf.Write(encode(l))
+		b.buf.WriteByte(lf)
+	}
+	return b
+}
+
+// Indented prints s wrapped with every line indented by indent spaces.
+func (b *Builder) Indented(indent int, s string) *Builder {
+	pad := strings.Repeat(" ", indent)
+	for _, l := range Wrap(s, b.Columns()-indent) {
+		b.buf.Write(encode(pad + l))
+		b.buf.WriteByte(lf)
+	}
+	return b
+}
+
+// Pair prints left and right on one line, right-aligned; left is wrapped
+// when both do not fit.
+func (b *Builder) Pair(left, right string) *Builder {
+	cols := b.Columns()
+	rw := utf8.RuneCountInString(right)
+	lines := Wrap(left, cols-rw-1)
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	for _, l := range lines[:len(lines)-1] {
+		b.buf.Write(encode(l))
+		b.buf.WriteByte(lf)
+	}
+	last := lines[len(lines)-1]
+	gap := cols - utf8.RuneCountInString(last) - rw
+	b.buf.Write(encode(last + strings.Repeat(" ", max(gap, 1)) + right))
+	b.buf.WriteByte(lf)
+	return b
+}
+
+// Rule prints a full-width line of ch.
+func (b *Builder) Rule(ch rune) *Builder {
+	b.buf.Write(encode(strings.Repeat(string(ch), b.Columns())))
+	b.buf.WriteByte(lf)
+	return b
+}
+
+func (b *Builder) Feed(lines int) *Builder {
+	b.buf.Write([]byte{esc, 'd', byte(lines)})
+	return b
+}
+
+// QR prints data as a model 2 QR code with error correction level M.
+// moduleSize is the dot size of one module (1..16).
+func (b *Builder) QR(data string, moduleSize int) *Builder {
+	moduleSize = min(max(moduleSize, 1), 16)
+	b.buf.Write([]byte{gs, '(', 'k', 4, 0, '1', 'A', '2', 0})
+	b.buf.Write([]byte{gs, '(', 'k', 3, 0, '1', 'C', byte(moduleSize)})
+	b.buf.Write([]byte{gs, '(', 'k', 3, 0, '1', 'E', '1'})
+	n := len(data) + 3
+	b.buf.Write([]byte{gs, '(', 'k', byte(n & 0xFF), byte(n >> 8), '1', 'P', '0'})
+	b.buf.WriteString(data)
+	b.buf.Write([]byte{gs, '(', 'k', 3, 0, '1', 'Q', '0'})
+	return b
+}
+
+// Cut feeds past the cutter and performs a partial cut.
+func (b *Builder) Cut() *Builder {
+	b.buf.Write([]byte{gs, 'V', 66, 0})
+	return b
+}
+
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// Wrap splits s into lines of at most width runes, breaking at spaces where
+// possible and hard-breaking longer words.
+func Wrap(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		cur := ""
+		for _, w := range words {
+			for utf8.RuneCountInString(w) > width {
+				if cur != "" {
+					lines = append(lines, cur)
+					cur = ""
+				}
+				r := []rune(w)
+				lines = append(lines, string(r[:width]))
+				w = string(r[width:])
+			}
+			switch {
+			case cur == "":
+				cur = w
+			case utf8.RuneCountInString(cur)+1+utf8.RuneCountInString(w) <= width:
+				cur += " " + w
+			default:
+				lines = append(lines, cur)
+				cur = w
+			}
+		}
+		if cur != "" {
+			lines = append(lines, cur)
+		}
+	}
+	return lines
+}
+
+// pc858 maps the non-ASCII characters that show up in product names and
+// labels. Everything else outside ASCII prints as '?'.
+var pc858 = map[rune]byte{
+	'Ç': 0x80, 'ü': 0x81, 'é': 0x82, 'â': 0x83, 'ä': 0x84, 'à': 0x85, 'ç': 0x87,
+	'ê': 0x88, 'ë': 0x89, 'è': 0x8A, 'ï': 0x8B, 'î': 0x8C, 'Ä': 0x8E, 'É': 0x90,
+	'ô': 0x93, 'ö': 0x94, 'û': 0x96, 'ù': 0x97, 'Ö': 0x99, 'Ü': 0x9A, '«': 0xAE,
+	'»': 0xAF, 'À': 0xB7, 'È': 0xD4, '€': 0xD5, 'ß': 0xE1, '°': 0xF8, '·': 0xFA,
+}
+
+var asciiFallback = map[rune]byte{
+	'–': '-', '—': '-', '‘': '\'', '’': '\'', '“': '"', '”': '"', '…': '.', '•': '*',
+	'\u00a0': ' ',
+}
+
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7F:
+			out = append(out, byte(r))
+		default:
+			if c, ok := pc858[r]; ok {
+				out = append(out, c)
+			} else if c, ok := asciiFallback[r]; ok {
+				out = append(out, c)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/tz"
+)
+
+// TicketLine is one ordered product; bundles list their chosen components.
+type TicketLine struct {
+	Quantity   int
+	Title      string
+	PriceCents int64
+	Components []TicketComponent
+}
+
+type TicketComponent struct {
+	Quantity int
+	Slot     string
+	Title    string
+}
+
+// OrderTicket is the customer/pickup ticket printed at the POS.
+type OrderTicket struct {
+	Header     string
+	OrderID    string
+	PickupCode string
+	Origin     string
+	CreatedAt  time.Time
+	Lines      []TicketLine
+	TotalCents int64
+	Payments   []string
+	Notes      []string
+	QRPayload  string
+}
+
+// StationSlip is the kitchen slip for the items one station prepares.
+type StationSlip struct {
+	StationName string
+	OrderID     string
+	PickupCode  string
+	CreatedAt   time.Time
+	Lines       []TicketLine
+	Notes       []string
+	QRPayload   string
+}
+
+// RenderOrderTicket renders t for the given paper width.
+func RenderOrderTicket(t OrderTicket, p Paper) []byte {
+	b := NewBuilder(p)
+
+	b.Align(AlignCenter)
+	if t.Header != "" {
+		b.Bold(true).Line(t.Header).Bold(false)
+	}
+	b.Size(2, 2).Bold(true).Line(t.PickupCode).Bold(false).Size(1, 1)
+	b.Line(formatTicketTime(t.CreatedAt))
+	if t.Origin != "" {
+		b.Line(t.Origin)
+	}
+	b.Align(AlignLeft).Rule('-')
+
+	for _, l := range t.Lines {
+		b.Bold(true).Pair(fmt.Sprintf("%dx %s", l.Quantity, l.Title), formatCHF(l.PriceCents*int64(l.Quantity))).Bold(false)
+		writeComponents(b, l.Components)
+	}
+
+	b.Rule('-')
+	b.Bold(true).Pair("Total", "CHF "+formatCHF(t.TotalCents)).Bold(false)
+	for _, pay := range t.Payments {
+		b.Line(pay)
+	}
+	writeNotes(b, t.Notes)
+
+	if t.QRPayload != "" {
+		b.Feed(1).Align(AlignCenter).QR(t.QRPayload, qrModuleSize(p))
+		b.Line("Bestellung " + t.OrderID)
+		b.Align(AlignLeft)
+	}
+	return b.Feed(3).Cut().Bytes()
+}
+
+// RenderStationSlip renders s for the given paper width. Prices are left out;
+// the kitchen only needs what to prepare.
+func RenderStationSlip(s StationSlip, p Paper) []byte {
+	b := NewBuilder(p)
+
+	b.Align(AlignCenter)
+	if s.StationName != "" {
+		b.Bold(true).Line(s.StationName).Bold(false)
+	}
+	b.Size(2, 2).Bold(true).Line(s.PickupCode).Bold(false).Size(1, 1)
+	b.Line(formatTicketTime(s.CreatedAt))
+	b.Align(AlignLeft).Rule('-')
+
+	for _, l := range s.Lines {
+		b.Size(1, 2).Bold(true).Line(fmt.Sprintf("%dx %s", l.Quantity, l.Title)).Bold(false).Size(1, 1)
+		writeComponents(b, l.Components)
+	}
+	writeNotes(b, s.Notes)
+
+	if s.QRPayload != "" {
+		b.Rule('-').Align(AlignCenter).QR(s.QRPayload, qrModuleSize(p)).Align(AlignLeft)
+	}
+	return b.Feed(3).Cut().Bytes()
+}
+
+func writeComponents(b *Builder, components []TicketComponent) {
+	for _, c := range components {
+		text := fmt.Sprintf("%dx %s", c.Quantity, c.Title)
+		if c.Slot != "" {
+			text = c.Slot + ": " + text
+		}
+		b.Indented(3, text)
+	}
+}
+
+func writeNotes(b *Builder, notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+	b.Rule('-').Bold(true).Line("Hinweise").Bold(false)
+	for _, n := range notes {
+		b.Indented(2, "* "+strings.TrimSpace(n))
+	}
+}
+
+func qrModuleSize(p Paper) int {
+	if p == Paper80 {
+		return 8
+	}
+	return 6
+}
+
+// formatTicketTime prints t in Swiss local time.
+func formatTicketTime(t time.Time) string {
+	return t.In(tz.Zurich()).Format("02.01.2006 15:04")
+}
+
+func formatCHF(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
@@ -0,0 +1,132 @@
+package escpos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Run `go test ./internal/escpos -update` after an intentional layout change
+// and review the golden diff.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func sampleTicket() OrderTicket {
+	return OrderTicket{
+		Header:     "BlessThun Food",
+		OrderID:    "HS79U1yH7Zd3",
+		PickupCode: "#7Zd3",
+		Origin:     "Kasse",
+		CreatedAt:  time.Date(2026, 6, 20, 16, 5, 0, 0, time.UTC),
+		Lines: []TicketLine{
+			{
+				Quantity:   1,
+				Title:      "Burger Menü",
+				PriceCents: 1800,
+				Components: []TicketComponent{
+					{Quantity: 1, Slot: "Burger", Title: "Cheeseburger"},
+					{Quantity: 1, Slot: "Beilage", Title: "Pommes"},
+					{Quantity: 1, Slot: "Getränk", Title: "Eistee"},
+				},
+			},
+			{Quantity: 2, Title: "Bratwurst mit Brot und extra viel Senf", PriceCents: 650},
+		},
+		TotalCents: 3100,
+		Payments:   []string{"Bar CHF 31.00"},
+		Notes:      []string{"Pommes: 1/1 bereits ausgegeben"},
+		QRPayload:  "BFS1.O.HS79U1yH7Zd3.1767225600.q5Jc0uJ3n0m9yS2dH8Zp1A",
+	}
+}
+
+func sampleSlip() StationSlip {
+	return StationSlip{
+		StationName: "Grill",
+		OrderID:     "HS79U1yH7Zd3",
+		PickupCode:  "#7Zd3",
+		CreatedAt:   time.Date(2026, 6, 20, 16, 5, 0, 0, time.UTC),
+		Lines: []TicketLine{
+			{Quantity: 1, Title: "Burger Menü", Components: []TicketComponent{
+				{Quantity: 1, Slot: "Burger", Title: "Cheeseburger"},
+			}},
+			{Quantity: 2, Title: "Bratwurst"},
+		},
+		QRPayload: "BFS1.O.HS79U1yH7Zd3.1767225600.q5Jc0uJ3n0m9yS2dH8Zp1A",
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		out  []byte
+	}{
+		{"order_ticket_58", RenderOrderTicket(sampleTicket(), Paper58)},
+		{"order_ticket_80", RenderOrderTicket(sampleTicket(), Paper80)},
+		{"station_slip_58", RenderStationSlip(sampleSlip(), Paper58)},
+		{"station_slip_80", RenderStationSlip(sampleSlip(), Paper80)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Golden files are hex dumps so layout changes show up as
+			// reviewable diffs.
+			got := []byte(hex.Dump(tc.out))
+			path := filepath.Join("testdata", tc.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s; run with -update and review the diff\n got:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestRenderStartsAndEndsCleanly(t *testing.T) {
+	out := RenderOrderTicket(sampleTicket(), Paper58)
+	if !bytes.HasPrefix(out, []byte{esc, '@', esc, 't', codePagePC858}) {
+		t.Fatalf("missing init sequence: % x", out[:5])
+	}
+	if !bytes.HasSuffix(out, []byte{gs, 'V', 66, 0}) {
+		t.Fatalf("missing cut: % x", out[len(out)-4:])
+	}
+	if !bytes.Contains(out, []byte("Burger Men\x81")) {
+		t.Fatal("umlaut not transcoded to PC858")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := Wrap("Bratwurst mit Brot und Senf", 10)
+	want := []string{"Bratwurst", "mit Brot", "und Senf"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+
+	if got := Wrap("Supercalifragilistic", 8); len(got) != 3 || got[0] != "Supercal" {
+		t.Fatalf("long word not hard-broken: %q", got)
+	}
+}
+
+func TestParsePaper(t *testing.T) {
+	for in, want := range map[string]Paper{"": Paper58, "58": Paper58, "58mm": Paper58, "80": Paper80, "80MM": Paper80} {
+		got, err := ParsePaper(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePaper(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParsePaper("112"); err == nil {
+		t.Error("expected error for 112mm")
+	}
+}
@@ -28,6 +28,8 @@ func NewRouter(
 	sseRouter.Use(securityMw.CORS)
 	sseRouter.Use(systemMw.RequireEnabled)
 	sseRouter.Get("/v1/inventory/stream", apiHandlers.StreamInventory)
+	sseRouter.With(deviceAuthMw.RequireDevice(auth.DeviceTypeStation)).
+		Get("/v1/stations/queue/stream", apiHandlers.StreamStationQueue)
 
 	// ── Main router with full middleware stack ────────────────────────
 	r := chi.NewRouter()
@@ -100,6 +102,10 @@ func NewRouter(
 			station.Get("/stations/me", wrapper.GetCurrentStation)
 			station.Post("/stations/redeem", wrapper.RedeemAtStation)
 			station.Post("/stations/redeem-campaign", apiHandlers.RedeemCampaignAtStation)
+			station.Get("/stations/queue", apiHandlers.GetStationQueue)
+			station.Post("/stations/redemptions/{batchId}/undo", apiHandlers.UndoStationRedemption)
+			station.Get("/stations/orders/{orderId}/ticket", apiHandlers.GetOrderTicket)
+			station.Get("/stations/orders/{orderId}/slip", apiHandlers.GetStationSlip)
 		})
 
 		v1.Group(func(pos chi.Router) {
@@ -108,8 +114,11 @@ func NewRouter(
 			pos.Get("/pos/me", wrapper.GetCurrentPos)
 			pos.Get("/club100/people", wrapper.ListClub100People)
 			pos.Get("/club100/remaining/{elvantoPersonId}", wrapper.GetClub100Remaining)
+			pos.Post("/club100/cards/lookup", wrapper.LookupClub100Card)
 			pos.Patch("/pos/products/{productId}/inventory", wrapper.AdjustProductInventory)
+			pos.Patch("/pos/products/{productId}/variants/{variantId}/inventory", apiHandlers.AdjustProductVariantInventory)
 			pos.Patch("/pos/products/{productId}/active", apiHandlers.SetProductActive)
+			pos.Get("/pos/orders/{orderId}/ticket", apiHandlers.GetOrderTicket)
 		})
 
 		// ── Orders (anonymous allowed, blocked when disabled) ────
@@ -143,10 +152,18 @@ func NewRouter(
 			admin.Get("/products/{productId}/inventory", wrapper.GetProductInventory)
 			admin.Get("/products/{productId}/inventory/history", wrapper.GetProductInventoryHistory)
 			admin.Patch("/products/{productId}/inventory", wrapper.AdjustProductInventory)
+			admin.Post("/products/{productId}/variants", apiHandlers.CreateProductVariant)
+			admin.Patch("/products/{productId}/variants/{variantId}", apiHandlers.UpdateProductVariant)
+			admin.Delete("/products/{productId}/variants/{variantId}", apiHandlers.DeleteProductVariant)
+			admin.Patch("/products/{productId}/variants/{variantId}/inventory", apiHandlers.AdjustProductVariantInventory)
+			admin.Get("/products/{productId}/availability", apiHandlers.GetProductAvailability)
+			admin.Put("/products/{productId}/availability", apiHandlers.SetProductAvailability)
 
 			admin.Post("/categories", wrapper.CreateCategory)
 			admin.Patch("/categories/{categoryId}", wrapper.UpdateCategory)
 			admin.Delete("/categories/{categoryId}", wrapper.DeleteCategory)
+			admin.Get("/categories/{categoryId}/availability", apiHandlers.GetCategoryAvailability)
+			admin.Put("/categories/{categoryId}/availability", apiHandlers.SetCategoryAvailability)
 
 			admin.Post("/menus", wrapper.CreateMenu)
 			admin.Patch("/menus/{menuId}", wrapper.UpdateMenu)
@@ -158,6 +175,9 @@ func NewRouter(
 			admin.Post("/menus/{menuId}/slots/{slotId}/options", wrapper.AddSlotOption)
 			admin.Delete("/menus/{menuId}/slots/{slotId}/options/{optionProductId}", wrapper.RemoveSlotOption)
 
+			admin.Get("/catalog/export", apiHandlers.ExportCatalog)
+			admin.Post("/catalog/import", apiHandlers.ImportCatalog)
+
 			admin.Patch("/orders/{orderId}", wrapper.UpdateOrderStatus)
 
 			admin.Get("/stations", wrapper.ListStations)
@@ -170,9 +190,29 @@ func NewRouter(
 			admin.Delete("/stations/{stationId}", wrapper.RevokeStation)
 
 			admin.Get("/pos/devices", wrapper.ListPosDevices)
+			admin.Put("/pos/devices/{deviceId}/config", wrapper.UpdatePosDeviceConfig)
 
 			admin.Get("/settings", wrapper.GetSettings)
 			admin.Patch("/settings", wrapper.UpdateSettings)
+			admin.Get("/settings/opening-hours", apiHandlers.GetOpeningHours)
+			admin.Put("/settings/opening-hours", apiHandlers.PutOpeningHours)
+			admin.Put("/settings/open-until", apiHandlers.PutSystemOpenUntil)
+			admin.Get("/settings/history", apiHandlers.GetSettingsHistory)
+			admin.Post("/settings/history/{version}/rollback", apiHandlers.RollbackSettings)
+
+			admin.Get("/club100/sync", wrapper.GetClub100SyncStatus)
+			admin.Post("/club100/sync", wrapper.SyncClub100Members)
+			admin.Post("/club100/members/import", apiHandlers.ImportClub100Members)
+			admin.Get("/club100/periods", wrapper.ListClub100Periods)
+			admin.Post("/club100/periods", wrapper.CreateClub100Period)
+			admin.Patch("/club100/periods/{periodId}", wrapper.UpdateClub100Period)
+			admin.Delete("/club100/periods/{periodId}", wrapper.DeleteClub100Period)
+			admin.Get("/club100/periods/{periodId}/report", wrapper.GetClub100PeriodReport)
+			admin.Get("/club100/cards", wrapper.ListClub100Cards)
+			admin.Post("/club100/cards", wrapper.IssueClub100Cards)
+			admin.Get("/club100/cards/print.pdf", apiHandlers.PrintClub100Cards)
+			admin.Post("/club100/cards/{elvantoPersonId}", wrapper.IssueClub100Card)
+			admin.Delete("/club100/cards/{elvantoPersonId}", wrapper.RevokeClub100Card)
 
 			admin.Get("/jetons", wrapper.ListJetons)
 			admin.Post("/jetons", wrapper.CreateJeton)
@@ -194,7 +234,25 @@ func NewRouter(
 			admin.Get("/invites/{inviteId}", wrapper.GetInvite)
 			admin.Delete("/invites/{inviteId}", wrapper.DeleteInvite)
 
-			admin.Get("/events", wrapper.ListEvents)
+			admin.Get("/events", apiHandlers.ListEvents)
+			admin.Post("/events", apiHandlers.CreateEvent)
+			admin.Get("/events/days", wrapper.ListEventDays)
+			admin.Get("/events/{eventId}", apiHandlers.GetEvent)
+			admin.Patch("/events/{eventId}", apiHandlers.UpdateEvent)
+			admin.Delete("/events/{eventId}", apiHandlers.DeleteEvent)
+			admin.Get("/events/{eventId}/products", apiHandlers.GetEventProducts)
+			admin.Put("/events/{eventId}/products", apiHandlers.PutEventProducts)
+			admin.Get("/price-rules", apiHandlers.ListPriceRules)
+			admin.Post("/price-rules", apiHandlers.CreatePriceRule)
+			admin.Get("/price-rules/report", apiHandlers.GetPriceRuleReport)
+			admin.Patch("/price-rules/{priceRuleId}", apiHandlers.UpdatePriceRule)
+			admin.Delete("/price-rules/{priceRuleId}", apiHandlers.DeletePriceRule)
+
+			admin.Get("/analytics/stations", apiHandlers.GetStationAnalytics)
+			admin.Get("/analytics/products", apiHandlers.GetProductAnalytics)
+
+			admin.Get("/redemptions/undos", apiHandlers.ListRedemptionUndos)
+			admin.Post("/redemptions/{batchId}/undo", apiHandlers.UndoRedemption)
 
 			admin.Post("/staff-meals", apiHandlers.CreateVolunteerCampaign)
 			admin.Get("/staff-meals", apiHandlers.ListVolunteerCampaigns)
@@ -203,12 +261,25 @@ func NewRouter(
 			admin.Post("/staff-meals/{campaignId}/end", apiHandlers.EndVolunteerCampaign)
 			admin.Post("/staff-meals/{campaignId}/rotate-token", apiHandlers.RotateVolunteerCampaignToken)
 			admin.Get("/staff-meals/{campaignId}/print.pdf", apiHandlers.PrintStaffMealSlips)
+			admin.Post("/staff-meals/{campaignId}/logo", apiHandlers.UploadStaffMealLogo)
+			admin.Delete("/staff-meals/{campaignId}/logo", apiHandlers.DeleteStaffMealLogo)
+			admin.Get("/staff-meals/{campaignId}/slips", apiHandlers.GetStaffMealSlipSummary)
+			admin.Post("/staff-meals/{campaignId}/slips", apiHandlers.IssueStaffMealSlips)
+			admin.Get("/staff-meals/{campaignId}/slips/print.pdf", apiHandlers.PrintNumberedStaffMealSlips)
+			admin.Get("/staff-meals/{campaignId}/schedule", apiHandlers.GetVolunteerSchedule)
+			admin.Put("/staff-meals/{campaignId}/schedule", apiHandlers.PutVolunteerSchedule)
+			admin.Get("/staff-meals/{campaignId}/choices", apiHandlers.GetVolunteerChoiceGroups)
+			admin.Put("/staff-meals/{campaignId}/choices", apiHandlers.PutVolunteerChoiceGroups)
+			admin.Post("/staff-meals/{campaignId}/volunteers/import", apiHandlers.ImportVolunteerRoster)
+			admin.Get("/staff-meals/{campaignId}/volunteers", apiHandlers.ListVolunteerTokens)
+			admin.Get("/staff-meals/{campaignId}/volunteers/print.pdf", apiHandlers.PrintPersonalStaffMealSlips)
+			admin.Delete("/staff-meals/{campaignId}/volunteers/{volunteerId}", apiHandlers.DeleteVolunteerToken)
 		})
 	})
 
 	// Compose: SSE bypasses main middleware, everything else uses full stack
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		if req.URL.Path == "/v1/inventory/stream" {
+		if req.URL.Path == "/v1/inventory/stream" || req.URL.Path == "/v1/stations/queue/stream" {
 			sseRouter.ServeHTTP(w, req)
 			return
 		}
@@ -0,0 +1,197 @@
+// Package i18n selects the language of customer-facing texts: catalog names
+// and descriptions, emails and printouts. German is the language the catalog
+// is entered in and the fallback for everything not translated.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/tz"
+)
+
+type Locale string
+
+const (
+	German  Locale = "de"
+	French  Locale = "fr"
+	English Locale = "en"
+
+	Default = German
+)
+
+// Supported locales, the default first.
+var Supported = []Locale{German, French, English}
+
+var ErrUnsupportedLocale = errors.New("locale_unsupported")
+
+// ErrInvalidTranslations is wrapped by every error of Translations.Normalize.
+var ErrInvalidTranslations = errors.New("translations_invalid")
+
+// Parse accepts a language tag such as "fr", "fr-CH" or "EN" and returns the
+// supported locale of its primary language.
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, l := range Supported {
+		if string(l) == tag {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the supported locale the Accept-Language header prefers
+// most, falling back to German.
+func Negotiate(acceptLanguage string) Locale {
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		l, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{l, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// FromRequest is the locale a request asks for: the lang query parameter,
+// then Accept-Language.
+func FromRequest(r *http.Request) Locale {
+	if l, ok := Parse(r.URL.Query().Get("lang")); ok {
+		return l
+	}
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// Or returns the locale s names, or the default when it names none; for
+// locales stored with orders and invites.
+func Or(s *string) Locale {
+	if s == nil {
+		return Default
+	}
+	if l, ok := Parse(*s); ok {
+		return l
+	}
+	return Default
+}
+
+type ctxKey struct{}
+
+func WithLocale(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the locale stored by WithLocale, or the default.
+func FromContext(ctx context.Context) Locale {
+	if l, ok := ctx.Value(ctxKey{}).(Locale); ok {
+		return l
+	}
+	return Default
+}
+
+// Text is the translation of a catalog name and description.
+type Text struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Translations of a catalog entry by locale. German is the entry itself and
+// never stored here.
+type Translations map[Locale]Text
+
+// Name is the name in l, or fallback when it is not translated.
+func (t Translations) Name(l Locale, fallback string) string {
+	if tx, ok := t[l]; ok && tx.Name != "" {
+		return tx.Name
+	}
+	return fallback
+}
+
+// Description is the description in l, or fallback when it is not
+// translated.
+func (t Translations) Description(l Locale, fallback *string) *string {
+	if tx, ok := t[l]; ok && tx.Description != nil {
+		return tx.Description
+	}
+	return fallback
+}
+
+// Normalize trims the texts and drops empty ones and the German entry.
+// Unsupported locales and names longer than maxName are an
+// ErrInvalidTranslations.
+func (t Translations) Normalize(maxName int) (Translations, error) {
+	out := make(Translations, len(t))
+	for l, tx := range t {
+		parsed, ok := Parse(string(l))
+		if !ok {
+			return nil, fmt.Errorf("%w: %w: %q", ErrInvalidTranslations, ErrUnsupportedLocale, l)
+		}
+		if parsed == German {
+			continue
+		}
+		name := strings.TrimSpace(tx.Name)
+		if len([]rune(name)) > maxName {
+			return nil, fmt.Errorf("%w: %s name longer than %d characters", ErrInvalidTranslations, parsed, maxName)
+		}
+		var desc *string
+		if tx.Description != nil {
+			if d := strings.TrimSpace(*tx.Description); d != "" {
+				desc = &d
+			}
+		}
+		if name == "" && desc == nil {
+			continue
+		}
+		out[parsed] = Text{Name: name, Description: desc}
+	}
+	return out, nil
+}
+
+var months = map[Locale][12]string{
+	German:  {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	French:  {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	English: {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+}
+
+// FormatDateTime formats t in Swiss local time, e.g. "2. Januar 2025, 14:30",
+// "2 janvier 2025, 14:30" or "2 January 2025, 14:30".
+func FormatDateTime(l Locale, t time.Time) string {
+	t = t.In(tz.Zurich())
+	names, ok := months[l]
+	if !ok {
+		names, l = months[Default], Default
+	}
+	day := strconv.Itoa(t.Day())
+	if l == German {
+		day += "."
+	}
+	return fmt.Sprintf("%s %s %d, %02d:%02d", day, names[t.Month()-1], t.Year(), t.Hour(), t.Minute())
+}
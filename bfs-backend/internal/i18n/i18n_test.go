@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]Locale{
+		"":                               German,
+		"fr-CH":                          French,
+		"en-US,en;q=0.9,de;q=0.8":        English,
+		"it-CH, fr;q=0.5, en;q=0.7":      English,
+		"de-CH;q=0.4, fr-CH":             French,
+		"es, pt":                         German,
+		"fr;q=0, en;q=0.2":               English,
+		"fr;q=abc, en":                   English,
+		"FR-ch ; q=0.8 , de-ch ; q=0.9 ": German,
+	}
+	for header, want := range cases {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestFromRequestPrefersQuery(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/products?lang=en", nil)
+	r.Header.Set("Accept-Language", "fr-CH")
+	if got := FromRequest(r); got != English {
+		t.Fatalf("got %q, want en", got)
+	}
+	r = httptest.NewRequest("GET", "/v1/products?lang=xx", nil)
+	r.Header.Set("Accept-Language", "fr-CH")
+	if got := FromRequest(r); got != French {
+		t.Fatalf("got %q, want fr", got)
+	}
+}
+
+func TestTranslationsFallback(t *testing.T) {
+	desc := "Frites maison"
+	tr := Translations{French: {Name: "Frites", Description: &desc}, English: {Name: "Fries"}}
+	base := "Hausgemacht"
+
+	if got := tr.Name(French, "Pommes"); got != "Frites" {
+		t.Errorf("fr name = %q", got)
+	}
+	if got := tr.Name(German, "Pommes"); got != "Pommes" {
+		t.Errorf("de name = %q", got)
+	}
+	if got := tr.Description(English, &base); got != &base {
+		t.Errorf("en description should fall back to German")
+	}
+	var none Translations
+	if got := none.Name(French, "Pommes"); got != "Pommes" {
+		t.Errorf("nil translations name = %q", got)
+	}
+}
+
+func TestTranslationsNormalize(t *testing.T) {
+	blank := "  "
+	out, err := Translations{"FR": {Name: " Frites "}, "de": {Name: "Pommes"}, "en": {Name: "", Description: &blank}}.Normalize(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[French].Name != "Frites" {
+		t.Fatalf("got %#v", out)
+	}
+	if _, err := (Translations{"it": {Name: "Patatine"}}).Normalize(20); !errors.Is(err, ErrInvalidTranslations) || !errors.Is(err, ErrUnsupportedLocale) {
+		t.Fatalf("expected unsupported locale error, got %v", err)
+	}
+	if _, err := (Translations{"en": {Name: "A very long name for fries"}}).Normalize(20); !errors.Is(err, ErrInvalidTranslations) {
+		t.Fatalf("expected length error, got %v", err)
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	at := time.Date(2026, 7, 2, 12, 30, 0, 0, time.UTC) // 14:30 in Zurich
+	cases := map[Locale]string{
+		German:  "2. Juli 2026, 14:30",
+		French:  "2 juillet 2026, 14:30",
+		English: "2 July 2026, 14:30",
+	}
+	for l, want := range cases {
+		if got := FormatDateTime(l, at); got != want {
+			t.Errorf("%s: got %q, want %q", l, got, want)
+		}
+	}
+}
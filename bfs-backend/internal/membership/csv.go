@@ -0,0 +1,180 @@
+package membership
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidCSV wraps every problem ParseCSV reports.
+var ErrInvalidCSV = errors.New("invalid_member_csv")
+
+// MaxCSVMembers bounds one uploaded list.
+const MaxCSVMembers = 10000
+
+// csvListSep separates several groups or tags within one cell. It differs
+// from both field separators so lists survive either export format.
+const csvListSep = "|"
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+var csvHeaderAliases = map[string]string{
+	"id": "id", "person_id": "id", "nr": "id",
+	"first_name": "first_name", "firstname": "first_name", "vorname": "first_name",
+	"last_name": "last_name", "lastname": "last_name", "nachname": "last_name",
+	"preferred_name": "preferred_name", "rufname": "preferred_name",
+	"groups": "groups", "gruppen": "groups",
+	"tags": "tags",
+}
+
+// ParseCSV reads a member list exported from a spreadsheet. The first row is
+// a header naming the columns in any order: id, first_name and last_name are
+// required, preferred_name, groups and tags are optional (German names such
+// as vorname or gruppen work too). Groups and tags hold several values
+// separated by "|". Excel in Swiss locale separates fields with ';', Google
+// Sheets with ',', so the delimiter is detected from the header. Unlike a
+// volunteer roster a member list is all or nothing: any bad row fails it.
+func ParseCSV(r io.Reader) ([]Member, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidCSV)
+	}
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = detectDelimiter(data)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		if name, ok := csvHeaderAliases[strings.ToLower(strings.TrimSpace(h))]; ok {
+			col[name] = i
+		}
+	}
+	for _, required := range []string{"id", "first_name", "last_name"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, required)
+		}
+	}
+
+	var members []Member
+	seen := make(map[string]int)
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if isBlank(rec) {
+			continue
+		}
+		if len(members) >= MaxCSVMembers {
+			return nil, fmt.Errorf("%w: more than %d members", ErrInvalidCSV, MaxCSVMembers)
+		}
+		line, _ := cr.FieldPos(0)
+		cell := func(name string) string {
+			i, ok := col[name]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[i])
+		}
+
+		m := Member{
+			ID:            cell("id"),
+			FirstName:     cell("first_name"),
+			LastName:      cell("last_name"),
+			PreferredName: cell("preferred_name"),
+			Groups:        splitList(cell("groups")),
+			Tags:          splitList(cell("tags")),
+		}
+		if m.ID == "" {
+			return nil, fmt.Errorf("%w: line %d has no id", ErrInvalidCSV, line)
+		}
+		if m.FirstName == "" && m.LastName == "" {
+			return nil, fmt.Errorf("%w: line %d has no name", ErrInvalidCSV, line)
+		}
+		if prev, dup := seen[m.ID]; dup {
+			return nil, fmt.Errorf("%w: id %q on line %d repeats line %d", ErrInvalidCSV, m.ID, line, prev)
+		}
+		seen[m.ID] = line
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func detectDelimiter(data []byte) rune {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	for _, v := range strings.Split(s, csvListSep) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// CSVSource returns the most recently uploaded member list, or
+// ErrNotConfigured when none has been uploaded yet.
+type CSVSource func(ctx context.Context) ([]byte, error)
+
+type csvProvider struct {
+	source CSVSource
+}
+
+// NewCSV returns a provider serving the uploaded member list. The list only
+// changes on upload, so there is nothing to poll.
+func NewCSV(source CSVSource) Provider {
+	return &csvProvider{source: source}
+}
+
+func (p *csvProvider) Name() string { return ProviderCSV }
+
+// IsConfigured is always true: uploading a list is how the CSV provider is
+// set up.
+func (p *csvProvider) IsConfigured() bool { return true }
+
+func (p *csvProvider) ListMembers(ctx context.Context, f Filter) ([]Member, error) {
+	data, err := p.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members, err := ParseCSV(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return f.Apply(members), nil
+}
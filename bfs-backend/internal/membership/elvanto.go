@@ -0,0 +1,192 @@
+package membership
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// elvantoPageSize is the largest page the people search accepts.
+const elvantoPageSize = 1000
+
+// elvantoMaxPages guards against a paging loop if the API keeps reporting
+// more people than it returns.
+const elvantoMaxPages = 50
+
+// ElvantoConfig configures the Elvanto provider. DefaultGroupID is searched
+// when the filter names no groups.
+type ElvantoConfig struct {
+	APIKey         string
+	DefaultGroupID string
+}
+
+type elvantoProvider struct {
+	cfg    ElvantoConfig
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewElvanto returns a provider reading the people search of the Elvanto
+// API. Groups are Elvanto group IDs and are searched server-side; tags are
+// matched against the names of a person's Elvanto demographics.
+func NewElvanto(cfg ElvantoConfig, logger *zap.Logger) Provider {
+	return &elvantoProvider{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+func (p *elvantoProvider) Name() string { return ProviderElvanto }
+
+func (p *elvantoProvider) IsConfigured() bool {
+	return p.cfg.APIKey != ""
+}
+
+func (p *elvantoProvider) ListMembers(ctx context.Context, f Filter) ([]Member, error) {
+	if !p.IsConfigured() {
+		p.logger.Warn("elvanto not configured")
+		return nil, ErrNotConfigured
+	}
+
+	groups := f.Groups
+	if len(groups) == 0 && p.cfg.DefaultGroupID != "" {
+		groups = []string{p.cfg.DefaultGroupID}
+	}
+
+	// Someone in two of the groups is returned by both searches; merge them.
+	byID := make(map[string]int)
+	var members []Member
+	for _, groupID := range groups {
+		people, err := p.listGroup(ctx, groupID, len(f.Tags) > 0)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", groupID, err)
+		}
+		for _, person := range people {
+			if i, ok := byID[person.ID]; ok {
+				members[i].Groups = append(members[i].Groups, groupID)
+				continue
+			}
+			byID[person.ID] = len(members)
+			members = append(members, Member{
+				ID:            person.ID,
+				FirstName:     person.FirstName,
+				LastName:      person.LastName,
+				PreferredName: person.PreferredName,
+				Groups:        []string{groupID},
+				Tags:          person.demographicNames(),
+			})
+		}
+	}
+	return Filter{Tags: f.Tags}.Apply(members), nil
+}
+
+func (p *elvantoProvider) listGroup(ctx context.Context, groupID string, withDemographics bool) ([]elvantoPerson, error) {
+	p.logger.Info("listing elvanto group members", zap.String("groupId", groupID))
+
+	var people []elvantoPerson
+	for page := 1; page <= elvantoMaxPages; page++ {
+		result, err := p.searchPage(ctx, groupID, page, withDemographics)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+		people = append(people, result.People.Person...)
+		if len(result.People.Person) < elvantoPageSize || len(people) >= result.People.Total {
+			p.logger.Info("elvanto returned group members", zap.Int("count", len(people)), zap.Int("pages", page))
+			return people, nil
+		}
+	}
+	return nil, fmt.Errorf("elvanto group has more than %d pages", elvantoMaxPages)
+}
+
+func (p *elvantoProvider) searchPage(ctx context.Context, groupID string, page int, withDemographics bool) (*elvantoResponse, error) {
+	reqBody := map[string]any{
+		"page":      page,
+		"page_size": elvantoPageSize,
+		"search": map[string]any{
+			"groups": groupID,
+		},
+	}
+	if withDemographics {
+		reqBody["fields"] = []string{"demographics"}
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.elvanto.com/v1/people/search.json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.SetBasicAuth(p.cfg.APIKey, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		p.logger.Error("elvanto request failed", zap.Error(err))
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		p.logger.Error("elvanto API error", zap.Int("status", resp.StatusCode), zap.String("body", string(body)))
+		return nil, fmt.Errorf("elvanto API returned %d", resp.StatusCode)
+	}
+
+	var result elvantoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		p.logger.Error("elvanto decode error", zap.Error(err))
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if result.Status != "ok" {
+		p.logger.Error("elvanto status not ok", zap.String("status", result.Status))
+		return nil, fmt.Errorf("elvanto error: %s", result.Status)
+	}
+	return &result, nil
+}
+
+type elvantoPerson struct {
+	ID            string `json:"id"`
+	FirstName     string `json:"firstname"`
+	LastName      string `json:"lastname"`
+	PreferredName string `json:"preferred_name"`
+	// Demographics is an object when the person has any and an empty
+	// string otherwise.
+	Demographics json.RawMessage `json:"demographics"`
+}
+
+func (p elvantoPerson) demographicNames() []string {
+	var d struct {
+		Demographic []struct {
+			Name string `json:"name"`
+		} `json:"demographic"`
+	}
+	if len(p.Demographics) == 0 || json.Unmarshal(p.Demographics, &d) != nil {
+		return nil
+	}
+	names := make([]string, 0, len(d.Demographic))
+	for _, x := range d.Demographic {
+		names = append(names, x.Name)
+	}
+	return names
+}
+
+type elvantoResponse struct {
+	Status string `json:"status"`
+	People struct {
+		Total  int             `json:"total"`
+		Person []elvantoPerson `json:"person"`
+	} `json:"people"`
+}
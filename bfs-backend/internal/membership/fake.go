@@ -0,0 +1,65 @@
+package membership
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake is an in-memory provider for tests and for running locally without
+// access to a real directory.
+type Fake struct {
+	mu         sync.Mutex
+	members    []Member
+	err        error
+	configured bool
+}
+
+// NewFake returns a configured fake serving members.
+func NewFake(members ...Member) *Fake {
+	return &Fake{members: members, configured: true}
+}
+
+// NewUnconfiguredFake returns a fake that reports it is not set up.
+func NewUnconfiguredFake() *Fake {
+	return &Fake{}
+}
+
+// SampleMembers is what MEMBERSHIP_PROVIDER=fake serves.
+func SampleMembers() []Member {
+	return []Member{
+		{ID: "fake-1", FirstName: "Anna", LastName: "Muster", Groups: []string{"club100"}},
+		{ID: "fake-2", FirstName: "Johannes", LastName: "Müller", PreferredName: "Hans", Groups: []string{"club100"}},
+		{ID: "fake-3", FirstName: "Lea", LastName: "Keller", Groups: []string{"club100"}, Tags: []string{"vorstand"}},
+		{ID: "fake-4", FirstName: "Marco", LastName: "Rossi", Groups: []string{"club100"}},
+	}
+}
+
+// Set replaces the served members.
+func (f *Fake) Set(members ...Member) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.members = members
+}
+
+// Fail makes ListMembers return err until it is called again with nil.
+func (f *Fake) Fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *Fake) Name() string { return ProviderFake }
+
+func (f *Fake) IsConfigured() bool { return f.configured }
+
+func (f *Fake) ListMembers(_ context.Context, filter Filter) ([]Member, error) {
+	if !f.configured {
+		return nil, ErrNotConfigured
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return filter.Apply(append([]Member(nil), f.members...)), nil
+}
@@ -0,0 +1,83 @@
+// Package membership reads the people entitled to a program such as Club100
+// from an external member directory. Each directory (Elvanto, an uploaded CSV
+// file, a fake for tests) implements Provider; callers copy the result into
+// their own tables and never query the provider on the hot path.
+package membership
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNotConfigured is returned by providers that lack credentials or data.
+var ErrNotConfigured = errors.New("membership_not_configured")
+
+// Provider names as used in MEMBERSHIP_PROVIDER.
+const (
+	ProviderElvanto = "elvanto"
+	ProviderCSV     = "csv"
+	ProviderFake    = "fake"
+)
+
+// Member is one person in the directory. ID is the provider's stable person
+// ID. Groups and Tags hold whatever the provider uses to classify people
+// (group IDs or names, categories) and are only used for filtering.
+type Member struct {
+	ID            string
+	FirstName     string
+	LastName      string
+	PreferredName string
+	Groups        []string
+	Tags          []string
+}
+
+// Filter narrows the directory to the people a program is for. A member
+// matches when they are in at least one of Groups and carry at least one of
+// Tags; an empty list places no restriction. Values compare case-insensitively.
+type Filter struct {
+	Groups []string
+	Tags   []string
+}
+
+// Match reports whether m passes the filter.
+func (f Filter) Match(m Member) bool {
+	return anyOf(f.Groups, m.Groups) && anyOf(f.Tags, m.Tags)
+}
+
+// Apply returns the members that pass the filter, keeping their order.
+func (f Filter) Apply(members []Member) []Member {
+	if len(f.Groups) == 0 && len(f.Tags) == 0 {
+		return members
+	}
+	out := make([]Member, 0, len(members))
+	for _, m := range members {
+		if f.Match(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func anyOf(want, have []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		for _, h := range have {
+			if strings.EqualFold(strings.TrimSpace(w), strings.TrimSpace(h)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type Provider interface {
+	// Name is one of the Provider* constants.
+	Name() string
+	IsConfigured() bool
+	// ListMembers returns every member passing f. An empty result is valid;
+	// callers decide whether to trust it.
+	ListMembers(ctx context.Context, f Filter) ([]Member, error)
+}
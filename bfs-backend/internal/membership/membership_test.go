@@ -0,0 +1,77 @@
+package membership
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	members := []Member{
+		{ID: "1", Groups: []string{"Club100"}},
+		{ID: "2", Groups: []string{"club100", "choir"}, Tags: []string{"Vorstand"}},
+		{ID: "3", Groups: []string{"choir"}, Tags: []string{"vorstand"}},
+	}
+	ids := func(ms []Member) []string {
+		out := make([]string, len(ms))
+		for i, m := range ms {
+			out[i] = m.ID
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, ids(Filter{}.Apply(members)))
+	assert.Equal(t, []string{"1", "2"}, ids(Filter{Groups: []string{"CLUB100"}}.Apply(members)))
+	assert.Equal(t, []string{"2", "3"}, ids(Filter{Tags: []string{"vorstand"}}.Apply(members)))
+	assert.Equal(t, []string{"2"}, ids(Filter{Groups: []string{"club100"}, Tags: []string{"vorstand"}}.Apply(members)))
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Run("semicolons and lists", func(t *testing.T) {
+		in := "\ufeffid;first_name;last_name;preferred_name;groups;tags\n" +
+			"a1;Johannes;Müller;Hans;club100 | choir;\n" +
+			"\n" +
+			"a2;Lea;Keller;;club100;Vorstand\n"
+		members, err := ParseCSV(strings.NewReader(in))
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, Member{
+			ID: "a1", FirstName: "Johannes", LastName: "Müller", PreferredName: "Hans",
+			Groups: []string{"club100", "choir"},
+		}, members[0])
+		assert.Equal(t, []string{"Vorstand"}, members[1].Tags)
+	})
+
+	t.Run("german headers in any order", func(t *testing.T) {
+		members, err := ParseCSV(strings.NewReader("Nr,Nachname,Vorname,Bemerkung\n7,Rossi,Marco,x\n"))
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, "Marco", members[0].FirstName)
+		assert.Equal(t, "Rossi", members[0].LastName)
+	})
+
+	for name, in := range map[string]string{
+		"empty":          "",
+		"missing column": "id,first_name\n1,Anna\n",
+		"missing id":     "id,first_name,last_name\n,Anna,Muster\n",
+		"duplicate id":   "id,first_name,last_name\n1,Anna,Muster\n1,Lea,Keller\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(in))
+			assert.ErrorIs(t, err, ErrInvalidCSV)
+		})
+	}
+}
+
+func TestCSVProvider(t *testing.T) {
+	p := NewCSV(func(context.Context) ([]byte, error) {
+		return []byte("id,first_name,last_name,groups\n1,Anna,Muster,club100\n2,Lea,Keller,choir\n"), nil
+	})
+	members, err := p.ListMembers(context.Background(), Filter{Groups: []string{"club100"}})
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "1", members[0].ID)
+}
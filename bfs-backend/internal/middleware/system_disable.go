@@ -3,8 +3,6 @@ package middleware
 import (
 	"context"
 	"net/http"
-	"sync"
-	"time"
 
 	"backend/internal/response"
 	"backend/internal/service"
@@ -14,61 +12,45 @@ type AdminChecker interface {
 	IsAdmin(r *http.Request) bool
 }
 
+// SystemDisableMiddleware turns non-admin requests away while the system is
+// closed, either manually or by its opening hours. The opening hours service
+// caches the settings, so every request asks it for the current status.
 type SystemDisableMiddleware struct {
-	settings     service.SettingsService
+	openingHours service.OpeningHoursService
 	adminChecker AdminChecker
-
-	mu        sync.RWMutex
-	cached    bool
-	fetchedAt time.Time
 }
 
-const systemCacheTTL = 5 * time.Second
-
-func NewSystemDisableMiddleware(settings service.SettingsService, adminChecker AdminChecker) *SystemDisableMiddleware {
+func NewSystemDisableMiddleware(openingHours service.OpeningHoursService, adminChecker AdminChecker) *SystemDisableMiddleware {
 	return &SystemDisableMiddleware{
-		settings:     settings,
+		openingHours: openingHours,
 		adminChecker: adminChecker,
-		cached:       true,
 	}
 }
 
-func (m *SystemDisableMiddleware) isEnabled(ctx context.Context) bool {
-	m.mu.RLock()
-	if !m.fetchedAt.IsZero() && time.Since(m.fetchedAt) < systemCacheTTL {
-		v := m.cached
-		m.mu.RUnlock()
-		return v
-	}
-	m.mu.RUnlock()
-
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if !m.fetchedAt.IsZero() && time.Since(m.fetchedAt) < systemCacheTTL {
-		return m.cached
-	}
-
-	enabled, err := m.settings.IsSystemEnabled(ctx)
+// status fails open: when the settings cannot be read the system stays usable.
+func (m *SystemDisableMiddleware) status(ctx context.Context) *service.SystemStatus {
+	status, err := m.openingHours.Status(ctx)
 	if err != nil {
-		return true
+		return &service.SystemStatus{Open: true}
 	}
-	m.cached = enabled
-	m.fetchedAt = time.Now()
-	return m.cached
+	return status
 }
 
 func (m *SystemDisableMiddleware) RequireEnabled(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !m.isEnabled(r.Context()) {
+		if status := m.status(r.Context()); !status.Open {
 			if m.adminChecker.IsAdmin(r) {
 				next.ServeHTTP(w, r)
 				return
 			}
+			detail := "The food system is currently closed."
+			if status.ClosedMessage != "" {
+				detail = status.ClosedMessage
+			}
 			response.WriteProblem(w, response.NewProblem(
 				http.StatusServiceUnavailable,
 				"Service Unavailable",
-				"The food system is currently closed.",
+				detail,
 			))
 			return
 		}
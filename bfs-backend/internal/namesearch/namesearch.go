@@ -0,0 +1,152 @@
+// Package namesearch ranks people by how well their name matches a typed
+// query. It is forgiving on purpose: cashiers type fast, drop accents and
+// misspell names ("Muller", "Mueler" and "Müller" all find Müller).
+package namesearch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Match scores per query token; a name must match every token.
+const (
+	scoreExact  = 3
+	scorePrefix = 2
+	scoreFuzzy  = 1
+)
+
+var folds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'ä': "a",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ß': "ss", 'æ': "ae", 'œ': "oe",
+}
+
+// Normalize lowercases s, folds accents and umlauts to plain letters and
+// turns everything that is not a letter or digit into single spaces.
+func Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if f, ok := folds[r]; ok {
+			b.WriteString(f)
+			space = false
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space && b.Len() > 0 {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Score rates how well name (normalized) matches the query (normalized).
+// Every query token must match a name token exactly, as a prefix or within a
+// small edit distance; zero means no match. An empty query matches everything
+// with score zero, see Rank.
+func Score(query, name string) int {
+	nameTokens := strings.Fields(name)
+	total := 0
+	for _, q := range strings.Fields(query) {
+		best := 0
+		for _, n := range nameTokens {
+			best = max(best, tokenScore(q, n))
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+func tokenScore(q, n string) int {
+	switch {
+	case q == n:
+		return scoreExact
+	case strings.HasPrefix(n, q):
+		return scorePrefix
+	}
+	qr, nr := []rune(q), []rune(n)
+	allowed := maxEdits(len(qr))
+	if allowed == 0 {
+		return 0
+	}
+	// A typo in a prefix still counts: compare against the name token cut
+	// to the query's length as well as the whole token.
+	if len(nr) > len(qr) && distance(qr, nr[:len(qr)]) <= allowed {
+		return scoreFuzzy
+	}
+	if distance(qr, nr) <= allowed {
+		return scoreFuzzy
+	}
+	return 0
+}
+
+// maxEdits allows one typo from four letters on and two from eight.
+func maxEdits(n int) int {
+	switch {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// Rank returns the indexes of names matching query, best match first. Ties
+// keep their input order, so callers can pre-sort alphabetically. An empty
+// query returns every index.
+func Rank(query string, names []string) []int {
+	q := Normalize(query)
+	type hit struct{ idx, score int }
+	hits := make([]hit, 0, len(names))
+	for i, name := range names {
+		if q == "" {
+			hits = append(hits, hit{i, 0})
+			continue
+		}
+		if s := Score(q, Normalize(name)); s > 0 {
+			hits = append(hits, hit{i, s})
+		}
+	}
+	sort.SliceStable(hits, func(a, b int) bool { return hits[a].score > hits[b].score })
+	out := make([]int, len(hits))
+	for i, h := range hits {
+		out[i] = h.idx
+	}
+	return out
+}
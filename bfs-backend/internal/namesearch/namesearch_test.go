@@ -0,0 +1,33 @@
+package namesearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "muller jean luc", Normalize("  Müller, Jean-Luc "))
+	assert.Equal(t, "strasser", Normalize("Straßer"))
+	assert.Equal(t, "", Normalize(" - "))
+}
+
+func TestScore(t *testing.T) {
+	name := Normalize("Hans-Peter Müller")
+	assert.Equal(t, scoreExact+scoreExact, Score("hans muller", name))
+	assert.Equal(t, scorePrefix, Score(Normalize("mül"), name))
+	assert.Equal(t, scoreFuzzy, Score("mueller", name))
+	assert.Equal(t, scoreFuzzy, Score("muler", name))
+	assert.Zero(t, Score("meier", name))
+	assert.Zero(t, Score("hans meier", name), "every token must match")
+	assert.Zero(t, Score("mx", name), "short tokens get no typo allowance")
+}
+
+func TestRank(t *testing.T) {
+	names := []string{"Anna Meier", "Hans Müller", "Anna Müller", "Beat Mueller"}
+	assert.Equal(t, []int{1, 2, 3}, Rank("müller", names))
+	assert.Equal(t, []int{2}, Rank("anna muller", names))
+	assert.Equal(t, []int{3, 1, 2}, Rank("mueller", names), "exact match ranks first")
+	assert.Equal(t, []int{0, 1, 2, 3}, Rank("", names))
+	assert.Empty(t, Rank("zoe", names))
+}
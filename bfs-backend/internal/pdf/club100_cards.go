@@ -0,0 +1,152 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"backend/internal/i18n"
+
+	"github.com/go-pdf/fpdf"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Member cards are credit-card sized (ISO ID-1) and printed ten to an A4
+// sheet, two columns by five rows, with cut lines like the slips.
+const (
+	cardWidthMM  = 85.6
+	cardHeightMM = 54.0
+	cardColumns  = 2
+	cardRows     = 5
+	cardQRSizeMM = 40.0
+
+	cardTitleFontSize   = 11.0
+	cardTitleLineHeight = 4.6
+	cardNameFontSize    = 10.0
+	cardNameLineHeight  = 4.2
+	cardNoteFontSize    = 6.5
+	cardNoteLineHeight  = 2.8
+)
+
+type MemberCard struct {
+	Name      string
+	QRPayload string
+	// Note is printed small below the name, e.g. the issue date.
+	Note string
+}
+
+type MemberCardInput struct {
+	// Title is printed on every card, e.g. "100 Club".
+	Title string
+	Cards []MemberCard
+	// Locale of the printed instruction; German when empty.
+	Locale i18n.Locale
+}
+
+// RenderMemberCards renders one card per entry: the QR code on the left, the
+// title and member name on the right.
+func RenderMemberCards(in MemberCardInput) ([]byte, error) {
+	if len(in.Cards) == 0 {
+		return nil, fmt.Errorf("at least one card required")
+	}
+
+	layout := Layout{
+		Width:   LayoutA4.Width,
+		Height:  LayoutA4.Height,
+		Columns: cardColumns,
+		Rows:    cardRows,
+	}
+	layout.Margin = (layout.Width - cardColumns*cardWidthMM - (cardColumns-1)*slipHGap) / 2
+	topMargin := (layout.Height - cardRows*cardHeightMM - (cardRows-1)*slipVGap) / 2
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: layout.Width, Ht: layout.Height},
+	})
+	pdf.SetMargins(layout.Margin, topMargin, layout.Margin)
+	pdf.SetAutoPageBreak(false, topMargin)
+	pdf.SetTextColor(20, 20, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	images := make([]string, len(in.Cards))
+	for i, c := range in.Cards {
+		if strings.TrimSpace(c.QRPayload) == "" {
+			return nil, fmt.Errorf("qr payload required for %q", c.Name)
+		}
+		qrPng, err := qrcode.Encode(c.QRPayload, qrcode.Medium, 512)
+		if err != nil {
+			return nil, fmt.Errorf("encode qr: %w", err)
+		}
+		images[i] = fmt.Sprintf("qr%d", i)
+		pdf.RegisterImageOptionsReader(images[i], fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qrPng))
+	}
+
+	textWidth := cardWidthMM - cardQRSizeMM - 3*paddingMM
+	titleLines := wrapTextLines(tr(in.Title), textWidth, cardTitleFontSize, pdf, "B")
+	instructionLines := wrapTextLines(tr(textsFor(in.Locale).cardInstruction), textWidth, cardNoteFontSize, pdf, "I")
+
+	perPage := cardColumns * cardRows
+	for start := 0; start < len(in.Cards); start += perPage {
+		pdf.AddPage()
+		pageCards := min(perPage, len(in.Cards)-start)
+		for slot := 0; slot < pageCards; slot++ {
+			c := in.Cards[start+slot]
+			x := layout.Margin + float64(slot%cardColumns)*(cardWidthMM+slipHGap)
+			y := topMargin + float64(slot/cardColumns)*(cardHeightMM+slipVGap)
+
+			pdf.ImageOptions(images[start+slot], x+paddingMM, y+(cardHeightMM-cardQRSizeMM)/2, cardQRSizeMM, cardQRSizeMM,
+				false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+			tx := x + cardQRSizeMM + 2*paddingMM
+			ty := y + 2*paddingMM
+			ty = drawCardLines(pdf, tx, ty, textWidth, titleLines, "B", cardTitleFontSize, cardTitleLineHeight)
+			ty += 1.5
+			nameLines := wrapTextLines(tr(c.Name), textWidth, cardNameFontSize, pdf, "B")
+			ty = drawCardLines(pdf, tx, ty, textWidth, nameLines, "B", cardNameFontSize, cardNameLineHeight)
+
+			pdf.SetTextColor(110, 110, 110)
+			bottom := y + cardHeightMM - 2*paddingMM
+			var noteLines []string
+			if c.Note != "" {
+				noteLines = wrapTextLines(tr(c.Note), textWidth, cardNoteFontSize, pdf, "")
+			}
+			footY := bottom - float64(len(instructionLines)+len(noteLines))*cardNoteLineHeight
+			footY = drawCardLines(pdf, tx, max(ty+1, footY), textWidth, instructionLines, "I", cardNoteFontSize, cardNoteLineHeight)
+			drawCardLines(pdf, tx, footY, textWidth, noteLines, "", cardNoteFontSize, cardNoteLineHeight)
+			pdf.SetTextColor(20, 20, 20)
+		}
+		drawCardCutLines(pdf, layout, topMargin, pageCards)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("write pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func drawCardLines(pdf *fpdf.Fpdf, x, y, width float64, lines []string, style string, fontSize, lineHeight float64) float64 {
+	pdf.SetFont("Helvetica", style, fontSize)
+	for _, line := range lines {
+		pdf.SetXY(x, y)
+		pdf.CellFormat(width, lineHeight, line, "", 0, "L", false, 0, "")
+		y += lineHeight
+	}
+	return y
+}
+
+// drawCardCutLines draws the outline of every card; unlike slips, cards are
+// centred vertically, so the grid starts at topMargin.
+func drawCardCutLines(pdf *fpdf.Fpdf, layout Layout, topMargin float64, cardsOnPage int) {
+	pdf.SetDrawColor(160, 160, 160)
+	pdf.SetLineWidth(0.1)
+	pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
+	defer pdf.SetDashPattern([]float64{}, 0)
+
+	for slot := 0; slot < cardsOnPage; slot++ {
+		x := layout.Margin + float64(slot%cardColumns)*(cardWidthMM+slipHGap)
+		y := topMargin + float64(slot/cardColumns)*(cardHeightMM+slipVGap)
+		pdf.Rect(x, y, cardWidthMM, cardHeightMM, "D")
+	}
+}
@@ -0,0 +1,37 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestRenderMemberCards(t *testing.T) {
+	cards := make([]MemberCard, 12)
+	for i := range cards {
+		cards[i] = MemberCard{
+			Name:      fmt.Sprintf("Mitglied %d Müller-Lüdenscheidt", i+1),
+			QRPayload: fmt.Sprintf("BFS1.M.tkn_card__%02d.9999999999.sig", i),
+			Note:      "Ausgestellt 18.10.2026",
+		}
+	}
+	out, err := RenderMemberCards(MemberCardInput{Title: "100 Club", Cards: cards})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatalf("output missing PDF magic bytes; got first 8 bytes = %q", out[:8])
+	}
+	if pages := bytes.Count(out, []byte("/Type /Page\n")); pages != 2 {
+		t.Fatalf("expected 2 pages for 12 cards, got %d", pages)
+	}
+}
+
+func TestRenderMemberCards_RejectsBadInput(t *testing.T) {
+	if _, err := RenderMemberCards(MemberCardInput{Title: "100 Club"}); err == nil {
+		t.Fatal("expected error without cards")
+	}
+	if _, err := RenderMemberCards(MemberCardInput{Cards: []MemberCard{{Name: "Anna"}}}); err == nil {
+		t.Fatal("expected error for empty payload")
+	}
+}
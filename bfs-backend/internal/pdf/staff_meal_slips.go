@@ -2,9 +2,12 @@ package pdf
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 
+	"backend/internal/i18n"
+
 	"github.com/go-pdf/fpdf"
 	qrcode "github.com/skip2/go-qrcode"
 )
@@ -14,21 +17,95 @@ type SlipProduct struct {
 	Quantity int
 }
 
+// Layout is the sheet slips are printed on, in mm.
+type Layout struct {
+	Width float64
+	// Height is zero for continuous label rolls: every row of slips gets its
+	// own page, cut to the slip's height.
+	Height  float64
+	Margin  float64
+	Columns int
+	// Rows fixes the rows per page, e.g. for label sheets with a given pitch;
+	// zero fits as many rows as the page holds.
+	Rows int
+}
+
+var (
+	LayoutA4      = Layout{Width: 210, Height: 297, Margin: 6, Columns: 5}
+	LayoutRoll62  = Layout{Width: 62, Margin: 3, Columns: 1}
+	LayoutRoll102 = Layout{Width: 102, Margin: 4, Columns: 1}
+)
+
+// ErrInvalidLayout wraps layout errors, e.g. more columns than the page
+// width allows.
+var ErrInvalidLayout = errors.New("invalid slip layout")
+
+const (
+	MaxLayoutColumns = 8
+	MaxLayoutRows    = 20
+)
+
+// LayoutByName resolves the format names accepted by the print endpoints.
+func LayoutByName(name string) (Layout, bool) {
+	switch name {
+	case "", "a4":
+		return LayoutA4, true
+	case "62mm":
+		return LayoutRoll62, true
+	case "102mm":
+		return LayoutRoll102, true
+	}
+	return Layout{}, false
+}
+
+func (l Layout) Continuous() bool {
+	return l.Height == 0
+}
+
+func (l Layout) validate() error {
+	if l.Columns < 1 || l.Columns > MaxLayoutColumns {
+		return fmt.Errorf("%w: columns must be between 1 and %d", ErrInvalidLayout, MaxLayoutColumns)
+	}
+	if l.Rows < 0 || l.Rows > MaxLayoutRows {
+		return fmt.Errorf("%w: rows must be between 0 and %d", ErrInvalidLayout, MaxLayoutRows)
+	}
+	if l.Width-2*l.Margin < float64(l.Columns)*minSlipWidthMM {
+		return fmt.Errorf("%w: page too narrow for %d columns", ErrInvalidLayout, l.Columns)
+	}
+	return nil
+}
+
+// SlipStyle is shared by all slip variants.
+type SlipStyle struct {
+	// Layout defaults to LayoutA4.
+	Layout Layout
+	// Logo is a PNG or JPEG printed above the QR code.
+	Logo []byte
+	// Validity is printed at the bottom of each slip, e.g. the validity
+	// range and meal windows of the campaign.
+	Validity []string
+	// Locale of the printed instruction and labels; German when empty.
+	Locale i18n.Locale
+}
+
 type SlipInput struct {
 	CampaignName string
 	QRPayload    string
 	Products     []SlipProduct
 	Count        int
+	// FirstNumber numbers the slips sequentially from here; zero prints no
+	// numbers.
+	FirstNumber int
+	Style       SlipStyle
 }
 
 const (
-	pageWidth             = 210.0
-	pageHeight            = 297.0
-	pageMarginMM          = 6.0
-	slipColumns           = 5
 	slipHGap              = 2.0
 	slipVGap              = 2.0
 	qrSizeMM              = 28.0
+	qrMaxSizeMM           = 45.0
+	minSlipWidthMM        = 20.0
+	logoHeightMM          = 8.0
 	paddingMM             = 2.5
 	nameFontSize          = 8.0
 	nameLineHeight        = 3.2
@@ -36,10 +113,34 @@ const (
 	instructionLineHeight = 2.3
 	itemFontSize          = 6.5
 	itemLineHeight        = 2.6
-
-	instructionText = "An der Station vorzeigen"
 )
 
+// PersonalSlip is one slip with its own QR code: a volunteer's, with their
+// name as Holder, or an anonymous single-use slip identified by Number.
+type PersonalSlip struct {
+	Holder         string
+	QRPayload      string
+	MaxRedemptions int
+	// Number is printed as "Nr. 0042" (in the slip's locale) when positive.
+	Number int
+}
+
+type PersonalSlipInput struct {
+	CampaignName string
+	Products     []SlipProduct
+	Slips        []PersonalSlip
+	Style        SlipStyle
+}
+
+// slipContent is what differs between slips on a sheet: the registered QR
+// image, an optional number and optional holder lines printed above the
+// campaign name.
+type slipContent struct {
+	image  string
+	number int
+	holder []string
+}
+
 func RenderStaffMealSlips(in SlipInput) ([]byte, error) {
 	if in.Count <= 0 {
 		return nil, fmt.Errorf("count must be positive")
@@ -53,59 +154,185 @@ func RenderStaffMealSlips(in SlipInput) ([]byte, error) {
 		return nil, fmt.Errorf("encode qr: %w", err)
 	}
 
-	pdf := fpdf.New("P", "mm", "A4", "")
-	pdf.SetMargins(pageMarginMM, pageMarginMM, pageMarginMM)
-	pdf.SetAutoPageBreak(false, pageMarginMM)
+	slips := make([]slipContent, in.Count)
+	for i := range slips {
+		slips[i] = slipContent{image: "qr"}
+		if in.FirstNumber > 0 {
+			slips[i].number = in.FirstNumber + i
+		}
+	}
+	return renderSlips(in.CampaignName, in.Products, in.Style, slips, map[string][]byte{"qr": qrPng})
+}
+
+// RenderPersonalStaffMealSlips renders one slip per entry, each with its own
+// QR code and the holder's name or slip number.
+func RenderPersonalStaffMealSlips(in PersonalSlipInput) ([]byte, error) {
+	if len(in.Slips) == 0 {
+		return nil, fmt.Errorf("at least one slip required")
+	}
+
+	images := make(map[string][]byte, len(in.Slips))
+	slips := make([]slipContent, 0, len(in.Slips))
+	for i, ps := range in.Slips {
+		if strings.TrimSpace(ps.QRPayload) == "" {
+			return nil, fmt.Errorf("qr payload required for %q", ps.Holder)
+		}
+		qrPng, err := qrcode.Encode(ps.QRPayload, qrcode.Medium, 512)
+		if err != nil {
+			return nil, fmt.Errorf("encode qr: %w", err)
+		}
+		name := fmt.Sprintf("qr%d", i)
+		images[name] = qrPng
+		var holder []string
+		if ps.Holder != "" {
+			holder = append(holder, ps.Holder)
+		}
+		if ps.MaxRedemptions > 1 {
+			holder = append(holder, fmt.Sprintf(textsFor(in.Style.Locale).validFor, ps.MaxRedemptions))
+		}
+		slips = append(slips, slipContent{image: name, number: ps.Number, holder: holder})
+	}
+	return renderSlips(in.CampaignName, in.Products, in.Style, slips, images)
+}
+
+// slipBlock is the measured layout shared by every slip of one document.
+type slipBlock struct {
+	width            float64
+	height           float64
+	qrSize           float64
+	logoWidth        float64
+	logoHeight       float64
+	numberHeight     float64
+	holderHeight     float64
+	nameLines        []string
+	instructionLines []string
+	productLines     [][]string
+	validityLines    []string
+	numberFormat     string
+}
+
+func renderSlips(campaignName string, products []SlipProduct, style SlipStyle, slips []slipContent, images map[string][]byte) ([]byte, error) {
+	layout := style.Layout
+	if layout == (Layout{}) {
+		layout = LayoutA4
+	}
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+
+	initHeight := layout.Height
+	if layout.Continuous() {
+		initHeight = layout.Width
+	}
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: layout.Width, Ht: initHeight},
+	})
+	pdf.SetMargins(layout.Margin, layout.Margin, layout.Margin)
+	pdf.SetAutoPageBreak(false, layout.Margin)
 	pdf.SetTextColor(20, 20, 20)
 	tr := pdf.UnicodeTranslatorFromDescriptor("")
 
-	pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qrPng))
+	for name, png := range images {
+		pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	}
 
-	usableWidth := pageWidth - 2*pageMarginMM
-	slipWidth := (usableWidth - float64(slipColumns-1)*slipHGap) / float64(slipColumns)
+	usableWidth := layout.Width - 2*layout.Margin
+	b := &slipBlock{
+		width: (usableWidth - float64(layout.Columns-1)*slipHGap) / float64(layout.Columns),
+	}
+	inner := b.width - 2*paddingMM
+	b.qrSize = min(inner, qrMaxSizeMM, max(qrSizeMM, b.width*0.6))
 
-	campaignNameEnc := tr(in.CampaignName)
-	instructionEnc := tr(instructionText)
+	if len(style.Logo) > 0 {
+		imgType, err := logoImageType(style.Logo)
+		if err != nil {
+			return nil, err
+		}
+		info := pdf.RegisterImageOptionsReader("logo", fpdf.ImageOptions{ImageType: imgType}, bytes.NewReader(style.Logo))
+		if err := pdf.Error(); err != nil {
+			return nil, fmt.Errorf("logo: %w", err)
+		}
+		b.logoHeight = logoHeightMM
+		b.logoWidth = logoHeightMM * info.Width() / info.Height()
+		if b.logoWidth > inner {
+			b.logoHeight *= inner / b.logoWidth
+			b.logoWidth = inner
+		}
+	}
 
-	nameLines := wrapTextLines(campaignNameEnc, slipWidth-2*paddingMM, nameFontSize, pdf, "B")
-	instructionLines := wrapTextLines(instructionEnc, slipWidth-2*paddingMM, instructionFontSize, pdf, "I")
+	b.nameLines = wrapTextLines(tr(campaignName), inner, nameFontSize, pdf, "B")
+	texts := textsFor(style.Locale)
+	b.instructionLines = wrapTextLines(tr(texts.slipInstruction), inner, instructionFontSize, pdf, "I")
+	b.numberFormat = tr(texts.number)
 
-	productLines := make([][]string, len(in.Products))
+	b.productLines = make([][]string, len(products))
 	totalItemLines := 0
-	for i, p := range in.Products {
-		line := tr(formatProductLine(p))
-		wrapped := wrapTextLines(line, slipWidth-2*paddingMM, itemFontSize, pdf, "")
-		productLines[i] = wrapped
-		totalItemLines += len(wrapped)
+	for i, p := range products {
+		b.productLines[i] = wrapTextLines(tr(formatProductLine(p)), inner, itemFontSize, pdf, "")
+		totalItemLines += len(b.productLines[i])
+	}
+	for _, v := range style.Validity {
+		b.validityLines = append(b.validityLines, wrapTextLines(tr(v), inner, instructionFontSize, pdf, "I")...)
+	}
+
+	// Every slip in a document has the same height so the cut lines line up;
+	// size the holder block for the longest name.
+	holderLines := make([][]string, len(slips))
+	maxHolderLines := 0
+	numbered := false
+	for i, sc := range slips {
+		for _, h := range sc.holder {
+			holderLines[i] = append(holderLines[i], wrapTextLines(tr(h), inner, nameFontSize, pdf, "B")...)
+		}
+		maxHolderLines = max(maxHolderLines, len(holderLines[i]))
+		numbered = numbered || sc.number > 0
+	}
+	if maxHolderLines > 0 {
+		b.holderHeight = float64(maxHolderLines)*nameLineHeight + 1.2
+	}
+	if numbered {
+		b.numberHeight = nameLineHeight + 0.8
 	}
 
-	slipHeight := paddingMM + qrSizeMM + 1.2 +
-		float64(len(instructionLines))*instructionLineHeight + 1.5 +
-		float64(len(nameLines))*nameLineHeight + 1.2 +
+	b.height = paddingMM + b.qrSize + 1.2 +
+		float64(len(b.instructionLines))*instructionLineHeight + 1.5 +
+		b.numberHeight +
+		b.holderHeight +
+		float64(len(b.nameLines))*nameLineHeight + 1.2 +
 		float64(totalItemLines)*itemLineHeight + paddingMM
+	if b.logoHeight > 0 {
+		b.height += b.logoHeight + 1.2
+	}
+	if len(b.validityLines) > 0 {
+		b.height += 1.2 + float64(len(b.validityLines))*instructionLineHeight
+	}
 
-	maxRows := int((pageHeight - 2*pageMarginMM + slipVGap) / (slipHeight + slipVGap))
-	if maxRows < 1 {
-		maxRows = 1
+	rows, cellHeight, err := pageRows(layout, b.height)
+	if err != nil {
+		return nil, err
 	}
-	perPage := maxRows * slipColumns
+	perPage := rows * layout.Columns
 
 	idx := 0
-	for idx < in.Count {
-		pdf.AddPage()
-		remaining := in.Count - idx
-		pageSlips := perPage
-		if remaining < pageSlips {
-			pageSlips = remaining
+	for idx < len(slips) {
+		if layout.Continuous() {
+			pdf.AddPageFormat("P", fpdf.SizeType{Wd: layout.Width, Ht: cellHeight + 2*layout.Margin})
+		} else {
+			pdf.AddPage()
 		}
+		pageSlips := min(perPage, len(slips)-idx)
 		for slot := 0; slot < pageSlips; slot++ {
-			row := slot / slipColumns
-			col := slot % slipColumns
-			x := pageMarginMM + float64(col)*(slipWidth+slipHGap)
-			y := pageMarginMM + float64(row)*(slipHeight+slipVGap)
-			drawSlip(pdf, x, y, slipWidth, nameLines, instructionLines, productLines)
+			row := slot / layout.Columns
+			col := slot % layout.Columns
+			x := layout.Margin + float64(col)*(b.width+slipHGap)
+			y := layout.Margin + float64(row)*(cellHeight+slipVGap)
+			drawSlip(pdf, x, y, b, slips[idx+slot], holderLines[idx+slot])
+		}
+		if !layout.Continuous() || layout.Columns > 1 {
+			drawCutLines(pdf, layout, b.width, cellHeight, pageSlips)
 		}
-		drawCutLines(pdf, slipWidth, slipHeight, pageSlips)
 		idx += pageSlips
 	}
 
@@ -116,6 +343,33 @@ func RenderStaffMealSlips(in SlipInput) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// pageRows returns the rows per page and the height of one row's cell. Rolls
+// print one row per page; fixed rows split the page evenly.
+func pageRows(layout Layout, slipHeight float64) (int, float64, error) {
+	if layout.Continuous() {
+		return 1, slipHeight, nil
+	}
+	usable := layout.Height - 2*layout.Margin
+	if layout.Rows > 0 {
+		cell := (usable+slipVGap)/float64(layout.Rows) - slipVGap
+		if cell < slipHeight {
+			return 0, 0, fmt.Errorf("%w: slips need %.0f mm and do not fit %d rows per page", ErrInvalidLayout, slipHeight, layout.Rows)
+		}
+		return layout.Rows, cell, nil
+	}
+	return max(1, int((usable+slipVGap)/(slipHeight+slipVGap))), slipHeight, nil
+}
+
+func logoImageType(b []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(b, []byte("\x89PNG")):
+		return "PNG", nil
+	case bytes.HasPrefix(b, []byte("\xff\xd8\xff")):
+		return "JPG", nil
+	}
+	return "", fmt.Errorf("logo must be a PNG or JPEG image")
+}
+
 func formatProductLine(p SlipProduct) string {
 	qty := p.Quantity
 	if qty < 1 {
@@ -124,15 +378,20 @@ func formatProductLine(p SlipProduct) string {
 	return fmt.Sprintf("%d\u00d7 %s", qty, p.Name)
 }
 
-func drawSlip(pdf *fpdf.Fpdf, x, y, w float64, nameLines, instructionLines []string, productLines [][]string) {
-	qrX := x + (w-qrSizeMM)/2
-	qrY := y + paddingMM
-	pdf.ImageOptions("qr", qrX, qrY, qrSizeMM, qrSizeMM, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+func drawSlip(pdf *fpdf.Fpdf, x, y float64, b *slipBlock, sc slipContent, holderLines []string) {
+	w := b.width
+	textY := y + paddingMM
+	if b.logoHeight > 0 {
+		pdf.ImageOptions("logo", x+(w-b.logoWidth)/2, textY, b.logoWidth, b.logoHeight, false, fpdf.ImageOptions{}, 0, "")
+		textY += b.logoHeight + 1.2
+	}
+
+	pdf.ImageOptions(sc.image, x+(w-b.qrSize)/2, textY, b.qrSize, b.qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	textY += b.qrSize + 1.2
 
-	textY := qrY + qrSizeMM + 1.2
 	pdf.SetFont("Helvetica", "I", instructionFontSize)
 	pdf.SetTextColor(110, 110, 110)
-	for _, line := range instructionLines {
+	for _, line := range b.instructionLines {
 		pdf.SetXY(x+paddingMM, textY)
 		pdf.CellFormat(w-2*paddingMM, instructionLineHeight, line, "", 0, "C", false, 0, "")
 		textY += instructionLineHeight
@@ -140,8 +399,26 @@ func drawSlip(pdf *fpdf.Fpdf, x, y, w float64, nameLines, instructionLines []str
 	pdf.SetTextColor(20, 20, 20)
 
 	textY += 1.5
+	if b.numberHeight > 0 {
+		if sc.number > 0 {
+			pdf.SetFont("Helvetica", "B", nameFontSize)
+			pdf.SetXY(x+paddingMM, textY)
+			pdf.CellFormat(w-2*paddingMM, nameLineHeight, fmt.Sprintf(b.numberFormat, sc.number), "", 0, "C", false, 0, "")
+		}
+		textY += b.numberHeight
+	}
+	if b.holderHeight > 0 {
+		pdf.SetFont("Helvetica", "B", nameFontSize)
+		holderY := textY
+		for _, line := range holderLines {
+			pdf.SetXY(x+paddingMM, holderY)
+			pdf.CellFormat(w-2*paddingMM, nameLineHeight, line, "", 0, "C", false, 0, "")
+			holderY += nameLineHeight
+		}
+		textY += b.holderHeight
+	}
 	pdf.SetFont("Helvetica", "B", nameFontSize)
-	for _, line := range nameLines {
+	for _, line := range b.nameLines {
 		pdf.SetXY(x+paddingMM, textY)
 		pdf.CellFormat(w-2*paddingMM, nameLineHeight, line, "", 0, "C", false, 0, "")
 		textY += nameLineHeight
@@ -149,39 +426,55 @@ func drawSlip(pdf *fpdf.Fpdf, x, y, w float64, nameLines, instructionLines []str
 
 	textY += 1.2
 	pdf.SetFont("Helvetica", "", itemFontSize)
-	for _, wrapped := range productLines {
+	for _, wrapped := range b.productLines {
 		for _, wl := range wrapped {
 			pdf.SetXY(x+paddingMM, textY)
 			pdf.CellFormat(w-2*paddingMM, itemLineHeight, wl, "", 0, "C", false, 0, "")
 			textY += itemLineHeight
 		}
 	}
+
+	if len(b.validityLines) > 0 {
+		textY += 1.2
+		pdf.SetFont("Helvetica", "I", instructionFontSize)
+		pdf.SetTextColor(110, 110, 110)
+		for _, line := range b.validityLines {
+			pdf.SetXY(x+paddingMM, textY)
+			pdf.CellFormat(w-2*paddingMM, instructionLineHeight, line, "", 0, "C", false, 0, "")
+			textY += instructionLineHeight
+		}
+		pdf.SetTextColor(20, 20, 20)
+	}
 }
 
-func drawCutLines(pdf *fpdf.Fpdf, slipWidth, slipHeight float64, slipsOnPage int) {
+func drawCutLines(pdf *fpdf.Fpdf, layout Layout, slipWidth, slipHeight float64, slipsOnPage int) {
 	pdf.SetDrawColor(160, 160, 160)
 	pdf.SetLineWidth(0.1)
 	pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
 	defer pdf.SetDashPattern([]float64{}, 0)
 
-	rows := (slipsOnPage-1)/slipColumns + 1
-	topEdge := pageMarginMM - slipVGap/2
-	bottomEdge := pageMarginMM + float64(rows-1)*(slipHeight+slipVGap) + slipHeight + slipVGap/2
+	margin := layout.Margin
+	rows := (slipsOnPage-1)/layout.Columns + 1
+	topEdge := margin - slipVGap/2
+	bottomEdge := margin + float64(rows-1)*(slipHeight+slipVGap) + slipHeight + slipVGap/2
 
-	for r := 0; r <= rows; r++ {
-		var y float64
-		switch r {
-		case 0:
-			y = topEdge
-		case rows:
-			y = bottomEdge
-		default:
-			y = pageMarginMM + float64(r)*(slipHeight+slipVGap) - slipVGap/2
+	// On rolls the printer cuts between rows; only the columns need lines.
+	if !layout.Continuous() {
+		for r := 0; r <= rows; r++ {
+			var y float64
+			switch r {
+			case 0:
+				y = topEdge
+			case rows:
+				y = bottomEdge
+			default:
+				y = margin + float64(r)*(slipHeight+slipVGap) - slipVGap/2
+			}
+			pdf.Line(margin-1, y, layout.Width-margin+1, y)
 		}
-		pdf.Line(pageMarginMM-1, y, pageWidth-pageMarginMM+1, y)
 	}
-	for c := 1; c < slipColumns; c++ {
-		x := pageMarginMM + float64(c)*slipWidth + float64(c-1)*slipHGap + slipHGap/2
+	for c := 1; c < layout.Columns; c++ {
+		x := margin + float64(c)*slipWidth + float64(c-1)*slipHGap + slipHGap/2
 		pdf.Line(x, topEdge, x, bottomEdge)
 	}
 }
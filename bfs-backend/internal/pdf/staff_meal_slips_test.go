@@ -2,7 +2,13 @@ package pdf
 
 import (
 	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
 	"testing"
+
+	"backend/internal/i18n"
 )
 
 func TestRenderStaffMealSlips_ProducesPDF(t *testing.T) {
@@ -34,3 +40,141 @@ func TestRenderStaffMealSlips_RejectsBadInput(t *testing.T) {
 		t.Fatal("expected error for empty payload")
 	}
 }
+
+func TestRenderPersonalStaffMealSlips(t *testing.T) {
+	out, err := RenderPersonalStaffMealSlips(PersonalSlipInput{
+		CampaignName: "Helferessen Samstag",
+		Products:     []SlipProduct{{Name: "Pizza Margherita", Quantity: 1}},
+		Slips: []PersonalSlip{
+			{Holder: "Anna Muster", QRPayload: "CAMP:tkn_pers___1", MaxRedemptions: 1},
+			{Holder: "Jean-Philippe Müller-Lüdenscheidt", QRPayload: "CAMP:tkn_pers___2", MaxRedemptions: 3},
+		},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatalf("output missing PDF magic bytes; got first 8 bytes = %q", out[:8])
+	}
+
+	if _, err := RenderPersonalStaffMealSlips(PersonalSlipInput{}); err == nil {
+		t.Fatal("expected error without slips")
+	}
+	if _, err := RenderPersonalStaffMealSlips(PersonalSlipInput{Slips: []PersonalSlip{{Holder: "Anna"}}}); err == nil {
+		t.Fatal("expected error for empty payload")
+	}
+}
+
+func testLogo(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 120, 40))
+	for x := 0; x < 120; x++ {
+		for y := 0; y < 40; y++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode logo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRenderStaffMealSlips_Layouts(t *testing.T) {
+	style := SlipStyle{
+		Logo:     testLogo(t),
+		Validity: []string{"Gültig bis 31.07.2026", "Mittag: Mo–Fr 11:30–13:30"},
+	}
+	for _, name := range []string{"a4", "62mm", "102mm"} {
+		layout, ok := LayoutByName(name)
+		if !ok {
+			t.Fatalf("layout %q not found", name)
+		}
+		style.Layout = layout
+		out, err := RenderStaffMealSlips(SlipInput{
+			CampaignName: "Helferessen Samstag",
+			QRPayload:    "CAMP:tkn_camp___1",
+			Products:     []SlipProduct{{Name: "Pizza Margherita", Quantity: 1}},
+			Count:        12,
+			FirstNumber:  41,
+			Style:        style,
+		})
+		if err != nil {
+			t.Fatalf("%s: render: %v", name, err)
+		}
+		if !bytes.HasPrefix(out, []byte("%PDF-")) {
+			t.Fatalf("%s: output missing PDF magic bytes", name)
+		}
+	}
+	if _, ok := LayoutByName("a5"); ok {
+		t.Fatal("unknown layout resolved")
+	}
+}
+
+func TestRenderStaffMealSlips_GridLimits(t *testing.T) {
+	in := SlipInput{CampaignName: "X", QRPayload: "CAMP:x", Count: 4}
+
+	in.Style.Layout = LayoutA4
+	in.Style.Layout.Columns = 3
+	in.Style.Layout.Rows = 4
+	if _, err := RenderStaffMealSlips(in); err != nil {
+		t.Fatalf("3x4 grid: %v", err)
+	}
+
+	in.Style.Layout.Rows = 20
+	if _, err := RenderStaffMealSlips(in); !errors.Is(err, ErrInvalidLayout) {
+		t.Fatalf("expected ErrInvalidLayout when slips do not fit the rows, got %v", err)
+	}
+
+	in.Style.Layout = LayoutRoll62
+	in.Style.Layout.Columns = 4
+	if _, err := RenderStaffMealSlips(in); !errors.Is(err, ErrInvalidLayout) {
+		t.Fatalf("expected ErrInvalidLayout for too many columns on a narrow roll, got %v", err)
+	}
+}
+
+func TestRenderStaffMealSlips_RejectsUnsupportedLogo(t *testing.T) {
+	_, err := RenderStaffMealSlips(SlipInput{
+		QRPayload: "CAMP:x",
+		Count:     1,
+		Style:     SlipStyle{Logo: []byte("GIF89a")},
+	})
+	if err == nil {
+		t.Fatal("expected error for GIF logo")
+	}
+}
+
+func TestRenderPersonalStaffMealSlips_Numbered(t *testing.T) {
+	out, err := RenderPersonalStaffMealSlips(PersonalSlipInput{
+		CampaignName: "Helferessen Samstag",
+		Slips: []PersonalSlip{
+			{QRPayload: "CAMP:tkn_slip___1", MaxRedemptions: 1, Number: 1},
+			{QRPayload: "CAMP:tkn_slip___2", MaxRedemptions: 1, Number: 2},
+		},
+		Style: SlipStyle{Layout: LayoutRoll62},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("output missing PDF magic bytes")
+	}
+}
+
+func TestRenderPersonalStaffMealSlips_Localized(t *testing.T) {
+	for _, l := range i18n.Supported {
+		out, err := RenderPersonalStaffMealSlips(PersonalSlipInput{
+			CampaignName: "Helferessen Samstag",
+			Slips: []PersonalSlip{
+				{Holder: "Anna Muster", QRPayload: "CAMP:tkn_slip___1", MaxRedemptions: 2, Number: 7},
+			},
+			Style: SlipStyle{Locale: l},
+		})
+		if err != nil {
+			t.Fatalf("%s: render: %v", l, err)
+		}
+		if !bytes.HasPrefix(out, []byte("%PDF-")) {
+			t.Fatalf("%s: output missing PDF magic bytes", l)
+		}
+	}
+}
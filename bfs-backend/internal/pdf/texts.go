@@ -0,0 +1,40 @@
+package pdf
+
+import "backend/internal/i18n"
+
+// printTexts are the fixed texts of slips and cards in one language.
+type printTexts struct {
+	slipInstruction string
+	cardInstruction string
+	validFor        string // %d: redemptions
+	number          string // %04d: slip number
+}
+
+var localizedTexts = map[i18n.Locale]printTexts{
+	i18n.German: {
+		slipInstruction: "An der Station vorzeigen",
+		cardInstruction: "An der Kasse vorzeigen",
+		validFor:        "Gültig für %d Bezüge",
+		number:          "Nr. %04d",
+	},
+	i18n.French: {
+		slipInstruction: "À présenter au stand",
+		cardInstruction: "À présenter à la caisse",
+		validFor:        "Valable pour %d retraits",
+		number:          "N° %04d",
+	},
+	i18n.English: {
+		slipInstruction: "Show at the station",
+		cardInstruction: "Show at the till",
+		validFor:        "Valid for %d servings",
+		number:          "No. %04d",
+	},
+}
+
+// textsFor returns the texts in l, or the German ones.
+func textsFor(l i18n.Locale) printTexts {
+	if t, ok := localizedTexts[l]; ok {
+		return t
+	}
+	return localizedTexts[i18n.Default]
+}
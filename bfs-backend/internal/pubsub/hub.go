@@ -0,0 +1,55 @@
+// Package pubsub fans in-process events out to server-sent event streams.
+package pubsub
+
+import "sync"
+
+// Hub delivers every published message to all current subscribers. A
+// subscriber that falls behind misses messages instead of blocking the
+// publisher.
+type Hub[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[string]chan T
+}
+
+func NewHub[T any]() *Hub[T] {
+	return &Hub[T]{
+		subscribers: make(map[string]chan T),
+	}
+}
+
+func (h *Hub[T]) Subscribe(id string) <-chan T {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan T, 64)
+	h.subscribers[id] = ch
+	return ch
+}
+
+func (h *Hub[T]) Unsubscribe(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if ch, ok := h.subscribers[id]; ok {
+		close(ch)
+		delete(h.subscribers, id)
+	}
+}
+
+func (h *Hub[T]) Publish(msg T) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, ch := range h.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+func (h *Hub[T]) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
@@ -0,0 +1,33 @@
+package pubsub
+
+import "testing"
+
+func TestHub(t *testing.T) {
+	h := NewHub[int]()
+	a := h.Subscribe("a")
+	b := h.Subscribe("b")
+
+	h.Publish(1)
+	if got := <-a; got != 1 {
+		t.Fatalf("a got %d", got)
+	}
+	if got := <-b; got != 1 {
+		t.Fatalf("b got %d", got)
+	}
+
+	h.Unsubscribe("a")
+	if _, ok := <-a; ok {
+		t.Fatal("unsubscribing closes the channel")
+	}
+	if n := h.SubscriberCount(); n != 1 {
+		t.Fatalf("SubscriberCount = %d", n)
+	}
+
+	// A full subscriber drops messages instead of blocking Publish.
+	for i := range 100 {
+		h.Publish(i)
+	}
+	if n := len(b); n != cap(b) {
+		t.Fatalf("buffered %d of %d", n, cap(b))
+	}
+}
@@ -0,0 +1,74 @@
+package qrpayload
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	s := NewSigner([]byte("test-secret"))
+	now := time.Unix(1_750_000_000, 0)
+	exp := now.Add(time.Hour)
+
+	raw := s.Sign(TypeOrder, "abcDEF123_-x", exp)
+	assert.True(t, IsSigned(raw))
+	assert.True(t, strings.HasPrefix(raw, "BFS1.O.abcDEF123_-x."))
+
+	p, err := s.Verify(raw, now)
+	require.NoError(t, err)
+	assert.Equal(t, TypeOrder, p.Type)
+	assert.Equal(t, "abcDEF123_-x", p.Subject)
+	assert.True(t, exp.Equal(p.ExpiresAt))
+
+	// Scanners sometimes append whitespace or a newline.
+	_, err = s.Verify(raw+"\n", now)
+	assert.NoError(t, err)
+
+	p, err = s.Verify(s.Sign(TypeMember, "tkn_card___1", exp), now)
+	require.NoError(t, err)
+	assert.Equal(t, TypeMember, p.Type)
+}
+
+func TestVerifyRejects(t *testing.T) {
+	s := NewSigner([]byte("test-secret"))
+	now := time.Unix(1_750_000_000, 0)
+	raw := s.Sign(TypeCampaign, "tkn_camp___1", now.Add(time.Minute))
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := s.Verify(raw, now.Add(time.Minute))
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("tampered subject", func(t *testing.T) {
+		forged := strings.Replace(raw, "tkn_camp___1", "tkn_camp___2", 1)
+		_, err := s.Verify(forged, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("tampered type", func(t *testing.T) {
+		forged := strings.Replace(raw, "BFS1.C.", "BFS1.O.", 1)
+		_, err := s.Verify(forged, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		_, err := NewSigner([]byte("other")).Verify(raw, now)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		assert.False(t, IsSigned("CAMP:tkn_camp___1"))
+		_, err := s.Verify("CAMP:tkn_camp___1", now)
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		forged := s.Sign(Type("X"), "abc", now.Add(time.Minute))
+		_, err := s.Verify(forged, now)
+		assert.ErrorIs(t, err, ErrUnknownType)
+	})
+}
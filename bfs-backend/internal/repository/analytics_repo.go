@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/orderline"
+	"backend/internal/generated/ent/orderlineredemption"
+	"backend/internal/generated/ent/orderpayment"
+	"backend/internal/generated/ent/redemptionbatch"
+	"backend/internal/generated/ent/redemptionbatchitem"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// AnalyticsRepository loads the raw payment and redemption timestamps the
+// station analytics are computed from.
+type AnalyticsRepository interface {
+	// ListRedemptions returns every unit handed out in [from, to) that has not
+	// been undone. Redemptions from before batches existed have no station.
+	ListRedemptions(ctx context.Context, from, to time.Time) ([]RedemptionRecord, error)
+	// FirstPaidBetween returns the first payment time of every order whose
+	// first payment falls into [from, to).
+	FirstPaidBetween(ctx context.Context, from, to time.Time) (map[string]time.Time, error)
+	// FirstPaidAt returns the first payment time of the given orders.
+	FirstPaidAt(ctx context.Context, orderIDs []string) (map[string]time.Time, error)
+	// ListRedeemableLines returns the non-bundle lines of the given orders with
+	// their parent line loaded, i.e. everything a station can hand out.
+	ListRedeemableLines(ctx context.Context, orderIDs []string) ([]*ent.OrderLine, error)
+	// OrdersOfEvent returns which of the given orders belong to the event.
+	OrdersOfEvent(ctx context.Context, eventID string, orderIDs []string) (map[string]bool, error)
+}
+
+// RedemptionRecord is one line's share of a redemption.
+type RedemptionRecord struct {
+	OrderID     string
+	OrderLineID string
+	ProductID   string
+	Title       string
+	StationID   *string
+	Quantity    int
+	RedeemedAt  time.Time
+}
+
+type analyticsRepo struct {
+	client *ent.Client
+}
+
+func NewAnalyticsRepository(client *ent.Client) AnalyticsRepository {
+	return &analyticsRepo{client: client}
+}
+
+func (r *analyticsRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *analyticsRepo) ListRedemptions(ctx context.Context, from, to time.Time) ([]RedemptionRecord, error) {
+	batches, err := r.ec(ctx).RedemptionBatch.Query().
+		Where(
+			redemptionbatch.CreatedAtGTE(from),
+			redemptionbatch.CreatedAtLT(to),
+			redemptionbatch.UndoneAtIsNil(),
+		).
+		WithItems(func(q *ent.RedemptionBatchItemQuery) {
+			q.WithOrderLine()
+		}).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var out []RedemptionRecord
+	for _, b := range batches {
+		for _, it := range b.Edges.Items {
+			line := it.Edges.OrderLine
+			if line == nil {
+				continue
+			}
+			out = append(out, RedemptionRecord{
+				OrderID:     b.OrderID,
+				OrderLineID: line.ID,
+				ProductID:   line.ProductID,
+				Title:       line.Title,
+				StationID:   b.StationDeviceID,
+				Quantity:    it.Quantity,
+				RedeemedAt:  b.CreatedAt,
+			})
+		}
+	}
+
+	// Lines redeemed before batches were recorded only carry the
+	// all-or-nothing redemption row.
+	legacy, err := r.ec(ctx).OrderLineRedemption.Query().
+		Where(
+			orderlineredemption.RedeemedAtGTE(from),
+			orderlineredemption.RedeemedAtLT(to),
+			func(s *sql.Selector) {
+				bi := sql.Table(redemptionbatchitem.Table)
+				s.Where(sql.NotIn(
+					s.C(orderlineredemption.FieldOrderLineID),
+					sql.Select(bi.C(redemptionbatchitem.FieldOrderLineID)).From(bi),
+				))
+			},
+		).
+		WithOrderLine().
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	for _, red := range legacy {
+		line := red.Edges.OrderLine
+		if line == nil || line.LineType == orderline.LineTypeBundle {
+			continue
+		}
+		out = append(out, RedemptionRecord{
+			OrderID:     line.OrderID,
+			OrderLineID: line.ID,
+			ProductID:   line.ProductID,
+			Title:       line.Title,
+			Quantity:    line.Quantity,
+			RedeemedAt:  red.RedeemedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *analyticsRepo) FirstPaidBetween(ctx context.Context, from, to time.Time) (map[string]time.Time, error) {
+	return r.firstPaid(ctx, func(s *sql.Selector) {
+		first := "MIN(" + s.C(orderpayment.FieldPaidAt) + ")"
+		s.Having(sql.And(sql.GTE(first, from), sql.LT(first, to)))
+	})
+}
+
+func (r *analyticsRepo) FirstPaidAt(ctx context.Context, orderIDs []string) (map[string]time.Time, error) {
+	if len(orderIDs) == 0 {
+		return map[string]time.Time{}, nil
+	}
+	return r.firstPaid(ctx, func(s *sql.Selector) {
+		args := make([]any, len(orderIDs))
+		for i, id := range orderIDs {
+			args[i] = id
+		}
+		s.Where(sql.In(s.C(orderpayment.FieldOrderID), args...))
+	})
+}
+
+func (r *analyticsRepo) firstPaid(ctx context.Context, filter func(*sql.Selector)) (map[string]time.Time, error) {
+	var rows []struct {
+		OrderID string    `json:"order_id"`
+		PaidAt  time.Time `json:"paid_at"`
+	}
+	err := r.ec(ctx).OrderPayment.Query().
+		Modify(func(s *sql.Selector) {
+			s.Select(
+				s.C(orderpayment.FieldOrderID),
+				sql.As("MIN("+s.C(orderpayment.FieldPaidAt)+")", "paid_at"),
+			)
+			s.GroupBy(s.C(orderpayment.FieldOrderID))
+			filter(s)
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	result := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		result[row.OrderID] = row.PaidAt
+	}
+	return result, nil
+}
+
+func (r *analyticsRepo) ListRedeemableLines(ctx context.Context, orderIDs []string) ([]*ent.OrderLine, error) {
+	if len(orderIDs) == 0 {
+		return []*ent.OrderLine{}, nil
+	}
+	rows, err := r.ec(ctx).OrderLine.Query().
+		Where(
+			orderline.OrderIDIn(orderIDs...),
+			orderline.LineTypeNEQ(orderline.LineTypeBundle),
+		).
+		WithParentLine().
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *analyticsRepo) OrdersOfEvent(ctx context.Context, eventID string, orderIDs []string) (map[string]bool, error) {
+	if len(orderIDs) == 0 {
+		return map[string]bool{}, nil
+	}
+	ids, err := r.ec(ctx).Order.Query().
+		Where(
+			order.IDIn(orderIDs...),
+			order.EventIDEQ(eventID),
+		).
+		IDs(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	result := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
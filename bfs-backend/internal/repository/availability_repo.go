@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/availabilitywindow"
+	"backend/internal/generated/ent/predicate"
+)
+
+// AvailabilityWindowInput is one window of a product or category. A nil Date
+// makes it weekly on Weekdays.
+type AvailabilityWindowInput struct {
+	Weekdays    int
+	Date        *string
+	StartMinute int
+	EndMinute   int
+}
+
+type AvailabilityRepository interface {
+	// ListAll returns the windows of all products and categories.
+	ListAll(ctx context.Context) ([]*ent.AvailabilityWindow, error)
+	ListForProduct(ctx context.Context, productID string) ([]*ent.AvailabilityWindow, error)
+	ListForCategory(ctx context.Context, categoryID string) ([]*ent.AvailabilityWindow, error)
+	// ReplaceForProduct swaps out the product's windows.
+	ReplaceForProduct(ctx context.Context, productID string, windows []AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error)
+	// ReplaceForCategory swaps out the category's windows.
+	ReplaceForCategory(ctx context.Context, categoryID string, windows []AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error)
+}
+
+type availabilityRepo struct {
+	client *ent.Client
+}
+
+func NewAvailabilityRepository(client *ent.Client) AvailabilityRepository {
+	return &availabilityRepo{client: client}
+}
+
+func (r *availabilityRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *availabilityRepo) ListAll(ctx context.Context) ([]*ent.AvailabilityWindow, error) {
+	rows, err := r.ec(ctx).AvailabilityWindow.Query().
+		Order(ent.Asc(availabilitywindow.FieldStartMinute), ent.Asc(availabilitywindow.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *availabilityRepo) ListForProduct(ctx context.Context, productID string) ([]*ent.AvailabilityWindow, error) {
+	return r.list(ctx, availabilitywindow.ProductID(productID))
+}
+
+func (r *availabilityRepo) ListForCategory(ctx context.Context, categoryID string) ([]*ent.AvailabilityWindow, error) {
+	return r.list(ctx, availabilitywindow.CategoryID(categoryID))
+}
+
+func (r *availabilityRepo) list(ctx context.Context, where ...predicate.AvailabilityWindow) ([]*ent.AvailabilityWindow, error) {
+	rows, err := r.ec(ctx).AvailabilityWindow.Query().
+		Where(where...).
+		Order(ent.Asc(availabilitywindow.FieldDate), ent.Asc(availabilitywindow.FieldStartMinute), ent.Asc(availabilitywindow.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *availabilityRepo) ReplaceForProduct(ctx context.Context, productID string, windows []AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error) {
+	return r.replace(ctx, availabilitywindow.ProductID(productID), windows, func(c *ent.AvailabilityWindowCreate) {
+		c.SetProductID(productID)
+	})
+}
+
+func (r *availabilityRepo) ReplaceForCategory(ctx context.Context, categoryID string, windows []AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error) {
+	return r.replace(ctx, availabilitywindow.CategoryID(categoryID), windows, func(c *ent.AvailabilityWindowCreate) {
+		c.SetCategoryID(categoryID)
+	})
+}
+
+func (r *availabilityRepo) replace(ctx context.Context, owner predicate.AvailabilityWindow, windows []AvailabilityWindowInput, scope func(*ent.AvailabilityWindowCreate)) ([]*ent.AvailabilityWindow, error) {
+	err := RunInTx(ctx, r.client, func(ctx context.Context) error {
+		client := r.ec(ctx)
+		if _, err := client.AvailabilityWindow.Delete().Where(owner).Exec(ctx); err != nil {
+			return translateError(err)
+		}
+		if len(windows) == 0 {
+			return nil
+		}
+		builders := make([]*ent.AvailabilityWindowCreate, len(windows))
+		for i, w := range windows {
+			builders[i] = client.AvailabilityWindow.Create().
+				SetWeekdays(w.Weekdays).
+				SetNillableDate(w.Date).
+				SetStartMinute(w.StartMinute).
+				SetEndMinute(w.EndMinute)
+			scope(builders[i])
+		}
+		if _, err := client.AvailabilityWindow.CreateBulk(builders...).Save(ctx); err != nil {
+			return translateError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.list(ctx, owner)
+}
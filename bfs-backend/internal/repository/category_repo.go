@@ -5,6 +5,7 @@ import (
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/category"
+	"backend/internal/i18n"
 )
 
 type CategoryRepository interface {
@@ -14,6 +15,8 @@ type CategoryRepository interface {
 	GetAllActive(ctx context.Context) ([]*ent.Category, error)
 	List(ctx context.Context, limit, offset int) ([]*ent.Category, int64, error)
 	Update(ctx context.Context, id string, name string, position int, isActive bool) (*ent.Category, error)
+	// UpdateTranslations replaces the translated names of a category.
+	UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Category, error)
 	Delete(ctx context.Context, id string) error
 }
 
@@ -102,6 +105,16 @@ func (r *categoryRepo) Update(ctx context.Context, id string, name string, posit
 	return updated, nil
 }
 
+func (r *categoryRepo) UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Category, error) {
+	updated, err := r.ec(ctx).Category.UpdateOneID(id).
+		SetTranslations(translations).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
+}
+
 func (r *categoryRepo) Delete(ctx context.Context, id string) error {
 	err := r.ec(ctx).Category.DeleteOneID(id).Exec(ctx)
 	return translateError(err)
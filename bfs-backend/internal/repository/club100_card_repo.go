@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/club100card"
+	"backend/internal/generated/ent/club100member"
+)
+
+type Club100CardRepository interface {
+	// Issue revokes the member's current card, if any, and creates a new one.
+	// Run it in a transaction so the member never ends up without a card.
+	Issue(ctx context.Context, memberID string) (*ent.Club100Card, error)
+	// GetByToken returns the card with its member, revoked or not.
+	GetByToken(ctx context.Context, token string) (*ent.Club100Card, error)
+	// ListCurrent returns the cards that are not revoked, with their members,
+	// ordered by member name.
+	ListCurrent(ctx context.Context) ([]*ent.Club100Card, error)
+	// RevokeForMember revokes the member's current card and reports whether
+	// there was one.
+	RevokeForMember(ctx context.Context, memberID string) (bool, error)
+}
+
+type club100CardRepo struct {
+	client *ent.Client
+}
+
+func NewClub100CardRepository(client *ent.Client) Club100CardRepository {
+	return &club100CardRepo{client: client}
+}
+
+func (r *club100CardRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *club100CardRepo) Issue(ctx context.Context, memberID string) (*ent.Club100Card, error) {
+	if _, err := r.RevokeForMember(ctx, memberID); err != nil {
+		return nil, err
+	}
+	created, err := r.ec(ctx).Club100Card.Create().
+		SetMemberID(memberID).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
+func (r *club100CardRepo) GetByToken(ctx context.Context, token string) (*ent.Club100Card, error) {
+	card, err := r.ec(ctx).Club100Card.Query().
+		Where(club100card.TokenEQ(token)).
+		WithMember().
+		Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return card, nil
+}
+
+func (r *club100CardRepo) ListCurrent(ctx context.Context) ([]*ent.Club100Card, error) {
+	rows, err := r.ec(ctx).Club100Card.Query().
+		Where(club100card.RevokedAtIsNil()).
+		WithMember().
+		Order(club100card.ByMemberField(club100member.FieldLastName), club100card.ByMemberField(club100member.FieldFirstName)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *club100CardRepo) RevokeForMember(ctx context.Context, memberID string) (bool, error) {
+	n, err := r.ec(ctx).Club100Card.Update().
+		Where(
+			club100card.MemberIDEQ(memberID),
+			club100card.RevokedAtIsNil(),
+		).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/club100member"
+	"backend/internal/generated/ent/club100syncrun"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type Club100MemberInput struct {
+	// ExternalPersonID is the person's ID at Provider.
+	ExternalPersonID string
+	Provider         string
+	FirstName        string
+	LastName         string
+	PreferredName    *string
+}
+
+// Club100SyncResult counts what a directory sync changed. Added includes
+// members who rejoined the group.
+type Club100SyncResult struct {
+	Added   int
+	Removed int
+}
+
+type Club100MemberRepository interface {
+	// ListActive returns provider's current members ordered by last and first
+	// name.
+	ListActive(ctx context.Context, provider string) ([]*ent.Club100Member, error)
+	// GetByExternalID finds the person with externalPersonID at provider.
+	GetByExternalID(ctx context.Context, provider, externalPersonID string) (*ent.Club100Member, error)
+	// Lock takes a row lock on the member until the surrounding transaction
+	// ends, serializing redemptions for the same person. ErrNotFound when the
+	// person is not in the directory.
+	Lock(ctx context.Context, provider, externalPersonID string) error
+	// ReplaceAll makes the directory match members: new people are added,
+	// known ones updated and everyone else deactivated. People are matched
+	// by provider and ID, so members of another provider are never merged
+	// into them. Run it in a transaction.
+	ReplaceAll(ctx context.Context, members []Club100MemberInput, syncedAt time.Time) (Club100SyncResult, error)
+
+	CreateSyncRun(ctx context.Context, trigger club100syncrun.Trigger, provider string) (*ent.Club100SyncRun, error)
+	FinishSyncRun(ctx context.Context, id string, status club100syncrun.Status, memberCount int, result Club100SyncResult, errMsg *string) (*ent.Club100SyncRun, error)
+	// LatestSyncRun returns the most recent run, optionally only those with
+	// the given status; ErrNotFound when there is none.
+	LatestSyncRun(ctx context.Context, status *club100syncrun.Status) (*ent.Club100SyncRun, error)
+}
+
+// club100MemberKey identifies a person across providers.
+type club100MemberKey struct {
+	provider, externalPersonID string
+}
+
+type club100MemberRepo struct {
+	client *ent.Client
+}
+
+func NewClub100MemberRepository(client *ent.Client) Club100MemberRepository {
+	return &club100MemberRepo{client: client}
+}
+
+func (r *club100MemberRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *club100MemberRepo) ListActive(ctx context.Context, provider string) ([]*ent.Club100Member, error) {
+	rows, err := r.ec(ctx).Club100Member.Query().
+		Where(
+			club100member.ProviderEQ(provider),
+			club100member.ActiveEQ(true),
+		).
+		Order(club100member.ByLastName(), club100member.ByFirstName()).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *club100MemberRepo) GetByExternalID(ctx context.Context, provider, externalPersonID string) (*ent.Club100Member, error) {
+	row, err := r.ec(ctx).Club100Member.Query().
+		Where(
+			club100member.ProviderEQ(provider),
+			club100member.ExternalPersonIDEQ(externalPersonID),
+		).
+		Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
+
+func (r *club100MemberRepo) Lock(ctx context.Context, provider, externalPersonID string) error {
+	var rows []struct {
+		ID string `json:"id"`
+	}
+	err := r.ec(ctx).Club100Member.Query().
+		Where(
+			club100member.ProviderEQ(provider),
+			club100member.ExternalPersonIDEQ(externalPersonID),
+		).
+		Modify(func(s *sql.Selector) {
+			s.Select(s.C(club100member.FieldID)).ForUpdate()
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return translateError(err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *club100MemberRepo) ReplaceAll(ctx context.Context, members []Club100MemberInput, syncedAt time.Time) (Club100SyncResult, error) {
+	var result Club100SyncResult
+	client := r.ec(ctx)
+
+	existing, err := client.Club100Member.Query().All(ctx)
+	if err != nil {
+		return result, translateError(err)
+	}
+	known := make(map[club100MemberKey]*ent.Club100Member, len(existing))
+	for _, m := range existing {
+		known[club100MemberKey{m.Provider, m.ExternalPersonID}] = m
+	}
+
+	seen := make(map[club100MemberKey]struct{}, len(members))
+	var creates []*ent.Club100MemberCreate
+	for _, in := range members {
+		key := club100MemberKey{in.Provider, in.ExternalPersonID}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		m, ok := known[key]
+		if !ok {
+			creates = append(creates, client.Club100Member.Create().
+				SetExternalPersonID(in.ExternalPersonID).
+				SetProvider(in.Provider).
+				SetFirstName(in.FirstName).
+				SetLastName(in.LastName).
+				SetNillablePreferredName(in.PreferredName).
+				SetSyncedAt(syncedAt))
+			result.Added++
+			continue
+		}
+		if !m.Active {
+			result.Added++
+		}
+		upd := client.Club100Member.UpdateOneID(m.ID).
+			SetFirstName(in.FirstName).
+			SetLastName(in.LastName).
+			SetActive(true).
+			SetSyncedAt(syncedAt)
+		if in.PreferredName != nil {
+			upd.SetPreferredName(*in.PreferredName)
+		} else {
+			upd.ClearPreferredName()
+		}
+		if _, err := upd.Save(ctx); err != nil {
+			return result, translateError(err)
+		}
+	}
+	if len(creates) > 0 {
+		if err := client.Club100Member.CreateBulk(creates...).Exec(ctx); err != nil {
+			return result, translateError(err)
+		}
+	}
+
+	var gone []string
+	for _, m := range existing {
+		if _, ok := seen[club100MemberKey{m.Provider, m.ExternalPersonID}]; !ok && m.Active {
+			gone = append(gone, m.ID)
+		}
+	}
+	if len(gone) > 0 {
+		n, err := client.Club100Member.Update().
+			Where(club100member.IDIn(gone...)).
+			SetActive(false).
+			Save(ctx)
+		if err != nil {
+			return result, translateError(err)
+		}
+		result.Removed = n
+	}
+	return result, nil
+}
+
+func (r *club100MemberRepo) CreateSyncRun(ctx context.Context, trigger club100syncrun.Trigger, provider string) (*ent.Club100SyncRun, error) {
+	row, err := r.ec(ctx).Club100SyncRun.Create().
+		SetTrigger(trigger).
+		SetProvider(provider).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
+
+func (r *club100MemberRepo) FinishSyncRun(ctx context.Context, id string, status club100syncrun.Status, memberCount int, result Club100SyncResult, errMsg *string) (*ent.Club100SyncRun, error) {
+	row, err := r.ec(ctx).Club100SyncRun.UpdateOneID(id).
+		SetStatus(status).
+		SetMemberCount(memberCount).
+		SetAddedCount(result.Added).
+		SetRemovedCount(result.Removed).
+		SetNillableError(errMsg).
+		SetFinishedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
+
+func (r *club100MemberRepo) LatestSyncRun(ctx context.Context, status *club100syncrun.Status) (*ent.Club100SyncRun, error) {
+	q := r.ec(ctx).Club100SyncRun.Query()
+	if status != nil {
+		q.Where(club100syncrun.StatusEQ(*status))
+	}
+	row, err := q.Order(club100syncrun.ByStartedAt(entDescOpt())).First(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
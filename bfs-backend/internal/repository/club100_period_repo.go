@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/club100period"
+)
+
+type Club100PeriodRepository interface {
+	Create(ctx context.Context, name string, startsAt, endsAt time.Time) (*ent.Club100Period, error)
+	GetByID(ctx context.Context, id string) (*ent.Club100Period, error)
+	// List returns all periods, latest first.
+	List(ctx context.Context) ([]*ent.Club100Period, error)
+	// GetActiveAt returns the period containing t, or ErrNotFound.
+	GetActiveAt(ctx context.Context, t time.Time) (*ent.Club100Period, error)
+	// Overlapping reports whether another period than excludeID intersects
+	// [startsAt, endsAt).
+	Overlapping(ctx context.Context, startsAt, endsAt time.Time, excludeID string) (bool, error)
+	Update(ctx context.Context, id string, name string, startsAt, endsAt time.Time) (*ent.Club100Period, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type club100PeriodRepo struct {
+	client *ent.Client
+}
+
+func NewClub100PeriodRepository(client *ent.Client) Club100PeriodRepository {
+	return &club100PeriodRepo{client: client}
+}
+
+func (r *club100PeriodRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *club100PeriodRepo) Create(ctx context.Context, name string, startsAt, endsAt time.Time) (*ent.Club100Period, error) {
+	created, err := r.ec(ctx).Club100Period.Create().
+		SetName(name).
+		SetStartsAt(startsAt).
+		SetEndsAt(endsAt).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
+func (r *club100PeriodRepo) GetByID(ctx context.Context, id string) (*ent.Club100Period, error) {
+	e, err := r.ec(ctx).Club100Period.Get(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *club100PeriodRepo) List(ctx context.Context) ([]*ent.Club100Period, error) {
+	rows, err := r.ec(ctx).Club100Period.Query().
+		Order(club100period.ByStartsAt(entDescOpt())).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *club100PeriodRepo) GetActiveAt(ctx context.Context, t time.Time) (*ent.Club100Period, error) {
+	e, err := r.ec(ctx).Club100Period.Query().
+		Where(
+			club100period.StartsAtLTE(t),
+			club100period.EndsAtGT(t),
+		).
+		Order(club100period.ByStartsAt(entDescOpt())).
+		First(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *club100PeriodRepo) Overlapping(ctx context.Context, startsAt, endsAt time.Time, excludeID string) (bool, error) {
+	q := r.ec(ctx).Club100Period.Query().
+		Where(
+			club100period.StartsAtLT(endsAt),
+			club100period.EndsAtGT(startsAt),
+		)
+	if excludeID != "" {
+		q.Where(club100period.IDNEQ(excludeID))
+	}
+	exists, err := q.Exist(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return exists, nil
+}
+
+func (r *club100PeriodRepo) Update(ctx context.Context, id string, name string, startsAt, endsAt time.Time) (*ent.Club100Period, error) {
+	updated, err := r.ec(ctx).Club100Period.UpdateOneID(id).
+		SetName(name).
+		SetStartsAt(startsAt).
+		SetEndsAt(endsAt).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
+}
+
+func (r *club100PeriodRepo) Delete(ctx context.Context, id string) error {
+	return translateError(r.ec(ctx).Club100Period.DeleteOneID(id).Exec(ctx))
+}
@@ -10,10 +10,23 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// Club100RedemptionTotal sums one member's redemptions over a time range.
+type Club100RedemptionTotal struct {
+	Provider         string
+	ExternalPersonID string
+	PersonName       string
+	Quantity         int
+	Orders           int
+}
+
+// Totals count redemptions created in [from, to). A person is identified by
+// the membership provider and their ID there.
 type Club100RedemptionRepository interface {
-	Create(ctx context.Context, elvantoPersonID, elvantoPersonName string, orderID string, quantity int) (*ent.Club100Redemption, error)
-	GetTotalRedemptions(ctx context.Context, elvantoPersonID string) (int, error)
-	GetTotalRedemptionsBatch(ctx context.Context, elvantoPersonIDs []string) (map[string]int, error)
+	Create(ctx context.Context, provider, externalPersonID, personName string, orderID string, eventID *string, quantity int) (*ent.Club100Redemption, error)
+	GetTotalRedemptions(ctx context.Context, provider, externalPersonID string, from, to time.Time) (int, error)
+	GetTotalRedemptionsBatch(ctx context.Context, provider string, externalPersonIDs []string, from, to time.Time) (map[string]int, error)
+	// SummarizeByPerson totals every member's redemptions, most first.
+	SummarizeByPerson(ctx context.Context, from, to time.Time) ([]Club100RedemptionTotal, error)
 	GetByOrderID(ctx context.Context, orderID string) ([]*ent.Club100Redemption, error)
 }
 
@@ -29,12 +42,14 @@ func (r *club100RedemptionRepo) ec(ctx context.Context) *ent.Client {
 	return ClientFromContext(ctx, r.client)
 }
 
-func (r *club100RedemptionRepo) Create(ctx context.Context, elvantoPersonID, elvantoPersonName string, orderID string, quantity int) (*ent.Club100Redemption, error) {
+func (r *club100RedemptionRepo) Create(ctx context.Context, provider, externalPersonID, personName string, orderID string, eventID *string, quantity int) (*ent.Club100Redemption, error) {
 	e, err := r.ec(ctx).Club100Redemption.Create().
-		SetElvantoPersonID(elvantoPersonID).
-		SetElvantoPersonName(elvantoPersonName).
+		SetProvider(provider).
+		SetExternalPersonID(externalPersonID).
+		SetPersonName(personName).
 		SetOrderID(orderID).
 		SetFreeProductQuantity(quantity).
+		SetNillableEventID(eventID).
 		Save(ctx)
 	if err != nil {
 		return nil, translateError(err)
@@ -42,17 +57,13 @@ func (r *club100RedemptionRepo) Create(ctx context.Context, elvantoPersonID, elv
 	return e, nil
 }
 
-func (r *club100RedemptionRepo) GetTotalRedemptions(ctx context.Context, elvantoPersonID string) (int, error) {
-	loc, _ := time.LoadLocation("Europe/Zurich")
-	now := time.Now().In(loc)
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
-	dayEnd := dayStart.AddDate(0, 0, 1)
-
+func (r *club100RedemptionRepo) GetTotalRedemptions(ctx context.Context, provider, externalPersonID string, from, to time.Time) (int, error) {
 	rows, err := r.ec(ctx).Club100Redemption.Query().
 		Where(
-			club100redemption.ElvantoPersonIDEQ(elvantoPersonID),
-			club100redemption.CreatedAtGTE(dayStart),
-			club100redemption.CreatedAtLT(dayEnd),
+			club100redemption.ProviderEQ(provider),
+			club100redemption.ExternalPersonIDEQ(externalPersonID),
+			club100redemption.CreatedAtGTE(from),
+			club100redemption.CreatedAtLT(to),
 		).
 		All(ctx)
 	if err != nil {
@@ -65,32 +76,28 @@ func (r *club100RedemptionRepo) GetTotalRedemptions(ctx context.Context, elvanto
 	return total, nil
 }
 
-func (r *club100RedemptionRepo) GetTotalRedemptionsBatch(ctx context.Context, elvantoPersonIDs []string) (map[string]int, error) {
-	result := make(map[string]int, len(elvantoPersonIDs))
-	if len(elvantoPersonIDs) == 0 {
+func (r *club100RedemptionRepo) GetTotalRedemptionsBatch(ctx context.Context, provider string, externalPersonIDs []string, from, to time.Time) (map[string]int, error) {
+	result := make(map[string]int, len(externalPersonIDs))
+	if len(externalPersonIDs) == 0 {
 		return result, nil
 	}
 
-	loc, _ := time.LoadLocation("Europe/Zurich")
-	now := time.Now().In(loc)
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
-	dayEnd := dayStart.AddDate(0, 0, 1)
-
 	var rows []struct {
-		ElvantoPersonID string `json:"elvanto_person_id"`
-		Total           int    `json:"total"`
+		ExternalPersonID string `json:"external_person_id"`
+		Total            int    `json:"total"`
 	}
 	err := r.ec(ctx).Club100Redemption.Query().
 		Where(
-			club100redemption.ElvantoPersonIDIn(elvantoPersonIDs...),
-			club100redemption.CreatedAtGTE(dayStart),
-			club100redemption.CreatedAtLT(dayEnd),
+			club100redemption.ProviderEQ(provider),
+			club100redemption.ExternalPersonIDIn(externalPersonIDs...),
+			club100redemption.CreatedAtGTE(from),
+			club100redemption.CreatedAtLT(to),
 		).
 		Modify(func(s *sql.Selector) {
 			s.Select(
-				s.C(club100redemption.FieldElvantoPersonID),
+				s.C(club100redemption.FieldExternalPersonID),
 				sql.As(sql.Sum(s.C(club100redemption.FieldFreeProductQuantity)), "total"),
-			).GroupBy(s.C(club100redemption.FieldElvantoPersonID))
+			).GroupBy(s.C(club100redemption.FieldExternalPersonID))
 		}).
 		Scan(ctx, &rows)
 	if err != nil {
@@ -98,11 +105,53 @@ func (r *club100RedemptionRepo) GetTotalRedemptionsBatch(ctx context.Context, el
 	}
 
 	for _, row := range rows {
-		result[row.ElvantoPersonID] = row.Total
+		result[row.ExternalPersonID] = row.Total
 	}
 	return result, nil
 }
 
+func (r *club100RedemptionRepo) SummarizeByPerson(ctx context.Context, from, to time.Time) ([]Club100RedemptionTotal, error) {
+	var rows []struct {
+		Provider         string `json:"provider"`
+		ExternalPersonID string `json:"external_person_id"`
+		PersonName       string `json:"name"`
+		Total            int    `json:"total"`
+		Orders           int    `json:"orders"`
+	}
+	err := r.ec(ctx).Club100Redemption.Query().
+		Where(
+			club100redemption.CreatedAtGTE(from),
+			club100redemption.CreatedAtLT(to),
+		).
+		Modify(func(s *sql.Selector) {
+			s.Select(
+				s.C(club100redemption.FieldProvider),
+				s.C(club100redemption.FieldExternalPersonID),
+				sql.As(sql.Max(s.C(club100redemption.FieldPersonName)), "name"),
+				sql.As(sql.Sum(s.C(club100redemption.FieldFreeProductQuantity)), "total"),
+				sql.As(sql.Count(sql.Distinct(s.C(club100redemption.FieldOrderID))), "orders"),
+			).
+				GroupBy(s.C(club100redemption.FieldProvider), s.C(club100redemption.FieldExternalPersonID)).
+				OrderBy(sql.Desc("total"), sql.Asc("name"))
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	out := make([]Club100RedemptionTotal, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, Club100RedemptionTotal{
+			Provider:         row.Provider,
+			ExternalPersonID: row.ExternalPersonID,
+			PersonName:       row.PersonName,
+			Quantity:         row.Total,
+			Orders:           row.Orders,
+		})
+	}
+	return out, nil
+}
+
 func (r *club100RedemptionRepo) GetByOrderID(ctx context.Context, orderID string) ([]*ent.Club100Redemption, error) {
 	rows, err := r.ec(ctx).Club100Redemption.Query().
 		Where(club100redemption.OrderIDEQ(orderID)).
@@ -14,6 +14,8 @@ type DeviceProductRepository interface {
 	Delete(ctx context.Context, deviceID, productID string) error
 	DeleteByDeviceID(ctx context.Context, deviceID string) error
 	ReplaceForDevice(ctx context.Context, deviceID string, productIDs []string) error
+	// ReplaceForProduct routes the product to exactly the given stations.
+	ReplaceForProduct(ctx context.Context, productID string, deviceIDs []string) error
 }
 
 type deviceProductRepo struct {
@@ -128,3 +130,24 @@ func (r *deviceProductRepo) ReplaceForDevice(ctx context.Context, deviceID strin
 
 	return tx.Commit()
 }
+
+func (r *deviceProductRepo) ReplaceForProduct(ctx context.Context, productID string, deviceIDs []string) error {
+	return RunInTx(ctx, r.client, func(ctx context.Context) error {
+		ec := r.ec(ctx)
+		if _, err := ec.DeviceProduct.Delete().
+			Where(entDeviceProductProductID(productID)).
+			Exec(ctx); err != nil {
+			return translateError(err)
+		}
+		if len(deviceIDs) == 0 {
+			return nil
+		}
+		builders := make([]*ent.DeviceProductCreate, len(deviceIDs))
+		for i, id := range deviceIDs {
+			builders[i] = ec.DeviceProduct.Create().
+				SetDeviceID(id).
+				SetProductID(productID)
+		}
+		return translateError(ec.DeviceProduct.CreateBulk(builders...).Exec(ctx))
+	})
+}
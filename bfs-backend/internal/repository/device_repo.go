@@ -10,6 +10,15 @@ import (
 	"backend/internal/generated/ent/device"
 )
 
+// DevicePosConfig holds a POS device's configuration overrides; nil fields
+// inherit the global settings.
+type DevicePosConfig struct {
+	PosMode            *device.PosMode
+	PaymentMethods     []string
+	VisibleCategoryIDs []string
+	GratisTypes        []string
+}
+
 type DeviceRepository interface {
 	Create(ctx context.Context, name, deviceKey string, deviceType device.Type, status device.Status, model *string, os *string, decidedBy *string, decidedAt, expiresAt *time.Time, pendingSessionToken, pairingCode *string, pairingCodeExpiresAt *time.Time) (*ent.Device, error)
 	GetByID(ctx context.Context, id string) (*ent.Device, error)
@@ -27,6 +36,7 @@ type DeviceRepository interface {
 	GeneratePairingCode(ctx context.Context, name, deviceModel, os, deviceKey string, deviceType device.Type) (*ent.Device, error)
 	GetByPairingCode(ctx context.Context, code string) (*ent.Device, error)
 	ClearPairingCode(ctx context.Context, deviceID string) error
+	SetPosConfig(ctx context.Context, deviceID string, cfg DevicePosConfig) (*ent.Device, error)
 }
 
 type deviceRepo struct {
@@ -354,3 +364,32 @@ func (r *deviceRepo) ClearPairingCode(ctx context.Context, deviceID string) erro
 		Save(ctx)
 	return translateError(err)
 }
+
+func (r *deviceRepo) SetPosConfig(ctx context.Context, deviceID string, cfg DevicePosConfig) (*ent.Device, error) {
+	upd := r.ec(ctx).Device.UpdateOneID(deviceID)
+	if cfg.PosMode != nil {
+		upd.SetPosMode(*cfg.PosMode)
+	} else {
+		upd.ClearPosMode()
+	}
+	if cfg.PaymentMethods != nil {
+		upd.SetPaymentMethods(cfg.PaymentMethods)
+	} else {
+		upd.ClearPaymentMethods()
+	}
+	if cfg.VisibleCategoryIDs != nil {
+		upd.SetVisibleCategoryIds(cfg.VisibleCategoryIDs)
+	} else {
+		upd.ClearVisibleCategoryIds()
+	}
+	if cfg.GratisTypes != nil {
+		upd.SetGratisTypes(cfg.GratisTypes)
+	} else {
+		upd.ClearGratisTypes()
+	}
+	updated, err := upd.Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
+}
@@ -10,6 +10,9 @@ import (
 var (
 	ErrNotFound = errors.New("record not found")
 	ErrConflict = errors.New("conflict: record already exists")
+	// ErrOrderNotPending is returned when a payment is recorded for an order
+	// that another request has already paid or cancelled.
+	ErrOrderNotPending = errors.New("not_pending")
 )
 
 func translateError(err error) error {
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/event"
+	"backend/internal/generated/ent/eventproduct"
+)
+
+// EventProductInput overrides one product for an event. A nil PriceCents
+// keeps the base price.
+type EventProductInput struct {
+	ProductID  string
+	Available  bool
+	PriceCents *int64
+}
+
+type EventRepository interface {
+	Create(ctx context.Context, name string, startsAt, endsAt time.Time) (*ent.Event, error)
+	GetByID(ctx context.Context, id string) (*ent.Event, error)
+	// List returns all events, latest first.
+	List(ctx context.Context) ([]*ent.Event, error)
+	// GetActive returns the active event, or ErrNotFound.
+	GetActive(ctx context.Context) (*ent.Event, error)
+	Update(ctx context.Context, id string, name string, startsAt, endsAt time.Time) (*ent.Event, error)
+	// SetActive activates or deactivates an event. Activating deactivates
+	// every other event; run it in a transaction.
+	SetActive(ctx context.Context, id string, active bool) error
+	// InUse reports whether orders, ledger entries or Club100 redemptions are
+	// tagged with the event.
+	InUse(ctx context.Context, id string) (bool, error)
+	Delete(ctx context.Context, id string) error
+
+	ListProducts(ctx context.Context, eventID string) ([]*ent.EventProduct, error)
+	// ReplaceProducts swaps the event's overrides for the given ones.
+	ReplaceProducts(ctx context.Context, eventID string, products []EventProductInput) error
+}
+
+type eventRepo struct {
+	client *ent.Client
+}
+
+func NewEventRepository(client *ent.Client) EventRepository {
+	return &eventRepo{client: client}
+}
+
+func (r *eventRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *eventRepo) Create(ctx context.Context, name string, startsAt, endsAt time.Time) (*ent.Event, error) {
+	created, err := r.ec(ctx).Event.Create().
+		SetName(name).
+		SetStartsAt(startsAt).
+		SetEndsAt(endsAt).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
+func (r *eventRepo) GetByID(ctx context.Context, id string) (*ent.Event, error) {
+	e, err := r.ec(ctx).Event.Get(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *eventRepo) List(ctx context.Context) ([]*ent.Event, error) {
+	rows, err := r.ec(ctx).Event.Query().
+		Order(event.ByStartsAt(entDescOpt())).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *eventRepo) GetActive(ctx context.Context) (*ent.Event, error) {
+	e, err := r.ec(ctx).Event.Query().
+		Where(event.Active(true)).
+		Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *eventRepo) Update(ctx context.Context, id string, name string, startsAt, endsAt time.Time) (*ent.Event, error) {
+	updated, err := r.ec(ctx).Event.UpdateOneID(id).
+		SetName(name).
+		SetStartsAt(startsAt).
+		SetEndsAt(endsAt).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
+}
+
+func (r *eventRepo) SetActive(ctx context.Context, id string, active bool) error {
+	if active {
+		if _, err := r.ec(ctx).Event.Update().
+			Where(event.Active(true), event.IDNEQ(id)).
+			SetActive(false).
+			Save(ctx); err != nil {
+			return translateError(err)
+		}
+	}
+	_, err := r.ec(ctx).Event.UpdateOneID(id).
+		SetActive(active).
+		Save(ctx)
+	return translateError(err)
+}
+
+func (r *eventRepo) InUse(ctx context.Context, id string) (bool, error) {
+	used, err := r.ec(ctx).Event.Query().
+		Where(
+			event.ID(id),
+			event.Or(
+				event.HasOrders(),
+				event.HasInventoryLedgerEntries(),
+				event.HasClub100Redemptions(),
+			),
+		).
+		Exist(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return used, nil
+}
+
+func (r *eventRepo) Delete(ctx context.Context, id string) error {
+	return translateError(r.ec(ctx).Event.DeleteOneID(id).Exec(ctx))
+}
+
+func (r *eventRepo) ListProducts(ctx context.Context, eventID string) ([]*ent.EventProduct, error) {
+	rows, err := r.ec(ctx).EventProduct.Query().
+		Where(eventproduct.EventIDEQ(eventID)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *eventRepo) ReplaceProducts(ctx context.Context, eventID string, products []EventProductInput) error {
+	ec := r.ec(ctx)
+	if _, err := ec.EventProduct.Delete().
+		Where(eventproduct.EventIDEQ(eventID)).
+		Exec(ctx); err != nil {
+		return translateError(err)
+	}
+	if len(products) == 0 {
+		return nil
+	}
+	builders := make([]*ent.EventProductCreate, 0, len(products))
+	for _, p := range products {
+		builders = append(builders, ec.EventProduct.Create().
+			SetEventID(eventID).
+			SetProductID(p.ProductID).
+			SetAvailable(p.Available).
+			SetNillablePriceCents(p.PriceCents))
+	}
+	return translateError(ec.EventProduct.CreateBulk(builders...).Exec(ctx))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/deviceproduct"
@@ -29,6 +30,25 @@ func ClientFromContext(ctx context.Context, fallback *ent.Client) *ent.Client {
 	return fallback
 }
 
+// RunInTx runs fn in a transaction. When ctx already carries a transactional
+// client fn joins that transaction and the outermost caller commits;
+// otherwise a new transaction is started on client and committed when fn
+// returns nil.
+func RunInTx(ctx context.Context, client *ent.Client, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txClientKey{}).(*ent.Client); ok {
+		return fn(ctx)
+	}
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(ContextWithClient(ctx, tx.Client())); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // entDescOpt returns an sql.OrderTermOption that sorts in descending order.
 func entDescOpt() sql.OrderTermOption {
 	return sql.OrderDesc()
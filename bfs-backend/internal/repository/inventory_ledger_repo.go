@@ -11,7 +11,7 @@ import (
 )
 
 type InventoryLedgerRepository interface {
-	Create(ctx context.Context, productID string, delta int, reason inventoryledger.Reason, orderID, orderLineID, deviceID *string, createdBy *string) (*ent.InventoryLedger, error)
+	Create(ctx context.Context, productID string, delta int, reason inventoryledger.Reason, orderID, orderLineID, deviceID, eventID *string, createdBy *string) (*ent.InventoryLedger, error)
 	CreateMany(ctx context.Context, entries []InventoryLedgerCreateParams) ([]*ent.InventoryLedger, error)
 	GetByID(ctx context.Context, id string) (*ent.InventoryLedger, error)
 	GetByProductID(ctx context.Context, productID string) ([]*ent.InventoryLedger, error)
@@ -19,6 +19,8 @@ type InventoryLedgerRepository interface {
 	GetByDateRange(ctx context.Context, start, end time.Time) ([]*ent.InventoryLedger, error)
 	GetCurrentStock(ctx context.Context, productID string) (int, error)
 	GetCurrentStockBatch(ctx context.Context, productIDs []string) (map[string]int, error)
+	// GetVariantStockBatch sums the entries of each variant.
+	GetVariantStockBatch(ctx context.Context, variantIDs []string) (map[string]int, error)
 	SumByProductIDs(ctx context.Context, ids []string) (map[string]int64, error)
 }
 
@@ -30,6 +32,8 @@ type InventoryLedgerCreateParams struct {
 	OrderID     *string
 	OrderLineID *string
 	DeviceID    *string
+	EventID     *string
+	VariantID   *string
 	CreatedBy   *string
 }
 
@@ -45,7 +49,7 @@ func (r *inventoryLedgerRepo) ec(ctx context.Context) *ent.Client {
 	return ClientFromContext(ctx, r.client)
 }
 
-func (r *inventoryLedgerRepo) Create(ctx context.Context, productID string, delta int, reason inventoryledger.Reason, orderID, orderLineID, deviceID *string, createdBy *string) (*ent.InventoryLedger, error) {
+func (r *inventoryLedgerRepo) Create(ctx context.Context, productID string, delta int, reason inventoryledger.Reason, orderID, orderLineID, deviceID, eventID *string, createdBy *string) (*ent.InventoryLedger, error) {
 	builder := r.ec(ctx).InventoryLedger.Create().
 		SetProductID(productID).
 		SetDelta(delta).
@@ -59,6 +63,9 @@ func (r *inventoryLedgerRepo) Create(ctx context.Context, productID string, delt
 	if deviceID != nil {
 		builder.SetDeviceID(*deviceID)
 	}
+	if eventID != nil {
+		builder.SetEventID(*eventID)
+	}
 	if createdBy != nil {
 		builder.SetCreatedBy(*createdBy)
 	}
@@ -88,6 +95,10 @@ func (r *inventoryLedgerRepo) CreateMany(ctx context.Context, entries []Inventor
 		if entry.DeviceID != nil {
 			b.SetDeviceID(*entry.DeviceID)
 		}
+		if entry.EventID != nil {
+			b.SetEventID(*entry.EventID)
+		}
+		b.SetNillableVariantID(entry.VariantID)
 		if entry.CreatedBy != nil {
 			b.SetCreatedBy(*entry.CreatedBy)
 		}
@@ -201,6 +212,38 @@ func (r *inventoryLedgerRepo) GetCurrentStockBatch(ctx context.Context, productI
 	return stocks, nil
 }
 
+func (r *inventoryLedgerRepo) GetVariantStockBatch(ctx context.Context, variantIDs []string) (map[string]int, error) {
+	stocks := make(map[string]int, len(variantIDs))
+	if len(variantIDs) == 0 {
+		return stocks, nil
+	}
+
+	var results []struct {
+		VariantID string `json:"variant_id"`
+		Stock     int    `json:"stock"`
+	}
+	err := r.ec(ctx).InventoryLedger.Query().
+		Where(inventoryledger.VariantIDIn(variantIDs...)).
+		Modify(func(s *sql.Selector) {
+			s.Select(
+				s.C(inventoryledger.FieldVariantID),
+				sql.As(sql.Sum(s.C(inventoryledger.FieldDelta)), "stock"),
+			).GroupBy(s.C(inventoryledger.FieldVariantID))
+		}).
+		Scan(ctx, &results)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	for _, id := range variantIDs {
+		stocks[id] = 0
+	}
+	for _, r := range results {
+		stocks[r.VariantID] = r.Stock
+	}
+	return stocks, nil
+}
+
 func (r *inventoryLedgerRepo) SumByProductIDs(ctx context.Context, ids []string) (map[string]int64, error) {
 	result := make(map[string]int64)
 	if len(ids) == 0 {
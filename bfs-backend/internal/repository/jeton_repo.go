@@ -9,6 +9,8 @@ import (
 
 type JetonRepository interface {
 	Create(ctx context.Context, name, color string) (*ent.Jeton, error)
+	// Restore recreates a deleted jeton under its former id.
+	Restore(ctx context.Context, id, name, color string) (*ent.Jeton, error)
 	GetByID(ctx context.Context, id string) (*ent.Jeton, error)
 	GetAll(ctx context.Context) ([]*ent.Jeton, error)
 	Update(ctx context.Context, id string, name, color string) (*ent.Jeton, error)
@@ -38,6 +40,18 @@ func (r *jetonRepo) Create(ctx context.Context, name, color string) (*ent.Jeton,
 	return created, nil
 }
 
+func (r *jetonRepo) Restore(ctx context.Context, id, name, color string) (*ent.Jeton, error) {
+	created, err := r.ec(ctx).Jeton.Create().
+		SetID(id).
+		SetName(name).
+		SetColor(color).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
 func (r *jetonRepo) GetByID(ctx context.Context, id string) (*ent.Jeton, error) {
 	e, err := r.ec(ctx).Jeton.Get(ctx, id)
 	if err != nil {
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/membershipupload"
+)
+
+type MembershipUploadRepository interface {
+	Create(ctx context.Context, filename, content string, memberCount int) (*ent.MembershipUpload, error)
+	// Latest returns the most recent upload; ErrNotFound when there is none.
+	Latest(ctx context.Context) (*ent.MembershipUpload, error)
+}
+
+type membershipUploadRepo struct {
+	client *ent.Client
+}
+
+func NewMembershipUploadRepository(client *ent.Client) MembershipUploadRepository {
+	return &membershipUploadRepo{client: client}
+}
+
+func (r *membershipUploadRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *membershipUploadRepo) Create(ctx context.Context, filename, content string, memberCount int) (*ent.MembershipUpload, error) {
+	row, err := r.ec(ctx).MembershipUpload.Create().
+		SetFilename(filename).
+		SetContent(content).
+		SetMemberCount(memberCount).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
+
+func (r *membershipUploadRepo) Latest(ctx context.Context) (*ent.MembershipUpload, error) {
+	row, err := r.ec(ctx).MembershipUpload.Query().
+		Order(membershipupload.ByCreatedAt(entDescOpt())).
+		First(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/menuslot"
+	"backend/internal/i18n"
 )
 
 type MenuSlotRepository interface {
@@ -13,6 +14,8 @@ type MenuSlotRepository interface {
 	GetByMenuProductID(ctx context.Context, menuProductID string) ([]*ent.MenuSlot, error)
 	GetByMenuProductIDs(ctx context.Context, menuProductIDs []string) ([]*ent.MenuSlot, error)
 	Update(ctx context.Context, id, menuProductID string, name string, sequence int) (*ent.MenuSlot, error)
+	// UpdateTranslations replaces the translated names of a slot.
+	UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.MenuSlot, error)
 	Delete(ctx context.Context, id string) error
 	DeleteByMenuProductID(ctx context.Context, menuProductID string) error
 }
@@ -97,6 +100,16 @@ func (r *menuSlotRepo) Update(ctx context.Context, id, menuProductID string, nam
 	return updated, nil
 }
 
+func (r *menuSlotRepo) UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.MenuSlot, error) {
+	updated, err := r.ec(ctx).MenuSlot.UpdateOneID(id).
+		SetTranslations(translations).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
+}
+
 func (r *menuSlotRepo) Delete(ctx context.Context, id string) error {
 	return translateError(r.ec(ctx).MenuSlot.DeleteOneID(id).Exec(ctx))
 }
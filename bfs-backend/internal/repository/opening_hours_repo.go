@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/openinghoursexception"
+	"backend/internal/generated/ent/openinghourswindow"
+)
+
+type OpeningHoursRepository interface {
+	ListWindows(ctx context.Context) ([]*ent.OpeningHoursWindow, error)
+	// ListExceptions returns the exceptions on or after fromDate
+	// ("2006-01-02"), ordered by date. An empty fromDate returns all.
+	ListExceptions(ctx context.Context, fromDate string) ([]*ent.OpeningHoursException, error)
+	// Replace swaps out all windows and exceptions.
+	Replace(ctx context.Context, windows []OpeningHoursWindowInput, exceptions []OpeningHoursExceptionInput) error
+}
+
+type OpeningHoursWindowInput struct {
+	Label       string
+	Weekdays    int
+	StartMinute int
+	EndMinute   int
+}
+
+// OpeningHoursExceptionInput is one row of an exception date. Nil minutes
+// close the date.
+type OpeningHoursExceptionInput struct {
+	Date        string
+	Label       string
+	StartMinute *int
+	EndMinute   *int
+}
+
+type openingHoursRepo struct {
+	client *ent.Client
+}
+
+func NewOpeningHoursRepository(client *ent.Client) OpeningHoursRepository {
+	return &openingHoursRepo{client: client}
+}
+
+func (r *openingHoursRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *openingHoursRepo) ListWindows(ctx context.Context) ([]*ent.OpeningHoursWindow, error) {
+	rows, err := r.ec(ctx).OpeningHoursWindow.Query().
+		Order(ent.Asc(openinghourswindow.FieldStartMinute), ent.Asc(openinghourswindow.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *openingHoursRepo) ListExceptions(ctx context.Context, fromDate string) ([]*ent.OpeningHoursException, error) {
+	q := r.ec(ctx).OpeningHoursException.Query()
+	if fromDate != "" {
+		q = q.Where(openinghoursexception.DateGTE(fromDate))
+	}
+	rows, err := q.
+		Order(ent.Asc(openinghoursexception.FieldDate), ent.Asc(openinghoursexception.FieldStartMinute)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *openingHoursRepo) Replace(ctx context.Context, windows []OpeningHoursWindowInput, exceptions []OpeningHoursExceptionInput) error {
+	client := r.ec(ctx)
+	if _, err := client.OpeningHoursWindow.Delete().Exec(ctx); err != nil {
+		return translateError(err)
+	}
+	if _, err := client.OpeningHoursException.Delete().Exec(ctx); err != nil {
+		return translateError(err)
+	}
+	if len(windows) > 0 {
+		builders := make([]*ent.OpeningHoursWindowCreate, len(windows))
+		for i, w := range windows {
+			builders[i] = client.OpeningHoursWindow.Create().
+				SetLabel(w.Label).
+				SetWeekdays(w.Weekdays).
+				SetStartMinute(w.StartMinute).
+				SetEndMinute(w.EndMinute)
+		}
+		if _, err := client.OpeningHoursWindow.CreateBulk(builders...).Save(ctx); err != nil {
+			return translateError(err)
+		}
+	}
+	if len(exceptions) > 0 {
+		builders := make([]*ent.OpeningHoursExceptionCreate, len(exceptions))
+		for i, e := range exceptions {
+			builders[i] = client.OpeningHoursException.Create().
+				SetDate(e.Date).
+				SetLabel(e.Label).
+				SetNillableStartMinute(e.StartMinute).
+				SetNillableEndMinute(e.EndMinute)
+		}
+		if _, err := client.OpeningHoursException.CreateBulk(builders...).Save(ctx); err != nil {
+			return translateError(err)
+		}
+	}
+	return nil
+}
@@ -17,7 +17,8 @@ type OrderLineRedemptionRepository interface {
 	GetByID(ctx context.Context, id string) (*ent.OrderLineRedemption, error)
 	GetByOrderLineID(ctx context.Context, orderLineID string) (*ent.OrderLineRedemption, error)
 	ExistsByOrderLineID(ctx context.Context, orderLineID string) (bool, error)
-	RedeemUnredeemedByOrderLineIDs(ctx context.Context, orderLineIDs []string) (int64, error)
+	RedeemQuantity(ctx context.Context, orderLineID string, quantity int) (int, error)
+	UnredeemQuantity(ctx context.Context, orderLineID string, quantity int) error
 }
 
 type orderLineRedemptionRepo struct {
@@ -87,40 +88,83 @@ func (r *orderLineRedemptionRepo) ExistsByOrderLineID(ctx context.Context, order
 	return exists, nil
 }
 
-func (r *orderLineRedemptionRepo) RedeemUnredeemedByOrderLineIDs(ctx context.Context, orderLineIDs []string) (int64, error) {
-	if len(orderLineIDs) == 0 {
+// RedeemQuantity hands out up to `quantity` units of an order line, clamped to
+// what is still open, and returns how many units were actually redeemed. Once
+// the line is complete its redemption row is written, so HasRedemption keeps
+// meaning "fully redeemed". The increment is guarded by the previously read
+// redeemed_quantity; concurrent redemptions of the same line are retried.
+func (r *orderLineRedemptionRepo) RedeemQuantity(ctx context.Context, orderLineID string, quantity int) (int, error) {
+	if quantity <= 0 {
 		return 0, nil
 	}
 
-	// Cheap COUNT of how many lines still need a redemption — used for the
-	// "redeemed" tally returned to the caller (and the response cached by the
-	// idempotency layer in the service).
-	newCount, err := r.ec(ctx).OrderLine.Query().
+	for range redeemQuantityAttempts {
+		line, err := r.ec(ctx).OrderLine.Get(ctx, orderLineID)
+		if err != nil {
+			return 0, translateError(err)
+		}
+		open := line.Quantity - line.RedeemedQuantity
+		if open <= 0 {
+			return 0, nil
+		}
+		take := min(quantity, open)
+
+		n, err := r.ec(ctx).OrderLine.Update().
+			Where(
+				orderline.ID(orderLineID),
+				orderline.RedeemedQuantityEQ(line.RedeemedQuantity),
+			).
+			AddRedeemedQuantity(take).
+			Save(ctx)
+		if err != nil {
+			return 0, translateError(err)
+		}
+		if n == 0 {
+			continue
+		}
+
+		if line.RedeemedQuantity+take == line.Quantity {
+			// Bulk form so ON CONFLICT DO NOTHING doesn't surface as ErrNoRows.
+			if err := r.ec(ctx).OrderLineRedemption.CreateBulk(
+				r.ec(ctx).OrderLineRedemption.Create().SetOrderLineID(orderLineID),
+			).
+				OnConflict(sql.ConflictColumns(orderlineredemption.FieldOrderLineID)).
+				DoNothing().
+				Exec(ctx); err != nil {
+				return 0, translateError(err)
+			}
+		}
+		return take, nil
+	}
+	return 0, ErrConflict
+}
+
+const redeemQuantityAttempts = 3
+
+// UnredeemQuantity gives `quantity` units of an order line back, e.g. when a
+// redemption batch is undone. The line's redemption row is removed since the
+// line is no longer fully redeemed.
+func (r *orderLineRedemptionRepo) UnredeemQuantity(ctx context.Context, orderLineID string, quantity int) error {
+	if quantity <= 0 {
+		return nil
+	}
+	n, err := r.ec(ctx).OrderLine.Update().
 		Where(
-			orderline.IDIn(orderLineIDs...),
-			orderline.Not(orderline.HasRedemption()),
+			orderline.ID(orderLineID),
+			orderline.RedeemedQuantityGTE(quantity),
 		).
-		Count(ctx)
+		AddRedeemedQuantity(-quantity).
+		Save(ctx)
 	if err != nil {
-		return 0, translateError(err)
+		return translateError(err)
 	}
-	if newCount == 0 {
-		return 0, nil
-	}
-
-	// One round-trip: bulk INSERT … ON CONFLICT (order_line_id) DO NOTHING.
-	// The unique index on order_line_id silently skips already-redeemed lines,
-	// replacing the previous SELECT-then-INSERT pair.
-	builders := make([]*ent.OrderLineRedemptionCreate, len(orderLineIDs))
-	for i, id := range orderLineIDs {
-		builders[i] = r.ec(ctx).OrderLineRedemption.Create().SetOrderLineID(id)
+	if n == 0 {
+		return ErrConflict
 	}
-	if err := r.ec(ctx).OrderLineRedemption.CreateBulk(builders...).
-		OnConflict(sql.ConflictColumns(orderlineredemption.FieldOrderLineID)).
-		DoNothing().
+	if _, err := r.ec(ctx).OrderLineRedemption.Delete().
+		Where(orderlineredemption.OrderLineIDEQ(orderLineID)).
 		Exec(ctx); err != nil {
-		return 0, translateError(err)
+		return translateError(err)
 	}
-
-	return int64(newCount), nil
+	return nil
 }
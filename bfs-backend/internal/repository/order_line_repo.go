@@ -5,6 +5,7 @@ import (
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/deviceproduct"
+	"backend/internal/generated/ent/devicevariant"
 	"backend/internal/generated/ent/orderline"
 	"backend/internal/generated/ent/orderlineredemption"
 
@@ -20,6 +21,7 @@ type OrderLineRepository interface {
 	Update(ctx context.Context, id, orderID string, lineType orderline.LineType, productID string, title string, quantity int, unitPriceCents int64, parentLineID, menuSlotID *string, menuSlotName *string) (*ent.OrderLine, error)
 	GetByOrderAndProductIDs(ctx context.Context, orderID string, productIDs []string) ([]*ent.OrderLine, error)
 	GetByOrderAndStationID(ctx context.Context, orderID, stationID string) ([]*ent.OrderLine, error)
+	GetByOrderIDsAndStationID(ctx context.Context, orderIDs []string, stationID string) ([]*ent.OrderLine, error)
 	GetByParentLineIDs(ctx context.Context, parentIDs []string) ([]*ent.OrderLine, error)
 }
 
@@ -35,6 +37,11 @@ type OrderLineCreateParams struct {
 	ParentLineID   *string
 	MenuSlotID     *string
 	MenuSlotName   *string
+	VariantID      *string
+	VariantName    *string
+	PriceRuleID    *string
+	ListPriceCents *int64
+	Allergens      []string
 }
 
 type orderLineRepo struct {
@@ -98,6 +105,13 @@ func (r *orderLineRepo) CreateBatch(ctx context.Context, lines []OrderLineCreate
 		if line.MenuSlotName != nil {
 			b.SetMenuSlotName(*line.MenuSlotName)
 		}
+		b.SetNillableVariantID(line.VariantID).
+			SetNillableVariantName(line.VariantName).
+			SetNillablePriceRuleID(line.PriceRuleID).
+			SetNillableListPriceCents(line.ListPriceCents)
+		if len(line.Allergens) > 0 {
+			b.SetAllergens(line.Allergens)
+		}
 		builders[i] = b
 	}
 	created, err := r.ec(ctx).OrderLine.CreateBulk(builders...).Save(ctx)
@@ -202,15 +216,27 @@ func (r *orderLineRepo) GetByOrderAndStationID(ctx context.Context, orderID, sta
 	rows, err := r.ec(ctx).OrderLine.Query().
 		Where(
 			orderline.OrderIDEQ(orderID),
-			func(s *sql.Selector) {
-				dp := sql.Table(deviceproduct.Table)
-				s.Where(sql.In(
-					s.C(orderline.FieldProductID),
-					sql.Select(dp.C(deviceproduct.FieldProductID)).
-						From(dp).
-						Where(sql.EQ(dp.C(deviceproduct.FieldDeviceID), stationID)),
-				))
-			},
+			assignedToStation(stationID),
+		).
+		WithProduct().
+		WithRedemption().
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+// GetByOrderIDsAndStationID is the multi-order variant of GetByOrderAndStationID,
+// used to build the station queue without one round-trip per order.
+func (r *orderLineRepo) GetByOrderIDsAndStationID(ctx context.Context, orderIDs []string, stationID string) ([]*ent.OrderLine, error) {
+	if len(orderIDs) == 0 {
+		return []*ent.OrderLine{}, nil
+	}
+	rows, err := r.ec(ctx).OrderLine.Query().
+		Where(
+			orderline.OrderIDIn(orderIDs...),
+			assignedToStation(stationID),
 		).
 		WithProduct().
 		WithRedemption().
@@ -221,6 +247,40 @@ func (r *orderLineRepo) GetByOrderAndStationID(ctx context.Context, orderID, sta
 	return rows, nil
 }
 
+// assignedToStation restricts an order line query to lines assigned to the
+// station: lines of a variant with its own stations via device_variant, all
+// other lines via their product's device_product.
+func assignedToStation(stationID string) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		dp := sql.Table(deviceproduct.Table)
+		routed := sql.Table(devicevariant.Table)
+		dv := sql.Table(devicevariant.Table)
+		s.Where(sql.Or(
+			sql.And(
+				sql.In(
+					s.C(orderline.FieldProductID),
+					sql.Select(dp.C(deviceproduct.FieldProductID)).
+						From(dp).
+						Where(sql.EQ(dp.C(deviceproduct.FieldDeviceID), stationID)),
+				),
+				sql.Or(
+					sql.IsNull(s.C(orderline.FieldVariantID)),
+					sql.NotIn(
+						s.C(orderline.FieldVariantID),
+						sql.Select(routed.C(devicevariant.FieldVariantID)).From(routed),
+					),
+				),
+			),
+			sql.In(
+				s.C(orderline.FieldVariantID),
+				sql.Select(dv.C(devicevariant.FieldVariantID)).
+					From(dv).
+					Where(sql.EQ(dv.C(devicevariant.FieldDeviceID), stationID)),
+			),
+		))
+	}
+}
+
 func (r *orderLineRepo) GetByParentLineIDs(ctx context.Context, parentIDs []string) ([]*ent.OrderLine, error) {
 	if len(parentIDs) == 0 {
 		return []*ent.OrderLine{}, nil
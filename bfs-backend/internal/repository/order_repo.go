@@ -6,6 +6,7 @@ import (
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/orderline"
 	"backend/internal/generated/ent/orderpayment"
 
 	"entgo.io/ent/dialect/sql"
@@ -13,7 +14,7 @@ import (
 )
 
 type OrderRepository interface {
-	Create(ctx context.Context, totalCents int64, status order.Status, origin order.Origin, customerID, contactEmail, paymentAttemptID *string, payrexxGatewayID, payrexxTransactionID *int) (*ent.Order, error)
+	Create(ctx context.Context, totalCents int64, status order.Status, origin order.Origin, eventID, customerID, contactEmail, locale, paymentAttemptID *string, payrexxGatewayID, payrexxTransactionID *int) (*ent.Order, error)
 	GetByID(ctx context.Context, id string) (*ent.Order, error)
 	GetByIDWithRelations(ctx context.Context, id string) (*ent.Order, error)
 	GetByCustomerID(ctx context.Context, customerID string) ([]*ent.Order, error)
@@ -23,7 +24,7 @@ type OrderRepository interface {
 	Update(ctx context.Context, id string, totalCents int64, status order.Status, origin order.Origin, customerID, contactEmail, paymentAttemptID *string, payrexxGatewayID, payrexxTransactionID *int) (*ent.Order, error)
 	UpdateStatus(ctx context.Context, id string, status order.Status) error
 
-	ListAdmin(ctx context.Context, status *order.Status, from, to *time.Time, q *string) ([]*ent.Order, int64, error)
+	ListAdmin(ctx context.Context, status *order.Status, eventID *string, from, to *time.Time, q *string) ([]*ent.Order, int64, error)
 	ListByCustomerIDPaginated(ctx context.Context, customerID string) ([]*ent.Order, int64, error)
 
 	// POS payment methods - creates payment record and updates order status
@@ -41,8 +42,13 @@ type OrderRepository interface {
 	FindPendingByAttemptID(ctx context.Context, attemptID string) (*ent.Order, error)
 	DeletePendingByAttemptIDExcept(ctx context.Context, attemptID string, except string) (int64, error)
 
+	// Station queue
+	ListPaidPendingForStation(ctx context.Context, stationID string) ([]*ent.Order, error)
+
 	// Aggregation
-	GetEventDays(ctx context.Context) ([]EventDay, error)
+	// GetEventDays returns the Europe/Zurich days with paid orders, optionally
+	// only those of one event.
+	GetEventDays(ctx context.Context, eventID *string) ([]EventDay, error)
 }
 
 type EventDay struct {
@@ -64,17 +70,19 @@ func (r *orderRepo) ec(ctx context.Context) *ent.Client {
 	return ClientFromContext(ctx, r.client)
 }
 
-func (r *orderRepo) Create(ctx context.Context, totalCents int64, status order.Status, origin order.Origin, customerID, contactEmail, paymentAttemptID *string, payrexxGatewayID, payrexxTransactionID *int) (*ent.Order, error) {
+func (r *orderRepo) Create(ctx context.Context, totalCents int64, status order.Status, origin order.Origin, eventID, customerID, contactEmail, locale, paymentAttemptID *string, payrexxGatewayID, payrexxTransactionID *int) (*ent.Order, error) {
 	builder := r.ec(ctx).Order.Create().
 		SetTotalCents(totalCents).
 		SetStatus(status).
-		SetOrigin(origin)
+		SetOrigin(origin).
+		SetNillableEventID(eventID)
 	if customerID != nil {
 		builder.SetCustomerID(*customerID)
 	}
 	if contactEmail != nil {
 		builder.SetContactEmail(*contactEmail)
 	}
+	builder.SetNillableLocale(locale)
 	if paymentAttemptID != nil {
 		builder.SetPaymentAttemptID(*paymentAttemptID)
 	}
@@ -209,11 +217,14 @@ func (r *orderRepo) UpdateStatus(ctx context.Context, id string, status order.St
 	return translateError(err)
 }
 
-func (r *orderRepo) ListAdmin(ctx context.Context, status *order.Status, from, to *time.Time, q *string) ([]*ent.Order, int64, error) {
+func (r *orderRepo) ListAdmin(ctx context.Context, status *order.Status, eventID *string, from, to *time.Time, q *string) ([]*ent.Order, int64, error) {
 	applyFilters := func(query *ent.OrderQuery) *ent.OrderQuery {
 		if status != nil {
 			query = query.Where(order.StatusEQ(*status))
 		}
+		if eventID != nil {
+			query = query.Where(order.EventIDEQ(*eventID))
+		}
 		if from != nil {
 			query = query.Where(order.CreatedAtGTE(*from))
 		}
@@ -305,46 +316,53 @@ type CardMeta struct {
 	TransactionID *string
 }
 
+// setPosPayment marks a pending order paid and records the payment. It joins
+// a transaction carried by ctx, so callers can commit it together with other
+// writes such as a Club100 redemption.
 func (r *orderRepo) setPosPayment(ctx context.Context, orderID string, deviceID *string, method orderpayment.Method, amountCents int64, card *CardMeta) error {
-	tx, err := r.ec(ctx).Tx(ctx)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	payBuilder := tx.OrderPayment.Create().
-		SetOrderID(orderID).
-		SetMethod(method).
-		SetAmountCents(amountCents).
-		SetPaidAt(time.Now())
-	if deviceID != nil {
-		payBuilder.SetDeviceID(*deviceID)
-	}
-	if card != nil {
-		if card.Brand != nil {
-			payBuilder.SetCardBrand(*card.Brand)
+	return RunInTx(ctx, r.client, func(ctx context.Context) error {
+		c := r.ec(ctx)
+
+		// Only the first of two concurrent payments for an order gets to flip
+		// it from pending.
+		n, err := c.Order.Update().
+			Where(order.ID(orderID), order.StatusEQ(order.StatusPending)).
+			SetStatus(order.StatusPaid).
+			Save(ctx)
+		if err != nil {
+			return translateError(err)
 		}
-		if card.Last4 != nil {
-			payBuilder.SetCardLast4(*card.Last4)
+		if n == 0 {
+			return ErrOrderNotPending
 		}
-		if card.EntryMode != nil {
-			payBuilder.SetEntryMode(*card.EntryMode)
+
+		payBuilder := c.OrderPayment.Create().
+			SetOrderID(orderID).
+			SetMethod(method).
+			SetAmountCents(amountCents).
+			SetPaidAt(time.Now())
+		if deviceID != nil {
+			payBuilder.SetDeviceID(*deviceID)
 		}
-		if card.TransactionID != nil {
-			payBuilder.SetCardTransactionID(*card.TransactionID)
+		if card != nil {
+			if card.Brand != nil {
+				payBuilder.SetCardBrand(*card.Brand)
+			}
+			if card.Last4 != nil {
+				payBuilder.SetCardLast4(*card.Last4)
+			}
+			if card.EntryMode != nil {
+				payBuilder.SetEntryMode(*card.EntryMode)
+			}
+			if card.TransactionID != nil {
+				payBuilder.SetCardTransactionID(*card.TransactionID)
+			}
 		}
-	}
-	if _, err := payBuilder.Save(ctx); err != nil {
-		return translateError(err)
-	}
-
-	if _, err := tx.Order.UpdateOneID(orderID).
-		SetStatus(order.StatusPaid).
-		Save(ctx); err != nil {
-		return translateError(err)
-	}
-
-	return tx.Commit()
+		if _, err := payBuilder.Save(ctx); err != nil {
+			return translateError(err)
+		}
+		return nil
+	})
 }
 
 func (r *orderRepo) SetPosPaymentCash(ctx context.Context, orderID string, deviceID *string, amountCents int64) error {
@@ -428,10 +446,48 @@ func (r *orderRepo) DeletePendingByAttemptIDExcept(ctx context.Context, attemptI
 	return int64(n), nil
 }
 
-func (r *orderRepo) GetEventDays(ctx context.Context) ([]EventDay, error) {
+// ListPaidPendingForStation returns paid orders, oldest first, that still have
+// at least one unredeemed line whose product is assigned to `stationID`. Bundle
+// lines count as pending while any of their component children is unredeemed.
+func (r *orderRepo) ListPaidPendingForStation(ctx context.Context, stationID string) ([]*ent.Order, error) {
+	rows, err := r.ec(ctx).Order.Query().
+		Where(
+			order.StatusEQ(order.StatusPaid),
+			order.HasLinesWith(
+				assignedToStation(stationID),
+				orderline.Or(
+					orderline.And(
+						orderline.LineTypeNEQ(orderline.LineTypeBundle),
+						orderline.Not(orderline.HasRedemption()),
+					),
+					orderline.And(
+						orderline.LineTypeEQ(orderline.LineTypeBundle),
+						orderline.HasChildLinesWith(orderline.Not(orderline.HasRedemption())),
+					),
+				),
+			),
+		).
+		Order(order.ByCreatedAt()).
+		Limit(stationQueueHardCap).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+// stationQueueHardCap bounds the station queue so a backlog of forgotten
+// orders can't turn every kitchen screen refresh into a full table scan.
+const stationQueueHardCap = 200
+
+func (r *orderRepo) GetEventDays(ctx context.Context, eventID *string) ([]EventDay, error) {
 	var result []EventDay
-	err := r.ec(ctx).Order.Query().
-		Where(order.StatusEQ(order.StatusPaid)).
+	query := r.ec(ctx).Order.Query().
+		Where(order.StatusEQ(order.StatusPaid))
+	if eventID != nil {
+		query = query.Where(order.EventIDEQ(*eventID))
+	}
+	err := query.
 		Modify(func(s *sql.Selector) {
 			zurich := "created_at AT TIME ZONE 'Europe/Zurich'"
 			yearExpr := "EXTRACT(YEAR FROM " + zurich + ")::int"
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/orderline"
+	"backend/internal/generated/ent/predicate"
+	"backend/internal/generated/ent/pricerule"
+)
+
+// PriceRuleInput is a price rule's fields. Exactly one of ProductID and
+// CategoryID is set.
+type PriceRuleInput struct {
+	Name        string
+	ProductID   *string
+	CategoryID  *string
+	Weekdays    int
+	StartMinute int
+	EndMinute   int
+	Kind        pricerule.Kind
+	Value       int64
+	Active      bool
+}
+
+// PriceRuleUsage sums the paid order lines a rule priced.
+type PriceRuleUsage struct {
+	PriceRuleID   string
+	Quantity      int
+	RevenueCents  int64
+	DiscountCents int64
+}
+
+type PriceRuleRepository interface {
+	Create(ctx context.Context, in PriceRuleInput) (*ent.PriceRule, error)
+	GetByID(ctx context.Context, id string) (*ent.PriceRule, error)
+	// List returns all rules by name.
+	List(ctx context.Context) ([]*ent.PriceRule, error)
+	ListActive(ctx context.Context) ([]*ent.PriceRule, error)
+	Update(ctx context.Context, id string, in PriceRuleInput) (*ent.PriceRule, error)
+	// InUse reports whether order lines were priced by the rule.
+	InUse(ctx context.Context, id string) (bool, error)
+	Delete(ctx context.Context, id string) error
+	// Usage sums the paid order lines per rule, optionally of one event.
+	Usage(ctx context.Context, eventID *string) ([]PriceRuleUsage, error)
+}
+
+type priceRuleRepo struct {
+	client *ent.Client
+}
+
+func NewPriceRuleRepository(client *ent.Client) PriceRuleRepository {
+	return &priceRuleRepo{client: client}
+}
+
+func (r *priceRuleRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *priceRuleRepo) Create(ctx context.Context, in PriceRuleInput) (*ent.PriceRule, error) {
+	created, err := r.ec(ctx).PriceRule.Create().
+		SetName(in.Name).
+		SetNillableProductID(in.ProductID).
+		SetNillableCategoryID(in.CategoryID).
+		SetWeekdays(in.Weekdays).
+		SetStartMinute(in.StartMinute).
+		SetEndMinute(in.EndMinute).
+		SetKind(in.Kind).
+		SetValue(in.Value).
+		SetActive(in.Active).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
+func (r *priceRuleRepo) GetByID(ctx context.Context, id string) (*ent.PriceRule, error) {
+	rule, err := r.ec(ctx).PriceRule.Get(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rule, nil
+}
+
+func (r *priceRuleRepo) List(ctx context.Context) ([]*ent.PriceRule, error) {
+	rows, err := r.ec(ctx).PriceRule.Query().
+		Order(ent.Asc(pricerule.FieldName), ent.Asc(pricerule.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *priceRuleRepo) ListActive(ctx context.Context) ([]*ent.PriceRule, error) {
+	rows, err := r.ec(ctx).PriceRule.Query().
+		Where(pricerule.Active(true)).
+		Order(ent.Asc(pricerule.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *priceRuleRepo) Update(ctx context.Context, id string, in PriceRuleInput) (*ent.PriceRule, error) {
+	upd := r.ec(ctx).PriceRule.UpdateOneID(id).
+		SetName(in.Name).
+		SetWeekdays(in.Weekdays).
+		SetStartMinute(in.StartMinute).
+		SetEndMinute(in.EndMinute).
+		SetKind(in.Kind).
+		SetValue(in.Value).
+		SetActive(in.Active)
+	if in.ProductID != nil {
+		upd.SetProductID(*in.ProductID)
+	} else {
+		upd.ClearProductID()
+	}
+	if in.CategoryID != nil {
+		upd.SetCategoryID(*in.CategoryID)
+	} else {
+		upd.ClearCategoryID()
+	}
+	updated, err := upd.Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
+}
+
+func (r *priceRuleRepo) InUse(ctx context.Context, id string) (bool, error) {
+	used, err := r.ec(ctx).OrderLine.Query().
+		Where(orderline.PriceRuleID(id)).
+		Exist(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return used, nil
+}
+
+func (r *priceRuleRepo) Delete(ctx context.Context, id string) error {
+	return translateError(r.ec(ctx).PriceRule.DeleteOneID(id).Exec(ctx))
+}
+
+func (r *priceRuleRepo) Usage(ctx context.Context, eventID *string) ([]PriceRuleUsage, error) {
+	paid := []predicate.Order{order.StatusEQ(order.StatusPaid)}
+	if eventID != nil {
+		paid = append(paid, order.EventID(*eventID))
+	}
+	lines, err := r.ec(ctx).OrderLine.Query().
+		Where(
+			orderline.PriceRuleIDNotNil(),
+			orderline.HasOrderWith(paid...),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	var out []PriceRuleUsage
+	index := make(map[string]int)
+	for _, l := range lines {
+		i, ok := index[*l.PriceRuleID]
+		if !ok {
+			i = len(out)
+			index[*l.PriceRuleID] = i
+			out = append(out, PriceRuleUsage{PriceRuleID: *l.PriceRuleID})
+		}
+		out[i].Quantity += l.Quantity
+		out[i].RevenueCents += l.UnitPriceCents * int64(l.Quantity)
+		if l.ListPriceCents != nil {
+			out[i].DiscountCents += (*l.ListPriceCents - l.UnitPriceCents) * int64(l.Quantity)
+		}
+	}
+	return out, nil
+}
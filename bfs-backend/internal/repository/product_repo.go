@@ -4,7 +4,10 @@ import (
 	"context"
 
 	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/menuslot"
 	"backend/internal/generated/ent/product"
+	"backend/internal/generated/ent/productvariant"
+	"backend/internal/i18n"
 
 	"entgo.io/ent/dialect/sql"
 )
@@ -17,8 +20,12 @@ func NewProductRepository(client *ent.Client) *ProductRepository {
 	return &ProductRepository{client: client}
 }
 
+func (r *ProductRepository) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
 func (r *ProductRepository) Create(ctx context.Context, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string) (*ent.Product, error) {
-	builder := r.client.Product.Create().
+	builder := r.ec(ctx).Product.Create().
 		SetCategoryID(categoryID).
 		SetType(productType).
 		SetName(name).
@@ -41,7 +48,7 @@ func (r *ProductRepository) Create(ctx context.Context, categoryID string, produ
 }
 
 func (r *ProductRepository) GetByID(ctx context.Context, id string) (*ent.Product, error) {
-	e, err := r.client.Product.Get(ctx, id)
+	e, err := r.ec(ctx).Product.Get(ctx, id)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -49,10 +56,11 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*ent.Produc
 }
 
 func (r *ProductRepository) GetByIDWithRelations(ctx context.Context, id string) (*ent.Product, error) {
-	e, err := r.client.Product.Query().
+	e, err := r.ec(ctx).Product.Query().
 		Where(product.ID(id)).
 		WithCategory().
 		WithJeton().
+		WithVariants(withVariantDetails).
 		WithMenuSlots(func(q *ent.MenuSlotQuery) {
 			q.WithOptions(func(oq *ent.MenuSlotOptionQuery) {
 				oq.WithOptionProduct(func(pq *ent.ProductQuery) {
@@ -68,9 +76,10 @@ func (r *ProductRepository) GetByIDWithRelations(ctx context.Context, id string)
 }
 
 func (r *ProductRepository) GetAll(ctx context.Context) ([]*ent.Product, error) {
-	rows, err := r.client.Product.Query().
+	rows, err := r.ec(ctx).Product.Query().
 		WithCategory().
 		WithJeton().
+		WithVariants(withVariantDetails).
 		WithMenuSlots(func(q *ent.MenuSlotQuery) {
 			q.WithOptions(func(oq *ent.MenuSlotOptionQuery) {
 				oq.WithOptionProduct(func(pq *ent.ProductQuery) {
@@ -86,11 +95,30 @@ func (r *ProductRepository) GetAll(ctx context.Context) ([]*ent.Product, error)
 	return rows, nil
 }
 
+// GetCatalog returns every product by name with what a catalog export
+// needs: its variants, the station routing of both, and its menu slots with
+// their options.
+func (r *ProductRepository) GetCatalog(ctx context.Context) ([]*ent.Product, error) {
+	rows, err := r.ec(ctx).Product.Query().
+		WithDeviceProducts().
+		WithVariants(withVariantDetails).
+		WithMenuSlots(func(q *ent.MenuSlotQuery) {
+			q.WithOptions().Order(menuslot.BySequence(), menuslot.ByName())
+		}).
+		Order(product.ByName()).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
 func (r *ProductRepository) GetAllActive(ctx context.Context) ([]*ent.Product, error) {
-	rows, err := r.client.Product.Query().
+	rows, err := r.ec(ctx).Product.Query().
 		Where(product.IsActive(true)).
 		WithCategory().
 		WithJeton().
+		WithVariants(withVariantDetails).
 		WithMenuSlots(func(q *ent.MenuSlotQuery) {
 			q.WithOptions(func(oq *ent.MenuSlotOptionQuery) {
 				oq.WithOptionProduct(func(pq *ent.ProductQuery) {
@@ -107,10 +135,11 @@ func (r *ProductRepository) GetAllActive(ctx context.Context) ([]*ent.Product, e
 }
 
 func (r *ProductRepository) GetByCategory(ctx context.Context, categoryID string) ([]*ent.Product, error) {
-	rows, err := r.client.Product.Query().
+	rows, err := r.ec(ctx).Product.Query().
 		Where(product.CategoryIDEQ(categoryID)).
 		WithCategory().
 		WithJeton().
+		WithVariants(withVariantDetails).
 		WithMenuSlots(func(q *ent.MenuSlotQuery) {
 			q.WithOptions(func(oq *ent.MenuSlotOptionQuery) {
 				oq.WithOptionProduct(func(pq *ent.ProductQuery) {
@@ -127,13 +156,14 @@ func (r *ProductRepository) GetByCategory(ctx context.Context, categoryID string
 }
 
 func (r *ProductRepository) GetByCategoryActive(ctx context.Context, categoryID string) ([]*ent.Product, error) {
-	rows, err := r.client.Product.Query().
+	rows, err := r.ec(ctx).Product.Query().
 		Where(
 			product.CategoryIDEQ(categoryID),
 			product.IsActive(true),
 		).
 		WithCategory().
 		WithJeton().
+		WithVariants(withVariantDetails).
 		WithMenuSlots(func(q *ent.MenuSlotQuery) {
 			q.WithOptions(func(oq *ent.MenuSlotOptionQuery) {
 				oq.WithOptionProduct(func(pq *ent.ProductQuery) {
@@ -150,10 +180,11 @@ func (r *ProductRepository) GetByCategoryActive(ctx context.Context, categoryID
 }
 
 func (r *ProductRepository) GetByIDs(ctx context.Context, ids []string) ([]*ent.Product, error) {
-	rows, err := r.client.Product.Query().
+	rows, err := r.ec(ctx).Product.Query().
 		Where(product.IDIn(ids...)).
 		WithCategory().
 		WithJeton().
+		WithVariants(withVariantDetails).
 		All(ctx)
 	if err != nil {
 		return nil, translateError(err)
@@ -162,7 +193,7 @@ func (r *ProductRepository) GetByIDs(ctx context.Context, ids []string) ([]*ent.
 }
 
 func (r *ProductRepository) Update(ctx context.Context, id, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string) (*ent.Product, error) {
-	builder := r.client.Product.UpdateOneID(id).
+	builder := r.ec(ctx).Product.UpdateOneID(id).
 		SetCategoryID(categoryID).
 		SetType(productType).
 		SetName(name).
@@ -191,12 +222,12 @@ func (r *ProductRepository) Update(ctx context.Context, id, categoryID string, p
 }
 
 func (r *ProductRepository) Delete(ctx context.Context, id string) error {
-	return translateError(r.client.Product.DeleteOneID(id).Exec(ctx))
+	return translateError(r.ec(ctx).Product.DeleteOneID(id).Exec(ctx))
 }
 
 func (r *ProductRepository) GetMenus(ctx context.Context, q *string, active *bool, limit, offset int) ([]*ent.Product, int64, error) {
 	// Build count query
-	countQ := r.client.Product.Query().
+	countQ := r.ec(ctx).Product.Query().
 		Where(product.TypeEQ(product.TypeMenu))
 	if active != nil {
 		countQ = countQ.Where(product.IsActive(*active))
@@ -210,7 +241,7 @@ func (r *ProductRepository) GetMenus(ctx context.Context, q *string, active *boo
 	}
 
 	// Build data query
-	dataQ := r.client.Product.Query().
+	dataQ := r.ec(ctx).Product.Query().
 		Where(product.TypeEQ(product.TypeMenu)).
 		WithCategory().
 		WithJeton().
@@ -240,7 +271,7 @@ func (r *ProductRepository) GetMenus(ctx context.Context, q *string, active *boo
 }
 
 func (r *ProductRepository) CountActiveWithoutJeton(ctx context.Context) (int64, error) {
-	count, err := r.client.Product.Query().
+	count, err := r.ec(ctx).Product.Query().
 		Where(
 			product.IsActive(true),
 			product.TypeEQ(product.TypeSimple),
@@ -253,6 +284,9 @@ func (r *ProductRepository) CountActiveWithoutJeton(ctx context.Context) (int64,
 	return int64(count), nil
 }
 
+// CountByJetonIDs counts the simple products and the variants that use each
+// of the jetons. Variants count too: deleting their jeton would silently put
+// them on their product's jeton.
 func (r *ProductRepository) CountByJetonIDs(ctx context.Context, ids []string) (map[string]int64, error) {
 	result := make(map[string]int64)
 	if len(ids) == 0 {
@@ -264,7 +298,7 @@ func (r *ProductRepository) CountByJetonIDs(ctx context.Context, ids []string) (
 		JetonID string `json:"jeton_id"`
 		Count   int    `json:"count"`
 	}
-	err := r.client.Product.Query().
+	err := r.ec(ctx).Product.Query().
 		Where(
 			product.JetonIDIn(ids...),
 			product.TypeEQ(product.TypeSimple),
@@ -279,11 +313,24 @@ func (r *ProductRepository) CountByJetonIDs(ctx context.Context, ids []string) (
 	for _, row := range rows {
 		result[row.JetonID] = int64(row.Count)
 	}
+
+	rows = nil
+	err = r.ec(ctx).ProductVariant.Query().
+		Where(productvariant.JetonIDIn(ids...)).
+		GroupBy(productvariant.FieldJetonID).
+		Aggregate(ent.Count()).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	for _, row := range rows {
+		result[row.JetonID] += int64(row.Count)
+	}
 	return result, nil
 }
 
 func (r *ProductRepository) UpdateJeton(ctx context.Context, id string, jetonID *string) error {
-	builder := r.client.Product.UpdateOneID(id)
+	builder := r.ec(ctx).Product.UpdateOneID(id)
 	if jetonID != nil {
 		builder.SetJetonID(*jetonID)
 	} else {
@@ -293,6 +340,37 @@ func (r *ProductRepository) UpdateJeton(ctx context.Context, id string, jetonID
 	return translateError(err)
 }
 
+// UpdateDietary replaces the allergens and dietary tags of a product; a nil
+// list is left unchanged.
+func (r *ProductRepository) UpdateDietary(ctx context.Context, id string, allergens, dietaryTags []string) (*ent.Product, error) {
+	builder := r.ec(ctx).Product.UpdateOneID(id)
+	if allergens != nil {
+		builder.SetAllergens(allergens)
+	}
+	if dietaryTags != nil {
+		builder.SetDietaryTags(dietaryTags)
+	}
+	p, err := builder.Save(ctx)
+	return p, translateError(err)
+}
+
+// UpdateTranslations replaces the translated names and descriptions of a
+// product.
+func (r *ProductRepository) UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Product, error) {
+	p, err := r.ec(ctx).Product.UpdateOneID(id).
+		SetTranslations(translations).
+		Save(ctx)
+	return p, translateError(err)
+}
+
+// withVariantDetails loads a product's variants by position, with their jeton
+// and station routing.
+func withVariantDetails(q *ent.ProductVariantQuery) {
+	q.WithJeton().
+		WithDeviceVariants().
+		Order(productvariant.ByPosition(), productvariant.ByName())
+}
+
 // productNameContainsILIKE provides ILIKE search via sql modifier.
 // This is used when the generated NameContainsFold is not sufficient.
 var _ = func() sql.Querier { return nil } // import anchor
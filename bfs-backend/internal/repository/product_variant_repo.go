@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/devicevariant"
+	"backend/internal/generated/ent/productvariant"
+)
+
+// ProductVariantInput holds the editable fields of a variant. A nil JetonID
+// uses the product's jeton; no StationIDs route the variant with its product.
+type ProductVariantInput struct {
+	Name       string
+	PriceCents int64
+	JetonID    *string
+	Position   int
+	IsActive   bool
+	StationIDs []string
+}
+
+type ProductVariantRepository interface {
+	// Create and Update return the variant like GetByID.
+	Create(ctx context.Context, productID string, in ProductVariantInput) (*ent.ProductVariant, error)
+	// GetByID returns the variant with its jeton and station IDs loaded.
+	GetByID(ctx context.Context, id string) (*ent.ProductVariant, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*ent.ProductVariant, error)
+	// ListByProduct returns the product's variants by position, with their
+	// jeton and station IDs loaded.
+	ListByProduct(ctx context.Context, productID string) ([]*ent.ProductVariant, error)
+	Update(ctx context.Context, id string, in ProductVariantInput) (*ent.ProductVariant, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type productVariantRepo struct {
+	client *ent.Client
+}
+
+func NewProductVariantRepository(client *ent.Client) ProductVariantRepository {
+	return &productVariantRepo{client: client}
+}
+
+func (r *productVariantRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *productVariantRepo) Create(ctx context.Context, productID string, in ProductVariantInput) (*ent.ProductVariant, error) {
+	var created *ent.ProductVariant
+	err := RunInTx(ctx, r.client, func(ctx context.Context) error {
+		var err error
+		created, err = r.ec(ctx).ProductVariant.Create().
+			SetProductID(productID).
+			SetName(in.Name).
+			SetPriceCents(in.PriceCents).
+			SetNillableJetonID(in.JetonID).
+			SetPosition(in.Position).
+			SetIsActive(in.IsActive).
+			Save(ctx)
+		if err != nil {
+			return translateError(err)
+		}
+		return r.replaceStations(ctx, created.ID, in.StationIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, created.ID)
+}
+
+func (r *productVariantRepo) GetByID(ctx context.Context, id string) (*ent.ProductVariant, error) {
+	q := r.ec(ctx).ProductVariant.Query().
+		Where(productvariant.ID(id))
+	withVariantDetails(q)
+	v, err := q.Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return v, nil
+}
+
+func (r *productVariantRepo) GetByIDs(ctx context.Context, ids []string) ([]*ent.ProductVariant, error) {
+	if len(ids) == 0 {
+		return []*ent.ProductVariant{}, nil
+	}
+	rows, err := r.ec(ctx).ProductVariant.Query().
+		Where(productvariant.IDIn(ids...)).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *productVariantRepo) ListByProduct(ctx context.Context, productID string) ([]*ent.ProductVariant, error) {
+	q := r.ec(ctx).ProductVariant.Query().
+		Where(productvariant.ProductIDEQ(productID))
+	withVariantDetails(q)
+	rows, err := q.All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *productVariantRepo) Update(ctx context.Context, id string, in ProductVariantInput) (*ent.ProductVariant, error) {
+	err := RunInTx(ctx, r.client, func(ctx context.Context) error {
+		upd := r.ec(ctx).ProductVariant.UpdateOneID(id).
+			SetName(in.Name).
+			SetPriceCents(in.PriceCents).
+			SetPosition(in.Position).
+			SetIsActive(in.IsActive)
+		if in.JetonID != nil {
+			upd.SetJetonID(*in.JetonID)
+		} else {
+			upd.ClearJetonID()
+		}
+		if err := upd.Exec(ctx); err != nil {
+			return translateError(err)
+		}
+		return r.replaceStations(ctx, id, in.StationIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *productVariantRepo) Delete(ctx context.Context, id string) error {
+	return translateError(r.ec(ctx).ProductVariant.DeleteOneID(id).Exec(ctx))
+}
+
+func (r *productVariantRepo) replaceStations(ctx context.Context, variantID string, deviceIDs []string) error {
+	ec := r.ec(ctx)
+	if _, err := ec.DeviceVariant.Delete().
+		Where(devicevariant.VariantIDEQ(variantID)).
+		Exec(ctx); err != nil {
+		return translateError(err)
+	}
+	if len(deviceIDs) == 0 {
+		return nil
+	}
+	builders := make([]*ent.DeviceVariantCreate, 0, len(deviceIDs))
+	for _, id := range deviceIDs {
+		builders = append(builders, ec.DeviceVariant.Create().
+			SetDeviceID(id).
+			SetVariantID(variantID))
+	}
+	return translateError(ec.DeviceVariant.CreateBulk(builders...).Exec(ctx))
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/redemptionbatch"
+)
+
+type RedemptionBatchRepository interface {
+	Create(ctx context.Context, orderID string, stationDeviceID *string, items []RedemptionBatchItemParams) (*ent.RedemptionBatch, error)
+	GetByID(ctx context.Context, id string) (*ent.RedemptionBatch, error)
+	// MarkUndone flags the batch as undone iff it has not been undone yet.
+	// Returns false when another undo got there first.
+	MarkUndone(ctx context.Context, id string, deviceID, userID *string, reason string, at time.Time) (bool, error)
+	ListUndone(ctx context.Context, limit int) ([]*ent.RedemptionBatch, error)
+}
+
+// RedemptionBatchItemParams is one line's share of a redemption batch.
+type RedemptionBatchItemParams struct {
+	OrderLineID string
+	Quantity    int
+}
+
+type redemptionBatchRepo struct {
+	client *ent.Client
+}
+
+func NewRedemptionBatchRepository(client *ent.Client) RedemptionBatchRepository {
+	return &redemptionBatchRepo{client: client}
+}
+
+func (r *redemptionBatchRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *redemptionBatchRepo) Create(ctx context.Context, orderID string, stationDeviceID *string, items []RedemptionBatchItemParams) (*ent.RedemptionBatch, error) {
+	b := r.ec(ctx).RedemptionBatch.Create().
+		SetOrderID(orderID)
+	if stationDeviceID != nil {
+		b.SetStationDeviceID(*stationDeviceID)
+	}
+	batch, err := b.Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if len(items) > 0 {
+		builders := make([]*ent.RedemptionBatchItemCreate, len(items))
+		for i, item := range items {
+			builders[i] = r.ec(ctx).RedemptionBatchItem.Create().
+				SetBatchID(batch.ID).
+				SetOrderLineID(item.OrderLineID).
+				SetQuantity(item.Quantity)
+		}
+		created, err := r.ec(ctx).RedemptionBatchItem.CreateBulk(builders...).Save(ctx)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		batch.Edges.Items = created
+	}
+	return batch, nil
+}
+
+func (r *redemptionBatchRepo) GetByID(ctx context.Context, id string) (*ent.RedemptionBatch, error) {
+	e, err := r.ec(ctx).RedemptionBatch.Query().
+		Where(redemptionbatch.ID(id)).
+		WithItems().
+		Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *redemptionBatchRepo) MarkUndone(ctx context.Context, id string, deviceID, userID *string, reason string, at time.Time) (bool, error) {
+	u := r.ec(ctx).RedemptionBatch.Update().
+		Where(
+			redemptionbatch.ID(id),
+			redemptionbatch.UndoneAtIsNil(),
+		).
+		SetUndoneAt(at).
+		SetUndoReason(reason)
+	if deviceID != nil {
+		u.SetUndoneByDeviceID(*deviceID)
+	}
+	if userID != nil {
+		u.SetUndoneByUserID(*userID)
+	}
+	n, err := u.Save(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return n > 0, nil
+}
+
+func (r *redemptionBatchRepo) ListUndone(ctx context.Context, limit int) ([]*ent.RedemptionBatch, error) {
+	rows, err := r.ec(ctx).RedemptionBatch.Query().
+		Where(redemptionbatch.UndoneAtNotNil()).
+		WithItems().
+		Order(redemptionbatch.ByUndoneAt(entDescOpt())).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
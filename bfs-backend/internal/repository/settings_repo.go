@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/club100freeproduct"
@@ -15,6 +16,14 @@ type SettingsRepository interface {
 	UpdateClub100Settings(ctx context.Context, freeProductIDs []string, maxRedemptions int) error
 	IsSystemEnabled(ctx context.Context) (bool, error)
 	SetSystemEnabled(ctx context.Context, enabled bool) error
+	// SetSystemOpenUntil sets or, with nil, clears the manual override that
+	// opens the system outside its opening hours.
+	SetSystemOpenUntil(ctx context.Context, until *time.Time) error
+	SetOpeningHours(ctx context.Context, enabled bool, closedMessage string) error
+	SetAcceptLegacyQR(ctx context.Context, accept bool) error
+	// Lock creates the settings row if needed and holds its row lock until
+	// the transaction carried by ctx ends.
+	Lock(ctx context.Context) error
 }
 
 type settingsRepo struct {
@@ -38,6 +47,7 @@ func (r *settingsRepo) Get(ctx context.Context) (*ent.Settings, error) {
 				PosMode:               settings.PosModeQR_CODE,
 				SystemEnabled:         true,
 				Club100MaxRedemptions: 2,
+				AcceptLegacyQr:        true,
 			}, nil
 		}
 		return nil, translateError(err)
@@ -57,6 +67,7 @@ func (r *settingsRepo) GetWithProducts(ctx context.Context) (*ent.Settings, erro
 				PosMode:               settings.PosModeQR_CODE,
 				SystemEnabled:         true,
 				Club100MaxRedemptions: 2,
+				AcceptLegacyQr:        true,
 			}, nil
 		}
 		return nil, translateError(err)
@@ -124,3 +135,48 @@ func (r *settingsRepo) SetSystemEnabled(ctx context.Context, enabled bool) error
 		Exec(ctx)
 	return translateError(err)
 }
+
+func (r *settingsRepo) SetSystemOpenUntil(ctx context.Context, until *time.Time) error {
+	upsert := r.ec(ctx).Settings.Create().
+		SetID("default").
+		SetNillableSystemOpenUntil(until).
+		OnConflictColumns(settings.FieldID)
+	if until != nil {
+		upsert.SetSystemOpenUntil(*until)
+	} else {
+		upsert.ClearSystemOpenUntil()
+	}
+	return translateError(upsert.Exec(ctx))
+}
+
+func (r *settingsRepo) SetOpeningHours(ctx context.Context, enabled bool, closedMessage string) error {
+	err := r.ec(ctx).Settings.Create().
+		SetID("default").
+		SetOpeningHoursEnabled(enabled).
+		SetClosedMessage(closedMessage).
+		OnConflictColumns(settings.FieldID).
+		SetOpeningHoursEnabled(enabled).
+		SetClosedMessage(closedMessage).
+		Exec(ctx)
+	return translateError(err)
+}
+
+func (r *settingsRepo) SetAcceptLegacyQR(ctx context.Context, accept bool) error {
+	err := r.ec(ctx).Settings.Create().
+		SetID("default").
+		SetAcceptLegacyQr(accept).
+		OnConflictColumns(settings.FieldID).
+		SetAcceptLegacyQr(accept).
+		Exec(ctx)
+	return translateError(err)
+}
+
+func (r *settingsRepo) Lock(ctx context.Context) error {
+	// ON CONFLICT DO UPDATE locks the existing row, unlike DO NOTHING.
+	err := r.ec(ctx).Settings.Create().
+		SetID("default").
+		OnConflictColumns(settings.FieldID).
+		Ignore().
+		Exec(ctx)
+	return translateError(err)
+}
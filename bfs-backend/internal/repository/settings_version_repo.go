@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/settingsversion"
+)
+
+// SettingsVersionInput is one snapshot to append to the settings history.
+type SettingsVersionInput struct {
+	Change         string
+	Snapshot       []byte
+	CreatedBy      *string
+	CreatedByName  *string
+	RolledBackFrom *int
+}
+
+type SettingsVersionRepository interface {
+	// Create appends a version numbered after the latest one. Callers hold
+	// the settings row lock so concurrent changes cannot pick the same number.
+	Create(ctx context.Context, in SettingsVersionInput) (*ent.SettingsVersion, error)
+	// Latest returns the newest version, or ErrNotFound.
+	Latest(ctx context.Context) (*ent.SettingsVersion, error)
+	GetByVersion(ctx context.Context, version int) (*ent.SettingsVersion, error)
+	// List returns up to limit versions older than before (all when before
+	// is 0), newest first.
+	List(ctx context.Context, limit, before int) ([]*ent.SettingsVersion, error)
+}
+
+type settingsVersionRepo struct {
+	client *ent.Client
+}
+
+func NewSettingsVersionRepository(client *ent.Client) SettingsVersionRepository {
+	return &settingsVersionRepo{client: client}
+}
+
+func (r *settingsVersionRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *settingsVersionRepo) Create(ctx context.Context, in SettingsVersionInput) (*ent.SettingsVersion, error) {
+	next := 1
+	latest, err := r.Latest(ctx)
+	switch {
+	case err == nil:
+		next = latest.Version + 1
+	case !errors.Is(err, ErrNotFound):
+		return nil, err
+	}
+	created, err := r.ec(ctx).SettingsVersion.Create().
+		SetVersion(next).
+		SetChange(in.Change).
+		SetSnapshot(in.Snapshot).
+		SetNillableCreatedBy(in.CreatedBy).
+		SetNillableCreatedByName(in.CreatedByName).
+		SetNillableRolledBackFrom(in.RolledBackFrom).
+		Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return created, nil
+}
+
+func (r *settingsVersionRepo) Latest(ctx context.Context) (*ent.SettingsVersion, error) {
+	e, err := r.ec(ctx).SettingsVersion.Query().
+		Order(settingsversion.ByVersion(entDescOpt())).
+		First(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *settingsVersionRepo) GetByVersion(ctx context.Context, version int) (*ent.SettingsVersion, error) {
+	e, err := r.ec(ctx).SettingsVersion.Query().
+		Where(settingsversion.VersionEQ(version)).
+		Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return e, nil
+}
+
+func (r *settingsVersionRepo) List(ctx context.Context, limit, before int) ([]*ent.SettingsVersion, error) {
+	q := r.ec(ctx).SettingsVersion.Query()
+	if before > 0 {
+		q = q.Where(settingsversion.VersionLT(before))
+	}
+	rows, err := q.
+		Order(settingsversion.ByVersion(entDescOpt())).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
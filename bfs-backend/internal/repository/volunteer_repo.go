@@ -6,7 +6,10 @@ import (
 
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/volunteercampaign"
+	"backend/internal/generated/ent/volunteercampaignchoicegroup"
+	"backend/internal/generated/ent/volunteercampaignchoiceoption"
 	"backend/internal/generated/ent/volunteercampaignproduct"
+	"backend/internal/generated/ent/volunteercampaignwindow"
 	"backend/internal/generated/ent/volunteerredemption"
 
 	nanoid "backend/internal/id"
@@ -14,19 +17,32 @@ import (
 )
 
 type VolunteerCampaignRepository interface {
-	Create(ctx context.Context, name, accessCode string, validFrom, validUntil *time.Time, status volunteercampaign.Status, maxRedemptions int) (*ent.VolunteerCampaign, error)
+	Create(ctx context.Context, name, accessCode string, eventID *string, validFrom, validUntil *time.Time, status volunteercampaign.Status, maxRedemptions int) (*ent.VolunteerCampaign, error)
 	GetByID(ctx context.Context, id string) (*ent.VolunteerCampaign, error)
 	GetByIDWithProducts(ctx context.Context, id string) (*ent.VolunteerCampaign, error)
 	GetByClaimToken(ctx context.Context, token string) (*ent.VolunteerCampaign, error)
-	List(ctx context.Context) ([]*ent.VolunteerCampaign, error)
+	// List returns all campaigns, or only those of one event, newest first.
+	List(ctx context.Context, eventID *string) ([]*ent.VolunteerCampaign, error)
 	Update(ctx context.Context, id string, name, accessCode string, validFrom, validUntil *time.Time, status volunteercampaign.Status) (*ent.VolunteerCampaign, error)
 	UpdateMaxRedemptions(ctx context.Context, id string, newMax int) (*ent.VolunteerCampaign, bool, error)
 	RotateClaimToken(ctx context.Context, id string) (string, error)
 	SetStatus(ctx context.Context, id string, status volunteercampaign.Status) error
+	// SetLogoURL sets the slip logo; nil clears it.
+	SetLogoURL(ctx context.Context, id string, logoURL *string) error
 
 	ReplaceProducts(ctx context.Context, campaignID string, items []VolunteerCampaignProductInput) error
 	ListProducts(ctx context.Context, campaignID string) ([]*ent.VolunteerCampaignProduct, error)
 
+	// SetDailyLimits sets the per-day limits; nil clears a limit.
+	SetDailyLimits(ctx context.Context, campaignID string, maxPerDay, maxPerPersonPerDay *int) error
+	ReplaceWindows(ctx context.Context, campaignID string, items []VolunteerCampaignWindowInput) error
+	ListWindows(ctx context.Context, campaignID string) ([]*ent.VolunteerCampaignWindow, error)
+
+	ReplaceChoiceGroups(ctx context.Context, campaignID string, items []VolunteerChoiceGroupInput) error
+	// ListChoiceGroups returns the groups in position order with their
+	// options (and option products) loaded.
+	ListChoiceGroups(ctx context.Context, campaignID string) ([]*ent.VolunteerCampaignChoiceGroup, error)
+
 	// IncrementRedemptionAtomic conditionally increments redemption_count iff the campaign
 	// is active, inside its validity window, and under max_redemptions. Returns true when
 	// the increment succeeded.
@@ -38,10 +54,28 @@ type VolunteerCampaignProductInput struct {
 	Quantity  int
 }
 
+type VolunteerCampaignWindowInput struct {
+	Label          string
+	Weekdays       int
+	StartMinute    int
+	EndMinute      int
+	MaxRedemptions *int
+	MaxPerPerson   *int
+}
+
+type VolunteerChoiceGroupInput struct {
+	Name       string
+	Quantity   int
+	ProductIDs []string
+}
+
 type VolunteerRedemptionRepository interface {
-	Create(ctx context.Context, campaignID, orderID string, stationDeviceID *string, idempotencyKey *string) (*ent.VolunteerRedemption, error)
+	Create(ctx context.Context, campaignID, orderID string, volunteerTokenID, stationDeviceID, idempotencyKey *string) (*ent.VolunteerRedemption, error)
 	GetByIdempotencyKey(ctx context.Context, campaignID string, key string) (*ent.VolunteerRedemption, error)
 	ListByCampaign(ctx context.Context, campaignID string, limit int) ([]*ent.VolunteerRedemption, error)
+	// CountBetween counts redemptions in [from, to). A nil volunteerTokenID
+	// counts shared-QR redemptions only.
+	CountBetween(ctx context.Context, campaignID string, volunteerTokenID *string, from, to time.Time) (int, error)
 }
 
 type volunteerCampaignRepo struct {
@@ -68,12 +102,13 @@ func (r *volunteerRedemptionRepo) ec(ctx context.Context) *ent.Client {
 	return ClientFromContext(ctx, r.client)
 }
 
-func (r *volunteerCampaignRepo) Create(ctx context.Context, name, accessCode string, validFrom, validUntil *time.Time, status volunteercampaign.Status, maxRedemptions int) (*ent.VolunteerCampaign, error) {
+func (r *volunteerCampaignRepo) Create(ctx context.Context, name, accessCode string, eventID *string, validFrom, validUntil *time.Time, status volunteercampaign.Status, maxRedemptions int) (*ent.VolunteerCampaign, error) {
 	b := r.ec(ctx).VolunteerCampaign.Create().
 		SetName(name).
 		SetAccessCode(accessCode).
 		SetStatus(status).
-		SetMaxRedemptions(maxRedemptions)
+		SetMaxRedemptions(maxRedemptions).
+		SetNillableEventID(eventID)
 	if validFrom != nil {
 		b.SetValidFrom(*validFrom)
 	}
@@ -118,8 +153,12 @@ func (r *volunteerCampaignRepo) GetByClaimToken(ctx context.Context, token strin
 	return e, nil
 }
 
-func (r *volunteerCampaignRepo) List(ctx context.Context) ([]*ent.VolunteerCampaign, error) {
-	rows, err := r.ec(ctx).VolunteerCampaign.Query().
+func (r *volunteerCampaignRepo) List(ctx context.Context, eventID *string) ([]*ent.VolunteerCampaign, error) {
+	q := r.ec(ctx).VolunteerCampaign.Query()
+	if eventID != nil {
+		q.Where(volunteercampaign.EventIDEQ(*eventID))
+	}
+	rows, err := q.
 		Order(volunteercampaign.ByCreatedAt(entDescOpt())).
 		All(ctx)
 	if err != nil {
@@ -192,6 +231,17 @@ func (r *volunteerCampaignRepo) SetStatus(ctx context.Context, id string, status
 	return translateError(err)
 }
 
+func (r *volunteerCampaignRepo) SetLogoURL(ctx context.Context, id string, logoURL *string) error {
+	b := r.ec(ctx).VolunteerCampaign.UpdateOneID(id)
+	if logoURL != nil {
+		b.SetLogoURL(*logoURL)
+	} else {
+		b.ClearLogoURL()
+	}
+	_, err := b.Save(ctx)
+	return translateError(err)
+}
+
 func (r *volunteerCampaignRepo) ReplaceProducts(ctx context.Context, campaignID string, items []VolunteerCampaignProductInput) error {
 	client := r.ec(ctx)
 	_, err := client.VolunteerCampaignProduct.Delete().
@@ -225,6 +275,115 @@ func (r *volunteerCampaignRepo) ListProducts(ctx context.Context, campaignID str
 	return rows, nil
 }
 
+func (r *volunteerCampaignRepo) SetDailyLimits(ctx context.Context, campaignID string, maxPerDay, maxPerPersonPerDay *int) error {
+	u := r.ec(ctx).VolunteerCampaign.UpdateOneID(campaignID)
+	if maxPerDay != nil {
+		u.SetMaxRedemptionsPerDay(*maxPerDay)
+	} else {
+		u.ClearMaxRedemptionsPerDay()
+	}
+	if maxPerPersonPerDay != nil {
+		u.SetMaxPerPersonPerDay(*maxPerPersonPerDay)
+	} else {
+		u.ClearMaxPerPersonPerDay()
+	}
+	return translateError(u.Exec(ctx))
+}
+
+func (r *volunteerCampaignRepo) ReplaceWindows(ctx context.Context, campaignID string, items []VolunteerCampaignWindowInput) error {
+	client := r.ec(ctx)
+	_, err := client.VolunteerCampaignWindow.Delete().
+		Where(volunteercampaignwindow.CampaignIDEQ(campaignID)).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	builders := make([]*ent.VolunteerCampaignWindowCreate, len(items))
+	for i, it := range items {
+		builders[i] = client.VolunteerCampaignWindow.Create().
+			SetCampaignID(campaignID).
+			SetLabel(it.Label).
+			SetWeekdays(it.Weekdays).
+			SetStartMinute(it.StartMinute).
+			SetEndMinute(it.EndMinute).
+			SetNillableMaxRedemptions(it.MaxRedemptions).
+			SetNillableMaxPerPerson(it.MaxPerPerson)
+	}
+	_, err = client.VolunteerCampaignWindow.CreateBulk(builders...).Save(ctx)
+	return translateError(err)
+}
+
+func (r *volunteerCampaignRepo) ListWindows(ctx context.Context, campaignID string) ([]*ent.VolunteerCampaignWindow, error) {
+	rows, err := r.ec(ctx).VolunteerCampaignWindow.Query().
+		Where(volunteercampaignwindow.CampaignIDEQ(campaignID)).
+		Order(
+			volunteercampaignwindow.ByStartMinute(),
+			volunteercampaignwindow.ByEndMinute(),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *volunteerCampaignRepo) ReplaceChoiceGroups(ctx context.Context, campaignID string, items []VolunteerChoiceGroupInput) error {
+	client := r.ec(ctx)
+	// Options go with their group via ON DELETE CASCADE.
+	_, err := client.VolunteerCampaignChoiceGroup.Delete().
+		Where(volunteercampaignchoicegroup.CampaignIDEQ(campaignID)).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	groups := make([]*ent.VolunteerCampaignChoiceGroupCreate, len(items))
+	for i, it := range items {
+		groups[i] = client.VolunteerCampaignChoiceGroup.Create().
+			SetCampaignID(campaignID).
+			SetName(it.Name).
+			SetQuantity(it.Quantity).
+			SetPosition(i)
+	}
+	created, err := client.VolunteerCampaignChoiceGroup.CreateBulk(groups...).Save(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+	var options []*ent.VolunteerCampaignChoiceOptionCreate
+	for i, it := range items {
+		for pos, pid := range it.ProductIDs {
+			options = append(options, client.VolunteerCampaignChoiceOption.Create().
+				SetGroupID(created[i].ID).
+				SetProductID(pid).
+				SetPosition(pos))
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	_, err = client.VolunteerCampaignChoiceOption.CreateBulk(options...).Save(ctx)
+	return translateError(err)
+}
+
+func (r *volunteerCampaignRepo) ListChoiceGroups(ctx context.Context, campaignID string) ([]*ent.VolunteerCampaignChoiceGroup, error) {
+	rows, err := r.ec(ctx).VolunteerCampaignChoiceGroup.Query().
+		Where(volunteercampaignchoicegroup.CampaignIDEQ(campaignID)).
+		Order(volunteercampaignchoicegroup.ByPosition()).
+		WithOptions(func(q *ent.VolunteerCampaignChoiceOptionQuery) {
+			q.WithProduct().Order(volunteercampaignchoiceoption.ByPosition())
+		}).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
 func (r *volunteerCampaignRepo) IncrementRedemptionAtomic(ctx context.Context, campaignID string) (bool, error) {
 	now := time.Now()
 	n, err := r.ec(ctx).VolunteerCampaign.Update().
@@ -254,10 +413,11 @@ func (r *volunteerCampaignRepo) IncrementRedemptionAtomic(ctx context.Context, c
 	return n > 0, nil
 }
 
-func (r *volunteerRedemptionRepo) Create(ctx context.Context, campaignID, orderID string, stationDeviceID *string, idempotencyKey *string) (*ent.VolunteerRedemption, error) {
+func (r *volunteerRedemptionRepo) Create(ctx context.Context, campaignID, orderID string, volunteerTokenID, stationDeviceID, idempotencyKey *string) (*ent.VolunteerRedemption, error) {
 	b := r.ec(ctx).VolunteerRedemption.Create().
 		SetCampaignID(campaignID).
-		SetOrderID(orderID)
+		SetOrderID(orderID).
+		SetNillableVolunteerTokenID(volunteerTokenID)
 	if stationDeviceID != nil {
 		b.SetStationDeviceID(*stationDeviceID)
 	}
@@ -297,3 +457,22 @@ func (r *volunteerRedemptionRepo) ListByCampaign(ctx context.Context, campaignID
 	}
 	return rows, nil
 }
+
+func (r *volunteerRedemptionRepo) CountBetween(ctx context.Context, campaignID string, volunteerTokenID *string, from, to time.Time) (int, error) {
+	q := r.ec(ctx).VolunteerRedemption.Query().
+		Where(
+			volunteerredemption.CampaignIDEQ(campaignID),
+			volunteerredemption.CreatedAtGTE(from),
+			volunteerredemption.CreatedAtLT(to),
+		)
+	if volunteerTokenID != nil {
+		q = q.Where(volunteerredemption.VolunteerTokenIDEQ(*volunteerTokenID))
+	} else {
+		q = q.Where(volunteerredemption.VolunteerTokenIDIsNil())
+	}
+	n, err := q.Count(ctx)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return n, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/volunteercampaign"
+	"backend/internal/generated/ent/volunteertoken"
+	nanoid "backend/internal/id"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type VolunteerTokenInput struct {
+	Name           string
+	Email          *string
+	MaxRedemptions int
+}
+
+type VolunteerTokenRepository interface {
+	// CreateMany inserts the roster entries, skipping names that already exist
+	// in the campaign. Returns only the rows this call inserted.
+	CreateMany(ctx context.Context, campaignID string, items []VolunteerTokenInput) ([]*ent.VolunteerToken, error)
+	GetByID(ctx context.Context, id string) (*ent.VolunteerToken, error)
+	GetByToken(ctx context.Context, token string) (*ent.VolunteerToken, error)
+	// ListByCampaign returns the roster, i.e. all tokens except numbered slips.
+	ListByCampaign(ctx context.Context, campaignID string) ([]*ent.VolunteerToken, error)
+	Delete(ctx context.Context, campaignID, id string) error
+
+	// CreateNumbered issues count single-use slips numbered on from the
+	// campaign's highest serial. Run it in a transaction: it locks the
+	// campaign row until commit so concurrent issues wait for each other.
+	CreateNumbered(ctx context.Context, campaignID string, count int) ([]*ent.VolunteerToken, error)
+	// ListNumbered returns the numbered slips with from <= serial <= to in
+	// serial order. A zero bound is open.
+	ListNumbered(ctx context.Context, campaignID string, from, to int) ([]*ent.VolunteerToken, error)
+
+	// IncrementRedemptionAtomic increments redemption_count iff it is below the
+	// token's max_redemptions. Returns true when the increment succeeded.
+	IncrementRedemptionAtomic(ctx context.Context, id string) (bool, error)
+}
+
+type volunteerTokenRepo struct {
+	client *ent.Client
+}
+
+func NewVolunteerTokenRepository(client *ent.Client) VolunteerTokenRepository {
+	return &volunteerTokenRepo{client: client}
+}
+
+func (r *volunteerTokenRepo) ec(ctx context.Context) *ent.Client {
+	return ClientFromContext(ctx, r.client)
+}
+
+func (r *volunteerTokenRepo) CreateMany(ctx context.Context, campaignID string, items []VolunteerTokenInput) ([]*ent.VolunteerToken, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	// The ids are set here so the rows this call inserted can be told apart
+	// from those a concurrent import of the same names inserted first.
+	ids := make([]string, 0, len(items))
+	builders := make([]*ent.VolunteerTokenCreate, 0, len(items))
+	for _, it := range items {
+		id := nanoid.New()
+		ids = append(ids, id)
+		b := r.ec(ctx).VolunteerToken.Create().
+			SetID(id).
+			SetCampaignID(campaignID).
+			SetName(it.Name).
+			SetNillableEmail(it.Email).
+			SetMaxRedemptions(it.MaxRedemptions)
+		builders = append(builders, b)
+	}
+	err := r.ec(ctx).VolunteerToken.CreateBulk(builders...).
+		OnConflictColumns(volunteertoken.FieldCampaignID, volunteertoken.FieldName).
+		DoNothing().
+		Exec(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := r.ec(ctx).VolunteerToken.Query().
+		Where(volunteertoken.IDIn(ids...)).
+		Order(volunteertoken.ByName()).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *volunteerTokenRepo) GetByID(ctx context.Context, id string) (*ent.VolunteerToken, error) {
+	row, err := r.ec(ctx).VolunteerToken.Get(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
+
+func (r *volunteerTokenRepo) GetByToken(ctx context.Context, token string) (*ent.VolunteerToken, error) {
+	row, err := r.ec(ctx).VolunteerToken.Query().
+		Where(volunteertoken.TokenEQ(token)).
+		Only(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return row, nil
+}
+
+func (r *volunteerTokenRepo) ListByCampaign(ctx context.Context, campaignID string) ([]*ent.VolunteerToken, error) {
+	rows, err := r.ec(ctx).VolunteerToken.Query().
+		Where(
+			volunteertoken.CampaignIDEQ(campaignID),
+			volunteertoken.SerialIsNil(),
+		).
+		Order(volunteertoken.ByName()).
+		All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *volunteerTokenRepo) CreateNumbered(ctx context.Context, campaignID string, count int) ([]*ent.VolunteerToken, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	client := r.ec(ctx)
+
+	// Locking the campaign row serialises concurrent issues, so each one
+	// numbers on from the serials the previous one committed.
+	var locked []struct {
+		ID string `json:"id"`
+	}
+	err := client.VolunteerCampaign.Query().
+		Where(volunteercampaign.IDEQ(campaignID)).
+		Modify(func(s *sql.Selector) {
+			s.Select(s.C(volunteercampaign.FieldID)).ForUpdate()
+		}).
+		Scan(ctx, &locked)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if len(locked) == 0 {
+		return nil, ErrNotFound
+	}
+
+	last, err := client.VolunteerToken.Query().
+		Where(
+			volunteertoken.CampaignIDEQ(campaignID),
+			volunteertoken.SerialNotNil(),
+		).
+		Order(volunteertoken.BySerial(sql.OrderDesc())).
+		First(ctx)
+	next := 1
+	switch {
+	case err == nil:
+		next = *last.Serial + 1
+	case !ent.IsNotFound(err):
+		return nil, translateError(err)
+	}
+
+	builders := make([]*ent.VolunteerTokenCreate, 0, count)
+	for i := 0; i < count; i++ {
+		serial := next + i
+		builders = append(builders, client.VolunteerToken.Create().
+			SetCampaignID(campaignID).
+			SetName(fmt.Sprintf("Bon %04d", serial)).
+			SetSerial(serial).
+			SetMaxRedemptions(1))
+	}
+	rows, err := client.VolunteerToken.CreateBulk(builders...).Save(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *volunteerTokenRepo) ListNumbered(ctx context.Context, campaignID string, from, to int) ([]*ent.VolunteerToken, error) {
+	q := r.ec(ctx).VolunteerToken.Query().
+		Where(
+			volunteertoken.CampaignIDEQ(campaignID),
+			volunteertoken.SerialNotNil(),
+		)
+	if from > 0 {
+		q.Where(volunteertoken.SerialGTE(from))
+	}
+	if to > 0 {
+		q.Where(volunteertoken.SerialLTE(to))
+	}
+	rows, err := q.Order(volunteertoken.BySerial()).All(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
+}
+
+func (r *volunteerTokenRepo) Delete(ctx context.Context, campaignID, id string) error {
+	n, err := r.ec(ctx).VolunteerToken.Delete().
+		Where(
+			volunteertoken.IDEQ(id),
+			volunteertoken.CampaignIDEQ(campaignID),
+		).
+		Exec(ctx)
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *volunteerTokenRepo) IncrementRedemptionAtomic(ctx context.Context, id string) (bool, error) {
+	n, err := r.ec(ctx).VolunteerToken.Update().
+		Where(
+			volunteertoken.IDEQ(id),
+			func(s *sql.Selector) {
+				s.Where(sql.ColumnsLT(
+					s.C(volunteertoken.FieldRedemptionCount),
+					s.C(volunteertoken.FieldMaxRedemptions),
+				))
+			},
+		).
+		AddRedemptionCount(1).
+		Save(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return n > 0, nil
+}
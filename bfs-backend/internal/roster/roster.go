@@ -0,0 +1,182 @@
+// Package roster parses volunteer rosters exported from spreadsheets. Excel in
+// Swiss locale saves CSV with ';', Google Sheets with ',', so the delimiter is
+// detected from the first line.
+package roster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxNameLen  = 100
+	MaxEmailLen = 255
+	// MaxEntries bounds a single import; larger events import in batches.
+	MaxEntries = 2000
+)
+
+var ErrEmpty = errors.New("roster_empty")
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Entry is one volunteer. Line is the 1-based line in the source file.
+type Entry struct {
+	Line           int
+	Name           string
+	Email          string
+	MaxRedemptions int
+}
+
+// LineError reports a row that was skipped.
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Parse reads name, email and an optional per-person redemption limit. A
+// header row is recognised by its column names (name, email, max) and may
+// list them in any order; without one the columns are taken in that order.
+// Rows without a limit get defaultMax. Invalid rows are reported, not fatal.
+func Parse(r io.Reader, defaultMax int) ([]Entry, []LineError, error) {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(len(utf8BOM)); bytes.Equal(bom, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	sample, err := br.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, nil, err
+	}
+	if len(bytes.TrimSpace(sample)) == 0 {
+		return nil, nil, ErrEmpty
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = detectDelimiter(sample)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	cols := columns{name: 0, email: 1, max: 2}
+	var entries []Entry
+	var lineErrs []LineError
+	seen := make(map[string]int)
+	for row := 0; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				lineErrs = append(lineErrs, LineError{Line: pe.Line, Message: pe.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if row == 0 {
+			if c, ok := parseHeader(rec); ok {
+				cols = c
+				continue
+			}
+		}
+		if isBlank(rec) {
+			continue
+		}
+		if len(entries) >= MaxEntries {
+			return nil, nil, fmt.Errorf("roster has more than %d entries", MaxEntries)
+		}
+
+		e := Entry{
+			Line:           line,
+			Name:           strings.TrimSpace(cols.get(rec, cols.name)),
+			Email:          strings.TrimSpace(cols.get(rec, cols.email)),
+			MaxRedemptions: defaultMax,
+		}
+		switch {
+		case e.Name == "":
+			lineErrs = append(lineErrs, LineError{Line: line, Message: "name is required"})
+			continue
+		case utf8.RuneCountInString(e.Name) > MaxNameLen:
+			lineErrs = append(lineErrs, LineError{Line: line, Message: fmt.Sprintf("name is longer than %d characters", MaxNameLen)})
+			continue
+		case len(e.Email) > MaxEmailLen || (e.Email != "" && !strings.Contains(e.Email, "@")):
+			lineErrs = append(lineErrs, LineError{Line: line, Message: "invalid email"})
+			continue
+		}
+		if raw := strings.TrimSpace(cols.get(rec, cols.max)); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				lineErrs = append(lineErrs, LineError{Line: line, Message: "max redemptions must be a positive number"})
+				continue
+			}
+			e.MaxRedemptions = n
+		}
+		key := strings.ToLower(e.Name)
+		if prev, ok := seen[key]; ok {
+			lineErrs = append(lineErrs, LineError{Line: line, Message: fmt.Sprintf("duplicate of line %d", prev)})
+			continue
+		}
+		seen[key] = line
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 && len(lineErrs) == 0 {
+		return nil, nil, ErrEmpty
+	}
+	return entries, lineErrs, nil
+}
+
+type columns struct {
+	name, email, max int
+}
+
+func (c columns) get(rec []string, i int) string {
+	if i < 0 || i >= len(rec) {
+		return ""
+	}
+	return rec[i]
+}
+
+var headerAliases = map[string]string{
+	"name": "name", "vorname nachname": "name", "helfer": "name", "helferin": "name",
+	"email": "email", "e-mail": "email", "mail": "email",
+	"max": "max", "max_redemptions": "max", "maxredemptions": "max", "anzahl": "max", "bezüge": "max",
+}
+
+func parseHeader(rec []string) (columns, bool) {
+	c := columns{name: -1, email: -1, max: -1}
+	for i, h := range rec {
+		switch headerAliases[strings.ToLower(strings.TrimSpace(h))] {
+		case "name":
+			c.name = i
+		case "email":
+			c.email = i
+		case "max":
+			c.max = i
+		}
+	}
+	return c, c.name >= 0
+}
+
+func detectDelimiter(sample []byte) rune {
+	line, _, _ := bytes.Cut(sample, []byte("\n"))
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
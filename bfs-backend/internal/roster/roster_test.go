@@ -0,0 +1,59 @@
+package roster
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExcelSemicolonWithHeader(t *testing.T) {
+	in := "\xef\xbb\xbfE-Mail;Name;Anzahl\r\n" +
+		"anna@example.ch;Anna Muster;2\r\n" +
+		";Beat Keller;\r\n" +
+		"\r\n"
+	entries, errs, err := Parse(strings.NewReader(in), 1)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	require.Len(t, entries, 2)
+	assert.Equal(t, Entry{Line: 2, Name: "Anna Muster", Email: "anna@example.ch", MaxRedemptions: 2}, entries[0])
+	assert.Equal(t, Entry{Line: 3, Name: "Beat Keller", MaxRedemptions: 1}, entries[1])
+}
+
+func TestParseCommaWithoutHeader(t *testing.T) {
+	in := "Anna Muster,anna@example.ch\n\"Keller, Beat\",,3\n"
+	entries, errs, err := Parse(strings.NewReader(in), 1)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Keller, Beat", entries[1].Name)
+	assert.Equal(t, 3, entries[1].MaxRedemptions)
+}
+
+func TestParseReportsInvalidRows(t *testing.T) {
+	in := "name,email,max\n" +
+		"Anna,anna@example.ch,1\n" +
+		",nobody@example.ch,1\n" +
+		"Beat,not-an-email,1\n" +
+		"Chris,,zero\n" +
+		"anna,,1\n" +
+		"Dora,,0\n"
+	entries, errs, err := Parse(strings.NewReader(in), 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []LineError{
+		{Line: 3, Message: "name is required"},
+		{Line: 4, Message: "invalid email"},
+		{Line: 5, Message: "max redemptions must be a positive number"},
+		{Line: 6, Message: "duplicate of line 2"},
+		{Line: 7, Message: "max redemptions must be a positive number"},
+	}, errs)
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, in := range []string{"", "\xef\xbb\xbf", " \n\n", "name;email\n"} {
+		_, _, err := Parse(strings.NewReader(in), 1)
+		assert.ErrorIs(t, err, ErrEmpty, "input %q", in)
+	}
+}
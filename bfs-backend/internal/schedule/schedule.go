@@ -0,0 +1,240 @@
+// Package schedule evaluates recurring daily time windows ("lunch 11:30–14:00
+// on Fri–Sun") in a local time zone. Windows are given in minutes since local
+// midnight so they keep their wall-clock time across DST changes.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MinutesPerDay = 24 * 60
+
+var (
+	ErrInvalidWindow = errors.New("schedule_invalid_window")
+	ErrNoWeekdays    = errors.New("schedule_no_weekdays")
+	ErrInvalidClock  = errors.New("schedule_invalid_clock")
+)
+
+// Weekdays is a bit set indexed by time.Weekday (bit 0 = Sunday).
+type Weekdays uint8
+
+const AllWeekdays Weekdays = 1<<7 - 1
+
+func WeekdaysOf(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, d := range days {
+		w |= 1 << uint(d)
+	}
+	return w
+}
+
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w&(1<<uint(d)) != 0
+}
+
+// ISO returns the days as ISO numbers (1 = Monday … 7 = Sunday), the form the
+// API uses.
+func (w Weekdays) ISO() []int {
+	out := make([]int, 0, 7)
+	for iso := 1; iso <= 7; iso++ {
+		if w.Has(time.Weekday(iso % 7)) {
+			out = append(out, iso)
+		}
+	}
+	return out
+}
+
+// Format names the days Monday first, joining runs of three or more
+// consecutive days with an en dash: "Mo–Fr, So". names holds the day names in
+// ISO order (Monday first).
+func (w Weekdays) Format(names [7]string) string {
+	days := w.ISO()
+	var parts []string
+	for i := 0; i < len(days); {
+		j := i
+		for j+1 < len(days) && days[j+1] == days[j]+1 {
+			j++
+		}
+		switch {
+		case j-i >= 2:
+			parts = append(parts, names[days[i]-1]+"–"+names[days[j]-1])
+		default:
+			for k := i; k <= j; k++ {
+				parts = append(parts, names[days[k]-1])
+			}
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WeekdaysFromISO is the inverse of ISO. Values outside 1..7 are rejected.
+func WeekdaysFromISO(days []int) (Weekdays, error) {
+	var w Weekdays
+	for _, iso := range days {
+		if iso < 1 || iso > 7 {
+			return 0, ErrInvalidWindow
+		}
+		w |= 1 << uint(iso%7)
+	}
+	return w, nil
+}
+
+// Window is a daily window [Start, End) in minutes since local midnight on
+// the given weekdays. Windows do not cross midnight.
+type Window struct {
+	Weekdays Weekdays
+	Start    int
+	End      int
+}
+
+func (w Window) Validate() error {
+	if w.Weekdays&AllWeekdays == 0 {
+		return ErrNoWeekdays
+	}
+	if w.Start < 0 || w.End > MinutesPerDay || w.Start >= w.End {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Occurrence is one concrete instance of a window. Index points into the
+// slice passed to Active or Next.
+type Occurrence struct {
+	Index int
+	Start time.Time
+	End   time.Time
+}
+
+// Active returns the window occurrence containing t, if any. When windows
+// overlap the one listed first wins.
+func Active(windows []Window, t time.Time, loc *time.Location) (Occurrence, bool) {
+	day := DayStart(t, loc)
+	for i, w := range windows {
+		occ, ok := occurrenceOn(w, i, day, loc)
+		if ok && !t.Before(occ.Start) && t.Before(occ.End) {
+			return occ, true
+		}
+	}
+	return Occurrence{}, false
+}
+
+// Next returns the earliest occurrence that ends after t, looking at most
+// days ahead. An occurrence that is currently running is returned as well.
+func Next(windows []Window, t time.Time, loc *time.Location, days int) (Occurrence, bool) {
+	day := DayStart(t, loc)
+	for d := 0; d <= days; d++ {
+		var candidates []Occurrence
+		for i, w := range windows {
+			if occ, ok := occurrenceOn(w, i, day, loc); ok && occ.End.After(t) {
+				candidates = append(candidates, occ)
+			}
+		}
+		if len(candidates) > 0 {
+			sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].Start.Before(candidates[b].Start) })
+			return candidates[0], true
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+	return Occurrence{}, false
+}
+
+// DayStart returns local midnight of the day containing t.
+func DayStart(t time.Time, loc *time.Location) time.Time {
+	lt := t.In(loc)
+	return time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, loc)
+}
+
+// DayBounds returns [midnight, next midnight) of the local day containing t.
+func DayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := DayStart(t, loc)
+	return start, time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+}
+
+func occurrenceOn(w Window, idx int, day time.Time, loc *time.Location) (Occurrence, bool) {
+	if !w.Weekdays.Has(day.Weekday()) {
+		return Occurrence{}, false
+	}
+	return Occurrence{
+		Index: idx,
+		Start: atMinute(day, w.Start, loc),
+		End:   atMinute(day, w.End, loc),
+	}, true
+}
+
+func atMinute(day time.Time, minute int, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, loc)
+}
+
+// ParseClock parses "HH:MM" into minutes since midnight. "24:00" is accepted
+// as the end of the day.
+func ParseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || len(mm) != 2 {
+		return 0, ErrInvalidClock
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, ErrInvalidClock
+	}
+	return h*60 + m, nil
+}
+
+// FormatClock formats minutes since midnight as "HH:MM".
+func FormatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// Calendar is a weekly schedule with exceptions for single dates. The windows
+// of an exception replace the weekly windows on that date; an exception
+// without windows closes the whole day. Exceptions are keyed by DateKey and
+// their windows' Weekdays are ignored.
+type Calendar struct {
+	Weekly     []Window
+	Exceptions map[string][]Window
+}
+
+// DateKey formats the local date of t as "2006-01-02".
+func DateKey(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(time.DateOnly)
+}
+
+// Active returns the occurrence containing t, if any. Index points into the
+// windows in effect that day: the exception's when there is one.
+func (c Calendar) Active(t time.Time, loc *time.Location) (Occurrence, bool) {
+	return Active(c.windowsOn(DayStart(t, loc)), t, loc)
+}
+
+// Next returns the earliest occurrence that ends after t, looking at most
+// days ahead. An occurrence that is currently running is returned as well.
+func (c Calendar) Next(t time.Time, loc *time.Location, days int) (Occurrence, bool) {
+	day := DayStart(t, loc)
+	from := t
+	for d := 0; d <= days; d++ {
+		if occ, ok := Next(c.windowsOn(day), from, loc, 0); ok {
+			return occ, true
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+		from = day
+	}
+	return Occurrence{}, false
+}
+
+func (c Calendar) windowsOn(day time.Time) []Window {
+	exception, ok := c.Exceptions[day.Format(time.DateOnly)]
+	if !ok {
+		return c.Weekly
+	}
+	windows := make([]Window, len(exception))
+	for i, w := range exception {
+		w.Weekdays = AllWeekdays
+		windows[i] = w
+	}
+	return windows
+}
@@ -0,0 +1,172 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zurich(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	return loc
+}
+
+func festival() []Window {
+	return []Window{
+		{Weekdays: AllWeekdays, Start: 11*60 + 30, End: 14 * 60},
+		{Weekdays: AllWeekdays, Start: 17*60 + 30, End: 20 * 60},
+	}
+}
+
+func TestActive(t *testing.T) {
+	loc := zurich(t)
+	at := func(h, m int) time.Time { return time.Date(2026, 7, 3, h, m, 0, 0, loc) }
+
+	occ, ok := Active(festival(), at(12, 0), loc)
+	require.True(t, ok)
+	assert.Equal(t, 0, occ.Index)
+	assert.True(t, occ.Start.Equal(at(11, 30)))
+	assert.True(t, occ.End.Equal(at(14, 0)))
+
+	_, ok = Active(festival(), at(14, 0), loc)
+	assert.False(t, ok, "end is exclusive")
+
+	occ, ok = Active(festival(), at(17, 30), loc)
+	require.True(t, ok)
+	assert.Equal(t, 1, occ.Index)
+
+	// Same instant expressed in UTC.
+	_, ok = Active(festival(), at(12, 0).UTC(), loc)
+	assert.True(t, ok)
+}
+
+func TestNext(t *testing.T) {
+	loc := zurich(t)
+	at := func(d, h, m int) time.Time { return time.Date(2026, 7, d, h, m, 0, 0, loc) }
+
+	occ, ok := Next(festival(), at(3, 15, 0), loc, 7)
+	require.True(t, ok)
+	assert.True(t, occ.Start.Equal(at(3, 17, 30)))
+
+	occ, ok = Next(festival(), at(3, 21, 0), loc, 7)
+	require.True(t, ok)
+	assert.True(t, occ.Start.Equal(at(4, 11, 30)), "rolls over to tomorrow's lunch")
+
+	occ, ok = Next(festival(), at(3, 12, 0), loc, 7)
+	require.True(t, ok)
+	assert.True(t, occ.Start.Equal(at(3, 11, 30)), "running window counts as next")
+
+	// 2026-07-03 is a Friday; a Sunday-only window is two days out.
+	sunday := []Window{{Weekdays: WeekdaysOf(time.Sunday), Start: 600, End: 660}}
+	occ, ok = Next(sunday, at(3, 12, 0), loc, 7)
+	require.True(t, ok)
+	assert.True(t, occ.Start.Equal(at(5, 10, 0)))
+
+	_, ok = Next(sunday, at(3, 12, 0), loc, 1)
+	assert.False(t, ok)
+}
+
+func TestDSTKeepsWallClock(t *testing.T) {
+	loc := zurich(t)
+	// 2026-03-29 switches to CEST at 02:00.
+	w := []Window{{Weekdays: AllWeekdays, Start: 11*60 + 30, End: 14 * 60}}
+	occ, ok := Active(w, time.Date(2026, 3, 29, 12, 0, 0, 0, loc), loc)
+	require.True(t, ok)
+	assert.Equal(t, "11:30", occ.Start.In(loc).Format("15:04"))
+
+	start, end := DayBounds(time.Date(2026, 3, 29, 12, 0, 0, 0, loc), loc)
+	assert.Equal(t, 23*time.Hour, end.Sub(start))
+}
+
+func TestWeekdaysISO(t *testing.T) {
+	w, err := WeekdaysFromISO([]int{5, 6, 7})
+	require.NoError(t, err)
+	assert.True(t, w.Has(time.Friday))
+	assert.True(t, w.Has(time.Sunday))
+	assert.False(t, w.Has(time.Monday))
+	assert.Equal(t, []int{5, 6, 7}, w.ISO())
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, AllWeekdays.ISO())
+
+	_, err = WeekdaysFromISO([]int{0})
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+}
+
+func TestWeekdaysFormat(t *testing.T) {
+	names := [7]string{"Mo", "Di", "Mi", "Do", "Fr", "Sa", "So"}
+	cases := []struct {
+		days []int
+		want string
+	}{
+		{[]int{1, 2, 3, 4, 5}, "Mo–Fr"},
+		{[]int{1, 2, 3, 4, 5, 7}, "Mo–Fr, So"},
+		{[]int{6, 7}, "Sa, So"},
+		{[]int{1, 3, 5}, "Mo, Mi, Fr"},
+		{[]int{1, 2, 3, 4, 5, 6, 7}, "Mo–So"},
+	}
+	for _, c := range cases {
+		w, err := WeekdaysFromISO(c.days)
+		require.NoError(t, err)
+		assert.Equal(t, c.want, w.Format(names), "%v", c.days)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Window{Weekdays: AllWeekdays, Start: 0, End: MinutesPerDay}.Validate())
+	assert.ErrorIs(t, Window{Weekdays: AllWeekdays, Start: 600, End: 600}.Validate(), ErrInvalidWindow)
+	assert.ErrorIs(t, Window{Weekdays: AllWeekdays, Start: 1200, End: 60}.Validate(), ErrInvalidWindow)
+	assert.ErrorIs(t, Window{Start: 0, End: 60}.Validate(), ErrNoWeekdays)
+}
+
+func TestClock(t *testing.T) {
+	for in, want := range map[string]int{"00:00": 0, "11:30": 690, "9:05": 545, "24:00": MinutesPerDay} {
+		got, err := ParseClock(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "11", "11:3", "11:60", "24:01", "-1:00", "ab:cd"} {
+		_, err := ParseClock(in)
+		assert.ErrorIs(t, err, ErrInvalidClock, in)
+	}
+	assert.Equal(t, "09:05", FormatClock(545))
+	assert.Equal(t, "24:00", FormatClock(MinutesPerDay))
+}
+
+func TestCalendar(t *testing.T) {
+	loc := zurich(t)
+	at := func(day, h, m int) time.Time { return time.Date(2026, 7, day, h, m, 0, 0, loc) }
+	c := Calendar{
+		Weekly: []Window{{Weekdays: WeekdaysOf(time.Friday, time.Saturday, time.Sunday), Start: 11 * 60, End: MinutesPerDay}},
+		Exceptions: map[string][]Window{
+			"2026-07-04": nil,
+			"2026-07-05": {{Start: 10 * 60, End: 16 * 60}},
+		},
+	}
+
+	occ, ok := c.Active(at(3, 23, 59), loc)
+	require.True(t, ok, "friday runs until midnight")
+	assert.True(t, occ.End.Equal(at(4, 0, 0)))
+
+	_, ok = c.Active(at(4, 12, 0), loc)
+	assert.False(t, ok, "saturday is closed by exception")
+
+	occ, ok = c.Active(at(5, 10, 30), loc)
+	require.True(t, ok, "sunday opens early by exception")
+	assert.True(t, occ.End.Equal(at(5, 16, 0)))
+
+	occ, ok = c.Next(at(4, 9, 0), loc, 7)
+	require.True(t, ok)
+	assert.True(t, occ.Start.Equal(at(5, 10, 0)), "skips the closed saturday")
+
+	occ, ok = c.Next(at(5, 17, 0), loc, 7)
+	require.True(t, ok)
+	assert.True(t, occ.Start.Equal(at(10, 11, 0)), "next friday")
+
+	_, ok = c.Next(at(5, 17, 0), loc, 3)
+	assert.False(t, ok, "beyond the horizon")
+
+	assert.Equal(t, "2026-07-04", DateKey(time.Date(2026, 7, 3, 22, 30, 0, 0, time.UTC), loc))
+}
@@ -43,6 +43,11 @@ func (AdminInvite) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			StorageKey("used_at"),
+		// Language of the invite email.
+		field.String("locale").
+			MaxLen(5).
+			Optional().
+			Nillable(),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable().
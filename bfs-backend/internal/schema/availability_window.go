@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AvailabilityWindow limits when a product, or every product in a category,
+// can be ordered, in Europe/Zurich time. Without windows a product is always
+// orderable. Windows with a date apply on that date only (an event day) and
+// replace the weekly windows there.
+type AvailabilityWindow struct {
+	ent.Schema
+}
+
+func (AvailabilityWindow) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "availability_window"},
+	}
+}
+
+func (AvailabilityWindow) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		// Exactly one of product_id and category_id is set.
+		field.String("product_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.String("category_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		// Bit set indexed by time.Weekday (bit 0 = Sunday); see package
+		// schedule. Ignored when date is set.
+		field.Int("weekdays").
+			Default(127),
+		// Local date as "2006-01-02".
+		field.String("date").
+			MaxLen(10).
+			Optional().
+			Nillable(),
+		// Minutes since local midnight, end exclusive.
+		field.Int("start_minute").
+			NonNegative(),
+		field.Int("end_minute").
+			Positive(),
+	}
+}
+
+func (AvailabilityWindow) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("product", Product.Type).
+			Ref("availability_windows").
+			Field("product_id").
+			Unique(),
+		edge.From("category", Category.Type).
+			Ref("availability_windows").
+			Field("category_id").
+			Unique(),
+	}
+}
+
+func (AvailabilityWindow) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("product_id"),
+		index.Fields("category_id"),
+	}
+}
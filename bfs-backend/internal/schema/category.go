@@ -8,6 +8,8 @@ import (
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+
+	"backend/internal/i18n"
 )
 
 type Category struct {
@@ -30,6 +32,9 @@ func (Category) Fields() []ent.Field {
 			Default(true),
 		field.Int("position").
 			Default(0),
+		// French and English names; the German one is name.
+		field.JSON("translations", i18n.Translations{}).
+			Optional(),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -42,5 +47,7 @@ func (Category) Fields() []ent.Field {
 func (Category) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("products", Product.Type),
+		edge.To("price_rules", PriceRule.Type),
+		edge.To("availability_windows", AvailabilityWindow.Type),
 	}
 }
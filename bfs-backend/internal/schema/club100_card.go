@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+
+	"backend/internal/id"
+)
+
+// Club100Card is a printed member card. Its QR code carries the signed token;
+// a member has at most one card that is not revoked, reissuing revokes the
+// previous one.
+type Club100Card struct {
+	ent.Schema
+}
+
+func (Club100Card) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "club100_card"},
+	}
+}
+
+func (Club100Card) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("member_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("token").
+			MaxLen(36).
+			NotEmpty().
+			DefaultFunc(id.New).
+			Unique(),
+		field.Time("revoked_at").
+			Optional().
+			Nillable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+func (Club100Card) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("member", Club100Member.Type).
+			Field("member_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (Club100Card) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("member_id"),
+	}
+}
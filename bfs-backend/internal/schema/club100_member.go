@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Club100Member is the local copy of a 100 Club member, synced from the
+// configured membership provider. POS lookups read this table so they keep
+// working when the provider is slow or down. Members who leave the group are
+// kept with active=false so their redemptions still resolve to a name.
+type Club100Member struct {
+	ent.Schema
+}
+
+func (Club100Member) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "club100_member"},
+	}
+}
+
+func (Club100Member) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("external_person_id").
+			MaxLen(50).
+			NotEmpty(),
+		field.String("provider").
+			MaxLen(20).
+			Default("elvanto"),
+		field.String("first_name").
+			MaxLen(100),
+		field.String("last_name").
+			MaxLen(100),
+		field.String("preferred_name").
+			MaxLen(100).
+			Optional().
+			Nillable(),
+		field.Bool("active").
+			Default(true),
+		field.Time("synced_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+func (Club100Member) Indexes() []ent.Index {
+	return []ent.Index{
+		// A person ID is only unique within its provider.
+		index.Fields("provider", "external_person_id").
+			Unique(),
+		index.Fields("active"),
+	}
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Club100Period is an entitlement period (an event, a calendar year, ...).
+// Members get club100_max_redemptions free products per period; remaining
+// counts only look at redemptions inside the period active at the time.
+// Periods never overlap; outside every period the allowance resets daily.
+type Club100Period struct {
+	ent.Schema
+}
+
+func (Club100Period) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "club100_period"},
+	}
+}
+
+func (Club100Period) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("name").
+			MaxLen(100).
+			NotEmpty(),
+		field.Time("starts_at"),
+		// Exclusive.
+		field.Time("ends_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+func (Club100Period) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("starts_at"),
+	}
+}
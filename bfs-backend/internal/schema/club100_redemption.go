@@ -8,6 +8,7 @@ import (
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 )
 
 type Club100Redemption struct {
@@ -23,10 +24,14 @@ func (Club100Redemption) Annotations() []schema.Annotation {
 func (Club100Redemption) Fields() []ent.Field {
 	return []ent.Field{
 		nanoidPK(),
-		field.String("elvanto_person_id").
+		field.String("external_person_id").
 			MaxLen(50).
 			NotEmpty(),
-		field.String("elvanto_person_name").
+		// provider is the membership provider the person ID belongs to.
+		field.String("provider").
+			MaxLen(20).
+			Default("elvanto"),
+		field.String("person_name").
 			MaxLen(100).
 			NotEmpty(),
 		field.String("order_id").
@@ -34,12 +39,22 @@ func (Club100Redemption) Fields() []ent.Field {
 			NotEmpty(),
 		field.Int("free_product_quantity").
 			Default(1),
+		field.String("event_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
 	}
 }
 
+func (Club100Redemption) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("provider", "external_person_id"),
+	}
+}
+
 func (Club100Redemption) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("order", Order.Type).
@@ -47,5 +62,9 @@ func (Club100Redemption) Edges() []ent.Edge {
 			Field("order_id").
 			Unique().
 			Required(),
+		edge.From("event", Event.Type).
+			Ref("club100_redemptions").
+			Field("event_id").
+			Unique(),
 	}
 }
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Club100SyncRun records one sync of the member directory from Elvanto; the
+// latest row is the sync status shown in the admin.
+type Club100SyncRun struct {
+	ent.Schema
+}
+
+func (Club100SyncRun) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "club100_sync_run"},
+	}
+}
+
+func (Club100SyncRun) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.Enum("trigger").
+			Values("scheduled", "manual"),
+		// Membership provider the run read from.
+		field.String("provider").
+			MaxLen(20).
+			Default("elvanto"),
+		field.Enum("status").
+			Values("running", "succeeded", "failed").
+			Default("running"),
+		field.Int("member_count").
+			NonNegative().
+			Default(0),
+		field.Int("added_count").
+			NonNegative().
+			Default(0),
+		field.Int("removed_count").
+			NonNegative().
+			Default(0),
+		field.String("error").
+			MaxLen(1000).
+			Optional().
+			Nillable(),
+		field.Time("started_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("finished_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (Club100SyncRun) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("started_at"),
+	}
+}
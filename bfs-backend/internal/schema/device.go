@@ -70,6 +70,19 @@ func (Device) Fields() []ent.Field {
 		field.Time("pairing_code_expires_at").
 			Optional().
 			Nillable(),
+		// POS configuration overrides merged over the global settings; null
+		// inherits. Payment methods are cash/card/twint, gratis types
+		// guest/vip/staff/100club.
+		field.Enum("pos_mode").
+			Values("QR_CODE", "JETON", "HYBRID").
+			Optional().
+			Nillable(),
+		field.Strings("payment_methods").
+			Optional(),
+		field.Strings("visible_category_ids").
+			Optional(),
+		field.Strings("gratis_types").
+			Optional(),
 	}
 }
 
@@ -78,6 +91,8 @@ func (Device) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("products", Product.Type).
 			Through("device_products", DeviceProduct.Type),
+		edge.To("variants", ProductVariant.Type).
+			Through("device_variants", DeviceVariant.Type),
 		edge.To("order_payments", OrderPayment.Type),
 		edge.To("inventory_ledger_entries", InventoryLedger.Type),
 	}
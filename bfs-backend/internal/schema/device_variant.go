@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// DeviceVariant routes a product variant to a station, overriding the
+// product's device_product routing.
+type DeviceVariant struct {
+	ent.Schema
+}
+
+func (DeviceVariant) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "device_variant"},
+		field.ID("device_id", "variant_id"),
+	}
+}
+
+func (DeviceVariant) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("device_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("variant_id").
+			MaxLen(36).
+			NotEmpty(),
+	}
+}
+
+func (DeviceVariant) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("device", Device.Type).
+			Field("device_id").
+			Unique().
+			Required(),
+		edge.To("variant", ProductVariant.Type).
+			Field("variant_id").
+			Unique().
+			Required(),
+	}
+}
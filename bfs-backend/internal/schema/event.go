@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Event is one run of the food service (summer festival, Christmas market,
+// ...). At most one event is active; new orders, ledger entries, staff meal
+// campaigns and Club100 redemptions are tagged with it, and its product
+// overrides change the menu and prices customers see.
+type Event struct {
+	ent.Schema
+}
+
+func (Event) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "event"},
+	}
+}
+
+func (Event) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("name").
+			MaxLen(100).
+			NotEmpty(),
+		field.Time("starts_at"),
+		// Exclusive.
+		field.Time("ends_at"),
+		field.Bool("active").
+			Default(false),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+func (Event) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("products", EventProduct.Type),
+		edge.To("orders", Order.Type),
+		edge.To("inventory_ledger_entries", InventoryLedger.Type),
+		edge.To("volunteer_campaigns", VolunteerCampaign.Type),
+		edge.To("club100_redemptions", Club100Redemption.Type),
+	}
+}
+
+func (Event) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("starts_at"),
+	}
+}
@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// EventProduct overrides one product for one event. Products without a row
+// are sold as in the base catalog.
+type EventProduct struct {
+	ent.Schema
+}
+
+func (EventProduct) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "event_product"},
+	}
+}
+
+func (EventProduct) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("event_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("product_id").
+			MaxLen(36).
+			NotEmpty(),
+		// False takes the product off the event's menu.
+		field.Bool("available").
+			Default(true),
+		// Nil keeps the base price.
+		field.Int64("price_cents").
+			NonNegative().
+			Optional().
+			Nillable(),
+	}
+}
+
+func (EventProduct) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("event", Event.Type).
+			Ref("products").
+			Field("event_id").
+			Unique().
+			Required(),
+		edge.To("product", Product.Type).
+			Field("product_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (EventProduct) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("event_id", "product_id").Unique(),
+	}
+}
@@ -48,6 +48,16 @@ func (InventoryLedger) Fields() []ent.Field {
 		field.String("created_by").
 			Optional().
 			Nillable(),
+		field.String("event_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		// Set for stock of a product variant; the product's stock is the sum
+		// over all its entries.
+		field.String("variant_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
 	}
 }
 
@@ -70,5 +80,13 @@ func (InventoryLedger) Edges() []ent.Edge {
 			Ref("inventory_ledger_entries").
 			Field("device_id").
 			Unique(),
+		edge.From("event", Event.Type).
+			Ref("inventory_ledger_entries").
+			Field("event_id").
+			Unique(),
+		edge.From("variant", ProductVariant.Type).
+			Ref("inventory_ledger_entries").
+			Field("variant_id").
+			Unique(),
 	}
 }
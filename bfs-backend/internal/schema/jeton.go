@@ -41,5 +41,6 @@ func (Jeton) Fields() []ent.Field {
 func (Jeton) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("products", Product.Type),
+		edge.To("variants", ProductVariant.Type),
 	}
 }
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// MembershipUpload is a member list uploaded for MEMBERSHIP_PROVIDER=csv.
+// The latest upload is the directory; older ones are kept for reference.
+type MembershipUpload struct {
+	ent.Schema
+}
+
+func (MembershipUpload) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "membership_upload"},
+	}
+}
+
+func (MembershipUpload) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("filename").
+			MaxLen(255),
+		field.Text("content"),
+		field.Int("member_count").
+			NonNegative(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+func (MembershipUpload) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("created_at"),
+	}
+}
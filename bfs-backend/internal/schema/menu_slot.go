@@ -6,6 +6,8 @@ import (
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+
+	"backend/internal/i18n"
 )
 
 type MenuSlot struct {
@@ -29,6 +31,9 @@ func (MenuSlot) Fields() []ent.Field {
 			NotEmpty(),
 		field.Int("sequence").
 			Default(0),
+		// French and English names; the German one is name.
+		field.JSON("translations", i18n.Translations{}).
+			Optional(),
 	}
 }
 
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OpeningHoursException replaces the weekly opening hours on one
+// Europe/Zurich date. A date with a single row without minutes is closed all
+// day; otherwise each row is one opening window on that date.
+type OpeningHoursException struct {
+	ent.Schema
+}
+
+func (OpeningHoursException) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "opening_hours_exception"},
+	}
+}
+
+func (OpeningHoursException) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		// Local date as "2006-01-02".
+		field.String("date").
+			MaxLen(10).
+			NotEmpty(),
+		field.String("label").
+			MaxLen(50).
+			Default(""),
+		field.Int("start_minute").
+			NonNegative().
+			Optional().
+			Nillable(),
+		field.Int("end_minute").
+			Positive().
+			Optional().
+			Nillable(),
+	}
+}
+
+func (OpeningHoursException) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("date"),
+	}
+}
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+)
+
+// OpeningHoursWindow is a recurring daily opening window of the food system
+// in Europe/Zurich time.
+type OpeningHoursWindow struct {
+	ent.Schema
+}
+
+func (OpeningHoursWindow) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "opening_hours_window"},
+	}
+}
+
+func (OpeningHoursWindow) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("label").
+			MaxLen(50).
+			Default(""),
+		// Bit set indexed by time.Weekday (bit 0 = Sunday); see package schedule.
+		field.Int("weekdays").
+			Default(127),
+		// Minutes since local midnight, end exclusive.
+		field.Int("start_minute").
+			NonNegative(),
+		field.Int("end_minute").
+			Positive(),
+	}
+}
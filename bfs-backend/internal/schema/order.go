@@ -53,6 +53,15 @@ func (Order) Fields() []ent.Field {
 		field.Int("payrexx_transaction_id").
 			Optional().
 			Nillable(),
+		field.String("event_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		// Language the customer ordered in, for the receipt.
+		field.String("locale").
+			MaxLen(5).
+			Optional().
+			Nillable(),
 	}
 }
 
@@ -62,5 +71,9 @@ func (Order) Edges() []ent.Edge {
 		edge.To("lines", OrderLine.Type),
 		edge.To("inventory_ledger_entries", InventoryLedger.Type),
 		edge.To("club100_redemptions", Club100Redemption.Type),
+		edge.From("event", Event.Type).
+			Ref("orders").
+			Field("event_id").
+			Unique(),
 	}
 }
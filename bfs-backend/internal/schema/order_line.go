@@ -49,6 +49,32 @@ func (OrderLine) Fields() []ent.Field {
 			MaxLen(20).
 			Optional().
 			Nillable(),
+		// Units already handed out at a station. The line counts as redeemed
+		// (and gets its redemption row) once this reaches quantity.
+		field.Int("redeemed_quantity").
+			Default(0),
+		// The variant sold, with its name kept for receipts and stations.
+		field.String("variant_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.String("variant_name").
+			MaxLen(20).
+			Optional().
+			Nillable(),
+		// The price rule that set unit_price_cents, and the price it started
+		// from. Both are nil when the line sold at its list price.
+		field.String("price_rule_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.Int64("list_price_cents").
+			Optional().
+			Nillable(),
+		// Allergens of the product at the time of sale; a menu's include
+		// those of the picked options.
+		field.Strings("allergens").
+			Optional(),
 	}
 }
 
@@ -74,6 +100,14 @@ func (OrderLine) Edges() []ent.Edge {
 			Ref("order_lines").
 			Field("menu_slot_id").
 			Unique(),
+		edge.From("variant", ProductVariant.Type).
+			Ref("order_lines").
+			Field("variant_id").
+			Unique(),
+		edge.From("price_rule", PriceRule.Type).
+			Ref("order_lines").
+			Field("price_rule_id").
+			Unique(),
 		edge.To("redemption", OrderLineRedemption.Type).
 			Unique(),
 		edge.To("inventory_ledger_entries", InventoryLedger.Type),
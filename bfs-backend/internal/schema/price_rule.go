@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// PriceRule lowers the price of a product, or of every product in a
+// category, during a recurring daily window in Europe/Zurich time: happy
+// hour drinks, food cleared out before closing. Rules do not stack; when
+// several apply, the lowest price wins.
+type PriceRule struct {
+	ent.Schema
+}
+
+func (PriceRule) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "price_rule"},
+	}
+}
+
+func (PriceRule) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("name").
+			MaxLen(100).
+			NotEmpty(),
+		// Exactly one of product_id and category_id is set.
+		field.String("product_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.String("category_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		// Bit set indexed by time.Weekday (bit 0 = Sunday); see package schedule.
+		field.Int("weekdays").
+			Default(127),
+		// Minutes since local midnight, end exclusive.
+		field.Int("start_minute").
+			NonNegative(),
+		field.Int("end_minute").
+			Positive(),
+		// percent: value is the percentage off (1-100); amount: value is the
+		// cents off. Prices never drop below zero.
+		field.Enum("kind").
+			Values("percent", "amount"),
+		field.Int64("value").
+			Positive(),
+		field.Bool("active").
+			Default(true),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+func (PriceRule) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("product", Product.Type).
+			Ref("price_rules").
+			Field("product_id").
+			Unique(),
+		edge.From("category", Category.Type).
+			Ref("price_rules").
+			Field("category_id").
+			Unique(),
+		edge.To("order_lines", OrderLine.Type),
+	}
+}
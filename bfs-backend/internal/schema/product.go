@@ -8,6 +8,8 @@ import (
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+
+	"backend/internal/i18n"
 )
 
 type Product struct {
@@ -48,6 +50,16 @@ func (Product) Fields() []ent.Field {
 			Nillable(),
 		field.Bool("is_active").
 			Default(true),
+		// Keys from package allergen, in its order. Menus declare only their
+		// own; the options picked for their slots add theirs.
+		field.Strings("allergens").
+			Default([]string{}),
+		field.Strings("dietary_tags").
+			Default([]string{}),
+		// French and English names and descriptions; the German ones are
+		// the fields above.
+		field.JSON("translations", i18n.Translations{}).
+			Optional(),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -68,6 +80,9 @@ func (Product) Edges() []ent.Edge {
 			Ref("products").
 			Field("jeton_id").
 			Unique(),
+		edge.To("variants", ProductVariant.Type),
+		edge.To("price_rules", PriceRule.Type),
+		edge.To("availability_windows", AvailabilityWindow.Type),
 		edge.To("menu_slots", MenuSlot.Type),
 		edge.From("menu_slot_options", MenuSlot.Type).
 			Ref("option_products").
@@ -83,5 +98,8 @@ func (Product) Edges() []ent.Edge {
 		edge.From("volunteer_campaigns", VolunteerCampaign.Type).
 			Ref("products").
 			Through("campaign_products", VolunteerCampaignProduct.Type),
+		edge.From("volunteer_choice_groups", VolunteerCampaignChoiceGroup.Type).
+			Ref("products").
+			Through("volunteer_choice_options", VolunteerCampaignChoiceOption.Type),
 	}
 }
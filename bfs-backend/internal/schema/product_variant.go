@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// ProductVariant is a sellable size or flavour of a simple product, e.g. a
+// 3dl and a 5dl drink. Once a product has active variants, customers pick one
+// and the variant's price, stock, jeton and stations apply.
+type ProductVariant struct {
+	ent.Schema
+}
+
+func (ProductVariant) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "product_variant"},
+	}
+}
+
+func (ProductVariant) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("product_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("name").
+			MaxLen(20).
+			NotEmpty(),
+		field.Int64("price_cents").
+			NonNegative().
+			Default(0),
+		// Nil uses the product's jeton.
+		field.String("jeton_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.Int("position").
+			Default(0),
+		field.Bool("is_active").
+			Default(true),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+func (ProductVariant) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("product", Product.Type).
+			Ref("variants").
+			Field("product_id").
+			Unique().
+			Required(),
+		edge.From("jeton", Jeton.Type).
+			Ref("variants").
+			Field("jeton_id").
+			Unique(),
+		// Stations serving this variant. Without any, the variant goes to the
+		// product's stations.
+		edge.From("devices", Device.Type).
+			Ref("variants").
+			Through("device_variants", DeviceVariant.Type),
+		edge.To("order_lines", OrderLine.Type),
+		edge.To("inventory_ledger_entries", InventoryLedger.Type),
+	}
+}
+
+func (ProductVariant) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("product_id", "name").Unique(),
+	}
+}
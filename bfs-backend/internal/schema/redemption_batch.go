@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// RedemptionBatch groups the units handed out by one station redeem request so
+// the request can be undone as a whole. The undo columns double as audit trail.
+type RedemptionBatch struct {
+	ent.Schema
+}
+
+func (RedemptionBatch) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "redemption_batch"},
+	}
+}
+
+func (RedemptionBatch) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("order_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("station_device_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("undone_at").
+			Optional().
+			Nillable(),
+		field.String("undone_by_device_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
+		field.String("undone_by_user_id").
+			Optional().
+			Nillable(),
+		field.String("undo_reason").
+			MaxLen(200).
+			Optional().
+			Nillable(),
+	}
+}
+
+func (RedemptionBatch) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("order", Order.Type).
+			Field("order_id").
+			Unique().
+			Required(),
+		edge.To("items", RedemptionBatchItem.Type),
+	}
+}
+
+func (RedemptionBatch) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("order_id"),
+		index.Fields("undone_at"),
+	}
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+type RedemptionBatchItem struct {
+	ent.Schema
+}
+
+func (RedemptionBatchItem) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "redemption_batch_item"},
+	}
+}
+
+func (RedemptionBatchItem) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("batch_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("order_line_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.Int("quantity").
+			Positive(),
+	}
+}
+
+func (RedemptionBatchItem) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("batch", RedemptionBatch.Type).
+			Ref("items").
+			Field("batch_id").
+			Unique().
+			Required(),
+		edge.To("order_line", OrderLine.Type).
+			Field("order_line_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (RedemptionBatchItem) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("batch_id"),
+	}
+}
@@ -30,10 +30,25 @@ func (Settings) Fields() []ent.Field {
 			Values("QR_CODE", "JETON", "HYBRID").
 			Default("QR_CODE").
 			StorageKey("pos_mode"),
+		// Manual switch. Off closes the system regardless of opening hours.
 		field.Bool("system_enabled").
 			Default(true),
+		// Whether the opening hours decide when the system is open.
+		field.Bool("opening_hours_enabled").
+			Default(false),
+		// Manual override opening the system outside its opening hours.
+		field.Time("system_open_until").
+			Optional().
+			Nillable(),
+		// Shown to customers while closed; empty uses the app's default text.
+		field.String("closed_message").
+			MaxLen(500).
+			Default(""),
 		field.Int("club100_max_redemptions").
 			Default(2),
+		// Whether stations still accept unsigned QR codes.
+		field.Bool("accept_legacy_qr").
+			Default(true),
 		field.Time("updated_at").
 			Default(time.Now).
 			UpdateDefault(time.Now),
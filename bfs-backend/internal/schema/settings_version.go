@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+)
+
+// SettingsVersion is an immutable snapshot of the global settings and jetons
+// taken after every change, so the history shows who changed what and any
+// version can be restored.
+type SettingsVersion struct {
+	ent.Schema
+}
+
+func (SettingsVersion) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "settings_version"},
+	}
+}
+
+func (SettingsVersion) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.Int("version").
+			Positive().
+			Unique().
+			Immutable(),
+		// What changed: pos_mode, system_enabled, club100, jeton, rollback, ...
+		field.String("change").
+			MaxLen(50).
+			NotEmpty().
+			Immutable(),
+		field.JSON("snapshot", json.RawMessage{}).
+			Immutable(),
+		// Acting user; empty for the baseline taken before the first change.
+		field.String("created_by").
+			Optional().
+			Nillable().
+			Immutable(),
+		field.String("created_by_name").
+			MaxLen(255).
+			Optional().
+			Nillable().
+			Immutable(),
+		// Version restored by a rollback.
+		field.Int("rolled_back_from").
+			Optional().
+			Nillable().
+			Immutable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
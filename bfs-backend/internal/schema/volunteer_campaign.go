@@ -52,6 +52,25 @@ func (VolunteerCampaign) Fields() []ent.Field {
 		field.Int("redemption_count").
 			Default(0).
 			NonNegative(),
+		// Per Europe/Zurich day: for the shared QR across everyone, for a
+		// personal token per volunteer. Nil means unlimited.
+		field.Int("max_redemptions_per_day").
+			Positive().
+			Optional().
+			Nillable(),
+		field.Int("max_per_person_per_day").
+			Positive().
+			Optional().
+			Nillable(),
+		// Blob URL of the logo printed on the campaign's slips.
+		field.String("logo_url").
+			MaxLen(1024).
+			Optional().
+			Nillable(),
+		field.String("event_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -66,6 +85,10 @@ func (VolunteerCampaign) Edges() []ent.Edge {
 		edge.To("products", Product.Type).
 			Through("campaign_products", VolunteerCampaignProduct.Type),
 		edge.To("redemptions", VolunteerRedemption.Type),
+		edge.From("event", Event.Type).
+			Ref("volunteer_campaigns").
+			Field("event_id").
+			Unique(),
 	}
 }
 
@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// VolunteerCampaignChoiceGroup is a "pick one of these" slot of a campaign,
+// e.g. one main out of three. The station picks the option at redemption time.
+type VolunteerCampaignChoiceGroup struct {
+	ent.Schema
+}
+
+func (VolunteerCampaignChoiceGroup) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "volunteer_campaign_choice_group"},
+	}
+}
+
+func (VolunteerCampaignChoiceGroup) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("campaign_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("name").
+			MaxLen(50).
+			NotEmpty(),
+		// Quantity of the chosen product per redemption.
+		field.Int("quantity").
+			Positive().
+			Default(1),
+		field.Int("position").
+			Default(0),
+	}
+}
+
+func (VolunteerCampaignChoiceGroup) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("campaign", VolunteerCampaign.Type).
+			Field("campaign_id").
+			Unique().
+			Required(),
+		edge.To("products", Product.Type).
+			Through("options", VolunteerCampaignChoiceOption.Type),
+	}
+}
+
+func (VolunteerCampaignChoiceGroup) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("campaign_id"),
+	}
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+type VolunteerCampaignChoiceOption struct {
+	ent.Schema
+}
+
+func (VolunteerCampaignChoiceOption) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "volunteer_campaign_choice_option"},
+		field.ID("group_id", "product_id"),
+	}
+}
+
+func (VolunteerCampaignChoiceOption) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("group_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("product_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.Int("position").
+			Default(0),
+	}
+}
+
+func (VolunteerCampaignChoiceOption) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("group", VolunteerCampaignChoiceGroup.Type).
+			Field("group_id").
+			Unique().
+			Required(),
+		edge.To("product", Product.Type).
+			Field("product_id").
+			Unique().
+			Required(),
+	}
+}
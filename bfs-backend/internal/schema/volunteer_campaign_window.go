@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// VolunteerCampaignWindow is a recurring daily meal window of a campaign in
+// Europe/Zurich time. A campaign with windows is only redeemable inside one.
+type VolunteerCampaignWindow struct {
+	ent.Schema
+}
+
+func (VolunteerCampaignWindow) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "volunteer_campaign_window"},
+	}
+}
+
+func (VolunteerCampaignWindow) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("campaign_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("label").
+			MaxLen(50).
+			Default(""),
+		// Bit set indexed by time.Weekday (bit 0 = Sunday); see package schedule.
+		field.Int("weekdays").
+			Default(127),
+		// Minutes since local midnight, end exclusive.
+		field.Int("start_minute").
+			NonNegative(),
+		field.Int("end_minute").
+			Positive(),
+		// Per occurrence of the window: for the shared QR across everyone, for
+		// a personal token per volunteer. Nil means unlimited.
+		field.Int("max_redemptions").
+			Positive().
+			Optional().
+			Nillable(),
+		field.Int("max_per_person").
+			Positive().
+			Optional().
+			Nillable(),
+	}
+}
+
+func (VolunteerCampaignWindow) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("campaign", VolunteerCampaign.Type).
+			Field("campaign_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (VolunteerCampaignWindow) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("campaign_id"),
+	}
+}
@@ -31,6 +31,10 @@ func (VolunteerRedemption) Fields() []ent.Field {
 			MaxLen(36).
 			NotEmpty().
 			Unique(),
+		field.String("volunteer_token_id").
+			MaxLen(36).
+			Optional().
+			Nillable(),
 		field.String("station_device_id").
 			MaxLen(36).
 			Optional().
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+
+	"backend/internal/id"
+)
+
+// VolunteerToken is a personal staff-meal QR code issued to one volunteer
+// from an imported roster. Unlike the campaign's shared QR it carries its own
+// redemption limit.
+type VolunteerToken struct {
+	ent.Schema
+}
+
+func (VolunteerToken) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "volunteer_token"},
+	}
+}
+
+func (VolunteerToken) Fields() []ent.Field {
+	return []ent.Field{
+		nanoidPK(),
+		field.String("campaign_id").
+			MaxLen(36).
+			NotEmpty(),
+		field.String("name").
+			MaxLen(100).
+			NotEmpty(),
+		field.String("email").
+			MaxLen(255).
+			Optional().
+			Nillable(),
+		field.String("token").
+			MaxLen(36).
+			NotEmpty().
+			DefaultFunc(id.New).
+			Unique(),
+		field.Int("max_redemptions").
+			Positive().
+			Default(1),
+		field.Int("redemption_count").
+			NonNegative().
+			Default(0),
+		// Set for numbered single-use slips printed in bulk; nil for tokens
+		// issued from the roster.
+		field.Int("serial").
+			Positive().
+			Optional().
+			Nillable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+func (VolunteerToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("campaign", VolunteerCampaign.Type).
+			Field("campaign_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (VolunteerToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("campaign_id", "name").Unique(),
+		index.Fields("campaign_id", "serial").Unique(),
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/admininvite"
 	"backend/internal/generated/ent/user"
+	"backend/internal/i18n"
 	nanoid "backend/internal/id"
 	"backend/internal/repository"
 )
@@ -37,7 +38,7 @@ type AdminInviteService interface {
 	// Admin operations (requires auth)
 	List(ctx context.Context, status *string, email *string) ([]*ent.AdminInvite, int64, error)
 	GetByID(ctx context.Context, id string) (*ent.AdminInvite, error)
-	Create(ctx context.Context, inviterID, email string, expiresInSec *int) (*ent.AdminInvite, error)
+	Create(ctx context.Context, inviterID, email string, expiresInSec *int, locale i18n.Locale) (*ent.AdminInvite, error)
 	Delete(ctx context.Context, id string) error
 	Revoke(ctx context.Context, id string) error
 	Resend(ctx context.Context, id string) error
@@ -100,7 +101,7 @@ func (s *adminInviteService) List(ctx context.Context, status *string, email *st
 	return invites, total, nil
 }
 
-func (s *adminInviteService) Create(ctx context.Context, inviterID, email string, expiresInSec *int) (*ent.AdminInvite, error) {
+func (s *adminInviteService) Create(ctx context.Context, inviterID, email string, expiresInSec *int, locale i18n.Locale) (*ent.AdminInvite, error) {
 	// Normalize email
 	email = strings.ToLower(strings.TrimSpace(email))
 
@@ -120,14 +121,15 @@ func (s *adminInviteService) Create(ctx context.Context, inviterID, email string
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	invite, err := s.inviteRepo.Create(ctx, inviterID, email, tokenHash, admininvite.StatusPending, expiresAt)
+	lang := string(locale)
+	invite, err := s.inviteRepo.Create(ctx, inviterID, email, tokenHash, admininvite.StatusPending, expiresAt, &lang)
 	if err != nil {
 		return nil, err
 	}
 
 	// Build invite URL and send email
 	inviteURL := s.buildInviteURL(token)
-	_ = s.emailSvc.SendInviteEmail(ctx, email, inviteURL, expiresAt)
+	_ = s.emailSvc.SendInviteEmail(ctx, email, inviteURL, expiresAt, locale)
 
 	return invite, nil
 }
@@ -200,7 +202,7 @@ func (s *adminInviteService) Resend(ctx context.Context, id string) error {
 
 	// Send email
 	inviteURL := s.buildInviteURL(token)
-	return s.emailSvc.SendInviteEmail(ctx, invite.InviteeEmail, inviteURL, expiresAt)
+	return s.emailSvc.SendInviteEmail(ctx, invite.InviteeEmail, inviteURL, expiresAt, i18n.Or(invite.Locale))
 }
 
 func (s *adminInviteService) Verify(ctx context.Context, token string) (*ent.AdminInvite, error) {
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"backend/internal/analytics"
+	"backend/internal/repository"
+	"backend/internal/tz"
+)
+
+var ErrAnalyticsInvalidDate = errors.New("invalid_date")
+
+// StationAnalytics is the throughput of one station on one event day. The
+// queue depth counts orders, since that is what the kitchen screen shows.
+// Redemptions without a known station, such as those recorded before
+// redemption batches, are reported together with an empty StationID.
+type StationAnalytics struct {
+	StationID   string
+	StationName string
+	analytics.Metrics
+}
+
+// ProductAnalytics is the throughput of one product across all stations on
+// one event day. The queue depth counts units.
+type ProductAnalytics struct {
+	ProductID   string
+	ProductName string
+	analytics.Metrics
+}
+
+type AnalyticsService interface {
+	// StationStats reports per-station figures for the Europe/Zurich day date
+	// (YYYY-MM-DD, today when empty), counting only the orders of eventID
+	// when set. Redemptions without a known station come last, in one entry
+	// with an empty StationID and no queue depth.
+	StationStats(ctx context.Context, date string, eventID *string) ([]StationAnalytics, error)
+	// ProductStats reports per-product figures for the Europe/Zurich day date
+	// (YYYY-MM-DD, today when empty), counting only the orders of eventID
+	// when set.
+	ProductStats(ctx context.Context, date string, eventID *string) ([]ProductAnalytics, error)
+}
+
+type analyticsService struct {
+	repo     repository.AnalyticsRepository
+	stations StationService
+}
+
+func NewAnalyticsService(repo repository.AnalyticsRepository, stations StationService) AnalyticsService {
+	return &analyticsService{repo: repo, stations: stations}
+}
+
+// analyticsDay is everything loaded for one event day: the redemptions made
+// that day, the lines of orders paid that day, and first payment times for
+// both.
+type analyticsDay struct {
+	loc         *time.Location
+	redemptions []repository.RedemptionRecord
+	lines       []redeemableLine
+	paidAt      map[string]time.Time
+}
+
+type redeemableLine struct {
+	orderID         string
+	productID       string
+	title           string
+	parentProductID *string
+	quantity        int
+}
+
+func (s *analyticsService) loadDay(ctx context.Context, date string, eventID *string) (*analyticsDay, error) {
+	loc := tz.Zurich()
+	var dayStart time.Time
+	if date == "" {
+		now := time.Now().In(loc)
+		dayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	} else {
+		d, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return nil, ErrAnalyticsInvalidDate
+		}
+		dayStart = d
+	}
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	redemptions, err := s.repo.ListRedemptions(ctx, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	paidAt, err := s.repo.FirstPaidBetween(ctx, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	if eventID != nil {
+		if redemptions, err = s.onlyEvent(ctx, *eventID, redemptions, paidAt); err != nil {
+			return nil, err
+		}
+	}
+
+	paidOrderIDs := make([]string, 0, len(paidAt))
+	for id := range paidAt {
+		paidOrderIDs = append(paidOrderIDs, id)
+	}
+	entLines, err := s.repo.ListRedeemableLines(ctx, paidOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders paid the evening before and redeemed today still need their
+	// payment time for the wait.
+	var missing []string
+	seen := make(map[string]bool)
+	for _, r := range redemptions {
+		if _, ok := paidAt[r.OrderID]; !ok && !seen[r.OrderID] {
+			seen[r.OrderID] = true
+			missing = append(missing, r.OrderID)
+		}
+	}
+	earlier, err := s.repo.FirstPaidAt(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for id, t := range earlier {
+		paidAt[id] = t
+	}
+
+	lines := make([]redeemableLine, 0, len(entLines))
+	for _, l := range entLines {
+		rl := redeemableLine{
+			orderID:   l.OrderID,
+			productID: l.ProductID,
+			title:     l.Title,
+			quantity:  l.Quantity,
+		}
+		if l.Edges.ParentLine != nil {
+			rl.parentProductID = &l.Edges.ParentLine.ProductID
+		}
+		lines = append(lines, rl)
+	}
+
+	return &analyticsDay{loc: loc, redemptions: redemptions, lines: lines, paidAt: paidAt}, nil
+}
+
+// onlyEvent drops the redemptions and payments of orders outside the event.
+func (s *analyticsService) onlyEvent(ctx context.Context, eventID string, redemptions []repository.RedemptionRecord, paidAt map[string]time.Time) ([]repository.RedemptionRecord, error) {
+	ids := make([]string, 0, len(paidAt)+len(redemptions))
+	for id := range paidAt {
+		ids = append(ids, id)
+	}
+	for _, r := range redemptions {
+		if _, ok := paidAt[r.OrderID]; !ok {
+			ids = append(ids, r.OrderID)
+		}
+	}
+	inEvent, err := s.repo.OrdersOfEvent(ctx, eventID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for id := range paidAt {
+		if !inEvent[id] {
+			delete(paidAt, id)
+		}
+	}
+	kept := redemptions[:0]
+	for _, r := range redemptions {
+		if inEvent[r.OrderID] {
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
+}
+
+// toRedemption drops redemptions without a known payment, which can only
+// happen for gratis orders created before payments were recorded.
+func (d *analyticsDay) toRedemption(r repository.RedemptionRecord) (analytics.Redemption, bool) {
+	paid, ok := d.paidAt[r.OrderID]
+	if !ok {
+		return analytics.Redemption{}, false
+	}
+	return analytics.Redemption{
+		OrderID:    r.OrderID,
+		Quantity:   r.Quantity,
+		PaidAt:     paid,
+		RedeemedAt: r.RedeemedAt,
+	}, true
+}
+
+func (s *analyticsService) StationStats(ctx context.Context, date string, eventID *string) ([]StationAnalytics, error) {
+	day, err := s.loadDay(ctx, date, eventID)
+	if err != nil {
+		return nil, err
+	}
+	stations, err := s.stations.ListStations(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(stations))
+	for _, st := range stations {
+		known[st.ID] = true
+	}
+	redemptions := make(map[string][]analytics.Redemption)
+	var unknown []analytics.Redemption
+	for _, r := range day.redemptions {
+		red, ok := day.toRedemption(r)
+		if !ok {
+			continue
+		}
+		if r.StationID == nil || !known[*r.StationID] {
+			unknown = append(unknown, red)
+			continue
+		}
+		redemptions[*r.StationID] = append(redemptions[*r.StationID], red)
+	}
+
+	// A line is due at every station its product, or its bundle's product,
+	// is currently assigned to — the same rule the station queue uses.
+	stationsByProduct := make(map[string][]string)
+	for _, st := range stations {
+		for _, dp := range st.Edges.DeviceProducts {
+			stationsByProduct[dp.ProductID] = append(stationsByProduct[dp.ProductID], st.ID)
+		}
+	}
+	demand := make(map[string][]analytics.Demand)
+	for _, l := range day.lines {
+		targets := make(map[string]bool)
+		for _, id := range stationsByProduct[l.productID] {
+			targets[id] = true
+		}
+		if l.parentProductID != nil {
+			for _, id := range stationsByProduct[*l.parentProductID] {
+				targets[id] = true
+			}
+		}
+		for id := range targets {
+			demand[id] = append(demand[id], analytics.Demand{
+				OrderID:  l.orderID,
+				Quantity: l.quantity,
+				PaidAt:   day.paidAt[l.orderID],
+			})
+		}
+	}
+
+	out := make([]StationAnalytics, 0, len(stations))
+	for _, st := range stations {
+		out = append(out, StationAnalytics{
+			StationID:   st.ID,
+			StationName: st.Name,
+			Metrics:     analytics.Compute(redemptions[st.ID], demand[st.ID], analytics.DepthOrders, day.loc),
+		})
+	}
+	if len(unknown) > 0 {
+		out = append(out, StationAnalytics{
+			Metrics: analytics.Compute(unknown, nil, analytics.DepthOrders, day.loc),
+		})
+	}
+	return out, nil
+}
+
+func (s *analyticsService) ProductStats(ctx context.Context, date string, eventID *string) ([]ProductAnalytics, error) {
+	day, err := s.loadDay(ctx, date, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	redemptions := make(map[string][]analytics.Redemption)
+	for _, r := range day.redemptions {
+		names[r.ProductID] = r.Title
+		if red, ok := day.toRedemption(r); ok {
+			redemptions[r.ProductID] = append(redemptions[r.ProductID], red)
+		}
+	}
+	demand := make(map[string][]analytics.Demand)
+	for _, l := range day.lines {
+		names[l.productID] = l.title
+		demand[l.productID] = append(demand[l.productID], analytics.Demand{
+			OrderID:  l.orderID,
+			Quantity: l.quantity,
+			PaidAt:   day.paidAt[l.orderID],
+		})
+	}
+
+	out := make([]ProductAnalytics, 0, len(names))
+	for id, name := range names {
+		out = append(out, ProductAnalytics{
+			ProductID:   id,
+			ProductName: name,
+			Metrics:     analytics.Compute(redemptions[id], demand[id], analytics.DepthItems, day.loc),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].RedeemedQuantity != out[j].RedeemedQuantity {
+			return out[i].RedeemedQuantity > out[j].RedeemedQuantity
+		}
+		return out[i].ProductName < out[j].ProductName
+	})
+	return out, nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/tz"
+)
+
+var (
+	// ErrProductUnavailable: the product is outside its availability windows,
+	// or those of its category, right now.
+	ErrProductUnavailable  = errors.New("product_unavailable")
+	ErrAvailabilityInvalid = errors.New("availability_invalid")
+)
+
+// availabilityHorizonDays is how far ahead the next availability is looked
+// up. Products not orderable within it report no next time.
+const availabilityHorizonDays = 14
+
+// CatalogAvailability is the availability of the catalog evaluated at one
+// moment. It holds until the next window boundary. A nil
+// *CatalogAvailability makes every product available.
+type CatalogAvailability struct {
+	at         time.Time
+	loc        *time.Location
+	products   map[string]schedule.Calendar
+	categories map[string]schedule.Calendar
+}
+
+func newCatalogAvailability(windows []*ent.AvailabilityWindow, at time.Time, loc *time.Location) *CatalogAvailability {
+	a := &CatalogAvailability{
+		at:         at,
+		loc:        loc,
+		products:   make(map[string]schedule.Calendar),
+		categories: make(map[string]schedule.Calendar),
+	}
+	for _, w := range windows {
+		set, key := a.categories, w.CategoryID
+		if w.ProductID != nil {
+			set, key = a.products, w.ProductID
+		}
+		if key == nil {
+			continue
+		}
+		cal := set[*key]
+		window := schedule.Window{Weekdays: schedule.Weekdays(w.Weekdays), Start: w.StartMinute, End: w.EndMinute}
+		if w.Date == nil {
+			cal.Weekly = append(cal.Weekly, window)
+		} else {
+			if cal.Exceptions == nil {
+				cal.Exceptions = make(map[string][]schedule.Window)
+			}
+			cal.Exceptions[*w.Date] = append(cal.Exceptions[*w.Date], window)
+		}
+		set[*key] = cal
+	}
+	return a
+}
+
+// Available reports whether p can be ordered now.
+func (a *CatalogAvailability) Available(p *ent.Product) bool {
+	ok, _ := a.Check(p)
+	return ok
+}
+
+// Check reports whether p can be ordered now and, if not, when it next can:
+// the first moment inside both its own windows and its category's. next is
+// nil when p is available or not within the next two weeks.
+func (a *CatalogAvailability) Check(p *ent.Product) (available bool, next *time.Time) {
+	if a == nil {
+		return true, nil
+	}
+	var calendars []schedule.Calendar
+	if cal, ok := a.products[p.ID]; ok {
+		calendars = append(calendars, cal)
+	}
+	if cal, ok := a.categories[p.CategoryID]; ok {
+		calendars = append(calendars, cal)
+	}
+	if len(calendars) == 0 {
+		return true, nil
+	}
+
+	horizon := a.at.AddDate(0, 0, availabilityHorizonDays)
+	t := a.at
+	for t.Before(horizon) {
+		// The earliest overlap of the next occurrences; when they do not
+		// overlap, retry after the one ending first.
+		start, end := t, time.Time{}
+		for _, cal := range calendars {
+			occ, ok := cal.Next(t, a.loc, availabilityHorizonDays)
+			if !ok {
+				return false, nil
+			}
+			if occ.Start.After(start) {
+				start = occ.Start
+			}
+			if end.IsZero() || occ.End.Before(end) {
+				end = occ.End
+			}
+		}
+		if start.Before(end) {
+			if !start.After(a.at) {
+				return true, nil
+			}
+			if start.After(horizon) {
+				return false, nil
+			}
+			return false, &start
+		}
+		t = end
+	}
+	return false, nil
+}
+
+// nextBoundary is the earliest window start or end after the moment the
+// availability was evaluated, when the result may change.
+func (a *CatalogAvailability) nextBoundary() (time.Time, bool) {
+	var next time.Time
+	visit := func(cal schedule.Calendar) {
+		occ, ok := cal.Next(a.at, a.loc, availabilityHorizonDays)
+		if !ok {
+			return
+		}
+		b := occ.Start
+		if !b.After(a.at) {
+			b = occ.End
+		}
+		if next.IsZero() || b.Before(next) {
+			next = b
+		}
+	}
+	for _, cal := range a.products {
+		visit(cal)
+	}
+	for _, cal := range a.categories {
+		visit(cal)
+	}
+	return next, !next.IsZero()
+}
+
+type availabilityState struct {
+	avail     *CatalogAvailability
+	gen       uint64
+	expiresAt time.Time
+}
+
+func (s *productService) Availability(ctx context.Context) (*CatalogAvailability, error) {
+	if s.availabilityRepo == nil {
+		return nil, nil
+	}
+	// The state is valid for the catalog generation it was computed in; the
+	// cache flips the generation at the next window boundary.
+	gen := s.cache.generation()
+	now := time.Now()
+	if st := s.avail.Load(); st != nil && st.gen == gen && now.Before(st.expiresAt) {
+		return st.avail, nil
+	}
+	windows, err := s.availabilityRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list availability windows: %w", err)
+	}
+	// Availability follows the same local clock as the opening hours.
+	avail := newCatalogAvailability(windows, now, tz.Zurich())
+	if next, ok := avail.nextBoundary(); ok {
+		s.cache.scheduleFlip(next)
+	}
+	s.avail.Store(&availabilityState{avail: avail, gen: gen, expiresAt: now.Add(productCatalogTTL)})
+	return avail, nil
+}
+
+func (s *productService) ProductAvailability(ctx context.Context, productID string) ([]*ent.AvailabilityWindow, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.availabilityRepo.ListForProduct(ctx, productID)
+}
+
+func (s *productService) SetProductAvailability(ctx context.Context, productID string, windows []repository.AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error) {
+	if err := checkAvailabilityWindows(windows); err != nil {
+		return nil, err
+	}
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	saved, err := s.availabilityRepo.ReplaceForProduct(ctx, productID, windows)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.invalidate()
+	return saved, nil
+}
+
+func (s *productService) CategoryAvailability(ctx context.Context, categoryID string) ([]*ent.AvailabilityWindow, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	return s.availabilityRepo.ListForCategory(ctx, categoryID)
+}
+
+func (s *productService) SetCategoryAvailability(ctx context.Context, categoryID string, windows []repository.AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error) {
+	if err := checkAvailabilityWindows(windows); err != nil {
+		return nil, err
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	saved, err := s.availabilityRepo.ReplaceForCategory(ctx, categoryID, windows)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.invalidate()
+	return saved, nil
+}
+
+func checkAvailabilityWindows(windows []repository.AvailabilityWindowInput) error {
+	for i, w := range windows {
+		if w.Date != nil {
+			if _, err := time.Parse(time.DateOnly, *w.Date); err != nil {
+				return fmt.Errorf("%w: window %d: date %q must be YYYY-MM-DD", ErrAvailabilityInvalid, i+1, *w.Date)
+			}
+		}
+		sw := schedule.Window{Weekdays: schedule.Weekdays(w.Weekdays), Start: w.StartMinute, End: w.EndMinute}
+		if w.Weekdays&^int(schedule.AllWeekdays) != 0 {
+			return fmt.Errorf("%w: window %d: invalid weekdays", ErrAvailabilityInvalid, i+1)
+		}
+		if err := sw.Validate(); err != nil {
+			return fmt.Errorf("%w: window %d: %w", ErrAvailabilityInvalid, i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,779 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/allergen"
+	"backend/internal/catalogfile"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/device"
+	"backend/internal/generated/ent/product"
+	"backend/internal/i18n"
+	"backend/internal/repository"
+)
+
+// CatalogCounts are the records of each kind an import creates or updates.
+type CatalogCounts struct {
+	Jetons     int
+	Categories int
+	Products   int
+	Variants   int
+	Slots      int
+}
+
+// CatalogImportResult is what an import changed or, on a dry run, would
+// change. When a record has problems nothing is changed.
+type CatalogImportResult struct {
+	DryRun   bool
+	Applied  bool
+	Created  CatalogCounts
+	Updated  CatalogCounts
+	Problems []catalogfile.Problem
+}
+
+type CatalogService interface {
+	// Export returns the jetons, the categories and all products with their
+	// variants, station routing and menu slots.
+	Export(ctx context.Context) (*catalogfile.Catalog, error)
+	// Import matches the records of c with the catalog by name and creates
+	// or updates them in one transaction. Records that are not in c are
+	// kept. Nothing is written on a dry run or when a record has problems.
+	Import(ctx context.Context, c *catalogfile.Catalog, dryRun bool) (*CatalogImportResult, error)
+}
+
+type catalogService struct {
+	client         *ent.Client
+	products       *repository.ProductRepository
+	variants       repository.ProductVariantRepository
+	categories     repository.CategoryRepository
+	menuSlots      repository.MenuSlotRepository
+	menuSlotOpts   repository.MenuSlotOptionRepository
+	jetons         repository.JetonRepository
+	devices        repository.DeviceRepository
+	deviceProducts repository.DeviceProductRepository
+	settings       SettingsService
+	productSvc     ProductService
+}
+
+func NewCatalogService(
+	client *ent.Client,
+	products *repository.ProductRepository,
+	variants repository.ProductVariantRepository,
+	categories repository.CategoryRepository,
+	menuSlots repository.MenuSlotRepository,
+	menuSlotOpts repository.MenuSlotOptionRepository,
+	jetons repository.JetonRepository,
+	devices repository.DeviceRepository,
+	deviceProducts repository.DeviceProductRepository,
+	settings SettingsService,
+	productSvc ProductService,
+) CatalogService {
+	return &catalogService{
+		client:         client,
+		products:       products,
+		variants:       variants,
+		categories:     categories,
+		menuSlots:      menuSlots,
+		menuSlotOpts:   menuSlotOpts,
+		jetons:         jetons,
+		devices:        devices,
+		deviceProducts: deviceProducts,
+		settings:       settings,
+		productSvc:     productSvc,
+	}
+}
+
+// catalogState is the catalog as stored, which exports are written from and
+// imports are matched against.
+type catalogState struct {
+	jetons     []*ent.Jeton
+	categories []*ent.Category
+	products   []*ent.Product
+	stations   []*ent.Device
+}
+
+func (s *catalogService) load(ctx context.Context) (*catalogState, error) {
+	var st catalogState
+	var err error
+	if st.jetons, err = s.jetons.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if st.categories, err = s.categories.GetAll(ctx); err != nil {
+		return nil, err
+	}
+	if st.products, err = s.products.GetCatalog(ctx); err != nil {
+		return nil, err
+	}
+	if st.stations, err = s.devices.GetByType(ctx, device.TypeSTATION); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (s *catalogService) Export(ctx context.Context) (*catalogfile.Catalog, error) {
+	st, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jetonNames := make(map[string]string, len(st.jetons))
+	categoryNames := make(map[string]string, len(st.categories))
+	productNames := make(map[string]string, len(st.products))
+	stationNames := make(map[string]string, len(st.stations))
+	for _, d := range st.stations {
+		stationNames[d.ID] = d.Name
+	}
+	for _, p := range st.products {
+		productNames[p.ID] = p.Name
+	}
+
+	c := &catalogfile.Catalog{
+		Version:    catalogfile.Version,
+		Jetons:     make([]catalogfile.Jeton, 0, len(st.jetons)),
+		Categories: make([]catalogfile.Category, 0, len(st.categories)),
+		Products:   make([]catalogfile.Product, 0, len(st.products)),
+	}
+	for _, j := range st.jetons {
+		jetonNames[j.ID] = j.Name
+		c.Jetons = append(c.Jetons, catalogfile.Jeton{Name: j.Name, Color: j.Color})
+	}
+	for _, cat := range st.categories {
+		categoryNames[cat.ID] = cat.Name
+		c.Categories = append(c.Categories, catalogfile.Category{
+			Name:         cat.Name,
+			Position:     cat.Position,
+			IsActive:     &cat.IsActive,
+			Translations: exportTranslations(cat.Translations),
+		})
+	}
+	for _, p := range st.products {
+		out := catalogfile.Product{
+			Name:         p.Name,
+			Category:     categoryNames[p.CategoryID],
+			Type:         string(p.Type),
+			PriceCents:   p.PriceCents,
+			IsActive:     &p.IsActive,
+			Description:  p.Description,
+			Image:        p.Image,
+			Jeton:        exportName(jetonNames, p.JetonID),
+			Allergens:    append([]string{}, p.Allergens...),
+			DietaryTags:  append([]string{}, p.DietaryTags...),
+			Translations: exportTranslations(p.Translations),
+		}
+		stationIDs := make([]string, 0, len(p.Edges.DeviceProducts))
+		for _, dp := range p.Edges.DeviceProducts {
+			stationIDs = append(stationIDs, dp.DeviceID)
+		}
+		out.Stations = exportNames(stationNames, stationIDs)
+		for _, v := range p.Edges.Variants {
+			stationIDs := make([]string, 0, len(v.Edges.DeviceVariants))
+			for _, dv := range v.Edges.DeviceVariants {
+				stationIDs = append(stationIDs, dv.DeviceID)
+			}
+			out.Variants = append(out.Variants, catalogfile.Variant{
+				Name:       v.Name,
+				PriceCents: v.PriceCents,
+				Jeton:      exportName(jetonNames, v.JetonID),
+				Position:   v.Position,
+				IsActive:   &v.IsActive,
+				Stations:   exportNames(stationNames, stationIDs),
+			})
+		}
+		for _, slot := range p.Edges.MenuSlots {
+			optionIDs := make([]string, 0, len(slot.Edges.Options))
+			for _, o := range slot.Edges.Options {
+				optionIDs = append(optionIDs, o.OptionProductID)
+			}
+			out.Slots = append(out.Slots, catalogfile.Slot{
+				Name:         slot.Name,
+				Sequence:     slot.Sequence,
+				Translations: exportTranslations(slot.Translations),
+				Options:      exportNames(productNames, optionIDs),
+			})
+		}
+		c.Products = append(c.Products, out)
+	}
+	return c, nil
+}
+
+// exportTranslations writes an entry without translations as {} rather than
+// null, which would leave the translations unchanged on import.
+func exportTranslations(t i18n.Translations) i18n.Translations {
+	if t == nil {
+		return i18n.Translations{}
+	}
+	return t
+}
+
+func exportName(names map[string]string, id *string) *string {
+	if id == nil {
+		return nil
+	}
+	if name, ok := names[*id]; ok {
+		return &name
+	}
+	return nil
+}
+
+// exportNames returns the sorted names of ids; ids without a name (e.g. a
+// POS device) are left out.
+func exportNames(names map[string]string, ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			out = append(out, name)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func (s *catalogService) Import(ctx context.Context, c *catalogfile.Catalog, dryRun bool) (*CatalogImportResult, error) {
+	st, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imp := newCatalogImport(c, st)
+	imp.check()
+	imp.result.DryRun = dryRun
+	if dryRun || len(imp.result.Problems) > 0 {
+		return imp.result, nil
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	if err := s.apply(txCtx, imp); err != nil {
+		return nil, err
+	}
+	// In the jeton modes every active product needs a jeton, and the import
+	// must not take that away any more than the settings can.
+	cfg, err := s.settings.GetSettings(txCtx)
+	if err != nil {
+		return nil, err
+	}
+	var missing MissingJetonForActiveProductsError
+	if err := s.settings.CheckJetonsAssigned(txCtx, cfg.PosMode); errors.As(err, &missing) {
+		imp.fail(0, "catalog", "%d active products would have no jeton, which the POS mode %s requires", missing.Count, cfg.PosMode)
+		return imp.result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	s.productSvc.InvalidateCache()
+	imp.result.Applied = true
+	return imp.result, nil
+}
+
+// catalogImport checks a file against the stored catalog and records which
+// stored entry each of its records updates; nil entries are created.
+type catalogImport struct {
+	file   *catalogfile.Catalog
+	result *CatalogImportResult
+
+	jetons     map[string][]*ent.Jeton
+	categories map[string][]*ent.Category
+	products   map[string][]*ent.Product
+	stations   map[string][]*ent.Device
+
+	// Names in the file by key; for products the type.
+	fileJetons     map[string]bool
+	fileCategories map[string]bool
+	fileProducts   map[string]product.Type
+
+	matchedJetons     []*ent.Jeton
+	matchedCategories []*ent.Category
+	matchedProducts   []*ent.Product
+}
+
+func newCatalogImport(c *catalogfile.Catalog, st *catalogState) *catalogImport {
+	imp := &catalogImport{
+		file:              c,
+		result:            &CatalogImportResult{Problems: []catalogfile.Problem{}},
+		jetons:            byKey(st.jetons, func(j *ent.Jeton) string { return j.Name }),
+		categories:        byKey(st.categories, func(c *ent.Category) string { return c.Name }),
+		products:          byKey(st.products, func(p *ent.Product) string { return p.Name }),
+		stations:          byKey(st.stations, func(d *ent.Device) string { return d.Name }),
+		fileJetons:        make(map[string]bool, len(c.Jetons)),
+		fileCategories:    make(map[string]bool, len(c.Categories)),
+		fileProducts:      make(map[string]product.Type, len(c.Products)),
+		matchedJetons:     make([]*ent.Jeton, len(c.Jetons)),
+		matchedCategories: make([]*ent.Category, len(c.Categories)),
+		matchedProducts:   make([]*ent.Product, len(c.Products)),
+	}
+	for _, j := range c.Jetons {
+		imp.fileJetons[catalogfile.Key(j.Name)] = true
+	}
+	for _, cat := range c.Categories {
+		imp.fileCategories[catalogfile.Key(cat.Name)] = true
+	}
+	for _, p := range c.Products {
+		if _, dup := imp.fileProducts[catalogfile.Key(p.Name)]; !dup {
+			imp.fileProducts[catalogfile.Key(p.Name)] = importProductType(p.Type)
+		}
+	}
+	return imp
+}
+
+func byKey[T any](items []T, name func(T) string) map[string][]T {
+	out := make(map[string][]T, len(items))
+	for _, it := range items {
+		k := catalogfile.Key(name(it))
+		out[k] = append(out[k], it)
+	}
+	return out
+}
+
+// importProductType defaults to simple products.
+func importProductType(t string) product.Type {
+	if t == "" {
+		return product.TypeSimple
+	}
+	return product.Type(strings.ToLower(t))
+}
+
+func (imp *catalogImport) fail(line int, record, format string, args ...any) {
+	imp.result.Problems = append(imp.result.Problems, catalogfile.Problem{
+		Line:    line,
+		Record:  record,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkName reports names that are empty or longer than the 20 characters
+// the catalog allows.
+func (imp *catalogImport) checkName(line int, record, name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 20 {
+		imp.fail(line, record, "name must be 1-20 characters")
+		return false
+	}
+	return true
+}
+
+// match returns the stored entry a record updates, nil when it is new. A
+// name shared by several stored entries cannot be matched.
+func match[T any](imp *catalogImport, stored map[string][]T, line int, record, kind, name string) (T, bool) {
+	var zero T
+	switch found := stored[catalogfile.Key(name)]; len(found) {
+	case 0:
+		return zero, true
+	case 1:
+		return found[0], true
+	default:
+		imp.fail(line, record, "%d stored %s are named %q; rename them so the name is unique", len(found), kind, name)
+		return zero, false
+	}
+}
+
+// checkRef reports a reference to a name that is neither in the file nor
+// stored exactly once.
+func checkRef[T any](imp *catalogImport, inFile bool, stored map[string][]T, line int, record, kind, name string) {
+	if inFile {
+		return
+	}
+	switch n := len(stored[catalogfile.Key(name)]); {
+	case n == 0:
+		imp.fail(line, record, "unknown %s %q", kind, name)
+	case n > 1:
+		imp.fail(line, record, "%d %ss are named %q; rename them so the name is unique", n, kind, name)
+	}
+}
+
+func (imp *catalogImport) checkStations(line int, record string, names []string) {
+	for _, name := range names {
+		checkRef(imp, false, imp.stations, line, record, "station", name)
+	}
+}
+
+// checkDuplicate reports a name that appeared before in the same list.
+func checkDuplicate(imp *catalogImport, seen map[string]bool, line int, record, name string) bool {
+	k := catalogfile.Key(name)
+	if seen[k] {
+		imp.fail(line, record, "is listed more than once")
+		return false
+	}
+	seen[k] = true
+	return true
+}
+
+func (imp *catalogImport) check() {
+	seen := make(map[string]bool)
+	for i, j := range imp.file.Jetons {
+		ref := j.Ref()
+		if !imp.checkName(j.Line, ref, j.Name) || !checkDuplicate(imp, seen, j.Line, ref, j.Name) {
+			continue
+		}
+		if _, err := normalizeSettingsColor(j.Color); err != nil {
+			imp.fail(j.Line, ref, "color must be a hex color like #FF8800")
+		}
+		if m, ok := match(imp, imp.jetons, j.Line, ref, "jetons", j.Name); ok {
+			imp.matchedJetons[i] = m
+			imp.count(m != nil, func(c *CatalogCounts) { c.Jetons++ })
+		}
+	}
+
+	seen = make(map[string]bool)
+	for i, cat := range imp.file.Categories {
+		ref := cat.Ref()
+		if !imp.checkName(cat.Line, ref, cat.Name) || !checkDuplicate(imp, seen, cat.Line, ref, cat.Name) {
+			continue
+		}
+		if cat.Position < 0 {
+			imp.fail(cat.Line, ref, "position must not be negative")
+		}
+		if _, err := cat.Translations.Normalize(20); err != nil {
+			imp.fail(cat.Line, ref, "%v", err)
+		}
+		if m, ok := match(imp, imp.categories, cat.Line, ref, "categories", cat.Name); ok {
+			imp.matchedCategories[i] = m
+			imp.count(m != nil, func(c *CatalogCounts) { c.Categories++ })
+		}
+	}
+
+	seen = make(map[string]bool)
+	for i, p := range imp.file.Products {
+		ref := p.Ref()
+		if !imp.checkName(p.Line, ref, p.Name) || !checkDuplicate(imp, seen, p.Line, ref, p.Name) {
+			continue
+		}
+		m, ok := match(imp, imp.products, p.Line, ref, "products", p.Name)
+		if ok {
+			imp.matchedProducts[i] = m
+			imp.count(m != nil, func(c *CatalogCounts) { c.Products++ })
+		}
+		imp.checkProduct(p, m)
+	}
+}
+
+func (imp *catalogImport) count(update bool, add func(*CatalogCounts)) {
+	if update {
+		add(&imp.result.Updated)
+	} else {
+		add(&imp.result.Created)
+	}
+}
+
+func (imp *catalogImport) checkProduct(p catalogfile.Product, stored *ent.Product) {
+	ref := p.Ref()
+	typ := importProductType(p.Type)
+	if err := product.TypeValidator(typ); err != nil {
+		imp.fail(p.Line, ref, "type must be simple or menu")
+	} else if stored != nil && stored.Type != typ {
+		imp.fail(p.Line, ref, "is stored as %s; the type of a product cannot be changed", stored.Type)
+	}
+	if p.PriceCents < 0 {
+		imp.fail(p.Line, ref, "price must not be negative")
+	}
+	if p.Description != nil && utf8.RuneCountInString(*p.Description) > 500 {
+		imp.fail(p.Line, ref, "description must be at most 500 characters")
+	}
+	if strings.TrimSpace(p.Category) == "" {
+		imp.fail(p.Line, ref, "category is required")
+	} else {
+		checkRef(imp, imp.fileCategories[catalogfile.Key(p.Category)], imp.categories, p.Line, ref, "category", p.Category)
+	}
+	if p.Jeton != nil && strings.TrimSpace(*p.Jeton) != "" {
+		checkRef(imp, imp.fileJetons[catalogfile.Key(*p.Jeton)], imp.jetons, p.Line, ref, "jeton", *p.Jeton)
+	}
+	if _, err := allergen.NormalizeAllergens(p.Allergens); err != nil {
+		imp.fail(p.Line, ref, "%v", err)
+	}
+	if _, err := allergen.NormalizeDietary(p.DietaryTags); err != nil {
+		imp.fail(p.Line, ref, "%v", err)
+	}
+	if _, err := p.Translations.Normalize(20); err != nil {
+		imp.fail(p.Line, ref, "%v", err)
+	}
+	imp.checkStations(p.Line, ref, p.Stations)
+
+	if len(p.Variants) > 0 && typ == product.TypeMenu {
+		imp.fail(p.Line, ref, "menus cannot have variants")
+	}
+	if len(p.Slots) > 0 && typ != product.TypeMenu {
+		imp.fail(p.Line, ref, "only menus have slots")
+	}
+
+	var storedVariants map[string][]*ent.ProductVariant
+	var storedSlots map[string][]*ent.MenuSlot
+	if stored != nil {
+		storedVariants = byKey(stored.Edges.Variants, func(v *ent.ProductVariant) string { return v.Name })
+		storedSlots = byKey(stored.Edges.MenuSlots, func(s *ent.MenuSlot) string { return s.Name })
+	}
+	seen := make(map[string]bool)
+	for _, v := range p.Variants {
+		vref := v.Ref(p.Name)
+		if !imp.checkName(v.Line, vref, v.Name) || !checkDuplicate(imp, seen, v.Line, vref, v.Name) {
+			continue
+		}
+		if v.PriceCents < 0 {
+			imp.fail(v.Line, vref, "price must not be negative")
+		}
+		if v.Jeton != nil && strings.TrimSpace(*v.Jeton) != "" {
+			checkRef(imp, imp.fileJetons[catalogfile.Key(*v.Jeton)], imp.jetons, v.Line, vref, "jeton", *v.Jeton)
+		}
+		imp.checkStations(v.Line, vref, v.Stations)
+		imp.count(len(storedVariants[catalogfile.Key(v.Name)]) > 0, func(c *CatalogCounts) { c.Variants++ })
+	}
+	seen = make(map[string]bool)
+	for _, slot := range p.Slots {
+		sref := slot.Ref(p.Name)
+		if !imp.checkName(slot.Line, sref, slot.Name) || !checkDuplicate(imp, seen, slot.Line, sref, slot.Name) {
+			continue
+		}
+		if _, err := slot.Translations.Normalize(20); err != nil {
+			imp.fail(slot.Line, sref, "%v", err)
+		}
+		for _, name := range slot.Options {
+			_, inFile := imp.fileProducts[catalogfile.Key(name)]
+			checkRef(imp, inFile, imp.products, slot.Line, sref, "product", name)
+		}
+		if _, ok := match(imp, storedSlots, slot.Line, sref, "slots", slot.Name); ok {
+			imp.count(len(storedSlots[catalogfile.Key(slot.Name)]) > 0, func(c *CatalogCounts) { c.Slots++ })
+		}
+	}
+}
+
+// catalogIDs resolves names to ids while an import is applied: the stored
+// entries, then those the import creates.
+type catalogIDs struct {
+	jetons     map[string]string
+	categories map[string]string
+	products   map[string]string
+	stations   map[string]string
+}
+
+func uniqueIDs[T any](stored map[string][]T, id func(T) string) map[string]string {
+	out := make(map[string]string, len(stored))
+	for k, items := range stored {
+		if len(items) == 1 {
+			out[k] = id(items[0])
+		}
+	}
+	return out
+}
+
+func (ids *catalogIDs) stationIDs(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if id := ids.stations[catalogfile.Key(name)]; id != "" && !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// jetonID is the jeton an import assigns: the stored one when the record
+// leaves the jeton out, none when it is empty.
+func (ids *catalogIDs) jetonID(name *string, stored *string) *string {
+	if name == nil {
+		return stored
+	}
+	if strings.TrimSpace(*name) == "" {
+		return nil
+	}
+	id := ids.jetons[catalogfile.Key(*name)]
+	return &id
+}
+
+// importText is the value an import writes to an optional text: the stored
+// one when the record leaves it out, none when it is blank.
+func importText(v, stored *string) *string {
+	if v == nil {
+		return stored
+	}
+	if strings.TrimSpace(*v) == "" {
+		return nil
+	}
+	return v
+}
+
+// apply writes a checked import. It runs in the import's transaction.
+func (s *catalogService) apply(ctx context.Context, imp *catalogImport) error {
+	ids := &catalogIDs{
+		jetons:     uniqueIDs(imp.jetons, func(j *ent.Jeton) string { return j.ID }),
+		categories: uniqueIDs(imp.categories, func(c *ent.Category) string { return c.ID }),
+		products:   uniqueIDs(imp.products, func(p *ent.Product) string { return p.ID }),
+		stations:   uniqueIDs(imp.stations, func(d *ent.Device) string { return d.ID }),
+	}
+
+	// Jetons are settings; changing them through the settings service keeps
+	// the settings history complete.
+	for i, j := range imp.file.Jetons {
+		var saved *ent.Jeton
+		var err error
+		if m := imp.matchedJetons[i]; m != nil {
+			saved, err = s.settings.UpdateJeton(ctx, m.ID, j.Name, j.Color)
+		} else {
+			saved, err = s.settings.CreateJeton(ctx, j.Name, j.Color)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", j.Ref(), err)
+		}
+		ids.jetons[catalogfile.Key(j.Name)] = saved.ID
+	}
+
+	for i, cat := range imp.file.Categories {
+		name := strings.TrimSpace(cat.Name)
+		var saved *ent.Category
+		var err error
+		if m := imp.matchedCategories[i]; m != nil {
+			saved, err = s.categories.Update(ctx, m.ID, name, cat.Position, catalogfile.Active(cat.IsActive))
+		} else {
+			saved, err = s.categories.Create(ctx, name, cat.Position, catalogfile.Active(cat.IsActive))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", cat.Ref(), err)
+		}
+		if cat.Translations != nil {
+			translations, _ := cat.Translations.Normalize(20)
+			if _, err := s.categories.UpdateTranslations(ctx, saved.ID, translations); err != nil {
+				return fmt.Errorf("%s: %w", cat.Ref(), err)
+			}
+		}
+		ids.categories[catalogfile.Key(cat.Name)] = saved.ID
+	}
+
+	// Products first, so slots can offer products the import creates.
+	for i, p := range imp.file.Products {
+		saved, err := s.saveProduct(ctx, ids, p, imp.matchedProducts[i])
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Ref(), err)
+		}
+		ids.products[catalogfile.Key(p.Name)] = saved.ID
+	}
+	for i, p := range imp.file.Products {
+		id := ids.products[catalogfile.Key(p.Name)]
+		if err := s.saveProductDetails(ctx, ids, id, p, imp.matchedProducts[i]); err != nil {
+			return fmt.Errorf("%s: %w", p.Ref(), err)
+		}
+	}
+	return nil
+}
+
+func (s *catalogService) saveProduct(ctx context.Context, ids *catalogIDs, p catalogfile.Product, stored *ent.Product) (*ent.Product, error) {
+	name := strings.TrimSpace(p.Name)
+	categoryID := ids.categories[catalogfile.Key(p.Category)]
+	typ := importProductType(p.Type)
+	var saved *ent.Product
+	var err error
+	if stored != nil {
+		saved, err = s.products.Update(ctx, stored.ID, categoryID, typ, name, p.PriceCents, catalogfile.Active(p.IsActive),
+			importText(p.Image, stored.Image), importText(p.Description, stored.Description), ids.jetonID(p.Jeton, stored.JetonID))
+	} else {
+		saved, err = s.products.Create(ctx, categoryID, typ, name, p.PriceCents, catalogfile.Active(p.IsActive),
+			importText(p.Image, nil), importText(p.Description, nil), ids.jetonID(p.Jeton, nil))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allergens, dietaryTags := p.Allergens, p.DietaryTags
+	if allergens != nil {
+		allergens, _ = allergen.NormalizeAllergens(allergens)
+	}
+	if dietaryTags != nil {
+		dietaryTags, _ = allergen.NormalizeDietary(dietaryTags)
+	}
+	if allergens != nil || dietaryTags != nil {
+		if saved, err = s.products.UpdateDietary(ctx, saved.ID, allergens, dietaryTags); err != nil {
+			return nil, err
+		}
+	}
+	if p.Translations != nil {
+		translations, _ := p.Translations.Normalize(20)
+		if saved, err = s.products.UpdateTranslations(ctx, saved.ID, translations); err != nil {
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+// saveProductDetails writes the station routing, variants and slots of a
+// product.
+func (s *catalogService) saveProductDetails(ctx context.Context, ids *catalogIDs, productID string, p catalogfile.Product, stored *ent.Product) error {
+	if p.Stations != nil {
+		if err := s.deviceProducts.ReplaceForProduct(ctx, productID, ids.stationIDs(p.Stations)); err != nil {
+			return err
+		}
+	}
+
+	var storedVariants map[string][]*ent.ProductVariant
+	var storedSlots map[string][]*ent.MenuSlot
+	if stored != nil {
+		storedVariants = byKey(stored.Edges.Variants, func(v *ent.ProductVariant) string { return v.Name })
+		storedSlots = byKey(stored.Edges.MenuSlots, func(s *ent.MenuSlot) string { return s.Name })
+	}
+
+	for _, v := range p.Variants {
+		in := repository.ProductVariantInput{
+			Name:       strings.TrimSpace(v.Name),
+			PriceCents: v.PriceCents,
+			JetonID:    ids.jetonID(v.Jeton, nil),
+			Position:   v.Position,
+			IsActive:   catalogfile.Active(v.IsActive),
+			StationIDs: ids.stationIDs(v.Stations),
+		}
+		var err error
+		if found := storedVariants[catalogfile.Key(v.Name)]; len(found) > 0 {
+			in.JetonID = ids.jetonID(v.Jeton, found[0].JetonID)
+			if v.Stations == nil {
+				in.StationIDs = in.StationIDs[:0]
+				for _, dv := range found[0].Edges.DeviceVariants {
+					in.StationIDs = append(in.StationIDs, dv.DeviceID)
+				}
+			}
+			_, err = s.variants.Update(ctx, found[0].ID, in)
+		} else {
+			_, err = s.variants.Create(ctx, productID, in)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", v.Ref(p.Name), err)
+		}
+	}
+
+	for _, slot := range p.Slots {
+		name := strings.TrimSpace(slot.Name)
+		var saved *ent.MenuSlot
+		var err error
+		if found := storedSlots[catalogfile.Key(slot.Name)]; len(found) > 0 {
+			saved, err = s.menuSlots.Update(ctx, found[0].ID, productID, name, slot.Sequence)
+		} else {
+			saved, err = s.menuSlots.Create(ctx, productID, name, slot.Sequence)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", slot.Ref(p.Name), err)
+		}
+		if slot.Translations != nil {
+			translations, _ := slot.Translations.Normalize(20)
+			if _, err := s.menuSlots.UpdateTranslations(ctx, saved.ID, translations); err != nil {
+				return fmt.Errorf("%s: %w", slot.Ref(p.Name), err)
+			}
+		}
+		if slot.Options != nil {
+			if err := s.menuSlotOpts.DeleteByMenuSlotID(ctx, saved.ID); err != nil {
+				return fmt.Errorf("%s: %w", slot.Ref(p.Name), err)
+			}
+			var optionIDs []string
+			for _, name := range slot.Options {
+				if id := ids.products[catalogfile.Key(name)]; !slices.Contains(optionIDs, id) {
+					optionIDs = append(optionIDs, id)
+				}
+			}
+			if _, err := s.menuSlotOpts.CreateBatch(ctx, saved.ID, optionIDs); err != nil {
+				return fmt.Errorf("%s: %w", slot.Ref(p.Name), err)
+			}
+		}
+	}
+	return nil
+}
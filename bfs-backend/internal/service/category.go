@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"backend/internal/generated/ent"
+	"backend/internal/i18n"
 	"backend/internal/repository"
 )
 
@@ -14,6 +15,7 @@ type CategoryService interface {
 	List(ctx context.Context, limit, offset int) ([]*ent.Category, int64, error)
 	Create(ctx context.Context, name string, position int) (*ent.Category, error)
 	Update(ctx context.Context, id string, name string, position int, isActive bool) (*ent.Category, error)
+	UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Category, error)
 	Delete(ctx context.Context, id string) error
 }
 
@@ -49,6 +51,15 @@ func (s *categoryService) Update(ctx context.Context, id string, name string, po
 	return s.repo.Update(ctx, id, name, position, isActive)
 }
 
+// UpdateTranslations replaces the translated names of a category.
+func (s *categoryService) UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Category, error) {
+	translations, err := translations.Normalize(20)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateTranslations(ctx, id, translations)
+}
+
 func (s *categoryService) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
@@ -1,21 +1,79 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
+	"backend/internal/config"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/club100syncrun"
+	"backend/internal/membership"
+	"backend/internal/namesearch"
 	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrProductNotFreeForClub100 = fmt.Errorf("product_not_free_for_club100")
+	ErrClub100SyncRunning       = errors.New("club100_sync_running")
+	// ErrClub100UploadUnsupported is returned for member list uploads when
+	// the directory is not the CSV provider.
+	ErrClub100UploadUnsupported = errors.New("club100_upload_unsupported")
+	// ErrClub100InsufficientRedemptions means the member has fewer free
+	// products left in the current window than the redemption asks for.
+	ErrClub100InsufficientRedemptions = errors.New("insufficient_remaining_redemptions")
 )
 
-var ErrProductNotFreeForClub100 = fmt.Errorf("product_not_free_for_club100")
+// club100SyncTimeout bounds one sync, including all provider pages.
+const club100SyncTimeout = 2 * time.Minute
+
+// club100MemberIDMaxLen is the width of club100_member.external_person_id.
+const club100MemberIDMaxLen = 50
 
 type Club100Service interface {
-	GetPeopleWithRedemptions(ctx context.Context) ([]Club100Person, error)
-	GetRemainingRedemptions(ctx context.Context, elvantoPersonID string) (remaining int, max int, err error)
-	RecordRedemption(ctx context.Context, elvantoPersonID, elvantoPersonName string, orderID string, qty int) error
+	// GetPeopleWithRedemptions lists the members of the local directory with
+	// their remaining redemptions in the current window. A non-empty query
+	// filters and ranks them by fuzzy name match.
+	GetPeopleWithRedemptions(ctx context.Context, query string) ([]Club100Person, error)
+	// GetRemainingRedemptions and RecordRedemption take the person's ID at
+	// the configured membership provider.
+	GetRemainingRedemptions(ctx context.Context, externalPersonID string) (remaining int, max int, err error)
+	// RecordRedemption books qty free products for a member, tagged with the
+	// order's event. The member is locked while the remaining count is
+	// checked, so concurrent redemptions cannot exceed the maximum. It joins a
+	// transaction carried by ctx; the lock then lasts until that transaction
+	// ends.
+	RecordRedemption(ctx context.Context, externalPersonID, personName string, orderID string, eventID *string, qty int) error
 	GetFreeProductIDs(ctx context.Context) ([]string, error)
 	GetMaxRedemptions(ctx context.Context) (int, error)
 	ValidateOrderForRedemption(ctx context.Context, orderID string) error
+
+	// SyncMembers copies the members passing MEMBERSHIP_GROUPS and
+	// MEMBERSHIP_TAGS from the membership provider into the local directory.
+	// Only one sync runs at a time; a second call returns
+	// ErrClub100SyncRunning. An unconfigured provider returns
+	// membership.ErrNotConfigured.
+	SyncMembers(ctx context.Context, trigger club100syncrun.Trigger) (*ent.Club100SyncRun, error)
+	GetSyncStatus(ctx context.Context) (*Club100SyncStatus, error)
+	// UploadMembers stores a member list for the CSV provider and syncs it
+	// right away. Invalid files wrap membership.ErrInvalidCSV.
+	UploadMembers(ctx context.Context, filename string, data []byte) (*ent.Club100SyncRun, error)
+
+	// GetCurrentWindow returns the range remaining redemptions are counted
+	// in right now; see Club100Window.
+	GetCurrentWindow(ctx context.Context) (*Club100Window, error)
+	ListPeriods(ctx context.Context) ([]*ent.Club100Period, error)
+	CreatePeriod(ctx context.Context, in Club100PeriodInput) (*ent.Club100Period, error)
+	UpdatePeriod(ctx context.Context, id string, patch Club100PeriodPatch) (*ent.Club100Period, error)
+	DeletePeriod(ctx context.Context, id string) error
+	GetPeriodReport(ctx context.Context, id string) (*Club100PeriodReport, error)
 }
 
 type Club100Person struct {
@@ -27,63 +85,110 @@ type Club100Person struct {
 	Max              int    `json:"max"`
 }
 
+// Club100SyncStatus describes the member directory: LastRun is the latest
+// sync whatever its outcome, LastSuccess the latest one that completed.
+type Club100SyncStatus struct {
+	Provider    string
+	Filter      membership.Filter
+	Configured  bool
+	Running     bool
+	MemberCount int
+	Interval    time.Duration
+	LastRun     *ent.Club100SyncRun
+	LastSuccess *ent.Club100SyncRun
+	// LastUpload is the member list in use by the CSV provider.
+	LastUpload *ent.MembershipUpload
+}
+
 type club100Service struct {
-	elvanto     ElvantoService
-	redemptions repository.Club100RedemptionRepository
-	settings    repository.SettingsRepository
-	orderLines  repository.OrderLineRepository
+	directory    membership.Provider
+	filter       membership.Filter
+	members      repository.Club100MemberRepository
+	periods      repository.Club100PeriodRepository
+	redemptions  repository.Club100RedemptionRepository
+	settings     repository.SettingsRepository
+	orderLines   repository.OrderLineRepository
+	uploads      repository.MembershipUploadRepository
+	client       *ent.Client
+	syncInterval time.Duration
+	logger       *zap.Logger
+	syncMu       sync.Mutex
 }
 
 func NewClub100Service(
-	elvanto ElvantoService,
+	directory membership.Provider,
+	members repository.Club100MemberRepository,
+	periods repository.Club100PeriodRepository,
 	redemptions repository.Club100RedemptionRepository,
 	settings repository.SettingsRepository,
 	orderLines repository.OrderLineRepository,
+	uploads repository.MembershipUploadRepository,
+	client *ent.Client,
+	cfg config.Config,
+	logger *zap.Logger,
 ) Club100Service {
 	return &club100Service{
-		elvanto:     elvanto,
-		redemptions: redemptions,
-		settings:    settings,
-		orderLines:  orderLines,
+		directory:    directory,
+		filter:       membership.Filter{Groups: cfg.Membership.Groups, Tags: cfg.Membership.Tags},
+		members:      members,
+		periods:      periods,
+		redemptions:  redemptions,
+		settings:     settings,
+		orderLines:   orderLines,
+		uploads:      uploads,
+		client:       client,
+		syncInterval: cfg.Membership.SyncInterval,
+		logger:       logger,
 	}
 }
 
-func (s *club100Service) GetPeopleWithRedemptions(ctx context.Context) ([]Club100Person, error) {
-	if !s.elvanto.IsConfigured() {
-		return []Club100Person{}, nil
-	}
-
+func (s *club100Service) GetPeopleWithRedemptions(ctx context.Context, query string) ([]Club100Person, error) {
 	settingsData, err := s.settings.Get(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get settings: %w", err)
 	}
 	maxRedemptions := settingsData.Club100MaxRedemptions
 
-	people, err := s.elvanto.SearchPeople(ctx)
+	members, err := s.members.ListActive(ctx, s.directory.Name())
 	if err != nil {
-		return nil, fmt.Errorf("search people: %w", err)
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+	if strings.TrimSpace(query) != "" {
+		names := make([]string, len(members))
+		for i, m := range members {
+			names[i] = memberSearchName(m)
+		}
+		ranked := make([]*ent.Club100Member, 0, len(members))
+		for _, idx := range namesearch.Rank(query, names) {
+			ranked = append(ranked, members[idx])
+		}
+		members = ranked
 	}
 
-	ids := make([]string, 0, len(people))
-	for _, p := range people {
-		ids = append(ids, p.ID)
+	window, err := s.currentWindow(ctx)
+	if err != nil {
+		return nil, err
 	}
-	totals, err := s.redemptions.GetTotalRedemptionsBatch(ctx, ids)
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.ExternalPersonID)
+	}
+	totals, err := s.redemptions.GetTotalRedemptionsBatch(ctx, s.directory.Name(), ids, window.Start, window.End)
 	if err != nil {
 		return nil, fmt.Errorf("get redemptions: %w", err)
 	}
 
-	result := make([]Club100Person, 0, len(people))
-	for _, p := range people {
-		total := totals[p.ID]
+	result := make([]Club100Person, 0, len(members))
+	for _, m := range members {
+		total := totals[m.ExternalPersonID]
 		remaining := maxRedemptions - total
 		if remaining < 0 {
 			remaining = 0
 		}
 		result = append(result, Club100Person{
-			ID:               p.ID,
-			FirstName:        p.FirstName,
-			LastName:         p.LastName,
+			ID:               m.ExternalPersonID,
+			FirstName:        m.FirstName,
+			LastName:         m.LastName,
 			TotalRedemptions: total,
 			Remaining:        remaining,
 			Max:              maxRedemptions,
@@ -93,14 +198,211 @@ func (s *club100Service) GetPeopleWithRedemptions(ctx context.Context) ([]Club10
 	return result, nil
 }
 
-func (s *club100Service) GetRemainingRedemptions(ctx context.Context, elvantoPersonID string) (remaining int, max int, err error) {
+// memberSearchName is what fuzzy search matches against: both names plus the
+// preferred name, so "Hans" finds Johannes.
+func memberSearchName(m *ent.Club100Member) string {
+	name := m.FirstName + " " + m.LastName
+	if m.PreferredName != nil {
+		name += " " + *m.PreferredName
+	}
+	return name
+}
+
+func (s *club100Service) SyncMembers(ctx context.Context, trigger club100syncrun.Trigger) (*ent.Club100SyncRun, error) {
+	if !s.directory.IsConfigured() {
+		return nil, membership.ErrNotConfigured
+	}
+	if !s.syncMu.TryLock() {
+		return nil, ErrClub100SyncRunning
+	}
+	defer s.syncMu.Unlock()
+	return s.syncLocked(ctx, trigger)
+}
+
+// syncLocked runs one sync; the caller holds syncMu.
+func (s *club100Service) syncLocked(ctx context.Context, trigger club100syncrun.Trigger) (*ent.Club100SyncRun, error) {
+	run, err := s.members.CreateSyncRun(ctx, trigger, s.directory.Name())
+	if err != nil {
+		return nil, fmt.Errorf("create sync run: %w", err)
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, club100SyncTimeout)
+	count, result, syncErr := s.syncMembers(syncCtx)
+	cancel()
+	status := club100syncrun.StatusSucceeded
+	var errMsg *string
+	if syncErr != nil {
+		status = club100syncrun.StatusFailed
+		msg := truncateRunes(syncErr.Error(), 1000)
+		errMsg = &msg
+		s.logger.Error("club100 member sync failed",
+			zap.String("provider", s.directory.Name()),
+			zap.String("trigger", string(trigger)),
+			zap.Error(syncErr))
+	} else {
+		s.logger.Info("club100 member sync finished",
+			zap.String("provider", s.directory.Name()),
+			zap.String("trigger", string(trigger)),
+			zap.Int("members", count),
+			zap.Int("added", result.Added),
+			zap.Int("removed", result.Removed))
+	}
+
+	// Record the outcome even when ctx was cancelled (shutdown, client gone)
+	// so the run never stays "running".
+	finished, err := s.members.FinishSyncRun(context.WithoutCancel(ctx), run.ID, status, count, result, errMsg)
+	if err != nil {
+		return nil, fmt.Errorf("finish sync run: %w", err)
+	}
+	return finished, nil
+}
+
+func (s *club100Service) syncMembers(ctx context.Context) (int, repository.Club100SyncResult, error) {
+	var result repository.Club100SyncResult
+
+	people, err := s.directory.ListMembers(ctx, s.filter)
+	if err != nil {
+		return 0, result, fmt.Errorf("list %s members: %w", s.directory.Name(), err)
+	}
+
+	inputs := make([]repository.Club100MemberInput, 0, len(people))
+	for _, p := range people {
+		if strings.TrimSpace(p.ID) == "" {
+			continue
+		}
+		if utf8.RuneCountInString(p.ID) > club100MemberIDMaxLen {
+			s.logger.Warn("skipping club100 member with overlong id", zap.String("id", truncateRunes(p.ID, 60)))
+			continue
+		}
+		in := repository.Club100MemberInput{
+			ExternalPersonID: p.ID,
+			Provider:         s.directory.Name(),
+			FirstName:        truncateRunes(strings.TrimSpace(p.FirstName), 100),
+			LastName:         truncateRunes(strings.TrimSpace(p.LastName), 100),
+		}
+		if pn := truncateRunes(strings.TrimSpace(p.PreferredName), 100); pn != "" && pn != in.FirstName {
+			in.PreferredName = &pn
+		}
+		inputs = append(inputs, in)
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return 0, result, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	// An empty result is almost always a provider hiccup or a wrong group or
+	// tag filter; keep the directory instead of deactivating everyone.
+	if len(inputs) == 0 {
+		active, err := s.members.ListActive(txCtx, s.directory.Name())
+		if err != nil {
+			return 0, result, err
+		}
+		if len(active) > 0 {
+			return 0, result, fmt.Errorf("%s returned no members, keeping the current directory", s.directory.Name())
+		}
+	}
+
+	result, err = s.members.ReplaceAll(txCtx, inputs, time.Now())
+	if err != nil {
+		return 0, result, fmt.Errorf("replace members: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, result, fmt.Errorf("commit: %w", err)
+	}
+	return len(inputs), result, nil
+}
+
+func (s *club100Service) GetSyncStatus(ctx context.Context) (*Club100SyncStatus, error) {
+	status := &Club100SyncStatus{
+		Provider:   s.directory.Name(),
+		Filter:     s.filter,
+		Configured: s.directory.IsConfigured(),
+		Interval:   s.syncInterval,
+	}
+	if s.syncMu.TryLock() {
+		s.syncMu.Unlock()
+	} else {
+		status.Running = true
+	}
+
+	members, err := s.members.ListActive(ctx, s.directory.Name())
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+	status.MemberCount = len(members)
+
+	last, err := s.members.LatestSyncRun(ctx, nil)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	status.LastRun = last
+
+	succeeded := club100syncrun.StatusSucceeded
+	lastOK, err := s.members.LatestSyncRun(ctx, &succeeded)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	status.LastSuccess = lastOK
+
+	if s.directory.Name() == membership.ProviderCSV {
+		upload, err := s.uploads.Latest(ctx)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		status.LastUpload = upload
+	}
+	return status, nil
+}
+
+func (s *club100Service) UploadMembers(ctx context.Context, filename string, data []byte) (*ent.Club100SyncRun, error) {
+	if s.directory.Name() != membership.ProviderCSV {
+		return nil, ErrClub100UploadUnsupported
+	}
+	members, err := membership.ParseCSV(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if utf8.RuneCountInString(m.ID) > club100MemberIDMaxLen {
+			return nil, fmt.Errorf("%w: id %q is longer than %d characters", membership.ErrInvalidCSV, m.ID, club100MemberIDMaxLen)
+		}
+	}
+
+	// Hold the sync lock across storing and syncing so a scheduled run
+	// cannot pick up the new list half way.
+	if !s.syncMu.TryLock() {
+		return nil, ErrClub100SyncRunning
+	}
+	defer s.syncMu.Unlock()
+
+	if _, err := s.uploads.Create(ctx, truncateRunes(filename, 255), string(data), len(members)); err != nil {
+		return nil, fmt.Errorf("store upload: %w", err)
+	}
+	return s.syncLocked(ctx, club100syncrun.TriggerManual)
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func (s *club100Service) GetRemainingRedemptions(ctx context.Context, externalPersonID string) (remaining int, max int, err error) {
 	settingsData, err := s.settings.Get(ctx)
 	if err != nil {
 		return 0, 0, fmt.Errorf("get settings: %w", err)
 	}
 	max = settingsData.Club100MaxRedemptions
 
-	total, err := s.redemptions.GetTotalRedemptions(ctx, elvantoPersonID)
+	window, err := s.currentWindow(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	total, err := s.redemptions.GetTotalRedemptions(ctx, s.directory.Name(), externalPersonID, window.Start, window.End)
 	if err != nil {
 		return 0, 0, fmt.Errorf("get total redemptions: %w", err)
 	}
@@ -112,24 +414,32 @@ func (s *club100Service) GetRemainingRedemptions(ctx context.Context, elvantoPer
 	return remaining, max, nil
 }
 
-func (s *club100Service) RecordRedemption(ctx context.Context, elvantoPersonID, elvantoPersonName string, orderID string, qty int) error {
+func (s *club100Service) RecordRedemption(ctx context.Context, externalPersonID, personName string, orderID string, eventID *string, qty int) error {
 	if qty <= 0 {
 		return nil
 	}
 
-	remaining, _, err := s.GetRemainingRedemptions(ctx, elvantoPersonID)
-	if err != nil {
-		return fmt.Errorf("check remaining: %w", err)
-	}
-	if remaining < qty {
-		return fmt.Errorf("insufficient remaining redemptions: have %d, need %d", remaining, qty)
-	}
+	return repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		if err := s.members.Lock(ctx, s.directory.Name(), externalPersonID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrClub100MemberNotFound
+			}
+			return fmt.Errorf("lock member: %w", err)
+		}
 
-	_, err = s.redemptions.Create(ctx, elvantoPersonID, elvantoPersonName, orderID, qty)
-	if err != nil {
-		return fmt.Errorf("create redemption: %w", err)
-	}
-	return nil
+		remaining, _, err := s.GetRemainingRedemptions(ctx, externalPersonID)
+		if err != nil {
+			return fmt.Errorf("check remaining: %w", err)
+		}
+		if remaining < qty {
+			return fmt.Errorf("%w: have %d, need %d", ErrClub100InsufficientRedemptions, remaining, qty)
+		}
+
+		if _, err := s.redemptions.Create(ctx, s.directory.Name(), externalPersonID, personName, orderID, eventID, qty); err != nil {
+			return fmt.Errorf("create redemption: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *club100Service) GetFreeProductIDs(ctx context.Context) ([]string, error) {
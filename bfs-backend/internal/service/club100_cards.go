@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/internal/generated/ent"
+	"backend/internal/membership"
+	"backend/internal/qrpayload"
+	"backend/internal/repository"
+)
+
+var (
+	ErrClub100MemberNotFound = errors.New("club100_member_not_found")
+	ErrClub100MemberInactive = errors.New("club100_member_inactive")
+	ErrClub100CardNotFound   = errors.New("club100_card_not_found")
+	ErrClub100CardRevoked    = errors.New("club100_card_revoked")
+)
+
+// Club100CardService issues the printed member cards and resolves scanned
+// ones at the POS.
+type Club100CardService interface {
+	// ListMembers returns every active member with their current card, if
+	// any.
+	ListMembers(ctx context.Context) ([]Club100MemberCard, error)
+	// IssueCard gives a member a new card; an existing one is revoked, so a
+	// reissued card replaces a lost one.
+	IssueCard(ctx context.Context, externalPersonID string) (*Club100MemberCard, error)
+	// IssueMissingCards gives every active member without a card one and
+	// returns how many were issued.
+	IssueMissingCards(ctx context.Context) (int, error)
+	RevokeCard(ctx context.Context, externalPersonID string) error
+	// PrintableCards returns the current cards of the given members, or of
+	// every active member when ids is empty, with their QR payloads.
+	PrintableCards(ctx context.Context, externalPersonIDs []string) ([]Club100PrintableCard, error)
+	// Lookup resolves a scanned card to the member's remaining redemptions.
+	Lookup(ctx context.Context, raw string) (*Club100CardLookup, error)
+}
+
+type Club100MemberCard struct {
+	Member *ent.Club100Member
+	Card   *ent.Club100Card
+}
+
+type Club100PrintableCard struct {
+	Member    *ent.Club100Member
+	Card      *ent.Club100Card
+	QRPayload string
+}
+
+type Club100CardLookup struct {
+	Person         Club100Person
+	FreeProductIDs []string
+	Window         *Club100Window
+}
+
+type club100CardService struct {
+	cards     repository.Club100CardRepository
+	members   repository.Club100MemberRepository
+	directory membership.Provider
+	club100   Club100Service
+	qr        QRService
+	client    *ent.Client
+}
+
+func NewClub100CardService(
+	cards repository.Club100CardRepository,
+	members repository.Club100MemberRepository,
+	directory membership.Provider,
+	club100 Club100Service,
+	qr QRService,
+	client *ent.Client,
+) Club100CardService {
+	return &club100CardService{cards: cards, members: members, directory: directory, club100: club100, qr: qr, client: client}
+}
+
+func (s *club100CardService) ListMembers(ctx context.Context) ([]Club100MemberCard, error) {
+	members, err := s.members.ListActive(ctx, s.directory.Name())
+	if err != nil {
+		return nil, err
+	}
+	cards, err := s.currentCardsByMember(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Club100MemberCard, 0, len(members))
+	for _, m := range members {
+		out = append(out, Club100MemberCard{Member: m, Card: cards[m.ID]})
+	}
+	return out, nil
+}
+
+func (s *club100CardService) IssueCard(ctx context.Context, externalPersonID string) (*Club100MemberCard, error) {
+	member, err := s.activeMember(ctx, externalPersonID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	card, err := s.cards.Issue(repository.ContextWithClient(ctx, tx.Client()), member.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return &Club100MemberCard{Member: member, Card: card}, nil
+}
+
+func (s *club100CardService) IssueMissingCards(ctx context.Context) (int, error) {
+	members, err := s.members.ListActive(ctx, s.directory.Name())
+	if err != nil {
+		return 0, err
+	}
+	cards, err := s.currentCardsByMember(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	issued := 0
+	for _, m := range members {
+		if cards[m.ID] != nil {
+			continue
+		}
+		if _, err := s.cards.Issue(txCtx, m.ID); err != nil {
+			return 0, err
+		}
+		issued++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return issued, nil
+}
+
+func (s *club100CardService) RevokeCard(ctx context.Context, externalPersonID string) error {
+	member, err := s.members.GetByExternalID(ctx, s.directory.Name(), externalPersonID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrClub100MemberNotFound
+		}
+		return err
+	}
+	revoked, err := s.cards.RevokeForMember(ctx, member.ID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrClub100CardNotFound
+	}
+	return nil
+}
+
+func (s *club100CardService) PrintableCards(ctx context.Context, externalPersonIDs []string) ([]Club100PrintableCard, error) {
+	cards, err := s.cards.ListCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[string]bool, len(externalPersonIDs))
+	for _, id := range externalPersonIDs {
+		want[id] = true
+	}
+
+	out := make([]Club100PrintableCard, 0, len(cards))
+	for _, c := range cards {
+		m := c.Edges.Member
+		if m == nil || !m.Active || (len(want) > 0 && !want[m.ExternalPersonID]) {
+			continue
+		}
+		payload, err := s.qr.MemberCardPayload(c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Club100PrintableCard{Member: m, Card: c, QRPayload: payload})
+	}
+	return out, nil
+}
+
+func (s *club100CardService) Lookup(ctx context.Context, raw string) (*Club100CardLookup, error) {
+	token, err := s.qr.Resolve(ctx, qrpayload.TypeMember, raw, "")
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.cards.GetByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrClub100CardNotFound
+		}
+		return nil, err
+	}
+	if card.RevokedAt != nil {
+		return nil, ErrClub100CardRevoked
+	}
+	member := card.Edges.Member
+	if member == nil || !member.Active {
+		return nil, ErrClub100MemberInactive
+	}
+
+	remaining, max, err := s.club100.GetRemainingRedemptions(ctx, member.ExternalPersonID)
+	if err != nil {
+		return nil, err
+	}
+	freeProductIDs, err := s.club100.GetFreeProductIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	window, err := s.club100.GetCurrentWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Club100CardLookup{
+		Person: Club100Person{
+			ID:        member.ExternalPersonID,
+			FirstName: member.FirstName,
+			LastName:  member.LastName,
+			Remaining: remaining,
+			Max:       max,
+		},
+		FreeProductIDs: freeProductIDs,
+		Window:         window,
+	}, nil
+}
+
+func (s *club100CardService) activeMember(ctx context.Context, externalPersonID string) (*ent.Club100Member, error) {
+	member, err := s.members.GetByExternalID(ctx, s.directory.Name(), externalPersonID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrClub100MemberNotFound
+		}
+		return nil, err
+	}
+	if !member.Active {
+		return nil, ErrClub100MemberInactive
+	}
+	return member, nil
+}
+
+func (s *club100CardService) currentCardsByMember(ctx context.Context) (map[string]*ent.Club100Card, error) {
+	cards, err := s.cards.ListCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byMember := make(map[string]*ent.Club100Card, len(cards))
+	for _, c := range cards {
+		byMember[c.MemberID] = c
+	}
+	return byMember, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/tz"
+)
+
+var (
+	ErrClub100PeriodNotFound = errors.New("club100_period_not_found")
+	ErrClub100PeriodInvalid  = errors.New("club100_period_invalid")
+	ErrClub100PeriodOverlap  = errors.New("club100_period_overlap")
+)
+
+// Club100Window is the time range remaining redemptions are counted in: the
+// active entitlement period, or the current Europe/Zurich day when no period
+// is active (Period is nil then).
+type Club100Window struct {
+	Start  time.Time
+	End    time.Time
+	Period *ent.Club100Period
+}
+
+// Club100PeriodInput creates or replaces a period; EndsAt is exclusive.
+type Club100PeriodInput struct {
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// Club100PeriodPatch changes the fields that are set.
+type Club100PeriodPatch struct {
+	Name     *string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+// Club100PeriodReport sums the redemptions of one period per member. Max is
+// the current allowance, not necessarily the one in force back then.
+type Club100PeriodReport struct {
+	Period        *ent.Club100Period
+	Max           int
+	TotalQuantity int
+	Members       []repository.Club100RedemptionTotal
+}
+
+func (s *club100Service) currentWindow(ctx context.Context) (Club100Window, error) {
+	now := time.Now()
+	period, err := s.periods.GetActiveAt(ctx, now)
+	switch {
+	case err == nil:
+		return Club100Window{Start: period.StartsAt, End: period.EndsAt, Period: period}, nil
+	case !errors.Is(err, repository.ErrNotFound):
+		return Club100Window{}, fmt.Errorf("get active period: %w", err)
+	}
+	start, end := schedule.DayBounds(now, tz.Zurich())
+	return Club100Window{Start: start, End: end}, nil
+}
+
+func (s *club100Service) GetCurrentWindow(ctx context.Context) (*Club100Window, error) {
+	w, err := s.currentWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (s *club100Service) ListPeriods(ctx context.Context) ([]*ent.Club100Period, error) {
+	return s.periods.List(ctx)
+}
+
+func (s *club100Service) CreatePeriod(ctx context.Context, in Club100PeriodInput) (*ent.Club100Period, error) {
+	in, err := s.checkPeriod(ctx, in, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.periods.Create(ctx, in.Name, in.StartsAt, in.EndsAt)
+}
+
+func (s *club100Service) UpdatePeriod(ctx context.Context, id string, patch Club100PeriodPatch) (*ent.Club100Period, error) {
+	current, err := s.periods.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrClub100PeriodNotFound
+		}
+		return nil, err
+	}
+	in := Club100PeriodInput{Name: current.Name, StartsAt: current.StartsAt, EndsAt: current.EndsAt}
+	if patch.Name != nil {
+		in.Name = *patch.Name
+	}
+	if patch.StartsAt != nil {
+		in.StartsAt = *patch.StartsAt
+	}
+	if patch.EndsAt != nil {
+		in.EndsAt = *patch.EndsAt
+	}
+	in, err = s.checkPeriod(ctx, in, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.periods.Update(ctx, id, in.Name, in.StartsAt, in.EndsAt)
+}
+
+func (s *club100Service) DeletePeriod(ctx context.Context, id string) error {
+	err := s.periods.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrClub100PeriodNotFound
+	}
+	return err
+}
+
+func (s *club100Service) GetPeriodReport(ctx context.Context, id string) (*Club100PeriodReport, error) {
+	period, err := s.periods.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrClub100PeriodNotFound
+		}
+		return nil, err
+	}
+	max, err := s.GetMaxRedemptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.redemptions.SummarizeByPerson(ctx, period.StartsAt, period.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("summarize redemptions: %w", err)
+	}
+	report := &Club100PeriodReport{Period: period, Max: max, Members: members}
+	for _, m := range members {
+		report.TotalQuantity += m.Quantity
+	}
+	return report, nil
+}
+
+// checkPeriod trims and validates in. Periods must not overlap, otherwise
+// the active one (and with it everyone's allowance) would be ambiguous.
+func (s *club100Service) checkPeriod(ctx context.Context, in Club100PeriodInput, excludeID string) (Club100PeriodInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	switch {
+	case in.Name == "" || utf8.RuneCountInString(in.Name) > 100:
+		return in, fmt.Errorf("%w: name must be 1-100 characters", ErrClub100PeriodInvalid)
+	case in.StartsAt.IsZero() || in.EndsAt.IsZero():
+		return in, fmt.Errorf("%w: start and end are required", ErrClub100PeriodInvalid)
+	case !in.EndsAt.After(in.StartsAt):
+		return in, fmt.Errorf("%w: end must be after start", ErrClub100PeriodInvalid)
+	}
+	overlap, err := s.periods.Overlapping(ctx, in.StartsAt, in.EndsAt, excludeID)
+	if err != nil {
+		return in, err
+	}
+	if overlap {
+		return in, ErrClub100PeriodOverlap
+	}
+	return in, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"backend/internal/config"
+	"backend/internal/i18n"
 
 	"go.uber.org/zap"
 )
@@ -27,8 +28,8 @@ type PlunkSendRequest struct {
 }
 
 type EmailService interface {
-	SendInviteEmail(ctx context.Context, to string, inviteURL string, expiresAt time.Time) error
-	SendOTPEmail(ctx context.Context, to string, otp string, otpType OTPType) error
+	SendInviteEmail(ctx context.Context, to string, inviteURL string, expiresAt time.Time, locale i18n.Locale) error
+	SendOTPEmail(ctx context.Context, to string, otp string, otpType OTPType, locale i18n.Locale) error
 	SendReceiptEmail(ctx context.Context, to string, data ReceiptEmailData) error
 }
 
@@ -46,7 +47,7 @@ func NewEmailService(cfg config.Config, logger *zap.Logger) EmailService {
 	}
 }
 
-func (s *emailService) SendInviteEmail(ctx context.Context, to string, inviteURL string, expiresAt time.Time) error {
+func (s *emailService) SendInviteEmail(ctx context.Context, to string, inviteURL string, expiresAt time.Time, locale i18n.Locale) error {
 	// Check if Plunk is configured
 	if s.cfg.APIKey == "" {
 		s.logger.Warn("Plunk API key not configured, skipping email",
@@ -59,7 +60,8 @@ func (s *emailService) SendInviteEmail(ctx context.Context, to string, inviteURL
 	data := InviteEmailData{
 		Brand:     "BlessThun Food",
 		InviteURL: inviteURL,
-		ExpiresAt: formatExpiry(expiresAt),
+		ExpiresAt: i18n.FormatDateTime(locale, expiresAt),
+		Locale:    locale,
 	}
 
 	htmlBody := renderInviteHTML(data)
@@ -67,7 +69,7 @@ func (s *emailService) SendInviteEmail(ctx context.Context, to string, inviteURL
 
 	payload := PlunkSendRequest{
 		To:         to,
-		Subject:    "Admin-Einladung",
+		Subject:    texts(inviteTexts, locale).Subject,
 		Body:       htmlBody,
 		Subscribed: false,
 		Name:       s.cfg.FromName,
@@ -108,7 +110,7 @@ func (s *emailService) SendInviteEmail(ctx context.Context, to string, inviteURL
 	return nil
 }
 
-func (s *emailService) SendOTPEmail(ctx context.Context, to string, otp string, otpType OTPType) error {
+func (s *emailService) SendOTPEmail(ctx context.Context, to string, otp string, otpType OTPType, locale i18n.Locale) error {
 	if s.cfg.APIKey == "" {
 		s.logger.Warn("Plunk API key not configured, skipping OTP email",
 			zap.String("to", to),
@@ -123,13 +125,14 @@ func (s *emailService) SendOTPEmail(ctx context.Context, to string, otp string,
 	data := OTPEmailData{
 		Brand:       "BlessThun Food",
 		Code:        otp,
-		CodeTTL:     friendlyTTL(otpExpiresInSeconds),
-		SupportNote: "Wir werden dich niemals nach deinem Code fragen.",
+		CodeTTL:     friendlyTTL(otpExpiresInSeconds, locale),
+		SupportNote: texts(otpTexts, locale).SupportNote,
+		Locale:      locale,
 	}
 
 	htmlBody := renderOTPHTML(data)
 	textBody := renderOTPText(data)
-	subject := getOTPSubject(otpType)
+	subject := getOTPSubject(otpType, locale)
 
 	payload := PlunkSendRequest{
 		To:         to,
@@ -196,7 +199,7 @@ func (s *emailService) SendReceiptEmail(ctx context.Context, to string, data Rec
 
 	payload := PlunkSendRequest{
 		To:         to,
-		Subject:    "Deine Quittung — " + data.Brand,
+		Subject:    texts(receiptTexts, data.Locale).Subject + " — " + data.Brand,
 		Body:       htmlBody,
 		Subscribed: false,
 		Name:       s.cfg.FromName,
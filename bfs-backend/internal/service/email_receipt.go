@@ -3,7 +3,9 @@ package service
 import (
 	"fmt"
 	"strings"
-	"time"
+
+	"backend/internal/allergen"
+	"backend/internal/i18n"
 )
 
 type ReceiptLineItem struct {
@@ -11,6 +13,8 @@ type ReceiptLineItem struct {
 	Quantity int
 	Cents    int64
 	Children []ReceiptLineItem
+	// Allergens of the line including its children, as keys.
+	Allergens []string
 }
 
 type ReceiptEmailData struct {
@@ -21,6 +25,72 @@ type ReceiptEmailData struct {
 	Items      []ReceiptLineItem
 	TotalCents int64
 	Method     string
+	Locale     i18n.Locale
+}
+
+// receiptCopy holds the texts of the receipt email in one language.
+type receiptCopy struct {
+	Subject   string
+	Title     string
+	Preheader string // %s: brand, total
+	Thanks    string
+	OrderNo   string
+	Date      string
+	Method    string
+	Items     string
+	Allergens string
+	VAT       string
+	Button    string
+	Generated string
+	Keep      string
+}
+
+var receiptTexts = map[i18n.Locale]receiptCopy{
+	i18n.German: {
+		Subject:   "Deine Quittung",
+		Title:     "Quittung",
+		Preheader: "Deine Quittung von %s — %s",
+		Thanks:    "Vielen Dank für deine Bestellung!",
+		OrderNo:   "Bestellnr.",
+		Date:      "Datum",
+		Method:    "Zahlungsart",
+		Items:     "Artikel",
+		Allergens: "Allergene",
+		VAT:       "Alle Preise in CHF inkl. MwSt.",
+		Button:    "Bestellung & QR-Code anzeigen",
+		Generated: "Dies ist eine automatisch generierte Quittung.",
+		Keep:      "Bitte bewahre diese E-Mail als Zahlungsbeleg auf.",
+	},
+	i18n.French: {
+		Subject:   "Votre reçu",
+		Title:     "Reçu",
+		Preheader: "Votre reçu de %s — %s",
+		Thanks:    "Merci pour votre commande !",
+		OrderNo:   "N° de commande",
+		Date:      "Date",
+		Method:    "Moyen de paiement",
+		Items:     "Articles",
+		Allergens: "Allergènes",
+		VAT:       "Tous les prix en CHF, TVA incluse.",
+		Button:    "Afficher la commande et le code QR",
+		Generated: "Ce reçu a été généré automatiquement.",
+		Keep:      "Veuillez conserver cet e-mail comme justificatif de paiement.",
+	},
+	i18n.English: {
+		Subject:   "Your receipt",
+		Title:     "Receipt",
+		Preheader: "Your receipt from %s — %s",
+		Thanks:    "Thank you for your order!",
+		OrderNo:   "Order no.",
+		Date:      "Date",
+		Method:    "Payment method",
+		Items:     "Items",
+		Allergens: "Allergens",
+		VAT:       "All prices in CHF incl. VAT.",
+		Button:    "View order & QR code",
+		Generated: "This is an automatically generated receipt.",
+		Keep:      "Please keep this email as proof of payment.",
+	},
 }
 
 func formatCHF(cents int64) string {
@@ -32,17 +102,8 @@ func formatCHF(cents int64) string {
 	return fmt.Sprintf("CHF %d.%02d", whole, frac)
 }
 
-func formatOrderDate(t time.Time) string {
-	loc, _ := time.LoadLocation("Europe/Zurich")
-	t = t.In(loc)
-	months := []string{
-		"Januar", "Februar", "März", "April", "Mai", "Juni",
-		"Juli", "August", "September", "Oktober", "November", "Dezember",
-	}
-	return fmt.Sprintf("%d. %s %d, %02d:%02d", t.Day(), months[t.Month()-1], t.Year(), t.Hour(), t.Minute())
-}
-
 func renderReceiptHTML(data ReceiptEmailData) string {
+	c := texts(receiptTexts, data.Locale)
 	var itemRows strings.Builder
 	for _, item := range data.Items {
 		lineTotal := item.Cents * int64(item.Quantity)
@@ -63,10 +124,18 @@ func renderReceiptHTML(data ReceiptEmailData) string {
                       </td>
                     </tr>`, escHTML(child.Title)))
 		}
+		if len(item.Allergens) > 0 {
+			itemRows.WriteString(fmt.Sprintf(`
+                    <tr>
+                      <td colspan="2" style="padding:2px 0 6px 16px;font:11px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;border-bottom:1px solid #EEEEEE;">
+                        %s: %s
+                      </td>
+                    </tr>`, escHTML(c.Allergens), escHTML(strings.Join(allergen.LabelsIn(data.Locale, item.Allergens), ", "))))
+		}
 	}
 
 	return fmt.Sprintf(`<!doctype html>
-<html lang="de" dir="ltr"
+<html lang="%s" dir="ltr"
       xmlns:v="urn:schemas-microsoft-com:vml"
       xmlns:o="urn:schemas-microsoft-com:office:office">
   <head>
@@ -76,7 +145,7 @@ func renderReceiptHTML(data ReceiptEmailData) string {
     <meta name="format-detection" content="telephone=no,address=no,email=no,date=no,url=no">
     <meta name="color-scheme" content="light">
     <meta name="supported-color-schemes" content="light">
-    <title>%s Quittung</title>
+    <title>%s %s</title>
     <!--[if mso]>
       <noscript>
         <xml>
@@ -98,7 +167,7 @@ func renderReceiptHTML(data ReceiptEmailData) string {
   </head>
   <body style="margin:0;padding:0;">
     <div style="display:none;max-height:0;overflow:hidden;mso-hide:all;color:transparent;opacity:0;">
-      Deine Quittung von %s — %s &nbsp;&#8205;&nbsp;&#8205;&nbsp;&#8205;
+      %s &nbsp;&#8205;&nbsp;&#8205;&nbsp;&#8205;
     </div>
     <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"
            bgcolor="#E9E7E6" style="background-color:#E9E7E6;">
@@ -122,10 +191,10 @@ func renderReceiptHTML(data ReceiptEmailData) string {
             <tr>
               <td class="px" style="padding:8px 24px 24px 24px;">
                 <p style="margin:0 0 4px 0;font:600 18px/1.3 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#000000;">
-                  Deine Quittung
+                  %s
                 </p>
                 <p style="margin:0 0 20px 0;font:13px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Vielen Dank für deine Bestellung!
+                  %s
                 </p>
 
                 <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"
@@ -135,7 +204,7 @@ func renderReceiptHTML(data ReceiptEmailData) string {
                       <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0">
                         <tr>
                           <td style="font:12px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;padding-bottom:2px;">
-                            Bestellnr.
+                            %s
                           </td>
                         </tr>
                         <tr>
@@ -145,7 +214,7 @@ func renderReceiptHTML(data ReceiptEmailData) string {
                         </tr>
                         <tr>
                           <td style="font:12px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                            Datum
+                            %s
                           </td>
                           <td align="right" style="font:12px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
                             %s
@@ -153,7 +222,7 @@ func renderReceiptHTML(data ReceiptEmailData) string {
                         </tr>
                         <tr>
                           <td style="font:12px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                            Zahlungsart
+                            %s
                           </td>
                           <td align="right" style="font:12px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
                             %s
@@ -177,14 +246,14 @@ func renderReceiptHTML(data ReceiptEmailData) string {
                 </table>
 
                 <p style="margin:8px 0 0 0;font:11px/1.4 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Alle Preise in CHF inkl. MwSt.
+                  %s
                 </p>
 
                 <table role="presentation" cellpadding="0" cellspacing="0" border="0" width="100%%" style="margin:20px 0 0 0;">
                   <tr>
                     <td align="center" bgcolor="#000000" style="border-radius:11px;">
                       <a href="%s" target="_blank" style="display:block;padding:16px 28px;font:600 16px/1 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#FFFFFF;text-decoration:none;text-align:center;">
-                        Bestellung &amp; QR-Code anzeigen
+                        %s
                       </a>
                     </td>
                   </tr>
@@ -203,8 +272,8 @@ func renderReceiptHTML(data ReceiptEmailData) string {
                   </tr>
                 </table>
                 <p style="margin:12px 0 0 0;font:11px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#999999;">
-                  Dies ist eine automatisch generierte Quittung.
-                  Bitte bewahre diese E-Mail als Zahlungsbeleg auf.
+                  %s
+                  %s
                 </p>
               </td>
             </tr>
@@ -214,19 +283,29 @@ func renderReceiptHTML(data ReceiptEmailData) string {
     </table>
   </body>
 </html>`,
+		data.Locale,
+		data.Brand, escHTML(c.Title),
+		escHTML(fmt.Sprintf(c.Preheader, data.Brand, formatCHF(data.TotalCents))),
 		data.Brand,
-		data.Brand, formatCHF(data.TotalCents),
-		data.Brand,
+		escHTML(c.Subject),
+		escHTML(c.Thanks),
+		escHTML(c.OrderNo),
 		escHTML(data.OrderID),
+		escHTML(c.Date),
 		escHTML(data.OrderDate),
+		escHTML(c.Method),
 		escHTML(data.Method),
 		itemRows.String(),
 		formatCHF(data.TotalCents),
+		escHTML(c.VAT),
 		escHTML(data.OrderURL),
+		escHTML(c.Button),
+		escHTML(c.Generated), escHTML(c.Keep),
 	)
 }
 
 func renderReceiptText(data ReceiptEmailData) string {
+	c := texts(receiptTexts, data.Locale)
 	var lines strings.Builder
 	for _, item := range data.Items {
 		lineTotal := item.Cents * int64(item.Quantity)
@@ -236,29 +315,33 @@ func renderReceiptText(data ReceiptEmailData) string {
 		}
 	}
 
-	return fmt.Sprintf(`%s — Quittung
+	return fmt.Sprintf(`%s — %s
 
-Vielen Dank für deine Bestellung!
+%s
 
-Bestellnr.: %s
-Datum: %s
-Zahlungsart: %s
+%s: %s
+%s: %s
+%s: %s
 
-Artikel:
+%s:
 %s
 Total: %s
-Alle Preise in CHF inkl. MwSt.
+%s
 
-Bestellung & QR-Code anzeigen:
+%s:
 %s
 
 ---
 BlessThun Food
 Industriestrasse 5, 3600 Thun
 
-Dies ist eine automatisch generierte Quittung.
-Bitte bewahre diese E-Mail als Zahlungsbeleg auf.
-`, data.Brand, data.OrderID, data.OrderDate, data.Method, lines.String(), formatCHF(data.TotalCents), data.OrderURL)
+%s
+%s
+`, data.Brand, c.Title, c.Thanks,
+		c.OrderNo, data.OrderID, c.Date, data.OrderDate, c.Method, data.Method,
+		c.Items, lines.String(), formatCHF(data.TotalCents), c.VAT,
+		c.Button, data.OrderURL,
+		c.Generated, c.Keep)
 }
 
 func escHTML(s string) string {
@@ -2,7 +2,9 @@ package service
 
 import (
 	"fmt"
-	"time"
+	"strings"
+
+	"backend/internal/i18n"
 )
 
 // InviteEmailData contains the data for rendering an admin invite email.
@@ -10,27 +12,67 @@ type InviteEmailData struct {
 	Brand     string
 	InviteURL string
 	ExpiresAt string
+	Locale    i18n.Locale
 }
 
-// formatExpiry formats a time.Time in German Swiss locale.
-func formatExpiry(t time.Time) string {
-	// Format as "2. Januar 2025, 14:30"
-	loc, _ := time.LoadLocation("Europe/Zurich")
-	t = t.In(loc)
+// inviteCopy holds the texts of the invite email in one language.
+type inviteCopy struct {
+	Subject     string
+	Preheader   string // %s: brand
+	Intro       string // %s: brand
+	Instruction string
+	Button      string
+	ValidUntil  string
+	LinkHint    string
+	Ignore      string
+}
 
-	months := []string{
-		"Januar", "Februar", "März", "April", "Mai", "Juni",
-		"Juli", "August", "September", "Oktober", "November", "Dezember",
-	}
-	month := months[t.Month()-1]
+var inviteTexts = map[i18n.Locale]inviteCopy{
+	i18n.German: {
+		Subject:     "Admin-Einladung",
+		Preheader:   "Du wurdest eingeladen, Admin bei %s zu werden",
+		Intro:       "Du wurdest eingeladen, als Administrator bei %s beizutreten.",
+		Instruction: "Klicke auf den Button unten, um die Einladung anzunehmen.",
+		Button:      "Einladung annehmen",
+		ValidUntil:  "Diese Einladung ist gueltig bis:",
+		LinkHint:    "Falls der Button nicht funktioniert, kopiere diesen Link in deinen Browser:",
+		Ignore:      "Wenn du diese Einladung nicht erwartet hast, kannst du diese E-Mail ignorieren.",
+	},
+	i18n.French: {
+		Subject:     "Invitation administrateur",
+		Preheader:   "Vous êtes invité à devenir administrateur de %s",
+		Intro:       "Vous êtes invité à rejoindre %s en tant qu'administrateur.",
+		Instruction: "Cliquez sur le bouton ci-dessous pour accepter l'invitation.",
+		Button:      "Accepter l'invitation",
+		ValidUntil:  "Cette invitation est valable jusqu'au :",
+		LinkHint:    "Si le bouton ne fonctionne pas, copiez ce lien dans votre navigateur :",
+		Ignore:      "Si vous n'attendiez pas cette invitation, vous pouvez ignorer cet e-mail.",
+	},
+	i18n.English: {
+		Subject:     "Admin invitation",
+		Preheader:   "You have been invited to become an admin of %s",
+		Intro:       "You have been invited to join %s as an administrator.",
+		Instruction: "Click the button below to accept the invitation.",
+		Button:      "Accept invitation",
+		ValidUntil:  "This invitation is valid until:",
+		LinkHint:    "If the button does not work, copy this link into your browser:",
+		Ignore:      "If you were not expecting this invitation, you can ignore this email.",
+	},
+}
 
-	return fmt.Sprintf("%d. %s %d, %02d:%02d", t.Day(), month, t.Year(), t.Hour(), t.Minute())
+// texts returns the copy of l, or the German one.
+func texts[T any](copies map[i18n.Locale]T, l i18n.Locale) T {
+	if c, ok := copies[l]; ok {
+		return c
+	}
+	return copies[i18n.Default]
 }
 
 // renderInviteHTML generates the HTML version of the admin invite email.
 func renderInviteHTML(data InviteEmailData) string {
+	c := texts(inviteTexts, data.Locale)
 	return fmt.Sprintf(`<!doctype html>
-<html lang="de" dir="ltr"
+<html lang="%s" dir="ltr"
       xmlns:v="urn:schemas-microsoft-com:vml"
       xmlns:o="urn:schemas-microsoft-com:office:office">
   <head>
@@ -40,7 +82,7 @@ func renderInviteHTML(data InviteEmailData) string {
     <meta name="format-detection" content="telephone=no,address=no,email=no,date=no,url=no">
     <meta name="color-scheme" content="light">
     <meta name="supported-color-schemes" content="light">
-    <title>%s Admin-Einladung</title>
+    <title>%s %s</title>
     <!--[if mso]>
       <noscript>
         <xml>
@@ -62,7 +104,7 @@ func renderInviteHTML(data InviteEmailData) string {
   </head>
   <body style="margin:0;padding:0;">
     <div style="display:none;max-height:0;overflow:hidden;mso-hide:all;color:transparent;opacity:0;">
-      Du wurdest eingeladen, Admin bei %s zu werden &nbsp;&#8205;&nbsp;&#8205;&nbsp;&#8205;
+      %s &nbsp;&#8205;&nbsp;&#8205;&nbsp;&#8205;
     </div>
     <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"
            bgcolor="#E9E7E6" style="background-color:#E9E7E6;">
@@ -86,29 +128,29 @@ func renderInviteHTML(data InviteEmailData) string {
             <tr>
               <td class="px" style="padding:8px 24px 24px 24px;">
                 <p style="margin:0 0 8px 0;font:600 18px/1.3 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#000000;">
-                  Admin-Einladung
+                  %s
                 </p>
                 <p style="margin:0 0 16px 0;font:13px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Du wurdest eingeladen, als Administrator bei %s beizutreten.
-                  Klicke auf den Button unten, um die Einladung anzunehmen.
+                  %s
+                  %s
                 </p>
                 <table role="presentation" cellpadding="0" cellspacing="0" border="0" style="margin:16px 0;">
                   <tr>
                     <td align="center" bgcolor="#000000" style="border-radius:7px;">
                       <a href="%s" target="_blank" style="display:inline-block;padding:14px 28px;font:600 14px/1 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#FFFFFF;text-decoration:none;">
-                        Einladung annehmen
+                        %s
                       </a>
                     </td>
                   </tr>
                 </table>
                 <p style="margin:16px 0 0 0;font:12px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Diese Einladung ist gueltig bis: %s
+                  %s %s
                 </p>
                 <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0" style="margin:16px 0;">
                   <tr><td height="1" style="line-height:1px;font-size:1px;background:#D7D7D7;">&nbsp;</td></tr>
                 </table>
                 <p style="margin:0;font:12px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Falls der Button nicht funktioniert, kopiere diesen Link in deinen Browser:
+                  %s
                 </p>
                 <p style="margin:8px 0 0 0;font:11px/1.5 ui-monospace,SFMono-Regular,Menlo,Monaco,Consolas,'Liberation Mono','Courier New',monospace;color:#7B7B7B;word-break:break-all;">
                   %s
@@ -118,7 +160,7 @@ func renderInviteHTML(data InviteEmailData) string {
             <tr>
               <td class="px" style="padding:16px 24px 24px 24px;">
                 <p style="margin:0;font:12px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Wenn du diese Einladung nicht erwartet hast, kannst du diese E-Mail ignorieren.
+                  %s
                 </p>
               </td>
             </tr>
@@ -127,22 +169,35 @@ func renderInviteHTML(data InviteEmailData) string {
       </tr>
     </table>
   </body>
-</html>`, data.Brand, data.Brand, data.Brand, data.Brand, data.InviteURL, data.ExpiresAt, data.InviteURL)
+</html>`,
+		data.Locale,
+		data.Brand, escHTML(c.Subject),
+		escHTML(fmt.Sprintf(c.Preheader, data.Brand)),
+		data.Brand,
+		escHTML(c.Subject),
+		escHTML(fmt.Sprintf(c.Intro, data.Brand)), escHTML(c.Instruction),
+		data.InviteURL, escHTML(c.Button),
+		escHTML(c.ValidUntil), data.ExpiresAt,
+		escHTML(c.LinkHint),
+		data.InviteURL,
+		escHTML(c.Ignore),
+	)
 }
 
 // renderInviteText generates the plain text version of the admin invite email.
 func renderInviteText(data InviteEmailData) string {
-	return fmt.Sprintf(`%s Admin-Einladung
+	c := texts(inviteTexts, data.Locale)
+	return fmt.Sprintf(`%s %s
 
-Du wurdest eingeladen, als Administrator bei %s beizutreten.
+%s
 
-Einladung annehmen:
+%s:
 %s
 
-Diese Einladung ist gueltig bis: %s
+%s %s
 
-Wenn du diese Einladung nicht erwartet hast, kannst du diese E-Mail ignorieren.
-`, data.Brand, data.Brand, data.InviteURL, data.ExpiresAt)
+%s
+`, data.Brand, c.Subject, fmt.Sprintf(c.Intro, data.Brand), c.Button, data.InviteURL, c.ValidUntil, data.ExpiresAt, c.Ignore)
 }
 
 // OTPType represents the type of OTP being sent.
@@ -160,10 +215,83 @@ type OTPEmailData struct {
 	Code        string
 	CodeTTL     string
 	SupportNote string
+	Locale      i18n.Locale
 }
 
-// friendlyTTL formats duration in a human-friendly German format.
-func friendlyTTL(seconds int) string {
+// otpCopy holds the texts of the OTP email in one language.
+type otpCopy struct {
+	SubjectSignIn   string
+	SubjectReset    string
+	SubjectVerify   string
+	Title           string
+	Preheader       string // %s: brand, code, TTL
+	Heading         string
+	Instruction     string
+	Expiry          string // %s: TTL
+	TextCode        string // %s: TTL
+	SupportNote     string
+	Ignore          string
+	Day, Days, Hour string // Days: %d
+	Minute          string
+}
+
+var otpTexts = map[i18n.Locale]otpCopy{
+	i18n.German: {
+		SubjectSignIn: "Dein Anmeldecode",
+		SubjectReset:  "Passwort zurücksetzen",
+		SubjectVerify: "Dein Verifizierungscode",
+		Title:         "Anmeldung",
+		Preheader:     "Dein einmaliger Code für %s: %s (läuft in %s ab)",
+		Heading:       "Anmelden mit Code",
+		Instruction:   "Gib diesen einmaligen 6-stelligen Code im Anmeldefenster ein:",
+		Expiry:        "Der Code läuft in %s ab und kann nur einmal verwendet werden.",
+		TextCode:      "Dein Code (läuft in %s ab):",
+		SupportNote:   "Wir werden dich niemals nach deinem Code fragen.",
+		Ignore:        "Wenn du dies nicht angefordert hast, kannst du diese E-Mail ignorieren.",
+		Day:           "1 Tag",
+		Days:          "%d Tagen",
+		Hour:          "Std",
+		Minute:        "Min",
+	},
+	i18n.French: {
+		SubjectSignIn: "Votre code de connexion",
+		SubjectReset:  "Réinitialiser le mot de passe",
+		SubjectVerify: "Votre code de vérification",
+		Title:         "Connexion",
+		Preheader:     "Votre code unique pour %s : %s (expire dans %s)",
+		Heading:       "Se connecter avec un code",
+		Instruction:   "Saisissez ce code unique à 6 chiffres dans la fenêtre de connexion :",
+		Expiry:        "Le code expire dans %s et ne peut être utilisé qu'une seule fois.",
+		TextCode:      "Votre code (expire dans %s) :",
+		SupportNote:   "Nous ne vous demanderons jamais votre code.",
+		Ignore:        "Si vous n'êtes pas à l'origine de cette demande, vous pouvez ignorer cet e-mail.",
+		Day:           "1 jour",
+		Days:          "%d jours",
+		Hour:          "h",
+		Minute:        "min",
+	},
+	i18n.English: {
+		SubjectSignIn: "Your sign-in code",
+		SubjectReset:  "Reset your password",
+		SubjectVerify: "Your verification code",
+		Title:         "Sign-in",
+		Preheader:     "Your one-time code for %s: %s (expires in %s)",
+		Heading:       "Sign in with a code",
+		Instruction:   "Enter this one-time 6-digit code in the sign-in window:",
+		Expiry:        "The code expires in %s and can only be used once.",
+		TextCode:      "Your code (expires in %s):",
+		SupportNote:   "We will never ask you for your code.",
+		Ignore:        "If you did not request this, you can ignore this email.",
+		Day:           "1 day",
+		Days:          "%d days",
+		Hour:          "h",
+		Minute:        "min",
+	},
+}
+
+// friendlyTTL formats duration in a human-friendly format in l.
+func friendlyTTL(seconds int, l i18n.Locale) string {
+	c := texts(otpTexts, l)
 	minutes := seconds / 60
 	hours := minutes / 60
 	days := hours / 24
@@ -172,48 +300,43 @@ func friendlyTTL(seconds int) string {
 
 	if days > 0 {
 		if days == 1 {
-			parts = append(parts, "1 Tag")
+			parts = append(parts, c.Day)
 		} else {
-			parts = append(parts, fmt.Sprintf("%d Tagen", days))
+			parts = append(parts, fmt.Sprintf(c.Days, days))
 		}
 	}
 
 	remainingHours := hours % 24
 	if remainingHours > 0 {
-		parts = append(parts, fmt.Sprintf("%d Std", remainingHours))
+		parts = append(parts, fmt.Sprintf("%d %s", remainingHours, c.Hour))
 	}
 
 	remainingMinutes := minutes % 60
 	if remainingMinutes > 0 || len(parts) == 0 {
-		parts = append(parts, fmt.Sprintf("%d Min", remainingMinutes))
+		parts = append(parts, fmt.Sprintf("%d %s", remainingMinutes, c.Minute))
 	}
 
-	result := ""
-	for i, p := range parts {
-		if i > 0 {
-			result += " "
-		}
-		result += p
-	}
-	return result
+	return strings.Join(parts, " ")
 }
 
 // getOTPSubject returns the email subject based on OTP type.
-func getOTPSubject(otpType OTPType) string {
+func getOTPSubject(otpType OTPType, l i18n.Locale) string {
+	c := texts(otpTexts, l)
 	switch otpType {
 	case OTPTypeSignIn:
-		return "Dein Anmeldecode"
+		return c.SubjectSignIn
 	case OTPTypeForgetPassword:
-		return "Passwort zurücksetzen"
+		return c.SubjectReset
 	default:
-		return "Dein Verifizierungscode"
+		return c.SubjectVerify
 	}
 }
 
 // renderOTPHTML generates the HTML version of the OTP email.
 func renderOTPHTML(data OTPEmailData) string {
+	c := texts(otpTexts, data.Locale)
 	return fmt.Sprintf(`<!doctype html>
-<html lang="de" dir="ltr"
+<html lang="%s" dir="ltr"
       xmlns:v="urn:schemas-microsoft-com:vml"
       xmlns:o="urn:schemas-microsoft-com:office:office">
   <head>
@@ -223,7 +346,7 @@ func renderOTPHTML(data OTPEmailData) string {
     <meta name="format-detection" content="telephone=no,address=no,email=no,date=no,url=no">
     <meta name="color-scheme" content="light">
     <meta name="supported-color-schemes" content="light">
-    <title>%s Anmeldung</title>
+    <title>%s %s</title>
     <!--[if mso]>
       <noscript>
         <xml>
@@ -245,7 +368,7 @@ func renderOTPHTML(data OTPEmailData) string {
   </head>
   <body style="margin:0;padding:0;">
     <div style="display:none;max-height:0;overflow:hidden;mso-hide:all;color:transparent;opacity:0;">
-      Dein einmaliger Code für %s: %s (läuft in %s ab) &nbsp;&#8205;&nbsp;&#8205;&nbsp;&#8205;
+      %s &nbsp;&#8205;&nbsp;&#8205;&nbsp;&#8205;
     </div>
     <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"
            bgcolor="#E9E7E6" style="background-color:#E9E7E6;">
@@ -269,10 +392,10 @@ func renderOTPHTML(data OTPEmailData) string {
             <tr>
               <td class="px" style="padding:8px 24px 24px 24px;">
                 <p style="margin:0 0 8px 0;font:600 18px/1.3 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#000000;">
-                  Anmelden mit Code
+                  %s
                 </p>
                 <p style="margin:0 0 16px 0;font:13px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Gib diesen einmaligen 6-stelligen Code im Anmeldefenster ein:
+                  %s
                 </p>
                 <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"
                        style="background:#FFFFFF;border:1px solid #D7D7D7;border-radius:7px;">
@@ -285,7 +408,7 @@ func renderOTPHTML(data OTPEmailData) string {
                   </tr>
                 </table>
                 <p style="margin:16px 0 0 0;font:12px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Der Code läuft in %s ab und kann nur einmal verwendet werden.
+                  %s
                 </p>
                 <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0" style="margin:16px 0;">
                   <tr><td height="1" style="line-height:1px;font-size:1px;background:#D7D7D7;">&nbsp;</td></tr>
@@ -298,7 +421,7 @@ func renderOTPHTML(data OTPEmailData) string {
             <tr>
               <td class="px" style="padding:16px 24px 24px 24px;">
                 <p style="margin:0;font:12px/1.5 -apple-system,Segoe UI,Roboto,Helvetica,Arial,sans-serif;color:#7B7B7B;">
-                  Wenn du dies nicht angefordert hast, kannst du diese E-Mail ignorieren.
+                  %s
                 </p>
               </td>
             </tr>
@@ -307,18 +430,30 @@ func renderOTPHTML(data OTPEmailData) string {
       </tr>
     </table>
   </body>
-</html>`, data.Brand, data.Brand, data.Code, data.CodeTTL, data.Brand, data.Code, data.CodeTTL, data.SupportNote)
+</html>`,
+		data.Locale,
+		data.Brand, escHTML(c.Title),
+		escHTML(fmt.Sprintf(c.Preheader, data.Brand, data.Code, data.CodeTTL)),
+		data.Brand,
+		escHTML(c.Heading),
+		escHTML(c.Instruction),
+		data.Code,
+		escHTML(fmt.Sprintf(c.Expiry, data.CodeTTL)),
+		escHTML(data.SupportNote),
+		escHTML(c.Ignore),
+	)
 }
 
 // renderOTPText generates the plain text version of the OTP email.
 func renderOTPText(data OTPEmailData) string {
-	return fmt.Sprintf(`%s Anmeldung
+	c := texts(otpTexts, data.Locale)
+	return fmt.Sprintf(`%s %s
 
-Dein Code (läuft in %s ab):
+%s
   %s
 
 %s
 
-Wenn du dies nicht angefordert hast, kannst du diese E-Mail ignorieren.
-`, data.Brand, data.CodeTTL, data.Code, data.SupportNote)
+%s
+`, data.Brand, c.Title, fmt.Sprintf(c.TextCode, data.CodeTTL), data.Code, data.SupportNote, c.Ignore)
 }
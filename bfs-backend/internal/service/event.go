@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/generated/ent"
+	nanoid "backend/internal/id"
+	"backend/internal/repository"
+)
+
+var (
+	ErrEventNotFound = errors.New("event_not_found")
+	ErrEventInvalid  = errors.New("event_invalid")
+	// ErrEventInUse: orders, ledger entries or Club100 redemptions are tagged
+	// with the event, so deleting it would drop them from its reports.
+	ErrEventInUse = errors.New("event_in_use")
+	// ErrProductNotAtEvent: the active event takes the product off its menu.
+	ErrProductNotAtEvent = errors.New("product_not_at_event")
+)
+
+const eventCacheTTL = 5 * time.Second
+
+// EventInput creates an event; EndsAt is exclusive.
+type EventInput struct {
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Active   bool
+}
+
+// EventPatch changes the fields that are set.
+type EventPatch struct {
+	Name     *string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Active   *bool
+}
+
+// EventCatalog is an event's product overrides. A nil catalog stands for no
+// active event and leaves the base catalog unchanged.
+type EventCatalog struct {
+	Event     *ent.Event
+	overrides map[string]*ent.EventProduct
+}
+
+// EventID returns the ID to tag new rows with, nil without an event.
+func (c *EventCatalog) EventID() *string {
+	if c == nil {
+		return nil
+	}
+	return &c.Event.ID
+}
+
+// Available reports whether the product is on the event's menu.
+func (c *EventCatalog) Available(productID string) bool {
+	if c == nil {
+		return true
+	}
+	o, ok := c.overrides[productID]
+	return !ok || o.Available
+}
+
+// PriceCents returns the product's price at the event.
+func (c *EventCatalog) PriceCents(p *ent.Product) int64 {
+	if c == nil {
+		return p.PriceCents
+	}
+	if o, ok := c.overrides[p.ID]; ok && o.PriceCents != nil {
+		return *o.PriceCents
+	}
+	return p.PriceCents
+}
+
+// VariantPriceCents returns the variant's price at the event. An override
+// moves the variant by as much as it moves the product, so the variants keep
+// their spread; the result never drops below zero.
+func (c *EventCatalog) VariantPriceCents(p *ent.Product, variantPriceCents int64) int64 {
+	return max(variantPriceCents+c.PriceCents(p)-p.PriceCents, 0)
+}
+
+type EventService interface {
+	// List returns all events, latest first.
+	List(ctx context.Context) ([]*ent.Event, error)
+	Get(ctx context.Context, id string) (*ent.Event, error)
+	// Create adds an event; an active one replaces the active event.
+	Create(ctx context.Context, in EventInput) (*ent.Event, error)
+	Update(ctx context.Context, id string, patch EventPatch) (*ent.Event, error)
+	// Delete removes an event nothing is tagged with yet.
+	Delete(ctx context.Context, id string) error
+	ListProducts(ctx context.Context, eventID string) ([]*ent.EventProduct, error)
+	// SetProducts replaces the event's product overrides.
+	SetProducts(ctx context.Context, eventID string, products []repository.EventProductInput) ([]*ent.EventProduct, error)
+	// Current returns the active event's catalog, nil without an active
+	// event. It is cached briefly because checkout and the product list ask
+	// on every request.
+	Current(ctx context.Context) (*EventCatalog, error)
+	// Catalog returns the catalog of any event.
+	Catalog(ctx context.Context, eventID string) (*EventCatalog, error)
+}
+
+type eventState struct {
+	catalog   *EventCatalog
+	expiresAt time.Time
+}
+
+type eventService struct {
+	events repository.EventRepository
+	client *ent.Client
+	cache  atomic.Pointer[eventState]
+}
+
+func NewEventService(events repository.EventRepository, client *ent.Client) EventService {
+	return &eventService{events: events, client: client}
+}
+
+func (s *eventService) List(ctx context.Context) ([]*ent.Event, error) {
+	return s.events.List(ctx)
+}
+
+func (s *eventService) Get(ctx context.Context, id string) (*ent.Event, error) {
+	e, err := s.events.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEventNotFound
+	}
+	return e, err
+}
+
+func (s *eventService) Create(ctx context.Context, in EventInput) (*ent.Event, error) {
+	in, err := checkEvent(in)
+	if err != nil {
+		return nil, err
+	}
+	var created *ent.Event
+	err = repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		var err error
+		if created, err = s.events.Create(ctx, in.Name, in.StartsAt, in.EndsAt); err != nil {
+			return err
+		}
+		if in.Active {
+			if err := s.events.SetActive(ctx, created.ID, true); err != nil {
+				return err
+			}
+			created.Active = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Store(nil)
+	return created, nil
+}
+
+func (s *eventService) Update(ctx context.Context, id string, patch EventPatch) (*ent.Event, error) {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	in := EventInput{Name: current.Name, StartsAt: current.StartsAt, EndsAt: current.EndsAt, Active: current.Active}
+	if patch.Name != nil {
+		in.Name = *patch.Name
+	}
+	if patch.StartsAt != nil {
+		in.StartsAt = *patch.StartsAt
+	}
+	if patch.EndsAt != nil {
+		in.EndsAt = *patch.EndsAt
+	}
+	if patch.Active != nil {
+		in.Active = *patch.Active
+	}
+	if in, err = checkEvent(in); err != nil {
+		return nil, err
+	}
+
+	var updated *ent.Event
+	err = repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.events.Update(ctx, id, in.Name, in.StartsAt, in.EndsAt); err != nil {
+			return err
+		}
+		if in.Active != current.Active {
+			if err := s.events.SetActive(ctx, id, in.Active); err != nil {
+				return err
+			}
+			updated.Active = in.Active
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Store(nil)
+	return updated, nil
+}
+
+func (s *eventService) Delete(ctx context.Context, id string) error {
+	used, err := s.events.InUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrEventInUse
+	}
+	err = s.events.Delete(ctx, id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrEventNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return ErrEventInUse
+	case err != nil:
+		return err
+	}
+	s.cache.Store(nil)
+	return nil
+}
+
+func (s *eventService) ListProducts(ctx context.Context, eventID string) ([]*ent.EventProduct, error) {
+	if _, err := s.Get(ctx, eventID); err != nil {
+		return nil, err
+	}
+	return s.events.ListProducts(ctx, eventID)
+}
+
+func (s *eventService) SetProducts(ctx context.Context, eventID string, products []repository.EventProductInput) ([]*ent.EventProduct, error) {
+	seen := make(map[string]bool, len(products))
+	for _, p := range products {
+		if !nanoid.Valid(p.ProductID) {
+			return nil, fmt.Errorf("%w: invalid product id %q", ErrEventInvalid, p.ProductID)
+		}
+		if seen[p.ProductID] {
+			return nil, fmt.Errorf("%w: product %s is listed twice", ErrEventInvalid, p.ProductID)
+		}
+		seen[p.ProductID] = true
+		if p.PriceCents != nil && *p.PriceCents < 0 {
+			return nil, fmt.Errorf("%w: price must not be negative", ErrEventInvalid)
+		}
+	}
+
+	err := repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		if _, err := s.Get(ctx, eventID); err != nil {
+			return err
+		}
+		return s.events.ReplaceProducts(ctx, eventID, products)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, fmt.Errorf("%w: unknown product", ErrEventInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Store(nil)
+	return s.events.ListProducts(ctx, eventID)
+}
+
+func (s *eventService) Current(ctx context.Context) (*EventCatalog, error) {
+	if cached := s.cache.Load(); cached != nil && time.Now().Before(cached.expiresAt) {
+		return cached.catalog, nil
+	}
+	active, err := s.events.GetActive(ctx)
+	var catalog *EventCatalog
+	switch {
+	case err == nil:
+		if catalog, err = s.catalogOf(ctx, active); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, fmt.Errorf("get active event: %w", err)
+	}
+	s.cache.Store(&eventState{catalog: catalog, expiresAt: time.Now().Add(eventCacheTTL)})
+	return catalog, nil
+}
+
+func (s *eventService) Catalog(ctx context.Context, eventID string) (*EventCatalog, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return s.catalogOf(ctx, e)
+}
+
+func (s *eventService) catalogOf(ctx context.Context, e *ent.Event) (*EventCatalog, error) {
+	rows, err := s.events.ListProducts(ctx, e.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list event products: %w", err)
+	}
+	catalog := &EventCatalog{Event: e, overrides: make(map[string]*ent.EventProduct, len(rows))}
+	for _, row := range rows {
+		catalog.overrides[row.ProductID] = row
+	}
+	return catalog, nil
+}
+
+func checkEvent(in EventInput) (EventInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	switch {
+	case in.Name == "" || utf8.RuneCountInString(in.Name) > 100:
+		return in, fmt.Errorf("%w: name must be 1-100 characters", ErrEventInvalid)
+	case in.StartsAt.IsZero() || in.EndsAt.IsZero():
+		return in, fmt.Errorf("%w: start and end are required", ErrEventInvalid)
+	case !in.EndsAt.After(in.StartsAt):
+		return in, fmt.Errorf("%w: end must be after start", ErrEventInvalid)
+	}
+	return in, nil
+}
+
+// currentEventCatalog returns the active event's catalog. Services built
+// without an event service, as in tests, sell the base catalog.
+func currentEventCatalog(ctx context.Context, events EventService) (*EventCatalog, error) {
+	if events == nil {
+		return nil, nil
+	}
+	return events.Current(ctx)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/internal/config"
+	"backend/internal/membership"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// NewMembershipProvider builds the member directory selected by
+// MEMBERSHIP_PROVIDER.
+func NewMembershipProvider(cfg config.Config, uploads repository.MembershipUploadRepository, logger *zap.Logger) (membership.Provider, error) {
+	switch cfg.Membership.Provider {
+	case membership.ProviderElvanto:
+		return membership.NewElvanto(membership.ElvantoConfig{
+			APIKey:         cfg.Elvanto.APIKey,
+			DefaultGroupID: cfg.Elvanto.GroupID,
+		}, logger), nil
+	case membership.ProviderCSV:
+		return membership.NewCSV(func(ctx context.Context) ([]byte, error) {
+			upload, err := uploads.Latest(ctx)
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, fmt.Errorf("%w: no member list uploaded yet", membership.ErrNotConfigured)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return []byte(upload.Content), nil
+		}), nil
+	case membership.ProviderFake:
+		logger.Warn("membership provider is fake, serving sample members")
+		return membership.NewFake(membership.SampleMembers()...), nil
+	default:
+		return nil, fmt.Errorf("unknown MEMBERSHIP_PROVIDER %q", cfg.Membership.Provider)
+	}
+}
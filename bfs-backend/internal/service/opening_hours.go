@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/tz"
+)
+
+var (
+	ErrOpeningHoursInvalid = errors.New("opening_hours_invalid")
+	ErrOpenUntilInPast     = errors.New("open_until_in_past")
+)
+
+// openingHoursHorizonDays bounds the search for the next opening shown to
+// customers while the system is closed.
+const openingHoursHorizonDays = 60
+
+const openingHoursCacheTTL = 5 * time.Second
+
+// Why the system is open or closed right now.
+const (
+	// SystemSourceManual: the manual switch decides, either because it is
+	// off or because no opening hours are in effect.
+	SystemSourceManual = "manual"
+	// SystemSourceOverride: opened manually outside the opening hours.
+	SystemSourceOverride = "override"
+	// SystemSourceSchedule: the opening hours decide.
+	SystemSourceSchedule = "schedule"
+)
+
+// OpeningWindow is a recurring daily opening window in Europe/Zurich time.
+type OpeningWindow struct {
+	Label       string
+	Weekdays    schedule.Weekdays
+	StartMinute int
+	EndMinute   int
+}
+
+// OpeningException replaces the weekly windows on one date ("2006-01-02").
+// No windows means closed all day; Weekdays of the windows are ignored.
+type OpeningException struct {
+	Date    string
+	Label   string
+	Windows []OpeningWindow
+}
+
+// OpeningHours is the schedule that opens and closes the system while
+// Enabled. ClosedMessage is shown to customers while closed.
+type OpeningHours struct {
+	Enabled       bool
+	ClosedMessage string
+	Windows       []OpeningWindow
+	Exceptions    []OpeningException
+}
+
+// SystemStatus says whether the system is open now, why, and when that
+// changes. OpensAt is set while closed by the opening hours, ClosesAt while
+// open by them or by the override.
+type SystemStatus struct {
+	Open          bool
+	Source        string
+	ClosedMessage string
+	OpensAt       *time.Time
+	ClosesAt      *time.Time
+	OpenUntil     *time.Time
+}
+
+type OpeningHoursService interface {
+	GetOpeningHours(ctx context.Context) (*OpeningHours, error)
+	// SetOpeningHours replaces the windows and exceptions. Exceptions before
+	// today are dropped.
+	SetOpeningHours(ctx context.Context, in OpeningHours) (*OpeningHours, error)
+	// SetOpenUntil opens the system until the given time regardless of the
+	// opening hours; nil ends the override. The manual switch still wins.
+	SetOpenUntil(ctx context.Context, until *time.Time) error
+	Status(ctx context.Context) (*SystemStatus, error)
+	IsOpen(ctx context.Context) (bool, error)
+}
+
+// openingHoursState is everything Status needs, cached briefly because
+// RequireEnabled and the public status endpoint ask on every request.
+type openingHoursState struct {
+	settings  *ent.Settings
+	calendar  schedule.Calendar
+	expiresAt time.Time
+}
+
+type openingHoursService struct {
+	hours    repository.OpeningHoursRepository
+	settings repository.SettingsRepository
+	client   *ent.Client
+	cache    atomic.Pointer[openingHoursState]
+}
+
+func NewOpeningHoursService(
+	hours repository.OpeningHoursRepository,
+	settings repository.SettingsRepository,
+	client *ent.Client,
+) OpeningHoursService {
+	return &openingHoursService{hours: hours, settings: settings, client: client}
+}
+
+func (s *openingHoursService) GetOpeningHours(ctx context.Context) (*OpeningHours, error) {
+	settingsData, err := s.settings.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.hours.ListWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := s.hours.ListExceptions(ctx, schedule.DateKey(time.Now(), tz.Zurich()))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &OpeningHours{
+		Enabled:       settingsData.OpeningHoursEnabled,
+		ClosedMessage: settingsData.ClosedMessage,
+		Windows:       make([]OpeningWindow, 0, len(windows)),
+		Exceptions:    []OpeningException{},
+	}
+	for _, w := range windows {
+		out.Windows = append(out.Windows, OpeningWindow{
+			Label:       w.Label,
+			Weekdays:    schedule.Weekdays(w.Weekdays),
+			StartMinute: w.StartMinute,
+			EndMinute:   w.EndMinute,
+		})
+	}
+	for _, e := range exceptions {
+		if n := len(out.Exceptions); n == 0 || out.Exceptions[n-1].Date != e.Date {
+			out.Exceptions = append(out.Exceptions, OpeningException{Date: e.Date, Label: e.Label, Windows: []OpeningWindow{}})
+		}
+		if e.StartMinute == nil || e.EndMinute == nil {
+			continue
+		}
+		last := &out.Exceptions[len(out.Exceptions)-1]
+		last.Windows = append(last.Windows, OpeningWindow{
+			Weekdays:    schedule.AllWeekdays,
+			StartMinute: *e.StartMinute,
+			EndMinute:   *e.EndMinute,
+		})
+	}
+	return out, nil
+}
+
+func (s *openingHoursService) SetOpeningHours(ctx context.Context, in OpeningHours) (*OpeningHours, error) {
+	in.ClosedMessage = strings.TrimSpace(in.ClosedMessage)
+	if utf8.RuneCountInString(in.ClosedMessage) > 500 {
+		return nil, fmt.Errorf("%w: closed message is longer than 500 characters", ErrOpeningHoursInvalid)
+	}
+
+	windows := make([]repository.OpeningHoursWindowInput, 0, len(in.Windows))
+	for i, w := range in.Windows {
+		if err := validateOpeningWindow(w, w.Weekdays); err != nil {
+			return nil, fmt.Errorf("%w: window %d: %w", ErrOpeningHoursInvalid, i+1, err)
+		}
+		windows = append(windows, repository.OpeningHoursWindowInput{
+			Label:       w.Label,
+			Weekdays:    int(w.Weekdays),
+			StartMinute: w.StartMinute,
+			EndMinute:   w.EndMinute,
+		})
+	}
+
+	today := schedule.DateKey(time.Now(), tz.Zurich())
+	seen := make(map[string]bool, len(in.Exceptions))
+	var exceptions []repository.OpeningHoursExceptionInput
+	for _, e := range in.Exceptions {
+		if _, err := time.Parse(time.DateOnly, e.Date); err != nil {
+			return nil, fmt.Errorf("%w: exception date %q must be YYYY-MM-DD", ErrOpeningHoursInvalid, e.Date)
+		}
+		if seen[e.Date] {
+			return nil, fmt.Errorf("%w: exception date %s is listed twice", ErrOpeningHoursInvalid, e.Date)
+		}
+		seen[e.Date] = true
+		if utf8.RuneCountInString(e.Label) > 50 {
+			return nil, fmt.Errorf("%w: exception %s: label is longer than 50 characters", ErrOpeningHoursInvalid, e.Date)
+		}
+		if e.Date < today {
+			continue
+		}
+		if len(e.Windows) == 0 {
+			exceptions = append(exceptions, repository.OpeningHoursExceptionInput{Date: e.Date, Label: e.Label})
+			continue
+		}
+		for _, w := range e.Windows {
+			if err := validateOpeningWindow(w, schedule.AllWeekdays); err != nil {
+				return nil, fmt.Errorf("%w: exception %s: %w", ErrOpeningHoursInvalid, e.Date, err)
+			}
+			exceptions = append(exceptions, repository.OpeningHoursExceptionInput{
+				Date:        e.Date,
+				Label:       e.Label,
+				StartMinute: &w.StartMinute,
+				EndMinute:   &w.EndMinute,
+			})
+		}
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	if err := s.settings.SetOpeningHours(txCtx, in.Enabled, in.ClosedMessage); err != nil {
+		return nil, err
+	}
+	if err := s.hours.Replace(txCtx, windows, exceptions); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	s.cache.Store(nil)
+	return s.GetOpeningHours(ctx)
+}
+
+func validateOpeningWindow(w OpeningWindow, weekdays schedule.Weekdays) error {
+	if err := (schedule.Window{Weekdays: weekdays, Start: w.StartMinute, End: w.EndMinute}).Validate(); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(w.Label) > 50 {
+		return errors.New("label is longer than 50 characters")
+	}
+	return nil
+}
+
+func (s *openingHoursService) SetOpenUntil(ctx context.Context, until *time.Time) error {
+	if until != nil && !until.After(time.Now()) {
+		return ErrOpenUntilInPast
+	}
+	if err := s.settings.SetSystemOpenUntil(ctx, until); err != nil {
+		return err
+	}
+	s.cache.Store(nil)
+	return nil
+}
+
+func (s *openingHoursService) IsOpen(ctx context.Context) (bool, error) {
+	status, err := s.Status(ctx)
+	if err != nil {
+		return true, err
+	}
+	return status.Open, nil
+}
+
+// Status evaluates, in order: the manual switch (off always closes), the
+// manual override opening outside the opening hours, then the opening hours.
+// Without opening hours the switch alone decides, as it always has.
+func (s *openingHoursService) Status(ctx context.Context) (*SystemStatus, error) {
+	state, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	settingsData := state.settings
+	status := &SystemStatus{
+		Source:        SystemSourceManual,
+		ClosedMessage: settingsData.ClosedMessage,
+	}
+	if settingsData.SystemOpenUntil != nil && settingsData.SystemOpenUntil.After(now) {
+		status.OpenUntil = settingsData.SystemOpenUntil
+	}
+
+	switch {
+	case !settingsData.SystemEnabled:
+		status.Open = false
+	case status.OpenUntil != nil:
+		status.Open = true
+		status.Source = SystemSourceOverride
+		status.ClosesAt = status.OpenUntil
+	case settingsData.OpeningHoursEnabled:
+		status.Source = SystemSourceSchedule
+		loc := tz.Zurich()
+		if occ, ok := state.calendar.Active(now, loc); ok {
+			status.Open = true
+			status.ClosesAt = &occ.End
+		} else if occ, ok := state.calendar.Next(now, loc, openingHoursHorizonDays); ok {
+			status.OpensAt = &occ.Start
+		}
+	default:
+		status.Open = true
+	}
+	return status, nil
+}
+
+func (s *openingHoursService) load(ctx context.Context) (*openingHoursState, error) {
+	if cached := s.cache.Load(); cached != nil && time.Now().Before(cached.expiresAt) {
+		return cached, nil
+	}
+	settingsData, err := s.settings.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state := &openingHoursState{settings: settingsData, expiresAt: time.Now().Add(openingHoursCacheTTL)}
+	if settingsData.OpeningHoursEnabled {
+		if state.calendar, err = s.loadCalendar(ctx); err != nil {
+			return nil, err
+		}
+	}
+	s.cache.Store(state)
+	return state, nil
+}
+
+func (s *openingHoursService) loadCalendar(ctx context.Context) (schedule.Calendar, error) {
+	windows, err := s.hours.ListWindows(ctx)
+	if err != nil {
+		return schedule.Calendar{}, err
+	}
+	exceptions, err := s.hours.ListExceptions(ctx, schedule.DateKey(time.Now(), tz.Zurich()))
+	if err != nil {
+		return schedule.Calendar{}, err
+	}
+
+	cal := schedule.Calendar{
+		Weekly:     make([]schedule.Window, 0, len(windows)),
+		Exceptions: make(map[string][]schedule.Window),
+	}
+	for _, w := range windows {
+		cal.Weekly = append(cal.Weekly, schedule.Window{
+			Weekdays: schedule.Weekdays(w.Weekdays),
+			Start:    w.StartMinute,
+			End:      w.EndMinute,
+		})
+	}
+	for _, e := range exceptions {
+		day := cal.Exceptions[e.Date]
+		if e.StartMinute != nil && e.EndMinute != nil {
+			day = append(day, schedule.Window{Start: *e.StartMinute, End: *e.EndMinute})
+		}
+		cal.Exceptions[e.Date] = day
+	}
+	return cal, nil
+}
@@ -25,15 +25,17 @@ type OrderService interface {
 	ListAdmin(ctx context.Context, params OrderListParams) ([]*ent.Order, int64, error)
 	// UpdateStatus updates an order's status.
 	UpdateStatus(ctx context.Context, id string, status order.Status) error
-	// ListEvents returns days with paid orders for dashboard navigation.
-	ListEvents(ctx context.Context) ([]repository.EventDay, error)
+	// ListEventDays returns days with paid orders for dashboard navigation,
+	// optionally only those of one event.
+	ListEventDays(ctx context.Context, eventID *string) ([]repository.EventDay, error)
 }
 
 type OrderListParams struct {
-	Status *order.Status
-	From   *string // RFC3339 timestamp
-	To     *string // RFC3339 timestamp
-	Query  *string
+	Status  *order.Status
+	EventID *string
+	From    *string // RFC3339 timestamp
+	To      *string // RFC3339 timestamp
+	Query   *string
 }
 
 type orderService struct {
@@ -89,7 +91,7 @@ func (s *orderService) ListAdmin(ctx context.Context, params OrderListParams) ([
 		}
 	}
 
-	return s.orderRepo.ListAdmin(ctx, params.Status, from, to, params.Query)
+	return s.orderRepo.ListAdmin(ctx, params.Status, params.EventID, from, to, params.Query)
 }
 
 func (s *orderService) UpdateStatus(ctx context.Context, id string, status order.Status) error {
@@ -119,12 +121,12 @@ func (s *orderService) UpdateStatus(ctx context.Context, id string, status order
 
 	switch status {
 	case order.StatusRefunded:
-		if err := s.restoreInventory(ctx, id, inventoryledger.ReasonRefund); err != nil {
+		if err := s.restoreInventory(ctx, ord, inventoryledger.ReasonRefund); err != nil {
 			trace.Err(ctx, fmt.Errorf("inventory restore failed: %w", err))
 			return fmt.Errorf("inventory restore failed: %w", err)
 		}
 	case order.StatusCancelled:
-		if err := s.restoreInventory(ctx, id, inventoryledger.ReasonCancellation); err != nil {
+		if err := s.restoreInventory(ctx, ord, inventoryledger.ReasonCancellation); err != nil {
 			trace.Err(ctx, fmt.Errorf("inventory restore failed: %w", err))
 			return fmt.Errorf("inventory restore failed: %w", err)
 		}
@@ -133,9 +135,10 @@ func (s *orderService) UpdateStatus(ctx context.Context, id string, status order
 	return nil
 }
 
-func (s *orderService) restoreInventory(ctx context.Context, orderID string, reason inventoryledger.Reason) error {
+func (s *orderService) restoreInventory(ctx context.Context, ord *ent.Order, reason inventoryledger.Reason) error {
 	ctx, finish := trace.StartSpan(ctx, "service", "order.restore_inventory")
 	defer finish()
+	orderID := ord.ID
 	trace.Data(ctx, "inventory.order_id", orderID)
 	trace.Data(ctx, "inventory.reason", string(reason))
 
@@ -155,10 +158,12 @@ func (s *orderService) restoreInventory(ctx context.Context, orderID string, rea
 		}
 		entries = append(entries, repository.InventoryLedgerCreateParams{
 			ProductID:   line.ProductID,
+			VariantID:   line.VariantID,
 			Delta:       line.Quantity,
 			Reason:      reason,
 			OrderID:     &orderID,
 			OrderLineID: &line.ID,
+			EventID:     ord.EventID,
 		})
 	}
 
@@ -172,7 +177,13 @@ func (s *orderService) restoreInventory(ctx context.Context, orderID string, rea
 }
 
 func (s *orderService) publishInventoryUpdates(ctx context.Context, entries []repository.InventoryLedgerCreateParams) {
-	if s.inventoryHub == nil {
+	publishStockUpdates(ctx, s.inventoryRepo, s.inventoryHub, entries)
+}
+
+// publishStockUpdates broadcasts the new stock of every product touched by
+// entries, which must already be written to the ledger.
+func publishStockUpdates(ctx context.Context, repo repository.InventoryLedgerRepository, hub *inventory.Hub, entries []repository.InventoryLedgerCreateParams) {
+	if hub == nil {
 		return
 	}
 	productIDs := make([]string, 0, len(entries))
@@ -183,19 +194,55 @@ func (s *orderService) publishInventoryUpdates(ctx context.Context, entries []re
 		}
 		deltaByProduct[entry.ProductID] += entry.Delta
 	}
-	stocks, err := s.inventoryRepo.GetCurrentStockBatch(ctx, productIDs)
+	stocks, err := repo.GetCurrentStockBatch(ctx, productIDs)
 	if err != nil {
 		return
 	}
 	now := time.Now()
 	for _, productID := range productIDs {
-		s.inventoryHub.Publish(inventory.Update{
+		hub.Publish(inventory.Update{
 			ProductID: productID,
 			NewStock:  stocks[productID],
 			Delta:     deltaByProduct[productID],
 			Timestamp: now,
 		})
 	}
+	publishVariantStockUpdates(ctx, repo, hub, entries, now)
+}
+
+// publishVariantStockUpdates publishes the new stock of each variant touched by
+// entries, after the product-level updates.
+func publishVariantStockUpdates(ctx context.Context, repo repository.InventoryLedgerRepository, hub *inventory.Hub, entries []repository.InventoryLedgerCreateParams, now time.Time) {
+	var variantIDs []string
+	deltaByVariant := make(map[string]int)
+	productByVariant := make(map[string]string)
+	for _, entry := range entries {
+		if entry.VariantID == nil {
+			continue
+		}
+		id := *entry.VariantID
+		if _, seen := deltaByVariant[id]; !seen {
+			variantIDs = append(variantIDs, id)
+		}
+		deltaByVariant[id] += entry.Delta
+		productByVariant[id] = entry.ProductID
+	}
+	if len(variantIDs) == 0 {
+		return
+	}
+	stocks, err := repo.GetVariantStockBatch(ctx, variantIDs)
+	if err != nil {
+		return
+	}
+	for _, id := range variantIDs {
+		hub.Publish(inventory.Update{
+			ProductID: productByVariant[id],
+			VariantID: &id,
+			NewStock:  stocks[id],
+			Delta:     deltaByVariant[id],
+			Timestamp: now,
+		})
+	}
 }
 
 func isValidStatusTransition(from, to order.Status) bool {
@@ -228,6 +275,6 @@ func isValidStatusTransition(from, to order.Status) bool {
 	return false
 }
 
-func (s *orderService) ListEvents(ctx context.Context) ([]repository.EventDay, error) {
-	return s.orderRepo.GetEventDays(ctx)
+func (s *orderService) ListEventDays(ctx context.Context, eventID *string) ([]repository.EventDay, error) {
+	return s.orderRepo.GetEventDays(ctx, eventID)
 }
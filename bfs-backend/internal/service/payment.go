@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"backend/internal/allergen"
 	"backend/internal/config"
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/inventoryledger"
@@ -13,10 +14,12 @@ import (
 	"backend/internal/generated/ent/orderline"
 	"backend/internal/generated/ent/orderpayment"
 	"backend/internal/generated/ent/product"
+	"backend/internal/i18n"
 	nanoid "backend/internal/id"
 	"backend/internal/inventory"
 	"backend/internal/payrexx"
 	"backend/internal/repository"
+	"backend/internal/stationqueue"
 	"backend/internal/trace"
 
 	"go.uber.org/zap"
@@ -52,11 +55,16 @@ type CreateCheckoutInput struct {
 	CustomerEmail *string `json:"customerEmail,omitempty"`
 	// Origin is the order origin (shop or pos). Defaults to shop if empty.
 	Origin order.Origin `json:"-"`
+	// Locale the customer ordered in; the receipt is sent in it. Empty means
+	// German.
+	Locale i18n.Locale `json:"-"`
 }
 
 type CheckoutItemInput struct {
 	ProductID string `json:"productId"`
-	Quantity  int    `json:"quantity"`
+	// VariantID is required for products with active variants.
+	VariantID *string `json:"variantId,omitempty"`
+	Quantity  int     `json:"quantity"`
 	// Configuration maps slotID -> selected productID for menu items.
 	Configuration map[string]string `json:"configuration,omitempty"`
 }
@@ -77,9 +85,12 @@ type paymentService struct {
 	orderLineRepo    repository.OrderLineRepository
 	orderPaymentRepo repository.OrderPaymentRepository
 	products         ProductService
+	events           EventService
+	priceRules       PriceRuleService
 	menuSlotRepo     repository.MenuSlotRepository
 	inventoryRepo    repository.InventoryLedgerRepository
 	inventoryHub     *inventory.Hub
+	queueHub         *stationqueue.Hub
 	emailService     EmailService
 	logger           *zap.Logger
 }
@@ -90,9 +101,12 @@ func NewPaymentService(
 	orderLineRepo repository.OrderLineRepository,
 	orderPaymentRepo repository.OrderPaymentRepository,
 	products ProductService,
+	events EventService,
+	priceRules PriceRuleService,
 	menuSlotRepo repository.MenuSlotRepository,
 	inventoryRepo repository.InventoryLedgerRepository,
 	inventoryHub *inventory.Hub,
+	queueHub *stationqueue.Hub,
 	emailService EmailService,
 	logger *zap.Logger,
 ) PaymentService {
@@ -107,9 +121,12 @@ func NewPaymentService(
 		orderLineRepo:    orderLineRepo,
 		orderPaymentRepo: orderPaymentRepo,
 		products:         products,
+		events:           events,
+		priceRules:       priceRules,
 		menuSlotRepo:     menuSlotRepo,
 		inventoryRepo:    inventoryRepo,
 		inventoryHub:     inventoryHub,
+		queueHub:         queueHub,
 		emailService:     emailService,
 		logger:           logger,
 	}
@@ -133,13 +150,20 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 		return nil, fmt.Errorf("no items")
 	}
 
-	// Collect all product IDs
+	// Collect all product and variant IDs
 	productIDSet := make(map[string]struct{})
+	var variantIDs []string
 	for _, it := range in.Items {
 		if !nanoid.Valid(it.ProductID) {
 			return nil, fmt.Errorf("invalid productId: %s", it.ProductID)
 		}
 		productIDSet[it.ProductID] = struct{}{}
+		if it.VariantID != nil && *it.VariantID != "" {
+			if !nanoid.Valid(*it.VariantID) {
+				return nil, fmt.Errorf("invalid variantId: %s", *it.VariantID)
+			}
+			variantIDs = append(variantIDs, *it.VariantID)
+		}
 		// Also collect configured child products
 		for _, childID := range it.Configuration {
 			if childID == "" {
@@ -161,6 +185,10 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 		products       []*ent.Product
 		slots          []*ent.MenuSlot
 		preloadedStock map[string]int
+		variantStock   map[string]int
+		catalog        *EventCatalog
+		rules          *PriceRules
+		avail          *CatalogAvailability
 	)
 	g, gctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
@@ -187,6 +215,38 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 		}
 		return nil
 	})
+	g.Go(func() error {
+		var err error
+		variantStock, err = s.inventoryRepo.GetVariantStockBatch(gctx, variantIDs)
+		if err != nil {
+			return fmt.Errorf("check inventory: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		catalog, err = currentEventCatalog(gctx, s.events)
+		if err != nil {
+			return fmt.Errorf("load event: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		rules, err = currentPriceRules(gctx, s.priceRules)
+		if err != nil {
+			return fmt.Errorf("load price rules: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		avail, err = s.products.Availability(gctx)
+		if err != nil {
+			return fmt.Errorf("load availability: %w", err)
+		}
+		return nil
+	})
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
@@ -207,18 +267,51 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 		allowedBySlot[slot.ID] = allowed
 	}
 
-	// Calculate total and validate
+	// Calculate total and validate. Prices are evaluated once so the total
+	// and the order lines agree.
 	var totalCents int64
-	for _, it := range in.Items {
+	variants := make([]*ent.ProductVariant, len(in.Items))
+	prices := make([]itemPricing, len(in.Items))
+	for i, it := range in.Items {
 		pid := it.ProductID
 		p, ok := productMap[pid]
 		if !ok {
 			return nil, fmt.Errorf("unknown product: %s", it.ProductID)
 		}
-		totalCents += p.PriceCents * int64(it.Quantity)
+		if !catalog.Available(pid) {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotAtEvent, p.Name)
+		}
+		if !avail.Available(p) {
+			return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, p.Name)
+		}
+		v, err := resolveVariant(p, it.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		variants[i] = v
+		for _, childID := range it.Configuration {
+			child, ok := productMap[childID]
+			if !ok {
+				continue
+			}
+			if !catalog.Available(childID) {
+				return nil, fmt.Errorf("%w: %s", ErrProductNotAtEvent, child.Name)
+			}
+			if !avail.Available(child) {
+				return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, child.Name)
+			}
+			// A menu option has no way to name a variant, and the stock of
+			// a product with variants is kept per variant.
+			if len(activeVariants(child)) > 0 {
+				return nil, fmt.Errorf("%w: %s has variants and cannot be picked in a menu", ErrVariantInvalid, child.Name)
+			}
+		}
+		price, list, rule := itemPrice(catalog, rules, p, v)
+		prices[i] = itemPricing{unit: price, list: list, rule: rule}
+		totalCents += price * int64(it.Quantity)
 
 		// TWINT limit: 5000 CHF per transaction
-		if p.PriceCents*int64(it.Quantity) > 500000 {
+		if price*int64(it.Quantity) > 500000 {
 			return nil, fmt.Errorf("item exceeds TWINT max: %s", p.Name)
 		}
 	}
@@ -228,10 +321,13 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 
 	// Validate inventory availability
 	requiredQuantities := make(map[string]int)
-	for _, it := range in.Items {
+	requiredByVariant := make(map[string]int)
+	for i, it := range in.Items {
 		pid := it.ProductID
 		p := productMap[pid]
-		if p.Type == product.TypeSimple {
+		if v := variants[i]; v != nil {
+			requiredByVariant[v.ID] += it.Quantity
+		} else if p.Type == product.TypeSimple {
 			requiredQuantities[pid] += it.Quantity
 		}
 		for _, childIDStr := range it.Configuration {
@@ -253,6 +349,15 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 			return nil, fmt.Errorf("insufficient inventory for %s: requested %d, available %d", pName, required, available)
 		}
 	}
+	for i, v := range variants {
+		if v == nil {
+			continue
+		}
+		if required, available := requiredByVariant[v.ID], variantStock[v.ID]; available < required {
+			name := lineTitle(productMap[in.Items[i].ProductID].Name, &v.Name)
+			return nil, fmt.Errorf("insufficient inventory for %s: requested %d, available %d", name, required, available)
+		}
+	}
 
 	origin := in.Origin
 	if origin == "" {
@@ -260,7 +365,12 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 	}
 
 	// Create the order
-	ord, err := s.orderRepo.Create(ctx, totalCents, order.StatusPending, origin, userID, in.CustomerEmail, attemptID, nil, nil)
+	var locale *string
+	if in.Locale != "" {
+		l := string(in.Locale)
+		locale = &l
+	}
+	ord, err := s.orderRepo.Create(ctx, totalCents, order.StatusPending, origin, catalog.EventID(), userID, in.CustomerEmail, locale, attemptID, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create order: %w", err)
 	}
@@ -269,9 +379,10 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 	var orderLines []repository.OrderLineCreateParams
 	var inventoryEntries []repository.InventoryLedgerCreateParams
 
-	for _, it := range in.Items {
+	for i, it := range in.Items {
 		pid := it.ProductID
 		p := productMap[pid]
+		v := variants[i]
 
 		// Determine parent line type
 		lt := orderline.LineTypeSimple
@@ -287,17 +398,33 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 			ProductID:      p.ID,
 			Title:          p.Name,
 			Quantity:       it.Quantity,
-			UnitPriceCents: p.PriceCents,
+			UnitPriceCents: prices[i].unit,
+			ListPriceCents: prices[i].list,
+		}
+		if rule := prices[i].rule; rule != nil {
+			parentLine.PriceRuleID = &rule.ID
 		}
+		var variantID *string
+		if v != nil {
+			variantID = &v.ID
+			parentLine.VariantID = variantID
+			parentLine.VariantName = &v.Name
+		}
+		// A menu line carries its own allergens and, below, those of the
+		// options picked, so stations and receipts need not look them up.
+		parentIndex := len(orderLines)
+		parentLine.Allergens = p.Allergens
 		orderLines = append(orderLines, parentLine)
 
 		// Reserve inventory for simple products
 		if p.Type == product.TypeSimple && it.Quantity > 0 {
 			inventoryEntries = append(inventoryEntries, repository.InventoryLedgerCreateParams{
 				ProductID: p.ID,
+				VariantID: variantID,
 				Delta:     -it.Quantity,
 				Reason:    inventoryledger.ReasonSale,
 				OrderID:   &ord.ID,
+				EventID:   ord.EventID,
 			})
 		}
 
@@ -338,8 +465,10 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 					ParentLineID:   &parentLineID,
 					MenuSlotID:     &slotID,
 					MenuSlotName:   &slotName,
+					Allergens:      childProd.Allergens,
 				}
 				orderLines = append(orderLines, childLine)
+				orderLines[parentIndex].Allergens = allergen.Union(orderLines[parentIndex].Allergens, childProd.Allergens)
 
 				// Reserve inventory for component
 				if it.Quantity > 0 {
@@ -348,6 +477,7 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 						Delta:     -it.Quantity,
 						Reason:    inventoryledger.ReasonSale,
 						OrderID:   &ord.ID,
+						EventID:   ord.EventID,
 					})
 				}
 			}
@@ -372,7 +502,7 @@ func (s *paymentService) PrepareAndCreateOrder(ctx context.Context, in CreateChe
 	for _, line := range orderLines {
 		if line.ParentLineID == nil && line.UnitPriceCents > 0 && line.Quantity > 0 {
 			lineItems = append(lineItems, payrexx.InvoiceItem{
-				Name:     line.Title,
+				Name:     lineTitle(line.Title, line.VariantName),
 				Quantity: line.Quantity,
 				Amount:   int(line.UnitPriceCents),
 			})
@@ -462,6 +592,7 @@ func (s *paymentService) MarkOrderPaidByPayrexx(ctx context.Context, orderID str
 	if _, err = s.orderRepo.Update(ctx, ord.ID, ord.TotalCents, order.StatusPaid, ord.Origin, ord.CustomerID, ce, ord.PaymentAttemptID, &gatewayID, &transactionID); err != nil {
 		return err
 	}
+	publishQueueEvent(s.queueHub, stationqueue.EventOrderPaid, ord.ID, nil)
 
 	email := ""
 	if ce != nil {
@@ -495,6 +626,7 @@ func (s *paymentService) MarkOrderPaidDev(ctx context.Context, orderID string) e
 	if err := s.orderRepo.UpdateStatus(ctx, orderID, order.StatusPaid); err != nil {
 		return err
 	}
+	publishQueueEvent(s.queueHub, stationqueue.EventOrderPaid, orderID, nil)
 
 	email := ""
 	if ord.ContactEmail != nil {
@@ -551,9 +683,11 @@ func (s *paymentService) CleanupPendingOrderByID(ctx context.Context, orderID st
 			if line.LineType == orderline.LineTypeSimple || line.LineType == orderline.LineTypeComponent {
 				releaseEntries = append(releaseEntries, repository.InventoryLedgerCreateParams{
 					ProductID: line.ProductID,
+					VariantID: line.VariantID,
 					Delta:     line.Quantity, // Positive to add back
 					Reason:    inventoryledger.ReasonCorrection,
 					OrderID:   &orderID,
+					EventID:   ord.EventID,
 				})
 			}
 		}
@@ -589,12 +723,18 @@ func (s *paymentService) sendReceipt(orderID string, to string, totalCents int64
 		return
 	}
 
+	locale := i18n.Default
+	if ord, err := s.orderRepo.GetByID(ctx, orderID); err == nil {
+		locale = i18n.Or(ord.Locale)
+	}
+	title := s.receiptTitles(ctx, lines, locale)
+
 	childrenByParent := make(map[string][]ReceiptLineItem)
 	var roots []*ent.OrderLine
 	for _, l := range lines {
 		if l.ParentLineID != nil {
 			childrenByParent[*l.ParentLineID] = append(childrenByParent[*l.ParentLineID], ReceiptLineItem{
-				Title:    l.Title,
+				Title:    title(l),
 				Quantity: l.Quantity,
 				Cents:    l.UnitPriceCents,
 			})
@@ -606,10 +746,11 @@ func (s *paymentService) sendReceipt(orderID string, to string, totalCents int64
 	items := make([]ReceiptLineItem, 0, len(roots))
 	for _, r := range roots {
 		items = append(items, ReceiptLineItem{
-			Title:    r.Title,
-			Quantity: r.Quantity,
-			Cents:    r.UnitPriceCents,
-			Children: childrenByParent[r.ID],
+			Title:     title(r),
+			Quantity:  r.Quantity,
+			Cents:     r.UnitPriceCents,
+			Children:  childrenByParent[r.ID],
+			Allergens: r.Allergens,
 		})
 	}
 
@@ -620,10 +761,11 @@ func (s *paymentService) sendReceipt(orderID string, to string, totalCents int64
 		Brand:      "BlessThun Food",
 		OrderID:    orderID,
 		OrderURL:   orderURL,
-		OrderDate:  formatOrderDate(paidAt),
+		OrderDate:  i18n.FormatDateTime(locale, paidAt),
 		Items:      items,
 		TotalCents: totalCents,
 		Method:     method,
+		Locale:     locale,
 	}
 
 	if err := s.emailService.SendReceiptEmail(ctx, to, data); err != nil {
@@ -631,6 +773,31 @@ func (s *paymentService) sendReceipt(orderID string, to string, totalCents int64
 	}
 }
 
+// receiptTitles returns the title of an order line in locale: the product's
+// translated name when it has one, else the title recorded at checkout.
+func (s *paymentService) receiptTitles(ctx context.Context, lines []*ent.OrderLine, locale i18n.Locale) func(*ent.OrderLine) string {
+	names := make(map[string]string)
+	if locale != i18n.German {
+		ids := make([]string, 0, len(lines))
+		for _, l := range lines {
+			ids = append(ids, l.ProductID)
+		}
+		if products, err := s.products.GetByIDs(ctx, ids); err == nil {
+			for _, p := range products {
+				if name := p.Translations.Name(locale, ""); name != "" {
+					names[p.ID] = name
+				}
+			}
+		}
+	}
+	return func(l *ent.OrderLine) string {
+		if name, ok := names[l.ProductID]; ok {
+			return lineTitle(name, l.VariantName)
+		}
+		return lineTitle(l.Title, l.VariantName)
+	}
+}
+
 func safeStr(p *string) string {
 	if p == nil {
 		return ""
@@ -677,4 +844,5 @@ func (s *paymentService) publishInventoryUpdates(ctx context.Context, entries []
 			Timestamp: now,
 		})
 	}
+	publishVariantStockUpdates(ctx, s.inventoryRepo, s.inventoryHub, entries, now)
 }
@@ -9,7 +9,9 @@ import (
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/device"
 	"backend/internal/generated/ent/order"
+	"backend/internal/i18n"
 	"backend/internal/repository"
+	"backend/internal/stationqueue"
 )
 
 type POSService interface {
@@ -17,43 +19,69 @@ type POSService interface {
 	GetDeviceByToken(ctx context.Context, token string) (*ent.Device, error)
 	GetDeviceByID(ctx context.Context, id string) (*ent.Device, error)
 	// Orders
-	CreateOrder(ctx context.Context, items []POSCheckoutItem, customerEmail *string) (string, error)
-	PayCash(ctx context.Context, orderID string, deviceID *string) error
-	PayCard(ctx context.Context, orderID string, deviceID *string, card *repository.CardMeta) error
-	PayTwint(ctx context.Context, orderID string, deviceID *string) error
+	CreateOrder(ctx context.Context, items []POSCheckoutItem, customerEmail *string, locale i18n.Locale) (string, error)
+	// PayCash, PayCard and PayTwint take an optional Club100 redemption for
+	// the free products in the order; it commits together with the payment.
+	PayCash(ctx context.Context, orderID string, deviceID *string, club100 *Club100RedemptionInput) error
+	PayCard(ctx context.Context, orderID string, deviceID *string, card *repository.CardMeta, club100 *Club100RedemptionInput) error
+	PayTwint(ctx context.Context, orderID string, deviceID *string, club100 *Club100RedemptionInput) error
 	PayGratisGuest(ctx context.Context, orderID string, deviceID *string) error
 	PayGratisVIP(ctx context.Context, orderID string, deviceID *string) error
 	PayGratisStaff(ctx context.Context, orderID string, deviceID *string) error
-	PayGratis100Club(ctx context.Context, orderID string, deviceID *string, elvantoPersonID, elvantoPersonName string, freeQty int) error
+	PayGratis100Club(ctx context.Context, orderID string, deviceID *string, club100 Club100RedemptionInput) error
+	// SetConfig replaces the device's configuration overrides.
+	SetConfig(ctx context.Context, deviceID string, overrides repository.DevicePosConfig) (*ent.Device, error)
+}
+
+// Club100RedemptionInput names the member whose free products are redeemed
+// with a payment.
+type Club100RedemptionInput struct {
+	// ExternalPersonID is the member's ID at the membership provider.
+	ExternalPersonID string
+	PersonName       string
+	FreeQuantity     int
 }
 
 type POSCheckoutItem struct {
 	ProductID     string            `json:"productId"`
+	VariantID     *string           `json:"variantId,omitempty"`
 	Quantity      int               `json:"quantity"`
 	Configuration map[string]string `json:"configuration,omitempty"`
 }
 
 type posService struct {
-	cfg      config.Config
-	devices  repository.DeviceRepository
-	orders   repository.OrderRepository
-	payments PaymentService
-	club100  Club100Service
+	cfg        config.Config
+	client     *ent.Client
+	devices    repository.DeviceRepository
+	categories repository.CategoryRepository
+	orders     repository.OrderRepository
+	payments   PaymentService
+	settings   SettingsService
+	club100    Club100Service
+	queueHub   *stationqueue.Hub
 }
 
 func NewPOSService(
 	cfg config.Config,
 	devices repository.DeviceRepository,
+	categories repository.CategoryRepository,
 	orders repository.OrderRepository,
 	payments PaymentService,
+	settings SettingsService,
 	club100 Club100Service,
+	queueHub *stationqueue.Hub,
+	client *ent.Client,
 ) POSService {
 	return &posService{
-		cfg:      cfg,
-		devices:  devices,
-		orders:   orders,
-		payments: payments,
-		club100:  club100,
+		cfg:        cfg,
+		client:     client,
+		devices:    devices,
+		categories: categories,
+		orders:     orders,
+		payments:   payments,
+		settings:   settings,
+		club100:    club100,
+		queueHub:   queueHub,
 	}
 }
 
@@ -79,7 +107,7 @@ func (s *posService) GetDeviceByID(ctx context.Context, id string) (*ent.Device,
 	return d, nil
 }
 
-func (s *posService) CreateOrder(ctx context.Context, items []POSCheckoutItem, customerEmail *string) (string, error) {
+func (s *posService) CreateOrder(ctx context.Context, items []POSCheckoutItem, customerEmail *string, locale i18n.Locale) (string, error) {
 	if len(items) == 0 {
 		return "", fmt.Errorf("no items")
 	}
@@ -94,6 +122,7 @@ func (s *posService) CreateOrder(ctx context.Context, items []POSCheckoutItem, c
 		Items:         checkoutItems,
 		CustomerEmail: customerEmail,
 		Origin:        order.OriginPos,
+		Locale:        locale,
 	}
 
 	prep, err := s.payments.PrepareAndCreateOrder(ctx, in, nil, nil)
@@ -104,39 +133,65 @@ func (s *posService) CreateOrder(ctx context.Context, items []POSCheckoutItem, c
 	return prep.OrderID, nil
 }
 
-func (s *posService) PayCash(ctx context.Context, orderID string, deviceID *string) error {
-	if orderID == "" {
-		return fmt.Errorf("invalid order id")
-	}
+func (s *posService) PayCash(ctx context.Context, orderID string, deviceID *string, club100 *Club100RedemptionInput) error {
+	return s.pay(ctx, orderID, deviceID, "cash", club100, func(ctx context.Context, ord *ent.Order) error {
+		return s.orders.SetPosPaymentCash(ctx, orderID, deviceID, ord.TotalCents)
+	})
+}
 
-	ord, err := s.orders.GetByID(ctx, orderID)
-	if err != nil {
-		return err
-	}
-	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
-	}
+func (s *posService) PayCard(ctx context.Context, orderID string, deviceID *string, card *repository.CardMeta, club100 *Club100RedemptionInput) error {
+	return s.pay(ctx, orderID, deviceID, "card", club100, func(ctx context.Context, ord *ent.Order) error {
+		return s.orders.SetPosPaymentCard(ctx, orderID, deviceID, ord.TotalCents, card)
+	})
+}
 
-	return s.orders.SetPosPaymentCash(ctx, orderID, deviceID, ord.TotalCents)
+func (s *posService) PayTwint(ctx context.Context, orderID string, deviceID *string, club100 *Club100RedemptionInput) error {
+	return s.pay(ctx, orderID, deviceID, "twint", club100, func(ctx context.Context, ord *ent.Order) error {
+		return s.orders.SetPosPaymentTwint(ctx, orderID, deviceID, ord.TotalCents)
+	})
 }
 
-func (s *posService) PayCard(ctx context.Context, orderID string, deviceID *string, card *repository.CardMeta) error {
+// pay records a POS payment for a pending order once the device may take it
+// with method. With a Club100 redemption both run in one transaction: a
+// redemption over the member's limit fails the payment, and a failed payment
+// books no redemption.
+func (s *posService) pay(ctx context.Context, orderID string, deviceID *string, method string, club100 *Club100RedemptionInput, record func(ctx context.Context, ord *ent.Order) error) error {
 	if orderID == "" {
 		return fmt.Errorf("invalid order id")
 	}
 
-	ord, err := s.orders.GetByID(ctx, orderID)
+	err := repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		ord, err := s.orders.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if ord.Status != order.StatusPending {
+			return repository.ErrOrderNotPending
+		}
+		// Free Club100 products on a paid order still need the device to
+		// allow the Club100 gratis type.
+		methods := []string{method}
+		if club100 != nil && method != "gratis_100club" {
+			methods = append(methods, "gratis_100club")
+		}
+		if err := s.allowPayment(ctx, deviceID, ord, methods...); err != nil {
+			return err
+		}
+		if club100 != nil {
+			if err := s.club100.RecordRedemption(ctx, club100.ExternalPersonID, club100.PersonName, orderID, ord.EventID, club100.FreeQuantity); err != nil {
+				return fmt.Errorf("record redemption: %w", err)
+			}
+		}
+		return record(ctx, ord)
+	})
 	if err != nil {
 		return err
 	}
-	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
-	}
-
-	return s.orders.SetPosPaymentCard(ctx, orderID, deviceID, ord.TotalCents, card)
+	publishQueueEvent(s.queueHub, stationqueue.EventOrderPaid, orderID, nil)
+	return nil
 }
 
-func (s *posService) PayTwint(ctx context.Context, orderID string, deviceID *string) error {
+func (s *posService) PayGratisGuest(ctx context.Context, orderID string, deviceID *string) error {
 	if orderID == "" {
 		return fmt.Errorf("invalid order id")
 	}
@@ -146,26 +201,17 @@ func (s *posService) PayTwint(ctx context.Context, orderID string, deviceID *str
 		return err
 	}
 	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
+		return repository.ErrOrderNotPending
 	}
-
-	return s.orders.SetPosPaymentTwint(ctx, orderID, deviceID, ord.TotalCents)
-}
-
-func (s *posService) PayGratisGuest(ctx context.Context, orderID string, deviceID *string) error {
-	if orderID == "" {
-		return fmt.Errorf("invalid order id")
+	if err := s.allowPayment(ctx, deviceID, ord, "gratis_guest"); err != nil {
+		return err
 	}
 
-	ord, err := s.orders.GetByID(ctx, orderID)
-	if err != nil {
+	if err := s.orders.SetPosPaymentGratisGuest(ctx, orderID, deviceID, ord.TotalCents); err != nil {
 		return err
 	}
-	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
-	}
-
-	return s.orders.SetPosPaymentGratisGuest(ctx, orderID, deviceID, ord.TotalCents)
+	publishQueueEvent(s.queueHub, stationqueue.EventOrderPaid, orderID, nil)
+	return nil
 }
 
 func (s *posService) PayGratisVIP(ctx context.Context, orderID string, deviceID *string) error {
@@ -178,29 +224,20 @@ func (s *posService) PayGratisVIP(ctx context.Context, orderID string, deviceID
 		return err
 	}
 	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
+		return repository.ErrOrderNotPending
 	}
-
-	return s.orders.SetPosPaymentGratisVIP(ctx, orderID, deviceID, ord.TotalCents)
-}
-
-func (s *posService) PayGratisStaff(ctx context.Context, orderID string, deviceID *string) error {
-	if orderID == "" {
-		return fmt.Errorf("invalid order id")
+	if err := s.allowPayment(ctx, deviceID, ord, "gratis_vip"); err != nil {
+		return err
 	}
 
-	ord, err := s.orders.GetByID(ctx, orderID)
-	if err != nil {
+	if err := s.orders.SetPosPaymentGratisVIP(ctx, orderID, deviceID, ord.TotalCents); err != nil {
 		return err
 	}
-	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
-	}
-
-	return s.orders.SetPosPaymentGratisStaff(ctx, orderID, deviceID, ord.TotalCents)
+	publishQueueEvent(s.queueHub, stationqueue.EventOrderPaid, orderID, nil)
+	return nil
 }
 
-func (s *posService) PayGratis100Club(ctx context.Context, orderID string, deviceID *string, elvantoPersonID, elvantoPersonName string, freeQty int) error {
+func (s *posService) PayGratisStaff(ctx context.Context, orderID string, deviceID *string) error {
 	if orderID == "" {
 		return fmt.Errorf("invalid order id")
 	}
@@ -210,16 +247,26 @@ func (s *posService) PayGratis100Club(ctx context.Context, orderID string, devic
 		return err
 	}
 	if ord.Status != order.StatusPending {
-		return fmt.Errorf("not_pending")
+		return repository.ErrOrderNotPending
 	}
-
-	if err := s.club100.ValidateOrderForRedemption(ctx, orderID); err != nil {
+	if err := s.allowPayment(ctx, deviceID, ord, "gratis_staff"); err != nil {
 		return err
 	}
 
-	if err := s.club100.RecordRedemption(ctx, elvantoPersonID, elvantoPersonName, orderID, freeQty); err != nil {
-		return fmt.Errorf("record redemption: %w", err)
+	if err := s.orders.SetPosPaymentGratisStaff(ctx, orderID, deviceID, ord.TotalCents); err != nil {
+		return err
 	}
+	publishQueueEvent(s.queueHub, stationqueue.EventOrderPaid, orderID, nil)
+	return nil
+}
 
-	return s.orders.SetPosPaymentGratis100Club(ctx, orderID, deviceID, ord.TotalCents)
+func (s *posService) PayGratis100Club(ctx context.Context, orderID string, deviceID *string, club100 Club100RedemptionInput) error {
+	return s.pay(ctx, orderID, deviceID, "gratis_100club", &club100, func(ctx context.Context, ord *ent.Order) error {
+		// Fully free orders may only contain Club100 products; failing here
+		// rolls the redemption back too.
+		if err := s.club100.ValidateOrderForRedemption(ctx, orderID); err != nil {
+			return err
+		}
+		return s.orders.SetPosPaymentGratis100Club(ctx, orderID, deviceID, ord.TotalCents)
+	})
 }
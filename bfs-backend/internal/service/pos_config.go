@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/device"
+	"backend/internal/generated/ent/settings"
+	"backend/internal/repository"
+)
+
+// Payment methods and gratis types a POS device can be restricted to. Gratis
+// types are the gratis_* payment methods without prefix.
+var (
+	PosPaymentMethods = []string{"cash", "card", "twint"}
+	PosGratisTypes    = []string{"guest", "vip", "staff", "100club"}
+)
+
+var (
+	ErrPosConfigInvalid        = errors.New("pos_config_invalid")
+	ErrPaymentMethodNotAllowed = errors.New("payment_method_not_allowed")
+	ErrCategoryNotOnDevice     = errors.New("category_not_on_device")
+)
+
+// PosConfig is a POS device's effective configuration: its overrides merged
+// over the global settings.
+type PosConfig struct {
+	PosMode        settings.PosMode
+	PaymentMethods []string
+	GratisTypes    []string
+	// CategoryIDs lists the visible categories; nil shows all.
+	CategoryIDs []string
+	Overrides   repository.DevicePosConfig
+}
+
+// MergePosConfig merges a device's overrides over the global settings.
+func MergePosConfig(global *ent.Settings, d *ent.Device) PosConfig {
+	cfg := PosConfig{
+		PosMode:        global.PosMode,
+		PaymentMethods: PosPaymentMethods,
+		GratisTypes:    PosGratisTypes,
+		CategoryIDs:    d.VisibleCategoryIds,
+		Overrides: repository.DevicePosConfig{
+			PosMode:            d.PosMode,
+			PaymentMethods:     d.PaymentMethods,
+			VisibleCategoryIDs: d.VisibleCategoryIds,
+			GratisTypes:        d.GratisTypes,
+		},
+	}
+	if d.PosMode != nil {
+		cfg.PosMode = settings.PosMode(*d.PosMode)
+	}
+	if d.PaymentMethods != nil {
+		cfg.PaymentMethods = d.PaymentMethods
+	}
+	if d.GratisTypes != nil {
+		cfg.GratisTypes = d.GratisTypes
+	}
+	return cfg
+}
+
+func (s *posService) SetConfig(ctx context.Context, deviceID string, overrides repository.DevicePosConfig) (*ent.Device, error) {
+	d, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Type != device.TypePOS {
+		return nil, fmt.Errorf("%w: device is not a POS", ErrPosConfigInvalid)
+	}
+	if overrides.PosMode != nil {
+		if err := device.PosModeValidator(*overrides.PosMode); err != nil {
+			return nil, fmt.Errorf("%w: unknown POS mode %q", ErrPosConfigInvalid, *overrides.PosMode)
+		}
+		if err := s.settings.CheckJetonsAssigned(ctx, settings.PosMode(*overrides.PosMode)); err != nil {
+			return nil, err
+		}
+	}
+	if overrides.PaymentMethods, err = normalizePosOptions(overrides.PaymentMethods, PosPaymentMethods, "payment method"); err != nil {
+		return nil, err
+	}
+	if overrides.GratisTypes, err = normalizePosOptions(overrides.GratisTypes, PosGratisTypes, "gratis type"); err != nil {
+		return nil, err
+	}
+	if overrides.VisibleCategoryIDs != nil {
+		ids := slices.Compact(slices.Sorted(slices.Values(overrides.VisibleCategoryIDs)))
+		for _, id := range ids {
+			if _, err := s.categories.GetByID(ctx, id); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return nil, fmt.Errorf("%w: category %s not found", ErrPosConfigInvalid, id)
+				}
+				return nil, err
+			}
+		}
+		overrides.VisibleCategoryIDs = ids
+	}
+	return s.devices.SetPosConfig(ctx, d.ID, overrides)
+}
+
+// normalizePosOptions checks values against allowed and returns them in the
+// order of allowed, without duplicates. nil stays nil (inherit).
+func normalizePosOptions(values, allowed []string, what string) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	for _, v := range values {
+		if !slices.Contains(allowed, v) {
+			return nil, fmt.Errorf("%w: unknown %s %q", ErrPosConfigInvalid, what, v)
+		}
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range allowed {
+		if slices.Contains(values, v) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// allowPayment enforces the payment method, gratis type and category
+// restrictions of the paying device for every one of methods. Payments
+// without a device are not restricted.
+func (s *posService) allowPayment(ctx context.Context, deviceID *string, ord *ent.Order, methods ...string) error {
+	if deviceID == nil {
+		return nil
+	}
+	d, err := s.devices.GetByID(ctx, *deviceID)
+	if err != nil {
+		return err
+	}
+	for _, method := range methods {
+		allowed, name := d.PaymentMethods, method
+		if gratis, ok := strings.CutPrefix(method, "gratis_"); ok {
+			allowed, name = d.GratisTypes, gratis
+		}
+		if allowed != nil && !slices.Contains(allowed, name) {
+			return fmt.Errorf("%w: %s", ErrPaymentMethodNotAllowed, method)
+		}
+	}
+	if d.VisibleCategoryIds == nil {
+		return nil
+	}
+	withLines, err := s.orders.GetByIDWithRelations(ctx, ord.ID)
+	if err != nil {
+		return err
+	}
+	for _, line := range withLines.Edges.Lines {
+		// Menu components follow their menu.
+		if line.ParentLineID != nil || line.Edges.Product == nil {
+			continue
+		}
+		if !slices.Contains(d.VisibleCategoryIds, line.Edges.Product.CategoryID) {
+			return fmt.Errorf("%w: %s", ErrCategoryNotOnDevice, line.Title)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/pricerule"
+	nanoid "backend/internal/id"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/tz"
+)
+
+var (
+	ErrPriceRuleNotFound = errors.New("price_rule_not_found")
+	ErrPriceRuleInvalid  = errors.New("price_rule_invalid")
+	// ErrPriceRuleInUse: order lines were priced by the rule, so deleting it
+	// would drop them from its report. Deactivate it instead.
+	ErrPriceRuleInUse = errors.New("price_rule_in_use")
+)
+
+const priceRuleCacheTTL = 5 * time.Second
+
+// PriceRulePatch changes the fields that are set. Setting ProductID scopes
+// the rule to that product instead of its category, and vice versa.
+type PriceRulePatch struct {
+	Name        *string
+	ProductID   *string
+	CategoryID  *string
+	Weekdays    *int
+	StartMinute *int
+	EndMinute   *int
+	Kind        *pricerule.Kind
+	Value       *int64
+	Active      *bool
+}
+
+// PriceRules are the rules in effect at one moment. A nil *PriceRules
+// applies none.
+type PriceRules struct {
+	rules []*ent.PriceRule
+}
+
+// Apply lowers base, the product's price at the event or its variant's
+// price, by the rules for p. Rules do not stack: the one giving the lowest
+// price wins and is returned; nil when none lowers the price.
+func (r *PriceRules) Apply(p *ent.Product, base int64) (int64, *ent.PriceRule) {
+	if r == nil {
+		return base, nil
+	}
+	price := base
+	var applied *ent.PriceRule
+	for _, rule := range r.rules {
+		if !ruleCovers(rule, p) {
+			continue
+		}
+		if d := discountedPrice(rule, base); d < price {
+			price, applied = d, rule
+		}
+	}
+	return price, applied
+}
+
+func ruleCovers(rule *ent.PriceRule, p *ent.Product) bool {
+	if rule.ProductID != nil {
+		return *rule.ProductID == p.ID
+	}
+	return rule.CategoryID != nil && *rule.CategoryID == p.CategoryID
+}
+
+func discountedPrice(rule *ent.PriceRule, base int64) int64 {
+	var price int64
+	switch rule.Kind {
+	case pricerule.KindPercent:
+		// Round the discount down so the customer never pays a fraction of a
+		// cent more than the list price implies.
+		price = base - base*rule.Value/100
+	case pricerule.KindAmount:
+		price = base - rule.Value
+	default:
+		return base
+	}
+	return max(price, 0)
+}
+
+type PriceRuleService interface {
+	// List returns all rules by name.
+	List(ctx context.Context) ([]*ent.PriceRule, error)
+	Get(ctx context.Context, id string) (*ent.PriceRule, error)
+	Create(ctx context.Context, in repository.PriceRuleInput) (*ent.PriceRule, error)
+	Update(ctx context.Context, id string, patch PriceRulePatch) (*ent.PriceRule, error)
+	// Delete removes a rule no order line was priced by yet.
+	Delete(ctx context.Context, id string) error
+	// Usage sums the paid order lines per rule, optionally of one event.
+	Usage(ctx context.Context, eventID *string) ([]repository.PriceRuleUsage, error)
+	// At returns the rules in effect at t. The active rules are cached
+	// briefly because checkout and the product list ask on every request.
+	At(ctx context.Context, t time.Time) (*PriceRules, error)
+}
+
+type priceRuleState struct {
+	rules     []*ent.PriceRule
+	expiresAt time.Time
+}
+
+type priceRuleService struct {
+	rules repository.PriceRuleRepository
+	cache atomic.Pointer[priceRuleState]
+}
+
+func NewPriceRuleService(rules repository.PriceRuleRepository) PriceRuleService {
+	return &priceRuleService{rules: rules}
+}
+
+func (s *priceRuleService) List(ctx context.Context) ([]*ent.PriceRule, error) {
+	return s.rules.List(ctx)
+}
+
+func (s *priceRuleService) Get(ctx context.Context, id string) (*ent.PriceRule, error) {
+	rule, err := s.rules.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrPriceRuleNotFound
+	}
+	return rule, err
+}
+
+func (s *priceRuleService) Create(ctx context.Context, in repository.PriceRuleInput) (*ent.PriceRule, error) {
+	in, err := checkPriceRule(in)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.rules.Create(ctx, in)
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, fmt.Errorf("%w: unknown product or category", ErrPriceRuleInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Store(nil)
+	return created, nil
+}
+
+func (s *priceRuleService) Update(ctx context.Context, id string, patch PriceRulePatch) (*ent.PriceRule, error) {
+	if patch.ProductID != nil && patch.CategoryID != nil {
+		return nil, fmt.Errorf("%w: set either a product or a category", ErrPriceRuleInvalid)
+	}
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	in := repository.PriceRuleInput{
+		Name:        current.Name,
+		ProductID:   current.ProductID,
+		CategoryID:  current.CategoryID,
+		Weekdays:    current.Weekdays,
+		StartMinute: current.StartMinute,
+		EndMinute:   current.EndMinute,
+		Kind:        current.Kind,
+		Value:       current.Value,
+		Active:      current.Active,
+	}
+	if patch.Name != nil {
+		in.Name = *patch.Name
+	}
+	if patch.ProductID != nil {
+		in.ProductID, in.CategoryID = patch.ProductID, nil
+	}
+	if patch.CategoryID != nil {
+		in.CategoryID, in.ProductID = patch.CategoryID, nil
+	}
+	if patch.Weekdays != nil {
+		in.Weekdays = *patch.Weekdays
+	}
+	if patch.StartMinute != nil {
+		in.StartMinute = *patch.StartMinute
+	}
+	if patch.EndMinute != nil {
+		in.EndMinute = *patch.EndMinute
+	}
+	if patch.Kind != nil {
+		in.Kind = *patch.Kind
+	}
+	if patch.Value != nil {
+		in.Value = *patch.Value
+	}
+	if patch.Active != nil {
+		in.Active = *patch.Active
+	}
+	if in, err = checkPriceRule(in); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.rules.Update(ctx, id, in)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, ErrPriceRuleNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return nil, fmt.Errorf("%w: unknown product or category", ErrPriceRuleInvalid)
+	case err != nil:
+		return nil, err
+	}
+	s.cache.Store(nil)
+	return updated, nil
+}
+
+func (s *priceRuleService) Delete(ctx context.Context, id string) error {
+	used, err := s.rules.InUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrPriceRuleInUse
+	}
+	err = s.rules.Delete(ctx, id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrPriceRuleNotFound
+	case errors.Is(err, repository.ErrConflict):
+		return ErrPriceRuleInUse
+	case err != nil:
+		return err
+	}
+	s.cache.Store(nil)
+	return nil
+}
+
+func (s *priceRuleService) Usage(ctx context.Context, eventID *string) ([]repository.PriceRuleUsage, error) {
+	return s.rules.Usage(ctx, eventID)
+}
+
+func (s *priceRuleService) At(ctx context.Context, t time.Time) (*PriceRules, error) {
+	active, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Price rules follow the same local clock as the opening hours.
+	loc := tz.Zurich()
+	out := &PriceRules{}
+	for _, rule := range active {
+		w := schedule.Window{Weekdays: schedule.Weekdays(rule.Weekdays), Start: rule.StartMinute, End: rule.EndMinute}
+		if _, ok := schedule.Active([]schedule.Window{w}, t, loc); ok {
+			out.rules = append(out.rules, rule)
+		}
+	}
+	return out, nil
+}
+
+func (s *priceRuleService) active(ctx context.Context) ([]*ent.PriceRule, error) {
+	if cached := s.cache.Load(); cached != nil && time.Now().Before(cached.expiresAt) {
+		return cached.rules, nil
+	}
+	rules, err := s.rules.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list price rules: %w", err)
+	}
+	s.cache.Store(&priceRuleState{rules: rules, expiresAt: time.Now().Add(priceRuleCacheTTL)})
+	return rules, nil
+}
+
+func checkPriceRule(in repository.PriceRuleInput) (repository.PriceRuleInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > 100 {
+		return in, fmt.Errorf("%w: name must be 1-100 characters", ErrPriceRuleInvalid)
+	}
+	switch {
+	case (in.ProductID == nil) == (in.CategoryID == nil):
+		return in, fmt.Errorf("%w: set either a product or a category", ErrPriceRuleInvalid)
+	case in.ProductID != nil && !nanoid.Valid(*in.ProductID):
+		return in, fmt.Errorf("%w: invalid product id", ErrPriceRuleInvalid)
+	case in.CategoryID != nil && !nanoid.Valid(*in.CategoryID):
+		return in, fmt.Errorf("%w: invalid category id", ErrPriceRuleInvalid)
+	}
+	w := schedule.Window{Weekdays: schedule.Weekdays(in.Weekdays), Start: in.StartMinute, End: in.EndMinute}
+	if in.Weekdays&^int(schedule.AllWeekdays) != 0 || w.Validate() != nil {
+		return in, fmt.Errorf("%w: invalid time window", ErrPriceRuleInvalid)
+	}
+	switch in.Kind {
+	case pricerule.KindPercent:
+		if in.Value < 1 || in.Value > 100 {
+			return in, fmt.Errorf("%w: percent must be 1-100", ErrPriceRuleInvalid)
+		}
+	case pricerule.KindAmount:
+		if in.Value < 1 {
+			return in, fmt.Errorf("%w: amount must be positive", ErrPriceRuleInvalid)
+		}
+	default:
+		return in, fmt.Errorf("%w: kind must be percent or amount", ErrPriceRuleInvalid)
+	}
+	return in, nil
+}
+
+// currentPriceRules returns the rules in effect now. Services built without
+// a price rule service, as in tests, sell at list prices.
+func currentPriceRules(ctx context.Context, rules PriceRuleService) (*PriceRules, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	return rules.At(ctx, time.Now())
+}
+
+// itemPricing is a checkout item's itemPrice.
+type itemPricing struct {
+	unit int64
+	list *int64
+	rule *ent.PriceRule
+}
+
+// itemPrice is the unit price of a checkout item: the variant's price, or the
+// product's price at the event, lowered by the price rules in effect. rule is
+// the rule that lowered it and list the price before; both are nil when no
+// rule applied.
+func itemPrice(catalog *EventCatalog, rules *PriceRules, p *ent.Product, v *ent.ProductVariant) (price int64, list *int64, rule *ent.PriceRule) {
+	base := catalog.PriceCents(p)
+	if v != nil {
+		base = catalog.VariantPriceCents(p, v.PriceCents)
+	}
+	price, rule = rules.Apply(p, base)
+	if rule != nil {
+		list = &base
+	}
+	return price, list, rule
+}
@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"backend/internal/allergen"
 	"backend/internal/auth"
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/inventoryledger"
 	"backend/internal/generated/ent/product"
+	"backend/internal/i18n"
 	nanoid "backend/internal/id"
 	"backend/internal/inventory"
 	"backend/internal/repository"
@@ -21,12 +24,19 @@ type ProductService interface {
 	GetAll(ctx context.Context) ([]*ent.Product, error)
 	GetByCategory(ctx context.Context, categoryID string) ([]*ent.Product, error)
 	GetByIDs(ctx context.Context, ids []string) ([]*ent.Product, error)
-	Create(ctx context.Context, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string) (*ent.Product, error)
-	Update(ctx context.Context, id, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string) (*ent.Product, error)
+	// Create and Update write the product and its details in one
+	// transaction.
+	Create(ctx context.Context, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string, details ProductDetails) (*ent.Product, error)
+	Update(ctx context.Context, id, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string, details ProductDetails) (*ent.Product, error)
 	Delete(ctx context.Context, id string) error
 	CountActiveWithoutJeton(ctx context.Context) (int64, error)
 	CountByJetonIDs(ctx context.Context, ids []string) (map[string]int64, error)
 	UpdateJeton(ctx context.Context, id string, jetonID *string) error
+	UpdateDietary(ctx context.Context, id string, allergens, dietaryTags []string) (*ent.Product, error)
+	UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Product, error)
+	// InvalidateCache drops the cached catalog after changes made without the
+	// service, such as a catalog import.
+	InvalidateCache()
 
 	// Inventory
 	GetStock(ctx context.Context, id string) (int64, error)
@@ -34,12 +44,30 @@ type ProductService interface {
 	AdjustStock(ctx context.Context, id string, delta int64, reason string) error
 	ListInventoryHistory(ctx context.Context, productID string, limit, offset int) ([]*ent.InventoryLedger, error)
 
+	// Variants
+	GetVariant(ctx context.Context, productID, variantID string) (*ent.ProductVariant, error)
+	CreateVariant(ctx context.Context, productID string, in repository.ProductVariantInput) (*ent.ProductVariant, error)
+	UpdateVariant(ctx context.Context, productID, variantID string, in repository.ProductVariantInput) (*ent.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID, variantID string) error
+	GetVariantStockBatch(ctx context.Context, ids []string) (map[string]int, error)
+	AdjustVariantStock(ctx context.Context, productID, variantID string, delta int64, reason string) error
+
+	// Availability
+	// Availability evaluates the availability windows now. It is cached
+	// until the next window boundary.
+	Availability(ctx context.Context) (*CatalogAvailability, error)
+	ProductAvailability(ctx context.Context, productID string) ([]*ent.AvailabilityWindow, error)
+	SetProductAvailability(ctx context.Context, productID string, windows []repository.AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error)
+	CategoryAvailability(ctx context.Context, categoryID string) ([]*ent.AvailabilityWindow, error)
+	SetCategoryAvailability(ctx context.Context, categoryID string, windows []repository.AvailabilityWindowInput) ([]*ent.AvailabilityWindow, error)
+
 	// Menus
 	GetMenus(ctx context.Context) ([]*ent.Product, error)
 
 	// Menu slots
 	CreateMenuSlot(ctx context.Context, menuID string, name string) (*ent.MenuSlot, error)
 	UpdateMenuSlot(ctx context.Context, menuID, slotID string, name string) (*ent.MenuSlot, error)
+	UpdateMenuSlotTranslations(ctx context.Context, menuID, slotID string, translations i18n.Translations) (*ent.MenuSlot, error)
 	DeleteMenuSlot(ctx context.Context, menuID, slotID string) error
 	ReorderMenuSlots(ctx context.Context, menuID string, positions map[string]int) error
 
@@ -48,34 +76,55 @@ type ProductService interface {
 	RemoveSlotOption(ctx context.Context, menuID, slotID, optionProductID string) error
 }
 
+// ProductDetails are saved along with a product's own fields. A nil list or
+// nil translations leave the stored ones unchanged.
+type ProductDetails struct {
+	Allergens    []string
+	DietaryTags  []string
+	Translations i18n.Translations
+}
+
 type productService struct {
+	client             *ent.Client
 	productRepo        *repository.ProductRepository
+	variantRepo        repository.ProductVariantRepository
 	categoryRepo       repository.CategoryRepository
 	menuSlotRepo       repository.MenuSlotRepository
 	menuSlotOptionRepo repository.MenuSlotOptionRepository
 	inventoryRepo      repository.InventoryLedgerRepository
 	jetonRepo          repository.JetonRepository
+	availabilityRepo   repository.AvailabilityRepository
 	inventoryHub       *inventory.Hub
+	events             EventService
 	cache              *catalogCache
+	avail              atomic.Pointer[availabilityState]
 }
 
 func NewProductService(
 	productRepo *repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
 	categoryRepo repository.CategoryRepository,
 	menuSlotRepo repository.MenuSlotRepository,
 	menuSlotOptionRepo repository.MenuSlotOptionRepository,
 	inventoryRepo repository.InventoryLedgerRepository,
 	jetonRepo repository.JetonRepository,
+	availabilityRepo repository.AvailabilityRepository,
 	inventoryHub *inventory.Hub,
+	events EventService,
+	client *ent.Client,
 ) ProductService {
 	return &productService{
+		client:             client,
 		productRepo:        productRepo,
+		variantRepo:        variantRepo,
 		categoryRepo:       categoryRepo,
 		menuSlotRepo:       menuSlotRepo,
 		menuSlotOptionRepo: menuSlotOptionRepo,
 		inventoryRepo:      inventoryRepo,
 		jetonRepo:          jetonRepo,
+		availabilityRepo:   availabilityRepo,
 		inventoryHub:       inventoryHub,
+		events:             events,
 		cache:              newCatalogCache(),
 	}
 }
@@ -146,20 +195,82 @@ func (s *productService) GetByIDs(ctx context.Context, ids []string) ([]*ent.Pro
 	return result, nil
 }
 
-func (s *productService) Create(ctx context.Context, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string) (*ent.Product, error) {
-	created, err := s.productRepo.Create(ctx, categoryID, productType, name, priceCents, isActive, image, description, jetonID)
-	if err == nil {
-		s.cache.invalidate()
+func (s *productService) Create(ctx context.Context, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string, details ProductDetails) (*ent.Product, error) {
+	details, err := details.normalize()
+	if err != nil {
+		return nil, err
 	}
-	return created, err
+	var created *ent.Product
+	err = repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		p, err := s.productRepo.Create(ctx, categoryID, productType, name, priceCents, isActive, image, description, jetonID)
+		if err != nil {
+			return err
+		}
+		created, err = s.saveDetails(ctx, p, details)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.invalidate()
+	return created, nil
 }
 
-func (s *productService) Update(ctx context.Context, id, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string) (*ent.Product, error) {
-	updated, err := s.productRepo.Update(ctx, id, categoryID, productType, name, priceCents, isActive, image, description, jetonID)
-	if err == nil {
-		s.cache.invalidate()
+func (s *productService) Update(ctx context.Context, id, categoryID string, productType product.Type, name string, priceCents int64, isActive bool, image *string, description *string, jetonID *string, details ProductDetails) (*ent.Product, error) {
+	details, err := details.normalize()
+	if err != nil {
+		return nil, err
 	}
-	return updated, err
+	var updated *ent.Product
+	err = repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		p, err := s.productRepo.Update(ctx, id, categoryID, productType, name, priceCents, isActive, image, description, jetonID)
+		if err != nil {
+			return err
+		}
+		updated, err = s.saveDetails(ctx, p, details)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.invalidate()
+	return updated, nil
+}
+
+// normalize checks the details like UpdateDietary and UpdateTranslations do.
+func (d ProductDetails) normalize() (ProductDetails, error) {
+	var err error
+	if d.Allergens != nil {
+		if d.Allergens, err = allergen.NormalizeAllergens(d.Allergens); err != nil {
+			return d, err
+		}
+	}
+	if d.DietaryTags != nil {
+		if d.DietaryTags, err = allergen.NormalizeDietary(d.DietaryTags); err != nil {
+			return d, err
+		}
+	}
+	if d.Translations != nil {
+		if d.Translations, err = d.Translations.Normalize(20); err != nil {
+			return d, err
+		}
+	}
+	return d, nil
+}
+
+func (s *productService) saveDetails(ctx context.Context, p *ent.Product, d ProductDetails) (*ent.Product, error) {
+	var err error
+	if d.Allergens != nil || d.DietaryTags != nil {
+		if p, err = s.productRepo.UpdateDietary(ctx, p.ID, d.Allergens, d.DietaryTags); err != nil {
+			return nil, err
+		}
+	}
+	if d.Translations != nil {
+		if p, err = s.productRepo.UpdateTranslations(ctx, p.ID, d.Translations); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
 }
 
 func (s *productService) Delete(ctx context.Context, id string) error {
@@ -193,6 +304,46 @@ func (s *productService) UpdateJeton(ctx context.Context, id string, jetonID *st
 	return err
 }
 
+// UpdateDietary replaces the allergens and dietary tags of a product; a nil
+// list is left unchanged. Unknown keys are an allergen.ErrUnknownAllergen or
+// allergen.ErrUnknownDietary.
+func (s *productService) UpdateDietary(ctx context.Context, id string, allergens, dietaryTags []string) (*ent.Product, error) {
+	var err error
+	if allergens != nil {
+		if allergens, err = allergen.NormalizeAllergens(allergens); err != nil {
+			return nil, err
+		}
+	}
+	if dietaryTags != nil {
+		if dietaryTags, err = allergen.NormalizeDietary(dietaryTags); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := s.productRepo.UpdateDietary(ctx, id, allergens, dietaryTags)
+	if err == nil {
+		s.cache.invalidate()
+	}
+	return updated, err
+}
+
+// UpdateTranslations replaces the translated names and descriptions of a
+// product.
+func (s *productService) UpdateTranslations(ctx context.Context, id string, translations i18n.Translations) (*ent.Product, error) {
+	translations, err := translations.Normalize(20)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.productRepo.UpdateTranslations(ctx, id, translations)
+	if err == nil {
+		s.cache.invalidate()
+	}
+	return updated, err
+}
+
+func (s *productService) InvalidateCache() {
+	s.cache.invalidate()
+}
+
 // ---------------------------------------------------------------------------
 // Inventory
 // ---------------------------------------------------------------------------
@@ -220,24 +371,16 @@ func (s *productService) ListInventoryHistory(ctx context.Context, productID str
 }
 
 func (s *productService) AdjustStock(ctx context.Context, id string, delta int64, reason string) error {
-	r := inventoryledger.ReasonManualAdjust
-	switch reason {
-	case string(inventoryledger.ReasonOpeningBalance):
-		r = inventoryledger.ReasonOpeningBalance
-	case string(inventoryledger.ReasonSale):
-		r = inventoryledger.ReasonSale
-	case string(inventoryledger.ReasonRefund):
-		r = inventoryledger.ReasonRefund
-	case string(inventoryledger.ReasonCorrection):
-		r = inventoryledger.ReasonCorrection
-	case string(inventoryledger.ReasonManualAdjust):
-		r = inventoryledger.ReasonManualAdjust
-	}
+	r := adjustmentReason(reason)
 	var createdBy *string
 	if uid, ok := auth.GetUserID(ctx); ok {
 		createdBy = &uid
 	}
-	_, err := s.inventoryRepo.Create(ctx, id, int(delta), r, nil, nil, nil, createdBy)
+	catalog, err := currentEventCatalog(ctx, s.events)
+	if err != nil {
+		return err
+	}
+	_, err = s.inventoryRepo.Create(ctx, id, int(delta), r, nil, nil, nil, catalog.EventID(), createdBy)
 	if err != nil {
 		return err
 	}
@@ -255,6 +398,22 @@ func (s *productService) AdjustStock(ctx context.Context, id string, delta int64
 	return nil
 }
 
+// adjustmentReason maps a manual adjustment's reason to its ledger reason,
+// falling back to manual_adjust.
+func adjustmentReason(reason string) inventoryledger.Reason {
+	switch reason {
+	case string(inventoryledger.ReasonOpeningBalance):
+		return inventoryledger.ReasonOpeningBalance
+	case string(inventoryledger.ReasonSale):
+		return inventoryledger.ReasonSale
+	case string(inventoryledger.ReasonRefund):
+		return inventoryledger.ReasonRefund
+	case string(inventoryledger.ReasonCorrection):
+		return inventoryledger.ReasonCorrection
+	}
+	return inventoryledger.ReasonManualAdjust
+}
+
 // ---------------------------------------------------------------------------
 // Menus
 // ---------------------------------------------------------------------------
@@ -301,6 +460,26 @@ func (s *productService) UpdateMenuSlot(ctx context.Context, menuID, slotID stri
 	return updated, err
 }
 
+// UpdateMenuSlotTranslations replaces the translated names of a slot.
+func (s *productService) UpdateMenuSlotTranslations(ctx context.Context, menuID, slotID string, translations i18n.Translations) (*ent.MenuSlot, error) {
+	slot, err := s.menuSlotRepo.GetByID(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+	if slot.MenuProductID != menuID {
+		return nil, repository.ErrNotFound
+	}
+	translations, err = translations.Normalize(20)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.menuSlotRepo.UpdateTranslations(ctx, slotID, translations)
+	if err == nil {
+		s.cache.invalidate()
+	}
+	return updated, err
+}
+
 func (s *productService) DeleteMenuSlot(ctx context.Context, menuID, slotID string) error {
 	// Verify the slot belongs to this menu.
 	slot, err := s.menuSlotRepo.GetByID(ctx, slotID)
@@ -22,7 +22,10 @@ type catalogCache struct {
 	mu      sync.RWMutex
 	entries map[string]catalogEntry
 	gen     atomic.Uint64
-	sf      singleflight.Group
+	// flipAt is when the generation flips on its own, in Unix nanoseconds;
+	// zero for never. Product availability changes at window boundaries.
+	flipAt atomic.Int64
+	sf     singleflight.Group
 }
 
 func newCatalogCache() *catalogCache {
@@ -30,9 +33,27 @@ func newCatalogCache() *catalogCache {
 }
 
 func (c *catalogCache) generation() uint64 {
+	if at := c.flipAt.Load(); at != 0 && time.Now().UnixNano() >= at && c.flipAt.CompareAndSwap(at, 0) {
+		c.invalidate()
+	}
 	return c.gen.Load()
 }
 
+// scheduleFlip makes the generation flip at t, unless it already flips
+// earlier.
+func (c *catalogCache) scheduleFlip(t time.Time) {
+	at := t.UnixNano()
+	for {
+		cur := c.flipAt.Load()
+		if cur != 0 && cur <= at {
+			return
+		}
+		if c.flipAt.CompareAndSwap(cur, at) {
+			return
+		}
+	}
+}
+
 func (c *catalogCache) invalidate() {
 	c.gen.Add(1)
 	c.mu.Lock()
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/auth"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+)
+
+var (
+	ErrVariantInvalid = errors.New("variant_invalid")
+	// ErrVariantRequired: the product has active variants, so checkout must
+	// name one.
+	ErrVariantRequired = errors.New("variant_required")
+)
+
+// GetVariant returns a variant of the product, or ErrNotFound when it belongs
+// to another product.
+func (s *productService) GetVariant(ctx context.Context, productID, variantID string) (*ent.ProductVariant, error) {
+	v, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if v.ProductID != productID {
+		return nil, repository.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *productService) CreateVariant(ctx context.Context, productID string, in repository.ProductVariantInput) (*ent.ProductVariant, error) {
+	p, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Type != product.TypeSimple {
+		return nil, fmt.Errorf("%w: menus cannot have variants", ErrVariantInvalid)
+	}
+	if in, err = normalizeVariantInput(in); err != nil {
+		return nil, err
+	}
+	created, err := s.variantRepo.Create(ctx, productID, in)
+	if err == nil {
+		s.cache.invalidate()
+	}
+	return created, err
+}
+
+func (s *productService) UpdateVariant(ctx context.Context, productID, variantID string, in repository.ProductVariantInput) (*ent.ProductVariant, error) {
+	if _, err := s.GetVariant(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+	in, err := normalizeVariantInput(in)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.variantRepo.Update(ctx, variantID, in)
+	if err == nil {
+		s.cache.invalidate()
+	}
+	return updated, err
+}
+
+// DeleteVariant removes a variant. Variants that were sold are kept for their
+// orders (ErrConflict); deactivate them instead.
+func (s *productService) DeleteVariant(ctx context.Context, productID, variantID string) error {
+	if _, err := s.GetVariant(ctx, productID, variantID); err != nil {
+		return err
+	}
+	err := s.variantRepo.Delete(ctx, variantID)
+	if err == nil {
+		s.cache.invalidate()
+	}
+	return err
+}
+
+func (s *productService) GetVariantStockBatch(ctx context.Context, ids []string) (map[string]int, error) {
+	return s.inventoryRepo.GetVariantStockBatch(ctx, ids)
+}
+
+func (s *productService) AdjustVariantStock(ctx context.Context, productID, variantID string, delta int64, reason string) error {
+	if _, err := s.GetVariant(ctx, productID, variantID); err != nil {
+		return err
+	}
+	var createdBy *string
+	if uid, ok := auth.GetUserID(ctx); ok {
+		createdBy = &uid
+	}
+	catalog, err := currentEventCatalog(ctx, s.events)
+	if err != nil {
+		return err
+	}
+	entries := []repository.InventoryLedgerCreateParams{{
+		ProductID: productID,
+		VariantID: &variantID,
+		Delta:     int(delta),
+		Reason:    adjustmentReason(reason),
+		EventID:   catalog.EventID(),
+		CreatedBy: createdBy,
+	}}
+	if _, err := s.inventoryRepo.CreateMany(ctx, entries); err != nil {
+		return err
+	}
+	publishStockUpdates(ctx, s.inventoryRepo, s.inventoryHub, entries)
+	return nil
+}
+
+func normalizeVariantInput(in repository.ProductVariantInput) (repository.ProductVariantInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > 20 {
+		return in, fmt.Errorf("%w: name must be 1-20 characters", ErrVariantInvalid)
+	}
+	if in.PriceCents < 0 {
+		return in, fmt.Errorf("%w: price must not be negative", ErrVariantInvalid)
+	}
+	return in, nil
+}
+
+// activeVariants returns the product's sellable variants; a product without
+// any is sold as itself.
+func activeVariants(p *ent.Product) []*ent.ProductVariant {
+	var out []*ent.ProductVariant
+	for _, v := range p.Edges.Variants {
+		if v.IsActive {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// resolveVariant picks the variant a checkout item names. Products with
+// active variants require one; other products take none.
+func resolveVariant(p *ent.Product, variantID *string) (*ent.ProductVariant, error) {
+	active := activeVariants(p)
+	if variantID == nil || *variantID == "" {
+		if len(active) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrVariantRequired, p.Name)
+		}
+		return nil, nil
+	}
+	for _, v := range active {
+		if v.ID == *variantID {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown variant for %s", ErrVariantInvalid, p.Name)
+}
+
+// lineTitle is an order line's display title, with the variant's name after
+// the product's.
+func lineTitle(title string, variantName *string) string {
+	if variantName == nil || *variantName == "" {
+		return title
+	}
+	return title + " " + *variantName
+}
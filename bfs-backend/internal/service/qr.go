@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/generated/ent"
+	"backend/internal/qrpayload"
+
+	"go.uber.org/zap"
+)
+
+// campaignQRFallbackTTL bounds campaign QR codes for campaigns without an end
+// date. Rotating the claim token invalidates them earlier.
+const campaignQRFallbackTTL = 365 * 24 * time.Hour
+
+// memberCardQRTTL is the lifetime of a printed Club100 card; lost cards are
+// revoked instead.
+const memberCardQRTTL = 10 * 365 * 24 * time.Hour
+
+var (
+	ErrQRRequired       = errors.New("qr_required")
+	ErrQRInvalid        = errors.New("qr_invalid")
+	ErrQRExpired        = errors.New("qr_expired")
+	ErrQRWrongType      = errors.New("qr_wrong_type")
+	ErrQRLegacyDisabled = errors.New("legacy_qr_disabled")
+	ErrQRSigningOff     = errors.New("qr_signing_disabled")
+)
+
+// QRService issues and verifies the payloads encoded in customer-facing QR
+// codes. Without a signing secret it falls back to the unsigned legacy format.
+type QRService interface {
+	// OrderPayload returns the signed payload for an order QR code, or "" when
+	// signing is not configured (clients then encode the order ID themselves).
+	OrderPayload(o *ent.Order) string
+	// CampaignPayload returns the payload for a shared campaign QR code.
+	CampaignPayload(c *ent.VolunteerCampaign) string
+	// VolunteerPayload returns the payload for a volunteer's personal QR code.
+	// It is a campaign payload carrying the personal token, so stations route
+	// it like the shared QR; it expires with the campaign.
+	VolunteerPayload(v *ent.VolunteerToken, c *ent.VolunteerCampaign) string
+	// MemberCardPayload returns the payload printed on a Club100 member card.
+	// Cards are only issued signed; without a secret it returns
+	// ErrQRSigningOff.
+	MemberCardPayload(card *ent.Club100Card) (string, error)
+	// SigningEnabled reports whether a signing secret is configured.
+	SigningEnabled() bool
+	// Resolve returns the order ID or claim token a station scanned. A signed
+	// payload wins over legacyID; legacyID is only honoured while legacy QR
+	// codes are enabled in the settings.
+	Resolve(ctx context.Context, want qrpayload.Type, signed, legacyID string) (string, error)
+}
+
+type qrService struct {
+	signer     *qrpayload.Signer
+	payloadTTL time.Duration
+	settings   SettingsService
+}
+
+func NewQRService(cfg config.Config, settings SettingsService, logger *zap.Logger) QRService {
+	s := &qrService{payloadTTL: cfg.QR.PayloadTTL, settings: settings}
+	if cfg.QR.SigningSecret != "" {
+		s.signer = qrpayload.NewSigner([]byte(cfg.QR.SigningSecret))
+	} else {
+		logger.Warn("QR_SIGNING_SECRET not set, issuing unsigned legacy QR codes")
+	}
+	return s
+}
+
+func (s *qrService) OrderPayload(o *ent.Order) string {
+	if s.signer == nil {
+		return ""
+	}
+	return s.signer.Sign(qrpayload.TypeOrder, o.ID, o.CreatedAt.Add(s.payloadTTL))
+}
+
+func (s *qrService) CampaignPayload(c *ent.VolunteerCampaign) string {
+	return s.campaignPayload(c.ClaimToken, c)
+}
+
+func (s *qrService) VolunteerPayload(v *ent.VolunteerToken, c *ent.VolunteerCampaign) string {
+	return s.campaignPayload(v.Token, c)
+}
+
+func (s *qrService) SigningEnabled() bool {
+	return s.signer != nil
+}
+
+func (s *qrService) MemberCardPayload(card *ent.Club100Card) (string, error) {
+	if s.signer == nil {
+		return "", ErrQRSigningOff
+	}
+	return s.signer.Sign(qrpayload.TypeMember, card.Token, card.CreatedAt.Add(memberCardQRTTL)), nil
+}
+
+func (s *qrService) campaignPayload(token string, c *ent.VolunteerCampaign) string {
+	if s.signer == nil {
+		return BuildQRPayload(token)
+	}
+	expiresAt := c.CreatedAt.Add(campaignQRFallbackTTL)
+	if c.ValidUntil != nil {
+		expiresAt = *c.ValidUntil
+	}
+	return s.signer.Sign(qrpayload.TypeCampaign, token, expiresAt)
+}
+
+func (s *qrService) Resolve(ctx context.Context, want qrpayload.Type, signed, legacyID string) (string, error) {
+	signed = strings.TrimSpace(signed)
+	if signed != "" {
+		if s.signer == nil {
+			return "", ErrQRInvalid
+		}
+		p, err := s.signer.Verify(signed, time.Now())
+		if err != nil {
+			if errors.Is(err, qrpayload.ErrExpired) {
+				return "", ErrQRExpired
+			}
+			return "", ErrQRInvalid
+		}
+		if p.Type != want {
+			return "", ErrQRWrongType
+		}
+		return p.Subject, nil
+	}
+
+	if legacyID == "" {
+		return "", ErrQRRequired
+	}
+	// Without a secret nothing signed is in circulation, so legacy codes
+	// are the only codes.
+	if s.signer == nil {
+		return legacyID, nil
+	}
+	st, err := s.settings.GetSettings(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !st.AcceptLegacyQr {
+		return "", ErrQRLegacyDisabled
+	}
+	return legacyID, nil
+}
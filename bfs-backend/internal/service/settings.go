@@ -45,30 +45,45 @@ type SettingsService interface {
 	GetSettings(ctx context.Context) (*ent.Settings, error)
 	GetSettingsWithProducts(ctx context.Context) (*ent.Settings, error)
 	SetPosMode(ctx context.Context, mode settings.PosMode) error
+	// CheckJetonsAssigned rejects the jeton modes while active products have
+	// no jeton.
+	CheckJetonsAssigned(ctx context.Context, mode settings.PosMode) error
 	SetClub100Settings(ctx context.Context, freeProductIDs []string, maxRedemptions *int) error
 	IsSystemEnabled(ctx context.Context) (bool, error)
 	SetSystemEnabled(ctx context.Context, enabled bool) error
+	SetAcceptLegacyQR(ctx context.Context, accept bool) error
 	ListJetons(ctx context.Context) ([]*ent.Jeton, error)
 	CreateJeton(ctx context.Context, name, color string) (*ent.Jeton, error)
 	UpdateJeton(ctx context.Context, id string, name, color string) (*ent.Jeton, error)
 	DeleteJeton(ctx context.Context, id string) error
 	SetProductJeton(ctx context.Context, productID string, jetonID *string) error
 	GetFreeProductIDs(ctx context.Context) ([]string, error)
+	// History lists up to limit settings versions older than before (all
+	// when before is 0), newest first, each with its changes against the
+	// previous version.
+	History(ctx context.Context, limit, before int) ([]SettingsVersionEntry, error)
+	// Rollback restores the settings and jetons of version and records the
+	// result as a new version.
+	Rollback(ctx context.Context, version int) (*SettingsVersionEntry, error)
 }
 
 type settingsService struct {
 	settings           repository.SettingsRepository
+	versions           repository.SettingsVersionRepository
 	jetons             repository.JetonRepository
 	products           *repository.ProductRepository
+	client             *ent.Client
 	systemEnabledCache atomic.Pointer[systemEnabledCache]
 }
 
 func NewSettingsService(
 	settings repository.SettingsRepository,
+	versions repository.SettingsVersionRepository,
 	jetons repository.JetonRepository,
 	products *repository.ProductRepository,
+	client *ent.Client,
 ) SettingsService {
-	return &settingsService{settings: settings, jetons: jetons, products: products}
+	return &settingsService{settings: settings, versions: versions, jetons: jetons, products: products, client: client}
 }
 
 var settingsHexPattern = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
@@ -100,14 +115,24 @@ func (s *settingsService) SetPosMode(ctx context.Context, mode settings.PosMode)
 	if mode != settings.PosModeQR_CODE && mode != settings.PosModeJETON && mode != settings.PosModeHYBRID {
 		return fmt.Errorf("invalid_mode")
 	}
-	if mode == settings.PosModeJETON || mode == settings.PosModeHYBRID {
-		if missing, err := s.products.CountActiveWithoutJeton(ctx); err == nil && missing > 0 {
-			return MissingJetonForActiveProductsError{Count: missing}
-		} else if err != nil {
-			return err
-		}
+	if err := s.CheckJetonsAssigned(ctx, mode); err != nil {
+		return err
+	}
+	return s.change(ctx, settingsChangePosMode, func(ctx context.Context) error {
+		return s.settings.Upsert(ctx, mode)
+	})
+}
+
+func (s *settingsService) CheckJetonsAssigned(ctx context.Context, mode settings.PosMode) error {
+	if mode != settings.PosModeJETON && mode != settings.PosModeHYBRID {
+		return nil
 	}
-	return s.settings.Upsert(ctx, mode)
+	if missing, err := s.products.CountActiveWithoutJeton(ctx); err == nil && missing > 0 {
+		return MissingJetonForActiveProductsError{Count: missing}
+	} else if err != nil {
+		return err
+	}
+	return nil
 }
 
 func (s *settingsService) IsSystemEnabled(ctx context.Context) (bool, error) {
@@ -126,7 +151,10 @@ func (s *settingsService) IsSystemEnabled(ctx context.Context) (bool, error) {
 }
 
 func (s *settingsService) SetSystemEnabled(ctx context.Context, enabled bool) error {
-	if err := s.settings.SetSystemEnabled(ctx, enabled); err != nil {
+	err := s.change(ctx, settingsChangeSystemEnabled, func(ctx context.Context) error {
+		return s.settings.SetSystemEnabled(ctx, enabled)
+	})
+	if err != nil {
 		return err
 	}
 	s.systemEnabledCache.Store(&systemEnabledCache{
@@ -136,6 +164,12 @@ func (s *settingsService) SetSystemEnabled(ctx context.Context, enabled bool) er
 	return nil
 }
 
+func (s *settingsService) SetAcceptLegacyQR(ctx context.Context, accept bool) error {
+	return s.change(ctx, settingsChangeAcceptLegacyQR, func(ctx context.Context) error {
+		return s.settings.SetAcceptLegacyQR(ctx, accept)
+	})
+}
+
 func (s *settingsService) SetClub100Settings(ctx context.Context, freeProductIDs []string, maxRedemptions *int) error {
 	current, err := s.settings.Get(ctx)
 	if err != nil {
@@ -180,7 +214,9 @@ func (s *settingsService) SetClub100Settings(ctx context.Context, freeProductIDs
 		}
 	}
 
-	return s.settings.UpdateClub100Settings(ctx, validProductIDs, max)
+	return s.change(ctx, settingsChangeClub100, func(ctx context.Context) error {
+		return s.settings.UpdateClub100Settings(ctx, validProductIDs, max)
+	})
 }
 
 func (s *settingsService) ListJetons(ctx context.Context) ([]*ent.Jeton, error) {
@@ -196,7 +232,15 @@ func (s *settingsService) CreateJeton(ctx context.Context, name, color string) (
 	if err != nil {
 		return nil, err
 	}
-	return s.jetons.Create(ctx, name, normColor)
+	var created *ent.Jeton
+	err = s.change(ctx, settingsChangeJeton, func(ctx context.Context) error {
+		created, err = s.jetons.Create(ctx, name, normColor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 func (s *settingsService) UpdateJeton(ctx context.Context, id string, name, color string) (*ent.Jeton, error) {
@@ -211,7 +255,15 @@ func (s *settingsService) UpdateJeton(ctx context.Context, id string, name, colo
 	if err != nil {
 		return nil, err
 	}
-	return s.jetons.Update(ctx, id, name, normColor)
+	var updated *ent.Jeton
+	err = s.change(ctx, settingsChangeJeton, func(ctx context.Context) error {
+		updated, err = s.jetons.Update(ctx, id, name, normColor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 func (s *settingsService) DeleteJeton(ctx context.Context, id string) error {
@@ -221,7 +273,9 @@ func (s *settingsService) DeleteJeton(ctx context.Context, id string) error {
 			return JetonInUseError{Count: c}
 		}
 	}
-	return s.jetons.Delete(ctx, id)
+	return s.change(ctx, settingsChangeJeton, func(ctx context.Context) error {
+		return s.jetons.Delete(ctx, id)
+	})
 }
 
 func (s *settingsService) SetProductJeton(ctx context.Context, productID string, jetonID *string) error {
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"backend/internal/auth"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/settings"
+	"backend/internal/repository"
+)
+
+var ErrSettingsVersionNotFound = errors.New("settings_version_not_found")
+
+// Change labels stored with each settings version.
+const (
+	settingsChangeBaseline       = "baseline"
+	settingsChangePosMode        = "pos_mode"
+	settingsChangeSystemEnabled  = "system_enabled"
+	settingsChangeAcceptLegacyQR = "accept_legacy_qr"
+	settingsChangeClub100        = "club100"
+	settingsChangeJeton          = "jeton"
+	settingsChangeRollback       = "rollback"
+)
+
+// SettingsSnapshot is the state kept per settings version.
+type SettingsSnapshot struct {
+	PosMode               string          `json:"posMode"`
+	SystemEnabled         bool            `json:"systemEnabled"`
+	AcceptLegacyQR        bool            `json:"acceptLegacyQr"`
+	Club100MaxRedemptions int             `json:"club100MaxRedemptions"`
+	Club100FreeProductIDs []string        `json:"club100FreeProductIds"`
+	Jetons                []JetonSnapshot `json:"jetons"`
+}
+
+type JetonSnapshot struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// SettingsChange is one field that differs from the previous version. For
+// jetons From or To is nil when the jeton was added or removed.
+type SettingsChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type SettingsVersionEntry struct {
+	Version        int
+	Change         string
+	CreatedBy      *string
+	CreatedByName  *string
+	RolledBackFrom *int
+	CreatedAt      time.Time
+	Snapshot       SettingsSnapshot
+	Changes        []SettingsChange
+}
+
+// change runs fn under the settings row lock and records the resulting state
+// as a new version attributed to the acting user. When the history is empty
+// the state before fn is recorded first so the first change can be rolled
+// back; changes that leave everything as it was add no version.
+func (s *settingsService) change(ctx context.Context, change string, fn func(ctx context.Context) error) error {
+	return repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		if err := s.settings.Lock(ctx); err != nil {
+			return err
+		}
+		prev, err := s.latestSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		next, err := s.snapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if len(diffSettingsSnapshots(prev, next)) == 0 {
+			return nil
+		}
+		_, err = s.recordVersion(ctx, change, next, nil)
+		return err
+	})
+}
+
+// latestSnapshot returns the newest recorded state, recording a baseline of
+// the current one when there is none yet.
+func (s *settingsService) latestSnapshot(ctx context.Context) (SettingsSnapshot, error) {
+	latest, err := s.versions.Latest(ctx)
+	if errors.Is(err, repository.ErrNotFound) {
+		current, err := s.snapshot(ctx)
+		if err != nil {
+			return SettingsSnapshot{}, err
+		}
+		raw, err := json.Marshal(current)
+		if err != nil {
+			return SettingsSnapshot{}, err
+		}
+		_, err = s.versions.Create(ctx, repository.SettingsVersionInput{Change: settingsChangeBaseline, Snapshot: raw})
+		return current, err
+	}
+	if err != nil {
+		return SettingsSnapshot{}, err
+	}
+	return decodeSettingsSnapshot(latest.Snapshot)
+}
+
+func (s *settingsService) recordVersion(ctx context.Context, change string, snap SettingsSnapshot, rolledBackFrom *int) (*ent.SettingsVersion, error) {
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	in := repository.SettingsVersionInput{Change: change, Snapshot: raw, RolledBackFrom: rolledBackFrom}
+	if uid, ok := auth.GetUserID(ctx); ok {
+		in.CreatedBy = &uid
+	}
+	if name, ok := auth.GetUserName(ctx); ok && name != "" {
+		in.CreatedByName = &name
+	} else if email, ok := auth.GetUserEmail(ctx); ok && email != "" {
+		in.CreatedByName = &email
+	}
+	return s.versions.Create(ctx, in)
+}
+
+func (s *settingsService) snapshot(ctx context.Context) (SettingsSnapshot, error) {
+	current, err := s.settings.GetWithProducts(ctx)
+	if err != nil {
+		return SettingsSnapshot{}, err
+	}
+	jetons, err := s.jetons.GetAll(ctx)
+	if err != nil {
+		return SettingsSnapshot{}, err
+	}
+	snap := SettingsSnapshot{
+		PosMode:               string(current.PosMode),
+		SystemEnabled:         current.SystemEnabled,
+		AcceptLegacyQR:        current.AcceptLegacyQr,
+		Club100MaxRedemptions: current.Club100MaxRedemptions,
+		Club100FreeProductIDs: make([]string, 0, len(current.Edges.Club100FreeProducts)),
+		Jetons:                make([]JetonSnapshot, 0, len(jetons)),
+	}
+	for _, p := range current.Edges.Club100FreeProducts {
+		snap.Club100FreeProductIDs = append(snap.Club100FreeProductIDs, p.ID)
+	}
+	slices.Sort(snap.Club100FreeProductIDs)
+	for _, j := range jetons {
+		snap.Jetons = append(snap.Jetons, JetonSnapshot{ID: j.ID, Name: j.Name, Color: j.Color})
+	}
+	return snap, nil
+}
+
+func (s *settingsService) History(ctx context.Context, limit, before int) ([]SettingsVersionEntry, error) {
+	// One extra row gives the oldest entry on the page its predecessor.
+	rows, err := s.versions.List(ctx, limit+1, before)
+	if err != nil {
+		return nil, err
+	}
+	snaps := make([]SettingsSnapshot, len(rows))
+	for i, row := range rows {
+		if snaps[i], err = decodeSettingsSnapshot(row.Snapshot); err != nil {
+			return nil, fmt.Errorf("settings version %d: %w", row.Version, err)
+		}
+	}
+	out := make([]SettingsVersionEntry, 0, min(len(rows), limit))
+	for i := 0; i < len(rows) && i < limit; i++ {
+		var changes []SettingsChange
+		if i+1 < len(rows) {
+			changes = diffSettingsSnapshots(snaps[i+1], snaps[i])
+		}
+		out = append(out, settingsVersionEntry(rows[i], snaps[i], changes))
+	}
+	return out, nil
+}
+
+func (s *settingsService) Rollback(ctx context.Context, version int) (*SettingsVersionEntry, error) {
+	var out SettingsVersionEntry
+	err := repository.RunInTx(ctx, s.client, func(ctx context.Context) error {
+		if err := s.settings.Lock(ctx); err != nil {
+			return err
+		}
+		target, err := s.versions.GetByVersion(ctx, version)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: %d", ErrSettingsVersionNotFound, version)
+		} else if err != nil {
+			return err
+		}
+		want, err := decodeSettingsSnapshot(target.Snapshot)
+		if err != nil {
+			return err
+		}
+		prev, err := s.latestSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.restore(ctx, want); err != nil {
+			return err
+		}
+		next, err := s.snapshot(ctx)
+		if err != nil {
+			return err
+		}
+		created, err := s.recordVersion(ctx, settingsChangeRollback, next, &version)
+		if err != nil {
+			return err
+		}
+		out = settingsVersionEntry(created, next, diffSettingsSnapshots(prev, next))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.systemEnabledCache.Store(nil)
+	return &out, nil
+}
+
+// restore applies a snapshot. Deleted jetons come back under their old id;
+// jetons added since are deleted unless products still use them. Free
+// products that no longer exist are dropped.
+func (s *settingsService) restore(ctx context.Context, want SettingsSnapshot) error {
+	current, err := s.jetons.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*ent.Jeton, len(current))
+	for _, j := range current {
+		existing[j.ID] = j
+	}
+	for _, j := range want.Jetons {
+		cur, ok := existing[j.ID]
+		delete(existing, j.ID)
+		switch {
+		case !ok:
+			_, err = s.jetons.Restore(ctx, j.ID, j.Name, j.Color)
+		case cur.Name != j.Name || cur.Color != j.Color:
+			_, err = s.jetons.Update(ctx, j.ID, j.Name, j.Color)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	extra := make([]string, 0, len(existing))
+	for id := range existing {
+		extra = append(extra, id)
+	}
+	usage, err := s.products.CountByJetonIDs(ctx, extra)
+	if err != nil {
+		return err
+	}
+	for _, id := range extra {
+		if usage[id] > 0 {
+			continue
+		}
+		if err := s.jetons.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	mode := settings.PosMode(want.PosMode)
+	if err := settings.PosModeValidator(mode); err != nil {
+		return err
+	}
+	if err := s.CheckJetonsAssigned(ctx, mode); err != nil {
+		return err
+	}
+	if err := s.settings.Upsert(ctx, mode); err != nil {
+		return err
+	}
+	if err := s.settings.SetSystemEnabled(ctx, want.SystemEnabled); err != nil {
+		return err
+	}
+	if err := s.settings.SetAcceptLegacyQR(ctx, want.AcceptLegacyQR); err != nil {
+		return err
+	}
+	products, err := s.products.GetByIDs(ctx, want.Club100FreeProductIDs)
+	if err != nil {
+		return err
+	}
+	freeIDs := make([]string, 0, len(products))
+	for _, p := range products {
+		freeIDs = append(freeIDs, p.ID)
+	}
+	return s.settings.UpdateClub100Settings(ctx, freeIDs, want.Club100MaxRedemptions)
+}
+
+func decodeSettingsSnapshot(raw json.RawMessage) (SettingsSnapshot, error) {
+	var snap SettingsSnapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return SettingsSnapshot{}, fmt.Errorf("decode settings snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+func diffSettingsSnapshots(from, to SettingsSnapshot) []SettingsChange {
+	var changes []SettingsChange
+	if from.PosMode != to.PosMode {
+		changes = append(changes, SettingsChange{Field: "posMode", From: from.PosMode, To: to.PosMode})
+	}
+	if from.SystemEnabled != to.SystemEnabled {
+		changes = append(changes, SettingsChange{Field: "systemEnabled", From: from.SystemEnabled, To: to.SystemEnabled})
+	}
+	if from.AcceptLegacyQR != to.AcceptLegacyQR {
+		changes = append(changes, SettingsChange{Field: "acceptLegacyQr", From: from.AcceptLegacyQR, To: to.AcceptLegacyQR})
+	}
+	if from.Club100MaxRedemptions != to.Club100MaxRedemptions {
+		changes = append(changes, SettingsChange{Field: "club100MaxRedemptions", From: from.Club100MaxRedemptions, To: to.Club100MaxRedemptions})
+	}
+	if !slices.Equal(from.Club100FreeProductIDs, to.Club100FreeProductIDs) {
+		changes = append(changes, SettingsChange{Field: "club100FreeProductIds", From: from.Club100FreeProductIDs, To: to.Club100FreeProductIDs})
+	}
+
+	before := make(map[string]JetonSnapshot, len(from.Jetons))
+	for _, j := range from.Jetons {
+		before[j.ID] = j
+	}
+	for _, j := range to.Jetons {
+		old, ok := before[j.ID]
+		delete(before, j.ID)
+		switch {
+		case !ok:
+			changes = append(changes, SettingsChange{Field: "jeton", To: j})
+		case old != j:
+			changes = append(changes, SettingsChange{Field: "jeton", From: old, To: j})
+		}
+	}
+	for _, j := range from.Jetons {
+		if _, removed := before[j.ID]; removed {
+			changes = append(changes, SettingsChange{Field: "jeton", From: j})
+		}
+	}
+	return changes
+}
+
+func settingsVersionEntry(row *ent.SettingsVersion, snap SettingsSnapshot, changes []SettingsChange) SettingsVersionEntry {
+	return SettingsVersionEntry{
+		Version:        row.Version,
+		Change:         row.Change,
+		CreatedBy:      row.CreatedBy,
+		CreatedByName:  row.CreatedByName,
+		RolledBackFrom: row.RolledBackFrom,
+		CreatedAt:      row.CreatedAt,
+		Snapshot:       snap,
+		Changes:        changes,
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"backend/internal/config"
 	"backend/internal/generated/ent"
 	"backend/internal/generated/ent/device"
+	"backend/internal/generated/ent/order"
 	"backend/internal/generated/ent/orderline"
 	"backend/internal/repository"
+	"backend/internal/stationqueue"
 )
 
 type StationService interface {
@@ -23,9 +26,50 @@ type StationService interface {
 	ListStationProductIDs(ctx context.Context, stationID string) ([]string, error)
 	AssignedItemsForOrder(ctx context.Context, stationID, orderID string) ([]*ent.OrderLine, error)
 	RedeemAssigned(ctx context.Context, stationID, orderID string, idemKey string) (map[string]any, error)
+	RedeemSelected(ctx context.Context, stationID, orderID string, selections []RedeemSelection, idemKey string) (map[string]any, error)
+	Queue(ctx context.Context, stationID string) ([]StationQueueEntry, error)
+	QueueEntryForOrder(ctx context.Context, stationID, orderID string) (*StationQueueEntry, error)
+	UndoRedemption(ctx context.Context, batchID string, actor RedemptionUndoActor, reason string) (*ent.RedemptionBatch, error)
+	ListRedemptionUndos(ctx context.Context) ([]*ent.RedemptionBatch, error)
 	RenameStation(ctx context.Context, stationID string, name string) (*ent.Device, error)
 }
 
+var (
+	ErrStationLineNotAssigned          = errors.New("line_not_assigned")
+	ErrStationInvalidRedeemQuantity    = errors.New("invalid_quantity")
+	ErrStationQuantityExceedsRemaining = errors.New("quantity_exceeds_remaining")
+
+	ErrRedemptionBatchNotFound      = errors.New("redemption_batch_not_found")
+	ErrRedemptionAlreadyUndone      = errors.New("redemption_already_undone")
+	ErrRedemptionUndoWrongStation   = errors.New("redemption_undo_wrong_station")
+	ErrRedemptionUndoWindowExpired  = errors.New("redemption_undo_window_expired")
+	ErrRedemptionUndoReasonRequired = errors.New("redemption_undo_reason_required")
+)
+
+// RedemptionUndoActor identifies who undoes a redemption batch. Stations may
+// only undo their own batches inside the configured window; admins may always.
+type RedemptionUndoActor struct {
+	DeviceID *string
+	UserID   *string
+	Admin    bool
+}
+
+// RedeemSelection picks one order line to redeem at a station. A nil Quantity
+// redeems whatever is still open on that line.
+type RedeemSelection struct {
+	OrderLineID string
+	Quantity    *int
+}
+
+// StationQueueEntry is a paid order as seen by one station: only the redeemable
+// lines assigned to that station which are still waiting to be handed out.
+type StationQueueEntry struct {
+	OrderID   string
+	Origin    order.Origin
+	CreatedAt time.Time
+	Items     []*ent.OrderLine
+}
+
 type stationService struct {
 	cfg            config.Config
 	client         *ent.Client
@@ -33,7 +77,10 @@ type stationService struct {
 	deviceProducts repository.DeviceProductRepository
 	orderLineRepo  repository.OrderLineRepository
 	redemptionRepo repository.OrderLineRedemptionRepository
+	batches        repository.RedemptionBatchRepository
 	idempotency    repository.IdempotencyRepository
+	orders         repository.OrderRepository
+	queueHub       *stationqueue.Hub
 }
 
 func NewStationService(
@@ -43,7 +90,10 @@ func NewStationService(
 	deviceProducts repository.DeviceProductRepository,
 	orderLineRepo repository.OrderLineRepository,
 	redemptionRepo repository.OrderLineRedemptionRepository,
+	batches repository.RedemptionBatchRepository,
 	idempotency repository.IdempotencyRepository,
+	orders repository.OrderRepository,
+	queueHub *stationqueue.Hub,
 ) StationService {
 	return &stationService{
 		cfg:            cfg,
@@ -52,7 +102,10 @@ func NewStationService(
 		deviceProducts: deviceProducts,
 		orderLineRepo:  orderLineRepo,
 		redemptionRepo: redemptionRepo,
+		batches:        batches,
 		idempotency:    idempotency,
+		orders:         orders,
+		queueHub:       queueHub,
 	}
 }
 
@@ -131,8 +184,12 @@ func (s *stationService) AssignedItemsForOrder(ctx context.Context, stationID, o
 	if err != nil {
 		return nil, err
 	}
+	return s.expandBundleLines(ctx, lines)
+}
 
-	// Bundle lines are not redeemable — expand them to their component children.
+// expandBundleLines replaces bundle lines with their component children, since
+// bundle lines themselves are not redeemable.
+func (s *stationService) expandBundleLines(ctx context.Context, lines []*ent.OrderLine) ([]*ent.OrderLine, error) {
 	var bundleIDs []string
 	var result []*ent.OrderLine
 	for _, line := range lines {
@@ -154,6 +211,13 @@ func (s *stationService) AssignedItemsForOrder(ctx context.Context, stationID, o
 }
 
 func (s *stationService) RedeemAssigned(ctx context.Context, stationID, orderID string, idemKey string) (map[string]any, error) {
+	return s.RedeemSelected(ctx, stationID, orderID, nil, idemKey)
+}
+
+// RedeemSelected hands out the chosen lines (and quantities) assigned to the
+// station. An empty selection redeems everything still open, which is what
+// RedeemAssigned does.
+func (s *stationService) RedeemSelected(ctx context.Context, stationID, orderID string, selections []RedeemSelection, idemKey string) (map[string]any, error) {
 	scope := fmt.Sprintf("station:%s:order:%s", stationID, orderID)
 
 	// Check idempotency
@@ -170,33 +234,86 @@ func (s *stationService) RedeemAssigned(ctx context.Context, stationID, orderID
 		return nil, err
 	}
 
-	// Filter unredeemed items, skipping bundle parents (only components are redeemable)
-	var unredeemedIDs []string
-	for _, line := range assigned {
-		if line.Edges.Redemption == nil && line.LineType != orderline.LineTypeBundle {
-			unredeemedIDs = append(unredeemedIDs, line.ID)
-		}
+	wanted, err := resolveRedeemSelections(assigned, selections)
+	if err != nil {
+		return nil, err
 	}
 
-	// Redeem unredeemed items
-	var redeemed int64
-	if len(unredeemedIDs) > 0 {
-		redeemed, err = s.redemptionRepo.RedeemUnredeemedByOrderLineIDs(ctx, unredeemedIDs)
+	// Redeem in one transaction so a multi-line request is all-or-nothing, and
+	// record what was taken as a batch that can be undone later. A concurrent
+	// redemption can leave less open than was checked above; the request then
+	// fails instead of handing out fewer units than the operator picked.
+	taken := make(map[string]int, len(wanted))
+	var batchID string
+	if len(wanted) > 0 {
+		tx, err := s.client.Tx(ctx)
 		if err != nil {
 			return nil, err
 		}
+		defer func() { _ = tx.Rollback() }()
+		txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+		for _, line := range assigned {
+			qty, ok := wanted[line.ID]
+			if !ok {
+				continue
+			}
+			n, err := s.redemptionRepo.RedeemQuantity(txCtx, line.ID, qty)
+			if err != nil {
+				return nil, err
+			}
+			if n < qty {
+				return nil, ErrStationQuantityExceedsRemaining
+			}
+			taken[line.ID] = n
+		}
+
+		if len(taken) > 0 {
+			items := make([]repository.RedemptionBatchItemParams, 0, len(taken))
+			for _, line := range assigned {
+				if n, ok := taken[line.ID]; ok {
+					items = append(items, repository.RedemptionBatchItemParams{OrderLineID: line.ID, Quantity: n})
+				}
+			}
+			batch, err := s.batches.Create(txCtx, orderID, &stationID, items)
+			if err != nil {
+				return nil, err
+			}
+			batchID = batch.ID
+		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	var redeemedQty int
+	for _, n := range taken {
+		redeemedQty += n
 	}
 
 	now := time.Now().UTC()
 
-	// Build response
+	// Build response. Items reflect the order as scanned; remaining lists what
+	// is still open after this request.
+	remaining := remainingAfterRedemption(assigned, taken)
+	var remainingQty int
+	for _, item := range remaining {
+		remainingQty += item["remainingQuantity"].(int)
+	}
 	resp := map[string]any{
-		"orderId":    orderID,
-		"stationId":  stationID,
-		"matched":    len(assigned),
-		"redeemed":   redeemed,
-		"items":      toPublicOrderLines(assigned),
-		"redeemedAt": now.Format(time.RFC3339),
+		"orderId":           orderID,
+		"stationId":         stationID,
+		"matched":           len(assigned),
+		"redeemed":          int64(len(taken)),
+		"redeemedQuantity":  redeemedQty,
+		"remainingQuantity": remainingQty,
+		"items":             toPublicOrderLines(assigned),
+		"remaining":         remaining,
+		"redeemedAt":        now.Format(time.RFC3339),
+	}
+	if batchID != "" {
+		resp["batchId"] = batchID
 	}
 
 	// Save idempotency record
@@ -204,9 +321,246 @@ func (s *stationService) RedeemAssigned(ctx context.Context, stationID, orderID
 		_, _ = s.idempotency.SaveIfAbsent(ctx, scope, idemKey, resp, 24*time.Hour)
 	}
 
+	if len(taken) > 0 {
+		publishQueueEvent(s.queueHub, stationqueue.EventOrderRedeemed, orderID, &stationID)
+	}
+
 	return resp, nil
 }
 
+// resolveRedeemSelections maps each requested line to the quantity to redeem.
+// Without selections every open redeemable line is taken in full.
+func resolveRedeemSelections(assigned []*ent.OrderLine, selections []RedeemSelection) (map[string]int, error) {
+	open := make(map[string]int, len(assigned))
+	for _, line := range assigned {
+		if line.LineType == orderline.LineTypeBundle {
+			continue
+		}
+		open[line.ID] = line.Quantity - line.RedeemedQuantity
+	}
+
+	wanted := make(map[string]int)
+	if len(selections) == 0 {
+		for id, qty := range open {
+			if qty > 0 {
+				wanted[id] = qty
+			}
+		}
+		return wanted, nil
+	}
+
+	for _, sel := range selections {
+		remaining, ok := open[sel.OrderLineID]
+		if !ok {
+			return nil, ErrStationLineNotAssigned
+		}
+		qty := remaining - wanted[sel.OrderLineID]
+		if sel.Quantity != nil {
+			if *sel.Quantity <= 0 {
+				return nil, ErrStationInvalidRedeemQuantity
+			}
+			if *sel.Quantity > qty {
+				return nil, ErrStationQuantityExceedsRemaining
+			}
+			qty = *sel.Quantity
+		}
+		if qty > 0 {
+			wanted[sel.OrderLineID] += qty
+		}
+	}
+	return wanted, nil
+}
+
+// remainingAfterRedemption lists the redeemable lines that still have open
+// units once `taken` has been applied.
+func remainingAfterRedemption(assigned []*ent.OrderLine, taken map[string]int) []map[string]any {
+	out := make([]map[string]any, 0, len(assigned))
+	for _, line := range assigned {
+		if line.LineType == orderline.LineTypeBundle {
+			continue
+		}
+		left := line.Quantity - line.RedeemedQuantity - taken[line.ID]
+		if left <= 0 {
+			continue
+		}
+		out = append(out, map[string]any{
+			"id":                line.ID,
+			"productId":         line.ProductID,
+			"title":             line.Title,
+			"quantity":          line.Quantity,
+			"remainingQuantity": left,
+			"parentItemId":      line.ParentLineID,
+			"menuSlotId":        line.MenuSlotID,
+			"menuSlotName":      line.MenuSlotName,
+			"variantId":         line.VariantID,
+			"variantName":       line.VariantName,
+			"allergens":         line.Allergens,
+		})
+	}
+	return out
+}
+
+// UndoRedemption reverts every unit handed out by a redemption batch and
+// records who undid it and why on the batch itself.
+func (s *stationService) UndoRedemption(ctx context.Context, batchID string, actor RedemptionUndoActor, reason string) (*ent.RedemptionBatch, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrRedemptionUndoReasonRequired
+	}
+	reason = truncateRunes(reason, 200)
+
+	batch, err := s.batches.GetByID(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrRedemptionBatchNotFound
+		}
+		return nil, err
+	}
+	if batch.UndoneAt != nil {
+		return nil, ErrRedemptionAlreadyUndone
+	}
+
+	now := time.Now().UTC()
+	if !actor.Admin {
+		if actor.DeviceID == nil || batch.StationDeviceID == nil || *actor.DeviceID != *batch.StationDeviceID {
+			return nil, ErrRedemptionUndoWrongStation
+		}
+		if now.Sub(batch.CreatedAt) > s.cfg.Station.RedemptionUndoWindow {
+			return nil, ErrRedemptionUndoWindowExpired
+		}
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	ok, err := s.batches.MarkUndone(txCtx, batch.ID, actor.DeviceID, actor.UserID, reason, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRedemptionAlreadyUndone
+	}
+	for _, item := range batch.Edges.Items {
+		if err := s.redemptionRepo.UnredeemQuantity(txCtx, item.OrderLineID, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	publishQueueEvent(s.queueHub, stationqueue.EventRedemptionUndone, batch.OrderID, batch.StationDeviceID)
+
+	return s.batches.GetByID(ctx, batch.ID)
+}
+
+func (s *stationService) ListRedemptionUndos(ctx context.Context) ([]*ent.RedemptionBatch, error) {
+	return s.batches.ListUndone(ctx, redemptionUndoListLimit)
+}
+
+const redemptionUndoListLimit = 500
+
+func (s *stationService) Queue(ctx context.Context, stationID string) ([]StationQueueEntry, error) {
+	orders, err := s.orders.ListPaidPendingForStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return []StationQueueEntry{}, nil
+	}
+
+	orderIDs := make([]string, len(orders))
+	for i, o := range orders {
+		orderIDs[i] = o.ID
+	}
+	lines, err := s.orderLineRepo.GetByOrderIDsAndStationID(ctx, orderIDs, stationID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.expandBundleLines(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingByOrder := make(map[string][]*ent.OrderLine, len(orders))
+	for _, line := range pendingQueueItems(items) {
+		pendingByOrder[line.OrderID] = append(pendingByOrder[line.OrderID], line)
+	}
+
+	entries := make([]StationQueueEntry, 0, len(orders))
+	for _, o := range orders {
+		pending := pendingByOrder[o.ID]
+		if len(pending) == 0 {
+			continue
+		}
+		entries = append(entries, StationQueueEntry{
+			OrderID:   o.ID,
+			Origin:    o.Origin,
+			CreatedAt: o.CreatedAt,
+			Items:     pending,
+		})
+	}
+	return entries, nil
+}
+
+// QueueEntryForOrder returns the station's view of a single order, or nil when
+// none of the order's lines are assigned to the station. An entry with no items
+// means the order has left the station's queue.
+func (s *stationService) QueueEntryForOrder(ctx context.Context, stationID, orderID string) (*StationQueueEntry, error) {
+	o, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := s.AssignedItemsForOrder(ctx, stationID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(assigned) == 0 {
+		return nil, nil
+	}
+
+	entry := &StationQueueEntry{
+		OrderID:   o.ID,
+		Origin:    o.Origin,
+		CreatedAt: o.CreatedAt,
+		Items:     []*ent.OrderLine{},
+	}
+	if o.Status == order.StatusPaid {
+		entry.Items = pendingQueueItems(assigned)
+	}
+	return entry, nil
+}
+
+// pendingQueueItems keeps the redeemable lines that have not been handed out yet.
+func pendingQueueItems(lines []*ent.OrderLine) []*ent.OrderLine {
+	out := make([]*ent.OrderLine, 0, len(lines))
+	for _, line := range lines {
+		if line.Edges.Redemption == nil && line.LineType != orderline.LineTypeBundle {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// publishQueueEvent notifies station queue subscribers; a nil hub is a no-op so
+// services can be constructed without one in tests.
+func publishQueueEvent(hub *stationqueue.Hub, eventType stationqueue.EventType, orderID string, stationID *string) {
+	if hub == nil {
+		return
+	}
+	hub.Publish(stationqueue.Event{
+		Type:      eventType,
+		OrderID:   orderID,
+		StationID: stationID,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
 func toPublicOrderLines(lines []*ent.OrderLine) []map[string]any {
 	out := make([]map[string]any, 0, len(lines))
 	for _, line := range lines {
@@ -214,15 +568,19 @@ func toPublicOrderLines(lines []*ent.OrderLine) []map[string]any {
 		msID := line.MenuSlotID
 		isRedeemed := line.Edges.Redemption != nil
 		out = append(out, map[string]any{
-			"id":           line.ID,
-			"orderId":      line.OrderID,
-			"productId":    line.ProductID,
-			"title":        line.Title,
-			"quantity":     line.Quantity,
-			"isRedeemed":   isRedeemed,
-			"parentItemId": parentID,
-			"menuSlotId":   msID,
-			"menuSlotName": line.MenuSlotName,
+			"id":               line.ID,
+			"orderId":          line.OrderID,
+			"productId":        line.ProductID,
+			"title":            line.Title,
+			"quantity":         line.Quantity,
+			"redeemedQuantity": line.RedeemedQuantity,
+			"isRedeemed":       isRedeemed,
+			"parentItemId":     parentID,
+			"menuSlotId":       msID,
+			"menuSlotName":     line.MenuSlotName,
+			"variantId":        line.VariantID,
+			"variantName":      line.VariantName,
+			"allergens":        line.Allergens,
 		})
 	}
 	return out
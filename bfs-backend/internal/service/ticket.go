@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend/internal/config"
+	"backend/internal/escpos"
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/orderline"
+	"backend/internal/generated/ent/orderpayment"
+	"backend/internal/repository"
+)
+
+const ticketHeader = "BlessThun Food"
+
+// TicketService renders ESC/POS order tickets and station slips so every
+// device prints the same layout.
+type TicketService interface {
+	OrderTicket(ctx context.Context, orderID string, paper escpos.Paper) ([]byte, error)
+	StationSlip(ctx context.Context, stationID, orderID string, paper escpos.Paper) ([]byte, error)
+}
+
+type ticketService struct {
+	orders        repository.OrderRepository
+	stations      StationService
+	qr            QRService
+	publicBaseURL string
+}
+
+func NewTicketService(cfg config.Config, orders repository.OrderRepository, stations StationService, qr QRService) TicketService {
+	return &ticketService{
+		orders:        orders,
+		stations:      stations,
+		qr:            qr,
+		publicBaseURL: strings.TrimRight(cfg.App.PublicBaseURL, "/"),
+	}
+}
+
+func (s *ticketService) OrderTicket(ctx context.Context, orderID string, paper escpos.Paper) ([]byte, error) {
+	o, err := s.orders.GetByIDWithRelations(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []escpos.TicketLine
+	var notes []string
+	for _, l := range o.Edges.Lines {
+		if l.ParentLineID != nil {
+			continue
+		}
+		tl := escpos.TicketLine{Quantity: l.Quantity, Title: lineTitle(l.Title, l.VariantName), PriceCents: l.UnitPriceCents}
+		for _, c := range l.Edges.ChildLines {
+			tl.Components = append(tl.Components, ticketComponent(c))
+			notes = appendRedeemedNote(notes, c)
+		}
+		if l.LineType != orderline.LineTypeBundle {
+			notes = appendRedeemedNote(notes, l)
+		}
+		lines = append(lines, tl)
+	}
+
+	var payments []string
+	for _, p := range o.Edges.Payments {
+		payments = append(payments, fmt.Sprintf("%s CHF %d.%02d", paymentLabel(p.Method), p.AmountCents/100, p.AmountCents%100))
+	}
+	if o.Status != order.StatusPaid {
+		notes = append([]string{"Nicht bezahlt (" + string(o.Status) + ")"}, notes...)
+	}
+
+	return escpos.RenderOrderTicket(escpos.OrderTicket{
+		Header:     ticketHeader,
+		OrderID:    o.ID,
+		PickupCode: pickupCode(o.ID),
+		Origin:     originLabel(o.Origin),
+		CreatedAt:  o.CreatedAt,
+		Lines:      lines,
+		TotalCents: o.TotalCents,
+		Payments:   payments,
+		Notes:      notes,
+		QRPayload:  s.orderQRPayload(o),
+	}, paper), nil
+}
+
+// StationSlip lists what is still open for this station on the order. Bundle
+// components are grouped under their bundle, like on the kitchen screen.
+func (s *ticketService) StationSlip(ctx context.Context, stationID, orderID string, paper escpos.Paper) ([]byte, error) {
+	o, err := s.orders.GetByIDWithRelations(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*ent.OrderLine, len(o.Edges.Lines))
+	for _, l := range o.Edges.Lines {
+		byID[l.ID] = l
+	}
+	station, err := s.stations.GetStationByID(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.stations.AssignedItemsForOrder(ctx, stationID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []escpos.TicketLine
+	bundles := make(map[string]int)
+	var notes []string
+	for _, it := range items {
+		open := it.Quantity - it.RedeemedQuantity
+		if open <= 0 {
+			continue
+		}
+		if it.RedeemedQuantity > 0 {
+			notes = appendRedeemedNote(notes, it)
+		}
+		if it.ParentLineID == nil {
+			lines = append(lines, escpos.TicketLine{Quantity: open, Title: lineTitle(it.Title, it.VariantName)})
+			continue
+		}
+		idx, ok := bundles[*it.ParentLineID]
+		if !ok {
+			bundle := escpos.TicketLine{Quantity: 1}
+			if parent, ok := byID[*it.ParentLineID]; ok {
+				bundle.Quantity, bundle.Title = parent.Quantity, parent.Title
+			}
+			lines = append(lines, bundle)
+			idx = len(lines) - 1
+			bundles[*it.ParentLineID] = idx
+		}
+		c := ticketComponent(it)
+		c.Quantity = open
+		lines[idx].Components = append(lines[idx].Components, c)
+	}
+
+	return escpos.RenderStationSlip(escpos.StationSlip{
+		StationName: station.Name,
+		OrderID:     o.ID,
+		PickupCode:  pickupCode(o.ID),
+		CreatedAt:   o.CreatedAt,
+		Lines:       lines,
+		Notes:       notes,
+		QRPayload:   s.orderQRPayload(o),
+	}, paper), nil
+}
+
+// orderQRPayload falls back to the legacy pickup URL while QR signing is not
+// configured, matching what the customer's order page shows.
+func (s *ticketService) orderQRPayload(o *ent.Order) string {
+	if payload := s.qr.OrderPayload(o); payload != "" {
+		return payload
+	}
+	return s.publicBaseURL + "/o/" + o.ID
+}
+
+func ticketComponent(l *ent.OrderLine) escpos.TicketComponent {
+	c := escpos.TicketComponent{Quantity: l.Quantity, Title: l.Title}
+	if l.MenuSlotName != nil {
+		c.Slot = *l.MenuSlotName
+	}
+	return c
+}
+
+func appendRedeemedNote(notes []string, l *ent.OrderLine) []string {
+	if l.RedeemedQuantity <= 0 {
+		return notes
+	}
+	return append(notes, fmt.Sprintf("%s: %d/%d bereits ausgegeben", lineTitle(l.Title, l.VariantName), l.RedeemedQuantity, l.Quantity))
+}
+
+// pickupCode is the short code called out at the counter: the last four
+// characters of the order ID.
+func pickupCode(orderID string) string {
+	if len(orderID) <= 4 {
+		return "#" + orderID
+	}
+	return "#" + orderID[len(orderID)-4:]
+}
+
+func originLabel(o order.Origin) string {
+	switch o {
+	case order.OriginPos:
+		return "Kasse"
+	case order.OriginShop:
+		return "Online"
+	}
+	return string(o)
+}
+
+func paymentLabel(m orderpayment.Method) string {
+	switch m {
+	case orderpayment.MethodCASH:
+		return "Bar"
+	case orderpayment.MethodCARD:
+		return "Karte"
+	case orderpayment.MethodTWINT:
+		return "TWINT"
+	case orderpayment.MethodGRATIS_GUEST:
+		return "Gratis Gast"
+	case orderpayment.MethodGRATIS_VIP:
+		return "Gratis VIP"
+	case orderpayment.MethodGRATIS_STAFF:
+		return "Gratis Helfer"
+	case orderpayment.MethodGRATIS_100CLUB:
+		return "100er Club"
+	}
+	return string(m)
+}
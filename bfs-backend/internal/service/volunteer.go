@@ -6,14 +6,18 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/inventoryledger"
 	"backend/internal/generated/ent/order"
 	"backend/internal/generated/ent/orderline"
 	"backend/internal/generated/ent/orderpayment"
+	"backend/internal/generated/ent/product"
 	"backend/internal/generated/ent/volunteercampaign"
+	"backend/internal/inventory"
 	"backend/internal/repository"
 )
 
@@ -34,11 +38,23 @@ var (
 	ErrVolunteerMaxRedemptionsReached     = errors.New("volunteer_max_redemptions_reached")
 	ErrVolunteerMaxBelowCount             = errors.New("volunteer_max_redemptions_below_current_count")
 	ErrVolunteerStationNoMatchingProducts = errors.New("volunteer_station_has_no_matching_products")
+	ErrVolunteerTokenNotFound             = errors.New("volunteer_token_not_found")
+	ErrVolunteerPersonalLimitReached      = errors.New("volunteer_personal_limit_reached")
+	ErrVolunteerRosterEmpty               = errors.New("volunteer_roster_empty")
+	ErrVolunteerRosterInvalid             = errors.New("volunteer_roster_invalid")
+	ErrVolunteerCampaignOutsideWindow     = errors.New("volunteer_campaign_outside_window")
+	ErrVolunteerWindowLimitReached        = errors.New("volunteer_window_limit_reached")
+	ErrVolunteerDailyLimitReached         = errors.New("volunteer_daily_limit_reached")
+	ErrVolunteerScheduleInvalid           = errors.New("volunteer_schedule_invalid")
+	ErrVolunteerChoiceGroupsInvalid       = errors.New("volunteer_choice_groups_invalid")
+	ErrVolunteerChoiceInvalid             = errors.New("volunteer_choice_invalid")
+	ErrVolunteerSlipCountInvalid          = errors.New("volunteer_slip_count_invalid")
 )
 
 type VolunteerService interface {
 	CreateCampaign(ctx context.Context, input CreateVolunteerCampaignInput) (*ent.VolunteerCampaign, error)
-	ListCampaigns(ctx context.Context) ([]VolunteerCampaignSummary, error)
+	// ListCampaigns returns all campaigns, or only those of one event.
+	ListCampaigns(ctx context.Context, eventID *string) ([]VolunteerCampaignSummary, error)
 	GetCampaign(ctx context.Context, id string) (*VolunteerCampaignDetail, error)
 	UpdateCampaign(ctx context.Context, id string, input UpdateVolunteerCampaignInput) (*ent.VolunteerCampaign, error)
 	EndCampaign(ctx context.Context, id string) error
@@ -48,14 +64,36 @@ type VolunteerService interface {
 	NewSessionID() string
 
 	GetClaimView(ctx context.Context, token string) (*VolunteerClaimView, error)
-	RedeemSharedQR(ctx context.Context, claimToken string, stationID string, idempotencyKey string) (*VolunteerRedemptionResult, error)
+	// The redeem methods take the station's picks for the campaign's choice
+	// groups as group ID → product ID; see VolunteerChoiceRequiredError.
+	RedeemSharedQR(ctx context.Context, claimToken string, stationID string, idempotencyKey string, choices map[string]string) (*VolunteerRedemptionResult, error)
+	RedeemPersonalQR(ctx context.Context, token string, stationID string, idempotencyKey string, choices map[string]string) (*VolunteerRedemptionResult, error)
+
+	ImportVolunteers(ctx context.Context, campaignID string, csv io.Reader, defaultMax int) (*VolunteerImportResult, error)
+	ListVolunteers(ctx context.Context, campaignID string) ([]*ent.VolunteerToken, error)
+	DeleteVolunteer(ctx context.Context, campaignID, volunteerID string) error
+
+	GetSchedule(ctx context.Context, campaignID string) (*VolunteerSchedule, error)
+	SetSchedule(ctx context.Context, campaignID string, in VolunteerSchedule) (*VolunteerSchedule, error)
+
+	GetChoiceGroups(ctx context.Context, campaignID string) ([]VolunteerChoiceGroup, error)
+	SetChoiceGroups(ctx context.Context, campaignID string, groups []repository.VolunteerChoiceGroupInput) ([]VolunteerChoiceGroup, error)
+
+	GetSlipContent(ctx context.Context, campaignID string) (*VolunteerSlipContent, error)
+	SetLogo(ctx context.Context, campaignID string, logoURL *string) (previous *string, err error)
+	IssueNumberedSlips(ctx context.Context, campaignID string, count int) ([]*ent.VolunteerToken, error)
+	ListNumberedSlips(ctx context.Context, campaignID string, from, to int) ([]*ent.VolunteerToken, error)
+	GetSlipSummary(ctx context.Context, campaignID string) (*VolunteerSlipSummary, error)
 }
 
 type CreateVolunteerCampaignInput struct {
-	Name           string
+	Name string
+	// EventID defaults to the active event.
+	EventID        *string
 	ValidFrom      *time.Time
 	ValidUntil     *time.Time
 	Products       []repository.VolunteerCampaignProductInput
+	ChoiceGroups   []repository.VolunteerChoiceGroupInput
 	MaxRedemptions int
 }
 
@@ -94,45 +132,68 @@ type VolunteerCampaignDetail struct {
 }
 
 type VolunteerClaimView struct {
-	Campaign  *ent.VolunteerCampaign
-	Products  []VolunteerCampaignProductView
-	QRPayload string
+	Campaign     *ent.VolunteerCampaign
+	Products     []VolunteerCampaignProductView
+	ChoiceGroups []VolunteerChoiceGroup
+	QRPayload    string
+	Windows      []VolunteerWindow
+	// NextWindow is the running or next upcoming meal window; nil when the
+	// campaign has no windows or none is left within the validity range.
+	NextWindow *VolunteerWindowOccurrence
 }
 
+// VolunteerRedemptionResult reports the counter that applied: the campaign's
+// for the shared QR, the volunteer's own for a personal token.
 type VolunteerRedemptionResult struct {
 	OrderID         string
 	RedemptionCount int
 	MaxRedemptions  int
+	HolderName      string
 	StationResult   map[string]any
 }
 
 type volunteerService struct {
-	client      *ent.Client
-	campaigns   repository.VolunteerCampaignRepository
-	redemptions repository.VolunteerRedemptionRepository
-	orders      repository.OrderRepository
-	lines       repository.OrderLineRepository
-	payments    repository.OrderPaymentRepository
-	stations    StationService
+	client        *ent.Client
+	campaigns     repository.VolunteerCampaignRepository
+	redemptions   repository.VolunteerRedemptionRepository
+	tokens        repository.VolunteerTokenRepository
+	orders        repository.OrderRepository
+	lines         repository.OrderLineRepository
+	payments      repository.OrderPaymentRepository
+	inventoryRepo repository.InventoryLedgerRepository
+	inventoryHub  *inventory.Hub
+	stations      StationService
+	qr            QRService
+	events        EventService
 }
 
 func NewVolunteerService(
 	client *ent.Client,
 	campaigns repository.VolunteerCampaignRepository,
 	redemptions repository.VolunteerRedemptionRepository,
+	tokens repository.VolunteerTokenRepository,
 	orders repository.OrderRepository,
 	lines repository.OrderLineRepository,
 	payments repository.OrderPaymentRepository,
+	inventoryRepo repository.InventoryLedgerRepository,
+	inventoryHub *inventory.Hub,
 	stations StationService,
+	qr QRService,
+	events EventService,
 ) VolunteerService {
 	return &volunteerService{
-		client:      client,
-		campaigns:   campaigns,
-		redemptions: redemptions,
-		orders:      orders,
-		lines:       lines,
-		payments:    payments,
-		stations:    stations,
+		client:        client,
+		campaigns:     campaigns,
+		redemptions:   redemptions,
+		tokens:        tokens,
+		orders:        orders,
+		lines:         lines,
+		payments:      payments,
+		inventoryRepo: inventoryRepo,
+		inventoryHub:  inventoryHub,
+		stations:      stations,
+		qr:            qr,
+		events:        events,
 	}
 }
 
@@ -153,7 +214,8 @@ func generateAccessCode() string {
 	return string(buf)
 }
 
-// BuildQRPayload returns the string encoded in the shared campaign QR code.
+// BuildQRPayload returns the legacy, unsigned campaign QR payload. It is only
+// issued while no QR signing secret is configured; see QRService.
 // Station scanner detects the CAMP: prefix and routes to the campaign-redeem endpoint.
 func BuildQRPayload(claimToken string) string {
 	return volunteerQRPayloadPfx + claimToken
@@ -163,12 +225,29 @@ func (s *volunteerService) CreateCampaign(ctx context.Context, input CreateVolun
 	if strings.TrimSpace(input.Name) == "" {
 		return nil, errors.New("name_required")
 	}
-	if len(input.Products) == 0 {
+	if len(input.Products) == 0 && len(input.ChoiceGroups) == 0 {
 		return nil, ErrVolunteerCampaignHasNoProducts
 	}
 	if input.MaxRedemptions <= 0 {
 		return nil, errors.New("max_redemptions_must_be_positive")
 	}
+	groups, err := normalizeChoiceGroups(input.ChoiceGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := input.EventID
+	if eventID != nil {
+		if _, err := s.events.Get(ctx, *eventID); err != nil {
+			return nil, err
+		}
+	} else {
+		catalog, err := currentEventCatalog(ctx, s.events)
+		if err != nil {
+			return nil, err
+		}
+		eventID = catalog.EventID()
+	}
 
 	accessCode := generateAccessCode()
 
@@ -180,7 +259,7 @@ func (s *volunteerService) CreateCampaign(ctx context.Context, input CreateVolun
 
 	txCtx := repository.ContextWithClient(ctx, tx.Client())
 
-	campaign, err := s.campaigns.Create(txCtx, input.Name, accessCode, input.ValidFrom, input.ValidUntil, volunteercampaign.StatusActive, input.MaxRedemptions)
+	campaign, err := s.campaigns.Create(txCtx, input.Name, accessCode, eventID, input.ValidFrom, input.ValidUntil, volunteercampaign.StatusActive, input.MaxRedemptions)
 	if err != nil {
 		return nil, fmt.Errorf("create campaign: %w", err)
 	}
@@ -188,6 +267,9 @@ func (s *volunteerService) CreateCampaign(ctx context.Context, input CreateVolun
 	if err := s.campaigns.ReplaceProducts(txCtx, campaign.ID, input.Products); err != nil {
 		return nil, fmt.Errorf("attach products: %w", err)
 	}
+	if err := s.campaigns.ReplaceChoiceGroups(txCtx, campaign.ID, groups); err != nil {
+		return nil, fmt.Errorf("attach choice groups: %w", err)
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
@@ -199,24 +281,47 @@ type productSnapshot struct {
 	ID       string
 	Name     string
 	Quantity int
+	// Simple products are tracked in the inventory ledger.
+	Simple bool
 }
 
-func (s *volunteerService) createGratisOrder(ctx context.Context, products []productSnapshot) (string, error) {
-	ord, err := s.orders.Create(ctx, 0, order.StatusPaid, order.OriginShop, nil, nil, nil, nil, nil)
+// createGratisOrder creates the paid gratis order for one redemption and
+// books the stock of its simple products out of the inventory. Both belong to
+// the campaign's event. It returns the ledger entries so the caller can
+// publish them once the transaction commits.
+func (s *volunteerService) createGratisOrder(ctx context.Context, eventID *string, products []productSnapshot) (string, []repository.InventoryLedgerCreateParams, error) {
+	ord, err := s.orders.Create(ctx, 0, order.StatusPaid, order.OriginShop, eventID, nil, nil, nil, nil, nil, nil)
 	if err != nil {
-		return "", fmt.Errorf("create order: %w", err)
+		return "", nil, fmt.Errorf("create order: %w", err)
 	}
 
+	var entries []repository.InventoryLedgerCreateParams
 	for _, p := range products {
-		if _, err := s.lines.Create(ctx, ord.ID, orderline.LineTypeSimple, p.ID, truncatedTitle(p.Name), p.Quantity, 0, nil, nil, nil); err != nil {
-			return "", fmt.Errorf("create order line: %w", err)
+		line, err := s.lines.Create(ctx, ord.ID, orderline.LineTypeSimple, p.ID, truncatedTitle(p.Name), p.Quantity, 0, nil, nil, nil)
+		if err != nil {
+			return "", nil, fmt.Errorf("create order line: %w", err)
+		}
+		if p.Simple {
+			entries = append(entries, repository.InventoryLedgerCreateParams{
+				ProductID:   p.ID,
+				Delta:       -p.Quantity,
+				Reason:      inventoryledger.ReasonSale,
+				OrderID:     &ord.ID,
+				OrderLineID: &line.ID,
+				EventID:     eventID,
+			})
+		}
+	}
+	if len(entries) > 0 {
+		if _, err := s.inventoryRepo.CreateMany(ctx, entries); err != nil {
+			return "", nil, fmt.Errorf("book inventory: %w", err)
 		}
 	}
 
 	if _, err := s.payments.Create(ctx, ord.ID, orderpayment.MethodGRATIS_STAFF, 0, time.Now(), nil); err != nil {
-		return "", fmt.Errorf("create payment: %w", err)
+		return "", nil, fmt.Errorf("create payment: %w", err)
 	}
-	return ord.ID, nil
+	return ord.ID, entries, nil
 }
 
 func truncatedTitle(s string) string {
@@ -226,8 +331,8 @@ func truncatedTitle(s string) string {
 	return s
 }
 
-func (s *volunteerService) ListCampaigns(ctx context.Context) ([]VolunteerCampaignSummary, error) {
-	campaigns, err := s.campaigns.List(ctx)
+func (s *volunteerService) ListCampaigns(ctx context.Context, eventID *string) ([]VolunteerCampaignSummary, error) {
+	campaigns, err := s.campaigns.List(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
@@ -245,7 +350,7 @@ func (s *volunteerService) ListCampaigns(ctx context.Context) ([]VolunteerCampai
 func (s *volunteerService) GetCampaign(ctx context.Context, id string) (*VolunteerCampaignDetail, error) {
 	campaign, err := s.campaigns.GetByID(ctx, id)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrVolunteerCampaignNotFound
 		}
 		return nil, err
@@ -289,7 +394,7 @@ func (s *volunteerService) UpdateCampaign(ctx context.Context, id string, input
 
 	existing, err := s.campaigns.GetByID(txCtx, id)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrVolunteerCampaignNotFound
 		}
 		return nil, err
@@ -297,7 +402,7 @@ func (s *volunteerService) UpdateCampaign(ctx context.Context, id string, input
 
 	campaign, err := s.campaigns.Update(txCtx, id, input.Name, existing.AccessCode, input.ValidFrom, input.ValidUntil, input.Status)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrVolunteerCampaignNotFound
 		}
 		return nil, err
@@ -330,7 +435,7 @@ func (s *volunteerService) UpdateCampaign(ctx context.Context, id string, input
 
 func (s *volunteerService) EndCampaign(ctx context.Context, id string) error {
 	if err := s.campaigns.SetStatus(ctx, id, volunteercampaign.StatusEnded); err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return ErrVolunteerCampaignNotFound
 		}
 		return err
@@ -345,7 +450,7 @@ func (s *volunteerService) RotateClaimToken(ctx context.Context, id string) (str
 func (s *volunteerService) VerifyAccess(ctx context.Context, token string, code string) (string, error) {
 	campaign, err := s.campaigns.GetByClaimToken(ctx, token)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return "", ErrVolunteerCampaignNotFound
 		}
 		return "", err
@@ -353,8 +458,10 @@ func (s *volunteerService) VerifyAccess(ctx context.Context, token string, code
 	if campaign.Status != volunteercampaign.StatusActive {
 		return "", ErrVolunteerCampaignInactive
 	}
-	if !campaignWithinValidity(campaign, time.Now()) {
-		return "", ErrVolunteerCampaignOutsideValid
+	// Meal windows do not apply here: volunteers may open the claim page ahead
+	// of time to see when the next window starts.
+	if _, err := campaignWithinValidity(campaign, nil, time.Now()); err != nil {
+		return "", err
 	}
 	if code != campaign.AccessCode {
 		return "", ErrVolunteerAccessCodeInvalid
@@ -365,7 +472,7 @@ func (s *volunteerService) VerifyAccess(ctx context.Context, token string, code
 func (s *volunteerService) GetClaimView(ctx context.Context, token string) (*VolunteerClaimView, error) {
 	campaign, err := s.campaigns.GetByClaimToken(ctx, token)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrVolunteerCampaignNotFound
 		}
 		return nil, err
@@ -374,104 +481,240 @@ func (s *volunteerService) GetClaimView(ctx context.Context, token string) (*Vol
 	if err != nil {
 		return nil, err
 	}
+	groups, err := s.campaigns.ListChoiceGroups(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.campaigns.ListWindows(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
 	return &VolunteerClaimView{
-		Campaign:  campaign,
-		Products:  campaignProductsToViews(cps),
-		QRPayload: BuildQRPayload(campaign.ClaimToken),
+		Campaign:     campaign,
+		Products:     campaignProductsToViews(cps),
+		ChoiceGroups: choiceGroupsToViews(groups),
+		QRPayload:    s.qr.CampaignPayload(campaign),
+		Windows:      windowsToViews(windows),
+		NextWindow:   nextWindow(campaign, windows, time.Now()),
 	}, nil
 }
 
-func (s *volunteerService) RedeemSharedQR(ctx context.Context, claimToken string, stationID string, idempotencyKey string) (*VolunteerRedemptionResult, error) {
+func (s *volunteerService) RedeemSharedQR(ctx context.Context, claimToken string, stationID string, idempotencyKey string, choices map[string]string) (*VolunteerRedemptionResult, error) {
 	campaign, err := s.campaigns.GetByClaimToken(ctx, claimToken)
 	if err != nil {
-		if ent.IsNotFound(err) {
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrVolunteerCampaignNotFound
 		}
 		return nil, err
 	}
 
-	if idempotencyKey != "" {
-		if prev, err := s.redemptions.GetByIdempotencyKey(ctx, campaign.ID, idempotencyKey); err == nil && prev != nil {
-			stationResp, redeemErr := s.stations.RedeemAssigned(ctx, stationID, prev.OrderID, idempotencyKey)
-			if redeemErr != nil {
-				return nil, redeemErr
+	if orderID, stationResp, ok, err := s.replayRedemption(ctx, campaign.ID, stationID, idempotencyKey); err != nil {
+		return nil, err
+	} else if ok {
+		return &VolunteerRedemptionResult{
+			OrderID:         orderID,
+			RedemptionCount: campaign.RedemptionCount,
+			MaxRedemptions:  campaign.MaxRedemptions,
+			StationResult:   stationResp,
+		}, nil
+	}
+
+	now := time.Now()
+	products, window, err := s.redeemableProducts(ctx, campaign, stationID, choices, now)
+	if err != nil {
+		return nil, err
+	}
+
+	orderID, stationResp, err := s.issueRedemption(ctx, campaign, nil, products, stationID, idempotencyKey,
+		func(txCtx context.Context) error {
+			incremented, err := s.campaigns.IncrementRedemptionAtomic(txCtx, campaign.ID)
+			if err != nil {
+				return err
 			}
-			return &VolunteerRedemptionResult{
-				OrderID:         prev.OrderID,
-				RedemptionCount: campaign.RedemptionCount,
-				MaxRedemptions:  campaign.MaxRedemptions,
-				StationResult:   stationResp,
-			}, nil
+			if !incremented {
+				return ErrVolunteerMaxRedemptionsReached
+			}
+			return s.checkScheduleLimits(txCtx, campaign, window, nil, now)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return &VolunteerRedemptionResult{
+		OrderID:         orderID,
+		RedemptionCount: campaign.RedemptionCount + 1,
+		MaxRedemptions:  campaign.MaxRedemptions,
+		StationResult:   stationResp,
+	}, nil
+}
+
+// RedeemPersonalQR redeems a volunteer's personal token. The token's own limit
+// applies; the campaign's shared-QR counter is left alone.
+func (s *volunteerService) RedeemPersonalQR(ctx context.Context, token string, stationID string, idempotencyKey string, choices map[string]string) (*VolunteerRedemptionResult, error) {
+	vt, err := s.tokens.GetByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerTokenNotFound
+		}
+		return nil, err
+	}
+	campaign, err := s.campaigns.GetByID(ctx, vt.CampaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
 		}
+		return nil, err
 	}
 
+	if orderID, stationResp, ok, err := s.replayRedemption(ctx, campaign.ID, stationID, idempotencyKey); err != nil {
+		return nil, err
+	} else if ok {
+		return &VolunteerRedemptionResult{
+			OrderID:         orderID,
+			RedemptionCount: vt.RedemptionCount,
+			MaxRedemptions:  vt.MaxRedemptions,
+			HolderName:      vt.Name,
+			StationResult:   stationResp,
+		}, nil
+	}
+
+	now := time.Now()
+	products, window, err := s.redeemableProducts(ctx, campaign, stationID, choices, now)
+	if err != nil {
+		return nil, err
+	}
+
+	orderID, stationResp, err := s.issueRedemption(ctx, campaign, &vt.ID, products, stationID, idempotencyKey,
+		func(txCtx context.Context) error {
+			incremented, err := s.tokens.IncrementRedemptionAtomic(txCtx, vt.ID)
+			if err != nil {
+				return err
+			}
+			if !incremented {
+				return ErrVolunteerPersonalLimitReached
+			}
+			return s.checkScheduleLimits(txCtx, campaign, window, &vt.ID, now)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return &VolunteerRedemptionResult{
+		OrderID:         orderID,
+		RedemptionCount: vt.RedemptionCount + 1,
+		MaxRedemptions:  vt.MaxRedemptions,
+		HolderName:      vt.Name,
+		StationResult:   stationResp,
+	}, nil
+}
+
+// replayRedemption re-runs the station step for a redemption that was already
+// recorded under idempotencyKey, e.g. when the station retried after a timeout.
+func (s *volunteerService) replayRedemption(ctx context.Context, campaignID, stationID, idempotencyKey string) (string, map[string]any, bool, error) {
+	if idempotencyKey == "" {
+		return "", nil, false, nil
+	}
+	prev, err := s.redemptions.GetByIdempotencyKey(ctx, campaignID, idempotencyKey)
+	if err != nil || prev == nil {
+		return "", nil, false, nil
+	}
+	stationResp, err := s.stations.RedeemAssigned(ctx, stationID, prev.OrderID, idempotencyKey)
+	if err != nil {
+		return "", nil, false, err
+	}
+	return prev.OrderID, stationResp, true, nil
+}
+
+// redeemableProducts checks that the campaign can be redeemed at now at this
+// station and snapshots the products for the gratis order: the fixed products
+// the station serves plus one pick per choice group (see resolveChoices). It also returns the
+// running meal window, if the campaign has windows.
+func (s *volunteerService) redeemableProducts(ctx context.Context, campaign *ent.VolunteerCampaign, stationID string, choices map[string]string, now time.Time) ([]productSnapshot, *activeWindow, error) {
 	if campaign.Status != volunteercampaign.StatusActive {
-		return nil, ErrVolunteerCampaignInactive
+		return nil, nil, ErrVolunteerCampaignInactive
 	}
-	if !campaignWithinValidity(campaign, time.Now()) {
-		return nil, ErrVolunteerCampaignOutsideValid
+	windows, err := s.campaigns.ListWindows(ctx, campaign.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	window, err := campaignWithinValidity(campaign, windows, now)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	cps, err := s.campaigns.ListProducts(ctx, campaign.ID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if len(cps) == 0 {
-		return nil, ErrVolunteerCampaignHasNoProducts
+	groups, err := s.campaigns.ListChoiceGroups(ctx, campaign.ID)
+	if err != nil {
+		return nil, nil, err
 	}
-	products := make([]productSnapshot, 0, len(cps))
-	campaignProductIDs := make(map[string]struct{}, len(cps))
-	for _, cp := range cps {
-		p, _ := cp.Edges.ProductOrErr()
-		name := ""
-		if p != nil {
-			name = p.Name
-		}
-		qty := cp.Quantity
-		if qty <= 0 {
-			qty = 1
-		}
-		products = append(products, productSnapshot{
-			ID:       cp.ProductID,
-			Name:     name,
-			Quantity: qty,
-		})
-		campaignProductIDs[cp.ProductID] = struct{}{}
+	if len(cps) == 0 && len(groups) == 0 {
+		return nil, nil, ErrVolunteerCampaignHasNoProducts
 	}
 
 	stationProductIDs, err := s.stations.ListStationProductIDs(ctx, stationID)
 	if err != nil {
-		return nil, fmt.Errorf("list station products: %w", err)
+		return nil, nil, fmt.Errorf("list station products: %w", err)
 	}
-	hasMatch := false
+	served := make(map[string]struct{}, len(stationProductIDs))
 	for _, pid := range stationProductIDs {
-		if _, ok := campaignProductIDs[pid]; ok {
-			hasMatch = true
-			break
-		}
+		served[pid] = struct{}{}
 	}
-	if !hasMatch {
-		return nil, ErrVolunteerStationNoMatchingProducts
+
+	// Like the choices, the fixed products go into the gratis order only when
+	// this station hands them out; the others stay in stock.
+	products := make([]productSnapshot, 0, len(cps)+len(groups))
+	for _, cp := range cps {
+		if _, ok := served[cp.ProductID]; !ok {
+			continue
+		}
+		snap := productSnapshot{ID: cp.ProductID, Quantity: cp.Quantity}
+		if p, _ := cp.Edges.ProductOrErr(); p != nil {
+			snap.Name = p.Name
+			snap.Simple = p.Type == product.TypeSimple
+		}
+		if snap.Quantity <= 0 {
+			snap.Quantity = 1
+		}
+		products = append(products, snap)
 	}
 
-	incremented, err := s.campaigns.IncrementRedemptionAtomic(ctx, campaign.ID)
+	chosen, err := resolveChoices(groups, served, choices)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if !incremented {
-		return nil, ErrVolunteerMaxRedemptionsReached
+	if len(products) == 0 && len(chosen) == 0 {
+		return nil, nil, ErrVolunteerStationNoMatchingProducts
 	}
+	return append(products, chosen...), window, nil
+}
 
+// issueRedemption reserves a redemption via reserve, creates the gratis order
+// and records it in one transaction, then redeems the order at the station.
+func (s *volunteerService) issueRedemption(
+	ctx context.Context,
+	campaign *ent.VolunteerCampaign,
+	tokenID *string,
+	products []productSnapshot,
+	stationID, idempotencyKey string,
+	reserve func(txCtx context.Context) error,
+) (string, map[string]any, error) {
 	tx, err := s.client.Tx(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("begin tx: %w", err)
+		return "", nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 	txCtx := repository.ContextWithClient(ctx, tx.Client())
 
-	orderID, err := s.createGratisOrder(txCtx, products)
+	if err := reserve(txCtx); err != nil {
+		return "", nil, err
+	}
+
+	orderID, stock, err := s.createGratisOrder(txCtx, campaign.EventID, products)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	var idemPtr *string
@@ -482,35 +725,22 @@ func (s *volunteerService) RedeemSharedQR(ctx context.Context, claimToken string
 	if stationID != "" {
 		stationPtr = &stationID
 	}
-	if _, err := s.redemptions.Create(txCtx, campaign.ID, orderID, stationPtr, idemPtr); err != nil {
-		return nil, fmt.Errorf("record redemption: %w", err)
+	if _, err := s.redemptions.Create(txCtx, campaign.ID, orderID, tokenID, stationPtr, idemPtr); err != nil {
+		return "", nil, fmt.Errorf("record redemption: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit: %w", err)
+		return "", nil, fmt.Errorf("commit: %w", err)
+	}
+	if len(stock) > 0 {
+		publishStockUpdates(ctx, s.inventoryRepo, s.inventoryHub, stock)
 	}
 
 	stationResp, err := s.stations.RedeemAssigned(ctx, stationID, orderID, idempotencyKey)
 	if err != nil {
-		return nil, err
-	}
-
-	return &VolunteerRedemptionResult{
-		OrderID:         orderID,
-		RedemptionCount: campaign.RedemptionCount + 1,
-		MaxRedemptions:  campaign.MaxRedemptions,
-		StationResult:   stationResp,
-	}, nil
-}
-
-func campaignWithinValidity(c *ent.VolunteerCampaign, now time.Time) bool {
-	if c.ValidFrom != nil && now.Before(*c.ValidFrom) {
-		return false
-	}
-	if c.ValidUntil != nil && now.After(*c.ValidUntil) {
-		return false
+		return "", nil, err
 	}
-	return true
+	return orderID, stationResp, nil
 }
 
 func campaignProductsToViews(cps []*ent.VolunteerCampaignProduct) []VolunteerCampaignProductView {
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/generated/ent"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+)
+
+const (
+	maxVolunteerChoiceGroups  = 10
+	maxVolunteerChoiceOptions = 20
+)
+
+// VolunteerChoiceGroup is a "pick one of these" slot of a campaign, e.g. one
+// main out of three. Each redemption gets Quantity of the picked product.
+type VolunteerChoiceGroup struct {
+	ID       string
+	Name     string
+	Quantity int
+	Options  []VolunteerChoiceOption
+}
+
+type VolunteerChoiceOption struct {
+	ProductID    string
+	ProductName  string
+	ProductImage *string
+}
+
+// VolunteerChoiceRequiredError is returned by a redemption when the station
+// serves more than one option of a group and no pick was sent. Groups lists
+// the open groups with only the options this station serves.
+type VolunteerChoiceRequiredError struct {
+	Groups []VolunteerChoiceGroup
+}
+
+func (e VolunteerChoiceRequiredError) Error() string {
+	return "volunteer_choice_required"
+}
+
+func (s *volunteerService) GetChoiceGroups(ctx context.Context, campaignID string) ([]VolunteerChoiceGroup, error) {
+	if _, err := s.campaigns.GetByID(ctx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	groups, err := s.campaigns.ListChoiceGroups(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return choiceGroupsToViews(groups), nil
+}
+
+// SetChoiceGroups replaces the campaign's choice groups. A campaign needs at
+// least one fixed product or choice group, so clearing the groups of a
+// campaign without fixed products is rejected.
+func (s *volunteerService) SetChoiceGroups(ctx context.Context, campaignID string, in []repository.VolunteerChoiceGroupInput) ([]VolunteerChoiceGroup, error) {
+	items, err := normalizeChoiceGroups(in)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	if _, err := s.campaigns.GetByID(txCtx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	if len(items) == 0 {
+		cps, err := s.campaigns.ListProducts(txCtx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		if len(cps) == 0 {
+			return nil, ErrVolunteerCampaignHasNoProducts
+		}
+	}
+	if err := s.campaigns.ReplaceChoiceGroups(txCtx, campaignID, items); err != nil {
+		return nil, err
+	}
+	groups, err := s.campaigns.ListChoiceGroups(txCtx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return choiceGroupsToViews(groups), nil
+}
+
+// normalizeChoiceGroups trims names, defaults the quantity to 1 and rejects
+// empty groups or options listed twice.
+func normalizeChoiceGroups(in []repository.VolunteerChoiceGroupInput) ([]repository.VolunteerChoiceGroupInput, error) {
+	if len(in) > maxVolunteerChoiceGroups {
+		return nil, fmt.Errorf("%w: at most %d groups", ErrVolunteerChoiceGroupsInvalid, maxVolunteerChoiceGroups)
+	}
+	out := make([]repository.VolunteerChoiceGroupInput, 0, len(in))
+	for i, g := range in {
+		name := strings.TrimSpace(g.Name)
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("%w: group %d: name is required", ErrVolunteerChoiceGroupsInvalid, i+1)
+		case utf8.RuneCountInString(name) > 50:
+			return nil, fmt.Errorf("%w: group %d: name is longer than 50 characters", ErrVolunteerChoiceGroupsInvalid, i+1)
+		case g.Quantity < 0:
+			return nil, fmt.Errorf("%w: group %d: quantity must be positive", ErrVolunteerChoiceGroupsInvalid, i+1)
+		case len(g.ProductIDs) == 0:
+			return nil, fmt.Errorf("%w: group %d: at least one product is required", ErrVolunteerChoiceGroupsInvalid, i+1)
+		case len(g.ProductIDs) > maxVolunteerChoiceOptions:
+			return nil, fmt.Errorf("%w: group %d: at most %d products", ErrVolunteerChoiceGroupsInvalid, i+1, maxVolunteerChoiceOptions)
+		}
+		seen := make(map[string]struct{}, len(g.ProductIDs))
+		for _, pid := range g.ProductIDs {
+			if _, dup := seen[pid]; dup {
+				return nil, fmt.Errorf("%w: group %d: product listed twice", ErrVolunteerChoiceGroupsInvalid, i+1)
+			}
+			seen[pid] = struct{}{}
+		}
+		qty := g.Quantity
+		if qty == 0 {
+			qty = 1
+		}
+		out = append(out, repository.VolunteerChoiceGroupInput{Name: name, Quantity: qty, ProductIDs: g.ProductIDs})
+	}
+	return out, nil
+}
+
+// resolveChoices turns the station's picks (group ID → product ID) into
+// order snapshots. Only options the station serves count: a group the station
+// serves nothing of is left out, a group with a single served option is picked
+// automatically, and any other group without a pick is reported back in a
+// VolunteerChoiceRequiredError.
+func resolveChoices(groups []*ent.VolunteerCampaignChoiceGroup, served map[string]struct{}, picks map[string]string) ([]productSnapshot, error) {
+	known := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		known[g.ID] = struct{}{}
+	}
+	for gid := range picks {
+		if _, ok := known[gid]; !ok {
+			return nil, ErrVolunteerChoiceInvalid
+		}
+	}
+
+	var out []productSnapshot
+	var open []VolunteerChoiceGroup
+	for _, g := range groups {
+		var offered []*ent.VolunteerCampaignChoiceOption
+		for _, o := range g.Edges.Options {
+			if _, ok := served[o.ProductID]; ok {
+				offered = append(offered, o)
+			}
+		}
+		if len(offered) == 0 {
+			continue
+		}
+
+		var picked *ent.VolunteerCampaignChoiceOption
+		if pid, ok := picks[g.ID]; ok {
+			for _, o := range offered {
+				if o.ProductID == pid {
+					picked = o
+					break
+				}
+			}
+			if picked == nil {
+				return nil, ErrVolunteerChoiceInvalid
+			}
+		} else if len(offered) == 1 {
+			picked = offered[0]
+		}
+
+		if picked == nil {
+			view := choiceGroupToView(g)
+			view.Options = choiceOptionsToViews(offered)
+			open = append(open, view)
+			continue
+		}
+		out = append(out, optionSnapshot(picked, g.Quantity))
+	}
+	if len(open) > 0 {
+		return nil, VolunteerChoiceRequiredError{Groups: open}
+	}
+	return out, nil
+}
+
+func optionSnapshot(o *ent.VolunteerCampaignChoiceOption, qty int) productSnapshot {
+	snap := productSnapshot{ID: o.ProductID, Quantity: qty}
+	if p, _ := o.Edges.ProductOrErr(); p != nil {
+		snap.Name = p.Name
+		snap.Simple = p.Type == product.TypeSimple
+	}
+	return snap
+}
+
+func choiceGroupsToViews(groups []*ent.VolunteerCampaignChoiceGroup) []VolunteerChoiceGroup {
+	out := make([]VolunteerChoiceGroup, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, choiceGroupToView(g))
+	}
+	return out
+}
+
+func choiceGroupToView(g *ent.VolunteerCampaignChoiceGroup) VolunteerChoiceGroup {
+	return VolunteerChoiceGroup{
+		ID:       g.ID,
+		Name:     g.Name,
+		Quantity: g.Quantity,
+		Options:  choiceOptionsToViews(g.Edges.Options),
+	}
+}
+
+func choiceOptionsToViews(options []*ent.VolunteerCampaignChoiceOption) []VolunteerChoiceOption {
+	out := make([]VolunteerChoiceOption, 0, len(options))
+	for _, o := range options {
+		view := VolunteerChoiceOption{ProductID: o.ProductID}
+		if p, _ := o.Edges.ProductOrErr(); p != nil {
+			view.ProductName = p.Name
+			view.ProductImage = p.Image
+		}
+		out = append(out, view)
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/roster"
+)
+
+// VolunteerImportResult summarises a roster import. Rows that were invalid or
+// already on the roster are reported in Errors and skipped.
+type VolunteerImportResult struct {
+	Imported []*ent.VolunteerToken
+	Skipped  int
+	Errors   []roster.LineError
+}
+
+// ImportVolunteers issues a personal token for every volunteer on the CSV
+// roster. Names already on the campaign's roster are skipped, so re-importing
+// an updated export only adds the new people.
+func (s *volunteerService) ImportVolunteers(ctx context.Context, campaignID string, csv io.Reader, defaultMax int) (*VolunteerImportResult, error) {
+	if _, err := s.campaigns.GetByID(ctx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	if defaultMax < 1 {
+		defaultMax = 1
+	}
+
+	entries, lineErrs, err := roster.Parse(csv, defaultMax)
+	if err != nil {
+		if errors.Is(err, roster.ErrEmpty) {
+			return nil, ErrVolunteerRosterEmpty
+		}
+		return nil, errors.Join(ErrVolunteerRosterInvalid, err)
+	}
+
+	existing, err := s.tokens.ListByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]struct{}, len(existing))
+	for _, t := range existing {
+		known[strings.ToLower(t.Name)] = struct{}{}
+	}
+
+	result := &VolunteerImportResult{Errors: lineErrs}
+	items := make([]repository.VolunteerTokenInput, 0, len(entries))
+	lines := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if _, ok := known[strings.ToLower(e.Name)]; ok {
+			result.Skipped++
+			result.Errors = append(result.Errors, roster.LineError{Line: e.Line, Message: "already on the roster"})
+			continue
+		}
+		var email *string
+		if e.Email != "" {
+			email = &e.Email
+		}
+		items = append(items, repository.VolunteerTokenInput{
+			Name:           e.Name,
+			Email:          email,
+			MaxRedemptions: e.MaxRedemptions,
+		})
+		lines = append(lines, e.Line)
+	}
+	result.Skipped += len(lineErrs)
+
+	created, err := s.tokens.CreateMany(ctx, campaignID, items)
+	if err != nil {
+		return nil, err
+	}
+	result.Imported = created
+
+	// Names a concurrent import added first were not created by this one.
+	inserted := make(map[string]struct{}, len(created))
+	for _, t := range created {
+		inserted[strings.ToLower(t.Name)] = struct{}{}
+	}
+	for i, it := range items {
+		if _, ok := inserted[strings.ToLower(it.Name)]; !ok {
+			result.Skipped++
+			result.Errors = append(result.Errors, roster.LineError{Line: lines[i], Message: "already on the roster"})
+		}
+	}
+	return result, nil
+}
+
+func (s *volunteerService) ListVolunteers(ctx context.Context, campaignID string) ([]*ent.VolunteerToken, error) {
+	if _, err := s.campaigns.GetByID(ctx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	return s.tokens.ListByCampaign(ctx, campaignID)
+}
+
+func (s *volunteerService) DeleteVolunteer(ctx context.Context, campaignID, volunteerID string) error {
+	if err := s.tokens.Delete(ctx, campaignID, volunteerID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrVolunteerTokenNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/tz"
+)
+
+// nextWindowHorizonDays bounds the search for the next meal window shown on
+// the claim page.
+const nextWindowHorizonDays = 14
+
+// VolunteerWindow is a recurring daily meal window in Europe/Zurich time.
+type VolunteerWindow struct {
+	ID             string
+	Label          string
+	Weekdays       schedule.Weekdays
+	StartMinute    int
+	EndMinute      int
+	MaxRedemptions *int
+	MaxPerPerson   *int
+}
+
+// VolunteerSchedule holds a campaign's meal windows and per-day limits. The
+// Max* limits count shared-QR redemptions across everyone, the MaxPerPerson*
+// limits each personal token separately. Nil means unlimited.
+type VolunteerSchedule struct {
+	MaxRedemptionsPerDay *int
+	MaxPerPersonPerDay   *int
+	Windows              []VolunteerWindow
+}
+
+type VolunteerWindowOccurrence struct {
+	Label  string
+	Start  time.Time
+	End    time.Time
+	Active bool
+}
+
+// activeWindow is the window occurrence a redemption falls into.
+type activeWindow struct {
+	window *ent.VolunteerCampaignWindow
+	occ    schedule.Occurrence
+}
+
+func (s *volunteerService) GetSchedule(ctx context.Context, campaignID string) (*VolunteerSchedule, error) {
+	campaign, err := s.campaigns.GetByID(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	windows, err := s.campaigns.ListWindows(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return &VolunteerSchedule{
+		MaxRedemptionsPerDay: campaign.MaxRedemptionsPerDay,
+		MaxPerPersonPerDay:   campaign.MaxPerPersonPerDay,
+		Windows:              windowsToViews(windows),
+	}, nil
+}
+
+// SetSchedule replaces the campaign's meal windows and per-day limits.
+func (s *volunteerService) SetSchedule(ctx context.Context, campaignID string, in VolunteerSchedule) (*VolunteerSchedule, error) {
+	if !positiveOrNil(in.MaxRedemptionsPerDay) || !positiveOrNil(in.MaxPerPersonPerDay) {
+		return nil, fmt.Errorf("%w: limits must be positive", ErrVolunteerScheduleInvalid)
+	}
+	items := make([]repository.VolunteerCampaignWindowInput, 0, len(in.Windows))
+	for i, w := range in.Windows {
+		sw := schedule.Window{Weekdays: w.Weekdays, Start: w.StartMinute, End: w.EndMinute}
+		if err := sw.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: window %d: %w", ErrVolunteerScheduleInvalid, i+1, err)
+		}
+		if !positiveOrNil(w.MaxRedemptions) || !positiveOrNil(w.MaxPerPerson) {
+			return nil, fmt.Errorf("%w: window %d: limits must be positive", ErrVolunteerScheduleInvalid, i+1)
+		}
+		if utf8.RuneCountInString(w.Label) > 50 {
+			return nil, fmt.Errorf("%w: window %d: label is longer than 50 characters", ErrVolunteerScheduleInvalid, i+1)
+		}
+		items = append(items, repository.VolunteerCampaignWindowInput{
+			Label:          w.Label,
+			Weekdays:       int(w.Weekdays),
+			StartMinute:    w.StartMinute,
+			EndMinute:      w.EndMinute,
+			MaxRedemptions: w.MaxRedemptions,
+			MaxPerPerson:   w.MaxPerPerson,
+		})
+	}
+
+	if _, err := s.campaigns.GetByID(ctx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	if err := s.campaigns.SetDailyLimits(txCtx, campaignID, in.MaxRedemptionsPerDay, in.MaxPerPersonPerDay); err != nil {
+		return nil, err
+	}
+	if err := s.campaigns.ReplaceWindows(txCtx, campaignID, items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetSchedule(ctx, campaignID)
+}
+
+// campaignWithinValidity checks the campaign's validity range and, when it has
+// meal windows, that now falls into one of them. It returns the running
+// window, or nil for campaigns without windows.
+func campaignWithinValidity(c *ent.VolunteerCampaign, windows []*ent.VolunteerCampaignWindow, now time.Time) (*activeWindow, error) {
+	if c.ValidFrom != nil && now.Before(*c.ValidFrom) {
+		return nil, ErrVolunteerCampaignOutsideValid
+	}
+	if c.ValidUntil != nil && now.After(*c.ValidUntil) {
+		return nil, ErrVolunteerCampaignOutsideValid
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	occ, ok := schedule.Active(scheduleWindows(windows), now, tz.Zurich())
+	if !ok {
+		return nil, ErrVolunteerCampaignOutsideWindow
+	}
+	return &activeWindow{window: windows[occ.Index], occ: occ}, nil
+}
+
+// checkScheduleLimits enforces the per-window and per-day limits for the
+// shared QR (tokenID nil) or one personal token. It runs inside the
+// redemption tx after the counter increment, whose row lock on the campaign
+// or token serialises concurrent redemptions of the same code.
+func (s *volunteerService) checkScheduleLimits(ctx context.Context, c *ent.VolunteerCampaign, window *activeWindow, tokenID *string, now time.Time) error {
+	perDay := c.MaxRedemptionsPerDay
+	if tokenID != nil {
+		perDay = c.MaxPerPersonPerDay
+	}
+	if window != nil {
+		perWindow := window.window.MaxRedemptions
+		if tokenID != nil {
+			perWindow = window.window.MaxPerPerson
+		}
+		if perWindow != nil {
+			n, err := s.redemptions.CountBetween(ctx, c.ID, tokenID, window.occ.Start, window.occ.End)
+			if err != nil {
+				return err
+			}
+			if n >= *perWindow {
+				return ErrVolunteerWindowLimitReached
+			}
+		}
+	}
+	if perDay != nil {
+		from, to := schedule.DayBounds(now, tz.Zurich())
+		n, err := s.redemptions.CountBetween(ctx, c.ID, tokenID, from, to)
+		if err != nil {
+			return err
+		}
+		if n >= *perDay {
+			return ErrVolunteerDailyLimitReached
+		}
+	}
+	return nil
+}
+
+// nextWindow returns the running or next meal window inside the campaign's
+// validity range.
+func nextWindow(c *ent.VolunteerCampaign, windows []*ent.VolunteerCampaignWindow, now time.Time) *VolunteerWindowOccurrence {
+	if len(windows) == 0 {
+		return nil
+	}
+	from := now
+	if c.ValidFrom != nil && c.ValidFrom.After(from) {
+		from = *c.ValidFrom
+	}
+	occ, ok := schedule.Next(scheduleWindows(windows), from, tz.Zurich(), nextWindowHorizonDays)
+	if !ok || (c.ValidUntil != nil && !occ.Start.Before(*c.ValidUntil)) {
+		return nil
+	}
+	return &VolunteerWindowOccurrence{
+		Label:  windows[occ.Index].Label,
+		Start:  occ.Start,
+		End:    occ.End,
+		Active: !now.Before(occ.Start) && now.Before(occ.End),
+	}
+}
+
+func scheduleWindows(windows []*ent.VolunteerCampaignWindow) []schedule.Window {
+	out := make([]schedule.Window, len(windows))
+	for i, w := range windows {
+		out[i] = schedule.Window{Weekdays: schedule.Weekdays(w.Weekdays), Start: w.StartMinute, End: w.EndMinute}
+	}
+	return out
+}
+
+func windowsToViews(windows []*ent.VolunteerCampaignWindow) []VolunteerWindow {
+	out := make([]VolunteerWindow, 0, len(windows))
+	for _, w := range windows {
+		out = append(out, VolunteerWindow{
+			ID:             w.ID,
+			Label:          w.Label,
+			Weekdays:       schedule.Weekdays(w.Weekdays),
+			StartMinute:    w.StartMinute,
+			EndMinute:      w.EndMinute,
+			MaxRedemptions: w.MaxRedemptions,
+			MaxPerPerson:   w.MaxPerPerson,
+		})
+	}
+	return out
+}
+
+func positiveOrNil(n *int) bool {
+	return n == nil || *n > 0
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/generated/ent"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/tz"
+)
+
+// MaxNumberedSlipsPerIssue caps a single IssueNumberedSlips call.
+const MaxNumberedSlipsPerIssue = 500
+
+var slipWeekdayNames = [7]string{"Mo", "Di", "Mi", "Do", "Fr", "Sa", "So"}
+
+// VolunteerSlipContent is what the printed slips of a campaign show besides
+// the QR code.
+type VolunteerSlipContent struct {
+	Campaign     *ent.VolunteerCampaign
+	Products     []VolunteerCampaignProductView
+	ChoiceGroups []VolunteerChoiceGroup
+	// Validity holds the lines printed at the bottom of each slip: the
+	// validity range and the meal windows.
+	Validity []string
+}
+
+// VolunteerSlipSummary counts the numbered single-use slips of a campaign.
+type VolunteerSlipSummary struct {
+	Issued     int
+	Redeemed   int
+	LastSerial int
+}
+
+func (s *volunteerService) GetSlipContent(ctx context.Context, campaignID string) (*VolunteerSlipContent, error) {
+	campaign, err := s.campaigns.GetByID(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	cps, err := s.campaigns.ListProducts(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.campaigns.ListChoiceGroups(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.campaigns.ListWindows(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return &VolunteerSlipContent{
+		Campaign:     campaign,
+		Products:     campaignProductsToViews(cps),
+		ChoiceGroups: choiceGroupsToViews(groups),
+		Validity:     slipValidityLines(campaign, windowsToViews(windows), tz.Zurich()),
+	}, nil
+}
+
+// IssueNumberedSlips creates count anonymous single-use tokens, numbered on
+// from the campaign's last slip.
+func (s *volunteerService) IssueNumberedSlips(ctx context.Context, campaignID string, count int) ([]*ent.VolunteerToken, error) {
+	if count < 1 || count > MaxNumberedSlipsPerIssue {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrVolunteerSlipCountInvalid, MaxNumberedSlipsPerIssue)
+	}
+
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := repository.ContextWithClient(ctx, tx.Client())
+
+	if _, err := s.campaigns.GetByID(txCtx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	tokens, err := s.tokens.CreateNumbered(txCtx, campaignID, count)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return tokens, nil
+}
+
+// ListNumberedSlips returns the numbered slips from..to; zero bounds are open.
+func (s *volunteerService) ListNumberedSlips(ctx context.Context, campaignID string, from, to int) ([]*ent.VolunteerToken, error) {
+	if _, err := s.campaigns.GetByID(ctx, campaignID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	return s.tokens.ListNumbered(ctx, campaignID, from, to)
+}
+
+func (s *volunteerService) GetSlipSummary(ctx context.Context, campaignID string) (*VolunteerSlipSummary, error) {
+	tokens, err := s.ListNumberedSlips(ctx, campaignID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	summary := &VolunteerSlipSummary{Issued: len(tokens)}
+	for _, t := range tokens {
+		if t.RedemptionCount > 0 {
+			summary.Redeemed++
+		}
+		if t.Serial != nil && *t.Serial > summary.LastSerial {
+			summary.LastSerial = *t.Serial
+		}
+	}
+	return summary, nil
+}
+
+// SetLogo stores the slip logo URL (nil clears it) and returns the previous
+// one, so the caller can delete the old blob.
+func (s *volunteerService) SetLogo(ctx context.Context, campaignID string, logoURL *string) (*string, error) {
+	campaign, err := s.campaigns.GetByID(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVolunteerCampaignNotFound
+		}
+		return nil, err
+	}
+	if err := s.campaigns.SetLogoURL(ctx, campaignID, logoURL); err != nil {
+		return nil, err
+	}
+	return campaign.LogoURL, nil
+}
+
+// slipValidityLines describes when a slip can be redeemed, e.g.
+// "Gültig 01.07.2026–05.07.2026" and "Mittag: Mo–Fr 11:30–13:30".
+func slipValidityLines(c *ent.VolunteerCampaign, windows []VolunteerWindow, loc *time.Location) []string {
+	const day = "02.01.2006"
+	var lines []string
+	switch {
+	case c.ValidFrom != nil && c.ValidUntil != nil:
+		from, until := c.ValidFrom.In(loc).Format(day), c.ValidUntil.In(loc).Format(day)
+		if from == until {
+			lines = append(lines, "Gültig am "+from)
+		} else {
+			lines = append(lines, "Gültig "+from+"–"+until)
+		}
+	case c.ValidFrom != nil:
+		lines = append(lines, "Gültig ab "+c.ValidFrom.In(loc).Format(day))
+	case c.ValidUntil != nil:
+		lines = append(lines, "Gültig bis "+c.ValidUntil.In(loc).Format(day))
+	}
+	for _, w := range windows {
+		var b strings.Builder
+		if w.Label != "" {
+			b.WriteString(w.Label)
+			b.WriteString(": ")
+		}
+		if w.Weekdays != schedule.AllWeekdays {
+			b.WriteString(w.Weekdays.Format(slipWeekdayNames))
+			b.WriteString(" ")
+		}
+		b.WriteString(schedule.FormatClock(w.StartMinute))
+		b.WriteString("–")
+		b.WriteString(schedule.FormatClock(w.EndMinute))
+		lines = append(lines, b.String())
+	}
+	return lines
+}
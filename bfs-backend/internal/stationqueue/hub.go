@@ -0,0 +1,32 @@
+package stationqueue
+
+import (
+	"time"
+
+	"backend/internal/pubsub"
+)
+
+type EventType string
+
+const (
+	EventOrderPaid     EventType = "order-paid"
+	EventOrderRedeemed EventType = "order-redeemed"
+	// EventRedemptionUndone puts previously redeemed items back into the queue.
+	EventRedemptionUndone EventType = "redemption-undone"
+)
+
+// Event signals that the station queue for an order may have changed.
+// Subscribers re-read the order's station items rather than trusting a payload.
+type Event struct {
+	Type      EventType `json:"type"`
+	OrderID   string    `json:"orderId"`
+	StationID *string   `json:"stationId,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Hub fans queue events out to the station queue streams.
+type Hub = pubsub.Hub[Event]
+
+func NewHub() *Hub {
+	return pubsub.NewHub[Event]()
+}
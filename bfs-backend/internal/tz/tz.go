@@ -0,0 +1,18 @@
+// Package tz loads the time zone the event runs in. Days, opening hours and
+// printed times are all reckoned in Zurich time.
+package tz
+
+import (
+	"sync"
+	"time"
+)
+
+// Zurich returns Europe/Zurich. Without tzdata it falls back to the server's
+// local zone instead of failing.
+var Zurich = sync.OnceValue(func() *time.Location {
+	loc, err := time.LoadLocation("Europe/Zurich")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+})
@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/allergen"
+	"backend/internal/generated/ent/inventoryledger"
+	"backend/internal/generated/ent/orderline"
+	"backend/internal/generated/ent/product"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProductAllergens(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	products := NewProductSvc(repos)
+	payments := service.NewPaymentService(TestConfig(), repos.Order, repos.OrderLine, repos.OrderPayment, products, nil,
+		nil, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+
+	menus := fixtures.CreateCategory("Menus", 1, true)
+	sides := fixtures.CreateCategory("Beilagen", 2, true)
+	burger := fixtures.CreateProduct("Burger Menu", menus.ID, 1500, product.TypeMenu, nil)
+	fries := fixtures.CreateProduct("Pommes", sides.ID, 400, product.TypeSimple, nil)
+	salad := fixtures.CreateProduct("Salat", sides.ID, 500, product.TypeSimple, nil)
+	fixtures.AddInventory(fries.ID, 50, inventoryledger.ReasonOpeningBalance)
+	fixtures.AddInventory(salad.ID, 50, inventoryledger.ReasonOpeningBalance)
+	sideSlot := fixtures.CreateMenuSlot(burger.ID, "Beilage", 1)
+	fixtures.CreateMenuSlotOption(sideSlot.ID, fries.ID)
+	fixtures.CreateMenuSlotOption(sideSlot.ID, salad.ID)
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		_, err := products.UpdateDietary(ctx, fries.ID, []string{"pollen"}, nil)
+		require.ErrorIs(t, err, allergen.ErrUnknownAllergen)
+		_, err = products.UpdateDietary(ctx, fries.ID, nil, []string{"paleo"})
+		require.ErrorIs(t, err, allergen.ErrUnknownDietary)
+	})
+
+	t.Run("normalizes and keeps the other list", func(t *testing.T) {
+		updated, err := products.UpdateDietary(ctx, salad.ID, []string{"Mustard", "eggs", "mustard"}, []string{"vegetarian"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"eggs", "mustard"}, updated.Allergens)
+
+		updated, err = products.UpdateDietary(ctx, salad.ID, nil, []string{"vegan", "vegetarian"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"eggs", "mustard"}, updated.Allergens)
+		require.Equal(t, []string{"vegetarian", "vegan"}, updated.DietaryTags)
+	})
+
+	t.Run("products are created with their dietary details", func(t *testing.T) {
+		_, err := products.Create(ctx, sides.ID, product.TypeSimple, "Wedges", 450, true, nil, nil, nil,
+			service.ProductDetails{Allergens: []string{"pollen"}})
+		require.ErrorIs(t, err, allergen.ErrUnknownAllergen)
+
+		created, err := products.Create(ctx, sides.ID, product.TypeSimple, "Wedges", 450, true, nil, nil, nil,
+			service.ProductDetails{Allergens: []string{"Celery"}, DietaryTags: []string{"vegan"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"celery"}, created.Allergens)
+		require.Equal(t, []string{"vegan"}, created.DietaryTags)
+
+		all, err := repos.Product.GetAll(ctx)
+		require.NoError(t, err)
+		var wedges int
+		for _, p := range all {
+			if p.Name == "Wedges" {
+				wedges++
+			}
+		}
+		require.Equal(t, 1, wedges, "the rejected create left no product behind")
+	})
+
+	t.Run("order lines record the allergens of the options picked", func(t *testing.T) {
+		_, err := products.UpdateDietary(ctx, burger.ID, []string{"gluten", "sesame"}, nil)
+		require.NoError(t, err)
+
+		prep, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{
+				ProductID:     burger.ID,
+				Quantity:      1,
+				Configuration: map[string]string{sideSlot.ID: salad.ID},
+			}},
+		}, nil, nil)
+		require.NoError(t, err)
+
+		lines, err := repos.OrderLine.GetByOrderID(ctx, prep.OrderID)
+		require.NoError(t, err)
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			switch line.LineType {
+			case orderline.LineTypeBundle:
+				require.Equal(t, []string{"gluten", "eggs", "mustard", "sesame"}, line.Allergens)
+			case orderline.LineTypeComponent:
+				require.Equal(t, []string{"eggs", "mustard"}, line.Allergens)
+			}
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/generated/ent/inventoryledger"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProductAvailability(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	products := NewProductSvc(repos)
+	payments := service.NewPaymentService(TestConfig(), repos.Order, repos.OrderLine, repos.OrderPayment, products, nil,
+		nil, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+
+	breakfast := fixtures.CreateCategory("Frühstück", 1, true)
+	grill := fixtures.CreateCategory("Grill", 2, true)
+	croissant := fixtures.CreateProduct("Gipfeli", breakfast.ID, 250, product.TypeSimple, nil)
+	bratwurst := fixtures.CreateProduct("Bratwurst", grill.ID, 800, product.TypeSimple, nil)
+	steak := fixtures.CreateProduct("Steak", grill.ID, 2200, product.TypeSimple, nil)
+	fixtures.AddInventory(croissant.ID, 50, inventoryledger.ReasonOpeningBalance)
+	fixtures.AddInventory(bratwurst.ID, 50, inventoryledger.ReasonOpeningBalance)
+	fixtures.AddInventory(steak.ID, 50, inventoryledger.ReasonOpeningBalance)
+
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	now := time.Now().In(zurich)
+	tomorrow := schedule.DateKey(now.AddDate(0, 0, 1), zurich)
+	allDay := repository.AvailabilityWindowInput{Weekdays: int(schedule.AllWeekdays), StartMinute: 0, EndMinute: schedule.MinutesPerDay}
+	tomorrowMorning := repository.AvailabilityWindowInput{Weekdays: int(schedule.AllWeekdays), Date: &tomorrow, StartMinute: 8 * 60, EndMinute: 11 * 60}
+
+	t.Run("rejects invalid windows", func(t *testing.T) {
+		bad := "17.07.2026"
+		_, err := products.SetProductAvailability(ctx, croissant.ID, []repository.AvailabilityWindowInput{
+			{Weekdays: int(schedule.AllWeekdays), Date: &bad, StartMinute: 60, EndMinute: 120},
+		})
+		require.ErrorIs(t, err, service.ErrAvailabilityInvalid)
+		_, err = products.SetProductAvailability(ctx, croissant.ID, []repository.AvailabilityWindowInput{
+			{Weekdays: int(schedule.AllWeekdays), StartMinute: 120, EndMinute: 60},
+		})
+		require.ErrorIs(t, err, service.ErrAvailabilityInvalid)
+	})
+
+	t.Run("products without windows are always available", func(t *testing.T) {
+		avail, err := products.Availability(ctx)
+		require.NoError(t, err)
+		ok, next := avail.Check(bratwurst)
+		require.True(t, ok)
+		require.Nil(t, next)
+	})
+
+	t.Run("a dated window reports the next availability", func(t *testing.T) {
+		saved, err := products.SetCategoryAvailability(ctx, breakfast.ID, []repository.AvailabilityWindowInput{tomorrowMorning})
+		require.NoError(t, err)
+		require.Len(t, saved, 1)
+
+		avail, err := products.Availability(ctx)
+		require.NoError(t, err)
+		ok, next := avail.Check(croissant)
+		require.False(t, ok)
+		require.NotNil(t, next)
+		day, _ := time.ParseInLocation(time.DateOnly, tomorrow, zurich)
+		require.True(t, next.Equal(day.Add(8*time.Hour)), "next is %s", next)
+
+		_, err = payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: croissant.ID, Quantity: 1}},
+		}, nil, nil)
+		require.ErrorIs(t, err, service.ErrProductUnavailable)
+	})
+
+	t.Run("product and category windows must both allow ordering", func(t *testing.T) {
+		_, err := products.SetCategoryAvailability(ctx, grill.ID, []repository.AvailabilityWindowInput{allDay})
+		require.NoError(t, err)
+		_, err = products.SetProductAvailability(ctx, steak.ID, []repository.AvailabilityWindowInput{tomorrowMorning})
+		require.NoError(t, err)
+
+		avail, err := products.Availability(ctx)
+		require.NoError(t, err)
+		require.True(t, avail.Available(bratwurst))
+		require.False(t, avail.Available(steak))
+
+		prep, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: bratwurst.ID, Quantity: 1}},
+		}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(800), prep.TotalCents)
+	})
+
+	t.Run("clearing the windows makes a product available again", func(t *testing.T) {
+		saved, err := products.SetCategoryAvailability(ctx, breakfast.ID, nil)
+		require.NoError(t, err)
+		require.Empty(t, saved)
+
+		avail, err := products.Availability(ctx)
+		require.NoError(t, err)
+		require.True(t, avail.Available(croissant))
+
+		windows, err := products.ProductAvailability(ctx, steak.ID)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, tomorrow, *windows[0].Date)
+	})
+}
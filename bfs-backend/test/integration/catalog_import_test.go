@@ -0,0 +1,244 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/catalogfile"
+	entDevice "backend/internal/generated/ent/device"
+	"backend/internal/generated/ent/product"
+	entSettings "backend/internal/generated/ent/settings"
+	"backend/internal/i18n"
+	"backend/internal/repository"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogImport(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	products := NewProductSvc(repos)
+	settings := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	catalog := service.NewCatalogService(tdb.Client, repos.Product, repos.ProductVariant, repos.Category, repos.MenuSlot,
+		repos.MenuSlotOption, repos.Jeton, repos.Device, repos.DeviceProduct, settings, products)
+
+	grill := fixtures.CreateJeton("Grill", "#FF0000")
+	food := fixtures.CreateCategory("Essen", 1, true)
+	sausage := fixtures.CreateProduct("Bratwurst", food.ID, 750, product.TypeSimple, &grill.ID)
+	menu := fixtures.CreateProduct("Grillmenü", food.ID, 1500, product.TypeMenu, nil)
+	slot := fixtures.CreateMenuSlot(menu.ID, "Hauptgang", 0)
+	fixtures.CreateMenuSlotOption(slot.ID, sausage.ID)
+	station := fixtures.CreateDevice("Grillstand", "grill-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(station.ID, sausage.ID)
+	_, err := products.CreateVariant(ctx, sausage.ID, repository.ProductVariantInput{Name: "Doppelt", PriceCents: 1200, IsActive: true})
+	require.NoError(t, err)
+
+	t.Run("exports the catalog by name", func(t *testing.T) {
+		c, err := catalog.Export(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []catalogfile.Jeton{{Name: "Grill", Color: "#FF0000"}}, c.Jetons)
+		require.Len(t, c.Products, 2)
+
+		p := c.Products[0]
+		require.Equal(t, "Bratwurst", p.Name)
+		require.Equal(t, "Essen", p.Category)
+		require.Equal(t, "Grill", *p.Jeton)
+		require.Equal(t, []string{"Grillstand"}, p.Stations)
+		require.Len(t, p.Variants, 1)
+		require.Equal(t, "Doppelt", p.Variants[0].Name)
+
+		require.Len(t, c.Products[1].Slots, 1)
+		require.Equal(t, []string{"Bratwurst"}, c.Products[1].Slots[0].Options)
+	})
+
+	t.Run("importing an export changes nothing", func(t *testing.T) {
+		c, err := catalog.Export(ctx)
+		require.NoError(t, err)
+		result, err := catalog.Import(ctx, c, false)
+		require.NoError(t, err)
+		require.Empty(t, result.Problems)
+		require.True(t, result.Applied)
+		require.Equal(t, service.CatalogCounts{}, result.Created)
+		require.Equal(t, service.CatalogCounts{Jetons: 1, Categories: 1, Products: 2, Variants: 1, Slots: 1}, result.Updated)
+
+		again, err := catalog.Export(ctx)
+		require.NoError(t, err)
+		require.Equal(t, c, again)
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		c := &catalogfile.Catalog{Products: []catalogfile.Product{{Name: "Cola", Category: "Getränke", Type: "simple"}},
+			Categories: []catalogfile.Category{{Name: "Getränke"}}}
+		result, err := catalog.Import(ctx, c, true)
+		require.NoError(t, err)
+		require.Empty(t, result.Problems)
+		require.False(t, result.Applied)
+		require.Equal(t, service.CatalogCounts{Categories: 1, Products: 1}, result.Created)
+
+		all, err := repos.Product.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("problems stop the import", func(t *testing.T) {
+		c := &catalogfile.Catalog{
+			Categories: []catalogfile.Category{{Name: "Getränke"}},
+			Products: []catalogfile.Product{
+				{Name: "Cola", Category: "Getränke", Type: "simple", PriceCents: 300},
+				{Name: "Bratwurst", Category: "Essen", Type: "menu"},
+				{Name: "Fanta", Category: "Snacks", Type: "simple", Stations: []string{"Bar"}},
+			},
+		}
+		result, err := catalog.Import(ctx, c, false)
+		require.NoError(t, err)
+		require.False(t, result.Applied)
+		require.Equal(t, []catalogfile.Problem{
+			{Record: `product "Bratwurst"`, Message: "is stored as simple; the type of a product cannot be changed"},
+			{Record: `product "Fanta"`, Message: `unknown category "Snacks"`},
+			{Record: `product "Fanta"`, Message: `unknown station "Bar"`},
+		}, result.Problems)
+
+		all, err := repos.Product.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("upserts by name", func(t *testing.T) {
+		bar := "bar"
+		c := &catalogfile.Catalog{
+			Jetons:     []catalogfile.Jeton{{Name: "Bar", Color: "00ff00"}},
+			Categories: []catalogfile.Category{{Name: "Getränke", Position: 2}},
+			Products: []catalogfile.Product{
+				{
+					Name: "Cola", Category: "getränke", Type: "simple", PriceCents: 350, Jeton: &bar,
+					Translations: i18n.Translations{i18n.French: {Name: "Coca"}},
+					Variants:     []catalogfile.Variant{{Name: "5dl", PriceCents: 500, Stations: []string{"grillstand"}}},
+				},
+				{Name: " bratwurst ", Category: "Essen", PriceCents: 800, Stations: []string{}},
+				{Name: "Grillmenü", Category: "Essen", Type: "menu", PriceCents: 1500, Slots: []catalogfile.Slot{
+					{Name: "Hauptgang", Options: []string{"Bratwurst", "Cola"}},
+					{Name: "Getränk", Sequence: 1, Options: []string{"Cola"}},
+				}},
+			},
+		}
+		_, err := products.GetAll(ctx)
+		require.NoError(t, err)
+
+		result, err := catalog.Import(ctx, c, false)
+		require.NoError(t, err)
+		require.Empty(t, result.Problems)
+		require.True(t, result.Applied)
+		require.Equal(t, service.CatalogCounts{Jetons: 1, Categories: 1, Products: 1, Variants: 1, Slots: 1}, result.Created)
+		require.Equal(t, service.CatalogCounts{Products: 2, Slots: 1}, result.Updated)
+
+		// The import went past the product service, whose cached catalog
+		// must not keep the old prices.
+		cached, err := products.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, cached, 3)
+		for _, p := range cached {
+			if p.ID == sausage.ID {
+				require.Equal(t, int64(800), p.PriceCents)
+				require.NotNil(t, p.JetonID, "a record without a jeton keeps the stored one")
+				require.Equal(t, grill.ID, *p.JetonID)
+			}
+		}
+
+		out, err := catalog.Export(ctx)
+		require.NoError(t, err)
+		require.Len(t, out.Jetons, 2)
+		require.Equal(t, "#00FF00", out.Jetons[0].Color)
+		require.Len(t, out.Products, 3)
+
+		byName := map[string]catalogfile.Product{}
+		for _, p := range out.Products {
+			byName[p.Name] = p
+		}
+		cola := byName["Cola"]
+		require.Equal(t, "Getränke", cola.Category)
+		require.Equal(t, "Bar", *cola.Jeton)
+		require.Equal(t, "Coca", cola.Translations[i18n.French].Name)
+		require.Equal(t, []string{"Grillstand"}, cola.Variants[0].Stations)
+
+		wurst := byName["Bratwurst"]
+		require.Empty(t, wurst.Stations)
+		require.Len(t, wurst.Variants, 1, "variants left out of the file are kept")
+
+		slots := byName["Grillmenü"].Slots
+		require.Len(t, slots, 2)
+		require.Equal(t, []string{"Bratwurst", "Cola"}, slots[0].Options)
+		require.Equal(t, []string{"Cola"}, slots[1].Options)
+	})
+
+	t.Run("records without texts keep the stored ones", func(t *testing.T) {
+		image, description, jeton := "https://cdn.example.com/wurst.jpg", "Mit Senf", "Grill"
+		result, err := catalog.Import(ctx, &catalogfile.Catalog{Products: []catalogfile.Product{{
+			Name: "Bratwurst", Category: "Essen", PriceCents: 800, Image: &image, Description: &description,
+			Variants: []catalogfile.Variant{{Name: "Doppelt", PriceCents: 1200, Jeton: &jeton}},
+		}}}, false)
+		require.NoError(t, err)
+		require.True(t, result.Applied)
+
+		result, err = catalog.Import(ctx, &catalogfile.Catalog{Products: []catalogfile.Product{{
+			Name: "Bratwurst", Category: "Essen", PriceCents: 850,
+			Variants: []catalogfile.Variant{{Name: "Doppelt", PriceCents: 1250}},
+		}}}, false)
+		require.NoError(t, err)
+		require.True(t, result.Applied)
+
+		wurst := exportedProduct(t, catalog, "Bratwurst")
+		require.Equal(t, int64(850), wurst.PriceCents)
+		require.Equal(t, image, *wurst.Image)
+		require.Equal(t, description, *wurst.Description)
+		require.Equal(t, "Grill", *wurst.Jeton)
+		require.Equal(t, int64(1250), wurst.Variants[0].PriceCents)
+		require.Equal(t, "Grill", *wurst.Variants[0].Jeton)
+
+		// An empty text clears the stored one.
+		empty := ""
+		_, err = catalog.Import(ctx, &catalogfile.Catalog{Products: []catalogfile.Product{{
+			Name: "Bratwurst", Category: "Essen", PriceCents: 850, Image: &empty,
+			Variants: []catalogfile.Variant{{Name: "Doppelt", PriceCents: 1250, Jeton: &empty}},
+		}}}, false)
+		require.NoError(t, err)
+		wurst = exportedProduct(t, catalog, "Bratwurst")
+		require.Nil(t, wurst.Image)
+		require.Equal(t, description, *wurst.Description)
+		require.Nil(t, wurst.Variants[0].Jeton)
+	})
+
+	t.Run("jeton modes keep every active product on a jeton", func(t *testing.T) {
+		require.NoError(t, settings.SetPosMode(ctx, entSettings.PosModeJETON))
+		defer func() { require.NoError(t, settings.SetPosMode(ctx, entSettings.PosModeQR_CODE)) }()
+
+		empty := ""
+		result, err := catalog.Import(ctx, &catalogfile.Catalog{Products: []catalogfile.Product{{
+			Name: "Bratwurst", Category: "Essen", PriceCents: 850, Jeton: &empty,
+		}}}, false)
+		require.NoError(t, err)
+		require.False(t, result.Applied)
+		require.Len(t, result.Problems, 1)
+		require.Equal(t, "catalog", result.Problems[0].Record)
+
+		require.Equal(t, "Grill", *exportedProduct(t, catalog, "Bratwurst").Jeton)
+	})
+}
+
+func exportedProduct(t *testing.T, catalog service.CatalogService, name string) catalogfile.Product {
+	t.Helper()
+	c, err := catalog.Export(context.Background())
+	require.NoError(t, err)
+	for _, p := range c.Products {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("product %q not exported", name)
+	return catalogfile.Product{}
+}
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/generated/ent/club100syncrun"
+	"backend/internal/membership"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClub100CardService(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	require.NoError(t, repos.Settings.UpdateClub100Settings(ctx, nil, 2))
+
+	cfg := TestConfig()
+	cfg.QR.SigningSecret = "test-secret"
+	cfg.QR.PayloadTTL = time.Hour
+
+	directory := membership.NewFake(
+		membership.Member{ID: "p1", FirstName: "Anna", LastName: "Meier"},
+		membership.Member{ID: "p2", FirstName: "Beat", LastName: "Müller"},
+	)
+	club100 := service.NewClub100Service(directory, repos.Club100Member, repos.Club100Period, repos.Club100Redemption,
+		repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
+	_, err := club100.SyncMembers(ctx, club100syncrun.TriggerManual)
+	require.NoError(t, err)
+
+	settings := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	qr := service.NewQRService(cfg, settings, zap.NewNop())
+	svc := service.NewClub100CardService(repos.Club100Card, repos.Club100Member, directory, club100, qr, tdb.Client)
+
+	issued, err := svc.IssueMissingCards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, issued)
+
+	issued, err = svc.IssueMissingCards(ctx)
+	require.NoError(t, err)
+	require.Zero(t, issued, "members with a card keep it")
+
+	cards, err := svc.PrintableCards(ctx, []string{"p1"})
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	first := cards[0].QRPayload
+
+	t.Run("scanned card resolves to remaining redemptions", func(t *testing.T) {
+		lookup, err := svc.Lookup(ctx, first)
+		require.NoError(t, err)
+		require.Equal(t, "p1", lookup.Person.ID)
+		require.Equal(t, 2, lookup.Person.Remaining)
+		require.NotNil(t, lookup.Window)
+	})
+
+	t.Run("reissue revokes the old card", func(t *testing.T) {
+		reissued, err := svc.IssueCard(ctx, "p1")
+		require.NoError(t, err)
+		require.Equal(t, "p1", reissued.Member.ExternalPersonID)
+
+		_, err = svc.Lookup(ctx, first)
+		require.ErrorIs(t, err, service.ErrClub100CardRevoked)
+
+		cards, err := svc.PrintableCards(ctx, []string{"p1"})
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		_, err = svc.Lookup(ctx, cards[0].QRPayload)
+		require.NoError(t, err)
+	})
+
+	t.Run("revoked card stops working", func(t *testing.T) {
+		cards, err := svc.PrintableCards(ctx, []string{"p2"})
+		require.NoError(t, err)
+		require.NoError(t, svc.RevokeCard(ctx, "p2"))
+		require.ErrorIs(t, svc.RevokeCard(ctx, "p2"), service.ErrClub100CardNotFound)
+
+		_, err = svc.Lookup(ctx, cards[0].QRPayload)
+		require.ErrorIs(t, err, service.ErrClub100CardRevoked)
+
+		members, err := svc.ListMembers(ctx)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		for _, m := range members {
+			if m.Member.ExternalPersonID == "p2" {
+				require.Nil(t, m.Card)
+			}
+		}
+	})
+
+	t.Run("forged and foreign codes are rejected", func(t *testing.T) {
+		_, err := svc.Lookup(ctx, first[:len(first)-2]+"xx")
+		require.ErrorIs(t, err, service.ErrQRInvalid)
+
+		_, err = svc.Lookup(ctx, "BFS1.O.whatever.1.sig")
+		require.ErrorIs(t, err, service.ErrQRInvalid)
+	})
+
+	t.Run("cards are not printed without a signing secret", func(t *testing.T) {
+		unsigned := service.NewQRService(TestConfig(), settings, zap.NewNop())
+		svc := service.NewClub100CardService(repos.Club100Card, repos.Club100Member, directory, club100, unsigned, tdb.Client)
+		_, err := svc.PrintableCards(ctx, nil)
+		require.ErrorIs(t, err, service.ErrQRSigningOff)
+	})
+}
@@ -0,0 +1,229 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/membership"
+	"backend/internal/repository"
+	"backend/internal/service"
+
+	entDevice "backend/internal/generated/ent/device"
+	entOrder "backend/internal/generated/ent/order"
+	entOrderLine "backend/internal/generated/ent/orderline"
+	entProduct "backend/internal/generated/ent/product"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// club100PaymentSetup builds a POS service over a directory with one member
+// who may redeem maxRedemptions free products.
+func club100PaymentSetup(t *testing.T, tdb *TestDB, maxRedemptions int) (service.POSService, *Repositories, *Fixtures, string) {
+	t.Helper()
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	cfg := TestConfig()
+
+	cat := fixtures.CreateCategory("Drinks", 1, true)
+	free := fixtures.CreateProduct("Kaffee", cat.ID, 300, entProduct.TypeSimple, nil)
+	require.NoError(t, repos.Settings.UpdateClub100Settings(ctx, []string{free.ID}, maxRedemptions))
+
+	_, err := repos.Club100Member.ReplaceAll(ctx, []repository.Club100MemberInput{
+		{ExternalPersonID: "p1", Provider: membership.ProviderFake, FirstName: "Alice", LastName: "A"},
+	}, time.Now())
+	require.NoError(t, err)
+
+	paymentSvc := service.NewPaymentService(cfg, repos.Order, repos.OrderLine, repos.OrderPayment, NewProductSvc(repos), nil,
+		nil, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period,
+		repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
+	return svc, repos, fixtures, free.ID
+}
+
+func redeemedTotal(t *testing.T, repos *Repositories, personID string) int {
+	t.Helper()
+	total, err := repos.Club100Redemption.GetTotalRedemptions(context.Background(), membership.ProviderFake, personID,
+		time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return total
+}
+
+func TestClub100Payment_FailedPaymentBooksNoRedemption(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	svc, repos, fixtures, _ := club100PaymentSetup(t, tdb, 2)
+	ctx := context.Background()
+	device := fixtures.CreateDevice("POS 1", "pos-token", entDevice.TypePOS, entDevice.StatusApproved)
+	redemption := &service.Club100RedemptionInput{ExternalPersonID: "p1", PersonName: "Alice A", FreeQuantity: 1}
+
+	t.Run("redemption over the limit fails the cash payment", func(t *testing.T) {
+		ord := fixtures.CreateOrder(600, entOrder.StatusPending, entOrder.OriginPos)
+		over := *redemption
+		over.FreeQuantity = 3
+		err := svc.PayCash(ctx, ord.ID, &device.ID, &over)
+		require.ErrorIs(t, err, service.ErrClub100InsufficientRedemptions)
+
+		got, err := repos.Order.GetByID(ctx, ord.ID)
+		require.NoError(t, err)
+		require.Equal(t, entOrder.StatusPending, got.Status)
+		require.Zero(t, redeemedTotal(t, repos, "p1"))
+	})
+
+	t.Run("payment of a paid order books no redemption", func(t *testing.T) {
+		ord := fixtures.CreateOrder(300, entOrder.StatusPaid, entOrder.OriginPos)
+		err := svc.PayCash(ctx, ord.ID, &device.ID, redemption)
+		require.ErrorIs(t, err, repository.ErrOrderNotPending)
+		require.Zero(t, redeemedTotal(t, repos, "p1"))
+	})
+
+	t.Run("unknown members are rejected", func(t *testing.T) {
+		ord := fixtures.CreateOrder(300, entOrder.StatusPending, entOrder.OriginPos)
+		err := svc.PayCard(ctx, ord.ID, &device.ID, nil, &service.Club100RedemptionInput{
+			ExternalPersonID: "ghost", PersonName: "Ghost", FreeQuantity: 1,
+		})
+		require.ErrorIs(t, err, service.ErrClub100MemberNotFound)
+	})
+
+	t.Run("successful payment books the redemption", func(t *testing.T) {
+		ord := fixtures.CreateOrder(300, entOrder.StatusPending, entOrder.OriginPos)
+		require.NoError(t, svc.PayCash(ctx, ord.ID, &device.ID, redemption))
+		require.Equal(t, 1, redeemedTotal(t, repos, "p1"))
+	})
+}
+
+func TestClub100Payment_DeviceWithoutClub100GratisType(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	svc, repos, fixtures, _ := club100PaymentSetup(t, tdb, 2)
+	ctx := context.Background()
+	device := fixtures.CreateDevice("POS 1", "pos-token", entDevice.TypePOS, entDevice.StatusApproved)
+	_, err := svc.SetConfig(ctx, device.ID, repository.DevicePosConfig{GratisTypes: []string{"guest"}})
+	require.NoError(t, err)
+
+	t.Run("partial redemption with a cash payment is rejected", func(t *testing.T) {
+		ord := fixtures.CreateOrder(600, entOrder.StatusPending, entOrder.OriginPos)
+		err := svc.PayCash(ctx, ord.ID, &device.ID, &service.Club100RedemptionInput{
+			ExternalPersonID: "p1", PersonName: "Alice A", FreeQuantity: 1,
+		})
+		require.ErrorIs(t, err, service.ErrPaymentMethodNotAllowed)
+
+		got, err := repos.Order.GetByID(ctx, ord.ID)
+		require.NoError(t, err)
+		require.Equal(t, entOrder.StatusPending, got.Status)
+		require.Zero(t, redeemedTotal(t, repos, "p1"))
+	})
+
+	t.Run("cash payment without a redemption is allowed", func(t *testing.T) {
+		ord := fixtures.CreateOrder(600, entOrder.StatusPending, entOrder.OriginPos)
+		require.NoError(t, svc.PayCash(ctx, ord.ID, &device.ID, nil))
+	})
+}
+
+func TestClub100Payment_ConcurrentRedemptionsStayWithinMax(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	const maxRedemptions = 3
+	const devices = 10
+
+	svc, repos, fixtures, freeID := club100PaymentSetup(t, tdb, maxRedemptions)
+	ctx := context.Background()
+	device := fixtures.CreateDevice("POS 1", "pos-token", entDevice.TypePOS, entDevice.StatusApproved)
+
+	orders := make([]string, devices)
+	for i := range orders {
+		ord := fixtures.CreateOrder(0, entOrder.StatusPending, entOrder.OriginPos)
+		fixtures.CreateOrderLine(ord.ID, freeID, "Kaffee", 1, 300, entOrderLine.LineTypeSimple)
+		orders[i] = ord.ID
+	}
+
+	// Half the tablets pay fully free, the others pay cash with a discount;
+	// both paths must share the member's allowance.
+	var wg sync.WaitGroup
+	errs := make([]error, devices)
+	start := make(chan struct{})
+	for i, orderID := range orders {
+		wg.Add(1)
+		go func(i int, orderID string) {
+			defer wg.Done()
+			<-start
+			in := service.Club100RedemptionInput{ExternalPersonID: "p1", PersonName: "Alice A", FreeQuantity: 1}
+			if i%2 == 0 {
+				errs[i] = svc.PayGratis100Club(ctx, orderID, &device.ID, in)
+			} else {
+				errs[i] = svc.PayCash(ctx, orderID, &device.ID, &in)
+			}
+		}(i, orderID)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, service.ErrClub100InsufficientRedemptions)
+		got, gerr := repos.Order.GetByID(ctx, orders[i])
+		require.NoError(t, gerr)
+		require.Equal(t, entOrder.StatusPending, got.Status, "a rejected redemption leaves the order unpaid")
+	}
+	require.Equal(t, maxRedemptions, succeeded)
+	require.Equal(t, maxRedemptions, redeemedTotal(t, repos, "p1"))
+}
+
+func TestClub100Payment_ConcurrentPaymentsOfOneOrder(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	svc, repos, fixtures, _ := club100PaymentSetup(t, tdb, 5)
+	ctx := context.Background()
+	device := fixtures.CreateDevice("POS 1", "pos-token", entDevice.TypePOS, entDevice.StatusApproved)
+	ord := fixtures.CreateOrder(300, entOrder.StatusPending, entOrder.OriginPos)
+
+	// A retried request racing the original must neither pay twice nor
+	// redeem twice.
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	start := make(chan struct{})
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = svc.PayCash(ctx, ord.ID, &device.ID, &service.Club100RedemptionInput{
+				ExternalPersonID: "p1", PersonName: "Alice A", FreeQuantity: 1,
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			require.ErrorIs(t, err, repository.ErrOrderNotPending)
+		}
+	}
+	require.Equal(t, 1, succeeded)
+	require.Equal(t, 1, redeemedTotal(t, repos, "p1"))
+
+	payments, err := repos.OrderPayment.GetByOrderID(ctx, ord.ID)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+}
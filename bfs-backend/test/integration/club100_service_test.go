@@ -4,8 +4,10 @@ import (
 	"context"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"backend/internal/generated/ent"
+	"backend/internal/membership"
 	"backend/internal/repository"
 	"backend/internal/service"
 
@@ -13,6 +15,7 @@ import (
 	"entgo.io/ent/dialect"
 	entsql "entgo.io/ent/dialect/sql"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 type countingDriver struct {
@@ -41,7 +44,7 @@ func TestClub100Service_GetPeopleWithRedemptions_NoNPlusOne(t *testing.T) {
 	repos := NewRepositories(tdb.Client)
 	require.NoError(t, repos.Settings.UpdateClub100Settings(ctx, nil, 2))
 
-	people := []service.ElvantoPerson{
+	people := []membership.Member{
 		{ID: "p1", FirstName: "Alice", LastName: "A"},
 		{ID: "p2", FirstName: "Bob", LastName: "B"},
 		{ID: "p3", FirstName: "Carol", LastName: "C"},
@@ -50,10 +53,17 @@ func TestClub100Service_GetPeopleWithRedemptions_NoNPlusOne(t *testing.T) {
 	}
 
 	for _, p := range people {
-		_, err := repos.Club100Redemption.Create(ctx, p.ID, p.FirstName+" "+p.LastName, nanoid.New(), 1)
+		_, err := repos.Club100Redemption.Create(ctx, membership.ProviderFake, p.ID, p.FirstName+" "+p.LastName, nanoid.New(), nil, 1)
 		require.NoError(t, err)
 	}
-	_, err := repos.Club100Redemption.Create(ctx, "p1", "Alice A", nanoid.New(), 1)
+	_, err := repos.Club100Redemption.Create(ctx, membership.ProviderFake, "p1", "Alice A", nanoid.New(), nil, 1)
+	require.NoError(t, err)
+
+	members := make([]repository.Club100MemberInput, len(people))
+	for i, p := range people {
+		members[i] = repository.Club100MemberInput{ExternalPersonID: p.ID, Provider: membership.ProviderFake, FirstName: p.FirstName, LastName: p.LastName}
+	}
+	_, err = repos.Club100Member.ReplaceAll(ctx, members, time.Now())
 	require.NoError(t, err)
 
 	counter := &countingDriver{Driver: entsql.OpenDB(dialect.Postgres, tdb.DB)}
@@ -62,14 +72,16 @@ func TestClub100Service_GetPeopleWithRedemptions_NoNPlusOne(t *testing.T) {
 	countedRedemptions := repository.NewClub100RedemptionRepository(countedClient)
 	countedSettings := repository.NewSettingsRepository(countedClient)
 	countedOrderLines := repository.NewOrderLineRepository(countedClient)
+	countedMembers := repository.NewClub100MemberRepository(countedClient)
+	countedPeriods := repository.NewClub100PeriodRepository(countedClient)
 
-	elvanto := &MockElvantoService{Configured: true, People: people}
-	svc := service.NewClub100Service(elvanto, countedRedemptions, countedSettings, countedOrderLines)
+	svc := service.NewClub100Service(membership.NewFake(people...), countedMembers, countedPeriods, countedRedemptions, countedSettings,
+		countedOrderLines, repos.MembershipUpload, countedClient, TestConfig(), zap.NewNop())
 
 	atomic.StoreInt64(&counter.queries, 0)
 	atomic.StoreInt64(&counter.execs, 0)
 
-	result, err := svc.GetPeopleWithRedemptions(ctx)
+	result, err := svc.GetPeopleWithRedemptions(ctx, "")
 	require.NoError(t, err)
 	require.Len(t, result, len(people))
 
@@ -84,7 +96,99 @@ func TestClub100Service_GetPeopleWithRedemptions_NoNPlusOne(t *testing.T) {
 	require.Equal(t, 2, byID["p1"].Max)
 
 	queries := atomic.LoadInt64(&counter.queries)
-	require.LessOrEqualf(t, queries, int64(3),
-		"expected at most 3 SELECT queries (settings + batch redemptions), got %d — N+1 regression in /v1/club100/people",
+	require.LessOrEqualf(t, queries, int64(4),
+		"expected at most 4 SELECT queries (settings + members + active period + batch redemptions), got %d — N+1 regression in /v1/club100/people",
 		queries)
 }
+
+func TestClub100Service_Periods(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	require.NoError(t, repos.Settings.UpdateClub100Settings(ctx, nil, 2))
+
+	svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period,
+		repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, TestConfig(), zap.NewNop())
+
+	// Two free products three days ago: used up for a period, not for today.
+	r, err := repos.Club100Redemption.Create(ctx, membership.ProviderFake, "p1", "Alice A", nanoid.New(), nil, 2)
+	require.NoError(t, err)
+	_, err = tdb.DB.ExecContext(ctx, "UPDATE club100_redemption SET created_at = NOW() - INTERVAL '3 days' WHERE id = $1", r.ID)
+	require.NoError(t, err)
+
+	remaining, _, err := svc.GetRemainingRedemptions(ctx, "p1")
+	require.NoError(t, err)
+	require.Equal(t, 2, remaining, "without a period the allowance resets daily")
+
+	now := time.Now()
+	period, err := svc.CreatePeriod(ctx, service.Club100PeriodInput{
+		Name:     "Sommerfest",
+		StartsAt: now.AddDate(0, 0, -10),
+		EndsAt:   now.AddDate(0, 0, 10),
+	})
+	require.NoError(t, err)
+
+	remaining, _, err = svc.GetRemainingRedemptions(ctx, "p1")
+	require.NoError(t, err)
+	require.Zero(t, remaining)
+
+	window, err := svc.GetCurrentWindow(ctx)
+	require.NoError(t, err)
+	require.Equal(t, period.ID, window.Period.ID)
+
+	t.Run("overlapping and invalid periods are rejected", func(t *testing.T) {
+		_, err := svc.CreatePeriod(ctx, service.Club100PeriodInput{
+			Name:     "Overlap",
+			StartsAt: now.AddDate(0, 0, 5),
+			EndsAt:   now.AddDate(0, 0, 20),
+		})
+		require.ErrorIs(t, err, service.ErrClub100PeriodOverlap)
+
+		_, err = svc.CreatePeriod(ctx, service.Club100PeriodInput{
+			Name:     "Backwards",
+			StartsAt: now.AddDate(0, 1, 0),
+			EndsAt:   now.AddDate(0, 0, 20),
+		})
+		require.ErrorIs(t, err, service.ErrClub100PeriodInvalid)
+
+		name := "Sommerfest 2026"
+		_, err = svc.UpdatePeriod(ctx, period.ID, service.Club100PeriodPatch{Name: &name})
+		require.NoError(t, err, "a period does not overlap itself")
+	})
+
+	t.Run("reports cover current and past periods", func(t *testing.T) {
+		report, err := svc.GetPeriodReport(ctx, period.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, report.TotalQuantity)
+		require.Len(t, report.Members, 1)
+		require.Equal(t, "p1", report.Members[0].ExternalPersonID)
+		require.Equal(t, 1, report.Members[0].Orders)
+
+		past, err := svc.CreatePeriod(ctx, service.Club100PeriodInput{
+			Name:     "Frühling",
+			StartsAt: now.AddDate(0, 0, -40),
+			EndsAt:   now.AddDate(0, 0, -20),
+		})
+		require.NoError(t, err)
+		report, err = svc.GetPeriodReport(ctx, past.ID)
+		require.NoError(t, err)
+		require.Zero(t, report.TotalQuantity)
+
+		periods, err := svc.ListPeriods(ctx)
+		require.NoError(t, err)
+		require.Len(t, periods, 2)
+		require.Equal(t, period.ID, periods[0].ID, "latest first")
+	})
+
+	t.Run("deleting the active period falls back to daily", func(t *testing.T) {
+		require.NoError(t, svc.DeletePeriod(ctx, period.ID))
+		require.ErrorIs(t, svc.DeletePeriod(ctx, period.ID), service.ErrClub100PeriodNotFound)
+
+		remaining, _, err := svc.GetRemainingRedemptions(ctx, "p1")
+		require.NoError(t, err)
+		require.Equal(t, 2, remaining)
+	})
+}
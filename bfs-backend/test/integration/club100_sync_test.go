@@ -0,0 +1,208 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/generated/ent/club100syncrun"
+	nanoid "backend/internal/id"
+	"backend/internal/membership"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClub100Service_SyncMembers(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+
+	hans := membership.Member{ID: "p1", FirstName: "Johannes", LastName: "Müller", PreferredName: "Hans"}
+	anna := membership.Member{ID: "p2", FirstName: "Anna", LastName: "Meier"}
+	directory := membership.NewFake(hans, anna)
+	svc := service.NewClub100Service(directory, repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings,
+		repos.OrderLine, repos.MembershipUpload, tdb.Client, TestConfig(), zap.NewNop())
+
+	t.Run("initial sync adds everyone", func(t *testing.T) {
+		run, err := svc.SyncMembers(ctx, club100syncrun.TriggerManual)
+		require.NoError(t, err)
+		require.Equal(t, club100syncrun.StatusSucceeded, run.Status)
+		require.Equal(t, 2, run.MemberCount)
+		require.Equal(t, 2, run.AddedCount)
+		require.NotNil(t, run.FinishedAt)
+	})
+
+	t.Run("search is served locally and forgiving", func(t *testing.T) {
+		people, err := svc.GetPeopleWithRedemptions(ctx, "muler")
+		require.NoError(t, err)
+		require.Len(t, people, 1)
+		require.Equal(t, "p1", people[0].ID)
+
+		people, err = svc.GetPeopleWithRedemptions(ctx, "hans")
+		require.NoError(t, err)
+		require.Len(t, people, 1, "preferred name is searchable")
+	})
+
+	t.Run("members leaving the group are deactivated and can come back", func(t *testing.T) {
+		directory.Set(hans)
+		run, err := svc.SyncMembers(ctx, club100syncrun.TriggerScheduled)
+		require.NoError(t, err)
+		require.Equal(t, 1, run.RemovedCount)
+
+		people, err := svc.GetPeopleWithRedemptions(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, people, 1)
+
+		directory.Set(hans, anna)
+		run, err = svc.SyncMembers(ctx, club100syncrun.TriggerScheduled)
+		require.NoError(t, err)
+		require.Equal(t, 1, run.AddedCount)
+	})
+
+	t.Run("empty group keeps the directory", func(t *testing.T) {
+		directory.Set()
+		run, err := svc.SyncMembers(ctx, club100syncrun.TriggerScheduled)
+		require.NoError(t, err)
+		require.Equal(t, club100syncrun.StatusFailed, run.Status)
+		require.NotNil(t, run.Error)
+
+		status, err := svc.GetSyncStatus(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, status.MemberCount)
+		require.Equal(t, club100syncrun.StatusFailed, status.LastRun.Status)
+		require.Equal(t, club100syncrun.StatusSucceeded, status.LastSuccess.Status)
+	})
+
+	t.Run("unconfigured provider is rejected", func(t *testing.T) {
+		unconfigured := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption,
+			repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, TestConfig(), zap.NewNop())
+		_, err := unconfigured.SyncMembers(ctx, club100syncrun.TriggerManual)
+		require.ErrorIs(t, err, membership.ErrNotConfigured)
+	})
+}
+
+func TestClub100Service_SyncFilter(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+
+	directory := membership.NewFake(
+		membership.Member{ID: "p1", FirstName: "Anna", LastName: "Meier", Groups: []string{"club100"}},
+		membership.Member{ID: "p2", FirstName: "Beat", LastName: "Müller", Groups: []string{"club100"}, Tags: []string{"Vorstand"}},
+		membership.Member{ID: "p3", FirstName: "Carla", LastName: "Rossi", Groups: []string{"choir"}, Tags: []string{"vorstand"}},
+	)
+	cfg := TestConfig()
+	cfg.Membership.Groups = []string{"Club100"}
+	cfg.Membership.Tags = []string{"vorstand"}
+	svc := service.NewClub100Service(directory, repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings,
+		repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
+
+	run, err := svc.SyncMembers(ctx, club100syncrun.TriggerManual)
+	require.NoError(t, err)
+	require.Equal(t, club100syncrun.StatusSucceeded, run.Status)
+	require.Equal(t, membership.ProviderFake, run.Provider)
+	require.Equal(t, 1, run.MemberCount, "only members in the group and with the tag")
+
+	status, err := svc.GetSyncStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Club100"}, status.Filter.Groups)
+	require.Equal(t, []string{"vorstand"}, status.Filter.Tags)
+}
+
+func TestClub100Service_SwitchingProviders(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	require.NoError(t, repos.Settings.UpdateClub100Settings(ctx, nil, 2))
+
+	fake := service.NewClub100Service(membership.NewFake(membership.Member{ID: "7", FirstName: "Anna", LastName: "Meier"}),
+		repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload,
+		tdb.Client, TestConfig(), zap.NewNop())
+	_, err := fake.SyncMembers(ctx, club100syncrun.TriggerManual)
+	require.NoError(t, err)
+	require.NoError(t, fake.RecordRedemption(ctx, "7", "Anna Meier", nanoid.New(), nil, 2))
+
+	cfg := TestConfig()
+	cfg.Membership.Provider = membership.ProviderCSV
+	directory, err := service.NewMembershipProvider(cfg, repos.MembershipUpload, zap.NewNop())
+	require.NoError(t, err)
+	csv := service.NewClub100Service(directory, repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings,
+		repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
+	run, err := csv.UploadMembers(ctx, "mitglieder.csv", []byte("Nr;Vorname;Nachname\n7;Beat;Müller\n"))
+	require.NoError(t, err)
+	require.Equal(t, 1, run.AddedCount, "the same ID at another provider is a new member")
+
+	people, err := csv.GetPeopleWithRedemptions(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, people, 1)
+	require.Equal(t, "Beat", people[0].FirstName)
+	require.Equal(t, 2, people[0].Remaining, "the new member does not inherit the old one's redemptions")
+
+	anna, err := repos.Club100Member.GetByExternalID(ctx, membership.ProviderFake, "7")
+	require.NoError(t, err)
+	require.Equal(t, "Anna", anna.FirstName)
+	require.False(t, anna.Active)
+}
+
+func TestClub100Service_UploadMembers(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+
+	cfg := TestConfig()
+	cfg.Membership.Provider = membership.ProviderCSV
+	directory, err := service.NewMembershipProvider(cfg, repos.MembershipUpload, zap.NewNop())
+	require.NoError(t, err)
+	svc := service.NewClub100Service(directory, repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings,
+		repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
+
+	t.Run("nothing uploaded yet", func(t *testing.T) {
+		run, err := svc.SyncMembers(ctx, club100syncrun.TriggerManual)
+		require.NoError(t, err)
+		require.Equal(t, club100syncrun.StatusFailed, run.Status)
+	})
+
+	t.Run("upload syncs the list", func(t *testing.T) {
+		run, err := svc.UploadMembers(ctx, "mitglieder.csv", []byte("Nr;Vorname;Nachname\n1;Anna;Meier\n2;Beat;Müller\n"))
+		require.NoError(t, err)
+		require.Equal(t, club100syncrun.StatusSucceeded, run.Status)
+		require.Equal(t, membership.ProviderCSV, run.Provider)
+		require.Equal(t, 2, run.AddedCount)
+
+		status, err := svc.GetSyncStatus(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, status.LastUpload)
+		require.Equal(t, "mitglieder.csv", status.LastUpload.Filename)
+		require.Equal(t, 2, status.LastUpload.MemberCount)
+	})
+
+	t.Run("a bad file changes nothing", func(t *testing.T) {
+		_, err := svc.UploadMembers(ctx, "kaputt.csv", []byte("Nr;Vorname\n1;Anna\n"))
+		require.ErrorIs(t, err, membership.ErrInvalidCSV)
+
+		status, err := svc.GetSyncStatus(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "mitglieder.csv", status.LastUpload.Filename)
+		require.Equal(t, 2, status.MemberCount)
+	})
+
+	t.Run("other providers refuse uploads", func(t *testing.T) {
+		other := service.NewClub100Service(membership.NewFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption,
+			repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, TestConfig(), zap.NewNop())
+		_, err := other.UploadMembers(ctx, "x.csv", []byte("id,first_name,last_name\n1,A,B\n"))
+		require.ErrorIs(t, err, service.ErrClub100UploadUnsupported)
+	})
+}
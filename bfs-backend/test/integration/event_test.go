@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/generated/ent/inventoryledger"
+	"backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEventService(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	events := service.NewEventService(repos.Event, tdb.Client)
+	orders := service.NewOrderService(repos.Order, repos.OrderLine, repos.Inventory, nil)
+	payments := service.NewPaymentService(TestConfig(), repos.Order, repos.OrderLine, repos.OrderPayment, NewProductSvc(repos), events,
+		nil, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+
+	category := fixtures.CreateCategory("Grill", 1, true)
+	bratwurst := fixtures.CreateProduct("Bratwurst", category.ID, 800, product.TypeSimple, nil)
+	gluehwein := fixtures.CreateProduct("Glühwein", category.ID, 600, product.TypeSimple, nil)
+	fixtures.AddInventory(bratwurst.ID, 50, inventoryledger.ReasonOpeningBalance)
+	fixtures.AddInventory(gluehwein.ID, 50, inventoryledger.ReasonOpeningBalance)
+
+	now := time.Now()
+	summer, err := events.Create(ctx, service.EventInput{Name: "Sommerfest", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(48 * time.Hour), Active: true})
+	require.NoError(t, err)
+	require.True(t, summer.Active)
+
+	t.Run("rejects an end before the start", func(t *testing.T) {
+		_, err := events.Create(ctx, service.EventInput{Name: "Falsch", StartsAt: now, EndsAt: now.Add(-time.Hour)})
+		require.ErrorIs(t, err, service.ErrEventInvalid)
+	})
+
+	t.Run("checkout uses the event's menu and prices", func(t *testing.T) {
+		price := int64(700)
+		_, err := events.SetProducts(ctx, summer.ID, []repository.EventProductInput{
+			{ProductID: bratwurst.ID, Available: true, PriceCents: &price},
+			{ProductID: gluehwein.ID, Available: false},
+		})
+		require.NoError(t, err)
+
+		prep, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: bratwurst.ID, Quantity: 2}},
+		}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1400), prep.TotalCents)
+		require.NotNil(t, prep.Order.EventID)
+		require.Equal(t, summer.ID, *prep.Order.EventID)
+
+		entries, err := repos.Inventory.GetByProductID(ctx, bratwurst.ID)
+		require.NoError(t, err)
+		var tagged int
+		for _, e := range entries {
+			if e.EventID != nil && *e.EventID == summer.ID {
+				tagged++
+			}
+		}
+		require.Equal(t, 1, tagged, "the sale is booked against the event")
+
+		_, err = payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: gluehwein.ID, Quantity: 1}},
+		}, nil, nil)
+		require.ErrorIs(t, err, service.ErrProductNotAtEvent)
+	})
+
+	t.Run("activating an event deactivates the other", func(t *testing.T) {
+		active := true
+		christmas, err := events.Create(ctx, service.EventInput{Name: "Weihnachtsmarkt", StartsAt: now.AddDate(0, 5, 0), EndsAt: now.AddDate(0, 5, 2)})
+		require.NoError(t, err)
+		_, err = events.Update(ctx, christmas.ID, service.EventPatch{Active: &active})
+		require.NoError(t, err)
+
+		current, err := events.Current(ctx)
+		require.NoError(t, err)
+		require.Equal(t, christmas.ID, current.Event.ID)
+		require.True(t, current.Available(gluehwein.ID))
+
+		prev, err := events.Get(ctx, summer.ID)
+		require.NoError(t, err)
+		require.False(t, prev.Active)
+
+		require.NoError(t, events.Delete(ctx, christmas.ID), "nothing is tagged with it yet")
+	})
+
+	t.Run("admin lists filter by event", func(t *testing.T) {
+		fixtures.CreateOrder(500, order.StatusPaid, order.OriginPos)
+
+		all, _, err := orders.ListAdmin(ctx, service.OrderListParams{})
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+
+		atSummer, _, err := orders.ListAdmin(ctx, service.OrderListParams{EventID: &summer.ID})
+		require.NoError(t, err)
+		require.Len(t, atSummer, 1)
+	})
+
+	t.Run("an event with orders cannot be deleted", func(t *testing.T) {
+		require.ErrorIs(t, events.Delete(ctx, summer.ID), service.ErrEventInUse)
+	})
+}
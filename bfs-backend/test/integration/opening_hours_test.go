@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/schedule"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpeningHoursService(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	svc := service.NewOpeningHoursService(repos.OpeningHours, repos.Settings, tdb.Client)
+
+	loc, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	today := time.Now().In(loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	allDay := service.OpeningWindow{Weekdays: schedule.AllWeekdays, StartMinute: 0, EndMinute: schedule.MinutesPerDay}
+
+	t.Run("without opening hours the switch decides", func(t *testing.T) {
+		status, err := svc.Status(ctx)
+		require.NoError(t, err)
+		require.True(t, status.Open)
+		require.Equal(t, service.SystemSourceManual, status.Source)
+	})
+
+	t.Run("open inside the opening hours", func(t *testing.T) {
+		_, err := svc.SetOpeningHours(ctx, service.OpeningHours{Enabled: true, Windows: []service.OpeningWindow{allDay}})
+		require.NoError(t, err)
+
+		status, err := svc.Status(ctx)
+		require.NoError(t, err)
+		require.True(t, status.Open)
+		require.Equal(t, service.SystemSourceSchedule, status.Source)
+		require.NotNil(t, status.ClosesAt)
+	})
+
+	t.Run("an exception closes today", func(t *testing.T) {
+		hours, err := svc.SetOpeningHours(ctx, service.OpeningHours{
+			Enabled:       true,
+			ClosedMessage: "Heute geschlossen",
+			Windows:       []service.OpeningWindow{allDay},
+			Exceptions: []service.OpeningException{
+				{Date: today.AddDate(0, 0, -3).Format(time.DateOnly), Label: "vorbei"},
+				{Date: today.Format(time.DateOnly), Label: "Ruhetag"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, hours.Exceptions, 1, "past exceptions are dropped")
+
+		status, err := svc.Status(ctx)
+		require.NoError(t, err)
+		require.False(t, status.Open)
+		require.Equal(t, "Heute geschlossen", status.ClosedMessage)
+		require.NotNil(t, status.OpensAt)
+		require.Equal(t, tomorrow.Format(time.DateOnly), status.OpensAt.In(loc).Format(time.DateOnly))
+	})
+
+	t.Run("manual override opens outside the opening hours", func(t *testing.T) {
+		until := time.Now().Add(time.Hour)
+		require.NoError(t, svc.SetOpenUntil(ctx, &until))
+
+		status, err := svc.Status(ctx)
+		require.NoError(t, err)
+		require.True(t, status.Open)
+		require.Equal(t, service.SystemSourceOverride, status.Source)
+
+		past := time.Now().Add(-time.Minute)
+		require.ErrorIs(t, svc.SetOpenUntil(ctx, &past), service.ErrOpenUntilInPast)
+		require.NoError(t, svc.SetOpenUntil(ctx, nil))
+		open, err := svc.IsOpen(ctx)
+		require.NoError(t, err)
+		require.False(t, open)
+	})
+
+	t.Run("the switch closes regardless of the opening hours", func(t *testing.T) {
+		_, err := svc.SetOpeningHours(ctx, service.OpeningHours{Enabled: true, Windows: []service.OpeningWindow{allDay}})
+		require.NoError(t, err)
+		require.NoError(t, repos.Settings.SetSystemEnabled(ctx, false))
+		svc := service.NewOpeningHoursService(repos.OpeningHours, repos.Settings, tdb.Client)
+
+		status, err := svc.Status(ctx)
+		require.NoError(t, err)
+		require.False(t, status.Open)
+		require.Equal(t, service.SystemSourceManual, status.Source)
+	})
+
+	t.Run("invalid input is rejected", func(t *testing.T) {
+		_, err := svc.SetOpeningHours(ctx, service.OpeningHours{Exceptions: []service.OpeningException{{Date: "2026-13-01"}}})
+		require.ErrorIs(t, err, service.ErrOpeningHoursInvalid)
+		_, err = svc.SetOpeningHours(ctx, service.OpeningHours{Windows: []service.OpeningWindow{{Weekdays: schedule.AllWeekdays, StartMinute: 600, EndMinute: 600}}})
+		require.ErrorIs(t, err, service.ErrOpeningHoursInvalid)
+	})
+}
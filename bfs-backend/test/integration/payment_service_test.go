@@ -30,10 +30,13 @@ func TestPaymentService_PrepareAndCreateOrder(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 	ctx := context.Background()
@@ -180,10 +183,13 @@ func TestPaymentService_MenuCheckout(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 	ctx := context.Background()
@@ -299,10 +305,13 @@ func TestPaymentService_FindPendingOrderByAttemptID(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 	ctx := context.Background()
@@ -355,10 +364,13 @@ func TestPaymentService_MarkOrderPaidByPayrexx(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 	ctx := context.Background()
@@ -404,10 +416,13 @@ func TestPaymentService_CleanupPendingOrderByID(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 	ctx := context.Background()
@@ -473,10 +488,13 @@ func TestPaymentService_TWINTLimits(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 	ctx := context.Background()
@@ -4,12 +4,17 @@ import (
 	"context"
 	"testing"
 
+	"backend/internal/i18n"
+	"backend/internal/membership"
+	"backend/internal/repository"
 	"backend/internal/service"
 
 	entDevice "backend/internal/generated/ent/device"
 	entInventoryLedger "backend/internal/generated/ent/inventoryledger"
 	entOrder "backend/internal/generated/ent/order"
+	entOrderLine "backend/internal/generated/ent/orderline"
 	entProduct "backend/internal/generated/ent/product"
+	entSettings "backend/internal/generated/ent/settings"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -30,16 +35,20 @@ func TestPOSService_GetDeviceByToken(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 
-	club100Svc := service.NewClub100Service(&MockElvantoService{}, repos.Club100Redemption, repos.Settings, repos.OrderLine)
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
 
-	svc := service.NewPOSService(cfg, repos.Device, repos.Order, paymentSvc, club100Svc)
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
 	ctx := context.Background()
 
 	t.Run("GetDeviceByToken returns POS device", func(t *testing.T) {
@@ -80,16 +89,20 @@ func TestPOSService_CreateOrder(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 
-	club100Svc := service.NewClub100Service(&MockElvantoService{}, repos.Club100Redemption, repos.Settings, repos.OrderLine)
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
 
-	svc := service.NewPOSService(cfg, repos.Device, repos.Order, paymentSvc, club100Svc)
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
 	ctx := context.Background()
 
 	// Setup test products
@@ -107,7 +120,7 @@ func TestPOSService_CreateOrder(t *testing.T) {
 			{ProductID: sprite.ID, Quantity: 1},
 		}
 
-		orderID, err := svc.CreateOrder(ctx, items, nil)
+		orderID, err := svc.CreateOrder(ctx, items, nil, "")
 		require.NoError(t, err)
 		require.NotEqual(t, "", orderID)
 
@@ -125,17 +138,19 @@ func TestPOSService_CreateOrder(t *testing.T) {
 		}
 		email := "customer@test.com"
 
-		orderID, err := svc.CreateOrder(ctx, items, &email)
+		orderID, err := svc.CreateOrder(ctx, items, &email, i18n.French)
 		require.NoError(t, err)
 
 		order, err := repos.Order.GetByID(ctx, orderID)
 		require.NoError(t, err)
 		require.NotNil(t, order.ContactEmail)
 		require.Equal(t, email, *order.ContactEmail)
+		require.NotNil(t, order.Locale)
+		require.Equal(t, "fr", *order.Locale)
 	})
 
 	t.Run("CreateOrder with no items fails", func(t *testing.T) {
-		_, err := svc.CreateOrder(ctx, []service.POSCheckoutItem{}, nil)
+		_, err := svc.CreateOrder(ctx, []service.POSCheckoutItem{}, nil, "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no items")
 	})
@@ -145,7 +160,7 @@ func TestPOSService_CreateOrder(t *testing.T) {
 			{ProductID: "invalid-uuid", Quantity: 1},
 		}
 
-		_, err := svc.CreateOrder(ctx, items, nil)
+		_, err := svc.CreateOrder(ctx, items, nil, "")
 		require.Error(t, err)
 	})
 }
@@ -165,16 +180,20 @@ func TestPOSService_PayCash(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 
-	club100Svc := service.NewClub100Service(&MockElvantoService{}, repos.Club100Redemption, repos.Settings, repos.OrderLine)
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
 
-	svc := service.NewPOSService(cfg, repos.Device, repos.Order, paymentSvc, club100Svc)
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
 	ctx := context.Background()
 
 	// Create a POS device
@@ -183,7 +202,7 @@ func TestPOSService_PayCash(t *testing.T) {
 	t.Run("PayCash processes payment", func(t *testing.T) {
 		order := fixtures.CreateOrder(1000, entOrder.StatusPending, entOrder.OriginPos)
 
-		err := svc.PayCash(ctx, order.ID, &device.ID)
+		err := svc.PayCash(ctx, order.ID, &device.ID, nil)
 		require.NoError(t, err)
 
 		// Verify order status
@@ -195,13 +214,13 @@ func TestPOSService_PayCash(t *testing.T) {
 	t.Run("PayCash fails for non-pending order", func(t *testing.T) {
 		order := fixtures.CreateOrder(1000, entOrder.StatusPaid, entOrder.OriginPos)
 
-		err := svc.PayCash(ctx, order.ID, &device.ID)
+		err := svc.PayCash(ctx, order.ID, &device.ID, nil)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not_pending")
+		require.ErrorIs(t, err, repository.ErrOrderNotPending)
 	})
 
 	t.Run("PayCash fails with invalid order ID", func(t *testing.T) {
-		err := svc.PayCash(ctx, "", &device.ID)
+		err := svc.PayCash(ctx, "", &device.ID, nil)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid order id")
 	})
@@ -222,16 +241,20 @@ func TestPOSService_PayCard(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 
-	club100Svc := service.NewClub100Service(&MockElvantoService{}, repos.Club100Redemption, repos.Settings, repos.OrderLine)
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
 
-	svc := service.NewPOSService(cfg, repos.Device, repos.Order, paymentSvc, club100Svc)
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
 	ctx := context.Background()
 
 	device := fixtures.CreateDevice("POS 1", "pos-token", entDevice.TypePOS, entDevice.StatusApproved)
@@ -239,7 +262,7 @@ func TestPOSService_PayCard(t *testing.T) {
 	t.Run("PayCard processes payment", func(t *testing.T) {
 		order := fixtures.CreateOrder(1000, entOrder.StatusPending, entOrder.OriginPos)
 
-		err := svc.PayCard(ctx, order.ID, &device.ID, nil)
+		err := svc.PayCard(ctx, order.ID, &device.ID, nil, nil)
 		require.NoError(t, err)
 
 		// Verify order status
@@ -251,9 +274,9 @@ func TestPOSService_PayCard(t *testing.T) {
 	t.Run("PayCard fails for non-pending order", func(t *testing.T) {
 		order := fixtures.CreateOrder(1000, entOrder.StatusPaid, entOrder.OriginPos)
 
-		err := svc.PayCard(ctx, order.ID, &device.ID, nil)
+		err := svc.PayCard(ctx, order.ID, &device.ID, nil, nil)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not_pending")
+		require.ErrorIs(t, err, repository.ErrOrderNotPending)
 	})
 }
 
@@ -272,16 +295,20 @@ func TestPOSService_PayTwint(t *testing.T) {
 		repos.OrderLine,
 		repos.OrderPayment,
 		NewProductSvc(repos),
+		nil,
+		nil,
 		repos.MenuSlot,
 		repos.Inventory,
 		nil,
 		nil,
+		nil,
 		zap.NewNop(),
 	)
 
-	club100Svc := service.NewClub100Service(&MockElvantoService{}, repos.Club100Redemption, repos.Settings, repos.OrderLine)
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
 
-	svc := service.NewPOSService(cfg, repos.Device, repos.Order, paymentSvc, club100Svc)
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
 	ctx := context.Background()
 
 	device := fixtures.CreateDevice("POS 1", "pos-token", entDevice.TypePOS, entDevice.StatusApproved)
@@ -289,7 +316,7 @@ func TestPOSService_PayTwint(t *testing.T) {
 	t.Run("PayTwint processes payment", func(t *testing.T) {
 		order := fixtures.CreateOrder(1000, entOrder.StatusPending, entOrder.OriginPos)
 
-		err := svc.PayTwint(ctx, order.ID, &device.ID)
+		err := svc.PayTwint(ctx, order.ID, &device.ID, nil)
 		require.NoError(t, err)
 
 		// Verify order status
@@ -301,8 +328,113 @@ func TestPOSService_PayTwint(t *testing.T) {
 	t.Run("PayTwint fails for non-pending order", func(t *testing.T) {
 		order := fixtures.CreateOrder(1000, entOrder.StatusPaid, entOrder.OriginPos)
 
-		err := svc.PayTwint(ctx, order.ID, &device.ID)
+		err := svc.PayTwint(ctx, order.ID, &device.ID, nil)
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not_pending")
+		require.ErrorIs(t, err, repository.ErrOrderNotPending)
+	})
+}
+
+func TestPOSService_DeviceConfig(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	cfg := TestConfig()
+
+	paymentSvc := service.NewPaymentService(
+		cfg,
+		repos.Order,
+		repos.OrderLine,
+		repos.OrderPayment,
+		NewProductSvc(repos),
+		nil,
+		nil,
+		repos.MenuSlot,
+		repos.Inventory,
+		nil,
+		nil,
+		nil,
+		zap.NewNop(),
+	)
+
+	club100Svc := service.NewClub100Service(membership.NewUnconfiguredFake(), repos.Club100Member, repos.Club100Period, repos.Club100Redemption, repos.Settings, repos.OrderLine, repos.MembershipUpload, tdb.Client, cfg, zap.NewNop())
+
+	settingsSvc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+	svc := service.NewPOSService(cfg, repos.Device, repos.Category, repos.Order, paymentSvc, settingsSvc, club100Svc, nil, tdb.Client)
+	ctx := context.Background()
+
+	drinks := fixtures.CreateCategory("Drinks", 1, true)
+	food := fixtures.CreateCategory("Food", 2, true)
+	jeton := fixtures.CreateJeton("Red", "#EF4444")
+	cola := fixtures.CreateProduct("Cola", drinks.ID, 350, entProduct.TypeSimple, &jeton.ID)
+	burger := fixtures.CreateProduct("Burger", food.ID, 1200, entProduct.TypeSimple, &jeton.ID)
+	device := fixtures.CreateDevice("POS Bar", "pos-bar", entDevice.TypePOS, entDevice.StatusApproved)
+
+	t.Run("SetConfig stores overrides in canonical order", func(t *testing.T) {
+		mode := entDevice.PosModeJETON
+		updated, err := svc.SetConfig(ctx, device.ID, repository.DevicePosConfig{
+			PosMode:            &mode,
+			PaymentMethods:     []string{"twint", "cash", "cash"},
+			VisibleCategoryIDs: []string{drinks.ID},
+			GratisTypes:        []string{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"cash", "twint"}, updated.PaymentMethods)
+		require.Equal(t, []string{drinks.ID}, updated.VisibleCategoryIds)
+		require.Empty(t, updated.GratisTypes)
+
+		s, err := settingsSvc.GetSettings(ctx)
+		require.NoError(t, err)
+		merged := service.MergePosConfig(s, updated)
+		require.Equal(t, entSettings.PosModeJETON, merged.PosMode)
+		require.Equal(t, []string{"cash", "twint"}, merged.PaymentMethods)
+		require.Empty(t, merged.GratisTypes)
+	})
+
+	t.Run("SetConfig rejects unknown payment methods", func(t *testing.T) {
+		_, err := svc.SetConfig(ctx, device.ID, repository.DevicePosConfig{PaymentMethods: []string{"bitcoin"}})
+		require.ErrorIs(t, err, service.ErrPosConfigInvalid)
+	})
+
+	t.Run("SetConfig rejects station devices", func(t *testing.T) {
+		station := fixtures.CreateDevice("Station", "station-cfg", entDevice.TypeSTATION, entDevice.StatusApproved)
+		_, err := svc.SetConfig(ctx, station.ID, repository.DevicePosConfig{})
+		require.ErrorIs(t, err, service.ErrPosConfigInvalid)
+	})
+
+	t.Run("payment with a disabled method is rejected", func(t *testing.T) {
+		order := fixtures.CreateOrder(350, entOrder.StatusPending, entOrder.OriginPos)
+		fixtures.CreateOrderLine(order.ID, cola.ID, "Cola", 1, 350, entOrderLine.LineTypeSimple)
+
+		err := svc.PayCard(ctx, order.ID, &device.ID, nil, nil)
+		require.ErrorIs(t, err, service.ErrPaymentMethodNotAllowed)
+
+		err = svc.PayGratisGuest(ctx, order.ID, &device.ID)
+		require.ErrorIs(t, err, service.ErrPaymentMethodNotAllowed)
+
+		require.NoError(t, svc.PayCash(ctx, order.ID, &device.ID, nil))
+	})
+
+	t.Run("payment for a hidden category is rejected", func(t *testing.T) {
+		order := fixtures.CreateOrder(1200, entOrder.StatusPending, entOrder.OriginPos)
+		fixtures.CreateOrderLine(order.ID, burger.ID, "Burger", 1, 1200, entOrderLine.LineTypeSimple)
+
+		err := svc.PayCash(ctx, order.ID, &device.ID, nil)
+		require.ErrorIs(t, err, service.ErrCategoryNotOnDevice)
+
+		updated, err := repos.Order.GetByID(ctx, order.ID)
+		require.NoError(t, err)
+		require.Equal(t, entOrder.StatusPending, updated.Status)
+	})
+
+	t.Run("clearing overrides inherits the global settings", func(t *testing.T) {
+		_, err := svc.SetConfig(ctx, device.ID, repository.DevicePosConfig{})
+		require.NoError(t, err)
+
+		order := fixtures.CreateOrder(1200, entOrder.StatusPending, entOrder.OriginPos)
+		fixtures.CreateOrderLine(order.ID, burger.ID, "Burger", 1, 1200, entOrderLine.LineTypeSimple)
+		require.NoError(t, svc.PayCard(ctx, order.ID, &device.ID, nil, nil))
 	})
 }
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/generated/ent/inventoryledger"
+	"backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/orderline"
+	"backend/internal/generated/ent/pricerule"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+	"backend/internal/schedule"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPriceRules(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	rules := service.NewPriceRuleService(repos.PriceRule)
+	orders := service.NewOrderService(repos.Order, repos.OrderLine, repos.Inventory, nil)
+	payments := service.NewPaymentService(TestConfig(), repos.Order, repos.OrderLine, repos.OrderPayment, NewProductSvc(repos), nil,
+		rules, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+
+	drinks := fixtures.CreateCategory("Getränke", 1, true)
+	food := fixtures.CreateCategory("Essen", 2, true)
+	beer := fixtures.CreateProduct("Bier", drinks.ID, 600, product.TypeSimple, nil)
+	fries := fixtures.CreateProduct("Pommes", food.ID, 500, product.TypeSimple, nil)
+	fixtures.AddInventory(beer.ID, 50, inventoryledger.ReasonOpeningBalance)
+	fixtures.AddInventory(fries.ID, 50, inventoryledger.ReasonOpeningBalance)
+
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2026, time.July, 17, hour, minute, 0, 0, zurich)
+	}
+
+	t.Run("rejects rules without exactly one scope or with a bad window", func(t *testing.T) {
+		_, err := rules.Create(ctx, repository.PriceRuleInput{
+			Name: "Ohne", Weekdays: 127, StartMinute: 60, EndMinute: 120, Kind: pricerule.KindPercent, Value: 10,
+		})
+		require.ErrorIs(t, err, service.ErrPriceRuleInvalid)
+		_, err = rules.Create(ctx, repository.PriceRuleInput{
+			Name: "Beides", ProductID: &beer.ID, CategoryID: &drinks.ID, Weekdays: 127, StartMinute: 60, EndMinute: 120,
+			Kind: pricerule.KindPercent, Value: 10,
+		})
+		require.ErrorIs(t, err, service.ErrPriceRuleInvalid)
+		_, err = rules.Create(ctx, repository.PriceRuleInput{
+			Name: "Verkehrt", ProductID: &beer.ID, Weekdays: 127, StartMinute: 120, EndMinute: 60,
+			Kind: pricerule.KindPercent, Value: 10,
+		})
+		require.ErrorIs(t, err, service.ErrPriceRuleInvalid)
+		_, err = rules.Create(ctx, repository.PriceRuleInput{
+			Name: "Zu viel", ProductID: &beer.ID, Weekdays: 127, StartMinute: 60, EndMinute: 120,
+			Kind: pricerule.KindPercent, Value: 150,
+		})
+		require.ErrorIs(t, err, service.ErrPriceRuleInvalid)
+	})
+
+	happyHour, err := rules.Create(ctx, repository.PriceRuleInput{
+		Name: "Happy Hour", CategoryID: &drinks.ID, Weekdays: int(schedule.WeekdaysOf(time.Friday)),
+		StartMinute: 17 * 60, EndMinute: 19 * 60, Kind: pricerule.KindPercent, Value: 50, Active: true,
+	})
+	require.NoError(t, err)
+	beerDeal, err := rules.Create(ctx, repository.PriceRuleInput{
+		Name: "Bier-Aktion", ProductID: &beer.ID, Weekdays: int(schedule.AllWeekdays),
+		StartMinute: 16 * 60, EndMinute: 18 * 60, Kind: pricerule.KindAmount, Value: 100, Active: true,
+	})
+	require.NoError(t, err)
+
+	t.Run("rules apply inside their window and the lowest price wins", func(t *testing.T) {
+		at, err := rules.At(ctx, friday(16, 30))
+		require.NoError(t, err)
+		price, rule := at.Apply(beer, beer.PriceCents)
+		require.Equal(t, int64(500), price)
+		require.Equal(t, beerDeal.ID, rule.ID)
+
+		at, err = rules.At(ctx, friday(17, 30))
+		require.NoError(t, err)
+		price, rule = at.Apply(beer, beer.PriceCents)
+		require.Equal(t, int64(300), price, "half price beats one franc off")
+		require.Equal(t, happyHour.ID, rule.ID)
+		price, rule = at.Apply(fries, fries.PriceCents)
+		require.Equal(t, int64(500), price)
+		require.Nil(t, rule)
+
+		at, err = rules.At(ctx, friday(19, 0))
+		require.NoError(t, err)
+		_, rule = at.Apply(beer, beer.PriceCents)
+		require.Nil(t, rule, "the window end is exclusive")
+
+		at, err = rules.At(ctx, friday(17, 30).AddDate(0, 0, 1))
+		require.NoError(t, err)
+		_, rule = at.Apply(beer, beer.PriceCents)
+		require.Nil(t, rule, "happy hour is on Fridays only")
+	})
+
+	t.Run("checkout snapshots the rule and the list price", func(t *testing.T) {
+		clearance, err := rules.Create(ctx, repository.PriceRuleInput{
+			Name: "Ausverkauf", CategoryID: &food.ID, Weekdays: int(schedule.AllWeekdays),
+			StartMinute: 0, EndMinute: schedule.MinutesPerDay, Kind: pricerule.KindAmount, Value: 200, Active: true,
+		})
+		require.NoError(t, err)
+
+		prep, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: fries.ID, Quantity: 2}},
+		}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(600), prep.TotalCents)
+
+		lines, err := repos.OrderLine.GetByOrderID(ctx, prep.OrderID)
+		require.NoError(t, err)
+		require.Len(t, lines, 1)
+		require.Equal(t, orderline.LineTypeSimple, lines[0].LineType)
+		require.Equal(t, int64(300), lines[0].UnitPriceCents)
+		require.NotNil(t, lines[0].PriceRuleID)
+		require.Equal(t, clearance.ID, *lines[0].PriceRuleID)
+		require.NotNil(t, lines[0].ListPriceCents)
+		require.Equal(t, int64(500), *lines[0].ListPriceCents)
+
+		require.NoError(t, orders.UpdateStatus(ctx, prep.OrderID, order.StatusPaid))
+		usage, err := rules.Usage(ctx, nil)
+		require.NoError(t, err)
+		require.Equal(t, []repository.PriceRuleUsage{
+			{PriceRuleID: clearance.ID, Quantity: 2, RevenueCents: 600, DiscountCents: 400},
+		}, usage)
+
+		require.ErrorIs(t, rules.Delete(ctx, clearance.ID), service.ErrPriceRuleInUse)
+		inactive := false
+		_, err = rules.Update(ctx, clearance.ID, service.PriceRulePatch{Active: &inactive})
+		require.NoError(t, err)
+
+		prep, err = payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: fries.ID, Quantity: 1}},
+		}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(500), prep.TotalCents, "inactive rules no longer apply")
+	})
+
+	t.Run("unused rules can be deleted", func(t *testing.T) {
+		require.NoError(t, rules.Delete(ctx, happyHour.ID))
+		_, err := rules.Get(ctx, happyHour.ID)
+		require.ErrorIs(t, err, service.ErrPriceRuleNotFound)
+	})
+
+	t.Run("deleting a category drops its rules but keeps the priced lines", func(t *testing.T) {
+		require.NoError(t, tdb.Client.Product.UpdateOneID(fries.ID).SetCategoryID(drinks.ID).Exec(ctx))
+		require.NoError(t, tdb.Client.Category.DeleteOneID(food.ID).Exec(ctx))
+
+		lines, err := tdb.Client.OrderLine.Query().
+			Where(orderline.ProductIDEQ(fries.ID), orderline.ListPriceCentsNotNil()).
+			All(ctx)
+		require.NoError(t, err)
+		require.Len(t, lines, 1)
+		require.Nil(t, lines[0].PriceRuleID)
+		require.Equal(t, int64(500), *lines[0].ListPriceCents)
+	})
+}
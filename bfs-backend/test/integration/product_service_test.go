@@ -21,12 +21,16 @@ func TestProductService_ListProducts(t *testing.T) {
 
 	svc := service.NewProductService(
 		repos.Product,
+		repos.ProductVariant,
 		repos.Category,
 		repos.MenuSlot,
 		repos.MenuSlotOption,
 		repos.Inventory,
 		repos.Jeton,
+		repos.Availability,
 		nil,
+		nil,
+		tdb.Client,
 	)
 	ctx := context.Background()
 
@@ -93,12 +97,16 @@ func TestProductService_MenuProducts(t *testing.T) {
 
 	svc := service.NewProductService(
 		repos.Product,
+		repos.ProductVariant,
 		repos.Category,
 		repos.MenuSlot,
 		repos.MenuSlotOption,
 		repos.Inventory,
 		repos.Jeton,
+		repos.Availability,
+		nil,
 		nil,
+		tdb.Client,
 	)
 	ctx := context.Background()
 
@@ -161,12 +169,16 @@ func TestProductService_EmptyResults(t *testing.T) {
 
 	svc := service.NewProductService(
 		repos.Product,
+		repos.ProductVariant,
 		repos.Category,
 		repos.MenuSlot,
 		repos.MenuSlotOption,
 		repos.Inventory,
 		repos.Jeton,
+		repos.Availability,
+		nil,
 		nil,
+		tdb.Client,
 	)
 	ctx := context.Background()
 
@@ -0,0 +1,184 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	entDevice "backend/internal/generated/ent/device"
+	entOrder "backend/internal/generated/ent/order"
+	"backend/internal/generated/ent/orderline"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProductVariants(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	cfg := TestConfig()
+	ctx := context.Background()
+
+	products := NewProductSvc(repos)
+	payments := service.NewPaymentService(
+		cfg,
+		repos.Order,
+		repos.OrderLine,
+		repos.OrderPayment,
+		products,
+		nil,
+		nil,
+		repos.MenuSlot,
+		repos.Inventory,
+		nil,
+		nil,
+		nil,
+		zap.NewNop(),
+	)
+	stations := service.NewStationService(
+		cfg,
+		tdb.Client,
+		repos.Device,
+		repos.DeviceProduct,
+		repos.OrderLine,
+		repos.OrderRedemption,
+		repos.RedemptionBatch,
+		repos.Idempotency,
+		repos.Order,
+		nil,
+	)
+
+	category := fixtures.CreateCategory("Drinks", 1, true)
+	cola := fixtures.CreateProduct("Cola", category.ID, 350, product.TypeSimple, nil)
+	bar := fixtures.CreateDevice("Bar", "bar-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	tap := fixtures.CreateDevice("Tap", "tap-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(bar.ID, cola.ID)
+
+	small, err := products.CreateVariant(ctx, cola.ID, repository.ProductVariantInput{Name: "3dl", PriceCents: 350, IsActive: true})
+	require.NoError(t, err)
+	large, err := products.CreateVariant(ctx, cola.ID, repository.ProductVariantInput{
+		Name: "5dl", PriceCents: 500, Position: 1, IsActive: true, StationIDs: []string{tap.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, large.Edges.DeviceVariants, 1)
+
+	require.NoError(t, products.AdjustVariantStock(ctx, cola.ID, small.ID, 20, "opening_balance"))
+	require.NoError(t, products.AdjustVariantStock(ctx, cola.ID, large.ID, 10, "opening_balance"))
+
+	t.Run("rejects invalid variants", func(t *testing.T) {
+		_, err := products.CreateVariant(ctx, cola.ID, repository.ProductVariantInput{Name: " ", IsActive: true})
+		require.ErrorIs(t, err, service.ErrVariantInvalid)
+
+		_, err = products.CreateVariant(ctx, cola.ID, repository.ProductVariantInput{Name: "3dl", IsActive: true})
+		require.ErrorIs(t, err, repository.ErrConflict)
+
+		menu := fixtures.CreateProduct("Menu", category.ID, 1200, product.TypeMenu, nil)
+		_, err = products.CreateVariant(ctx, menu.ID, repository.ProductVariantInput{Name: "Gross", IsActive: true})
+		require.ErrorIs(t, err, service.ErrVariantInvalid)
+	})
+
+	t.Run("checkout requires a variant", func(t *testing.T) {
+		_, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: cola.ID, Quantity: 1}},
+		}, nil, nil)
+		require.ErrorIs(t, err, service.ErrVariantRequired)
+	})
+
+	t.Run("checkout charges and books the variant", func(t *testing.T) {
+		prep, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{
+				{ProductID: cola.ID, VariantID: &large.ID, Quantity: 2},
+				{ProductID: cola.ID, VariantID: &small.ID, Quantity: 1},
+			},
+		}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1350), prep.TotalCents)
+
+		lines, err := repos.OrderLine.GetByOrderID(ctx, prep.OrderID)
+		require.NoError(t, err)
+		require.Len(t, lines, 2)
+		for _, l := range lines {
+			require.NotNil(t, l.VariantID)
+			require.NotNil(t, l.VariantName)
+		}
+
+		stock, err := repos.Inventory.GetVariantStockBatch(ctx, []string{small.ID, large.ID})
+		require.NoError(t, err)
+		require.Equal(t, 19, stock[small.ID])
+		require.Equal(t, 8, stock[large.ID])
+
+		total, err := repos.Inventory.GetCurrentStock(ctx, cola.ID)
+		require.NoError(t, err)
+		require.Equal(t, 27, total)
+	})
+
+	t.Run("menus cannot offer products with variants", func(t *testing.T) {
+		menu := fixtures.CreateProduct("Colamenü", category.ID, 900, product.TypeMenu, nil)
+		slot := fixtures.CreateMenuSlot(menu.ID, "Getränk", 0)
+		fixtures.CreateMenuSlotOption(slot.ID, cola.ID)
+
+		_, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: menu.ID, Quantity: 1, Configuration: map[string]string{slot.ID: cola.ID}}},
+		}, nil, nil)
+		require.ErrorIs(t, err, service.ErrVariantInvalid)
+	})
+
+	t.Run("variants route to their own stations", func(t *testing.T) {
+		ord := fixtures.CreateOrder(850, entOrder.StatusPaid, entOrder.OriginShop)
+		_, err := repos.OrderLine.CreateBatch(ctx, []repository.OrderLineCreateParams{
+			{OrderID: ord.ID, LineType: orderline.LineTypeSimple, ProductID: cola.ID, Title: "Cola", Quantity: 1, UnitPriceCents: 350, VariantID: &small.ID, VariantName: &small.Name},
+			{OrderID: ord.ID, LineType: orderline.LineTypeSimple, ProductID: cola.ID, Title: "Cola", Quantity: 1, UnitPriceCents: 500, VariantID: &large.ID, VariantName: &large.Name},
+		})
+		require.NoError(t, err)
+
+		atBar, err := stations.AssignedItemsForOrder(ctx, bar.ID, ord.ID)
+		require.NoError(t, err)
+		require.Len(t, atBar, 1)
+		require.Equal(t, small.ID, *atBar[0].VariantID)
+
+		atTap, err := stations.AssignedItemsForOrder(ctx, tap.ID, ord.ID)
+		require.NoError(t, err)
+		require.Len(t, atTap, 1)
+		require.Equal(t, large.ID, *atTap[0].VariantID)
+	})
+
+	t.Run("inactive variants cannot be ordered", func(t *testing.T) {
+		in := repository.ProductVariantInput{Name: "5dl", PriceCents: 500, Position: 1, IsActive: false}
+		_, err := products.UpdateVariant(ctx, cola.ID, large.ID, in)
+		require.NoError(t, err)
+
+		_, err = payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: cola.ID, VariantID: &large.ID, Quantity: 1}},
+		}, nil, nil)
+		require.ErrorIs(t, err, service.ErrVariantInvalid)
+	})
+
+	t.Run("event prices move the variants along", func(t *testing.T) {
+		events := service.NewEventService(repos.Event, tdb.Client)
+		atEvent := service.NewPaymentService(cfg, repos.Order, repos.OrderLine, repos.OrderPayment, products, events,
+			nil, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+		now := time.Now()
+		fest, err := events.Create(ctx, service.EventInput{Name: "Sommerfest", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Active: true})
+		require.NoError(t, err)
+		price := int64(300)
+		_, err = events.SetProducts(ctx, fest.ID, []repository.EventProductInput{{ProductID: cola.ID, Available: true, PriceCents: &price}})
+		require.NoError(t, err)
+
+		prep, err := atEvent.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items: []service.CheckoutItemInput{{ProductID: cola.ID, VariantID: &small.ID, Quantity: 2}},
+		}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(600), prep.TotalCents)
+	})
+
+	t.Run("sold variants cannot be deleted", func(t *testing.T) {
+		require.ErrorIs(t, products.DeleteVariant(ctx, cola.ID, large.ID), repository.ErrConflict)
+	})
+}
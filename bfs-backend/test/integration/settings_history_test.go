@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/auth"
+	"backend/internal/generated/ent/product"
+	"backend/internal/generated/ent/settings"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsService_History(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+
+	ctx := context.WithValue(context.Background(), auth.UserIDKey, "user-anna")
+	ctx = context.WithValue(ctx, auth.UserNameKey, "Anna")
+
+	category := fixtures.CreateCategory("Grill", 1, true)
+	bratwurst := fixtures.CreateProduct("Bratwurst", category.ID, 800, product.TypeSimple, nil)
+
+	red, err := svc.CreateJeton(ctx, "Rot", "#ff0000")
+	require.NoError(t, err)
+	require.NoError(t, svc.SetProductJeton(ctx, bratwurst.ID, &red.ID))
+	require.NoError(t, svc.SetPosMode(ctx, settings.PosModeJETON))
+	require.NoError(t, svc.SetPosMode(ctx, settings.PosModeJETON), "no-op")
+
+	t.Run("records who changed what", func(t *testing.T) {
+		history, err := svc.History(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 3, "baseline, jeton, pos mode")
+
+		latest := history[0]
+		require.Equal(t, 3, latest.Version)
+		require.Equal(t, "pos_mode", latest.Change)
+		require.Equal(t, "user-anna", *latest.CreatedBy)
+		require.Equal(t, "Anna", *latest.CreatedByName)
+		require.Equal(t, []service.SettingsChange{{Field: "posMode", From: "QR_CODE", To: "JETON"}}, latest.Changes)
+
+		require.Equal(t, "baseline", history[2].Change)
+		require.Nil(t, history[2].CreatedBy)
+		require.Empty(t, history[2].Changes)
+
+		older, err := svc.History(ctx, 10, 2)
+		require.NoError(t, err)
+		require.Len(t, older, 1)
+		require.Equal(t, 1, older[0].Version)
+	})
+
+	t.Run("rollback restores a version", func(t *testing.T) {
+		_, err := svc.UpdateJeton(ctx, red.ID, "Rot", "#aa0000")
+		require.NoError(t, err)
+
+		entry, err := svc.Rollback(ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, "rollback", entry.Change)
+		require.Equal(t, 2, *entry.RolledBackFrom)
+		require.Len(t, entry.Changes, 2, "pos mode and jeton colour")
+
+		s, err := svc.GetSettings(ctx)
+		require.NoError(t, err)
+		require.Equal(t, settings.PosModeQR_CODE, s.PosMode)
+		jeton, err := repos.Jeton.GetByID(ctx, red.ID)
+		require.NoError(t, err)
+		require.Equal(t, "#FF0000", jeton.Color)
+	})
+
+	t.Run("rollback recreates deleted jetons", func(t *testing.T) {
+		require.NoError(t, svc.SetProductJeton(ctx, bratwurst.ID, nil))
+		require.NoError(t, svc.DeleteJeton(ctx, red.ID))
+
+		_, err := svc.Rollback(ctx, 2)
+		require.NoError(t, err)
+		jeton, err := repos.Jeton.GetByID(ctx, red.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Rot", jeton.Name)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := svc.Rollback(ctx, 99)
+		require.ErrorIs(t, err, service.ErrSettingsVersionNotFound)
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"backend/internal/generated/ent/product"
 	"backend/internal/generated/ent/settings"
+	"backend/internal/repository"
 	"backend/internal/service"
 
 	nanoid "backend/internal/id"
@@ -19,7 +20,7 @@ func TestSettingsService_GetSettings(t *testing.T) {
 
 	repos := NewRepositories(tdb.Client)
 
-	svc := service.NewSettingsService(repos.Settings, repos.Jeton, repos.Product)
+	svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
 	ctx := context.Background()
 
 	t.Run("GetSettings returns default settings when none exist", func(t *testing.T) {
@@ -39,7 +40,7 @@ func TestSettingsService_SetPosMode(t *testing.T) {
 	repos := NewRepositories(tdb.Client)
 	fixtures := NewFixtures(repos)
 
-	svc := service.NewSettingsService(repos.Settings, repos.Jeton, repos.Product)
+	svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
 	ctx := context.Background()
 
 	t.Run("SetPosMode to QR_CODE succeeds", func(t *testing.T) {
@@ -97,7 +98,7 @@ func TestSettingsService_JetonCRUD(t *testing.T) {
 
 	repos := NewRepositories(tdb.Client)
 
-	svc := service.NewSettingsService(repos.Settings, repos.Jeton, repos.Product)
+	svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
 	ctx := context.Background()
 
 	t.Run("CreateJeton creates new jeton with hex color", func(t *testing.T) {
@@ -185,7 +186,7 @@ func TestSettingsService_JetonCRUD(t *testing.T) {
 
 		repos := NewRepositories(tdb.Client)
 		fixtures := NewFixtures(repos)
-		svc := service.NewSettingsService(repos.Settings, repos.Jeton, repos.Product)
+		svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
 
 		jeton := fixtures.CreateJeton("InUse", "#EF4444")
 		category := fixtures.CreateCategory("Drinks", 1, true)
@@ -198,6 +199,30 @@ func TestSettingsService_JetonCRUD(t *testing.T) {
 		require.ErrorAs(t, err, &inUseErr)
 		require.EqualValues(t, 1, inUseErr.Count)
 	})
+
+	t.Run("DeleteJeton fails for jeton used only by a variant", func(t *testing.T) {
+		tdb.Cleanup(t)
+
+		repos := NewRepositories(tdb.Client)
+		fixtures := NewFixtures(repos)
+		svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
+
+		jeton := fixtures.CreateJeton("Gross", "#EF4444")
+		category := fixtures.CreateCategory("Drinks", 1, true)
+		cola := fixtures.CreateProduct("Cola", category.ID, 350, product.TypeSimple, nil)
+		_, err := repos.ProductVariant.Create(ctx, cola.ID, repository.ProductVariantInput{
+			Name: "5dl", PriceCents: 500, IsActive: true, JetonID: &jeton.ID,
+		})
+		require.NoError(t, err)
+
+		err = svc.DeleteJeton(ctx, jeton.ID)
+		var inUseErr service.JetonInUseError
+		require.ErrorAs(t, err, &inUseErr)
+		require.EqualValues(t, 1, inUseErr.Count)
+
+		_, err = repos.Jeton.GetByID(ctx, jeton.ID)
+		require.NoError(t, err)
+	})
 }
 
 func TestSettingsService_SetProductJeton(t *testing.T) {
@@ -208,7 +233,7 @@ func TestSettingsService_SetProductJeton(t *testing.T) {
 	repos := NewRepositories(tdb.Client)
 	fixtures := NewFixtures(repos)
 
-	svc := service.NewSettingsService(repos.Settings, repos.Jeton, repos.Product)
+	svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
 	ctx := context.Background()
 
 	t.Run("SetProductJeton assigns jeton to product", func(t *testing.T) {
@@ -261,7 +286,7 @@ func TestSettingsService_SetProductJeton(t *testing.T) {
 
 		repos := NewRepositories(tdb.Client)
 		fixtures := NewFixtures(repos)
-		svc := service.NewSettingsService(repos.Settings, repos.Jeton, repos.Product)
+		svc := service.NewSettingsService(repos.Settings, repos.SettingsVersion, repos.Jeton, repos.Product, tdb.Client)
 
 		category := fixtures.CreateCategory("Drinks", 1, true)
 		jeton := fixtures.CreateJeton("Red", "#EF4444")
@@ -2,12 +2,17 @@ package integration
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	entDevice "backend/internal/generated/ent/device"
 	entOrder "backend/internal/generated/ent/order"
 	"backend/internal/generated/ent/orderline"
 	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
 	"backend/internal/service"
 
 	"github.com/stretchr/testify/require"
@@ -29,7 +34,10 @@ func TestStationService_GetStationByKey(t *testing.T) {
 		repos.DeviceProduct,
 		repos.OrderLine,
 		repos.OrderRedemption,
+		repos.RedemptionBatch,
 		repos.Idempotency,
+		repos.Order,
+		nil,
 	)
 	ctx := context.Background()
 
@@ -72,7 +80,10 @@ func TestStationService_AssignedItemsForOrder(t *testing.T) {
 		repos.DeviceProduct,
 		repos.OrderLine,
 		repos.OrderRedemption,
+		repos.RedemptionBatch,
 		repos.Idempotency,
+		repos.Order,
+		nil,
 	)
 	ctx := context.Background()
 
@@ -134,7 +145,10 @@ func TestStationService_RedeemAssigned(t *testing.T) {
 		repos.DeviceProduct,
 		repos.OrderLine,
 		repos.OrderRedemption,
+		repos.RedemptionBatch,
 		repos.Idempotency,
+		repos.Order,
+		nil,
 	)
 	ctx := context.Background()
 
@@ -210,3 +224,297 @@ func TestStationService_RedeemAssigned(t *testing.T) {
 		require.EqualValues(t, 1, result2["matched"])  // Still matches
 	})
 }
+
+func TestStationService_Queue(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	cfg := TestConfig()
+
+	svc := service.NewStationService(
+		cfg,
+		tdb.Client,
+		repos.Device,
+		repos.DeviceProduct,
+		repos.OrderLine,
+		repos.OrderRedemption,
+		repos.RedemptionBatch,
+		repos.Idempotency,
+		repos.Order,
+		nil,
+	)
+	ctx := context.Background()
+
+	category := fixtures.CreateCategory("Food", 1, true)
+	fries := fixtures.CreateProduct("Fries", category.ID, 500, product.TypeSimple, nil)
+	cola := fixtures.CreateProduct("Cola", category.ID, 350, product.TypeSimple, nil)
+
+	station := fixtures.CreateDevice("Fries Station", "station-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(station.ID, fries.ID)
+
+	first := fixtures.CreateOrder(850, entOrder.StatusPaid, entOrder.OriginShop)
+	fixtures.CreateOrderLine(first.ID, fries.ID, "Fries", 1, 500, orderline.LineTypeSimple)
+	fixtures.CreateOrderLine(first.ID, cola.ID, "Cola", 1, 350, orderline.LineTypeSimple)
+
+	second := fixtures.CreateOrder(500, entOrder.StatusPaid, entOrder.OriginPos)
+	fixtures.CreateOrderLine(second.ID, fries.ID, "Fries", 2, 500, orderline.LineTypeSimple)
+
+	pending := fixtures.CreateOrder(500, entOrder.StatusPending, entOrder.OriginShop)
+	fixtures.CreateOrderLine(pending.ID, fries.ID, "Fries", 1, 500, orderline.LineTypeSimple)
+
+	drinksOnly := fixtures.CreateOrder(350, entOrder.StatusPaid, entOrder.OriginShop)
+	fixtures.CreateOrderLine(drinksOnly.ID, cola.ID, "Cola", 1, 350, orderline.LineTypeSimple)
+
+	t.Run("Queue lists paid orders with pending station items oldest first", func(t *testing.T) {
+		entries, err := svc.Queue(ctx, station.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, first.ID, entries[0].OrderID)
+		require.Equal(t, second.ID, entries[1].OrderID)
+
+		require.Len(t, entries[0].Items, 1)
+		require.Equal(t, "Fries", entries[0].Items[0].Title)
+	})
+
+	t.Run("Queue drops orders once redeemed", func(t *testing.T) {
+		_, err := svc.RedeemAssigned(ctx, station.ID, first.ID, "")
+		require.NoError(t, err)
+
+		entries, err := svc.Queue(ctx, station.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, second.ID, entries[0].OrderID)
+	})
+
+	t.Run("QueueEntryForOrder returns empty items for redeemed order", func(t *testing.T) {
+		entry, err := svc.QueueEntryForOrder(ctx, station.ID, first.ID)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		require.Empty(t, entry.Items)
+	})
+
+	t.Run("QueueEntryForOrder returns nil for unrelated order", func(t *testing.T) {
+		entry, err := svc.QueueEntryForOrder(ctx, station.ID, drinksOnly.ID)
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+}
+
+func TestStationService_RedeemSelected(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	cfg := TestConfig()
+
+	svc := service.NewStationService(
+		cfg,
+		tdb.Client,
+		repos.Device,
+		repos.DeviceProduct,
+		repos.OrderLine,
+		repos.OrderRedemption,
+		repos.RedemptionBatch,
+		repos.Idempotency,
+		repos.Order,
+		nil,
+	)
+	ctx := context.Background()
+
+	category := fixtures.CreateCategory("Grill", 1, true)
+	sausage := fixtures.CreateProduct("Sausage", category.ID, 600, product.TypeSimple, nil)
+	bread := fixtures.CreateProduct("Bread", category.ID, 100, product.TypeSimple, nil)
+
+	station := fixtures.CreateDevice("Grill Station", "station-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(station.ID, sausage.ID)
+	fixtures.AssignProductToDevice(station.ID, bread.ID)
+
+	ord := fixtures.CreateOrder(1900, entOrder.StatusPaid, entOrder.OriginShop)
+	sausageLine := fixtures.CreateOrderLine(ord.ID, sausage.ID, "Sausage", 3, 600, orderline.LineTypeSimple)
+	breadLine := fixtures.CreateOrderLine(ord.ID, bread.ID, "Bread", 1, 100, orderline.LineTypeSimple)
+
+	qty := func(n int) *int { return &n }
+
+	t.Run("RedeemSelected redeems a partial quantity of one line", func(t *testing.T) {
+		result, err := svc.RedeemSelected(ctx, station.ID, ord.ID, []service.RedeemSelection{
+			{OrderLineID: sausageLine.ID, Quantity: qty(2)},
+		}, "")
+		require.NoError(t, err)
+		require.EqualValues(t, 1, result["redeemed"])
+		require.EqualValues(t, 2, result["redeemedQuantity"])
+		require.EqualValues(t, 2, result["remainingQuantity"])
+
+		line, err := repos.OrderLine.GetByID(ctx, sausageLine.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, line.RedeemedQuantity)
+		require.Nil(t, line.Edges.Redemption)
+	})
+
+	t.Run("RedeemSelected rejects more than what is open", func(t *testing.T) {
+		_, err := svc.RedeemSelected(ctx, station.ID, ord.ID, []service.RedeemSelection{
+			{OrderLineID: sausageLine.ID, Quantity: qty(2)},
+		}, "")
+		require.ErrorIs(t, err, service.ErrStationQuantityExceedsRemaining)
+	})
+
+	t.Run("RedeemSelected rejects lines not assigned to the station", func(t *testing.T) {
+		_, err := svc.RedeemSelected(ctx, station.ID, ord.ID, []service.RedeemSelection{
+			{OrderLineID: "not-a-line"},
+		}, "")
+		require.ErrorIs(t, err, service.ErrStationLineNotAssigned)
+	})
+
+	t.Run("RedeemAssigned redeems whatever is still open", func(t *testing.T) {
+		result, err := svc.RedeemAssigned(ctx, station.ID, ord.ID, "")
+		require.NoError(t, err)
+		require.EqualValues(t, 2, result["redeemed"])
+		require.EqualValues(t, 2, result["redeemedQuantity"])
+		require.EqualValues(t, 0, result["remainingQuantity"])
+
+		for _, id := range []string{sausageLine.ID, breadLine.ID} {
+			line, err := repos.OrderLine.GetByID(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, line.Quantity, line.RedeemedQuantity)
+			require.NotNil(t, line.Edges.Redemption)
+		}
+	})
+
+	t.Run("concurrent redemptions never hand out fewer units than picked", func(t *testing.T) {
+		ord := fixtures.CreateOrder(1800, entOrder.StatusPaid, entOrder.OriginShop)
+		line := fixtures.CreateOrderLine(ord.ID, sausage.ID, "Sausage", 3, 600, orderline.LineTypeSimple)
+
+		const workers = 6
+		results := make([]map[string]any, workers)
+		errs := make([]error, workers)
+		var wg sync.WaitGroup
+		for i := range workers {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = svc.RedeemSelected(ctx, station.ID, ord.ID, []service.RedeemSelection{
+					{OrderLineID: line.ID, Quantity: qty(2)},
+				}, "")
+			}(i)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for i, err := range errs {
+			if err != nil {
+				if !errors.Is(err, repository.ErrConflict) {
+					require.ErrorIs(t, err, service.ErrStationQuantityExceedsRemaining)
+				}
+				continue
+			}
+			succeeded++
+			require.EqualValues(t, 2, results[i]["redeemedQuantity"])
+		}
+		require.Equal(t, 1, succeeded)
+
+		got, err := repos.OrderLine.GetByID(ctx, line.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, got.RedeemedQuantity)
+	})
+}
+
+func TestStationService_UndoRedemption(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	cfg := TestConfig()
+	cfg.Station.RedemptionUndoWindow = time.Minute
+
+	svc := service.NewStationService(
+		cfg,
+		tdb.Client,
+		repos.Device,
+		repos.DeviceProduct,
+		repos.OrderLine,
+		repos.OrderRedemption,
+		repos.RedemptionBatch,
+		repos.Idempotency,
+		repos.Order,
+		nil,
+	)
+	ctx := context.Background()
+
+	category := fixtures.CreateCategory("Grill", 1, true)
+	sausage := fixtures.CreateProduct("Sausage", category.ID, 600, product.TypeSimple, nil)
+
+	station := fixtures.CreateDevice("Grill Station", "station-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	other := fixtures.CreateDevice("Other Station", "other-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(station.ID, sausage.ID)
+
+	ord := fixtures.CreateOrder(1200, entOrder.StatusPaid, entOrder.OriginShop)
+	line := fixtures.CreateOrderLine(ord.ID, sausage.ID, "Sausage", 2, 600, orderline.LineTypeSimple)
+
+	result, err := svc.RedeemAssigned(ctx, station.ID, ord.ID, "")
+	require.NoError(t, err)
+	batchID, ok := result["batchId"].(string)
+	require.True(t, ok)
+
+	t.Run("UndoRedemption requires a reason", func(t *testing.T) {
+		_, err := svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{DeviceID: &station.ID}, "  ")
+		require.ErrorIs(t, err, service.ErrRedemptionUndoReasonRequired)
+	})
+
+	t.Run("UndoRedemption rejects other stations", func(t *testing.T) {
+		_, err := svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{DeviceID: &other.ID}, "wrong scan")
+		require.ErrorIs(t, err, service.ErrRedemptionUndoWrongStation)
+	})
+
+	t.Run("UndoRedemption restores the line and records the audit trail", func(t *testing.T) {
+		batch, err := svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{DeviceID: &station.ID}, "wrong scan")
+		require.NoError(t, err)
+		require.NotNil(t, batch.UndoneAt)
+		require.Equal(t, station.ID, *batch.UndoneByDeviceID)
+		require.Equal(t, "wrong scan", *batch.UndoReason)
+
+		restored, err := repos.OrderLine.GetByID(ctx, line.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, restored.RedeemedQuantity)
+		require.Nil(t, restored.Edges.Redemption)
+
+		undos, err := svc.ListRedemptionUndos(ctx)
+		require.NoError(t, err)
+		require.Len(t, undos, 1)
+	})
+
+	t.Run("UndoRedemption cannot undo twice", func(t *testing.T) {
+		_, err := svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{Admin: true}, "again")
+		require.ErrorIs(t, err, service.ErrRedemptionAlreadyUndone)
+	})
+
+	t.Run("UndoRedemption window applies to stations but not admins", func(t *testing.T) {
+		result, err := svc.RedeemAssigned(ctx, station.ID, ord.ID, "")
+		require.NoError(t, err)
+		batchID := result["batchId"].(string)
+
+		_, err = tdb.DB.ExecContext(ctx, "UPDATE redemption_batch SET created_at = NOW() - INTERVAL '10 minutes' WHERE id = $1", batchID)
+		require.NoError(t, err)
+
+		_, err = svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{DeviceID: &station.ID}, "too late")
+		require.ErrorIs(t, err, service.ErrRedemptionUndoWindowExpired)
+
+		_, err = svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{Admin: true}, "admin override")
+		require.NoError(t, err)
+	})
+
+	t.Run("UndoRedemption shortens long reasons by characters", func(t *testing.T) {
+		result, err := svc.RedeemAssigned(ctx, station.ID, ord.ID, "")
+		require.NoError(t, err)
+		batchID := result["batchId"].(string)
+
+		batch, err := svc.UndoRedemption(ctx, batchID, service.RedemptionUndoActor{Admin: true}, strings.Repeat("ü", 250))
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("ü", 200), *batch.UndoReason)
+	})
+}
@@ -80,22 +80,44 @@ func (tdb *TestDB) Cleanup(t *testing.T) {
 
 	// Tables ordered to respect foreign key constraints
 	tables := []string{
+		"volunteer_redemption",
+		"volunteer_token",
+		"volunteer_campaign_choice_option",
+		"volunteer_campaign_choice_group",
+		"volunteer_campaign_product",
+		"volunteer_campaign_window",
+		"volunteer_campaign",
 		"idempotency",
+		"redemption_batch_item",
+		"redemption_batch",
 		"order_line_redemption",
 		"inventory_ledger",
 		"order_payment",
 		"order_line",
 		"\"order\"",
+		"event_product",
+		"event",
 		"menu_slot_option",
 		"menu_slot",
 		"device_product",
+		"device_variant",
+		"availability_window",
+		"product_variant",
 		"device_binding",
 		"device",
 		"club100_free_product",
+		"club100_card",
+		"club100_member",
+		"club100_sync_run",
+		"club100_period",
+		"membership_upload",
 		"product",
 		"jeton",
 		"category",
 		"settings",
+		"settings_version",
+		"opening_hours_window",
+		"opening_hours_exception",
 		"session",
 		"admin_invite",
 		"verification",
@@ -123,6 +145,7 @@ func (tdb *TestDB) Close() {
 type Repositories struct {
 	Category          pgRepo.CategoryRepository
 	Product           *pgRepo.ProductRepository
+	ProductVariant    pgRepo.ProductVariantRepository
 	Jeton             pgRepo.JetonRepository
 	MenuSlot          pgRepo.MenuSlotRepository
 	MenuSlotOption    pgRepo.MenuSlotOptionRepository
@@ -130,12 +153,27 @@ type Repositories struct {
 	OrderLine         pgRepo.OrderLineRepository
 	OrderPayment      pgRepo.OrderPaymentRepository
 	OrderRedemption   pgRepo.OrderLineRedemptionRepository
+	RedemptionBatch   pgRepo.RedemptionBatchRepository
 	Club100Redemption pgRepo.Club100RedemptionRepository
+	Club100Member     pgRepo.Club100MemberRepository
+	Club100Period     pgRepo.Club100PeriodRepository
+	Club100Card       pgRepo.Club100CardRepository
+	MembershipUpload  pgRepo.MembershipUploadRepository
 	Inventory         pgRepo.InventoryLedgerRepository
 	Device            pgRepo.DeviceRepository
 	DeviceProduct     pgRepo.DeviceProductRepository
 	Settings          pgRepo.SettingsRepository
+	SettingsVersion   pgRepo.SettingsVersionRepository
+	OpeningHours      pgRepo.OpeningHoursRepository
+	Event             pgRepo.EventRepository
+	PriceRule         pgRepo.PriceRuleRepository
+	Availability      pgRepo.AvailabilityRepository
 	Idempotency       pgRepo.IdempotencyRepository
+	VolunteerCampaign pgRepo.VolunteerCampaignRepository
+	VolunteerToken    pgRepo.VolunteerTokenRepository
+
+	// client lets helpers build services that run their own transactions.
+	client *ent.Client
 }
 
 // NewRepositories creates all repository instances from an Ent client.
@@ -143,6 +181,7 @@ func NewRepositories(client *ent.Client) *Repositories {
 	return &Repositories{
 		Category:          pgRepo.NewCategoryRepository(client),
 		Product:           pgRepo.NewProductRepository(client),
+		ProductVariant:    pgRepo.NewProductVariantRepository(client),
 		Jeton:             pgRepo.NewJetonRepository(client),
 		MenuSlot:          pgRepo.NewMenuSlotRepository(client),
 		MenuSlotOption:    pgRepo.NewMenuSlotOptionRepository(client),
@@ -150,12 +189,26 @@ func NewRepositories(client *ent.Client) *Repositories {
 		OrderLine:         pgRepo.NewOrderLineRepository(client),
 		OrderPayment:      pgRepo.NewOrderPaymentRepository(client),
 		OrderRedemption:   pgRepo.NewOrderLineRedemptionRepository(client),
+		RedemptionBatch:   pgRepo.NewRedemptionBatchRepository(client),
 		Club100Redemption: pgRepo.NewClub100RedemptionRepository(client),
+		Club100Member:     pgRepo.NewClub100MemberRepository(client),
+		Club100Period:     pgRepo.NewClub100PeriodRepository(client),
+		Club100Card:       pgRepo.NewClub100CardRepository(client),
+		MembershipUpload:  pgRepo.NewMembershipUploadRepository(client),
 		Inventory:         pgRepo.NewInventoryLedgerRepository(client),
 		Device:            pgRepo.NewDeviceRepository(client),
 		DeviceProduct:     pgRepo.NewDeviceProductRepository(client),
 		Settings:          pgRepo.NewSettingsRepository(client),
+		SettingsVersion:   pgRepo.NewSettingsVersionRepository(client),
+		OpeningHours:      pgRepo.NewOpeningHoursRepository(client),
+		Event:             pgRepo.NewEventRepository(client),
+		PriceRule:         pgRepo.NewPriceRuleRepository(client),
+		Availability:      pgRepo.NewAvailabilityRepository(client),
 		Idempotency:       pgRepo.NewIdempotencyRepository(client),
+		VolunteerCampaign: pgRepo.NewVolunteerCampaignRepository(client),
+		VolunteerToken:    pgRepo.NewVolunteerTokenRepository(client),
+
+		client: client,
 	}
 }
 
@@ -175,12 +228,16 @@ func NewFixtures(repos *Repositories) *Fixtures {
 func NewProductSvc(repos *Repositories) service.ProductService {
 	return service.NewProductService(
 		repos.Product,
+		repos.ProductVariant,
 		repos.Category,
 		repos.MenuSlot,
 		repos.MenuSlotOption,
 		repos.Inventory,
 		repos.Jeton,
+		repos.Availability,
+		nil,
 		nil,
+		repos.client,
 	)
 }
 
@@ -213,7 +270,7 @@ func (f *Fixtures) CreateProduct(name string, categoryID string, priceCents int6
 
 // CreateOrder creates a test order.
 func (f *Fixtures) CreateOrder(totalCents int64, status order.Status, origin order.Origin) *ent.Order {
-	ord, err := f.repos.Order.Create(f.ctx, totalCents, status, origin, nil, nil, nil, nil, nil)
+	ord, err := f.repos.Order.Create(f.ctx, totalCents, status, origin, nil, nil, nil, nil, nil, nil, nil)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create order: %v", err))
 	}
@@ -222,7 +279,7 @@ func (f *Fixtures) CreateOrder(totalCents int64, status order.Status, origin ord
 
 // CreateOrderWithCustomer creates an order with a customer ID.
 func (f *Fixtures) CreateOrderWithCustomer(totalCents int64, status order.Status, origin order.Origin, customerID string) *ent.Order {
-	ord, err := f.repos.Order.Create(f.ctx, totalCents, status, origin, &customerID, nil, nil, nil, nil)
+	ord, err := f.repos.Order.Create(f.ctx, totalCents, status, origin, nil, &customerID, nil, nil, nil, nil, nil)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create order: %v", err))
 	}
@@ -249,7 +306,7 @@ func (f *Fixtures) CreateDevice(name, deviceKey string, deviceType device.Type,
 
 // AddInventory adds inventory for a product.
 func (f *Fixtures) AddInventory(productID string, delta int, reason inventoryledger.Reason) *ent.InventoryLedger {
-	entry, err := f.repos.Inventory.Create(f.ctx, productID, delta, reason, nil, nil, nil, nil)
+	entry, err := f.repos.Inventory.Create(f.ctx, productID, delta, reason, nil, nil, nil, nil, nil)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create inventory entry: %v", err))
 	}
@@ -303,18 +360,3 @@ func TimeWithin(a, b time.Time, d time.Duration) bool {
 	}
 	return diff <= d
 }
-
-// MockElvantoService is an ElvantoService stub for testing.
-// Defaults to unconfigured (returns no people); set Configured=true and People to simulate API responses.
-type MockElvantoService struct {
-	Configured bool
-	People     []service.ElvantoPerson
-}
-
-func (m *MockElvantoService) SearchPeople(_ context.Context) ([]service.ElvantoPerson, error) {
-	return m.People, nil
-}
-
-func (m *MockElvantoService) IsConfigured() bool {
-	return m.Configured
-}
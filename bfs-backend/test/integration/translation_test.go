@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/generated/ent/inventoryledger"
+	"backend/internal/generated/ent/product"
+	"backend/internal/i18n"
+	"backend/internal/repository"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCatalogTranslations(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	products := NewProductSvc(repos)
+	payments := service.NewPaymentService(TestConfig(), repos.Order, repos.OrderLine, repos.OrderPayment, products, nil,
+		nil, repos.MenuSlot, repos.Inventory, nil, nil, nil, zap.NewNop())
+
+	menus := fixtures.CreateCategory("Menus", 1, true)
+	sides := fixtures.CreateCategory("Beilagen", 2, true)
+	burger := fixtures.CreateProduct("Burger Menu", menus.ID, 1500, product.TypeMenu, nil)
+	fries := fixtures.CreateProduct("Pommes", sides.ID, 400, product.TypeSimple, nil)
+	fixtures.AddInventory(fries.ID, 10, inventoryledger.ReasonOpeningBalance)
+	sideSlot := fixtures.CreateMenuSlot(burger.ID, "Beilage", 1)
+
+	t.Run("normalizes product translations", func(t *testing.T) {
+		desc := " Faites maison "
+		updated, err := products.UpdateTranslations(ctx, fries.ID, i18n.Translations{
+			"FR": {Name: " Frites ", Description: &desc},
+			"de": {Name: "Pommes frites"},
+			"en": {Name: ""},
+		})
+		require.NoError(t, err)
+		require.Len(t, updated.Translations, 1)
+		require.Equal(t, "Frites", updated.Translations[i18n.French].Name)
+		require.Equal(t, "Faites maison", *updated.Translations[i18n.French].Description)
+
+		_, err = products.UpdateTranslations(ctx, fries.ID, i18n.Translations{"en": {Name: "Hand-cut fries with sea salt"}})
+		require.Error(t, err)
+	})
+
+	t.Run("slot translations belong to their menu", func(t *testing.T) {
+		slot, err := products.UpdateMenuSlotTranslations(ctx, burger.ID, sideSlot.ID, i18n.Translations{"en": {Name: "Side"}})
+		require.NoError(t, err)
+		require.Equal(t, "Side", slot.Translations.Name(i18n.English, slot.Name))
+		require.Equal(t, "Beilage", slot.Translations.Name(i18n.French, slot.Name))
+
+		_, err = products.UpdateMenuSlotTranslations(ctx, fries.ID, sideSlot.ID, i18n.Translations{"en": {Name: "Side"}})
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("orders record the customer's locale", func(t *testing.T) {
+		prep, err := payments.PrepareAndCreateOrder(ctx, service.CreateCheckoutInput{
+			Items:  []service.CheckoutItemInput{{ProductID: fries.ID, Quantity: 1}},
+			Locale: i18n.French,
+		}, nil, nil)
+		require.NoError(t, err)
+
+		ord, err := repos.Order.GetByID(ctx, prep.OrderID)
+		require.NoError(t, err)
+		require.Equal(t, i18n.French, i18n.Or(ord.Locale))
+	})
+}
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	entDevice "backend/internal/generated/ent/device"
+	"backend/internal/generated/ent/inventoryledger"
+	"backend/internal/generated/ent/product"
+	"backend/internal/repository"
+	"backend/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newVolunteerSvc(tdb *TestDB, repos *Repositories) service.VolunteerService {
+	stations := service.NewStationService(TestConfig(), tdb.Client, repos.Device, repos.DeviceProduct, repos.OrderLine,
+		repos.OrderRedemption, repos.RedemptionBatch, repos.Idempotency, repos.Order, nil)
+	return service.NewVolunteerService(
+		tdb.Client,
+		repos.VolunteerCampaign,
+		repository.NewVolunteerRedemptionRepository(tdb.Client),
+		repos.VolunteerToken,
+		repos.Order,
+		repos.OrderLine,
+		repos.OrderPayment,
+		repos.Inventory,
+		nil,
+		stations,
+		nil,
+		service.NewEventService(repos.Event, tdb.Client),
+	)
+}
+
+func TestVolunteerRedemption_OnlyServedProducts(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	volunteers := newVolunteerSvc(tdb, repos)
+
+	category := fixtures.CreateCategory("Essen", 1, true)
+	sausage := fixtures.CreateProduct("Bratwurst", category.ID, 750, product.TypeSimple, nil)
+	cola := fixtures.CreateProduct("Cola", category.ID, 350, product.TypeSimple, nil)
+	fixtures.AddInventory(sausage.ID, 10, inventoryledger.ReasonOpeningBalance)
+	fixtures.AddInventory(cola.ID, 10, inventoryledger.ReasonOpeningBalance)
+	bar := fixtures.CreateDevice("Bar", "bar-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(bar.ID, cola.ID)
+
+	campaign, err := volunteers.CreateCampaign(ctx, service.CreateVolunteerCampaignInput{
+		Name:           "Helfer",
+		Products:       []repository.VolunteerCampaignProductInput{{ProductID: sausage.ID, Quantity: 1}},
+		ChoiceGroups:   []repository.VolunteerChoiceGroupInput{{Name: "Getränk", Quantity: 1, ProductIDs: []string{cola.ID}}},
+		MaxRedemptions: 5,
+	})
+	require.NoError(t, err)
+
+	// The bar only hands out the drink, so the sausage stays in stock for
+	// the grill.
+	result, err := volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, bar.ID, "", nil)
+	require.NoError(t, err)
+
+	lines, err := repos.OrderLine.GetByOrderID(ctx, result.OrderID)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	require.Equal(t, cola.ID, lines[0].ProductID)
+
+	stock, err := repos.Inventory.GetCurrentStock(ctx, sausage.ID)
+	require.NoError(t, err)
+	require.Equal(t, 10, stock)
+	stock, err = repos.Inventory.GetCurrentStock(ctx, cola.ID)
+	require.NoError(t, err)
+	require.Equal(t, 9, stock)
+}
+
+func TestVolunteerRedemption_ChoiceGroups(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	fixtures := NewFixtures(repos)
+	volunteers := newVolunteerSvc(tdb, repos)
+
+	category := fixtures.CreateCategory("Essen", 1, true)
+	sausage := fixtures.CreateProduct("Bratwurst", category.ID, 750, product.TypeSimple, nil)
+	cola := fixtures.CreateProduct("Cola", category.ID, 350, product.TypeSimple, nil)
+	fanta := fixtures.CreateProduct("Fanta", category.ID, 350, product.TypeSimple, nil)
+	tea := fixtures.CreateProduct("Eistee", category.ID, 350, product.TypeSimple, nil)
+	bar := fixtures.CreateDevice("Bar", "bar-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(bar.ID, cola.ID)
+	fixtures.AssignProductToDevice(bar.ID, fanta.ID)
+	kiosk := fixtures.CreateDevice("Kiosk", "kiosk-key", entDevice.TypeSTATION, entDevice.StatusApproved)
+	fixtures.AssignProductToDevice(kiosk.ID, fixtures.CreateProduct("Chips", category.ID, 300, product.TypeSimple, nil).ID)
+
+	campaign, err := volunteers.CreateCampaign(ctx, service.CreateVolunteerCampaignInput{
+		Name:     "Helfer",
+		Products: []repository.VolunteerCampaignProductInput{{ProductID: sausage.ID, Quantity: 1}},
+		ChoiceGroups: []repository.VolunteerChoiceGroupInput{
+			{Name: "Getränk", Quantity: 1, ProductIDs: []string{cola.ID, fanta.ID, tea.ID}},
+		},
+		MaxRedemptions: 5,
+	})
+	require.NoError(t, err)
+	groups, err := volunteers.GetChoiceGroups(ctx, campaign.ID)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	drink := groups[0].ID
+
+	t.Run("without a pick the station is offered the options it serves", func(t *testing.T) {
+		_, err := volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, bar.ID, "", nil)
+		var required service.VolunteerChoiceRequiredError
+		require.ErrorAs(t, err, &required)
+		require.Len(t, required.Groups, 1)
+		offered := make([]string, 0, len(required.Groups[0].Options))
+		for _, o := range required.Groups[0].Options {
+			offered = append(offered, o.ProductID)
+		}
+		require.ElementsMatch(t, []string{cola.ID, fanta.ID}, offered)
+	})
+
+	t.Run("a valid pick goes into the gratis order", func(t *testing.T) {
+		result, err := volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, bar.ID, "", map[string]string{drink: fanta.ID})
+		require.NoError(t, err)
+
+		lines, err := repos.OrderLine.GetByOrderID(ctx, result.OrderID)
+		require.NoError(t, err)
+		require.Len(t, lines, 1, "the bar does not serve the sausage")
+		require.Equal(t, fanta.ID, lines[0].ProductID)
+	})
+
+	t.Run("a pick outside the group is rejected", func(t *testing.T) {
+		_, err := volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, bar.ID, "", map[string]string{drink: sausage.ID})
+		require.ErrorIs(t, err, service.ErrVolunteerChoiceInvalid)
+
+		_, err = volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, bar.ID, "", map[string]string{"no-such-group": cola.ID})
+		require.ErrorIs(t, err, service.ErrVolunteerChoiceInvalid)
+	})
+
+	t.Run("a pick the station does not serve is rejected", func(t *testing.T) {
+		_, err := volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, bar.ID, "", map[string]string{drink: tea.ID})
+		require.ErrorIs(t, err, service.ErrVolunteerChoiceInvalid)
+	})
+
+	t.Run("a station serving none of the products has nothing to hand out", func(t *testing.T) {
+		_, err := volunteers.RedeemSharedQR(ctx, campaign.ClaimToken, kiosk.ID, "", nil)
+		require.ErrorIs(t, err, service.ErrVolunteerStationNoMatchingProducts)
+	})
+
+	got, err := volunteers.GetCampaign(ctx, campaign.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, got.Campaign.RedemptionCount, "rejected picks use up no redemption")
+}
@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"backend/internal/generated/ent/volunteercampaign"
+	"backend/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolunteerTokenRepo_CreateMany(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	campaign, err := repos.VolunteerCampaign.Create(ctx, "Helfer", "1234", nil, nil, nil, volunteercampaign.StatusActive, 1)
+	require.NoError(t, err)
+
+	created, err := repos.VolunteerToken.CreateMany(ctx, campaign.ID, []repository.VolunteerTokenInput{
+		{Name: "Anna", MaxRedemptions: 1},
+		{Name: "Ben", MaxRedemptions: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+
+	// A second import of the same names, e.g. one that raced the first,
+	// creates nothing and must not report the first import's rows.
+	created, err = repos.VolunteerToken.CreateMany(ctx, campaign.ID, []repository.VolunteerTokenInput{
+		{Name: "Ben", MaxRedemptions: 1},
+		{Name: "Clara", MaxRedemptions: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "Clara", created[0].Name)
+
+	all, err := repos.VolunteerToken.ListByCampaign(ctx, campaign.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
+
+func TestVolunteerTokenRepo_CreateNumberedConcurrently(t *testing.T) {
+	tdb := NewTestDB(t)
+	defer tdb.Close()
+	tdb.Cleanup(t)
+
+	ctx := context.Background()
+	repos := NewRepositories(tdb.Client)
+	campaign, err := repos.VolunteerCampaign.Create(ctx, "Helfer", "1234", nil, nil, nil, volunteercampaign.StatusActive, 1)
+	require.NoError(t, err)
+
+	issue := func() error {
+		return repository.RunInTx(ctx, tdb.Client, func(ctx context.Context) error {
+			_, err := repos.VolunteerToken.CreateNumbered(ctx, campaign.ID, 5)
+			return err
+		})
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Go(func() { errs[i] = issue() })
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	slips, err := repos.VolunteerToken.ListNumbered(ctx, campaign.ID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, slips, 20)
+	for i, s := range slips {
+		require.Equal(t, i+1, *s.Serial)
+	}
+}
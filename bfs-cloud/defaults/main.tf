@@ -280,10 +280,11 @@ output "config" {
         }
         key_vault_secrets = merge(
           {
-            "DATABASE_URL"    = "database-url"
-            "PLUNK_API_KEY"   = "plunk-api-key"
-            "ELVANTO_API_KEY" = "elvanto-api-key"
-            "SENTRY_DSN"      = "backend-sentry-dsn"
+            "DATABASE_URL"      = "database-url"
+            "PLUNK_API_KEY"     = "plunk-api-key"
+            "ELVANTO_API_KEY"   = "elvanto-api-key"
+            "SENTRY_DSN"        = "backend-sentry-dsn"
+            "QR_SIGNING_SECRET" = "qr-signing-secret"
           },
           # Staging intentionally omits Payrexx secrets so the backend falls back to
           # simulated dev payments (IsPayrexxEnabled() == false). Production wires real Payrexx.
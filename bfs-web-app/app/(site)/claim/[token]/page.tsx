@@ -1,16 +1,32 @@
 "use client"
 
-import { Loader2, LockKeyhole, Utensils } from "lucide-react"
+import { Clock, Loader2, LockKeyhole, Utensils } from "lucide-react"
 import Image from "next/image"
 import { useParams } from "next/navigation"
 import { useCallback, useEffect, useRef, useState } from "react"
 import QRCode from "@/components/qrcode"
 import { InputOTP, InputOTPGroup, InputOTPSlot } from "@/components/ui/input-otp"
 import { readErrorMessage } from "@/lib/http"
-import type { ClaimCampaignResponse } from "@/types/volunteer"
+import type { ClaimCampaignResponse, ClaimWindowOccurrence } from "@/types/volunteer"
 
 const ACCESS_INPUT_PATTERN = "^[A-Za-z1-9]*$"
 
+const windowDay = new Intl.DateTimeFormat("de-CH", {
+  weekday: "short",
+  day: "numeric",
+  month: "numeric",
+  timeZone: "Europe/Zurich",
+})
+const windowTime = new Intl.DateTimeFormat("de-CH", { hour: "2-digit", minute: "2-digit", timeZone: "Europe/Zurich" })
+
+function describeWindow(w: ClaimWindowOccurrence): string {
+  const start = new Date(w.start)
+  const end = new Date(w.end)
+  const label = w.label ? `${w.label}, ` : ""
+  if (w.active) return `Jetzt einlösbar: ${label}bis ${windowTime.format(end)}`
+  return `Nächstes Zeitfenster: ${label}${windowDay.format(start)}, ${windowTime.format(start)}–${windowTime.format(end)}`
+}
+
 export default function ClaimPage() {
   const params = useParams<{ token: string }>()
   const token = params?.token
@@ -217,6 +233,17 @@ export default function ClaimPage() {
       <h1 className="mb-2 text-2xl font-semibold">{data.campaign.name}</h1>
       <p className="text-muted-foreground mb-6 text-sm">Zeig diesen QR-Code an der Station vor.</p>
 
+      {data.windows.length > 0 && (
+        <div
+          className={`mb-6 flex items-center gap-2 rounded-xl p-3 text-sm ${
+            data.nextWindow?.active ? "bg-green-50 text-green-900" : "bg-amber-50 text-amber-900"
+          }`}
+        >
+          <Clock className="size-4 shrink-0" aria-hidden />
+          <p>{data.nextWindow ? describeWindow(data.nextWindow) : "Keine weiteren Zeitfenster."}</p>
+        </div>
+      )}
+
       <QRCode value={data.qrPayload} size={260} className="mx-auto rounded-[11px] border-2 p-1" />
 
       {data.products.length > 0 && (
@@ -253,6 +280,23 @@ export default function ClaimPage() {
         </div>
       )}
 
+      {(data.choiceGroups ?? []).length > 0 && (
+        <div className="mt-6">
+          <h2 className="mb-3 text-lg font-semibold">Zur Auswahl</h2>
+          <ul className="flex flex-col gap-3">
+            {data.choiceGroups.map((g) => (
+              <li key={g.id} className="rounded-xl border p-3">
+                <div className="flex items-center justify-between gap-3">
+                  <p className="font-medium">{g.name}</p>
+                  <p className="text-muted-foreground shrink-0 text-sm tabular-nums">×{g.quantity}</p>
+                </div>
+                <p className="text-muted-foreground mt-1 text-sm">{g.options.map((o) => o.productName).join(" · ")}</p>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
       {error && (
         <div className="bg-destructive/10 text-destructive mt-6 rounded-xl p-3 text-sm" role="alert">
           {error}
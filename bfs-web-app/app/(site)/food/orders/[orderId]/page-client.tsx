@@ -8,9 +8,10 @@ import QRCode from "@/components/qrcode"
 import { Button } from "@/components/ui/button"
 import { useCart } from "@/contexts/cart-context"
 
+import { allergenLabels } from "@/lib/allergens"
 import { getOrderPublicById, type OrderLineDTO, type PublicOrderDetailsDTO } from "@/lib/api/orders"
 import { addOrder } from "@/lib/orders-storage"
-import { formatChf } from "@/lib/utils"
+import { formatChf, itemName } from "@/lib/utils"
 
 function paymentMethodLabel(method: string | undefined): string {
   switch (method) {
@@ -160,8 +161,10 @@ export default function OrderPageClient() {
       return <p className="text-red-600">Bestellnummer fehlt.</p>
     }
     if (qrReady) {
+      // Prefer the signed payload; the pickup URL is the legacy format.
       const base = process.env.NEXT_PUBLIC_APP_URL ?? window.location.origin
-      return <QRCode value={`${base}/o/${orderId}`} size={260} className="mx-auto rounded-[11px] border-2 p-1" />
+      const value = serverOrder?.qrPayload ?? `${base}/o/${orderId}`
+      return <QRCode value={value} size={260} className="mx-auto rounded-[11px] border-2 p-1" />
     }
     return <div className="mx-auto h-[260px] w-[260px] animate-pulse rounded-[11px] border-2 bg-gray-100" />
   })()
@@ -205,7 +208,7 @@ export default function OrderPageClient() {
                     <div className="min-w-0 flex-1">
                       <div className={`flex justify-between gap-3 ${isRedeemed ? "items-start" : "items-center"}`}>
                         <div className="min-w-0">
-                          <p className="truncate font-medium">{parent.title}</p>
+                          <p className="truncate font-medium">{itemName(parent.title, parent.variantName)}</p>
                           {parent.productDescription && (
                             <p className="text-muted-foreground mt-0.5 line-clamp-2 text-xs whitespace-pre-line">
                               {parent.productDescription}
@@ -223,6 +226,11 @@ export default function OrderPageClient() {
                               ))}
                             </div>
                           )}
+                          {allergenLabels(parent.allergens) && (
+                            <p className="text-muted-foreground mt-1 text-xs">
+                              Allergene: {allergenLabels(parent.allergens)}
+                            </p>
+                          )}
                           {isRedeemed && (
                             <div className="mt-1.5 inline-flex items-center gap-1 rounded-md bg-emerald-100 px-2 py-0.5 text-xs font-medium text-emerald-800">
                               <Check className="size-3" />
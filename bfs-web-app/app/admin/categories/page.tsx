@@ -11,6 +11,8 @@ import {
   AlertDialogTitle,
   AlertDialogTrigger,
 } from "@/components/ui/alert-dialog"
+import { type AvailabilityTarget, AvailabilityDialog } from "@/components/admin/availability-dialog"
+import { type TranslationsTarget, TranslationsDialog } from "@/components/admin/translations-dialog"
 import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
 import { Switch } from "@/components/ui/switch"
@@ -19,8 +21,9 @@ import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
 
 import { getCSRFToken } from "@/lib/csrf"
 import { readErrorMessage } from "@/lib/http"
+import type { Translations } from "@/types"
 
-type Category = { id: string; name: string; isActive: boolean; position: number }
+type Category = { id: string; name: string; isActive: boolean; position: number; translations?: Translations }
 
 export default function AdminCategoriesPage() {
   const fetchAuth = useAuthorizedFetch()
@@ -29,6 +32,8 @@ export default function AdminCategoriesPage() {
   const [position, setPosition] = useState<string>("0")
   const [posDrafts, setPosDrafts] = useState<Record<string, string>>({})
   const [error, setError] = useState<string | null>(null)
+  const [availabilityFor, setAvailabilityFor] = useState<AvailabilityTarget | null>(null)
+  const [translationsFor, setTranslationsFor] = useState<(TranslationsTarget & { id: string }) | null>(null)
 
   useEffect(() => {
     void reload()
@@ -221,6 +226,30 @@ export default function AdminCategoriesPage() {
                     </TableCell>
                     <TableCell className="text-right">
                       <div className="inline-flex items-center gap-1">
+                        <Button
+                          variant="ghost"
+                          size="sm"
+                          className="h-7"
+                          onClick={() => setAvailabilityFor({ kind: "categories", id: c.id, name: c.name })}
+                        >
+                          Verfügbarkeit
+                        </Button>
+                        <Button
+                          variant="ghost"
+                          size="sm"
+                          className="h-7"
+                          onClick={() =>
+                            setTranslationsFor({
+                              id: c.id,
+                              path: `/api/v1/categories/${encodeURIComponent(c.id)}`,
+                              name: c.name,
+                              withDescription: false,
+                              translations: c.translations,
+                            })
+                          }
+                        >
+                          Übersetzungen
+                        </Button>
                         <AlertDialog>
                           <AlertDialogTrigger asChild>
                             <Button variant="ghost" size="sm" className="h-7 text-red-700">
@@ -250,6 +279,15 @@ export default function AdminCategoriesPage() {
           </div>
         </div>
       </div>
+      <AvailabilityDialog target={availabilityFor} onOpenChange={(open) => !open && setAvailabilityFor(null)} />
+      <TranslationsDialog
+        target={translationsFor}
+        onOpenChange={(open) => !open && setTranslationsFor(null)}
+        onSaved={(translations) => {
+          if (!translationsFor) return
+          setItems((prev) => prev.map((it) => (it.id === translationsFor.id ? { ...it, translations } : it)))
+        }}
+      />
     </div>
   )
 }
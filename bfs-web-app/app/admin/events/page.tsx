@@ -0,0 +1,253 @@
+"use client"
+import { Fragment, useEffect, useState } from "react"
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+  AlertDialogTrigger,
+} from "@/components/ui/alert-dialog"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Switch } from "@/components/ui/switch"
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+type EventItem = { id: string; name: string; startsAt: string; endsAt: string; active: boolean }
+type Product = { id: string; name: string; priceCents: number }
+// One row per product; price is a CHF draft, empty keeps the base price.
+type Override = { available: boolean; price: string }
+
+function formatDate(iso: string) {
+  return new Date(iso).toLocaleString("de-CH", { dateStyle: "medium", timeStyle: "short" })
+}
+
+export default function AdminEventsPage() {
+  const fetchAuth = useAuthorizedFetch()
+  const [items, setItems] = useState<EventItem[]>([])
+  const [products, setProducts] = useState<Product[]>([])
+  const [name, setName] = useState("")
+  const [startsAt, setStartsAt] = useState("")
+  const [endsAt, setEndsAt] = useState("")
+  const [openId, setOpenId] = useState<string | null>(null)
+  const [overrides, setOverrides] = useState<Record<string, Override>>({})
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    void reload()
+    fetchAuth(`/api/v1/products?catalog=base`)
+      .then((res) => (res.ok ? res.json() : Promise.reject(new Error(`HTTP ${res.status}`))))
+      .then((data: { items?: Product[] }) => setProducts(data.items || []))
+      .catch(() => setProducts([]))
+  }, [fetchAuth])
+
+  async function reload() {
+    try {
+      const res = await fetchAuth(`/api/v1/events`)
+      if (!res.ok) throw new Error(`HTTP ${res.status}`)
+      const data = (await res.json()) as { items: EventItem[] }
+      setItems(data.items || [])
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Failed to load")
+    }
+  }
+
+  async function send(url: string, method: string, body?: unknown) {
+    const csrf = getCSRFToken()
+    const res = await fetchAuth(url, {
+      method,
+      headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+    if (!res.ok) {
+      setError(await readErrorMessage(res))
+      return null
+    }
+    setError(null)
+    return res
+  }
+
+  async function createEvent() {
+    if (!name.trim() || !startsAt || !endsAt) return
+    const res = await send(`/api/v1/events`, "POST", {
+      name: name.trim(),
+      startsAt: new Date(startsAt).toISOString(),
+      endsAt: new Date(endsAt).toISOString(),
+    })
+    if (!res) return
+    setName("")
+    setStartsAt("")
+    setEndsAt("")
+    await reload()
+  }
+
+  async function setActive(id: string, active: boolean) {
+    if (await send(`/api/v1/events/${id}`, "PATCH", { active })) await reload()
+  }
+
+  async function remove(id: string) {
+    if (await send(`/api/v1/events/${id}`, "DELETE")) await reload()
+  }
+
+  async function openProducts(id: string) {
+    if (openId === id) {
+      setOpenId(null)
+      return
+    }
+    const res = await fetchAuth(`/api/v1/events/${id}/products`)
+    if (!res.ok) {
+      setError(await readErrorMessage(res))
+      return
+    }
+    const data = (await res.json()) as { items: { productId: string; available: boolean; priceCents: number | null }[] }
+    const next: Record<string, Override> = {}
+    for (const p of products) next[p.id] = { available: true, price: "" }
+    for (const o of data.items || []) {
+      next[o.productId] = { available: o.available, price: o.priceCents == null ? "" : (o.priceCents / 100).toFixed(2) }
+    }
+    setOverrides(next)
+    setOpenId(id)
+  }
+
+  async function saveProducts(id: string) {
+    const items = []
+    for (const [productId, o] of Object.entries(overrides)) {
+      const price = o.price.trim() === "" ? null : Math.round(Number(o.price) * 100)
+      if (price !== null && (!Number.isFinite(price) || price < 0)) {
+        setError("Preis muss >= 0 sein")
+        return
+      }
+      if (o.available && price === null) continue
+      items.push({ productId, available: o.available, priceCents: price })
+    }
+    if (await send(`/api/v1/events/${id}/products`, "PUT", { items })) setOpenId(null)
+  }
+
+  return (
+    <div className="min-w-0 space-y-4">
+      <h1 className="text-xl font-semibold">Anlässe</h1>
+      {error && <div className="text-sm text-red-600">{error}</div>}
+
+      <div className="flex flex-wrap items-center gap-2">
+        <Input value={name} onChange={(e) => setName(e.target.value)} placeholder="Neuer Anlass" className="h-8 w-64" />
+        <Input
+          type="datetime-local"
+          value={startsAt}
+          onChange={(e) => setStartsAt(e.target.value)}
+          aria-label="Beginn"
+          className="h-8 w-52"
+        />
+        <Input
+          type="datetime-local"
+          value={endsAt}
+          onChange={(e) => setEndsAt(e.target.value)}
+          aria-label="Ende"
+          className="h-8 w-52"
+        />
+        <Button variant="outline" size="sm" className="h-8" onClick={() => void createEvent()}>
+          Erstellen
+        </Button>
+      </div>
+
+      <div className="rounded-md border">
+        <Table className="whitespace-nowrap">
+          <TableHeader className="bg-card sticky top-0">
+            <TableRow>
+              <TableHead>Name</TableHead>
+              <TableHead>Beginn</TableHead>
+              <TableHead>Ende</TableHead>
+              <TableHead>Aktiv</TableHead>
+              <TableHead className="text-right">Aktionen</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {items.map((ev) => (
+              <Fragment key={ev.id}>
+                <TableRow className="even:bg-card odd:bg-muted/40">
+                  <TableCell>{ev.name}</TableCell>
+                  <TableCell>{formatDate(ev.startsAt)}</TableCell>
+                  <TableCell>{formatDate(ev.endsAt)}</TableCell>
+                  <TableCell>
+                    <Switch checked={ev.active} onCheckedChange={(v) => void setActive(ev.id, v)} />
+                  </TableCell>
+                  <TableCell className="text-right">
+                    <div className="inline-flex items-center gap-1">
+                      <Button variant="ghost" size="sm" className="h-7" onClick={() => void openProducts(ev.id)}>
+                        {openId === ev.id ? "Schliessen" : "Angebot & Preise"}
+                      </Button>
+                      <AlertDialog>
+                        <AlertDialogTrigger asChild>
+                          <Button variant="ghost" size="sm" className="h-7 text-red-700">
+                            Löschen
+                          </Button>
+                        </AlertDialogTrigger>
+                        <AlertDialogContent>
+                          <AlertDialogHeader>
+                            <AlertDialogTitle>Anlass löschen?</AlertDialogTitle>
+                            <AlertDialogDescription>
+                              Anlässe mit Bestellungen können nicht gelöscht werden. Anlass "{ev.name}" dauerhaft
+                              löschen?
+                            </AlertDialogDescription>
+                          </AlertDialogHeader>
+                          <AlertDialogFooter>
+                            <AlertDialogCancel>Abbrechen</AlertDialogCancel>
+                            <AlertDialogAction onClick={() => void remove(ev.id)}>Löschen</AlertDialogAction>
+                          </AlertDialogFooter>
+                        </AlertDialogContent>
+                      </AlertDialog>
+                    </div>
+                  </TableCell>
+                </TableRow>
+                {openId === ev.id && (
+                  <TableRow>
+                    <TableCell colSpan={5} className="space-y-2">
+                      <p className="text-muted-foreground text-sm whitespace-normal">
+                        Leerer Preis übernimmt den Grundpreis. Nicht angebotene Produkte sind während des Anlasses
+                        weder im Shop noch am POS erhältlich.
+                      </p>
+                      {products.map((p) => {
+                        const o = overrides[p.id] ?? { available: true, price: "" }
+                        return (
+                          <div key={p.id} className="flex items-center gap-3">
+                            <Switch
+                              checked={o.available}
+                              onCheckedChange={(v) =>
+                                setOverrides((prev) => ({ ...prev, [p.id]: { ...o, available: v } }))
+                              }
+                            />
+                            <span className="w-56 truncate">{p.name}</span>
+                            <Input
+                              type="number"
+                              step="0.05"
+                              min="0"
+                              value={o.price}
+                              placeholder={(p.priceCents / 100).toFixed(2)}
+                              onChange={(e) =>
+                                setOverrides((prev) => ({ ...prev, [p.id]: { ...o, price: e.target.value } }))
+                              }
+                              className="h-7 w-28"
+                            />
+                          </div>
+                        )
+                      })}
+                      <Button size="sm" className="h-8" onClick={() => void saveProducts(ev.id)}>
+                        Speichern
+                      </Button>
+                    </TableCell>
+                  </TableRow>
+                )}
+              </Fragment>
+            ))}
+          </TableBody>
+        </Table>
+      </div>
+    </div>
+  )
+}
@@ -50,7 +50,7 @@ export default function InventoryHistoryPage() {
     let cancelled = false
     async function loadProducts() {
       try {
-        const res = await fetchAuth("/api/v1/products")
+        const res = await fetchAuth("/api/v1/products?catalog=base")
         if (!res.ok) return
         const data = (await res.json()) as { items?: Product[] }
         const items = data.items ?? []
@@ -80,7 +80,7 @@ export default function AdminJetonsPage() {
       setProductLoading(true)
       setAssignError(null)
       try {
-        const res = await fetchAuth(`/api/v1/products`)
+        const res = await fetchAuth(`/api/v1/products?catalog=base`)
         if (!res.ok) throw new Error(await readErrorMessage(res))
         const data = (await res.json()) as { items: ProductSummaryDTO[] }
         if (cancelled) return
@@ -175,8 +175,8 @@ export default function AdminJetonsPage() {
           const count = j.details?.usage
           throw new Error(
             typeof count === "number"
-              ? `Dieser Jeton ist noch ${count} Produkt${count === 1 ? "" : "en"} zugewiesen. Bitte entferne zuerst die Zuweisung${count === 1 ? "" : "en"}.`
-              : "Dieser Jeton ist noch Produkten zugewiesen. Bitte entferne zuerst die Zuweisungen."
+              ? `Dieser Jeton ist noch ${count} Produkt${count === 1 ? "" : "en"} oder Variante${count === 1 ? "" : "n"} zugewiesen. Bitte entferne zuerst die Zuweisung${count === 1 ? "" : "en"}.`
+              : "Dieser Jeton ist noch Produkten oder Varianten zugewiesen. Bitte entferne zuerst die Zuweisungen."
           )
         }
         throw new Error(j.message || `Löschen fehlgeschlagen (${res.status})`)
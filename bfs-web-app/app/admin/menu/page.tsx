@@ -1,9 +1,21 @@
 "use client"
 
-import { ArrowDown, ArrowUp, ChevronDown, ChevronRight, Pencil, Plus, RefreshCw, Trash2, X } from "lucide-react"
+import {
+  ArrowDown,
+  ArrowUp,
+  ChevronDown,
+  ChevronRight,
+  Languages,
+  Pencil,
+  Plus,
+  RefreshCw,
+  Trash2,
+  X,
+} from "lucide-react"
 import Image from "next/image"
 import { useCallback, useEffect, useMemo, useState } from "react"
 import { ImageUpload } from "@/components/admin/image-upload"
+import { TranslationsDialog } from "@/components/admin/translations-dialog"
 import {
   AlertDialog,
   AlertDialogAction,
@@ -215,7 +227,7 @@ export default function AdminMenuPage() {
       const [mr, cr, pr] = await Promise.all([
         fetchAuth(`/api/v1/menus`),
         fetchAuth(`/api/v1/categories`),
-        fetchAuth(`/api/v1/products?type=simple`),
+        fetchAuth(`/api/v1/products?type=simple&catalog=base`),
       ])
       if (!mr.ok) throw new Error(`Menus: HTTP ${mr.status}`)
       const menuData = (await mr.json()) as { items: Menu[] }
@@ -601,6 +613,19 @@ function SlotRow({
 }) {
   const [renaming, setRenaming] = useState(false)
   const [nameInput, setNameInput] = useState(slot.name)
+  const [translating, setTranslating] = useState(false)
+  const translationsTarget = useMemo(
+    () =>
+      translating
+        ? {
+            path: `/api/v1/menus/${encodeURIComponent(menuId)}/slots/${encodeURIComponent(slot.id)}`,
+            name: slot.name,
+            withDescription: false,
+            translations: slot.translations,
+          }
+        : null,
+    [translating, menuId, slot.id, slot.name, slot.translations]
+  )
 
   const handleRename = async () => {
     const name = nameInput.trim()
@@ -675,6 +700,15 @@ function SlotRow({
           </button>
         )}
         <div className="ml-auto flex items-center gap-0.5">
+          <Button
+            size="icon"
+            variant="ghost"
+            className="size-7"
+            onClick={() => setTranslating(true)}
+            aria-label="Übersetzungen"
+          >
+            <Languages className="size-3.5" />
+          </Button>
           <Button size="icon" variant="ghost" className="size-7" disabled={isFirst} onClick={onMoveUp}>
             <ArrowUp className="size-3.5" />
           </Button>
@@ -736,6 +770,12 @@ function SlotRow({
           </Select>
         </div>
       )}
+
+      <TranslationsDialog
+        target={translationsTarget}
+        onOpenChange={setTranslating}
+        onSaved={() => void onRefetch()}
+      />
     </div>
   )
 }
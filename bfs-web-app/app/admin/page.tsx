@@ -91,7 +91,7 @@ export default function AdminDashboard() {
   useEffect(() => {
     let cancelled = false
     setEventsLoading(true)
-    fetchAuth("/api/v1/events")
+    fetchAuth("/api/v1/events/days")
       .then((res) => (res.ok ? res.json() : Promise.reject(new Error("Failed to load events"))))
       .then((data) => {
         if (!cancelled) {
@@ -133,7 +133,10 @@ export default function AdminDashboard() {
           ? `date_from=${encodeURIComponent(new Date(prevEvent.year, prevEvent.month - 1, prevEvent.day).toISOString())}&date_to=${encodeURIComponent(new Date(prevEvent.year, prevEvent.month - 1, prevEvent.day + 1).toISOString())}`
           : null
 
-        const fetches: Promise<Response>[] = [fetchAuth(`/api/v1/orders?${dateParams}`), fetchAuth("/api/v1/products")]
+        const fetches: Promise<Response>[] = [
+          fetchAuth(`/api/v1/orders?${dateParams}`),
+          fetchAuth("/api/v1/products?catalog=base"),
+        ]
         if (prevDateParams) {
           fetches.push(fetchAuth(`/api/v1/orders?status=paid&${prevDateParams}`))
         }
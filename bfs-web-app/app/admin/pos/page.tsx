@@ -2,9 +2,11 @@
 
 import { useEffect, useState } from "react"
 import { PairDeviceCard } from "@/components/admin/pair-device-card"
+import { PosDeviceConfigDialog } from "@/components/admin/pos-device-config-dialog"
 import { Button } from "@/components/ui/button"
 import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
 import { getCSRFToken } from "@/lib/csrf"
+import type { PosDeviceConfig } from "@/types/jeton"
 
 type PosDevice = {
   id: string
@@ -16,6 +18,7 @@ type PosDevice = {
   status: string
   approvedAt?: string
   createdAt?: string
+  config?: PosDeviceConfig
 }
 
 export default function AdminPOSPage() {
@@ -23,6 +26,7 @@ export default function AdminPOSPage() {
   const [devices, setDevices] = useState<PosDevice[]>([])
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState<string | null>(null)
+  const [configuring, setConfiguring] = useState<PosDevice | null>(null)
 
   async function load() {
     setLoading(true)
@@ -104,6 +108,11 @@ export default function AdminPOSPage() {
               </div>
               <div className="flex shrink-0 items-center gap-2">
                 <StatusBadge status={d.status} />
+                {d.status === "approved" && (
+                  <Button variant="outline" size="sm" onClick={() => setConfiguring(d)}>
+                    Konfigurieren
+                  </Button>
+                )}
                 {d.status === "approved" && (
                   <Button
                     variant="outline"
@@ -119,6 +128,12 @@ export default function AdminPOSPage() {
           </div>
         ))}
       </div>
+
+      <PosDeviceConfigDialog
+        device={configuring}
+        onOpenChange={(open) => !open && setConfiguring(null)}
+        onSaved={load}
+      />
     </div>
   )
 }
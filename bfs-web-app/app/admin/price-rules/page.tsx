@@ -0,0 +1,314 @@
+"use client"
+import { useCallback, useEffect, useState } from "react"
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+  AlertDialogTrigger,
+} from "@/components/ui/alert-dialog"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { Switch } from "@/components/ui/switch"
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import { formatChf } from "@/lib/utils"
+
+type Kind = "percent" | "amount"
+type PriceRule = {
+  id: string
+  name: string
+  productId: string | null
+  categoryId: string | null
+  weekdays: number[]
+  start: string
+  end: string
+  kind: Kind
+  value: number
+  active: boolean
+}
+type Usage = { priceRuleId: string; quantity: number; revenueCents: number; discountCents: number }
+type Option = { id: string; name: string }
+
+const WEEKDAYS = ["Mo", "Di", "Mi", "Do", "Fr", "Sa", "So"]
+const ALL_EVENTS = "__all__"
+
+function formatWeekdays(days: number[]) {
+  if (days.length === 7) return "täglich"
+  return days.map((d) => WEEKDAYS[d - 1]).join(", ")
+}
+
+function formatAdjustment(rule: PriceRule) {
+  return rule.kind === "percent" ? `−${rule.value}%` : `−${formatChf(rule.value)}`
+}
+
+export default function AdminPriceRulesPage() {
+  const fetchAuth = useAuthorizedFetch()
+  const [items, setItems] = useState<PriceRule[]>([])
+  const [usage, setUsage] = useState<Record<string, Usage>>({})
+  const [products, setProducts] = useState<Option[]>([])
+  const [categories, setCategories] = useState<Option[]>([])
+  const [events, setEvents] = useState<Option[]>([])
+  const [eventId, setEventId] = useState(ALL_EVENTS)
+  const [error, setError] = useState<string | null>(null)
+
+  // Draft of a new rule; scope is "product:<id>" or "category:<id>".
+  const [name, setName] = useState("")
+  const [scope, setScope] = useState("")
+  const [weekdays, setWeekdays] = useState<number[]>([1, 2, 3, 4, 5, 6, 7])
+  const [start, setStart] = useState("17:00")
+  const [end, setEnd] = useState("19:00")
+  const [kind, setKind] = useState<Kind>("percent")
+  const [value, setValue] = useState("")
+
+  const reload = useCallback(async () => {
+    try {
+      const query = eventId === ALL_EVENTS ? "" : `?event_id=${encodeURIComponent(eventId)}`
+      const [rr, ur] = await Promise.all([
+        fetchAuth(`/api/v1/price-rules`),
+        fetchAuth(`/api/v1/price-rules/report${query}`),
+      ])
+      if (!rr.ok) throw new Error(`HTTP ${rr.status}`)
+      setItems(((await rr.json()) as { items: PriceRule[] }).items || [])
+      if (ur.ok) {
+        const next: Record<string, Usage> = {}
+        for (const u of ((await ur.json()) as { items: Usage[] }).items || []) next[u.priceRuleId] = u
+        setUsage(next)
+      }
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Failed to load")
+    }
+  }, [fetchAuth, eventId])
+
+  useEffect(() => {
+    void reload()
+  }, [reload])
+
+  useEffect(() => {
+    const load = (url: string, set: (items: Option[]) => void) =>
+      fetchAuth(url)
+        .then((res) => (res.ok ? res.json() : Promise.reject(new Error(`HTTP ${res.status}`))))
+        .then((data: { items?: Option[] }) => set(data.items || []))
+        .catch(() => set([]))
+    void load(`/api/v1/products?catalog=base`, setProducts)
+    void load(`/api/v1/categories`, setCategories)
+    void load(`/api/v1/events`, setEvents)
+  }, [fetchAuth])
+
+  async function send(url: string, method: string, body?: unknown) {
+    const csrf = getCSRFToken()
+    const res = await fetchAuth(url, {
+      method,
+      headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+    if (!res.ok) {
+      setError(await readErrorMessage(res))
+      return null
+    }
+    setError(null)
+    return res
+  }
+
+  async function createRule() {
+    const [scopeKind, scopeId] = scope.split(":")
+    const parsed = kind === "percent" ? parseInt(value, 10) : Math.round(Number(value.replace(",", ".")) * 100)
+    if (!name.trim() || !scopeId || !Number.isFinite(parsed) || parsed <= 0) {
+      setError("Bitte Name, Produkt oder Kategorie und Rabatt angeben.")
+      return
+    }
+    const res = await send(`/api/v1/price-rules`, "POST", {
+      name: name.trim(),
+      productId: scopeKind === "product" ? scopeId : null,
+      categoryId: scopeKind === "category" ? scopeId : null,
+      weekdays,
+      start,
+      end,
+      kind,
+      value: parsed,
+    })
+    if (!res) return
+    setName("")
+    setValue("")
+    await reload()
+  }
+
+  async function setActive(id: string, active: boolean) {
+    if (await send(`/api/v1/price-rules/${id}`, "PATCH", { active })) await reload()
+  }
+
+  async function remove(id: string) {
+    if (await send(`/api/v1/price-rules/${id}`, "DELETE")) await reload()
+  }
+
+  function scopeName(rule: PriceRule) {
+    if (rule.productId) return products.find((p) => p.id === rule.productId)?.name ?? "Produkt"
+    const category = categories.find((c) => c.id === rule.categoryId)?.name ?? ""
+    return `Kategorie ${category}`.trim()
+  }
+
+  return (
+    <div className="min-w-0 space-y-4">
+      <h1 className="text-xl font-semibold">Preisregeln</h1>
+      <p className="text-muted-foreground text-sm">
+        Zeitfenster, in denen Produkte oder ganze Kategorien günstiger sind, z.B. Happy Hour oder Ausverkauf vor
+        Ladenschluss. Gelten mehrere Regeln, zählt der tiefste Preis.
+      </p>
+      {error && <div className="text-sm text-red-600">{error}</div>}
+
+      <div className="flex flex-wrap items-center gap-2">
+        <Input value={name} onChange={(e) => setName(e.target.value)} placeholder="Neue Regel" className="h-8 w-48" />
+        <Select value={scope} onValueChange={setScope}>
+          <SelectTrigger className="h-8 w-56" aria-label="Gilt für">
+            <SelectValue placeholder="Produkt oder Kategorie" />
+          </SelectTrigger>
+          <SelectContent>
+            {categories.map((c) => (
+              <SelectItem key={c.id} value={`category:${c.id}`}>
+                Kategorie {c.name}
+              </SelectItem>
+            ))}
+            {products.map((p) => (
+              <SelectItem key={p.id} value={`product:${p.id}`}>
+                {p.name}
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+        <div className="inline-flex gap-1">
+          {WEEKDAYS.map((label, i) => {
+            const day = i + 1
+            const on = weekdays.includes(day)
+            return (
+              <Button
+                key={label}
+                variant={on ? "default" : "outline"}
+                size="sm"
+                className="h-8 w-9 px-0"
+                onClick={() =>
+                  setWeekdays((prev) => (on ? prev.filter((d) => d !== day) : [...prev, day].sort((a, b) => a - b)))
+                }
+              >
+                {label}
+              </Button>
+            )
+          })}
+        </div>
+        <Input
+          type="time"
+          value={start}
+          onChange={(e) => setStart(e.target.value)}
+          aria-label="Von"
+          className="h-8 w-28"
+        />
+        <Input type="time" value={end} onChange={(e) => setEnd(e.target.value)} aria-label="Bis" className="h-8 w-28" />
+        <Select value={kind} onValueChange={(v) => setKind(v as Kind)}>
+          <SelectTrigger className="h-8 w-28" aria-label="Art">
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            <SelectItem value="percent">% Rabatt</SelectItem>
+            <SelectItem value="amount">CHF Rabatt</SelectItem>
+          </SelectContent>
+        </Select>
+        <Input
+          value={value}
+          inputMode="decimal"
+          onChange={(e) => setValue(e.target.value)}
+          placeholder={kind === "percent" ? "50" : "1.00"}
+          aria-label="Rabatt"
+          className="h-8 w-20"
+        />
+        <Button variant="outline" size="sm" className="h-8" onClick={() => void createRule()}>
+          Erstellen
+        </Button>
+      </div>
+
+      <div className="flex items-center justify-end gap-2">
+        <span className="text-muted-foreground text-sm">Auswertung</span>
+        <Select value={eventId} onValueChange={setEventId}>
+          <SelectTrigger className="h-8 w-56" aria-label="Anlass">
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            <SelectItem value={ALL_EVENTS}>Alle Anlässe</SelectItem>
+            {events.map((ev) => (
+              <SelectItem key={ev.id} value={ev.id}>
+                {ev.name}
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+
+      <div className="rounded-md border">
+        <Table className="whitespace-nowrap">
+          <TableHeader className="bg-card sticky top-0">
+            <TableRow>
+              <TableHead>Name</TableHead>
+              <TableHead>Gilt für</TableHead>
+              <TableHead>Zeit</TableHead>
+              <TableHead>Rabatt</TableHead>
+              <TableHead className="text-right">Verkauft</TableHead>
+              <TableHead className="text-right">Umsatz</TableHead>
+              <TableHead className="text-right">Rabatt total</TableHead>
+              <TableHead>Aktiv</TableHead>
+              <TableHead className="text-right">Aktionen</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {items.map((rule) => {
+              const u = usage[rule.id]
+              return (
+                <TableRow key={rule.id} className="even:bg-card odd:bg-muted/40">
+                  <TableCell>{rule.name}</TableCell>
+                  <TableCell>{scopeName(rule)}</TableCell>
+                  <TableCell>
+                    {formatWeekdays(rule.weekdays)} {rule.start}–{rule.end}
+                  </TableCell>
+                  <TableCell>{formatAdjustment(rule)}</TableCell>
+                  <TableCell className="text-right">{u?.quantity ?? 0}</TableCell>
+                  <TableCell className="text-right">{formatChf(u?.revenueCents ?? 0)}</TableCell>
+                  <TableCell className="text-right">{formatChf(u?.discountCents ?? 0)}</TableCell>
+                  <TableCell>
+                    <Switch checked={rule.active} onCheckedChange={(v) => void setActive(rule.id, v)} />
+                  </TableCell>
+                  <TableCell className="text-right">
+                    <AlertDialog>
+                      <AlertDialogTrigger asChild>
+                        <Button variant="ghost" size="sm" className="h-7 text-red-700">
+                          Löschen
+                        </Button>
+                      </AlertDialogTrigger>
+                      <AlertDialogContent>
+                        <AlertDialogHeader>
+                          <AlertDialogTitle>Preisregel löschen?</AlertDialogTitle>
+                          <AlertDialogDescription>
+                            Bereits verrechnete Regeln können nur deaktiviert werden. Regel "{rule.name}" dauerhaft
+                            löschen?
+                          </AlertDialogDescription>
+                        </AlertDialogHeader>
+                        <AlertDialogFooter>
+                          <AlertDialogCancel>Abbrechen</AlertDialogCancel>
+                          <AlertDialogAction onClick={() => void remove(rule.id)}>Löschen</AlertDialogAction>
+                        </AlertDialogFooter>
+                      </AlertDialogContent>
+                    </AlertDialog>
+                  </TableCell>
+                </TableRow>
+              )
+            })}
+          </TableBody>
+        </Table>
+      </div>
+    </div>
+  )
+}
@@ -2,6 +2,10 @@
 import { Minus, Plus } from "lucide-react"
 import { useEffect, useMemo, useState } from "react"
 import { ImageUpload } from "@/components/admin/image-upload"
+import { AllergensDialog } from "@/components/admin/allergens-dialog"
+import { type AvailabilityTarget, AvailabilityDialog } from "@/components/admin/availability-dialog"
+import { ProductVariantsDialog } from "@/components/admin/product-variants-dialog"
+import { type TranslationsTarget, TranslationsDialog } from "@/components/admin/translations-dialog"
 import {
   AlertDialog,
   AlertDialogAction,
@@ -24,6 +28,7 @@ import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
 
 import { getCSRFToken } from "@/lib/csrf"
 import { readErrorMessage } from "@/lib/http"
+import type { Allergen, DietaryTag, ProductVariant, Translations } from "@/types"
 import type { Jeton, PosFulfillmentMode } from "@/types/jeton"
 
 type Product = {
@@ -37,6 +42,11 @@ type Product = {
   stock?: number | null
   jeton?: Jeton
   type: "simple" | "menu"
+  variants?: ProductVariant[]
+  allergens?: Allergen[]
+  declaredAllergens?: Allergen[]
+  dietaryTags?: DietaryTag[]
+  translations?: Translations
 }
 type Category = { id: string; name: string; isActive: boolean; position: number }
 const NO_JETON_VALUE = "__none__"
@@ -50,6 +60,10 @@ export default function AdminProductsPage() {
   const [jetons, setJetons] = useState<Jeton[]>([])
   const [posMode, setPosMode] = useState<PosFulfillmentMode>("QR_CODE")
   const [createOpen, setCreateOpen] = useState(false)
+  const [variantsFor, setVariantsFor] = useState<Product | null>(null)
+  const [availabilityFor, setAvailabilityFor] = useState<AvailabilityTarget | null>(null)
+  const [allergensFor, setAllergensFor] = useState<Product | null>(null)
+  const [translationsFor, setTranslationsFor] = useState<(TranslationsTarget & { id: string }) | null>(null)
 
   useEffect(() => {
     let cancelled = false
@@ -57,7 +71,7 @@ export default function AdminProductsPage() {
     setError(null)
     ;(async () => {
       try {
-        const res = await fetchAuth(`/api/v1/products`)
+        const res = await fetchAuth(`/api/v1/products?catalog=base`)
         if (!res.ok) throw new Error(`HTTP ${res.status}`)
         const data = (await res.json()) as { items: Product[]; count: number }
         if (cancelled) return
@@ -329,6 +343,18 @@ export default function AdminProductsPage() {
               await deleteHard(id)
               setItems((prev) => prev.filter((it) => it.id !== id))
             }}
+            onEditVariants={() => setVariantsFor(p)}
+            onEditAvailability={() => setAvailabilityFor({ kind: "products", id: p.id, name: p.name })}
+            onEditAllergens={() => setAllergensFor(p)}
+            onEditTranslations={() =>
+              setTranslationsFor({
+                id: p.id,
+                path: `/api/v1/products/${encodeURIComponent(p.id)}`,
+                name: p.name,
+                withDescription: true,
+                translations: p.translations,
+              })
+            }
             updatePrice={updatePrice}
             updateName={updateName}
             updateDescription={updateDescription}
@@ -341,6 +367,45 @@ export default function AdminProductsPage() {
           />
         ))}
       </div>
+      <ProductVariantsDialog
+        product={variantsFor}
+        jetons={jetons}
+        onOpenChange={(open) => !open && setVariantsFor(null)}
+        onChanged={(variants) => {
+          if (!variantsFor) return
+          setItems((prev) => prev.map((it) => (it.id === variantsFor.id ? { ...it, variants } : it)))
+        }}
+      />
+      <AvailabilityDialog target={availabilityFor} onOpenChange={(open) => !open && setAvailabilityFor(null)} />
+      <AllergensDialog
+        product={allergensFor}
+        onOpenChange={(open) => !open && setAllergensFor(null)}
+        onSaved={(saved) => {
+          if (!allergensFor) return
+          // The saved menu comes back without its slots; keep the listed
+          // allergens, which include those of its options.
+          setItems((prev) =>
+            prev.map((it) =>
+              it.id === allergensFor.id
+                ? {
+                    ...it,
+                    allergens: it.type === "menu" ? it.allergens : saved.allergens,
+                    declaredAllergens: saved.declaredAllergens,
+                    dietaryTags: saved.dietaryTags,
+                  }
+                : it
+            )
+          )
+        }}
+      />
+      <TranslationsDialog
+        target={translationsFor}
+        onOpenChange={(open) => !open && setTranslationsFor(null)}
+        onSaved={(translations) => {
+          if (!translationsFor) return
+          setItems((prev) => prev.map((it) => (it.id === translationsFor.id ? { ...it, translations } : it)))
+        }}
+      />
     </div>
   )
 }
@@ -352,6 +417,10 @@ type ProductCardProps = {
   posMode: PosFulfillmentMode
   onUpdated: (p: Product) => void
   onDelete: (id: string) => Promise<void>
+  onEditVariants: () => void
+  onEditAvailability: () => void
+  onEditAllergens: () => void
+  onEditTranslations: () => void
   onError: (msg: string) => void
   updatePrice: (id: string, priceCents: number) => Promise<void>
   updateName: (id: string, name: string) => Promise<void>
@@ -371,6 +440,10 @@ function ProductCardEditable({
   posMode,
   onUpdated,
   onDelete,
+  onEditVariants,
+  onEditAvailability,
+  onEditAllergens,
+  onEditTranslations,
   onError,
   updatePrice,
   updateName,
@@ -717,6 +790,20 @@ function ProductCardEditable({
           <div className="mt-auto flex flex-col gap-2 pt-3 text-sm md:flex-row md:items-center md:justify-between">
             {fieldError && <p className="text-destructive text-xs">{fieldError}</p>}
             <div className="flex flex-col gap-2 md:ml-auto md:flex-row">
+              {product.type === "simple" && (
+                <Button onClick={onEditVariants} variant="outline" className="w-full md:w-auto">
+                  Varianten{product.variants?.length ? ` (${product.variants.length})` : ""}
+                </Button>
+              )}
+              <Button onClick={onEditAvailability} variant="outline" className="w-full md:w-auto">
+                Verfügbarkeit
+              </Button>
+              <Button onClick={onEditAllergens} variant="outline" className="w-full md:w-auto">
+                Allergene
+              </Button>
+              <Button onClick={onEditTranslations} variant="outline" className="w-full md:w-auto">
+                Übersetzungen
+              </Button>
               <Button
                 onClick={() => setShowDeleteConfirm(true)}
                 variant="secondary"
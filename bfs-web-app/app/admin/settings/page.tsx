@@ -1,7 +1,13 @@
 "use client"
 
 import Link from "next/link"
-import { useEffect, useState } from "react"
+import { useCallback, useEffect, useState } from "react"
+import { CatalogTransferCard } from "@/components/admin/catalog-transfer-card"
+import { Club100CardsCard } from "@/components/admin/club100-cards-card"
+import { Club100PeriodsCard } from "@/components/admin/club100-periods-card"
+import { Club100SyncCard } from "@/components/admin/club100-sync-card"
+import { OpeningHoursCard } from "@/components/admin/opening-hours-card"
+import { SettingsHistoryCard } from "@/components/admin/settings-history-card"
 import { Button } from "@/components/ui/button"
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
 import { Checkbox } from "@/components/ui/checkbox"
@@ -35,28 +41,30 @@ export default function AdminSettingsPage() {
   const [saving, setSaving] = useState(false)
   const [error, setError] = useState<string | null>(null)
 
+  const loadSettings = useCallback(async () => {
+    const res = await fetchAuth(`/api/v1/settings`)
+    if (!res.ok) return
+    const s = (await res.json()) as SettingsResponse
+    setSystemEnabled(s.systemEnabled ?? true)
+    setPosMode(s.posMode ?? "QR_CODE")
+    setMissingJetons(s.missingJetons ?? 0)
+    setClub100FreeProductIds(s.club100FreeProductIds ?? [])
+    setClub100MaxRedemptions(s.club100MaxRedemptions ?? 2)
+  }, [fetchAuth])
+
   useEffect(() => {
     ;(async () => {
-      const [settingsRes, productsRes] = await Promise.all([
-        fetchAuth(`/api/v1/settings`),
-        fetchAuth(`/api/v1/products?type=simple&active=true`),
+      const [, productsRes] = await Promise.all([
+        loadSettings(),
+        fetchAuth(`/api/v1/products?type=simple&active=true&catalog=base`),
       ])
 
-      if (settingsRes.ok) {
-        const s = (await settingsRes.json()) as SettingsResponse
-        setSystemEnabled(s.systemEnabled ?? true)
-        setPosMode(s.posMode ?? "QR_CODE")
-        setMissingJetons(s.missingJetons ?? 0)
-        setClub100FreeProductIds(s.club100FreeProductIds ?? [])
-        setClub100MaxRedemptions(s.club100MaxRedemptions ?? 2)
-      }
-
       if (productsRes.ok) {
         const p = (await productsRes.json()) as { items: Product[] }
         setProducts(p.items ?? [])
       }
     })()
-  }, [fetchAuth])
+  }, [fetchAuth, loadSettings])
 
   async function updateSettings(updates: Partial<SettingsResponse>) {
     setSaving(true)
@@ -125,6 +133,8 @@ export default function AdminSettingsPage() {
         </CardHeader>
       </Card>
 
+      <OpeningHoursCard />
+
       <Card className="rounded-2xl">
         <CardHeader>
           <CardTitle>POS Modus</CardTitle>
@@ -202,7 +212,7 @@ export default function AdminSettingsPage() {
           </div>
 
           <div className="space-y-2">
-            <Label htmlFor="club100-max">Max. Einlösungen pro Person</Label>
+            <Label htmlFor="club100-max">Max. Einlösungen pro Person und Periode</Label>
             <Input
               id="club100-max"
               type="number"
@@ -223,6 +233,16 @@ export default function AdminSettingsPage() {
           </div>
         </CardContent>
       </Card>
+
+      <Club100PeriodsCard />
+
+      <Club100SyncCard />
+
+      <Club100CardsCard />
+
+      <CatalogTransferCard />
+
+      <SettingsHistoryCard onRollback={() => void loadSettings()} />
     </div>
   )
 }
@@ -4,6 +4,16 @@ import { AlertTriangle, ArrowLeft, Check, Copy, Loader2, Printer, RefreshCw } fr
 import Link from "next/link"
 import { useParams } from "next/navigation"
 import { useCallback, useEffect, useState } from "react"
+import {
+  DEFAULT_SLIP_PRINT_OPTIONS,
+  type SlipPrintOptions,
+  SlipPrintOptionsFields,
+  slipLayoutParams,
+} from "@/components/admin/slip-print-options"
+import { VolunteerChoicesCard } from "@/components/admin/volunteer-choices-card"
+import { VolunteerRosterCard } from "@/components/admin/volunteer-roster-card"
+import { VolunteerScheduleCard } from "@/components/admin/volunteer-schedule-card"
+import { VolunteerSlipsCard } from "@/components/admin/volunteer-slips-card"
 import {
   AlertDialog,
   AlertDialogAction,
@@ -16,6 +26,7 @@ import {
 } from "@/components/ui/alert-dialog"
 import { Button } from "@/components/ui/button"
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Checkbox } from "@/components/ui/checkbox"
 import { Dialog, DialogContent, DialogFooter, DialogHeader, DialogTitle } from "@/components/ui/dialog"
 import { Input } from "@/components/ui/input"
 import { Label } from "@/components/ui/label"
@@ -58,6 +69,9 @@ export default function AdminStaffMealDetailPage() {
 
   const [printOpen, setPrintOpen] = useState(false)
   const [printCount, setPrintCount] = useState<number | "">(30)
+  const [printNumbered, setPrintNumbered] = useState(false)
+  const [printStart, setPrintStart] = useState<number | "">(1)
+  const [printOptions, setPrintOptions] = useState<SlipPrintOptions>(DEFAULT_SLIP_PRINT_OPTIONS)
 
   const load = useCallback(async () => {
     if (!id) return
@@ -170,8 +184,13 @@ export default function AdminStaffMealDetailPage() {
     if (!id) return
     const raw = printCount === "" ? 0 : printCount
     const count = Math.max(1, Math.min(500, raw || 0))
-    const url = `/api/v1/staff-meals/${encodeURIComponent(id)}/print.pdf?count=${count}`
-    window.open(url, "_blank")
+    const params = slipLayoutParams(printOptions)
+    params.set("count", String(count))
+    if (printNumbered) {
+      params.set("numbered", "1")
+      params.set("start", String(Math.max(1, printStart === "" ? 1 : printStart)))
+    }
+    window.open(`/api/v1/staff-meals/${encodeURIComponent(id)}/print.pdf?${params}`, "_blank")
     setPrintOpen(false)
   }
 
@@ -372,7 +391,7 @@ export default function AdminStaffMealDetailPage() {
         </CardHeader>
         <CardContent>
           {detail.products.length === 0 ? (
-            <p className="text-muted-foreground text-sm">Keine Produkte.</p>
+            <p className="text-muted-foreground text-sm">Keine festen Produkte.</p>
           ) : (
             <ul className="flex flex-col gap-1.5">
               {detail.products.map((p) => (
@@ -386,6 +405,14 @@ export default function AdminStaffMealDetailPage() {
         </CardContent>
       </Card>
 
+      <VolunteerChoicesCard campaignId={detail.id} />
+
+      <VolunteerScheduleCard campaignId={detail.id} />
+
+      <VolunteerRosterCard campaignId={detail.id} />
+
+      <VolunteerSlipsCard campaignId={detail.id} logoUrl={detail.logoUrl ?? null} onLogoChange={load} />
+
       {/* Redemptions */}
       <Card className="rounded-2xl">
         <CardHeader>
@@ -460,9 +487,33 @@ export default function AdminStaffMealDetailPage() {
                 autoFocus
               />
               <p className="text-muted-foreground text-xs">
-                5 Slips pro Reihe auf A4. Alle enthalten den gleichen QR-Code.
+                Alle enthalten den gleichen QR-Code. Für Einmal-Bons mit eigenem QR-Code siehe unten.
               </p>
             </div>
+            <SlipPrintOptionsFields value={printOptions} onChange={setPrintOptions} idPrefix="print" />
+            <div className="flex flex-wrap items-center gap-3">
+              <Label htmlFor="print-numbered" className="flex items-center gap-2 font-normal">
+                <Checkbox
+                  id="print-numbered"
+                  checked={printNumbered}
+                  onCheckedChange={(v) => setPrintNumbered(v === true)}
+                />
+                Fortlaufend nummerieren ab
+              </Label>
+              <Input
+                id="print-start"
+                type="number"
+                min={1}
+                className="w-24"
+                disabled={!printNumbered}
+                value={printStart}
+                onChange={(e) => {
+                  const raw = e.target.value
+                  setPrintStart(raw === "" ? "" : parseInt(raw, 10))
+                }}
+                aria-label="Startnummer"
+              />
+            </div>
           </div>
           <DialogFooter>
             <Button variant="outline" onClick={() => setPrintOpen(false)}>
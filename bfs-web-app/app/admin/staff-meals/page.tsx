@@ -4,6 +4,11 @@ import { ArrowRight, Plus, Ticket, Trash2 } from "lucide-react"
 import Link from "next/link"
 import { useRouter } from "next/navigation"
 import { useCallback, useEffect, useState } from "react"
+import {
+  type ChoiceGroupDraft,
+  choiceGroupsPayload,
+  VolunteerChoiceGroupsEditor,
+} from "@/components/admin/volunteer-choice-groups-editor"
 import { Button } from "@/components/ui/button"
 import { Dialog, DialogContent, DialogFooter, DialogHeader, DialogTitle } from "@/components/ui/dialog"
 import { Input } from "@/components/ui/input"
@@ -42,6 +47,7 @@ export default function AdminStaffMealsPage() {
   const [formName, setFormName] = useState("")
   const [formMaxRedemptions, setFormMaxRedemptions] = useState<number | "">(20)
   const [formProducts, setFormProducts] = useState<ProductRow[]>([])
+  const [formChoices, setFormChoices] = useState<ChoiceGroupDraft[]>([])
   const [formValidUntil, setFormValidUntil] = useState<string>("")
   const [saving, setSaving] = useState(false)
   const [formError, setFormError] = useState<string | null>(null)
@@ -68,7 +74,7 @@ export default function AdminStaffMealsPage() {
     let cancelled = false
     ;(async () => {
       try {
-        const res = await fetchAuth("/api/v1/products")
+        const res = await fetchAuth("/api/v1/products?catalog=base")
         if (!res.ok) return
         const j = (await res.json()) as { items?: ProductSummaryDTO[] }
         if (!cancelled) setProducts(j.items || [])
@@ -83,6 +89,7 @@ export default function AdminStaffMealsPage() {
     setFormName("")
     setFormMaxRedemptions(20)
     setFormProducts([])
+    setFormChoices([])
     setFormValidUntil("")
     setFormError(null)
   }
@@ -98,8 +105,12 @@ export default function AdminStaffMealsPage() {
       setFormError("Name erforderlich.")
       return
     }
-    if (formProducts.length === 0) {
-      setFormError("Mindestens ein Produkt erforderlich.")
+    if (formProducts.length === 0 && formChoices.length === 0) {
+      setFormError("Mindestens ein Produkt oder eine Auswahlgruppe erforderlich.")
+      return
+    }
+    if (formChoices.some((g) => !g.name.trim() || g.productIds.length === 0)) {
+      setFormError("Jede Auswahlgruppe braucht einen Namen und mindestens ein Produkt.")
       return
     }
     const maxRedemptions = formMaxRedemptions === "" ? 0 : formMaxRedemptions
@@ -124,6 +135,7 @@ export default function AdminStaffMealsPage() {
           validUntil: formValidUntil ? new Date(formValidUntil).toISOString() : undefined,
           maxRedemptions,
           products: productsPayload,
+          choiceGroups: choiceGroupsPayload(formChoices),
         }),
       })
       if (!res.ok) throw new Error(await readErrorMessage(res))
@@ -297,6 +309,10 @@ export default function AdminStaffMealsPage() {
                 </Button>
               </div>
             </div>
+            <div className="grid gap-2">
+              <Label>Auswahl an der Station (optional)</Label>
+              <VolunteerChoiceGroupsEditor value={formChoices} onChange={setFormChoices} products={products} />
+            </div>
             {formError && (
               <div className="bg-destructive/10 text-destructive rounded-xl p-3 text-sm" role="alert">
                 {formError}
@@ -46,7 +46,10 @@ export default function AdminStationRequestsPage() {
     setLoading(true)
     setError(null)
     try {
-      const [sr, pr] = await Promise.all([fetchAuth(`/api/v1/stations`), fetchAuth(`/api/v1/products`)])
+      const [sr, pr] = await Promise.all([
+        fetchAuth(`/api/v1/stations`),
+        fetchAuth(`/api/v1/products?catalog=base`),
+      ])
       const sj = (await sr.json()) as { items: Station[] }
       setStations(sj.items || [])
       const pj = (await pr.json()) as { items?: { id: string; name: string }[] }
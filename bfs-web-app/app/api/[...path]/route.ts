@@ -50,6 +50,10 @@ async function handle(req: Request, params: Promise<{ path: string[] }>) {
   const cookieIn = inHeaders.get("cookie")
   if (cookieIn) outHeaders.set("cookie", cookieIn)
 
+  // Catalog texts, receipts and emails follow the browser's language.
+  const acceptLanguage = inHeaders.get("accept-language")
+  if (acceptLanguage) outHeaders.set("accept-language", acceptLanguage)
+
   const idempotencyKey = inHeaders.get("idempotency-key") || inHeaders.get("Idempotency-Key")
   if (idempotencyKey) outHeaders.set("Idempotency-Key", idempotencyKey)
 
@@ -3,7 +3,7 @@
 import "./bridge.client"
 
 import { Banknote, CreditCard, QrCode, ShoppingCart, UtensilsCrossed } from "lucide-react"
-import { useCallback, useEffect, useRef, useState } from "react"
+import { useCallback, useEffect, useMemo, useRef, useState } from "react"
 import { ProductConfigurationModal } from "@/components/cart/product-configuration-modal"
 import { PairingCodeDisplay } from "@/components/device/pairing-code-display"
 import { BasketPanel } from "@/components/pos/basket-panel"
@@ -20,9 +20,9 @@ import { useIsMobile } from "@/hooks/use-mobile"
 import { useOrderQueue } from "@/hooks/use-order-queue"
 import { listProducts } from "@/lib/api/products"
 import { getDeviceToken } from "@/lib/device-auth"
-import { cn, formatChf } from "@/lib/utils"
+import { cn, formatChf, withVariantStock } from "@/lib/utils"
 import type { ListResponse, ProductDTO } from "@/types"
-import type { PosFulfillmentMode } from "@/types/jeton"
+import type { PosDeviceConfig, PosFulfillmentMode } from "@/types/jeton"
 import type { PosPaymentMethod } from "@/types/order-queue"
 
 type PosStatus = {
@@ -31,6 +31,7 @@ type PosStatus = {
   name?: string
   cardCapable?: boolean | null
   mode?: PosFulfillmentMode
+  config?: PosDeviceConfig
 }
 
 type PosDeviceResponse = {
@@ -40,6 +41,7 @@ type PosDeviceResponse = {
   model?: string
   os?: string
   settings?: { posMode?: PosFulfillmentMode }
+  config?: PosDeviceConfig
 }
 
 function MobileTabBar({
@@ -152,6 +154,7 @@ function PosInner() {
           name: device.name,
           cardCapable: null,
           mode: device.settings?.posMode,
+          config: device.config,
         })
       } catch {
         setStatus({ exists: false, approved: false })
@@ -189,10 +192,11 @@ function PosInner() {
         }
       }
       // Update simple product stock
+      const withVariants = withVariantStock(p, stockMap)
       const newStock = stockMap.get(p.id)
-      if (newStock === undefined) return p
+      if (newStock === undefined) return withVariants
       return {
-        ...p,
+        ...withVariants,
         availableQuantity: newStock,
         isAvailable: newStock > 0,
         isLowStock: newStock > 0 && newStock <= 5,
@@ -241,7 +245,13 @@ function PosInner() {
       // Apply to products
       setProducts((prev) => ({
         ...prev,
-        items: prev.items.map((p) => {
+        items: prev.items.map((prod) => {
+          const variantStock = new Map<string, number>()
+          for (const v of prod.variants ?? []) {
+            const d = ce.detail.get(v.id)
+            if (d) variantStock.set(v.id, Math.max(0, (v.stock ?? 0) + d))
+          }
+          const p = withVariantStock(prod, variantStock)
           const delta = ce.detail.get(p.id)
           if (!delta) return p
           const newQty = Math.max(0, (p.availableQuantity ?? 0) + delta)
@@ -279,6 +289,7 @@ function PosInner() {
               name: device.name,
               cardCapable: null,
               mode: device.settings?.posMode,
+              config: device.config,
             })
           } catch {}
         }
@@ -318,6 +329,10 @@ function PosInner() {
     setProducts((prev) => ({
       ...prev,
       items: prev.items.map((p) => {
+        // Variant stock is keyed by the variant's ID.
+        if (p.variants?.some((v) => v.id === productId)) {
+          return withVariantStock(p, new Map([[productId, newStock]]))
+        }
         if (p.id !== productId) return p
         return {
           ...p,
@@ -350,6 +365,14 @@ function PosInner() {
 
   const showBothPanels = !isMobile || isLandscape
 
+  // Devices restricted to some categories only show and sell those.
+  const categoryIds = status?.config?.categoryIds
+  const visibleProducts = useMemo(() => {
+    if (!categoryIds) return products
+    const items = products.items.filter((p) => p.category?.id && categoryIds.includes(p.category.id))
+    return { ...products, items, count: items.length }
+  }, [products, categoryIds])
+
   if (systemDisabled) {
     return (
       <div className="bg-background flex min-h-screen items-center justify-center">
@@ -396,7 +419,7 @@ function PosInner() {
           >
             <div className={cn("min-h-0 overflow-hidden", !showBothPanels && mobileView !== "products" && "hidden")}>
               <ProductGrid
-                products={products}
+                products={visibleProducts}
                 onConfigure={(p) => {
                   setConfigProduct(p)
                   setConfigOpen(true)
@@ -407,6 +430,8 @@ function PosInner() {
               <BasketPanel
                 token={sessionToken || ""}
                 mode={status?.mode}
+                paymentMethods={status?.config?.paymentMethods}
+                gratisTypes={status?.config?.gratisTypes}
                 submitOrder={submitOrder}
                 stockMap={stockSnapshotRef.current}
               />
@@ -13,9 +13,12 @@ import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/u
 import { Label } from "@/components/ui/label"
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
 
+import { allergenLabels } from "@/lib/allergens"
 import { getDeviceToken } from "@/lib/device-auth"
 import { playScanSound, primeScanAudio } from "@/lib/scan-sound"
 import { parseScan } from "@/lib/station-scan"
+import { itemName } from "@/lib/utils"
+import type { VolunteerChoiceGroup } from "@/types/volunteer"
 
 const SCAN_RESUME_DELAY_MS = 1400
 const DUPLICATE_SCAN_WINDOW_MS = 5000
@@ -42,6 +45,9 @@ type OrderLine = {
   parentLineId?: string | null
   menuSlotId?: string | null
   menuSlotName?: string | null
+  variantId?: string | null
+  variantName?: string | null
+  allergens?: string[] | null
   childLines?: OrderLine[]
 }
 
@@ -59,6 +65,16 @@ type ScanResult = {
   bundleParents?: Record<string, OrderLine>
 }
 
+type CampaignRedeemBody = { qrPayload?: string; claimToken?: string; choices?: Record<string, string> }
+
+type PendingChoice = {
+  key: string
+  body: CampaignRedeemBody
+  idem: string
+  groups: VolunteerChoiceGroup[]
+  picks: Record<string, string>
+}
+
 type LineGroup = { key: string; parent: OrderLine; children: OrderLine[] }
 
 function groupLines(lines: OrderLine[], bundleParents?: Record<string, OrderLine>): LineGroup[] {
@@ -93,12 +109,14 @@ type ConsolidatedItem = {
   quantity: number
   redeemed: boolean
   redemptionTime?: string
+  allergens?: string[] | null
 }
 
 function consolidateLines(lines: OrderLine[]): ConsolidatedItem[] {
   const map = new Map<string, ConsolidatedItem>()
   for (const l of lines) {
-    const existing = map.get(l.productId)
+    const key = l.variantId ? `${l.productId}:${l.variantId}` : l.productId
+    const existing = map.get(key)
     if (existing) {
       existing.quantity += l.quantity
       if (!l.redemption) existing.redeemed = false
@@ -107,13 +125,14 @@ function consolidateLines(lines: OrderLine[]): ConsolidatedItem[] {
         existing.redemptionTime = t
       }
     } else {
-      map.set(l.productId, {
+      map.set(key, {
         productId: l.productId,
-        title: l.title,
+        title: itemName(l.title, l.variantName),
         productImage: l.productImage,
         quantity: l.quantity,
         redeemed: !!l.redemption,
         redemptionTime: l.redemption?.redeemedAt,
+        allergens: l.allergens,
       })
     }
   }
@@ -174,6 +193,7 @@ export default function StationPage() {
   const [error, setError] = useState<string | null>(null)
   const [scans, setScans] = useState<ScanResult[]>([])
   const [detailOrderId, setDetailOrderId] = useState<string | null>(null)
+  const [pendingChoice, setPendingChoice] = useState<PendingChoice | null>(null)
   const [cameraPermission, setCameraPermission] = useState<"idle" | "pending" | "granted" | "denied">("idle")
   const [scannerActive, setScannerActive] = useState(false)
   const [scannerKey, setScannerKey] = useState(0)
@@ -356,7 +376,7 @@ export default function StationPage() {
   }, [])
 
   const fireRedeem = useCallback(
-    (orderId: string) => {
+    (orderId: string, qrPayload?: string) => {
       const idem = `idem_${Date.now()}_${Math.random().toString(36).slice(2)}`
       fetch(`/api/v1/stations/redeem`, {
         method: "POST",
@@ -365,12 +385,124 @@ export default function StationPage() {
           Authorization: `Bearer ${bearerToken}`,
           "Idempotency-Key": idem,
         },
-        body: JSON.stringify({ orderId }),
+        body: JSON.stringify(qrPayload ? { qrPayload } : { orderId }),
       }).catch(() => {})
     },
     [bearerToken]
   )
 
+  // Redeems a staff meal QR. If the campaign has choice groups this station
+  // serves several options of, the backend answers choice_required and the
+  // choice dialog resubmits with the picks.
+  const redeemCampaign = useCallback(
+    async (key: string, body: CampaignRedeemBody, idem: string) => {
+      const res = await fetch(`/api/v1/stations/redeem-campaign`, {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: `Bearer ${bearerToken}`,
+          "Idempotency-Key": idem,
+        },
+        body: JSON.stringify(body),
+      })
+      type Problem = {
+        code?: string
+        detail?: string
+        message?: string
+        details?: { groups?: VolunteerChoiceGroup[] }
+      }
+      if (!res.ok) {
+        const j = (await res.json().catch(() => ({}))) as Problem
+        if (res.status === 409 && j.code === "choice_required" && j.details?.groups) {
+          // Keep the scanner paused until staff picked or cancelled.
+          setPendingChoice({ key, body, idem, groups: j.details.groups, picks: {} })
+          playScanSound("warning")
+          return
+        }
+        const detail =
+          j.detail ||
+          j.message ||
+          (res.status === 409
+            ? "Maximale Einlösungen erreicht."
+            : res.status === 410
+              ? "Kampagne nicht mehr aktiv."
+              : res.status === 404
+                ? "Kampagne nicht gefunden."
+                : `Fehler ${res.status}`)
+        throw new Error(detail)
+      }
+      type CampaignResp = {
+        orderId: string
+        redemptionCount: number
+        maxRedemptions: number
+        station?: {
+          orderId?: string
+          redeemedAt?: string
+          items?: Array<{
+            id: string
+            orderId: string
+            productId: string
+            title: string
+            quantity: number
+            isRedeemed: boolean
+            parentItemId?: string | null
+            menuSlotId?: string | null
+            menuSlotName?: string | null
+            variantId?: string | null
+            variantName?: string | null
+            allergens?: string[] | null
+          }>
+        }
+      }
+      const data = (await res.json()) as CampaignResp
+      const redeemedAt = data.station?.redeemedAt || new Date().toISOString()
+      const items = data.station?.items || []
+      const lines: OrderLine[] = items.map((it) => ({
+        id: it.id,
+        orderId: it.orderId,
+        productId: it.productId,
+        title: it.title,
+        quantity: it.quantity,
+        unitPriceCents: 0,
+        redemption: it.isRedeemed ? { id: it.id, redeemedAt } : null,
+        parentLineId: it.parentItemId ?? null,
+        menuSlotId: it.menuSlotId ?? null,
+        menuSlotName: it.menuSlotName ?? null,
+        variantId: it.variantId ?? null,
+        variantName: it.variantName ?? null,
+        allergens: it.allergens ?? null,
+      }))
+      const unredeemed = lines.filter((l) => !l.redemption)
+      const allRedeemed = lines.length > 0 && unredeemed.length === 0
+      const nextStatus: ScanStatus = lines.length === 0 ? "no-items" : allRedeemed ? "already-redeemed" : "success"
+      updateScan(key, { status: nextStatus, orderId: data.orderId, lines })
+      playScanSound(nextStatus === "success" ? "success" : "warning")
+      scheduleResume()
+    },
+    [bearerToken, scheduleResume, updateScan]
+  )
+
+  const submitChoice = useCallback(async () => {
+    const pending = pendingChoice
+    if (!pending) return
+    setPendingChoice(null)
+    try {
+      await redeemCampaign(pending.key, { ...pending.body, choices: pending.picks }, pending.idem)
+    } catch (e: unknown) {
+      const message = e instanceof Error ? e.message : "Scan fehlgeschlagen"
+      updateScan(pending.key, { status: "error", message })
+      playScanSound("error")
+      scheduleResume()
+    }
+  }, [pendingChoice, redeemCampaign, scheduleResume, updateScan])
+
+  const cancelChoice = useCallback(() => {
+    const pending = pendingChoice
+    setPendingChoice(null)
+    if (pending) updateScan(pending.key, { status: "error", message: "Auswahl abgebrochen" })
+    scheduleResume(0)
+  }, [pendingChoice, scheduleResume, updateScan])
+
   const handleScanned = useCallback(
     async (code: string) => {
       const parsed = parseScan(code)
@@ -409,71 +541,8 @@ export default function StationPage() {
       try {
         if (parsed.kind === "campaign") {
           const idem = `idem_${Date.now()}_${Math.random().toString(36).slice(2)}`
-          const res = await fetch(`/api/v1/stations/redeem-campaign`, {
-            method: "POST",
-            headers: {
-              "Content-Type": "application/json",
-              Authorization: `Bearer ${bearerToken}`,
-              "Idempotency-Key": idem,
-            },
-            body: JSON.stringify({ claimToken: parsed.id }),
-          })
-          type Problem = { detail?: string; message?: string }
-          if (!res.ok) {
-            const j = (await res.json().catch(() => ({}))) as Problem
-            const detail =
-              j.detail ||
-              j.message ||
-              (res.status === 409
-                ? "Maximale Einlösungen erreicht."
-                : res.status === 410
-                  ? "Kampagne nicht mehr aktiv."
-                  : res.status === 404
-                    ? "Kampagne nicht gefunden."
-                    : `Fehler ${res.status}`)
-            throw new Error(detail)
-          }
-          type CampaignResp = {
-            orderId: string
-            redemptionCount: number
-            maxRedemptions: number
-            station?: {
-              orderId?: string
-              redeemedAt?: string
-              items?: Array<{
-                id: string
-                orderId: string
-                productId: string
-                title: string
-                quantity: number
-                isRedeemed: boolean
-                parentItemId?: string | null
-                menuSlotId?: string | null
-                menuSlotName?: string | null
-              }>
-            }
-          }
-          const data = (await res.json()) as CampaignResp
-          const redeemedAt = data.station?.redeemedAt || new Date().toISOString()
-          const items = data.station?.items || []
-          const lines: OrderLine[] = items.map((it) => ({
-            id: it.id,
-            orderId: it.orderId,
-            productId: it.productId,
-            title: it.title,
-            quantity: it.quantity,
-            unitPriceCents: 0,
-            redemption: it.isRedeemed ? { id: it.id, redeemedAt } : null,
-            parentLineId: it.parentItemId ?? null,
-            menuSlotId: it.menuSlotId ?? null,
-            menuSlotName: it.menuSlotName ?? null,
-          }))
-          const unredeemed = lines.filter((l) => !l.redemption)
-          const allRedeemed = lines.length > 0 && unredeemed.length === 0
-          const nextStatus: ScanStatus = lines.length === 0 ? "no-items" : allRedeemed ? "already-redeemed" : "success"
-          updateScan(key, { status: nextStatus, orderId: data.orderId, lines })
-          playScanSound(nextStatus === "success" ? "success" : "warning")
-          scheduleResume()
+          const body = parsed.payload ? { qrPayload: parsed.payload } : { claimToken: parsed.id }
+          await redeemCampaign(key, body, idem)
           return
         }
 
@@ -515,7 +584,7 @@ export default function StationPage() {
         }
 
         if (nextStatus === "success") {
-          fireRedeem(orderData.id)
+          fireRedeem(orderData.id, parsed.payload)
           const redeemedAt = new Date().toISOString()
           const optimisticLines = stationLines.map((l) =>
             l.redemption ? l : { ...l, redemption: { id: l.id, redeemedAt } }
@@ -534,7 +603,7 @@ export default function StationPage() {
         scheduleResume()
       }
     },
-    [bearerToken, fireRedeem, pushScan, scheduleResume, status, updateScan]
+    [bearerToken, fireRedeem, pushScan, redeemCampaign, scheduleResume, status, updateScan]
   )
 
   const handleDecoded = useCallback(
@@ -718,6 +787,14 @@ export default function StationPage() {
       )}
 
       <OrderDetailDialog orderId={detailOrderId} bearerToken={bearerToken} onClose={() => setDetailOrderId(null)} />
+      <CampaignChoiceDialog
+        pending={pendingChoice}
+        onPick={(groupId, productId) =>
+          setPendingChoice((p) => (p ? { ...p, picks: { ...p.picks, [groupId]: productId } } : p))
+        }
+        onConfirm={submitChoice}
+        onCancel={cancelChoice}
+      />
     </div>
   )
 }
@@ -862,6 +939,7 @@ function ConsolidatedItemCard({ item, redeemed }: { item: ConsolidatedItem; rede
         )}
         <div className="min-w-0 flex-1">
           <p className="truncate font-medium">{item.title}</p>
+          <AllergenNote keys={item.allergens} />
           {redeemed && item.redemptionTime && (
             <div className="text-muted-foreground mt-0.5 text-xs tabular-nums">
               Eingelöst {new Date(item.redemptionTime).toLocaleTimeString("de-CH")}
@@ -874,6 +952,12 @@ function ConsolidatedItemCard({ item, redeemed }: { item: ConsolidatedItem; rede
   )
 }
 
+function AllergenNote({ keys }: { keys?: string[] | null }) {
+  const labels = allergenLabels(keys)
+  if (!labels) return null
+  return <div className="mt-0.5 truncate text-xs text-amber-700">Allergene: {labels}</div>
+}
+
 function LineGroupCard({ group, redeemed }: { group: LineGroup; redeemed: boolean }) {
   const { parent, children } = group
   const isBundle = children.length > 0
@@ -898,7 +982,7 @@ function LineGroupCard({ group, redeemed }: { group: LineGroup; redeemed: boolea
           </div>
         )}
         <div className="min-w-0 flex-1">
-          <p className="truncate font-medium">{parent.title}</p>
+          <p className="truncate font-medium">{itemName(parent.title, parent.variantName)}</p>
           {isBundle ? (
             <div className="mt-1 flex flex-row flex-wrap gap-1.5">
               {children.map((c) => (
@@ -912,6 +996,7 @@ function LineGroupCard({ group, redeemed }: { group: LineGroup; redeemed: boolea
               <div className="text-muted-foreground mt-0.5 truncate text-xs">{parent.menuSlotName}</div>
             )
           )}
+          <AllergenNote keys={isBundle ? children.flatMap((c) => c.allergens ?? []) : parent.allergens} />
           {redeemed && redemptionTime && (
             <div className="text-muted-foreground mt-0.5 text-xs tabular-nums">
               Eingelöst {new Date(redemptionTime).toLocaleTimeString("de-CH")}
@@ -1018,3 +1103,62 @@ function OrderDetailDialog({
     </Dialog>
   )
 }
+
+function CampaignChoiceDialog({
+  pending,
+  onPick,
+  onConfirm,
+  onCancel,
+}: {
+  pending: PendingChoice | null
+  onPick: (groupId: string, productId: string) => void
+  onConfirm: () => void
+  onCancel: () => void
+}) {
+  const complete = !!pending && pending.groups.every((g) => pending.picks[g.id])
+  return (
+    <Dialog open={!!pending} onOpenChange={(open) => !open && onCancel()}>
+      <DialogContent className="max-h-[85vh] overflow-y-auto sm:max-w-md">
+        <DialogHeader>
+          <DialogTitle className="font-primary text-center text-xl tracking-wide uppercase">Auswahl</DialogTitle>
+        </DialogHeader>
+        <div className="flex flex-col gap-5">
+          {pending?.groups.map((g) => (
+            <div key={g.id} className="flex flex-col gap-2">
+              <p className="font-medium">
+                {g.name}
+                {g.quantity > 1 && <span className="text-muted-foreground ml-2 tabular-nums">×{g.quantity}</span>}
+              </p>
+              <div className="grid grid-cols-2 gap-2">
+                {g.options.map((o) => {
+                  const on = pending.picks[g.id] === o.productId
+                  return (
+                    <Button
+                      key={o.productId}
+                      type="button"
+                      size="lg"
+                      variant={on ? "default" : "outline"}
+                      className="h-auto min-h-12 py-3 whitespace-normal"
+                      onClick={() => onPick(g.id, o.productId)}
+                      aria-pressed={on}
+                    >
+                      {o.productName}
+                    </Button>
+                  )
+                })}
+              </div>
+            </div>
+          ))}
+          <div className="flex gap-2">
+            <Button variant="outline" className="flex-1" onClick={onCancel}>
+              Abbrechen
+            </Button>
+            <Button className="flex-1" onClick={onConfirm} disabled={!complete}>
+              Einlösen
+            </Button>
+          </div>
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -0,0 +1,138 @@
+"use client"
+
+import { useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { ALLERGENS, DIETARY_TAGS } from "@/lib/allergens"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { Allergen, DietaryTag } from "@/types"
+
+export type AllergensTarget = {
+  id: string
+  name: string
+  type: "simple" | "menu"
+  allergens?: Allergen[]
+  declaredAllergens?: Allergen[]
+  dietaryTags?: DietaryTag[]
+}
+
+type Props = {
+  product: AllergensTarget | null
+  onOpenChange: (open: boolean) => void
+  onSaved: (saved: { allergens?: Allergen[]; declaredAllergens?: Allergen[]; dietaryTags?: DietaryTag[] }) => void
+}
+
+function toggle<T>(list: T[], key: T): T[] {
+  return list.includes(key) ? list.filter((k) => k !== key) : [...list, key]
+}
+
+// Edits the declared allergens and dietary tags of a product. A menu lists
+// only what it adds itself (e.g. a sauce); the allergens of its slot options
+// are added automatically.
+export function AllergensDialog({ product, onOpenChange, onSaved }: Props) {
+  const fetchAuth = useAuthorizedFetch()
+  const [allergens, setAllergens] = useState<Allergen[]>([])
+  const [dietaryTags, setDietaryTags] = useState<DietaryTag[]>([])
+  const [busy, setBusy] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    if (!product) return
+    // A menu's allergens include those of its options; edit its own.
+    setAllergens((product.type === "menu" ? product.declaredAllergens : product.allergens) ?? [])
+    setDietaryTags(product.dietaryTags ?? [])
+    setError(null)
+  }, [product])
+
+  async function save() {
+    if (!product) return
+    setBusy(true)
+    setError(null)
+    try {
+      const csrf = getCSRFToken()
+      const res = await fetchAuth(`/api/v1/products/${encodeURIComponent(product.id)}`, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+        body: JSON.stringify({ allergens, dietaryTags }),
+      })
+      if (!res.ok) {
+        setError(await readErrorMessage(res))
+        return
+      }
+      const saved = (await res.json()) as {
+        allergens?: Allergen[]
+        declaredAllergens?: Allergen[]
+        dietaryTags?: DietaryTag[]
+      }
+      onSaved({
+        allergens: saved.allergens,
+        declaredAllergens: saved.declaredAllergens,
+        dietaryTags: saved.dietaryTags,
+      })
+      onOpenChange(false)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  return (
+    <Dialog open={product !== null} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Allergene: {product?.name}</DialogTitle>
+        </DialogHeader>
+        {product?.type === "menu" && (
+          <p className="text-muted-foreground text-sm">
+            Nur was das Menü selbst enthält. Die Allergene der gewählten Optionen kommen automatisch dazu.
+          </p>
+        )}
+
+        {error && (
+          <div role="alert" className="text-destructive bg-destructive/10 rounded px-3 py-2 text-sm">
+            {error}
+          </div>
+        )}
+
+        <div className="space-y-2">
+          <p className="text-sm font-medium">Enthält</p>
+          <div className="flex flex-wrap gap-1.5">
+            {ALLERGENS.map((a) => (
+              <Button
+                key={a.key}
+                size="sm"
+                variant={allergens.includes(a.key) ? "default" : "outline"}
+                onClick={() => setAllergens((prev) => toggle(prev, a.key))}
+              >
+                {a.label}
+              </Button>
+            ))}
+          </div>
+        </div>
+
+        <div className="space-y-2">
+          <p className="text-sm font-medium">Ernährung</p>
+          <div className="flex flex-wrap gap-1.5">
+            {DIETARY_TAGS.map((t) => (
+              <Button
+                key={t.key}
+                size="sm"
+                variant={dietaryTags.includes(t.key) ? "default" : "outline"}
+                onClick={() => setDietaryTags((prev) => toggle(prev, t.key))}
+              >
+                {t.label}
+              </Button>
+            ))}
+          </div>
+        </div>
+
+        <div className="flex border-t pt-3">
+          <Button size="sm" className="ml-auto" onClick={() => void save()} disabled={busy}>
+            Speichern
+          </Button>
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
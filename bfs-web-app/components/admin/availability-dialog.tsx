@@ -0,0 +1,188 @@
+"use client"
+
+import { Trash2 } from "lucide-react"
+import { useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { Input } from "@/components/ui/input"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+// A window in Swiss local time. With a date it applies on that day only (an
+// event day) and replaces the weekly windows there.
+type AvailabilityWindow = { weekdays?: number[]; date: string | null; start: string; end: string }
+
+export type AvailabilityTarget = { kind: "products" | "categories"; id: string; name: string }
+
+type Props = {
+  target: AvailabilityTarget | null
+  onOpenChange: (open: boolean) => void
+}
+
+const WEEKDAYS = ["Mo", "Di", "Mi", "Do", "Fr", "Sa", "So"]
+const ALL_DAYS = [1, 2, 3, 4, 5, 6, 7]
+
+// Edits when a product or a whole category can be ordered, e.g. breakfast
+// until 11:00 or the grill from 17:00. Without windows it is always orderable.
+export function AvailabilityDialog({ target, onOpenChange }: Props) {
+  const fetchAuth = useAuthorizedFetch()
+  const [windows, setWindows] = useState<AvailabilityWindow[]>([])
+  const [busy, setBusy] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    if (!target) return
+    setWindows([])
+    setError(null)
+    ;(async () => {
+      const res = await fetchAuth(`/api/v1/${target.kind}/${target.id}/availability`)
+      if (!res.ok) {
+        setError(await readErrorMessage(res))
+        return
+      }
+      const data = (await res.json()) as { windows: AvailabilityWindow[] }
+      setWindows((data.windows ?? []).map((w) => ({ ...w, weekdays: w.date ? undefined : (w.weekdays ?? ALL_DAYS) })))
+    })()
+  }, [target, fetchAuth])
+
+  function change(index: number, patch: Partial<AvailabilityWindow>) {
+    setWindows((prev) => prev.map((w, i) => (i === index ? { ...w, ...patch } : w)))
+  }
+
+  function toggleDay(index: number, day: number) {
+    const days = windows[index].weekdays ?? ALL_DAYS
+    const next = days.includes(day) ? days.filter((d) => d !== day) : [...days, day].sort((a, b) => a - b)
+    change(index, { weekdays: next })
+  }
+
+  async function save() {
+    if (!target) return
+    if (windows.some((w) => !w.date && (w.weekdays ?? []).length === 0)) {
+      setError("Bitte mindestens einen Wochentag wählen.")
+      return
+    }
+    setBusy(true)
+    setError(null)
+    try {
+      const csrf = getCSRFToken()
+      const res = await fetchAuth(`/api/v1/${target.kind}/${target.id}/availability`, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+        body: JSON.stringify({ windows }),
+      })
+      if (!res.ok) {
+        setError(await readErrorMessage(res))
+        return
+      }
+      onOpenChange(false)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  return (
+    <Dialog open={target !== null} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Verfügbarkeit: {target?.name}</DialogTitle>
+        </DialogHeader>
+        <p className="text-muted-foreground text-sm">
+          Ohne Zeitfenster ist {target?.kind === "categories" ? "die Kategorie" : "das Produkt"} jederzeit bestellbar.
+          Fenster mit Datum gelten nur an diesem Tag und ersetzen dort die wöchentlichen.
+        </p>
+
+        {error && (
+          <div role="alert" className="text-destructive bg-destructive/10 rounded px-3 py-2 text-sm">
+            {error}
+          </div>
+        )}
+
+        <div className="space-y-3">
+          {windows.map((w, i) => (
+            <div key={i} className="flex flex-wrap items-center gap-2 rounded-md border p-2">
+              {w.date !== null ? (
+                <Input
+                  type="date"
+                  value={w.date}
+                  onChange={(e) => change(i, { date: e.target.value })}
+                  aria-label="Datum"
+                  className="h-8 w-40"
+                />
+              ) : (
+                <div className="inline-flex gap-1">
+                  {WEEKDAYS.map((label, d) => {
+                    const day = d + 1
+                    const on = (w.weekdays ?? ALL_DAYS).includes(day)
+                    return (
+                      <Button
+                        key={label}
+                        variant={on ? "default" : "outline"}
+                        size="sm"
+                        className="h-8 w-9 px-0"
+                        onClick={() => toggleDay(i, day)}
+                      >
+                        {label}
+                      </Button>
+                    )
+                  })}
+                </div>
+              )}
+              <Input
+                type="time"
+                value={w.start}
+                onChange={(e) => change(i, { start: e.target.value })}
+                aria-label="Von"
+                className="h-8 w-28"
+              />
+              <Input
+                type="time"
+                value={w.end}
+                onChange={(e) => change(i, { end: e.target.value })}
+                aria-label="Bis"
+                className="h-8 w-28"
+              />
+              <Button
+                variant="ghost"
+                size="icon"
+                className="h-8 w-8 text-red-700"
+                aria-label="Zeitfenster entfernen"
+                onClick={() => setWindows((prev) => prev.filter((_, j) => j !== i))}
+              >
+                <Trash2 className="size-4" />
+              </Button>
+            </div>
+          ))}
+          {windows.length === 0 && <p className="text-muted-foreground text-sm">Keine Zeitfenster.</p>}
+        </div>
+
+        <div className="flex flex-wrap gap-2 border-t pt-3">
+          <Button
+            variant="outline"
+            size="sm"
+            onClick={() =>
+              setWindows((prev) => [...prev, { weekdays: ALL_DAYS, date: null, start: "08:00", end: "11:00" }])
+            }
+          >
+            Wöchentlich
+          </Button>
+          <Button
+            variant="outline"
+            size="sm"
+            onClick={() =>
+              setWindows((prev) => [
+                ...prev,
+                { date: new Date().toISOString().slice(0, 10), start: "17:00", end: "22:00" },
+              ])
+            }
+          >
+            An Datum
+          </Button>
+          <Button size="sm" className="ml-auto" onClick={() => void save()} disabled={busy}>
+            Speichern
+          </Button>
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
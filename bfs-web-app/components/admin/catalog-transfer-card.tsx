@@ -0,0 +1,145 @@
+"use client"
+
+import { Download, Loader2, Upload } from "lucide-react"
+import { useRef, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { CatalogCounts, CatalogImportResult } from "@/types/catalog"
+
+const KINDS: { key: keyof CatalogCounts; label: string }[] = [
+  { key: "jetons", label: "Jetons" },
+  { key: "categories", label: "Kategorien" },
+  { key: "products", label: "Produkte" },
+  { key: "variants", label: "Varianten" },
+  { key: "slots", label: "Menu-Slots" },
+]
+
+function summary(counts: CatalogCounts): string {
+  const parts = KINDS.filter(({ key }) => counts[key] > 0).map(({ key, label }) => `${counts[key]} ${label}`)
+  return parts.length > 0 ? parts.join(", ") : "keine"
+}
+
+// Exports the catalog (jetons, categories, products with variants, stations
+// and menu slots) and imports it again. An import is always checked first;
+// it is only applied after the preview is confirmed.
+export function CatalogTransferCard() {
+  const fetchAuth = useAuthorizedFetch()
+  const fileRef = useRef<HTMLInputElement>(null)
+  const [file, setFile] = useState<File | null>(null)
+  const [result, setResult] = useState<CatalogImportResult | null>(null)
+  const [busy, setBusy] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  async function upload(f: File, dryRun: boolean) {
+    setBusy(true)
+    setError(null)
+    try {
+      const form = new FormData()
+      form.append("file", f)
+      const res = await fetchAuth(`/api/v1/catalog/import?dryRun=${dryRun}`, {
+        method: "POST",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+        body: form,
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setResult((await res.json()) as CatalogImportResult)
+    } catch (e: unknown) {
+      setResult(null)
+      setError(e instanceof Error ? e.message : "Import fehlgeschlagen")
+    } finally {
+      setBusy(false)
+      if (fileRef.current) fileRef.current.value = ""
+    }
+  }
+
+  function reset() {
+    setFile(null)
+    setResult(null)
+    setError(null)
+  }
+
+  const canApply = file !== null && result !== null && result.dryRun && result.problems.length === 0
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>Katalog importieren / exportieren</CardTitle>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-4">
+        <p className="text-muted-foreground text-sm">
+          Der Export enthält Jetons, Kategorien und Produkte mit Varianten, Stationen und Menu-Slots. Beim Import werden
+          Einträge über ihren Namen zugeordnet: bestehende werden aktualisiert, neue angelegt. Einträge, die in der Datei
+          fehlen, bleiben unverändert.
+        </p>
+        <div className="flex flex-wrap gap-3">
+          <Button variant="outline" onClick={() => window.open("/api/v1/catalog/export?format=json", "_blank")}>
+            <Download className="size-4" aria-hidden />
+            JSON exportieren
+          </Button>
+          <Button variant="outline" onClick={() => window.open("/api/v1/catalog/export?format=csv", "_blank")}>
+            <Download className="size-4" aria-hidden />
+            CSV exportieren
+          </Button>
+          <input
+            ref={fileRef}
+            type="file"
+            accept=".json,.csv,application/json,text/csv"
+            className="hidden"
+            onChange={(e) => {
+              const f = e.target.files?.[0]
+              if (!f) return
+              setFile(f)
+              void upload(f, true)
+            }}
+          />
+          <Button variant="outline" onClick={() => fileRef.current?.click()} disabled={busy}>
+            {busy ? <Loader2 className="size-4 animate-spin" aria-hidden /> : <Upload className="size-4" aria-hidden />}
+            Datei prüfen
+          </Button>
+        </div>
+
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2 text-sm">{error}</div>}
+        {result && (
+          <div className="bg-muted flex flex-col gap-2 rounded-xl px-3 py-2 text-sm">
+            <p className="font-medium">
+              {result.applied ? "Import übernommen" : result.problems.length > 0 ? "Import nicht möglich" : "Vorschau"}
+              {file && <span className="text-muted-foreground font-normal"> – {file.name}</span>}
+            </p>
+            <p>
+              {result.applied ? "Neu" : "Neu anzulegen"}: {summary(result.created)}
+            </p>
+            <p>
+              {result.applied ? "Aktualisiert" : "Zu aktualisieren"}: {summary(result.updated)}
+            </p>
+            {result.problems.length > 0 && (
+              <ul className="text-destructive list-disc pl-5 text-xs">
+                {result.problems.map((p, i) => (
+                  <li key={i}>
+                    {p.line ? `Zeile ${p.line}, ` : ""}
+                    {p.record}: {p.message}
+                  </li>
+                ))}
+              </ul>
+            )}
+            {result.problems.length > 0 && (
+              <p className="text-muted-foreground text-xs">Die Datei korrigieren und erneut prüfen.</p>
+            )}
+            <div className="flex gap-2">
+              {canApply && (
+                <Button size="sm" onClick={() => file && void upload(file, false)} disabled={busy}>
+                  Import übernehmen
+                </Button>
+              )}
+              <Button size="sm" variant="ghost" onClick={reset} disabled={busy}>
+                Schliessen
+              </Button>
+            </div>
+          </div>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,158 @@
+"use client"
+
+import { Ban, Loader2, Printer, RefreshCw } from "lucide-react"
+import { useCallback, useEffect, useMemo, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import type { Club100MemberCard } from "@/lib/api/club100"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+// Printed member cards carry a signed QR code the POS can scan instead of
+// searching by name. Reissuing a card invalidates the previous one.
+export function Club100CardsCard() {
+  const fetchAuth = useAuthorizedFetch()
+  const [items, setItems] = useState<Club100MemberCard[]>([])
+  const [signingEnabled, setSigningEnabled] = useState(true)
+  const [selected, setSelected] = useState<Set<string>>(new Set())
+  const [busy, setBusy] = useState<string | null>(null)
+  const [error, setError] = useState<string | null>(null)
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(`/api/v1/club100/cards`)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const data = (await res.json()) as { items: Club100MemberCard[]; signingEnabled: boolean }
+      setItems(data.items)
+      setSigningEnabled(data.signingEnabled)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  const withCard = useMemo(() => items.filter((m) => m.cardId), [items])
+  const missing = items.length - withCard.length
+
+  async function mutate(key: string, path: string, method: "POST" | "DELETE") {
+    setBusy(key)
+    setError(null)
+    try {
+      const res = await fetchAuth(path, { method, headers: { "X-CSRF": getCSRFToken() || "" } })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Aktion fehlgeschlagen")
+    } finally {
+      setBusy(null)
+      void load()
+    }
+  }
+
+  function reissue(m: Club100MemberCard) {
+    const name = `${m.firstName} ${m.lastName}`
+    if (m.cardId && !confirm(`Neue Karte für ${name} ausstellen? Die alte Karte wird ungültig.`)) return
+    void mutate(m.elvantoPersonId, `/api/v1/club100/cards/${encodeURIComponent(m.elvantoPersonId)}`, "POST")
+  }
+
+  function revoke(m: Club100MemberCard) {
+    if (!confirm(`Karte von ${m.firstName} ${m.lastName} sperren?`)) return
+    void mutate(m.elvantoPersonId, `/api/v1/club100/cards/${encodeURIComponent(m.elvantoPersonId)}`, "DELETE")
+  }
+
+  function toggle(id: string) {
+    setSelected((prev) => {
+      const next = new Set(prev)
+      if (next.has(id)) next.delete(id)
+      else next.add(id)
+      return next
+    })
+  }
+
+  function print(ids: string[]) {
+    const q = ids.length > 0 ? `?ids=${ids.map(encodeURIComponent).join(",")}` : ""
+    window.open(`/api/v1/club100/cards/print.pdf${q}`, "_blank")
+  }
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>100 Club Mitgliederkarten</CardTitle>
+        <p className="text-muted-foreground text-sm">
+          Karten mit QR-Code, die an der Kasse gescannt werden können. Eine neue Karte macht die alte ungültig.
+        </p>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-3 text-sm">
+        {!signingEnabled && (
+          <div className="text-amber-700">
+            QR-Signierung ist nicht konfiguriert (QR_SIGNING_SECRET) – Karten können nicht gedruckt werden.
+          </div>
+        )}
+        <div className="flex flex-wrap gap-2">
+          <Button
+            variant="outline"
+            onClick={() => void mutate("missing", `/api/v1/club100/cards`, "POST")}
+            disabled={busy !== null || missing === 0}
+          >
+            {busy === "missing" ? (
+              <Loader2 className="size-4 animate-spin" aria-hidden />
+            ) : (
+              <RefreshCw className="size-4" aria-hidden />
+            )}
+            Fehlende Karten ausstellen ({missing})
+          </Button>
+          <Button
+            variant="outline"
+            onClick={() => print(Array.from(selected))}
+            disabled={!signingEnabled || withCard.length === 0}
+          >
+            <Printer className="size-4" aria-hidden />
+            {selected.size > 0 ? `${selected.size} drucken` : "Alle drucken"}
+          </Button>
+        </div>
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+        <div className="max-h-96 divide-y overflow-y-auto rounded-xl border">
+          {items.length === 0 && <div className="text-muted-foreground px-3 py-2">Keine aktiven Mitglieder</div>}
+          {items.map((m) => (
+            <div key={m.elvantoPersonId} className="flex items-center gap-3 px-3 py-2">
+              <input
+                type="checkbox"
+                aria-label={`${m.firstName} ${m.lastName} auswählen`}
+                checked={selected.has(m.elvantoPersonId)}
+                onChange={() => toggle(m.elvantoPersonId)}
+                disabled={!m.cardId}
+              />
+              <div className="flex-1">
+                <div className="font-medium">
+                  {m.firstName} {m.lastName}
+                </div>
+                <div className="text-muted-foreground text-xs">
+                  {m.cardIssuedAt
+                    ? `Ausgestellt ${new Date(m.cardIssuedAt).toLocaleDateString("de-CH")}`
+                    : "Keine Karte"}
+                </div>
+              </div>
+              <Button size="sm" variant="outline" onClick={() => reissue(m)} disabled={busy !== null}>
+                {m.cardId ? "Neu ausstellen" : "Ausstellen"}
+              </Button>
+              {m.cardId && (
+                <Button
+                  size="sm"
+                  variant="ghost"
+                  onClick={() => revoke(m)}
+                  disabled={busy !== null}
+                  aria-label="Karte sperren"
+                >
+                  <Ban className="size-4" aria-hidden />
+                </Button>
+              )}
+            </div>
+          ))}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
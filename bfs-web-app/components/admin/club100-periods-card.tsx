@@ -0,0 +1,232 @@
+"use client"
+
+import { CalendarPlus, Loader2, Trash2 } from "lucide-react"
+import { useCallback, useEffect, useState } from "react"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import type { Club100Period, Club100PeriodReport } from "@/lib/api/club100"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+// Periods are edited as whole days: start at local midnight, end exclusive at
+// the midnight after the last day.
+function dayStart(date: string): Date {
+  const [y, m, d] = date.split("-").map(Number)
+  return new Date(y!, m! - 1, d!)
+}
+
+function formatDay(d: Date): string {
+  return d.toLocaleDateString("de-CH")
+}
+
+function formatRange(p: Club100Period): string {
+  const lastDay = new Date(new Date(p.endsAt).getTime() - 1)
+  return `${formatDay(new Date(p.startsAt))} – ${formatDay(lastDay)}`
+}
+
+// Entitlement periods: members get the configured number of free products
+// per period; outside every period the allowance resets daily.
+export function Club100PeriodsCard() {
+  const fetchAuth = useAuthorizedFetch()
+  const [periods, setPeriods] = useState<Club100Period[]>([])
+  const [name, setName] = useState("")
+  const [from, setFrom] = useState("")
+  const [to, setTo] = useState("")
+  const [saving, setSaving] = useState(false)
+  const [report, setReport] = useState<Club100PeriodReport | null>(null)
+  const [error, setError] = useState<string | null>(null)
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(`/api/v1/club100/periods`)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setPeriods(((await res.json()) as { items: Club100Period[] }).items)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  function presetYear() {
+    const year = new Date().getFullYear()
+    setName(String(year))
+    setFrom(`${year}-01-01`)
+    setTo(`${year}-12-31`)
+  }
+
+  async function create() {
+    if (!name.trim() || !from || !to) return
+    setSaving(true)
+    setError(null)
+    try {
+      const end = dayStart(to)
+      end.setDate(end.getDate() + 1)
+      const res = await fetchAuth(`/api/v1/club100/periods`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json", "X-CSRF": getCSRFToken() || "" },
+        body: JSON.stringify({ name: name.trim(), startsAt: dayStart(from).toISOString(), endsAt: end.toISOString() }),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setName("")
+      setFrom("")
+      setTo("")
+      await load()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  async function remove(p: Club100Period) {
+    if (!confirm(`Periode „${p.name}“ löschen? Die Einlösungen bleiben erhalten.`)) return
+    setError(null)
+    try {
+      const res = await fetchAuth(`/api/v1/club100/periods/${encodeURIComponent(p.id)}`, {
+        method: "DELETE",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+      })
+      if (!res.ok && res.status !== 204) throw new Error(await readErrorMessage(res))
+      await load()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Löschen fehlgeschlagen")
+    }
+  }
+
+  async function openReport(p: Club100Period) {
+    setError(null)
+    try {
+      const res = await fetchAuth(`/api/v1/club100/periods/${encodeURIComponent(p.id)}/report`)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setReport((await res.json()) as Club100PeriodReport)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }
+
+  const hasActive = periods.some((p) => p.active)
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>100 Club Perioden</CardTitle>
+        <p className="text-muted-foreground text-sm">
+          Die Gratis-Produkte gelten pro Periode (z.B. pro Anlass oder Kalenderjahr).
+          {!hasActive && " Aktuell ist keine Periode aktiv – das Kontingent gilt pro Tag."}
+        </p>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-5 text-sm">
+        {periods.length > 0 && (
+          <Table>
+            <TableHeader>
+              <TableRow>
+                <TableHead>Periode</TableHead>
+                <TableHead>Zeitraum</TableHead>
+                <TableHead />
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {periods.map((p) => (
+                <TableRow key={p.id}>
+                  <TableCell className="font-medium">
+                    {p.name} {p.active && <Badge variant="secondary">Aktiv</Badge>}
+                  </TableCell>
+                  <TableCell>{formatRange(p)}</TableCell>
+                  <TableCell className="text-right">
+                    <Button variant="outline" size="sm" onClick={() => void openReport(p)}>
+                      Auswertung
+                    </Button>
+                    <Button variant="ghost" size="sm" onClick={() => void remove(p)} aria-label="Löschen">
+                      <Trash2 className="size-4" aria-hidden />
+                    </Button>
+                  </TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        )}
+
+        <div className="flex flex-wrap items-end gap-3">
+          <div className="grid gap-1.5">
+            <Label htmlFor="club100-period-name">Name</Label>
+            <Input
+              id="club100-period-name"
+              className="w-40"
+              maxLength={100}
+              value={name}
+              onChange={(e) => setName(e.target.value)}
+            />
+          </div>
+          <div className="grid gap-1.5">
+            <Label htmlFor="club100-period-from">Von</Label>
+            <Input id="club100-period-from" type="date" value={from} onChange={(e) => setFrom(e.target.value)} />
+          </div>
+          <div className="grid gap-1.5">
+            <Label htmlFor="club100-period-to">Bis (inkl.)</Label>
+            <Input id="club100-period-to" type="date" value={to} onChange={(e) => setTo(e.target.value)} />
+          </div>
+          <Button variant="ghost" onClick={presetYear}>
+            Kalenderjahr
+          </Button>
+          <Button onClick={create} disabled={saving || !name.trim() || !from || !to}>
+            {saving ? (
+              <Loader2 className="size-4 animate-spin" aria-hidden />
+            ) : (
+              <CalendarPlus className="size-4" aria-hidden />
+            )}
+            Hinzufügen
+          </Button>
+        </div>
+
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+      </CardContent>
+
+      <Dialog open={report !== null} onOpenChange={(open) => !open && setReport(null)}>
+        <DialogContent className="flex max-h-[80vh] flex-col">
+          <DialogHeader>
+            <DialogTitle>{report?.period.name}</DialogTitle>
+          </DialogHeader>
+          {report && (
+            <div className="flex min-h-0 flex-col gap-3 text-sm">
+              <p className="text-muted-foreground">
+                {formatRange(report.period)} · {report.totalQuantity} Gratis-Produkte an {report.items.length}{" "}
+                Mitglieder
+              </p>
+              <div className="min-h-0 overflow-y-auto">
+                <Table>
+                  <TableHeader>
+                    <TableRow>
+                      <TableHead>Mitglied</TableHead>
+                      <TableHead className="text-right">Produkte</TableHead>
+                      <TableHead className="text-right">Bestellungen</TableHead>
+                    </TableRow>
+                  </TableHeader>
+                  <TableBody>
+                    {report.items.map((m) => (
+                      <TableRow key={m.elvantoPersonId}>
+                        <TableCell>{m.elvantoPersonName}</TableCell>
+                        <TableCell className="text-right">
+                          {m.quantity} / {report.max}
+                        </TableCell>
+                        <TableCell className="text-right">{m.orders}</TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </div>
+            </div>
+          )}
+        </DialogContent>
+      </Dialog>
+    </Card>
+  )
+}
@@ -0,0 +1,209 @@
+"use client"
+
+import { Loader2, RefreshCw, Upload } from "lucide-react"
+import { useCallback, useEffect, useRef, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import type { Club100SyncRun, Club100SyncStatus, MembershipProvider } from "@/lib/api/club100"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+const PROVIDER_LABELS: Record<MembershipProvider, string> = {
+  elvanto: "Elvanto",
+  csv: "einer hochgeladenen CSV-Datei",
+  fake: "Testdaten",
+}
+
+function formatRun(run: Club100SyncRun): string {
+  const at = new Date(run.finishedAt ?? run.startedAt).toLocaleString("de-CH")
+  const how = run.trigger === "manual" ? "manuell" : "automatisch"
+  return `${at} (${how})`
+}
+
+// Club100 members are synced from the configured membership provider into a
+// local directory; the POS only reads that directory. With the CSV provider
+// there is nothing to poll, the list is replaced by uploading a new file.
+export function Club100SyncCard() {
+  const fetchAuth = useAuthorizedFetch()
+  const fileRef = useRef<HTMLInputElement>(null)
+  const [status, setStatus] = useState<Club100SyncStatus | null>(null)
+  const [syncing, setSyncing] = useState(false)
+  const [uploading, setUploading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(`/api/v1/club100/sync`)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setStatus((await res.json()) as Club100SyncStatus)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  async function sync() {
+    setSyncing(true)
+    setError(null)
+    try {
+      const res = await fetchAuth(`/api/v1/club100/sync`, {
+        method: "POST",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const run = (await res.json()) as Club100SyncRun
+      if (run.status === "failed") setError(run.error ?? "Synchronisierung fehlgeschlagen")
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Synchronisierung fehlgeschlagen")
+    } finally {
+      setSyncing(false)
+      void load()
+    }
+  }
+
+  async function upload(file: File) {
+    setUploading(true)
+    setError(null)
+    try {
+      const form = new FormData()
+      form.append("file", file)
+      const res = await fetchAuth(`/api/v1/club100/members/import`, {
+        method: "POST",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+        body: form,
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const run = (await res.json()) as Club100SyncRun
+      if (run.status === "failed") setError(run.error ?? "Synchronisierung fehlgeschlagen")
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Hochladen fehlgeschlagen")
+    } finally {
+      setUploading(false)
+      if (fileRef.current) fileRef.current.value = ""
+      void load()
+    }
+  }
+
+  const isCSV = status?.provider === "csv"
+  const lastRun = status?.lastRun
+  const lastSuccess = status?.lastSuccess
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>100 Club Mitglieder</CardTitle>
+        {status && (
+          <p className="text-muted-foreground text-sm">
+            {isCSV
+              ? "Die Mitgliederliste stammt aus einer hochgeladenen CSV-Datei."
+              : `Die Mitgliederliste wird regelmässig aus ${PROVIDER_LABELS[status.provider]} übernommen (alle ${Math.round(status.intervalSeconds / 60)} Minuten).`}
+          </p>
+        )}
+      </CardHeader>
+      <CardContent className="flex max-w-lg flex-col gap-3 text-sm">
+        {status && !status.configured && (
+          <div className="text-amber-700">
+            {PROVIDER_LABELS[status.provider]} ist nicht konfiguriert – es kann nicht synchronisiert werden.
+          </div>
+        )}
+        {status && (
+          <dl className="grid grid-cols-[auto_1fr] gap-x-4 gap-y-1">
+            <dt className="text-muted-foreground">Aktive Mitglieder</dt>
+            <dd>{status.memberCount}</dd>
+            {status.groups.length > 0 && (
+              <>
+                <dt className="text-muted-foreground">Gruppen</dt>
+                <dd>{status.groups.join(", ")}</dd>
+              </>
+            )}
+            {status.tags.length > 0 && (
+              <>
+                <dt className="text-muted-foreground">Merkmale</dt>
+                <dd>{status.tags.join(", ")}</dd>
+              </>
+            )}
+            {isCSV && (
+              <>
+                <dt className="text-muted-foreground">Aktuelle Datei</dt>
+                <dd>
+                  {status.lastUpload
+                    ? `${status.lastUpload.filename} (${status.lastUpload.memberCount} Personen, ${new Date(status.lastUpload.uploadedAt).toLocaleString("de-CH")})`
+                    : "noch keine"}
+                </dd>
+              </>
+            )}
+            <dt className="text-muted-foreground">Letzte Synchronisierung</dt>
+            <dd>
+              {status.running ? "läuft…" : lastRun ? formatRun(lastRun) : "noch nie"}
+              {lastRun && !status.running && lastRun.status === "failed" && (
+                <span className="text-destructive"> – fehlgeschlagen</span>
+              )}
+            </dd>
+            {lastSuccess && lastSuccess.id !== lastRun?.id && (
+              <>
+                <dt className="text-muted-foreground">Letzte erfolgreiche</dt>
+                <dd>{formatRun(lastSuccess)}</dd>
+              </>
+            )}
+            {lastSuccess && (
+              <>
+                <dt className="text-muted-foreground">Änderungen</dt>
+                <dd>
+                  {lastSuccess.addedCount} neu, {lastSuccess.removedCount} entfernt
+                </dd>
+              </>
+            )}
+          </dl>
+        )}
+        {lastRun?.status === "failed" && lastRun.error && !error && (
+          <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{lastRun.error}</div>
+        )}
+        {isCSV && (
+          <p className="text-muted-foreground">
+            CSV mit den Spalten <span className="font-mono">id</span>, <span className="font-mono">first_name</span>,{" "}
+            <span className="font-mono">last_name</span> und optional <span className="font-mono">preferred_name</span>,{" "}
+            <span className="font-mono">groups</span> und <span className="font-mono">tags</span> (mehrere Werte mit{" "}
+            <span className="font-mono">|</span> getrennt). Die Datei ersetzt die bisherige Liste vollständig.
+          </p>
+        )}
+        <div className="flex flex-wrap gap-2">
+          {isCSV && (
+            <>
+              <input
+                ref={fileRef}
+                type="file"
+                accept=".csv,text/csv"
+                className="hidden"
+                onChange={(e) => {
+                  const f = e.target.files?.[0]
+                  if (f) void upload(f)
+                }}
+              />
+              <Button onClick={() => fileRef.current?.click()} disabled={uploading || status.running}>
+                {uploading ? (
+                  <Loader2 className="size-4 animate-spin" aria-hidden />
+                ) : (
+                  <Upload className="size-4" aria-hidden />
+                )}
+                CSV hochladen
+              </Button>
+            </>
+          )}
+          <Button variant="outline" onClick={sync} disabled={syncing || !status?.configured || status.running}>
+            {syncing ? (
+              <Loader2 className="size-4 animate-spin" aria-hidden />
+            ) : (
+              <RefreshCw className="size-4" aria-hidden />
+            )}
+            Jetzt synchronisieren
+          </Button>
+        </div>
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,414 @@
+"use client"
+
+import { CalendarX, Loader2, Plus, Trash2 } from "lucide-react"
+import { useCallback, useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Checkbox } from "@/components/ui/checkbox"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Switch } from "@/components/ui/switch"
+import { Textarea } from "@/components/ui/textarea"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import {
+  formatOpensAt,
+  type OpeningException,
+  type OpeningHours,
+  type OpeningWindow,
+  type SystemStatus,
+} from "@/lib/api/system"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+const WEEKDAYS: Array<{ iso: number; label: string }> = [
+  { iso: 1, label: "Mo" },
+  { iso: 2, label: "Di" },
+  { iso: 3, label: "Mi" },
+  { iso: 4, label: "Do" },
+  { iso: 5, label: "Fr" },
+  { iso: 6, label: "Sa" },
+  { iso: 7, label: "So" },
+]
+
+const ALL_DAYS = WEEKDAYS.map((d) => d.iso)
+
+// The backend reports midnight as "24:00", which time inputs cannot show; it
+// reads "00:00" as an end back as midnight.
+function toInput(clock: string): string {
+  return clock === "24:00" ? "00:00" : clock
+}
+
+function formatClock(iso: string): string {
+  return new Date(iso).toLocaleTimeString("de-CH", { hour: "2-digit", minute: "2-digit", timeZone: "Europe/Zurich" })
+}
+
+function describeStatus(s: SystemStatus): string {
+  if (s.enabled) {
+    if (s.source === "override" && s.openUntil) return `Geöffnet (manuell bis ${formatOpensAt(s.openUntil)})`
+    if (s.source === "schedule" && s.closesAt) return `Geöffnet bis ${formatClock(s.closesAt)} (Öffnungszeiten)`
+    return "Geöffnet"
+  }
+  if (s.source === "manual") return "Geschlossen (manuell ausgeschaltet)"
+  return s.opensAt ? `Geschlossen – öffnet ${formatOpensAt(s.opensAt)}` : "Geschlossen – keine Öffnung geplant"
+}
+
+// Opening hours in Europe/Zurich time that open and close the system on
+// their own. The main switch still wins: off always closes, and the system
+// can be opened by hand until a given time outside the opening hours.
+export function OpeningHoursCard() {
+  const fetchAuth = useAuthorizedFetch()
+  const [draft, setDraft] = useState<OpeningHours | null>(null)
+  const [status, setStatus] = useState<SystemStatus | null>(null)
+  const [openUntil, setOpenUntil] = useState("")
+  const [saving, setSaving] = useState(false)
+  const [saved, setSaved] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const loadStatus = useCallback(async () => {
+    const res = await fetchAuth(`/api/v1/system/status`)
+    if (res.ok) setStatus((await res.json()) as SystemStatus)
+  }, [fetchAuth])
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(`/api/v1/settings/opening-hours`)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setDraft((await res.json()) as OpeningHours)
+      await loadStatus()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth, loadStatus])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  function change(patch: Partial<OpeningHours>) {
+    setSaved(false)
+    setDraft((d) => (d ? { ...d, ...patch } : d))
+  }
+
+  function updateWindow(idx: number, patch: Partial<OpeningWindow>) {
+    if (!draft) return
+    change({ windows: draft.windows.map((w, i) => (i === idx ? { ...w, ...patch } : w)) })
+  }
+
+  function toggleDay(idx: number, iso: number) {
+    if (!draft) return
+    const days = draft.windows[idx]?.weekdays?.length ? draft.windows[idx]!.weekdays! : ALL_DAYS
+    const next = days.includes(iso) ? days.filter((d) => d !== iso) : [...days, iso].sort((a, b) => a - b)
+    updateWindow(idx, { weekdays: next })
+  }
+
+  function updateException(idx: number, patch: Partial<OpeningException>) {
+    if (!draft) return
+    change({ exceptions: draft.exceptions.map((e, i) => (i === idx ? { ...e, ...patch } : e)) })
+  }
+
+  async function save() {
+    if (!draft) return
+    setSaving(true)
+    setError(null)
+    try {
+      const res = await fetchAuth(`/api/v1/settings/opening-hours`, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json", "X-CSRF": getCSRFToken() || "" },
+        body: JSON.stringify(draft),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setDraft((await res.json()) as OpeningHours)
+      setSaved(true)
+      await loadStatus()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  async function override(until: string | null) {
+    setError(null)
+    try {
+      const res = await fetchAuth(`/api/v1/settings/open-until`, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json", "X-CSRF": getCSRFToken() || "" },
+        body: JSON.stringify({ until: until ? new Date(until).toISOString() : null }),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setStatus((await res.json()) as SystemStatus)
+      setOpenUntil("")
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    }
+  }
+
+  if (!draft) return null
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <div className="flex items-center justify-between gap-4">
+          <div>
+            <CardTitle>Öffnungszeiten</CardTitle>
+            <p className="text-muted-foreground text-sm">
+              Öffnet und schliesst das System automatisch (Zeit Europe/Zurich). Ausgeschaltet gilt nur der Schalter
+              oben.
+            </p>
+          </div>
+          <Switch checked={draft.enabled} onCheckedChange={(enabled) => change({ enabled })} />
+        </div>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-5 text-sm">
+        {status && (
+          <div className="flex flex-wrap items-end gap-3 rounded-xl border p-3">
+            <div className="grow">
+              <div className="text-muted-foreground">Aktueller Status</div>
+              <div className="font-medium">{describeStatus(status)}</div>
+            </div>
+            {status.source === "override" ? (
+              <Button variant="outline" onClick={() => override(null)}>
+                Übersteuerung beenden
+              </Button>
+            ) : (
+              <>
+                <div className="grid gap-1.5">
+                  <Label htmlFor="open-until">Manuell öffnen bis</Label>
+                  <Input
+                    id="open-until"
+                    type="datetime-local"
+                    className="w-52"
+                    value={openUntil}
+                    onChange={(e) => setOpenUntil(e.target.value)}
+                  />
+                </div>
+                <Button variant="outline" onClick={() => override(openUntil)} disabled={!openUntil}>
+                  Öffnen
+                </Button>
+              </>
+            )}
+          </div>
+        )}
+
+        <div className="flex flex-col gap-3">
+          <Label>Wöchentlich</Label>
+          {draft.windows.length === 0 && (
+            <p className="text-muted-foreground">Keine Zeitfenster – das System bleibt geschlossen.</p>
+          )}
+          {draft.windows.map((w, idx) => (
+            <div key={idx} className="flex flex-col gap-3 rounded-xl border p-3">
+              <div className="flex flex-wrap items-end gap-3">
+                <div className="grid gap-1.5">
+                  <Label htmlFor={`oh-label-${idx}`}>Bezeichnung</Label>
+                  <Input
+                    id={`oh-label-${idx}`}
+                    className="w-36"
+                    maxLength={50}
+                    placeholder="Festival"
+                    value={w.label ?? ""}
+                    onChange={(e) => updateWindow(idx, { label: e.target.value })}
+                  />
+                </div>
+                <div className="grid gap-1.5">
+                  <Label htmlFor={`oh-start-${idx}`}>Von</Label>
+                  <Input
+                    id={`oh-start-${idx}`}
+                    type="time"
+                    className="w-28"
+                    value={toInput(w.start)}
+                    onChange={(e) => updateWindow(idx, { start: e.target.value })}
+                  />
+                </div>
+                <div className="grid gap-1.5">
+                  <Label htmlFor={`oh-end-${idx}`}>Bis</Label>
+                  <Input
+                    id={`oh-end-${idx}`}
+                    type="time"
+                    className="w-28"
+                    value={toInput(w.end)}
+                    onChange={(e) => updateWindow(idx, { end: e.target.value })}
+                  />
+                </div>
+                <Button
+                  variant="ghost"
+                  size="icon"
+                  onClick={() => change({ windows: draft.windows.filter((_, i) => i !== idx) })}
+                  aria-label="Zeitfenster entfernen"
+                >
+                  <Trash2 className="size-4" aria-hidden />
+                </Button>
+              </div>
+              <div className="flex flex-wrap gap-1.5">
+                {WEEKDAYS.map((d) => {
+                  const on = (w.weekdays?.length ? w.weekdays : ALL_DAYS).includes(d.iso)
+                  return (
+                    <Button
+                      key={d.iso}
+                      type="button"
+                      size="sm"
+                      variant={on ? "default" : "outline"}
+                      onClick={() => toggleDay(idx, d.iso)}
+                      aria-pressed={on}
+                    >
+                      {d.label}
+                    </Button>
+                  )
+                })}
+              </div>
+            </div>
+          ))}
+          <div>
+            <Button
+              variant="outline"
+              onClick={() =>
+                change({ windows: [...draft.windows, { label: "", weekdays: ALL_DAYS, start: "11:00", end: "00:00" }] })
+              }
+            >
+              <Plus className="size-4" aria-hidden />
+              Zeitfenster
+            </Button>
+          </div>
+        </div>
+
+        <div className="flex flex-col gap-3">
+          <Label>Ausnahmen</Label>
+          <p className="text-muted-foreground">
+            Eine Ausnahme ersetzt die wöchentlichen Zeiten an diesem Datum. Vergangene Ausnahmen werden beim Speichern
+            entfernt.
+          </p>
+          {draft.exceptions.map((ex, idx) => {
+            const closed = ex.windows.length === 0
+            return (
+              <div key={idx} className="flex flex-col gap-3 rounded-xl border p-3">
+                <div className="flex flex-wrap items-end gap-3">
+                  <div className="grid gap-1.5">
+                    <Label htmlFor={`ex-date-${idx}`}>Datum</Label>
+                    <Input
+                      id={`ex-date-${idx}`}
+                      type="date"
+                      className="w-40"
+                      value={ex.date}
+                      onChange={(e) => updateException(idx, { date: e.target.value })}
+                    />
+                  </div>
+                  <div className="grid gap-1.5">
+                    <Label htmlFor={`ex-label-${idx}`}>Bezeichnung</Label>
+                    <Input
+                      id={`ex-label-${idx}`}
+                      className="w-44"
+                      maxLength={50}
+                      placeholder="Ruhetag"
+                      value={ex.label}
+                      onChange={(e) => updateException(idx, { label: e.target.value })}
+                    />
+                  </div>
+                  <label className="flex h-9 items-center gap-2">
+                    <Checkbox
+                      checked={closed}
+                      onCheckedChange={(v) =>
+                        updateException(idx, { windows: v === true ? [] : [{ start: "11:00", end: "00:00" }] })
+                      }
+                    />
+                    Ganzer Tag geschlossen
+                  </label>
+                  <Button
+                    variant="ghost"
+                    size="icon"
+                    onClick={() => change({ exceptions: draft.exceptions.filter((_, i) => i !== idx) })}
+                    aria-label="Ausnahme entfernen"
+                  >
+                    <Trash2 className="size-4" aria-hidden />
+                  </Button>
+                </div>
+                {ex.windows.map((w, wi) => (
+                  <div key={wi} className="flex flex-wrap items-end gap-3">
+                    <div className="grid gap-1.5">
+                      <Label htmlFor={`ex-start-${idx}-${wi}`}>Von</Label>
+                      <Input
+                        id={`ex-start-${idx}-${wi}`}
+                        type="time"
+                        className="w-28"
+                        value={toInput(w.start)}
+                        onChange={(e) =>
+                          updateException(idx, {
+                            windows: ex.windows.map((x, i) => (i === wi ? { ...x, start: e.target.value } : x)),
+                          })
+                        }
+                      />
+                    </div>
+                    <div className="grid gap-1.5">
+                      <Label htmlFor={`ex-end-${idx}-${wi}`}>Bis</Label>
+                      <Input
+                        id={`ex-end-${idx}-${wi}`}
+                        type="time"
+                        className="w-28"
+                        value={toInput(w.end)}
+                        onChange={(e) =>
+                          updateException(idx, {
+                            windows: ex.windows.map((x, i) => (i === wi ? { ...x, end: e.target.value } : x)),
+                          })
+                        }
+                      />
+                    </div>
+                    {ex.windows.length > 1 && (
+                      <Button
+                        variant="ghost"
+                        size="icon"
+                        onClick={() => updateException(idx, { windows: ex.windows.filter((_, i) => i !== wi) })}
+                        aria-label="Zeitfenster entfernen"
+                      >
+                        <Trash2 className="size-4" aria-hidden />
+                      </Button>
+                    )}
+                  </div>
+                ))}
+                {!closed && (
+                  <div>
+                    <Button
+                      variant="ghost"
+                      size="sm"
+                      onClick={() => updateException(idx, { windows: [...ex.windows, { start: "17:00", end: "00:00" }] })}
+                    >
+                      <Plus className="size-4" aria-hidden />
+                      Zeitfenster
+                    </Button>
+                  </div>
+                )}
+              </div>
+            )
+          })}
+          <div>
+            <Button
+              variant="outline"
+              onClick={() => change({ exceptions: [...draft.exceptions, { date: "", label: "", windows: [] }] })}
+            >
+              <CalendarX className="size-4" aria-hidden />
+              Ausnahme
+            </Button>
+          </div>
+        </div>
+
+        <div className="grid gap-2">
+          <Label htmlFor="closed-message">Hinweis für Gäste, solange geschlossen</Label>
+          <Textarea
+            id="closed-message"
+            maxLength={500}
+            placeholder="Das Bestellsystem ist momentan nicht verfügbar."
+            value={draft.closedMessage}
+            onChange={(e) => change({ closedMessage: e.target.value })}
+          />
+        </div>
+
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+
+        <div className="flex flex-wrap items-center gap-2">
+          <Button onClick={save} disabled={saving}>
+            {saving && <Loader2 className="size-4 animate-spin" aria-hidden />}
+            Speichern
+          </Button>
+          {saved && <span className="text-muted-foreground">Gespeichert.</span>}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
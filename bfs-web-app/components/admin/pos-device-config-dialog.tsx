@@ -0,0 +1,200 @@
+"use client"
+
+import { useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Checkbox } from "@/components/ui/checkbox"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { Label } from "@/components/ui/label"
+import { Switch } from "@/components/ui/switch"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { Category } from "@/types/category"
+import type {
+  PosDeviceConfig,
+  PosDeviceOverrides,
+  PosFulfillmentMode,
+  PosGratisType,
+  PosPaymentMethod,
+} from "@/types/jeton"
+
+const MODES: { value: PosFulfillmentMode; label: string }[] = [
+  { value: "QR_CODE", label: "QR-Code" },
+  { value: "JETON", label: "Jetons" },
+  { value: "HYBRID", label: "Hybrid" },
+]
+
+const PAYMENT_METHODS: { value: PosPaymentMethod; label: string }[] = [
+  { value: "cash", label: "Bar" },
+  { value: "card", label: "Karte" },
+  { value: "twint", label: "TWINT" },
+]
+
+const GRATIS_TYPES: { value: PosGratisType; label: string }[] = [
+  { value: "guest", label: "Gast" },
+  { value: "vip", label: "VIP" },
+  { value: "staff", label: "Mitarbeiter" },
+  { value: "100club", label: "100 Club" },
+]
+
+type Props = {
+  device: { id: string; name: string; config?: PosDeviceConfig } | null
+  onOpenChange: (open: boolean) => void
+  onSaved: () => void
+}
+
+// Edits a POS device's overrides. Every section either inherits the global
+// settings or restricts the device to the selected values.
+export function PosDeviceConfigDialog({ device, onOpenChange, onSaved }: Props) {
+  const fetchAuth = useAuthorizedFetch()
+  const [categories, setCategories] = useState<Category[]>([])
+  const [overrides, setOverrides] = useState<PosDeviceOverrides>({})
+  const [saving, setSaving] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    if (!device) return
+    setOverrides(device.config?.overrides ?? {})
+    setError(null)
+    ;(async () => {
+      const res = await fetchAuth(`/api/v1/categories`)
+      if (!res.ok) return
+      setCategories(((await res.json()) as { items: Category[] }).items ?? [])
+    })()
+  }, [device, fetchAuth])
+
+  async function save() {
+    if (!device) return
+    setSaving(true)
+    setError(null)
+    try {
+      const csrf = getCSRFToken()
+      const res = await fetchAuth(`/api/v1/pos/devices/${device.id}/config`, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+        body: JSON.stringify(overrides),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      onSaved()
+      onOpenChange(false)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Dialog open={device !== null} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Konfiguration: {device?.name}</DialogTitle>
+        </DialogHeader>
+
+        {error && (
+          <div role="alert" className="text-destructive bg-destructive/10 rounded px-3 py-2 text-sm">
+            {error}
+          </div>
+        )}
+
+        <div className="space-y-5">
+          <div className="space-y-2">
+            <Label>POS Modus</Label>
+            <div className="grid grid-cols-2 gap-2 md:grid-cols-4">
+              <Button
+                variant={overrides.posMode == null ? "default" : "outline"}
+                className="rounded-xl"
+                onClick={() => setOverrides({ ...overrides, posMode: null })}
+              >
+                Global
+              </Button>
+              {MODES.map((m) => (
+                <Button
+                  key={m.value}
+                  variant={overrides.posMode === m.value ? "default" : "outline"}
+                  className="rounded-xl"
+                  onClick={() => setOverrides({ ...overrides, posMode: m.value })}
+                >
+                  {m.label}
+                </Button>
+              ))}
+            </div>
+          </div>
+
+          <OverrideSection
+            label="Zahlungsarten"
+            options={PAYMENT_METHODS}
+            value={overrides.paymentMethods}
+            onChange={(paymentMethods) => setOverrides({ ...overrides, paymentMethods })}
+          />
+
+          <OverrideSection
+            label="Gratis-Arten"
+            options={GRATIS_TYPES}
+            value={overrides.gratisTypes}
+            onChange={(gratisTypes) => setOverrides({ ...overrides, gratisTypes })}
+          />
+
+          <OverrideSection
+            label="Sichtbare Kategorien"
+            options={categories.map((c) => ({ value: c.id, label: c.name }))}
+            value={overrides.categoryIds}
+            onChange={(categoryIds) => setOverrides({ ...overrides, categoryIds })}
+          />
+        </div>
+
+        <div className="flex justify-end gap-2">
+          <Button variant="outline" onClick={() => onOpenChange(false)} disabled={saving}>
+            Abbrechen
+          </Button>
+          <Button onClick={save} disabled={saving}>
+            Speichern
+          </Button>
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
+
+function OverrideSection<T extends string>({
+  label,
+  options,
+  value,
+  onChange,
+}: {
+  label: string
+  options: { value: T; label: string }[]
+  value: T[] | null | undefined
+  onChange: (value: T[] | null) => void
+}) {
+  const inherit = value == null
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center justify-between">
+        <Label>{label}</Label>
+        <label className="text-muted-foreground flex items-center gap-2 text-sm">
+          Global übernehmen
+          <Switch
+            checked={inherit}
+            onCheckedChange={(checked) => onChange(checked ? null : options.map((o) => o.value))}
+          />
+        </label>
+      </div>
+      {!inherit && (
+        <div className="max-h-48 space-y-1 overflow-y-auto rounded-xl border p-2">
+          {options.map((o) => (
+            <label key={o.value} className="hover:bg-muted flex cursor-pointer items-center gap-2 rounded-lg p-2">
+              <Checkbox
+                checked={value.includes(o.value)}
+                onCheckedChange={(checked) =>
+                  onChange(checked ? [...value, o.value] : value.filter((v) => v !== o.value))
+                }
+              />
+              <span>{o.label}</span>
+            </label>
+          ))}
+        </div>
+      )}
+    </div>
+  )
+}
@@ -0,0 +1,315 @@
+"use client"
+
+import { Minus, Plus, Trash2 } from "lucide-react"
+import { useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Checkbox } from "@/components/ui/checkbox"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { Switch } from "@/components/ui/switch"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { ProductVariant } from "@/types"
+import type { Jeton } from "@/types/jeton"
+
+const PRODUCT_JETON_VALUE = "__product__"
+
+type Station = { id: string; name: string }
+
+type Props = {
+  product: { id: string; name: string; variants?: ProductVariant[] } | null
+  jetons: Jeton[]
+  onOpenChange: (open: boolean) => void
+  onChanged: (variants: ProductVariant[]) => void
+}
+
+// Edits the variants of a product, e.g. 3dl and 5dl of a drink. Each variant
+// has its own price and stock; jeton and stations fall back to the product's.
+export function ProductVariantsDialog({ product, jetons, onOpenChange, onChanged }: Props) {
+  const fetchAuth = useAuthorizedFetch()
+  const [variants, setVariants] = useState<ProductVariant[]>([])
+  const [stations, setStations] = useState<Station[]>([])
+  const [newName, setNewName] = useState("")
+  const [newPrice, setNewPrice] = useState("")
+  const [busy, setBusy] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    if (!product) return
+    setVariants(product.variants ?? [])
+    setNewName("")
+    setNewPrice("")
+    setError(null)
+    ;(async () => {
+      const res = await fetchAuth(`/api/v1/stations`)
+      if (!res.ok) return
+      setStations(((await res.json()) as { items: Station[] }).items ?? [])
+    })()
+  }, [product, fetchAuth])
+
+  function commit(next: ProductVariant[]) {
+    setVariants(next)
+    onChanged(next)
+  }
+
+  async function request(path: string, method: string, body?: unknown): Promise<Response> {
+    const csrf = getCSRFToken()
+    const res = await fetchAuth(`/api/v1/products/${product!.id}/variants${path}`, {
+      method,
+      headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+    if (!res.ok) {
+      const msg = await readErrorMessage(res)
+      if (msg === "variant_in_use") throw new Error("Variante wurde bereits verkauft. Bitte deaktivieren.")
+      throw new Error(msg)
+    }
+    return res
+  }
+
+  async function run(action: () => Promise<void>) {
+    setBusy(true)
+    setError(null)
+    try {
+      await action()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  function create() {
+    const priceCents = parsePrice(newPrice)
+    if (!newName.trim() || priceCents == null || priceCents < 0) {
+      setError("Bitte Name und Preis angeben.")
+      return
+    }
+    run(async () => {
+      const res = await request("", "POST", {
+        name: newName.trim(),
+        priceCents,
+        position: variants.length,
+      })
+      const created = (await res.json()) as ProductVariant
+      commit([...variants, { ...created, stock: 0 }])
+      setNewName("")
+      setNewPrice("")
+    })
+  }
+
+  function update(variant: ProductVariant, patch: Partial<ProductVariant>) {
+    run(async () => {
+      const res = await request(`/${variant.id}`, "PATCH", {
+        name: patch.name,
+        priceCents: patch.priceCents,
+        jetonId: patch.jetonId === undefined ? undefined : (patch.jetonId ?? ""),
+        isActive: patch.isActive,
+        stationIds: patch.stationIds,
+      })
+      const updated = (await res.json()) as ProductVariant
+      commit(variants.map((v) => (v.id === variant.id ? { ...updated, stock: v.stock } : v)))
+    })
+  }
+
+  function adjustStock(variant: ProductVariant, delta: number) {
+    run(async () => {
+      const res = await request(`/${variant.id}/inventory`, "PATCH", { delta, reason: "manual_adjust" })
+      const { quantity } = (await res.json()) as { quantity: number }
+      commit(variants.map((v) => (v.id === variant.id ? { ...v, stock: quantity } : v)))
+    })
+  }
+
+  function remove(variant: ProductVariant) {
+    run(async () => {
+      await request(`/${variant.id}`, "DELETE")
+      commit(variants.filter((v) => v.id !== variant.id))
+    })
+  }
+
+  return (
+    <Dialog open={product !== null} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Varianten: {product?.name}</DialogTitle>
+        </DialogHeader>
+
+        {error && (
+          <div role="alert" className="text-destructive bg-destructive/10 rounded px-3 py-2 text-sm">
+            {error}
+          </div>
+        )}
+
+        <div className="space-y-3">
+          {variants.length === 0 && <p className="text-muted-foreground text-sm">Noch keine Varianten.</p>}
+          {variants.map((v) => (
+            <VariantRow
+              key={v.id}
+              variant={v}
+              jetons={jetons}
+              stations={stations}
+              disabled={busy}
+              onUpdate={(patch) => update(v, patch)}
+              onAdjustStock={(delta) => adjustStock(v, delta)}
+              onDelete={() => remove(v)}
+            />
+          ))}
+        </div>
+
+        <div className="space-y-2 border-t pt-3">
+          <Label>Neue Variante</Label>
+          <div className="flex gap-2">
+            <Input value={newName} placeholder="z.B. 5dl" onChange={(e) => setNewName(e.target.value)} />
+            <Input
+              value={newPrice}
+              inputMode="decimal"
+              placeholder="Preis (CHF)"
+              className="w-32"
+              onChange={(e) => setNewPrice(e.target.value)}
+            />
+            <Button onClick={create} disabled={busy}>
+              Hinzufügen
+            </Button>
+          </div>
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
+
+function VariantRow({
+  variant,
+  jetons,
+  stations,
+  disabled,
+  onUpdate,
+  onAdjustStock,
+  onDelete,
+}: {
+  variant: ProductVariant
+  jetons: Jeton[]
+  stations: Station[]
+  disabled: boolean
+  onUpdate: (patch: Partial<ProductVariant>) => void
+  onAdjustStock: (delta: number) => void
+  onDelete: () => void
+}) {
+  const [name, setName] = useState(variant.name)
+  const [price, setPrice] = useState((variant.priceCents / 100).toFixed(2))
+  const stationIds = variant.stationIds ?? []
+
+  useEffect(() => {
+    setName(variant.name)
+    setPrice((variant.priceCents / 100).toFixed(2))
+  }, [variant])
+
+  return (
+    <div className="space-y-3 rounded-xl border p-3">
+      <div className="flex items-center gap-2">
+        <Input
+          value={name}
+          aria-label="Name"
+          onChange={(e) => setName(e.target.value)}
+          onBlur={() => {
+            const trimmed = name.trim()
+            if (trimmed && trimmed !== variant.name) onUpdate({ name: trimmed })
+          }}
+        />
+        <Input
+          value={price}
+          inputMode="decimal"
+          aria-label="Preis"
+          className="w-28"
+          onChange={(e) => setPrice(e.target.value)}
+          onBlur={() => {
+            const cents = parsePrice(price)
+            if (cents != null && cents >= 0 && cents !== variant.priceCents) onUpdate({ priceCents: cents })
+          }}
+        />
+        <Switch
+          checked={variant.isActive}
+          disabled={disabled}
+          aria-label="Aktiv"
+          onCheckedChange={(checked) => onUpdate({ isActive: checked })}
+        />
+        <Button variant="ghost" size="icon" disabled={disabled} onClick={onDelete} aria-label="Variante löschen">
+          <Trash2 className="text-destructive h-4 w-4" />
+        </Button>
+      </div>
+
+      <div className="flex items-center justify-between gap-2">
+        <Select
+          value={variant.jetonId || PRODUCT_JETON_VALUE}
+          onValueChange={(value) => onUpdate({ jetonId: value === PRODUCT_JETON_VALUE ? null : value })}
+          disabled={disabled}
+        >
+          <SelectTrigger className="h-9 w-48" aria-label="Jeton">
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            <SelectItem value={PRODUCT_JETON_VALUE}>Jeton des Produkts</SelectItem>
+            {jetons.map((j) => (
+              <SelectItem key={j.id} value={j.id}>
+                {j.name}
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+        <div className="flex items-center gap-1">
+          <Button
+            variant="outline"
+            size="icon"
+            disabled={disabled || (variant.stock ?? 0) <= 0}
+            onClick={() => onAdjustStock(-1)}
+            aria-label="Bestand verringern"
+          >
+            <Minus className="h-4 w-4" />
+          </Button>
+          <span className="min-w-10 text-center text-sm tabular-nums">{variant.stock ?? 0}</span>
+          <Button
+            variant="outline"
+            size="icon"
+            disabled={disabled}
+            onClick={() => onAdjustStock(1)}
+            aria-label="Bestand erhöhen"
+          >
+            <Plus className="h-4 w-4" />
+          </Button>
+        </div>
+      </div>
+
+      {stations.length > 0 && (
+        <div className="space-y-1">
+          <Label className="text-muted-foreground text-xs">Stationen (leer = wie Produkt)</Label>
+          <div className="flex flex-wrap gap-3">
+            {stations.map((s) => (
+              <label key={s.id} className="flex cursor-pointer items-center gap-2 text-sm">
+                <Checkbox
+                  checked={stationIds.includes(s.id)}
+                  disabled={disabled}
+                  onCheckedChange={(checked) =>
+                    onUpdate({
+                      stationIds: checked ? [...stationIds, s.id] : stationIds.filter((id) => id !== s.id),
+                    })
+                  }
+                />
+                {s.name}
+              </label>
+            ))}
+          </div>
+        </div>
+      )}
+    </div>
+  )
+}
+
+function parsePrice(input: string): number | null {
+  const norm = input.replace(",", ".").trim()
+  if (!norm) return null
+  const val = Number(norm)
+  if (!isFinite(val) || Number.isNaN(val)) return null
+  return Math.round(val * 100)
+}
@@ -0,0 +1,170 @@
+"use client"
+
+import { History, Loader2 } from "lucide-react"
+import { useCallback, useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+
+type Jeton = { id: string; name: string; color: string }
+
+type SettingsChange = { field: string; from: unknown; to: unknown }
+
+type SettingsVersion = {
+  version: number
+  change: string
+  createdBy: string | null
+  createdByName: string | null
+  rolledBackFrom?: number
+  createdAt: string
+  changes: SettingsChange[]
+}
+
+const PAGE_SIZE = 20
+
+const FIELD_LABELS: Record<string, string> = {
+  posMode: "POS Modus",
+  systemEnabled: "Food System",
+  acceptLegacyQr: "Alte QR-Codes",
+  club100MaxRedemptions: "100 Club Max. Einlösungen",
+  club100FreeProductIds: "100 Club Gratis-Produkte",
+  jeton: "Jeton",
+}
+
+function formatValue(field: string, value: unknown): string {
+  if (value === null || value === undefined) return "–"
+  if (typeof value === "boolean") return value ? "an" : "aus"
+  if (field === "club100FreeProductIds" && Array.isArray(value)) return `${value.length} Produkte`
+  if (field === "jeton") {
+    const j = value as Jeton
+    return `${j.name} (${j.color})`
+  }
+  return String(value)
+}
+
+function describe(c: SettingsChange): string {
+  return `${FIELD_LABELS[c.field] ?? c.field}: ${formatValue(c.field, c.from)} → ${formatValue(c.field, c.to)}`
+}
+
+// Every change to the settings and jetons is kept as a version; any version
+// can be restored, which adds a new one.
+export function SettingsHistoryCard({ onRollback }: { onRollback?: () => void }) {
+  const fetchAuth = useAuthorizedFetch()
+  const [items, setItems] = useState<SettingsVersion[]>([])
+  const [hasMore, setHasMore] = useState(false)
+  const [busy, setBusy] = useState<number | null>(null)
+  const [error, setError] = useState<string | null>(null)
+
+  const load = useCallback(
+    async (before?: number) => {
+      try {
+        const qs = new URLSearchParams({ limit: String(PAGE_SIZE) })
+        if (before) qs.set("before", String(before))
+        const res = await fetchAuth(`/api/v1/settings/history?${qs.toString()}`)
+        if (!res.ok) throw new Error(await readErrorMessage(res))
+        const page = ((await res.json()) as { items: SettingsVersion[] }).items
+        setItems((prev) => (before ? [...prev, ...page] : page))
+        setHasMore(page.length === PAGE_SIZE)
+      } catch (e: unknown) {
+        setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+      }
+    },
+    [fetchAuth],
+  )
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  async function rollback(version: number) {
+    if (!window.confirm(`Einstellungen auf Version ${version} zurücksetzen?`)) return
+    setBusy(version)
+    setError(null)
+    try {
+      const res = await fetchAuth(`/api/v1/settings/history/${version}/rollback`, {
+        method: "POST",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      await load()
+      onRollback?.()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Zurücksetzen fehlgeschlagen")
+    } finally {
+      setBusy(null)
+    }
+  }
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <History className="size-5" /> Änderungsverlauf
+        </CardTitle>
+        <p className="text-muted-foreground text-sm">
+          Wer hat wann POS Modus, System, 100 Club oder Jetons geändert. Jede Version kann wiederhergestellt werden.
+        </p>
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {error && <div className="text-destructive text-sm">{error}</div>}
+        {items.length === 0 ? (
+          <p className="text-muted-foreground text-sm">Noch keine Änderungen erfasst.</p>
+        ) : (
+          <div className="rounded-xl border">
+            <Table>
+              <TableHeader>
+                <TableRow>
+                  <TableHead>Version</TableHead>
+                  <TableHead>Zeitpunkt</TableHead>
+                  <TableHead>Von</TableHead>
+                  <TableHead>Änderungen</TableHead>
+                  <TableHead />
+                </TableRow>
+              </TableHeader>
+              <TableBody>
+                {items.map((v, i) => (
+                  <TableRow key={v.version}>
+                    <TableCell>{v.version}</TableCell>
+                    <TableCell className="whitespace-nowrap">
+                      {new Date(v.createdAt).toLocaleString("de-CH", { dateStyle: "short", timeStyle: "short" })}
+                    </TableCell>
+                    <TableCell>{v.createdByName ?? (v.change === "baseline" ? "Ausgangsstand" : "–")}</TableCell>
+                    <TableCell className="text-sm">
+                      {v.rolledBackFrom && (
+                        <div className="font-medium">Zurückgesetzt auf Version {v.rolledBackFrom}</div>
+                      )}
+                      {v.changes.map((c, j) => (
+                        <div key={j}>{describe(c)}</div>
+                      ))}
+                    </TableCell>
+                    <TableCell className="text-right">
+                      {i > 0 && (
+                        <Button
+                          variant="outline"
+                          size="sm"
+                          disabled={busy !== null}
+                          onClick={() => void rollback(v.version)}
+                        >
+                          {busy === v.version && <Loader2 className="size-4 animate-spin" />}
+                          Wiederherstellen
+                        </Button>
+                      )}
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </div>
+        )}
+        {hasMore && (
+          <Button variant="ghost" size="sm" onClick={() => void load(items[items.length - 1]?.version)}>
+            Ältere laden
+          </Button>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
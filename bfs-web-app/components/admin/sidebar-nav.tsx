@@ -1,5 +1,6 @@
 "use client"
 import {
+  CalendarDays,
   Circle,
   ClipboardList,
   CreditCard,
@@ -8,6 +9,7 @@ import {
   Home,
   MailPlus,
   MonitorCheck,
+  Percent,
   ReceiptText,
   Settings,
   Smartphone,
@@ -35,6 +37,8 @@ export function AdminSidebar({ badges, version }: { badges?: Partial<Record<stri
     { href: "/admin/products", label: "Produkte", icon: <Hamburger className="size-5" /> },
     { href: "/admin/menu", label: "Menus", icon: <UtensilsCrossed className="size-5" /> },
     { href: "/admin/categories", label: "Kategorien", icon: <Grid2x2 className="size-5" /> },
+    { href: "/admin/events", label: "Anlässe", icon: <CalendarDays className="size-5" /> },
+    { href: "/admin/price-rules", label: "Preisregeln", icon: <Percent className="size-5" /> },
     { href: "/admin/orders", label: "Bestellungen", icon: <ReceiptText className="size-5" />, badge: badges?.orders },
     { href: "/admin/inventory", label: "Inventar", icon: <ClipboardList className="size-5" /> },
     { href: "/admin/devices", label: "Geräte", icon: <Smartphone className="size-5" /> },
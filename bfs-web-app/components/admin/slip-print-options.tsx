@@ -0,0 +1,106 @@
+"use client"
+
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import type { Locale } from "@/types"
+
+export type SlipFormat = "a4" | "62mm" | "102mm"
+
+export type SlipPrintOptions = { format: SlipFormat; columns: number | ""; rows: number | ""; lang: Locale }
+
+export const DEFAULT_SLIP_PRINT_OPTIONS: SlipPrintOptions = { format: "a4", columns: "", rows: "", lang: "de" }
+
+const FORMAT_LABEL: Record<SlipFormat, string> = {
+  a4: "A4-Bogen",
+  "62mm": "Etikettenrolle 62 mm",
+  "102mm": "Etikettenrolle 102 mm",
+}
+
+const LANG_LABEL: Record<Locale, string> = { de: "Deutsch", fr: "Französisch", en: "Englisch" }
+
+// Query parameters understood by every slip print endpoint. Empty fields keep
+// the format's defaults (A4: 5 columns, as many rows as fit). The printed
+// instructions are German unless another language is picked.
+export function slipLayoutParams(opts: SlipPrintOptions): URLSearchParams {
+  const params = new URLSearchParams({ format: opts.format })
+  if (opts.columns !== "") params.set("columns", String(opts.columns))
+  if (opts.rows !== "") params.set("rows", String(opts.rows))
+  if (opts.lang !== "de") params.set("lang", opts.lang)
+  return params
+}
+
+interface SlipPrintOptionsFieldsProps {
+  value: SlipPrintOptions
+  onChange: (next: SlipPrintOptions) => void
+  idPrefix?: string
+}
+
+export function SlipPrintOptionsFields({ value, onChange, idPrefix = "slip" }: SlipPrintOptionsFieldsProps) {
+  function numberField(raw: string): number | "" {
+    return raw === "" ? "" : parseInt(raw, 10)
+  }
+
+  return (
+    <div className="flex flex-wrap items-end gap-3">
+      <div className="grid gap-1.5">
+        <Label htmlFor={`${idPrefix}-format`}>Format</Label>
+        <Select value={value.format} onValueChange={(v) => onChange({ ...value, format: v as SlipFormat })}>
+          <SelectTrigger id={`${idPrefix}-format`} className="h-9 w-52">
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            {(Object.keys(FORMAT_LABEL) as SlipFormat[]).map((f) => (
+              <SelectItem key={f} value={f}>
+                {FORMAT_LABEL[f]}
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+      <div className="grid gap-1.5">
+        <Label htmlFor={`${idPrefix}-columns`}>Spalten</Label>
+        <Input
+          id={`${idPrefix}-columns`}
+          type="number"
+          min={1}
+          max={8}
+          placeholder="Auto"
+          className="w-20"
+          value={value.columns}
+          onChange={(e) => onChange({ ...value, columns: numberField(e.target.value) })}
+        />
+      </div>
+      {value.format === "a4" && (
+        <div className="grid gap-1.5">
+          <Label htmlFor={`${idPrefix}-rows`}>Zeilen</Label>
+          <Input
+            id={`${idPrefix}-rows`}
+            type="number"
+            min={1}
+            max={20}
+            placeholder="Auto"
+            className="w-20"
+            value={value.rows}
+            onChange={(e) => onChange({ ...value, rows: numberField(e.target.value) })}
+          />
+        </div>
+      )}
+      <div className="grid gap-1.5">
+        <Label htmlFor={`${idPrefix}-lang`}>Sprache</Label>
+        <Select value={value.lang} onValueChange={(v) => onChange({ ...value, lang: v as Locale })}>
+          <SelectTrigger id={`${idPrefix}-lang`} className="h-9 w-36">
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            {(Object.keys(LANG_LABEL) as Locale[]).map((l) => (
+              <SelectItem key={l} value={l}>
+                {LANG_LABEL[l]}
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1,140 @@
+"use client"
+
+import { useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Textarea } from "@/components/ui/textarea"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { Translations } from "@/types"
+
+// path is the PATCH endpoint of the product, category or menu slot, e.g.
+// /api/v1/categories/{id}. Only products have a description to translate.
+export type TranslationsTarget = {
+  path: string
+  name: string
+  withDescription: boolean
+  translations?: Translations
+}
+
+type Props = {
+  target: TranslationsTarget | null
+  onOpenChange: (open: boolean) => void
+  onSaved: (translations: Translations) => void
+}
+
+const LANGUAGES = [
+  { key: "fr", label: "Französisch" },
+  { key: "en", label: "Englisch" },
+] as const
+
+type Lang = (typeof LANGUAGES)[number]["key"]
+type Texts = Record<Lang, { name: string; description: string }>
+
+function toTexts(t?: Translations): Texts {
+  return {
+    fr: { name: t?.fr?.name ?? "", description: t?.fr?.description ?? "" },
+    en: { name: t?.en?.name ?? "", description: t?.en?.description ?? "" },
+  }
+}
+
+// Edits the French and English name (and description) shown to customers
+// who order in those languages. Empty fields fall back to German.
+export function TranslationsDialog({ target, onOpenChange, onSaved }: Props) {
+  const fetchAuth = useAuthorizedFetch()
+  const [texts, setTexts] = useState<Texts>(toTexts())
+  const [busy, setBusy] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  useEffect(() => {
+    if (!target) return
+    setTexts(toTexts(target.translations))
+    setError(null)
+  }, [target])
+
+  function change(lang: Lang, patch: Partial<Texts[Lang]>) {
+    setTexts((prev) => ({ ...prev, [lang]: { ...prev[lang], ...patch } }))
+  }
+
+  async function save() {
+    if (!target) return
+    setBusy(true)
+    setError(null)
+    try {
+      const translations: Translations = {}
+      for (const { key } of LANGUAGES) {
+        translations[key] = {
+          name: texts[key].name.trim(),
+          description: target.withDescription ? texts[key].description.trim() || null : null,
+        }
+      }
+      const csrf = getCSRFToken()
+      const res = await fetchAuth(target.path, {
+        method: "PATCH",
+        headers: { "Content-Type": "application/json", "X-CSRF": csrf || "" },
+        body: JSON.stringify({ translations }),
+      })
+      if (!res.ok) {
+        setError(await readErrorMessage(res))
+        return
+      }
+      const saved = (await res.json()) as { translations?: Translations }
+      onSaved(saved.translations ?? {})
+      onOpenChange(false)
+    } finally {
+      setBusy(false)
+    }
+  }
+
+  return (
+    <Dialog open={target !== null} onOpenChange={onOpenChange}>
+      <DialogContent className="max-h-[90vh] overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Übersetzungen: {target?.name}</DialogTitle>
+        </DialogHeader>
+        <p className="text-muted-foreground text-sm">Leere Felder werden auf Deutsch angezeigt.</p>
+
+        {error && (
+          <div role="alert" className="text-destructive bg-destructive/10 rounded px-3 py-2 text-sm">
+            {error}
+          </div>
+        )}
+
+        {LANGUAGES.map(({ key, label }) => (
+          <div key={key} className="space-y-2">
+            <p className="text-sm font-medium">{label}</p>
+            <div className="space-y-1">
+              <Label htmlFor={`translation-${key}-name`}>Name</Label>
+              <Input
+                id={`translation-${key}-name`}
+                value={texts[key].name}
+                onChange={(e) => change(key, { name: e.target.value })}
+                maxLength={20}
+              />
+            </div>
+            {target?.withDescription && (
+              <div className="space-y-1">
+                <Label htmlFor={`translation-${key}-description`}>Beschreibung</Label>
+                <Textarea
+                  id={`translation-${key}-description`}
+                  value={texts[key].description}
+                  onChange={(e) => change(key, { description: e.target.value })}
+                  rows={2}
+                />
+              </div>
+            )}
+          </div>
+        ))}
+
+        <div className="flex border-t pt-3">
+          <Button size="sm" className="ml-auto" onClick={() => void save()} disabled={busy}>
+            Speichern
+          </Button>
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
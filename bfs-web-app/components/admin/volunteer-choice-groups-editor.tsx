@@ -0,0 +1,112 @@
+"use client"
+
+import { Plus, Trash2 } from "lucide-react"
+import { Button } from "@/components/ui/button"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import type { ProductSummaryDTO } from "@/types/product"
+import type { VolunteerChoiceGroupInput } from "@/types/volunteer"
+
+export type ChoiceGroupDraft = { name: string; quantity: number | ""; productIds: string[] }
+
+export function choiceGroupsPayload(drafts: ChoiceGroupDraft[]): VolunteerChoiceGroupInput[] {
+  return drafts.map((g) => ({
+    name: g.name.trim(),
+    quantity: Math.max(1, g.quantity === "" ? 1 : g.quantity),
+    productIds: g.productIds,
+  }))
+}
+
+interface VolunteerChoiceGroupsEditorProps {
+  value: ChoiceGroupDraft[]
+  onChange: (next: ChoiceGroupDraft[]) => void
+  products: ProductSummaryDTO[]
+  idPrefix?: string
+}
+
+// Editor for "one main from these 3, one drink from these 5". Used in the
+// create dialog and on the campaign detail page.
+export function VolunteerChoiceGroupsEditor({
+  value,
+  onChange,
+  products,
+  idPrefix = "choice",
+}: VolunteerChoiceGroupsEditorProps) {
+  function update(idx: number, patch: Partial<ChoiceGroupDraft>) {
+    onChange(value.map((g, i) => (i === idx ? { ...g, ...patch } : g)))
+  }
+
+  function toggleProduct(idx: number, productId: string) {
+    const ids = value[idx]?.productIds ?? []
+    update(idx, { productIds: ids.includes(productId) ? ids.filter((p) => p !== productId) : [...ids, productId] })
+  }
+
+  return (
+    <div className="flex flex-col gap-3">
+      {value.map((g, idx) => (
+        <div key={idx} className="flex flex-col gap-3 rounded-xl border p-3">
+          <div className="flex flex-wrap items-end gap-3">
+            <div className="grid flex-1 gap-1.5">
+              <Label htmlFor={`${idPrefix}-name-${idx}`}>Gruppe</Label>
+              <Input
+                id={`${idPrefix}-name-${idx}`}
+                maxLength={50}
+                placeholder="Hauptgang"
+                value={g.name}
+                onChange={(e) => update(idx, { name: e.target.value })}
+              />
+            </div>
+            <div className="grid gap-1.5">
+              <Label htmlFor={`${idPrefix}-qty-${idx}`}>Menge</Label>
+              <Input
+                id={`${idPrefix}-qty-${idx}`}
+                type="number"
+                min={1}
+                className="w-20"
+                value={g.quantity}
+                onChange={(e) => {
+                  const raw = e.target.value
+                  update(idx, { quantity: raw === "" ? "" : parseInt(raw, 10) })
+                }}
+              />
+            </div>
+            <Button
+              variant="ghost"
+              size="icon"
+              onClick={() => onChange(value.filter((_, i) => i !== idx))}
+              aria-label="Gruppe entfernen"
+            >
+              <Trash2 className="size-4" aria-hidden />
+            </Button>
+          </div>
+          <div className="flex flex-wrap gap-1.5">
+            {products.map((p) => {
+              const on = g.productIds.includes(p.id)
+              return (
+                <Button
+                  key={p.id}
+                  type="button"
+                  size="sm"
+                  variant={on ? "default" : "outline"}
+                  onClick={() => toggleProduct(idx, p.id)}
+                  aria-pressed={on}
+                >
+                  {p.name}
+                </Button>
+              )
+            })}
+          </div>
+        </div>
+      ))}
+      <Button
+        variant="outline"
+        size="sm"
+        onClick={() => onChange([...value, { name: "", quantity: 1, productIds: [] }])}
+        disabled={products.length === 0}
+      >
+        <Plus className="size-4" aria-hidden />
+        Auswahlgruppe hinzufügen
+      </Button>
+    </div>
+  )
+}
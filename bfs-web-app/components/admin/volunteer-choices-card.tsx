@@ -0,0 +1,119 @@
+"use client"
+
+import { Loader2 } from "lucide-react"
+import { useCallback, useEffect, useState } from "react"
+import {
+  type ChoiceGroupDraft,
+  choiceGroupsPayload,
+  VolunteerChoiceGroupsEditor,
+} from "@/components/admin/volunteer-choice-groups-editor"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { ProductSummaryDTO } from "@/types/product"
+import type { VolunteerChoiceGroup } from "@/types/volunteer"
+
+interface VolunteerChoicesCardProps {
+  campaignId: string
+}
+
+function toDrafts(groups: VolunteerChoiceGroup[]): ChoiceGroupDraft[] {
+  return groups.map((g) => ({ name: g.name, quantity: g.quantity, productIds: g.options.map((o) => o.productId) }))
+}
+
+// Choice groups picked at the station, in addition to the fixed products.
+export function VolunteerChoicesCard({ campaignId }: VolunteerChoicesCardProps) {
+  const fetchAuth = useAuthorizedFetch()
+  const [drafts, setDrafts] = useState<ChoiceGroupDraft[] | null>(null)
+  const [products, setProducts] = useState<ProductSummaryDTO[]>([])
+  const [saving, setSaving] = useState(false)
+  const [saved, setSaved] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const url = `/api/v1/staff-meals/${encodeURIComponent(campaignId)}/choices`
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(url)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const j = (await res.json()) as { items: VolunteerChoiceGroup[] }
+      setDrafts(toDrafts(j.items))
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth, url])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  useEffect(() => {
+    let cancelled = false
+    ;(async () => {
+      try {
+        const res = await fetchAuth("/api/v1/products?catalog=base")
+        if (!res.ok) return
+        const j = (await res.json()) as { items?: ProductSummaryDTO[] }
+        if (!cancelled) setProducts(j.items || [])
+      } catch {}
+    })()
+    return () => {
+      cancelled = true
+    }
+  }, [fetchAuth])
+
+  async function save() {
+    if (!drafts) return
+    setSaving(true)
+    setError(null)
+    try {
+      const res = await fetchAuth(url, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json", "X-CSRF": getCSRFToken() || "" },
+        body: JSON.stringify({ items: choiceGroupsPayload(drafts) }),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const j = (await res.json()) as { items: VolunteerChoiceGroup[] }
+      setDrafts(toDrafts(j.items))
+      setSaved(true)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  if (!drafts) return null
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>Auswahl an der Station</CardTitle>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-4 text-sm">
+        <p className="text-muted-foreground">
+          Pro Gruppe wählt die Station bei der Einlösung eines der markierten Produkte, z. B. einen Hauptgang aus drei.
+          Angeboten wird nur, was die Station führt.
+        </p>
+        <VolunteerChoiceGroupsEditor
+          value={drafts}
+          onChange={(next) => {
+            setSaved(false)
+            setDrafts(next)
+          }}
+          products={products}
+        />
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+        <div className="flex flex-wrap items-center gap-2">
+          <Button onClick={save} disabled={saving}>
+            {saving && <Loader2 className="size-4 animate-spin" aria-hidden />}
+            Speichern
+          </Button>
+          {saved && <span className="text-muted-foreground">Gespeichert.</span>}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
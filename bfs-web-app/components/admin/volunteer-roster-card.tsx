@@ -0,0 +1,239 @@
+"use client"
+
+import { Loader2, Printer, Trash2, Upload } from "lucide-react"
+import { useCallback, useEffect, useRef, useState } from "react"
+import {
+  DEFAULT_SLIP_PRINT_OPTIONS,
+  type SlipPrintOptions,
+  SlipPrintOptionsFields,
+  slipLayoutParams,
+} from "@/components/admin/slip-print-options"
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+} from "@/components/ui/alert-dialog"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { VolunteerImportResult, VolunteerToken } from "@/types/volunteer"
+
+interface VolunteerRosterCardProps {
+  campaignId: string
+}
+
+// Personal QR codes: one per volunteer from an imported CSV roster, each with
+// its own redemption limit. Complements the shared campaign QR.
+export function VolunteerRosterCard({ campaignId }: VolunteerRosterCardProps) {
+  const fetchAuth = useAuthorizedFetch()
+  const fileRef = useRef<HTMLInputElement>(null)
+  const [volunteers, setVolunteers] = useState<VolunteerToken[]>([])
+  const [defaultMax, setDefaultMax] = useState<number | "">(1)
+  const [importing, setImporting] = useState(false)
+  const [result, setResult] = useState<VolunteerImportResult | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [pendingDelete, setPendingDelete] = useState<VolunteerToken | null>(null)
+  const [printOptions, setPrintOptions] = useState<SlipPrintOptions>(DEFAULT_SLIP_PRINT_OPTIONS)
+
+  const base = `/api/v1/staff-meals/${encodeURIComponent(campaignId)}/volunteers`
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(base)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const j = (await res.json()) as { items: VolunteerToken[] }
+      setVolunteers(j.items)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth, base])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  async function importRoster(file: File) {
+    setImporting(true)
+    setError(null)
+    setResult(null)
+    try {
+      const form = new FormData()
+      form.append("file", file)
+      const max = defaultMax === "" || defaultMax < 1 ? 1 : defaultMax
+      const res = await fetchAuth(`${base}/import?defaultMax=${max}`, {
+        method: "POST",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+        body: form,
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setResult((await res.json()) as VolunteerImportResult)
+      await load()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Import fehlgeschlagen")
+    } finally {
+      setImporting(false)
+      if (fileRef.current) fileRef.current.value = ""
+    }
+  }
+
+  async function remove(v: VolunteerToken) {
+    try {
+      const res = await fetchAuth(`${base}/${encodeURIComponent(v.id)}`, {
+        method: "DELETE",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+      })
+      if (!res.ok && res.status !== 204) throw new Error(await readErrorMessage(res))
+      await load()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Entfernen fehlgeschlagen")
+    }
+  }
+
+  function print(ids?: string[]) {
+    const params = slipLayoutParams(printOptions)
+    if (ids && ids.length > 0) params.set("ids", ids.join(","))
+    window.open(`${base}/print.pdf?${params}`, "_blank")
+  }
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>Persönliche QR-Codes ({volunteers.length})</CardTitle>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-4">
+        <p className="text-muted-foreground text-sm">
+          CSV mit den Spalten <span className="font-mono">name</span>, <span className="font-mono">email</span> und
+          optional <span className="font-mono">max</span> (Einlösungen pro Person). Bereits erfasste Namen werden
+          übersprungen.
+        </p>
+        <div className="flex flex-wrap items-end gap-3">
+          <div className="grid gap-2">
+            <Label htmlFor="roster-default-max">Einlösungen pro Person</Label>
+            <Input
+              id="roster-default-max"
+              type="number"
+              min={1}
+              className="w-32"
+              value={defaultMax}
+              onChange={(e) => {
+                const raw = e.target.value
+                setDefaultMax(raw === "" ? "" : parseInt(raw, 10))
+              }}
+            />
+          </div>
+          <input
+            ref={fileRef}
+            type="file"
+            accept=".csv,text/csv"
+            className="hidden"
+            onChange={(e) => {
+              const f = e.target.files?.[0]
+              if (f) void importRoster(f)
+            }}
+          />
+          <Button variant="outline" onClick={() => fileRef.current?.click()} disabled={importing}>
+            {importing ? (
+              <Loader2 className="size-4 animate-spin" aria-hidden />
+            ) : (
+              <Upload className="size-4" aria-hidden />
+            )}
+            CSV importieren
+          </Button>
+          <Button onClick={() => print()} disabled={volunteers.length === 0}>
+            <Printer className="size-4" aria-hidden />
+            Alle drucken
+          </Button>
+        </div>
+
+        <SlipPrintOptionsFields value={printOptions} onChange={setPrintOptions} idPrefix="roster-print" />
+
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2 text-sm">{error}</div>}
+        {result && (
+          <div className="bg-muted rounded-xl px-3 py-2 text-sm">
+            <p>
+              {result.imported.length} importiert, {result.skipped} übersprungen.
+            </p>
+            {result.errors.length > 0 && (
+              <ul className="text-muted-foreground mt-1 list-disc pl-5 text-xs">
+                {result.errors.map((e) => (
+                  <li key={`${e.line}-${e.message}`}>
+                    Zeile {e.line}: {e.message}
+                  </li>
+                ))}
+              </ul>
+            )}
+          </div>
+        )}
+
+        {volunteers.length > 0 && (
+          <div className="overflow-x-auto">
+            <Table>
+              <TableHeader>
+                <TableRow>
+                  <TableHead>Name</TableHead>
+                  <TableHead>E-Mail</TableHead>
+                  <TableHead className="text-right">Eingelöst</TableHead>
+                  <TableHead className="w-24" />
+                </TableRow>
+              </TableHeader>
+              <TableBody>
+                {volunteers.map((v) => (
+                  <TableRow key={v.id}>
+                    <TableCell>{v.name}</TableCell>
+                    <TableCell className="text-muted-foreground text-sm">{v.email ?? "–"}</TableCell>
+                    <TableCell className="text-right tabular-nums">
+                      {v.redemptionCount} / {v.maxRedemptions}
+                    </TableCell>
+                    <TableCell className="text-right">
+                      <div className="flex justify-end gap-1">
+                        <Button variant="ghost" size="icon" onClick={() => print([v.id])} aria-label="Slip drucken">
+                          <Printer className="size-4" aria-hidden />
+                        </Button>
+                        <Button variant="ghost" size="icon" onClick={() => setPendingDelete(v)} aria-label="Entfernen">
+                          <Trash2 className="size-4" aria-hidden />
+                        </Button>
+                      </div>
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </div>
+        )}
+      </CardContent>
+
+      <AlertDialog open={pendingDelete !== null} onOpenChange={(open) => !open && setPendingDelete(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>{pendingDelete?.name} entfernen?</AlertDialogTitle>
+            <AlertDialogDescription>
+              Der persönliche QR-Code wird ungültig. Bisherige Einlösungen bleiben erhalten.
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>Abbrechen</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={async () => {
+                const v = pendingDelete
+                setPendingDelete(null)
+                if (v) await remove(v)
+              }}
+            >
+              Entfernen
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </Card>
+  )
+}
@@ -0,0 +1,243 @@
+"use client"
+
+import { Loader2, Plus, Trash2 } from "lucide-react"
+import { useCallback, useEffect, useState } from "react"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { VolunteerSchedule, VolunteerWindow } from "@/types/volunteer"
+
+interface VolunteerScheduleCardProps {
+  campaignId: string
+}
+
+const WEEKDAYS: Array<{ iso: number; label: string }> = [
+  { iso: 1, label: "Mo" },
+  { iso: 2, label: "Di" },
+  { iso: 3, label: "Mi" },
+  { iso: 4, label: "Do" },
+  { iso: 5, label: "Fr" },
+  { iso: 6, label: "Sa" },
+  { iso: 7, label: "So" },
+]
+
+const ALL_DAYS = WEEKDAYS.map((d) => d.iso)
+
+function optionalNumber(raw: string): number | null {
+  if (raw === "") return null
+  const n = parseInt(raw, 10)
+  return Number.isFinite(n) ? n : null
+}
+
+// Recurring meal windows (Europe/Zurich) and per-window / per-day limits. The
+// limits apply to the shared QR as a whole and to each personal QR separately.
+export function VolunteerScheduleCard({ campaignId }: VolunteerScheduleCardProps) {
+  const fetchAuth = useAuthorizedFetch()
+  const [draft, setDraft] = useState<VolunteerSchedule | null>(null)
+  const [saving, setSaving] = useState(false)
+  const [saved, setSaved] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const url = `/api/v1/staff-meals/${encodeURIComponent(campaignId)}/schedule`
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(url)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setDraft((await res.json()) as VolunteerSchedule)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth, url])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  function updateWindow(idx: number, patch: Partial<VolunteerWindow>) {
+    setSaved(false)
+    setDraft((d) => (d ? { ...d, windows: d.windows.map((w, i) => (i === idx ? { ...w, ...patch } : w)) } : d))
+  }
+
+  function toggleDay(idx: number, iso: number) {
+    if (!draft) return
+    const days = draft.windows[idx]?.weekdays ?? ALL_DAYS
+    const next = days.includes(iso) ? days.filter((d) => d !== iso) : [...days, iso].sort((a, b) => a - b)
+    updateWindow(idx, { weekdays: next })
+  }
+
+  function addWindow() {
+    setSaved(false)
+    setDraft((d) =>
+      d ? { ...d, windows: [...d.windows, { label: "", weekdays: ALL_DAYS, start: "11:30", end: "14:00" }] } : d
+    )
+  }
+
+  function removeWindow(idx: number) {
+    setSaved(false)
+    setDraft((d) => (d ? { ...d, windows: d.windows.filter((_, i) => i !== idx) } : d))
+  }
+
+  async function save() {
+    if (!draft) return
+    setSaving(true)
+    setError(null)
+    try {
+      const res = await fetchAuth(url, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json", "X-CSRF": getCSRFToken() || "" },
+        body: JSON.stringify(draft),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setDraft((await res.json()) as VolunteerSchedule)
+      setSaved(true)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Speichern fehlgeschlagen")
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  if (!draft) return null
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>Essenszeiten &amp; Limits</CardTitle>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-5 text-sm">
+        <p className="text-muted-foreground">
+          Ohne Zeitfenster ist die Kampagne während der ganzen Gültigkeit einlösbar. Limits gelten für den geteilten
+          QR-Code insgesamt und für jeden persönlichen QR-Code einzeln. Leer = unbegrenzt.
+        </p>
+
+        <div className="grid gap-4 sm:grid-cols-2">
+          <div className="grid gap-2">
+            <Label htmlFor="sched-max-day">Pro Tag (geteilter QR)</Label>
+            <Input
+              id="sched-max-day"
+              type="number"
+              min={1}
+              value={draft.maxRedemptionsPerDay ?? ""}
+              onChange={(e) => {
+                setSaved(false)
+                setDraft({ ...draft, maxRedemptionsPerDay: optionalNumber(e.target.value) })
+              }}
+            />
+          </div>
+          <div className="grid gap-2">
+            <Label htmlFor="sched-max-person-day">Pro Tag und Person</Label>
+            <Input
+              id="sched-max-person-day"
+              type="number"
+              min={1}
+              value={draft.maxPerPersonPerDay ?? ""}
+              onChange={(e) => {
+                setSaved(false)
+                setDraft({ ...draft, maxPerPersonPerDay: optionalNumber(e.target.value) })
+              }}
+            />
+          </div>
+        </div>
+
+        {draft.windows.map((w, idx) => (
+          <div key={w.id ?? `new-${idx}`} className="flex flex-col gap-3 rounded-xl border p-3">
+            <div className="flex flex-wrap items-end gap-3">
+              <div className="grid gap-1.5">
+                <Label htmlFor={`win-label-${idx}`}>Bezeichnung</Label>
+                <Input
+                  id={`win-label-${idx}`}
+                  className="w-36"
+                  maxLength={50}
+                  placeholder="Mittag"
+                  value={w.label}
+                  onChange={(e) => updateWindow(idx, { label: e.target.value })}
+                />
+              </div>
+              <div className="grid gap-1.5">
+                <Label htmlFor={`win-start-${idx}`}>Von</Label>
+                <Input
+                  id={`win-start-${idx}`}
+                  type="time"
+                  className="w-28"
+                  value={w.start}
+                  onChange={(e) => updateWindow(idx, { start: e.target.value })}
+                />
+              </div>
+              <div className="grid gap-1.5">
+                <Label htmlFor={`win-end-${idx}`}>Bis</Label>
+                <Input
+                  id={`win-end-${idx}`}
+                  type="time"
+                  className="w-28"
+                  value={w.end}
+                  onChange={(e) => updateWindow(idx, { end: e.target.value })}
+                />
+              </div>
+              <div className="grid gap-1.5">
+                <Label htmlFor={`win-max-${idx}`}>Max. (geteilt)</Label>
+                <Input
+                  id={`win-max-${idx}`}
+                  type="number"
+                  min={1}
+                  className="w-24"
+                  value={w.maxRedemptions ?? ""}
+                  onChange={(e) => updateWindow(idx, { maxRedemptions: optionalNumber(e.target.value) })}
+                />
+              </div>
+              <div className="grid gap-1.5">
+                <Label htmlFor={`win-person-${idx}`}>Max. pro Person</Label>
+                <Input
+                  id={`win-person-${idx}`}
+                  type="number"
+                  min={1}
+                  className="w-24"
+                  value={w.maxPerPerson ?? ""}
+                  onChange={(e) => updateWindow(idx, { maxPerPerson: optionalNumber(e.target.value) })}
+                />
+              </div>
+              <Button variant="ghost" size="icon" onClick={() => removeWindow(idx)} aria-label="Zeitfenster entfernen">
+                <Trash2 className="size-4" aria-hidden />
+              </Button>
+            </div>
+            <div className="flex flex-wrap gap-1.5">
+              {WEEKDAYS.map((d) => {
+                const on = (w.weekdays.length === 0 ? ALL_DAYS : w.weekdays).includes(d.iso)
+                return (
+                  <Button
+                    key={d.iso}
+                    type="button"
+                    size="sm"
+                    variant={on ? "default" : "outline"}
+                    onClick={() => toggleDay(idx, d.iso)}
+                    aria-pressed={on}
+                  >
+                    {d.label}
+                  </Button>
+                )
+              })}
+            </div>
+          </div>
+        ))}
+
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+
+        <div className="flex flex-wrap items-center gap-2">
+          <Button variant="outline" onClick={addWindow}>
+            <Plus className="size-4" aria-hidden />
+            Zeitfenster
+          </Button>
+          <Button onClick={save} disabled={saving}>
+            {saving && <Loader2 className="size-4 animate-spin" aria-hidden />}
+            Speichern
+          </Button>
+          {saved && <span className="text-muted-foreground">Gespeichert.</span>}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
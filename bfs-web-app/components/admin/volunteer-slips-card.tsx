@@ -0,0 +1,250 @@
+"use client"
+
+import { ImagePlus, Loader2, Printer, Ticket, Trash2 } from "lucide-react"
+import Image from "next/image"
+import { useCallback, useEffect, useRef, useState } from "react"
+import {
+  DEFAULT_SLIP_PRINT_OPTIONS,
+  type SlipPrintOptions,
+  SlipPrintOptionsFields,
+  slipLayoutParams,
+} from "@/components/admin/slip-print-options"
+import { Button } from "@/components/ui/button"
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Input } from "@/components/ui/input"
+import { Label } from "@/components/ui/label"
+import { useAuthorizedFetch } from "@/hooks/use-authorized-fetch"
+import { getCSRFToken } from "@/lib/csrf"
+import { readErrorMessage } from "@/lib/http"
+import type { IssueVolunteerSlipsResponse, VolunteerSlipSummary } from "@/types/volunteer"
+
+interface VolunteerSlipsCardProps {
+  campaignId: string
+  logoUrl: string | null
+  onLogoChange: () => void | Promise<void>
+}
+
+// Slip logo and numbered single-use slips: each bon has its own QR code and
+// can be redeemed exactly once, unlike the shared campaign QR.
+export function VolunteerSlipsCard({ campaignId, logoUrl, onLogoChange }: VolunteerSlipsCardProps) {
+  const fetchAuth = useAuthorizedFetch()
+  const fileRef = useRef<HTMLInputElement>(null)
+  const [summary, setSummary] = useState<VolunteerSlipSummary | null>(null)
+  const [issueCount, setIssueCount] = useState<number | "">(50)
+  const [issuing, setIssuing] = useState(false)
+  const [rangeFrom, setRangeFrom] = useState<number | "">("")
+  const [rangeTo, setRangeTo] = useState<number | "">("")
+  const [printOptions, setPrintOptions] = useState<SlipPrintOptions>(DEFAULT_SLIP_PRINT_OPTIONS)
+  const [uploading, setUploading] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+
+  const base = `/api/v1/staff-meals/${encodeURIComponent(campaignId)}`
+
+  const load = useCallback(async () => {
+    try {
+      const res = await fetchAuth(`${base}/slips`)
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      setSummary((await res.json()) as VolunteerSlipSummary)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Laden fehlgeschlagen")
+    }
+  }, [fetchAuth, base])
+
+  useEffect(() => {
+    void load()
+  }, [load])
+
+  async function issue() {
+    if (issueCount === "" || issueCount < 1) return
+    setIssuing(true)
+    setError(null)
+    try {
+      const res = await fetchAuth(`${base}/slips`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json", "X-CSRF": getCSRFToken() || "" },
+        body: JSON.stringify({ count: issueCount }),
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      const j = (await res.json()) as IssueVolunteerSlipsResponse
+      setSummary(j)
+      setRangeFrom(j.from)
+      setRangeTo(j.to)
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Erstellen fehlgeschlagen")
+    } finally {
+      setIssuing(false)
+    }
+  }
+
+  function print() {
+    const params = slipLayoutParams(printOptions)
+    if (rangeFrom !== "") params.set("from", String(rangeFrom))
+    if (rangeTo !== "") params.set("to", String(rangeTo))
+    window.open(`${base}/slips/print.pdf?${params}`, "_blank")
+  }
+
+  async function uploadLogo(file: File) {
+    setUploading(true)
+    setError(null)
+    try {
+      const form = new FormData()
+      form.append("file", file)
+      const res = await fetchAuth(`${base}/logo`, {
+        method: "POST",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+        body: form,
+      })
+      if (!res.ok) throw new Error(await readErrorMessage(res))
+      await onLogoChange()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Upload fehlgeschlagen")
+    } finally {
+      setUploading(false)
+      if (fileRef.current) fileRef.current.value = ""
+    }
+  }
+
+  async function removeLogo() {
+    setError(null)
+    try {
+      const res = await fetchAuth(`${base}/logo`, {
+        method: "DELETE",
+        headers: { "X-CSRF": getCSRFToken() || "" },
+      })
+      if (!res.ok && res.status !== 204) throw new Error(await readErrorMessage(res))
+      await onLogoChange()
+    } catch (e: unknown) {
+      setError(e instanceof Error ? e.message : "Entfernen fehlgeschlagen")
+    }
+  }
+
+  return (
+    <Card className="rounded-2xl">
+      <CardHeader>
+        <CardTitle>Einmal-Bons & Logo</CardTitle>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-5 text-sm">
+        <div className="flex flex-wrap items-center gap-3">
+          {logoUrl ? (
+            <div className="relative h-12 w-32 rounded-md border">
+              <Image src={logoUrl} alt="Logo" fill sizes="128px" className="object-contain p-1" />
+            </div>
+          ) : (
+            <span className="text-muted-foreground">Kein Logo – die Slips zeigen nur QR-Code und Text.</span>
+          )}
+          <input
+            ref={fileRef}
+            type="file"
+            accept="image/png,image/jpeg"
+            className="hidden"
+            onChange={(e) => {
+              const f = e.target.files?.[0]
+              if (f) void uploadLogo(f)
+            }}
+          />
+          <Button variant="outline" size="sm" onClick={() => fileRef.current?.click()} disabled={uploading}>
+            {uploading ? (
+              <Loader2 className="size-4 animate-spin" aria-hidden />
+            ) : (
+              <ImagePlus className="size-4" aria-hidden />
+            )}
+            {logoUrl ? "Logo ersetzen" : "Logo hochladen"}
+          </Button>
+          {logoUrl && (
+            <Button variant="ghost" size="sm" onClick={() => void removeLogo()}>
+              <Trash2 className="size-4" aria-hidden />
+              Entfernen
+            </Button>
+          )}
+        </div>
+
+        <div className="flex flex-col gap-3">
+          <p className="text-muted-foreground">
+            Jeder Bon hat einen eigenen QR-Code und eine fortlaufende Nummer und kann genau einmal eingelöst werden.
+            {summary && summary.issued > 0 && (
+              <>
+                {" "}
+                Bisher {summary.issued} Bons erstellt (Nr. 1–{summary.lastSerial}), {summary.redeemed} eingelöst.
+              </>
+            )}
+          </p>
+          <div className="flex flex-wrap items-end gap-3">
+            <div className="grid gap-1.5">
+              <Label htmlFor="slips-count">Neue Bons</Label>
+              <Input
+                id="slips-count"
+                type="number"
+                min={1}
+                max={500}
+                className="w-24"
+                value={issueCount}
+                onChange={(e) => {
+                  const raw = e.target.value
+                  setIssueCount(raw === "" ? "" : parseInt(raw, 10))
+                }}
+              />
+            </div>
+            <Button
+              variant="outline"
+              onClick={issue}
+              disabled={issuing || issueCount === "" || issueCount < 1 || issueCount > 500}
+            >
+              {issuing ? (
+                <Loader2 className="size-4 animate-spin" aria-hidden />
+              ) : (
+                <Ticket className="size-4" aria-hidden />
+              )}
+              Erstellen
+            </Button>
+          </div>
+        </div>
+
+        {summary && summary.issued > 0 && (
+          <div className="flex flex-col gap-3">
+            <div className="flex flex-wrap items-end gap-3">
+              <div className="grid gap-1.5">
+                <Label htmlFor="slips-from">Von Nr.</Label>
+                <Input
+                  id="slips-from"
+                  type="number"
+                  min={1}
+                  placeholder="1"
+                  className="w-24"
+                  value={rangeFrom}
+                  onChange={(e) => {
+                    const raw = e.target.value
+                    setRangeFrom(raw === "" ? "" : parseInt(raw, 10))
+                  }}
+                />
+              </div>
+              <div className="grid gap-1.5">
+                <Label htmlFor="slips-to">Bis Nr.</Label>
+                <Input
+                  id="slips-to"
+                  type="number"
+                  min={1}
+                  placeholder={String(summary.lastSerial)}
+                  className="w-24"
+                  value={rangeTo}
+                  onChange={(e) => {
+                    const raw = e.target.value
+                    setRangeTo(raw === "" ? "" : parseInt(raw, 10))
+                  }}
+                />
+              </div>
+            </div>
+            <SlipPrintOptionsFields value={printOptions} onChange={setPrintOptions} idPrefix="slips-print" />
+            <div>
+              <Button onClick={print}>
+                <Printer className="size-4" aria-hidden />
+                Bons drucken
+              </Button>
+            </div>
+          </div>
+        )}
+
+        {error && <div className="text-destructive bg-destructive/10 rounded-xl px-3 py-2">{error}</div>}
+      </CardContent>
+    </Card>
+  )
+}
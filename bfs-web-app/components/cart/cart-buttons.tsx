@@ -3,6 +3,7 @@
 import { Minus, Plus } from "lucide-react"
 import { Button } from "@/components/ui/button"
 import { useCart } from "@/contexts/cart-context"
+import { activeVariants } from "@/lib/utils"
 import { CartItemConfiguration, ProductDTO } from "@/types"
 
 interface CartButtonsProps {
@@ -14,20 +15,22 @@ interface CartButtonsProps {
 
 export function CartButtons({ product, configuration, onConfigureProduct, disabled }: CartButtonsProps) {
   const { addToCart, updateQuantity, getItemQuantity, getTotalProductQuantity, cart } = useCart()
+  // Products with variants pick one like a menu picks its configuration.
+  const picksOption = product.type === "menu" || activeVariants(product).length > 0
 
   // For menu products without specific configuration, show total quantity across all configurations
   const quantity = configuration
     ? getItemQuantity(product.id, configuration)
-    : product.type === "menu"
+    : picksOption
       ? getTotalProductQuantity(product.id)
       : getItemQuantity(product.id, configuration)
 
-  const maxQty = typeof product.availableQuantity === "number" ? product.availableQuantity : undefined
+  const maxQty = typeof product.availableQuantity === "number" && !picksOption ? product.availableQuantity : undefined
   const reachedMax = typeof maxQty === "number" && quantity >= maxQty
 
   const handleAdd = () => {
     if (disabled || reachedMax) return
-    if (product.type === "menu" && !configuration && onConfigureProduct) {
+    if (picksOption && !configuration && onConfigureProduct) {
       onConfigureProduct()
     } else {
       addToCart(product, configuration)
@@ -43,7 +46,7 @@ export function CartButtons({ product, configuration, onConfigureProduct, disabl
         if (currentItem) {
           updateQuantity(currentItem.id, currentItem.quantity - 1)
         }
-      } else if (product.type === "menu") {
+      } else if (picksOption) {
         // For menu products without configuration, remove one from the last added configuration
         const productItems = cart.items.filter((item) => item.product.id === product.id)
         if (productItems.length > 0) {
@@ -72,7 +75,7 @@ export function CartButtons({ product, configuration, onConfigureProduct, disabl
       if (currentItem) {
         updateQuantity(currentItem.id, currentItem.quantity + 1)
       }
-    } else if (product.type === "menu") {
+    } else if (picksOption) {
       // For menu products without configuration, open configuration modal instead
       if (onConfigureProduct) {
         onConfigureProduct()
@@ -3,7 +3,7 @@
 import { Minus, Pen, Plus, Trash2 } from "lucide-react"
 import Image from "next/image"
 import { Button } from "@/components/ui/button"
-import { formatChf } from "@/lib/utils"
+import { formatChf, itemName } from "@/lib/utils"
 import { CartItem } from "@/types/cart"
 
 export interface CartItemDiscountInfo {
@@ -62,7 +62,7 @@ export function CartItemDisplay({
             <div className="flex flex-row justify-between">
               <div className="flex flex-col gap-1">
                 <h3 className={`font-family-secondary truncate font-medium ${isPOS ? "text-sm" : "text-lg"}`}>
-                  {item.product.name}
+                  {itemName(item.product.name, item.variant?.name)}
                 </h3>
                 {!isPOS && item.product.description && (
                   <p className="text-muted-foreground line-clamp-2 text-xs whitespace-pre-line">
@@ -115,7 +115,7 @@ export function CartItemDisplay({
                     <h4
                       className={`font-family-secondary text-muted-foreground truncate line-through ${isPOS ? "text-xs" : "text-base"}`}
                     >
-                      {formatChf(item.product.priceCents * item.quantity)}
+                      {formatChf(item.totalPriceCents * item.quantity)}
                     </h4>
                     <span className={`font-medium text-green-600 ${isPOS ? "text-xs" : "text-base"}`}>Gratis</span>
                   </>
@@ -124,10 +124,10 @@ export function CartItemDisplay({
                     <h4
                       className={`font-family-secondary text-muted-foreground truncate line-through ${isPOS ? "text-xs" : "text-base"}`}
                     >
-                      {formatChf(item.product.priceCents * item.quantity)}
+                      {formatChf(item.totalPriceCents * item.quantity)}
                     </h4>
                     <span className={`font-medium text-green-600 ${isPOS ? "text-xs" : "text-base"}`}>
-                      {formatChf(item.product.priceCents * (item.quantity - discountInfo!.discountedQuantity))}
+                      {formatChf(item.totalPriceCents * (item.quantity - discountInfo!.discountedQuantity))}
                     </span>
                     <span className={`text-muted-foreground ${isPOS ? "text-[10px]" : "text-xs"}`}>
                       ({discountInfo!.discountedQuantity}× gratis)
@@ -135,7 +135,7 @@ export function CartItemDisplay({
                   </>
                 ) : (
                   <h4 className={`font-family-secondary truncate ${isPOS ? "text-xs" : "text-base"}`}>
-                    {formatChf(item.product.priceCents)}
+                    {formatChf(item.totalPriceCents)}
                   </h4>
                 )}
               </div>
@@ -173,7 +173,7 @@ export function CartItemDisplay({
           <div className="flex flex-row items-center justify-between">
             <div className="flex flex-col gap-0">
               <h3 className={`font-family-secondary truncate font-medium ${isPOS ? "text-sm" : "text-lg"}`}>
-                {item.product.name}
+                {itemName(item.product.name, item.variant?.name)}
               </h3>
               {!isPOS && item.product.description && (
                 <p className="text-muted-foreground line-clamp-2 text-xs whitespace-pre-line">
@@ -186,7 +186,7 @@ export function CartItemDisplay({
                     <h4
                       className={`font-family-secondary text-muted-foreground truncate line-through ${isPOS ? "text-xs" : "text-sm"}`}
                     >
-                      {formatChf(item.product.priceCents * item.quantity)}
+                      {formatChf(item.totalPriceCents * item.quantity)}
                     </h4>
                     <span className={`font-medium text-green-600 ${isPOS ? "text-xs" : "text-sm"}`}>Gratis</span>
                   </>
@@ -195,10 +195,10 @@ export function CartItemDisplay({
                     <h4
                       className={`font-family-secondary text-muted-foreground truncate line-through ${isPOS ? "text-xs" : "text-sm"}`}
                     >
-                      {formatChf(item.product.priceCents * item.quantity)}
+                      {formatChf(item.totalPriceCents * item.quantity)}
                     </h4>
                     <span className={`font-medium text-green-600 ${isPOS ? "text-xs" : "text-sm"}`}>
-                      {formatChf(item.product.priceCents * (item.quantity - discountInfo!.discountedQuantity))}
+                      {formatChf(item.totalPriceCents * (item.quantity - discountInfo!.discountedQuantity))}
                     </span>
                     <span className={`text-muted-foreground ${isPOS ? "text-[10px]" : "text-xs"}`}>
                       ({discountInfo!.discountedQuantity}× gratis)
@@ -206,7 +206,7 @@ export function CartItemDisplay({
                   </>
                 ) : (
                   <h4 className={`font-family-secondary truncate ${isPOS ? "text-xs" : "text-sm"}`}>
-                    {formatChf(item.product.priceCents)}
+                    {formatChf(item.totalPriceCents)}
                   </h4>
                 )}
               </div>
@@ -1,2 +1,3 @@
 export { CartButtons } from "./cart-buttons"
 export { ProductConfigurationModal } from "./product-configuration-modal"
+export { VariantPickerModal } from "./variant-picker-modal"
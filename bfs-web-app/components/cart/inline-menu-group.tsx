@@ -30,7 +30,7 @@ export function InlineMenuGroup({
 }: InlineMenuGroupProps) {
   const { updateQuantity, removeFromCart, addToCart } = useCart()
 
-  const sumSimpleCents = useMemo(() => items.reduce((sum, it) => sum + it.product.priceCents, 0), [items])
+  const sumSimpleCents = useMemo(() => items.reduce((sum, it) => sum + it.totalPriceCents, 0), [items])
   const hasSavings = suggestion.savingsCents > 0
 
   const applyConversion = () => {
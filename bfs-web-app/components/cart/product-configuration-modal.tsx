@@ -6,6 +6,7 @@ import { Button } from "@/components/ui/button"
 import { Card, CardContent } from "@/components/ui/card"
 import { Dialog, DialogContent, DialogFooter, DialogHeader, DialogTitle } from "@/components/ui/dialog"
 import { useCart } from "@/contexts/cart-context"
+import { allergenLabels } from "@/lib/allergens"
 import { CartItemConfiguration, MenuSlotDTO, ProductDTO } from "@/types"
 
 interface ProductConfigurationModalProps {
@@ -188,6 +189,11 @@ function MenuSlotSelector({ slot, selectedProductId, onSelect, showDescription =
                         {item.description}
                       </p>
                     )}
+                    {allergenLabels(item.allergens) && (
+                      <p className="text-muted-foreground mt-0.5 text-[11px]">
+                        Enthält: {allergenLabels(item.allergens)}
+                      </p>
+                    )}
                   </div>
                 </div>
               </CardContent>
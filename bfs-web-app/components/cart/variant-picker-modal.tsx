@@ -0,0 +1,79 @@
+"use client"
+
+import { Card, CardContent } from "@/components/ui/card"
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from "@/components/ui/dialog"
+import { useCart } from "@/contexts/cart-context"
+import { activeVariants, formatChf } from "@/lib/utils"
+import { ProductDTO } from "@/types"
+
+interface VariantPickerModalProps {
+  product: ProductDTO
+  isOpen: boolean
+  onClose: () => void
+}
+
+// Adds one unit of the chosen variant, e.g. the 3dl or 5dl of a drink.
+export function VariantPickerModal({ product, isOpen, onClose }: VariantPickerModalProps) {
+  const { addToCart, getItemQuantity } = useCart()
+  const variants = activeVariants(product)
+
+  return (
+    <Dialog open={isOpen} onOpenChange={onClose}>
+      <DialogContent className="max-h-[80vh] max-w-md overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle className="font-family-primary text-xl">{product.name}</DialogTitle>
+        </DialogHeader>
+
+        <div className="grid gap-2">
+          {variants.map((variant) => {
+            const stock = typeof variant.stock === "number" ? variant.stock : null
+            const inCart = getItemQuantity(product.id, undefined, variant.id)
+            const isAvailable = stock === null || stock - inCart > 0
+            const isLowStock = stock !== null && stock > 0 && stock <= 10
+            return (
+              <Card
+                key={variant.id}
+                role="button"
+                aria-disabled={!isAvailable}
+                className={`relative rounded-2xl py-0 transition-all hover:shadow-md ${
+                  isAvailable
+                    ? "bg-muted/40 hover:bg-muted/70 cursor-pointer"
+                    : "pointer-events-none opacity-60 grayscale"
+                }`}
+                onClick={() => {
+                  if (!isAvailable) return
+                  addToCart(product, undefined, variant)
+                  onClose()
+                }}
+              >
+                <CardContent className="flex items-center justify-between gap-3 p-4">
+                  <div className="flex items-center gap-2">
+                    <h4 className="font-family-secondary text-lg font-medium">{variant.name}</h4>
+                    {!isAvailable && (
+                      <span className="rounded-full bg-red-600 px-2 py-0.5 text-xs font-medium text-white">
+                        Ausverkauft
+                      </span>
+                    )}
+                    {isAvailable && isLowStock && (
+                      <span className="rounded-full bg-amber-600 px-2 py-0.5 text-xs font-medium text-white">
+                        Nur {stock} übrig
+                      </span>
+                    )}
+                  </div>
+                  <span className="font-family-secondary text-base">
+                    {variant.listPriceCents != null && (
+                      <span className="text-muted-foreground mr-2 text-sm line-through">
+                        {formatChf(variant.listPriceCents)}
+                      </span>
+                    )}
+                    {formatChf(variant.priceCents)}
+                  </span>
+                </CardContent>
+              </Card>
+            )
+          })}
+        </div>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -2,6 +2,7 @@
 
 import { useCallback, useState } from "react"
 import { useInventoryStream } from "@/hooks/use-inventory-stream"
+import { withVariantStock } from "@/lib/utils"
 import type { ListResponse, ProductDTO } from "@/types"
 import MenuGrid from "./menu-grid"
 
@@ -43,7 +44,7 @@ function applyStockToProduct(p: ProductDTO, stockMap: Map<string, number>): Prod
     }
   }
 
-  return updated
+  return withVariantStock(updated, stockMap)
 }
 
 export function MenuGridLive({ initialProducts }: { initialProducts: ListResponse<ProductDTO> }) {
@@ -4,9 +4,11 @@ import Image from "next/image"
 import { useState } from "react"
 import { CartButtons } from "@/components/cart/cart-buttons"
 import { ProductConfigurationModal } from "@/components/cart/product-configuration-modal"
+import { VariantPickerModal } from "@/components/cart/variant-picker-modal"
 import { Card, CardContent, CardHeader } from "@/components/ui/card"
 import { useCart } from "@/contexts/cart-context"
-import { formatChf } from "@/lib/utils"
+import { allergenLabels, dietaryLabels } from "@/lib/allergens"
+import { activeVariants, formatAvailableFrom, formatChf } from "@/lib/utils"
 import { ListResponse, ProductDTO } from "@/types"
 
 export function MenuGrid({ products }: { products: ListResponse<ProductDTO> }) {
@@ -35,26 +37,33 @@ export function MenuGrid({ products }: { products: ListResponse<ProductDTO> }) {
 
 function MenuProductCard({ product }: { product: ProductDTO }) {
   const [isConfigModalOpen, setIsConfigModalOpen] = useState(false)
+  const [isVariantPickerOpen, setIsVariantPickerOpen] = useState(false)
   const { addToCart, getItemQuantity, getTotalProductQuantity } = useCart()
   const isMenu = product.type === "menu"
+  const variants = activeVariants(product)
+  const hasVariants = variants.length > 0
   const isAvailable = isMenu || product.isAvailable !== false
   const isLowStock = !isMenu && product.isLowStock === true
   const availableQty = isMenu ? null : (product.availableQuantity ?? null)
   const isActive = product.isActive !== false
-  const disabled = !isAvailable || !isActive
+  const inWindow = product.availableNow !== false
+  const disabled = !isAvailable || !isActive || !inWindow
 
   // Determine current quantity for max check, mirroring CartButtons logic
-  const quantity = product.type === "menu" ? getTotalProductQuantity(product.id) : getItemQuantity(product.id)
-  const maxQty = typeof availableQty === "number" ? availableQty : undefined
+  const quantity =
+    product.type === "menu" || hasVariants ? getTotalProductQuantity(product.id) : getItemQuantity(product.id)
+  // Variants are capped in the picker; the product's stock is their total.
+  const maxQty = typeof availableQty === "number" && !hasVariants ? availableQty : undefined
   const reachedMax = typeof maxQty === "number" && quantity >= maxQty
 
   const handleConfigureProduct = () => {
-    setIsConfigModalOpen(true)
+    if (hasVariants) setIsVariantPickerOpen(true)
+    else setIsConfigModalOpen(true)
   }
 
   const handleCardActivate = () => {
     if (disabled || reachedMax) return
-    if (product.type === "menu") {
+    if (product.type === "menu" || hasVariants) {
       // Open configuration for menu products when no specific config is chosen
       handleConfigureProduct()
     } else {
@@ -107,7 +116,14 @@ function MenuProductCard({ product }: { product: ProductDTO }) {
                 <span className="rounded-full bg-zinc-700 px-3 py-1 text-sm font-medium text-white">Nicht aktiv</span>
               </div>
             )}
-            {isLowStock && isAvailable && isActive && (
+            {isAvailable && isActive && !inWindow && (
+              <div className="absolute inset-0 z-10 grid place-items-center rounded-[11px] bg-black/55">
+                <span className="rounded-full bg-zinc-700 px-3 py-1 text-sm font-medium text-white">
+                  {formatAvailableFrom(product.nextAvailableAt)}
+                </span>
+              </div>
+            )}
+            {isLowStock && isAvailable && isActive && inWindow && (
               <div className="absolute top-1 left-2 z-10">
                 <span className="rounded-full bg-amber-600 px-2 py-0.5 text-xs font-medium text-white">
                   {availableQty !== null ? `Nur ${availableQty} übrig` : "Geringer Bestand"}
@@ -126,7 +142,30 @@ function MenuProductCard({ product }: { product: ProductDTO }) {
                   {product.description}
                 </p>
               )}
-              <p className="font-family-secondary mt-1 text-base">{formatChf(product.priceCents)}</p>
+              {dietaryLabels(product.dietaryTags).length > 0 && (
+                <div className="mt-1 flex flex-wrap gap-1">
+                  {dietaryLabels(product.dietaryTags).map((label) => (
+                    <span key={label} className="rounded-full bg-emerald-100 px-2 py-0.5 text-[11px] text-emerald-800">
+                      {label}
+                    </span>
+                  ))}
+                </div>
+              )}
+              {allergenLabels(product.allergens) && (
+                <p className="text-muted-foreground mt-0.5 text-[11px]">
+                  {product.type === "menu" ? "Kann enthalten" : "Enthält"}: {allergenLabels(product.allergens)}
+                </p>
+              )}
+              <p className="font-family-secondary mt-1 text-base">
+                {hasVariants
+                  ? variants.map((v) => `${v.name} ${formatChf(v.priceCents)}`).join(" · ")
+                  : formatChf(product.priceCents)}
+                {!hasVariants && product.listPriceCents != null && (
+                  <span className="text-muted-foreground ml-2 text-sm line-through">
+                    {formatChf(product.listPriceCents)}
+                  </span>
+                )}
+              </p>
             </div>
             <div className="flex items-center" onClick={(e) => e.stopPropagation()}>
               <CartButtons product={product} onConfigureProduct={handleConfigureProduct} disabled={disabled} />
@@ -140,6 +179,13 @@ function MenuProductCard({ product }: { product: ProductDTO }) {
         isOpen={isConfigModalOpen}
         onClose={() => setIsConfigModalOpen(false)}
       />
+      {hasVariants && (
+        <VariantPickerModal
+          product={product}
+          isOpen={isVariantPickerOpen}
+          onClose={() => setIsVariantPickerOpen(false)}
+        />
+      )}
     </>
   )
 }
@@ -27,6 +27,7 @@ export function CheckoutClient() {
     try {
       const items = cart.items.map((i) => ({
         productId: i.product.id,
+        variantId: i.variant?.id,
         quantity: i.quantity,
         menuSelections: i.configuration
           ? Object.entries(i.configuration).map(([slotId, productId]) => ({ slotId, productId }))
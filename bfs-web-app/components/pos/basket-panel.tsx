@@ -17,9 +17,9 @@ import { useIsMobile } from "@/hooks/use-mobile"
 import { getStockCap } from "@/hooks/use-stock-map"
 
 import type { Club100Person } from "@/lib/api/club100"
-import { formatChf } from "@/lib/utils"
+import { formatChf, itemName } from "@/lib/utils"
 import type { CartItem } from "@/types/cart"
-import type { PosFulfillmentMode } from "@/types/jeton"
+import type { PosDeviceConfig, PosFulfillmentMode } from "@/types/jeton"
 import type {
   CardMeta,
   Club100Discount,
@@ -72,6 +72,9 @@ function textColorForBg(hex: string) {
 interface BasketPanelProps {
   token: string
   mode?: PosFulfillmentMode
+  // Payment methods and gratis types the device may use; all when unset.
+  paymentMethods?: PosDeviceConfig["paymentMethods"]
+  gratisTypes?: PosDeviceConfig["gratisTypes"]
   submitOrder: (
     items: QueuedOrderItem[],
     totalCents: number,
@@ -83,7 +86,14 @@ interface BasketPanelProps {
   stockMap?: Map<string, number>
 }
 
-export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }: BasketPanelProps) {
+export function BasketPanel({
+  token,
+  mode = "QR_CODE",
+  paymentMethods,
+  gratisTypes,
+  submitOrder,
+  stockMap,
+}: BasketPanelProps) {
   const { cart, updateQuantity, removeFromCart, clearCart } = useCart()
   const isMobile = useIsMobile()
 
@@ -135,6 +145,7 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
   const cartIsEmpty = cart.items.length === 0
   const jetonMode = mode === "JETON" || mode === "HYBRID"
   const shouldPrint = mode !== "JETON"
+  const allowsMethod = (m: PosDeviceConfig["paymentMethods"][number]) => !paymentMethods || paymentMethods.includes(m)
   const resolveMenuSelections = useCallback((item: CartItem) => {
     if (item.product.type !== "menu" || !item.configuration) return []
     const slots = item.product.menu?.slots || []
@@ -155,7 +166,7 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
         for (const choice of selections) {
           if (!choice.jeton) return true
         }
-      } else if (!it.product?.jeton) {
+      } else if (!(it.variant?.jeton ?? it.product?.jeton)) {
         return true
       }
     }
@@ -175,7 +186,8 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
         }
         continue
       }
-      const jeton = it.product.jeton
+      // A variant's own jeton replaces the product's.
+      const jeton = it.variant?.jeton ?? it.product.jeton
       if (!jeton) continue
       const existing = totals.get(jeton.id)
       if (existing) existing.count += it.quantity
@@ -194,7 +206,7 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
         }
       }
       return {
-        title: it.product.name,
+        title: itemName(it.product.name, it.variant?.name),
         quantity: it.quantity,
         unitPriceCents: it.totalPriceCents,
         configuration: cfg.length ? cfg : undefined,
@@ -236,6 +248,9 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
     const decrements = new Map<string, number>()
     for (const item of items) {
       decrements.set(item.product.id, (decrements.get(item.product.id) || 0) - item.quantity)
+      if (item.variant) {
+        decrements.set(item.variant.id, (decrements.get(item.variant.id) || 0) - item.quantity)
+      }
       if (item.product.type === "menu" && item.configuration && item.product.menu?.slots) {
         for (const [slotId, productId] of Object.entries(item.configuration)) {
           const slot = item.product.menu.slots.find((s) => s.id === slotId)
@@ -359,6 +374,7 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
         if (d.success) {
           const itemsBody = cart.items.map((it) => ({
             productId: it.product.id,
+            variantId: it.variant?.id,
             quantity: it.quantity,
             menuSelections: toMenuSelections(it.configuration),
           }))
@@ -494,6 +510,7 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
 
     const items = cart.items.map((it) => ({
       productId: it.product.id,
+      variantId: it.variant?.id,
       quantity: it.quantity,
       menuSelections: toMenuSelections(it.configuration),
     }))
@@ -552,6 +569,7 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
 
     const items = cart.items.map((it) => ({
       productId: it.product.id,
+      variantId: it.variant?.id,
       quantity: it.quantity,
       menuSelections: toMenuSelections(it.configuration),
     }))
@@ -948,20 +966,22 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
 
           {tender === null && (
             <div className="grid grid-cols-2 gap-3 sm:grid-cols-3">
-              <Button
-                className="flex h-24 flex-col items-center justify-center gap-2 rounded-xl sm:h-36"
-                variant="outline"
-                onClick={() => {
-                  setError(null)
-                  setReceived("")
-                  setTender("cash")
-                }}
-                aria-label="Bar bezahlen"
-              >
-                <Banknote className="size-8 sm:size-12" />
-                <span className="text-base font-medium">Bar</span>
-              </Button>
-              {!isMobile && (
+              {allowsMethod("cash") && (
+                <Button
+                  className="flex h-24 flex-col items-center justify-center gap-2 rounded-xl sm:h-36"
+                  variant="outline"
+                  onClick={() => {
+                    setError(null)
+                    setReceived("")
+                    setTender("cash")
+                  }}
+                  aria-label="Bar bezahlen"
+                >
+                  <Banknote className="size-8 sm:size-12" />
+                  <span className="text-base font-medium">Bar</span>
+                </Button>
+              )}
+              {!isMobile && allowsMethod("card") && (
                 <Button
                   className="flex h-24 flex-col items-center justify-center gap-2 rounded-xl sm:h-36"
                   variant="outline"
@@ -979,22 +999,24 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
                   <span className="text-base font-medium">Karte</span>
                 </Button>
               )}
-              <Button
-                className="flex h-24 flex-col items-center justify-center gap-2 rounded-xl sm:h-36"
-                variant="outline"
-                onClick={() => {
-                  setError(null)
-                  setTender("twint")
-                }}
-                aria-label="Mit TWINT bezahlen"
-              >
-                <QrCode className="size-8 sm:size-12" />
-                <span className="text-base font-medium">TWINT</span>
-              </Button>
+              {allowsMethod("twint") && (
+                <Button
+                  className="flex h-24 flex-col items-center justify-center gap-2 rounded-xl sm:h-36"
+                  variant="outline"
+                  onClick={() => {
+                    setError(null)
+                    setTender("twint")
+                  }}
+                  aria-label="Mit TWINT bezahlen"
+                >
+                  <QrCode className="size-8 sm:size-12" />
+                  <span className="text-base font-medium">TWINT</span>
+                </Button>
+              )}
             </div>
           )}
 
-          {tender === null && (
+          {tender === null && (!gratisTypes || gratisTypes.length > 0) && (
             <div className="mt-2 flex justify-end">
               <Button
                 variant="outline"
@@ -1384,7 +1406,12 @@ export function BasketPanel({ token, mode = "QR_CODE", submitOrder, stockMap }:
       </Dialog>
 
       {/* Gratis type selection dialog */}
-      <GratisTypeDialog open={showGratisDialog} onOpenChange={setShowGratisDialog} onSelect={handleGratisTypeSelect} />
+      <GratisTypeDialog
+        open={showGratisDialog}
+        onOpenChange={setShowGratisDialog}
+        onSelect={handleGratisTypeSelect}
+        types={gratisTypes}
+      />
 
       {/* 100 Club member picker dialog */}
       <Club100PickerDialog
@@ -1,12 +1,13 @@
 "use client"
 
-import { Search } from "lucide-react"
-import { useCallback, useEffect, useMemo, useState } from "react"
+import { QrCode, Search } from "lucide-react"
+import { useCallback, useEffect, useRef, useState } from "react"
+import Html5QrcodeScannerPlugin from "@/components/html5-qrcode-scanner-plugin"
 import { Button } from "@/components/ui/button"
 import { Dialog, DialogContent, DialogFooter, DialogHeader, DialogTitle } from "@/components/ui/dialog"
 import { Input } from "@/components/ui/input"
-import { type Club100Person, listClub100People } from "@/lib/api/club100"
-import { formatChf } from "@/lib/utils"
+import { type Club100Person, listClub100People, lookupClub100Card } from "@/lib/api/club100"
+import { formatChf, itemName } from "@/lib/utils"
 import type { CartItem } from "@/types/cart"
 import type { Club100Discount, Club100DiscountItem } from "@/types/order-queue"
 
@@ -31,33 +32,43 @@ export function Club100PickerDialog({
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState<string | null>(null)
   const [search, setSearch] = useState("")
+  const [scanning, setScanning] = useState(false)
+  const [cardError, setCardError] = useState<string | null>(null)
+  const lookupBusy = useRef(false)
 
-  const loadPeople = useCallback(async () => {
-    if (!token) return
-    setLoading(true)
-    setError(null)
-    try {
-      const result = await listClub100People(token)
-      setPeople(result)
-    } catch (e) {
-      setError(e instanceof Error ? e.message : "Fehler beim Laden")
-    } finally {
-      setLoading(false)
-    }
-  }, [token])
+  const loadPeople = useCallback(
+    async (query: string) => {
+      if (!token) return
+      setLoading(true)
+      setError(null)
+      try {
+        const result = await listClub100People(token, query)
+        setPeople(result)
+      } catch (e) {
+        setError(e instanceof Error ? e.message : "Fehler beim Laden")
+      } finally {
+        setLoading(false)
+      }
+    },
+    [token]
+  )
 
   useEffect(() => {
     if (open) {
-      loadPeople()
       setSearch("")
+      setCardError(null)
+    } else {
+      setScanning(false)
     }
-  }, [open, loadPeople])
+  }, [open])
 
-  const filtered = useMemo(() => {
-    if (!search.trim()) return people
-    const q = search.toLowerCase()
-    return people.filter((p) => p.firstName.toLowerCase().includes(q) || p.lastName.toLowerCase().includes(q))
-  }, [people, search])
+  // The backend ranks typos and accents ("Muller" finds Müller); debounce so
+  // fast typing doesn't fire a request per key.
+  useEffect(() => {
+    if (!open) return
+    const timer = setTimeout(() => void loadPeople(search), search.trim() ? 200 : 0)
+    return () => clearTimeout(timer)
+  }, [open, search, loadPeople])
 
   const calculateDiscount = useCallback(
     (person: Club100Person): Club100Discount | null => {
@@ -82,7 +93,7 @@ export function Club100PickerDialog({
         discountedItems.push({
           cartItemId: item.id,
           productId: item.product.id,
-          productName: item.product.name,
+          productName: itemName(item.product.name, item.variant?.name),
           quantity: item.quantity,
           discountedQuantity,
           unitPriceCents: item.totalPriceCents,
@@ -129,6 +140,38 @@ export function Club100PickerDialog({
     [calculateDiscount, onSelect]
   )
 
+  // Member cards carry a signed "BFS1.M." code. Camera scans and hardware
+  // scanners (which type into the search field and press Enter) both end up
+  // here.
+  const handleCardScan = useCallback(
+    async (raw: string) => {
+      if (lookupBusy.current || !token) return
+      lookupBusy.current = true
+      setCardError(null)
+      try {
+        const result = await lookupClub100Card(token, raw.trim())
+        setScanning(false)
+        if (result.person.remaining <= 0) {
+          setCardError(`${result.person.firstName} ${result.person.lastName} hat keine Gutschriften mehr`)
+          return
+        }
+        handleSelect(result.person)
+      } catch (e) {
+        setCardError(e instanceof Error ? e.message : "Karte konnte nicht gelesen werden")
+      } finally {
+        lookupBusy.current = false
+      }
+    },
+    [token, handleSelect]
+  )
+
+  // The scanner restarts the camera whenever its callbacks change.
+  const onScan = useCallback((text: string) => void handleCardScan(text), [handleCardScan])
+  const onScanStartError = useCallback(() => {
+    setScanning(false)
+    setCardError("Kamera konnte nicht gestartet werden")
+  }, [])
+
   return (
     <Dialog open={open} onOpenChange={onOpenChange}>
       <DialogContent className="flex max-h-[80vh] flex-col" onOpenAutoFocus={(e) => e.preventDefault()}>
@@ -136,25 +179,53 @@ export function Club100PickerDialog({
           <DialogTitle>100 Club Mitglied wählen</DialogTitle>
         </DialogHeader>
 
-        <div className="relative">
-          <Search className="text-muted-foreground absolute top-1/2 left-3 size-4 -translate-y-1/2" />
-          <Input
-            placeholder="Nach Name suchen..."
-            value={search}
-            onChange={(e) => setSearch(e.target.value)}
-            className="pl-9"
-          />
+        <div className="flex gap-2">
+          <div className="relative flex-1">
+            <Search className="text-muted-foreground absolute top-1/2 left-3 size-4 -translate-y-1/2" />
+            <Input
+              placeholder="Nach Name suchen..."
+              value={search}
+              onChange={(e) => setSearch(e.target.value)}
+              onKeyDown={(e) => {
+                if (e.key === "Enter" && search.trim().startsWith("BFS1.M.")) {
+                  e.preventDefault()
+                  const raw = search
+                  setSearch("")
+                  void handleCardScan(raw)
+                }
+              }}
+              className="pl-9"
+            />
+          </div>
+          <Button
+            variant={scanning ? "default" : "outline"}
+            onClick={() => setScanning((s) => !s)}
+            aria-label="Mitgliederkarte scannen"
+          >
+            <QrCode className="size-4" />
+            Karte
+          </Button>
         </div>
 
+        {scanning && (
+          <Html5QrcodeScannerPlugin
+            fps={10}
+            maxWidthRem={18}
+            qrCodeSuccessCallback={onScan}
+            onStartError={onScanStartError}
+          />
+        )}
+        {cardError && <div className="text-center text-sm text-red-600">{cardError}</div>}
+
         <div className="min-h-0 flex-1 space-y-2 overflow-y-auto py-2">
           {loading && <div className="text-muted-foreground py-4 text-center">Laden...</div>}
           {error && <div className="py-4 text-center text-red-600">{error}</div>}
-          {!loading && !error && filtered.length === 0 && (
+          {!loading && !error && people.length === 0 && (
             <div className="text-muted-foreground py-4 text-center">Keine Mitglieder gefunden</div>
           )}
           {!loading &&
             !error &&
-            filtered.map((person) => {
+            people.map((person) => {
               const noRedemptionsLeft = person.remaining <= 0
               const preview = getPersonPreview(person)
               const noEligibleProducts = preview.eligibleCount === 0 && !noRedemptionsLeft
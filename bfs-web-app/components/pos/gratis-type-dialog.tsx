@@ -10,9 +10,12 @@ interface GratisTypeDialogProps {
   open: boolean
   onOpenChange: (open: boolean) => void
   onSelect: (type: GratisType) => void
+  // Types the device may use; all when unset.
+  types?: GratisType[]
 }
 
-export function GratisTypeDialog({ open, onOpenChange, onSelect }: GratisTypeDialogProps) {
+export function GratisTypeDialog({ open, onOpenChange, onSelect, types }: GratisTypeDialogProps) {
+  const allows = (t: GratisType) => !types || types.includes(t)
   return (
     <Dialog open={open} onOpenChange={onOpenChange}>
       <DialogContent>
@@ -20,42 +23,50 @@ export function GratisTypeDialog({ open, onOpenChange, onSelect }: GratisTypeDia
           <DialogTitle>Gratis-Typ wählen</DialogTitle>
         </DialogHeader>
         <div className="grid grid-cols-2 gap-3">
-          <Button
-            className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
-            variant="outline"
-            onClick={() => onSelect("guest")}
-            aria-label="Gäste"
-          >
-            <Users className="size-10" />
-            <span className="text-base font-medium">Gäste</span>
-          </Button>
-          <Button
-            className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
-            variant="outline"
-            onClick={() => onSelect("vip")}
-            aria-label="VIP"
-          >
-            <Star className="size-10" />
-            <span className="text-base font-medium">VIP</span>
-          </Button>
-          <Button
-            className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
-            variant="outline"
-            onClick={() => onSelect("staff")}
-            aria-label="Mitarbeiter"
-          >
-            <Handshake className="size-10" />
-            <span className="text-base font-medium">Mitarbeiter</span>
-          </Button>
-          <Button
-            className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
-            variant="outline"
-            onClick={() => onSelect("100club")}
-            aria-label="100 Club"
-          >
-            <Crown className="size-10" />
-            <span className="text-base font-medium">100 Club</span>
-          </Button>
+          {allows("guest") && (
+            <Button
+              className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
+              variant="outline"
+              onClick={() => onSelect("guest")}
+              aria-label="Gäste"
+            >
+              <Users className="size-10" />
+              <span className="text-base font-medium">Gäste</span>
+            </Button>
+          )}
+          {allows("vip") && (
+            <Button
+              className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
+              variant="outline"
+              onClick={() => onSelect("vip")}
+              aria-label="VIP"
+            >
+              <Star className="size-10" />
+              <span className="text-base font-medium">VIP</span>
+            </Button>
+          )}
+          {allows("staff") && (
+            <Button
+              className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
+              variant="outline"
+              onClick={() => onSelect("staff")}
+              aria-label="Mitarbeiter"
+            >
+              <Handshake className="size-10" />
+              <span className="text-base font-medium">Mitarbeiter</span>
+            </Button>
+          )}
+          {allows("100club") && (
+            <Button
+              className="flex h-28 flex-col items-center justify-center gap-2 rounded-xl"
+              variant="outline"
+              onClick={() => onSelect("100club")}
+              aria-label="100 Club"
+            >
+              <Crown className="size-10" />
+              <span className="text-base font-medium">100 Club</span>
+            </Button>
+          )}
         </div>
       </DialogContent>
     </Dialog>
@@ -7,20 +7,25 @@ import { Button } from "@/components/ui/button"
 import { Input } from "@/components/ui/input"
 import { Switch } from "@/components/ui/switch"
 import { setProductActive } from "@/lib/api/products"
-import { cn } from "@/lib/utils"
+import { activeVariants, cn } from "@/lib/utils"
 import type { ListResponse, ProductDTO } from "@/types"
 
+// StockControl adjusts the stock of a product, or of one of its variants when
+// variantId is set; updates are reported under the adjusted ID.
 function StockControl({
   product,
+  variantId,
   currentStock,
   token,
   onStockUpdated,
 }: {
   product: ProductDTO
+  variantId?: string
   currentStock: number
   token: string
   onStockUpdated: (productId: string, newStock: number) => void
 }) {
+  const stockId = variantId ?? product.id
   const [localStock, setLocalStock] = useState(String(currentStock))
   const [saving, setSaving] = useState(false)
   const pendingRef = useRef<AbortController | null>(null)
@@ -31,13 +36,16 @@ function StockControl({
 
       const optimistic = Math.max(0, parseInt(localStock, 10) + delta)
       setLocalStock(String(optimistic))
-      onStockUpdated(product.id, optimistic)
+      onStockUpdated(stockId, optimistic)
 
       const controller = new AbortController()
       pendingRef.current = controller
       setSaving(true)
       try {
-        const res = await fetch(`/api/v1/pos/products/${product.id}/inventory`, {
+        const path = variantId
+          ? `/api/v1/pos/products/${product.id}/variants/${variantId}/inventory`
+          : `/api/v1/pos/products/${product.id}/inventory`
+        const res = await fetch(path, {
           method: "PATCH",
           headers: {
             Authorization: `Bearer ${token}`,
@@ -49,20 +57,20 @@ function StockControl({
         if (res.ok) {
           const data = (await res.json()) as { quantity: number }
           setLocalStock(String(data.quantity))
-          onStockUpdated(product.id, data.quantity)
+          onStockUpdated(stockId, data.quantity)
         } else {
           setLocalStock(String(currentStock))
-          onStockUpdated(product.id, currentStock)
+          onStockUpdated(stockId, currentStock)
         }
       } catch (e) {
         if (e instanceof DOMException && e.name === "AbortError") return
         setLocalStock(String(currentStock))
-        onStockUpdated(product.id, currentStock)
+        onStockUpdated(stockId, currentStock)
       } finally {
         setSaving(false)
       }
     },
-    [product.id, token, onStockUpdated, localStock, currentStock]
+    [product.id, variantId, stockId, token, onStockUpdated, localStock, currentStock]
   )
 
   const handleAbsoluteSet = useCallback(async () => {
@@ -163,6 +171,7 @@ const ItemCard = memo(function ItemCard({
   const isActive = product.isActive !== false
   const isMenu = product.type === "menu"
   const stock = product.availableQuantity ?? 0
+  const variants = activeVariants(product)
 
   return (
     <div className={cn("flex items-center gap-4 rounded-xl border p-3", !isActive && "opacity-60")}>
@@ -189,11 +198,25 @@ const ItemCard = memo(function ItemCard({
         <div className="flex flex-wrap items-center justify-between gap-2">
           {isMenu ? (
             <span className="text-muted-foreground text-xs">Menü</span>
+          ) : variants.length > 0 ? (
+            <span className="text-muted-foreground text-xs">Total {stock}</span>
           ) : (
             <StockControl product={product} currentStock={stock} token={token} onStockUpdated={onStockUpdated} />
           )}
           <ActiveToggle product={product} token={token} onActiveUpdated={onActiveUpdated} />
         </div>
+        {variants.map((v) => (
+          <div key={v.id} className="flex items-center justify-between gap-2">
+            <span className="text-sm">{v.name}</span>
+            <StockControl
+              product={product}
+              variantId={v.id}
+              currentStock={v.stock ?? 0}
+              token={token}
+              onStockUpdated={onStockUpdated}
+            />
+          </div>
+        ))}
       </div>
     </div>
   )
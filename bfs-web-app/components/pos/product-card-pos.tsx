@@ -2,9 +2,12 @@
 
 import { Plus } from "lucide-react"
 import Image from "next/image"
+import { useState } from "react"
+import { VariantPickerModal } from "@/components/cart/variant-picker-modal"
 import { Button } from "@/components/ui/button"
 import { Card, CardContent, CardHeader } from "@/components/ui/card"
 import { useCart } from "@/contexts/cart-context"
+import { activeVariants, formatAvailableFrom } from "@/lib/utils"
 import type { ProductDTO } from "@/types"
 
 function formatPriceLabel(cents: number): string {
@@ -17,18 +20,24 @@ function formatPriceLabel(cents: number): string {
 
 export function ProductCardPOS({ product, onConfigure }: { product: ProductDTO; onConfigure: () => void }) {
   const { addToCart, getTotalProductQuantity } = useCart()
+  const [isVariantPickerOpen, setIsVariantPickerOpen] = useState(false)
+  const variants = activeVariants(product)
+  const hasVariants = variants.length > 0
   const isMenu = product.type === "menu"
   const isAvailable = isMenu || product.isAvailable !== false
   const isLowStock = !isMenu && product.isLowStock === true
   const availableQty = isMenu ? null : (product.availableQuantity ?? null)
   const isActive = product.isActive !== false
   const cartQuantity = getTotalProductQuantity(product.id)
-  const atMaxInventory = !isMenu && availableQty !== null && cartQuantity >= availableQty
-  const disabled = !isAvailable || !isActive || atMaxInventory
+  // Variants are capped in the picker; the product's stock is their total.
+  const atMaxInventory = !isMenu && !hasVariants && availableQty !== null && cartQuantity >= availableQty
+  const inWindow = product.availableNow !== false
+  const disabled = !isAvailable || !isActive || !inWindow || atMaxInventory
 
   const handleAdd = () => {
     if (disabled) return
     if (product.type === "menu") onConfigure()
+    else if (hasVariants) setIsVariantPickerOpen(true)
     else addToCart(product)
   }
 
@@ -40,69 +49,94 @@ export function ProductCardPOS({ product, onConfigure }: { product: ProductDTO;
   }
 
   return (
-    <Card
-      role="button"
-      tabIndex={disabled ? -1 : 0}
-      aria-disabled={disabled}
-      onClick={handleAdd}
-      onKeyDown={onCardKeyDown}
-      className={
-        "gap-0 overflow-hidden rounded-[11px] p-0 transition-shadow hover:shadow-lg " +
-        (disabled ? "" : "cursor-pointer")
-      }
-    >
-      <CardHeader className="p-2">
-        <div className="relative aspect-video rounded-[11px] rounded-t-lg bg-[#cec9c6]">
-          {product.image ? (
-            <Image
-              src={product.image}
-              alt={"Produktbild von " + product.name}
-              fill
-              sizes="(max-width: 640px) 50vw, (max-width: 1024px) 33vw, 25vw"
-              quality={90}
-              className="h-full w-full rounded-[11px] object-cover"
-              unoptimized={product.image.includes("localhost") || product.image.includes("127.0.0.1")}
-            />
-          ) : (
-            <div className="absolute inset-0 flex items-center justify-center text-zinc-500">Kein Bild</div>
-          )}
-          {!isAvailable && (
-            <div className="absolute inset-0 z-10 grid place-items-center rounded-[11px] bg-black/55">
-              <span className="rounded-full bg-red-400 px-3 py-1 text-sm font-medium text-white">Ausverkauft</span>
+    <>
+      <Card
+        role="button"
+        tabIndex={disabled ? -1 : 0}
+        aria-disabled={disabled}
+        onClick={handleAdd}
+        onKeyDown={onCardKeyDown}
+        className={
+          "gap-0 overflow-hidden rounded-[11px] p-0 transition-shadow hover:shadow-lg " +
+          (disabled ? "" : "cursor-pointer")
+        }
+      >
+        <CardHeader className="p-2">
+          <div className="relative aspect-video rounded-[11px] rounded-t-lg bg-[#cec9c6]">
+            {product.image ? (
+              <Image
+                src={product.image}
+                alt={"Produktbild von " + product.name}
+                fill
+                sizes="(max-width: 640px) 50vw, (max-width: 1024px) 33vw, 25vw"
+                quality={90}
+                className="h-full w-full rounded-[11px] object-cover"
+                unoptimized={product.image.includes("localhost") || product.image.includes("127.0.0.1")}
+              />
+            ) : (
+              <div className="absolute inset-0 flex items-center justify-center text-zinc-500">Kein Bild</div>
+            )}
+            {!isAvailable && (
+              <div className="absolute inset-0 z-10 grid place-items-center rounded-[11px] bg-black/55">
+                <span className="rounded-full bg-red-400 px-3 py-1 text-sm font-medium text-white">Ausverkauft</span>
+              </div>
+            )}
+            {isAvailable && !inWindow && (
+              <div className="absolute inset-0 z-10 grid place-items-center rounded-[11px] bg-black/55">
+                <span className="rounded-full bg-zinc-700 px-3 py-1 text-sm font-medium text-white">
+                  {formatAvailableFrom(product.nextAvailableAt)}
+                </span>
+              </div>
+            )}
+            {availableQty !== null && isAvailable && isActive && inWindow && (
+              <div className="absolute top-1 left-2 z-10">
+                <span
+                  className={`rounded-full px-2 py-0.5 text-xs font-medium text-white ${isLowStock ? "bg-amber-600" : "bg-zinc-600"}`}
+                >
+                  {availableQty} übrig
+                </span>
+              </div>
+            )}
+          </div>
+        </CardHeader>
+        <CardContent className="px-2 pt-0 pb-4">
+          <div className="flex items-center justify-between">
+            <div className="flex flex-col">
+              <h3 className="font-family-secondary text-base">{product.name}</h3>
+              <p className="font-family-secondary text-sm">
+                {hasVariants
+                  ? variants.map((v) => `${v.name} ${formatPriceLabel(v.priceCents)}`).join(" · ")
+                  : formatPriceLabel(product.priceCents)}
+                {!hasVariants && product.listPriceCents != null && (
+                  <span className="text-muted-foreground ml-2 line-through">
+                    {formatPriceLabel(product.listPriceCents)}
+                  </span>
+                )}
+              </p>
             </div>
-          )}
-          {availableQty !== null && isAvailable && isActive && (
-            <div className="absolute top-1 left-2 z-10">
-              <span
-                className={`rounded-full px-2 py-0.5 text-xs font-medium text-white ${isLowStock ? "bg-amber-600" : "bg-zinc-600"}`}
+            <div className="flex items-center">
+              <Button
+                size="icon"
+                onClick={(e) => {
+                  e.stopPropagation()
+                  handleAdd()
+                }}
+                aria-label={`Produkt ${product.name} hinzufügen`}
+                className="bg-foreground hover:bg-foreground/90 rounded-[10px] text-white"
               >
-                {availableQty} übrig
-              </span>
+                <Plus className="size-5" />
+              </Button>
             </div>
-          )}
-        </div>
-      </CardHeader>
-      <CardContent className="px-2 pt-0 pb-4">
-        <div className="flex items-center justify-between">
-          <div className="flex flex-col">
-            <h3 className="font-family-secondary text-base">{product.name}</h3>
-            <p className="font-family-secondary text-sm">{formatPriceLabel(product.priceCents)}</p>
-          </div>
-          <div className="flex items-center">
-            <Button
-              size="icon"
-              onClick={(e) => {
-                e.stopPropagation()
-                handleAdd()
-              }}
-              aria-label={`Produkt ${product.name} hinzufügen`}
-              className="bg-foreground hover:bg-foreground/90 rounded-[10px] text-white"
-            >
-              <Plus className="size-5" />
-            </Button>
           </div>
-        </div>
-      </CardContent>
-    </Card>
+        </CardContent>
+      </Card>
+      {hasVariants && (
+        <VariantPickerModal
+          product={product}
+          isOpen={isVariantPickerOpen}
+          onClose={() => setIsVariantPickerOpen(false)}
+        />
+      )}
+    </>
   )
 }
@@ -1,7 +1,7 @@
 "use client"
 
 import React, { createContext, ReactNode, useContext, useEffect, useReducer } from "react"
-import { ProductDTO } from "@/types"
+import { ProductDTO, ProductVariant } from "@/types"
 import { Cart, CartContextType, CartItem, CartItemConfiguration } from "@/types/cart"
 
 const CART_STORAGE_KEY = "bfs-cart"
@@ -40,21 +40,22 @@ function loadCartFromStorage(): Cart | null {
 }
 
 type CartAction =
-  | { type: "ADD_TO_CART"; product: ProductDTO; configuration?: CartItemConfiguration }
+  | { type: "ADD_TO_CART"; product: ProductDTO; configuration?: CartItemConfiguration; variant?: ProductVariant }
   | { type: "REMOVE_FROM_CART"; itemId: string }
   | { type: "UPDATE_QUANTITY"; itemId: string; quantity: number }
   | { type: "REPLACE_ITEM"; oldItemId: string; product: ProductDTO; configuration?: CartItemConfiguration }
   | { type: "CLEAR_CART" }
   | { type: "LOAD_FROM_STORAGE"; cart: Cart }
 
-function generateCartItemId(product: ProductDTO, configuration?: CartItemConfiguration): string {
+function generateCartItemId(product: ProductDTO, configuration?: CartItemConfiguration, variantId?: string): string {
   const configStr = configuration ? JSON.stringify(configuration) : ""
-  return `${product.id}-${configStr}`
+  const productKey = variantId ? `${product.id}:${variantId}` : product.id
+  return `${productKey}-${configStr}`
 }
 
-function calculateItemPrice(product: ProductDTO): number {
-  // All products (both simple and menu) use their defined priceCents
-  return product.priceCents
+function calculateItemPrice(product: ProductDTO, variant?: ProductVariant): number {
+  // Variants carry their own price; otherwise products (both simple and menu) use their defined priceCents
+  return variant ? variant.priceCents : product.priceCents
 }
 
 function cartReducer(state: Cart, action: CartAction): Cart {
@@ -62,7 +63,7 @@ function cartReducer(state: Cart, action: CartAction): Cart {
 
   switch (action.type) {
     case "ADD_TO_CART": {
-      const itemId = generateCartItemId(action.product, action.configuration)
+      const itemId = generateCartItemId(action.product, action.configuration, action.variant?.id)
       const existingItemIndex = state.items.findIndex((item) => item.id === itemId)
 
       if (existingItemIndex >= 0) {
@@ -72,6 +73,7 @@ function cartReducer(state: Cart, action: CartAction): Cart {
           updatedItems[existingItemIndex] = {
             id: existingItem.id,
             product: existingItem.product,
+            variant: existingItem.variant,
             configuration: existingItem.configuration,
             totalPriceCents: existingItem.totalPriceCents,
             quantity: existingItem.quantity + 1,
@@ -92,9 +94,10 @@ function cartReducer(state: Cart, action: CartAction): Cart {
       const newItem: CartItem = {
         id: itemId,
         product: action.product,
+        variant: action.variant,
         quantity: 1,
         configuration: action.configuration,
-        totalPriceCents: calculateItemPrice(action.product),
+        totalPriceCents: calculateItemPrice(action.product, action.variant),
       }
 
       const updatedItems = [...state.items, newItem]
@@ -230,8 +233,8 @@ export function CartProvider({ children }: { children: ReactNode }) {
     }
   }, [])
 
-  const addToCart = (product: ProductDTO, configuration?: CartItemConfiguration) => {
-    dispatch({ type: "ADD_TO_CART", product, configuration })
+  const addToCart = (product: ProductDTO, configuration?: CartItemConfiguration, variant?: ProductVariant) => {
+    dispatch({ type: "ADD_TO_CART", product, configuration, variant })
   }
 
   const updateItemConfiguration = (oldItemId: string, product: ProductDTO, configuration?: CartItemConfiguration) => {
@@ -250,8 +253,8 @@ export function CartProvider({ children }: { children: ReactNode }) {
     dispatch({ type: "CLEAR_CART" })
   }
 
-  const getItemQuantity = (productId: string, configuration?: CartItemConfiguration): number => {
-    const itemId = generateCartItemId({ id: productId } as ProductDTO, configuration)
+  const getItemQuantity = (productId: string, configuration?: CartItemConfiguration, variantId?: string): number => {
+    const itemId = generateCartItemId({ id: productId } as ProductDTO, configuration, variantId)
     const item = cart.items.find((item) => item.id === itemId)
     return item ? item.quantity : 0
   }
@@ -22,5 +22,5 @@ export function useStockMap(enabled = true): Map<string, number> {
 
 export function getStockCap(item: CartItem, stockMap?: Map<string, number>): number | null {
   if (!stockMap || item.product.type === "menu") return null
-  return stockMap.get(item.product.id) ?? null
+  return stockMap.get(item.variant?.id ?? item.product.id) ?? null
 }
@@ -0,0 +1,41 @@
+import type { Allergen, DietaryTag } from "@/types"
+
+// The 14 allergens of EU and Swiss food law, in the order of the regulation.
+export const ALLERGENS: { key: Allergen; label: string }[] = [
+  { key: "gluten", label: "Gluten" },
+  { key: "crustaceans", label: "Krebstiere" },
+  { key: "eggs", label: "Eier" },
+  { key: "fish", label: "Fisch" },
+  { key: "peanuts", label: "Erdnüsse" },
+  { key: "soybeans", label: "Soja" },
+  { key: "milk", label: "Milch" },
+  { key: "nuts", label: "Schalenfrüchte" },
+  { key: "celery", label: "Sellerie" },
+  { key: "mustard", label: "Senf" },
+  { key: "sesame", label: "Sesam" },
+  { key: "sulphites", label: "Sulfite" },
+  { key: "lupin", label: "Lupinen" },
+  { key: "molluscs", label: "Weichtiere" },
+]
+
+export const DIETARY_TAGS: { key: DietaryTag; label: string }[] = [
+  { key: "vegetarian", label: "Vegetarisch" },
+  { key: "vegan", label: "Vegan" },
+  { key: "halal", label: "Halal" },
+  { key: "kosher", label: "Koscher" },
+  { key: "gluten_free", label: "Glutenfrei" },
+  { key: "lactose_free", label: "Laktosefrei" },
+]
+
+// "Gluten, Milch" for display; empty when there are none.
+export function allergenLabels(keys?: string[] | null): string {
+  if (!keys || keys.length === 0) return ""
+  return ALLERGENS.filter((a) => keys.includes(a.key))
+    .map((a) => a.label)
+    .join(", ")
+}
+
+export function dietaryLabels(keys?: string[] | null): string[] {
+  if (!keys || keys.length === 0) return []
+  return DIETARY_TAGS.filter((t) => keys.includes(t.key)).map((t) => t.label)
+}
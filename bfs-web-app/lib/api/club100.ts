@@ -12,10 +12,64 @@ export interface Club100Remaining {
   elvantoPersonId: string
   remaining: number
   max: number
+  periodName?: string
+  resetsAt?: string
 }
 
-export async function listClub100People(token: string): Promise<Club100Person[]> {
-  const response = await apiRequest<{ items: Club100Person[] }>("/v1/club100/people", {
+export interface Club100Period {
+  id: string
+  name: string
+  startsAt: string
+  endsAt: string
+  active: boolean
+}
+
+export interface Club100PeriodReport {
+  period: Club100Period
+  max: number
+  totalQuantity: number
+  items: { elvantoPersonId: string; elvantoPersonName: string; quantity: number; orders: number }[]
+}
+
+export type MembershipProvider = "elvanto" | "csv" | "fake"
+
+export interface Club100SyncRun {
+  id: string
+  trigger: "scheduled" | "manual"
+  provider: MembershipProvider
+  status: "running" | "succeeded" | "failed"
+  memberCount: number
+  addedCount: number
+  removedCount: number
+  error?: string
+  startedAt: string
+  finishedAt?: string
+}
+
+export interface MembershipUpload {
+  filename: string
+  memberCount: number
+  uploadedAt: string
+}
+
+export interface Club100SyncStatus {
+  provider: MembershipProvider
+  configured: boolean
+  running: boolean
+  memberCount: number
+  intervalSeconds: number
+  groups: string[]
+  tags: string[]
+  lastRun?: Club100SyncRun
+  lastSuccess?: Club100SyncRun
+  lastUpload?: MembershipUpload
+}
+
+// Members come from the backend's local directory; a non-empty query is
+// matched fuzzily there, best match first.
+export async function listClub100People(token: string, query = ""): Promise<Club100Person[]> {
+  const q = query.trim() ? `?q=${encodeURIComponent(query.trim())}` : ""
+  const response = await apiRequest<{ items: Club100Person[] }>(`/v1/club100/people${q}`, {
     headers: {
       Authorization: `Bearer ${token}`,
     },
@@ -30,3 +84,30 @@ export async function getClub100Remaining(token: string, personId: string): Prom
     },
   })
 }
+
+export interface Club100CardLookup {
+  person: Club100Person
+  freeProductIds: string[]
+  periodName?: string
+  resetsAt: string
+}
+
+export interface Club100MemberCard {
+  elvantoPersonId: string
+  firstName: string
+  lastName: string
+  cardId?: string
+  cardIssuedAt?: string
+}
+
+// Resolves a scanned member card to the member and their remaining
+// allowance. Revoked or forged cards are rejected by the backend.
+export async function lookupClub100Card(token: string, qr: string): Promise<Club100CardLookup> {
+  return apiRequest<Club100CardLookup>(`/v1/club100/cards/lookup`, {
+    method: "POST",
+    headers: {
+      Authorization: `Bearer ${token}`,
+    },
+    body: JSON.stringify({ qr }),
+  })
+}
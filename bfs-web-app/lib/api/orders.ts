@@ -37,6 +37,12 @@ export interface OrderLineDTO {
   parentLineId?: string | null
   menuSlotId?: string | null
   menuSlotName?: string | null
+  variantId?: string | null
+  variantName?: string | null
+  priceRuleId?: string | null
+  listPriceCents?: number | null
+  // A menu's include those of the options picked.
+  allergens?: string[] | null
   productImage?: string | null
   productDescription?: string | null
   childLines?: OrderLineDTO[] | null
@@ -59,6 +65,7 @@ export interface PublicOrderDetailsDTO {
   status: OrderStatus
   totalCents: number
   createdAt: string
+  qrPayload?: string
   lines?: OrderLineDTO[]
   payments?: OrderPaymentSummaryDTO[]
 }
@@ -1,10 +1,14 @@
-import type { ListResponse, ProductDTO, ProductSummaryDTO } from "@/types"
+import type { Allergen, DietaryTag, ListResponse, ProductDTO, ProductSummaryDTO } from "@/types"
 import { apiRequest } from "../api"
 
 export interface ListProductsParams {
   categoryId?: string
   limit?: number
   offset?: number
+  // Only products (and menu options) free of these allergens.
+  allergenFree?: Allergen[]
+  // Only products (and menu options) with all of these tags.
+  dietary?: DietaryTag[]
 }
 
 interface RawSlotOption {
@@ -15,6 +19,8 @@ interface RawSlotOption {
   image?: string | null
   description?: string | null
   jeton?: { id: string; name: string; color: string } | null
+  allergens?: Allergen[]
+  dietaryTags?: DietaryTag[]
 }
 
 interface RawMenuSlot {
@@ -59,6 +65,8 @@ function normalizeProduct(raw: RawProduct): ProductDTO {
               isAvailable: true,
               category: null as unknown as ProductSummaryDTO["category"],
               jeton: opt.jeton ? { id: opt.jeton.id, name: opt.jeton.name, color: opt.jeton.color } : undefined,
+              allergens: opt.allergens,
+              dietaryTags: opt.dietaryTags,
             }))
           : null,
       })),
@@ -83,6 +91,14 @@ export async function listProducts(params: ListProductsParams = {}): Promise<Lis
     searchParams.append("offset", params.offset.toString())
   }
 
+  if (params.allergenFree && params.allergenFree.length > 0) {
+    searchParams.append("allergen_free", params.allergenFree.join(","))
+  }
+
+  if (params.dietary && params.dietary.length > 0) {
+    searchParams.append("dietary", params.dietary.join(","))
+  }
+
   const queryString = searchParams.toString()
   const endpoint = `/v1/products${queryString ? `?${queryString}` : ""}`
 
@@ -1,6 +1,35 @@
 import { apiRequest } from "@/lib/api"
 
-type SystemStatus = { enabled: boolean }
+// source says who decides right now: the manual switch, a manual override
+// opening outside the opening hours, or the opening hours themselves.
+export type SystemStatus = {
+  enabled: boolean
+  source?: "manual" | "override" | "schedule"
+  closedMessage?: string
+  opensAt?: string
+  closesAt?: string
+  openUntil?: string
+}
+
+export interface OpeningWindow {
+  label?: string
+  weekdays?: number[]
+  start: string
+  end: string
+}
+
+export interface OpeningException {
+  date: string
+  label: string
+  windows: OpeningWindow[]
+}
+
+export interface OpeningHours {
+  enabled: boolean
+  closedMessage: string
+  windows: OpeningWindow[]
+  exceptions: OpeningException[]
+}
 
 export async function getSystemStatus(): Promise<SystemStatus> {
   try {
@@ -9,3 +38,20 @@ export async function getSystemStatus(): Promise<SystemStatus> {
     return { enabled: true }
   }
 }
+
+// formatOpensAt renders the next opening for customers, e.g. "heute um 11:00"
+// or "Samstag, 4. Juli um 11:00".
+export function formatOpensAt(iso: string, now = new Date()): string {
+  const at = new Date(iso)
+  const time = at.toLocaleTimeString("de-CH", { hour: "2-digit", minute: "2-digit", timeZone: "Europe/Zurich" })
+  const day = (d: Date) => d.toLocaleDateString("de-CH", { timeZone: "Europe/Zurich" })
+  if (day(at) === day(now)) return `heute um ${time}`
+  if (day(at) === day(new Date(now.getTime() + 24 * 60 * 60 * 1000))) return `morgen um ${time}`
+  const date = at.toLocaleDateString("de-CH", {
+    weekday: "long",
+    day: "numeric",
+    month: "long",
+    timeZone: "Europe/Zurich",
+  })
+  return `${date} um ${time}`
+}
@@ -6,8 +6,9 @@ import { ac, adminRole, customerRole } from "./auth/permissions"
 // OTP types supported by the backend
 type OTPType = "sign-in" | "email-verification" | "forget-password"
 
-// Notify Go backend to look up the OTP from the database and send the email
-async function sendOTPEmailViaBackend(email: string, type: OTPType): Promise<void> {
+// Notify Go backend to look up the OTP from the database and send the email.
+// The browser's Accept-Language is passed on so the email is in the user's language.
+async function sendOTPEmailViaBackend(email: string, type: OTPType, acceptLanguage?: string | null): Promise<void> {
   const backendUrl = process.env.BACKEND_INTERNAL_URL || process.env.NEXT_PUBLIC_API_BASE_URL
   if (!backendUrl) {
     throw new Error("BACKEND_INTERNAL_URL or NEXT_PUBLIC_API_BASE_URL is required")
@@ -17,6 +18,7 @@ async function sendOTPEmailViaBackend(email: string, type: OTPType): Promise<voi
     method: "POST",
     headers: {
       "Content-Type": "application/json",
+      ...(acceptLanguage ? { "Accept-Language": acceptLanguage } : {}),
     },
     body: JSON.stringify({ email, type }),
   })
@@ -98,10 +100,14 @@ export const auth = betterAuth({
     emailOTP({
       otpLength: 6,
       expiresIn: 300, // 5 minutes
-      sendVerificationOTP: async ({ email, type }: { email: string; otp: string; type: string }) => {
+      sendVerificationOTP: async (
+        { email, type }: { email: string; otp: string; type: string },
+        ctx?: { headers?: Headers; request?: Request },
+      ) => {
         // OTP is not sent over the network — the backend reads it
         // directly from the verification table by email (identifier)
-        await sendOTPEmailViaBackend(email, type as OTPType)
+        const acceptLanguage = ctx?.headers?.get("accept-language") ?? ctx?.request?.headers.get("accept-language")
+        await sendOTPEmailViaBackend(email, type as OTPType, acceptLanguage)
       },
     }),
 
@@ -34,7 +34,8 @@ export function findBestMenuSuggestion(cart: Cart, allProducts: ProductDTO[]): M
   if (menus.length === 0) return null
 
   // Consider only simple products in cart with available quantity
-  const simpleItems = cart.items.filter((i) => i.product.type === "simple" && i.quantity > 0)
+  // Menu slots take products, not variants, so variant items are left alone.
+  const simpleItems = cart.items.filter((i) => i.product.type === "simple" && !i.variant && i.quantity > 0)
   if (simpleItems.length === 0) return null
 
   let best: MenuSuggestion | null = null
@@ -58,7 +59,7 @@ export function findBestMenuSuggestion(cart: Cart, allProducts: ProductDTO[]): M
     }
 
     if (chosen.length === slots.length) {
-      const sumSimple = chosen.reduce((sum, ch) => sum + ch.cartItem.product.priceCents, 0)
+      const sumSimple = chosen.reduce((sum, ch) => sum + ch.cartItem.totalPriceCents, 0)
       const savings = sumSimple - menu.priceCents
       if (savings >= 0) {
         const suggestion: MenuSuggestion = {
@@ -45,6 +45,20 @@ describe("parseScan", () => {
     expect(parseScan(`CAMP:${LEGACY_UUID}`)).toEqual({ kind: "campaign", id: LEGACY_UUID })
   })
 
+  it("parses a signed order payload", () => {
+    const raw = "BFS1.O.HS79U1yH7Zd3.1767225600.q5Jc0uJ3n0m9yS2dH8Zp1A"
+    expect(parseScan(raw)).toEqual({ kind: "order", id: "HS79U1yH7Zd3", payload: raw })
+  })
+
+  it("parses a signed campaign payload", () => {
+    const raw = "BFS1.C.tkn_camp___1.1767225600.q5Jc0uJ3n0m9yS2dH8Zp1A"
+    expect(parseScan(raw)).toEqual({ kind: "campaign", id: "tkn_camp___1", payload: raw })
+  })
+
+  it("rejects signed payloads of types stations cannot redeem", () => {
+    expect(parseScan("BFS1.W.HS79U1yH7Zd3.1767225600.q5Jc0uJ3n0m9yS2dH8Zp1A")).toBeNull()
+  })
+
   it("rejects campaign payloads with invalid tokens", () => {
     expect(parseScan("CAMP:not-an-id")).toBeNull()
   })
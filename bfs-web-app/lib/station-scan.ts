@@ -5,6 +5,9 @@ export const ENTITY_ID_RE = /^[123456789ABCDEFGHKMNPQRSTUVWXYZ_abcdefghkmnpqrstu
 const UUID_RE = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i
 
 const CAMPAIGN_PREFIX = "CAMP:"
+// Signed payloads `BFS1.<type>.<id>.<expiry>.<signature>` are verified by the
+// backend; the client only extracts the id for display and de-duplication.
+const SIGNED_RE = /^BFS1\.([OC])\.([^.]+)\.\d+\.[A-Za-z0-9_-]+$/
 // Order QR codes encode a pickup URL `${origin}/o/<orderId>`; extract the id
 // from the path segment rather than matching a window anywhere in the URL.
 const ORDER_URL_RE =
@@ -14,12 +17,19 @@ function isEntityId(s: string): boolean {
   return ENTITY_ID_RE.test(s) || UUID_RE.test(s)
 }
 
-export type ParsedScan = { kind: "order" | "campaign"; id: string }
+export type ParsedScan = { kind: "order" | "campaign"; id: string; payload?: string }
 
 export function parseScan(raw: string): ParsedScan | null {
   const trimmed = (raw ?? "").trim()
   if (!trimmed) return null
 
+  const signed = trimmed.match(SIGNED_RE)
+  if (signed) {
+    const [, type, id] = signed
+    if (!isEntityId(id)) return null
+    return { kind: type === "C" ? "campaign" : "order", id, payload: trimmed }
+  }
+
   if (trimmed.toUpperCase().startsWith(CAMPAIGN_PREFIX)) {
     const token = trimmed.slice(CAMPAIGN_PREFIX.length).trim()
     return isEntityId(token) ? { kind: "campaign", id: token } : null
@@ -1,5 +1,6 @@
 import { type ClassValue, clsx } from "clsx"
 import { twMerge } from "tailwind-merge"
+import type { ProductVariant } from "@/types"
 
 export function cn(...inputs: ClassValue[]) {
   return twMerge(clsx(inputs))
@@ -17,3 +18,41 @@ export function formatChf(cents: number): string {
   }
   return `CHF ${(cents / 100).toFixed(2)}`
 }
+
+// Active variants of a product, in menu order. Products with any must be
+// ordered through one of them.
+export function activeVariants(product: { variants?: ProductVariant[] }): ProductVariant[] {
+  return (product.variants ?? []).filter((v) => v.isActive).sort((a, b) => a.position - b.position)
+}
+
+// Display name of a cart item or order line, e.g. "Cola 5dl".
+export function itemName(productName: string, variantName?: string | null): string {
+  return variantName ? `${productName} ${variantName}` : productName
+}
+
+// Applies stock levels keyed by variant ID to a product's variants.
+export function withVariantStock<T extends { variants?: ProductVariant[] }>(
+  product: T,
+  stockMap: Map<string, number>
+): T {
+  if (!product.variants?.some((v) => stockMap.has(v.id))) return product
+  return {
+    ...product,
+    variants: product.variants.map((v) => {
+      const stock = stockMap.get(v.id)
+      return stock === undefined ? v : { ...v, stock }
+    }),
+  }
+}
+
+// Label for a product outside its availability windows, e.g. "Ab 17:00" or
+// "Ab Sa 08:00" when it is next orderable on another day.
+export function formatAvailableFrom(nextAvailableAt?: string | null, now: Date = new Date()): string {
+  if (!nextAvailableAt) return "Nicht verfügbar"
+  const next = new Date(nextAvailableAt)
+  const zone = { timeZone: "Europe/Zurich" } as const
+  const time = next.toLocaleTimeString("de-CH", { ...zone, hour: "2-digit", minute: "2-digit" })
+  const sameDay = next.toLocaleDateString("de-CH", zone) === now.toLocaleDateString("de-CH", zone)
+  if (sameDay) return `Ab ${time}`
+  return `Ab ${next.toLocaleDateString("de-CH", { ...zone, weekday: "short" })} ${time}`
+}
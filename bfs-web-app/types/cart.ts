@@ -1,5 +1,5 @@
 import type { Cents } from "./common"
-import type { ProductDTO } from "./index"
+import type { ProductDTO, ProductVariant } from "./index"
 
 export interface CartItemConfiguration {
   [slotId: string]: string // slotId -> selected productId
@@ -8,6 +8,7 @@ export interface CartItemConfiguration {
 export interface CartItem {
   id: string
   product: ProductDTO
+  variant?: ProductVariant
   quantity: number
   configuration?: CartItemConfiguration
   totalPriceCents: Cents
@@ -20,11 +21,11 @@ export interface Cart {
 
 export interface CartContextType {
   cart: Cart
-  addToCart: (product: ProductDTO, configuration?: CartItemConfiguration) => void
+  addToCart: (product: ProductDTO, configuration?: CartItemConfiguration, variant?: ProductVariant) => void
   removeFromCart: (itemId: string) => void
   updateQuantity: (itemId: string, quantity: number) => void
   updateItemConfiguration: (oldItemId: string, product: ProductDTO, configuration?: CartItemConfiguration) => void
   clearCart: () => void
-  getItemQuantity: (productId: string, configuration?: CartItemConfiguration) => number
+  getItemQuantity: (productId: string, configuration?: CartItemConfiguration, variantId?: string) => number
   getTotalProductQuantity: (productId: string) => number
 }
@@ -0,0 +1,22 @@
+export interface CatalogCounts {
+  jetons: number
+  categories: number
+  products: number
+  variants: number
+  slots: number
+}
+
+export interface CatalogProblem {
+  // Line in a CSV file; absent for JSON.
+  line?: number
+  record: string
+  message: string
+}
+
+export interface CatalogImportResult {
+  dryRun: boolean
+  applied: boolean
+  created: CatalogCounts
+  updated: CatalogCounts
+  problems: CatalogProblem[]
+}
@@ -1,9 +1,12 @@
+import type { Translations } from "./common"
+
 export interface Category {
   id: string
   name: string
   isActive: boolean
   // Required zero-based sort position; lower comes first
   position: number
+  translations?: Translations
   createdAt: string // ISO date
   updatedAt: string // ISO date
 }
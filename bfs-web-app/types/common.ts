@@ -4,3 +4,14 @@ export interface ListResponse<T> {
   items: T[]
   count: number
 }
+
+// Languages of the customer-facing texts. German is the catalog's own.
+export type Locale = "de" | "fr" | "en"
+
+export interface TranslatedText {
+  name?: string
+  description?: string | null
+}
+
+// Translations of a catalog name and description. Missing texts fall back to German.
+export type Translations = Partial<Record<Exclude<Locale, "de">, TranslatedText>>
@@ -1,8 +1,16 @@
-export type { Cents, ListResponse } from "./common"
+export type { Cents, ListResponse, Locale, TranslatedText, Translations } from "./common"
 export type { UserRole, User } from "./user"
 export type { Station, StationRequestStatus, StationRequest, StationProduct } from "./station"
 export type { Category, CategoryDTO } from "./category"
-export type { ProductType, Product, ProductSummaryDTO, ProductDTO } from "./product"
+export type {
+  ProductType,
+  Allergen,
+  DietaryTag,
+  Product,
+  ProductVariant,
+  ProductSummaryDTO,
+  ProductDTO,
+} from "./product"
 export type { Menu, MenuSlot, MenuSlotOption, MenuSlotDTO, MenuSlotItem, MenuSlotItemDTO, MenuDTO } from "./menu"
 export type { OrderStatus, Order, OrderItemType, OrderItem } from "./order"
 export type { AdminInviteStatus, AdminInvite } from "./admin"
@@ -1,5 +1,26 @@
 export type PosFulfillmentMode = "QR_CODE" | "JETON" | "HYBRID"
 
+// Effective configuration of a POS device: its overrides merged over the
+// global settings. categoryIds null shows all categories.
+export interface PosDeviceConfig {
+  posMode: PosFulfillmentMode
+  paymentMethods: PosPaymentMethod[]
+  gratisTypes: PosGratisType[]
+  categoryIds: string[] | null
+  overrides: PosDeviceOverrides
+}
+
+export type PosPaymentMethod = "cash" | "card" | "twint"
+export type PosGratisType = "guest" | "vip" | "staff" | "100club"
+
+// Per-device overrides; null inherits the global setting.
+export interface PosDeviceOverrides {
+  posMode?: PosFulfillmentMode | null
+  paymentMethods?: PosPaymentMethod[] | null
+  categoryIds?: string[] | null
+  gratisTypes?: PosGratisType[] | null
+}
+
 export interface Jeton {
   id: string
   name: string
@@ -1,3 +1,4 @@
+import type { Translations } from "./common"
 import type { ProductSummaryDTO } from "./product"
 
 /** Full menu object returned by GET /v1/menus */
@@ -19,6 +20,7 @@ export interface MenuSlot {
   menuProductId: string
   name: string
   sequence: number
+  translations?: Translations
   options: MenuSlotOption[]
 }
 
@@ -42,6 +42,7 @@ export interface Club100Discount {
 
 export interface QueuedOrderItem {
   productId: string
+  variantId?: string
   quantity: number
   menuSelections?: Array<{ slotId: string; productId: string }>
 }
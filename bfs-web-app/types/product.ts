@@ -1,10 +1,28 @@
 import type { CategoryDTO } from "./category"
-import type { Cents } from "./common"
+import type { Cents, Translations } from "./common"
 import type { Jeton } from "./jeton"
 import type { MenuDTO } from "./menu"
 
 export type ProductType = "simple" | "menu"
 
+export type Allergen =
+  | "gluten"
+  | "crustaceans"
+  | "eggs"
+  | "fish"
+  | "peanuts"
+  | "soybeans"
+  | "milk"
+  | "nuts"
+  | "celery"
+  | "mustard"
+  | "sesame"
+  | "sulphites"
+  | "lupin"
+  | "molluscs"
+
+export type DietaryTag = "vegetarian" | "vegan" | "halal" | "kosher" | "gluten_free" | "lactose_free"
+
 export interface Product {
   id: string
   categoryId: string
@@ -14,10 +32,31 @@ export interface Product {
   description: string | null
   priceCents: Cents
   isActive: boolean
+  allergens?: Allergen[]
+  dietaryTags?: DietaryTag[]
+  translations?: Translations
   createdAt: string // ISO date
   updatedAt: string // ISO date
 }
 
+// A size or flavour of a simple product with its own price, stock, jeton and
+// stations. Products with active variants are ordered through one of them.
+export interface ProductVariant {
+  id: string
+  productId: string
+  name: string
+  priceCents: Cents
+  jetonId: string | null
+  jeton?: Jeton | null
+  position: number
+  isActive: boolean
+  stationIds?: string[]
+  stock?: number
+  // Set while a price rule (happy hour, clearance) lowers priceCents.
+  listPriceCents?: Cents | null
+  priceRuleId?: string | null
+}
+
 export interface ProductSummaryDTO {
   id: string
   category: CategoryDTO
@@ -26,11 +65,22 @@ export interface ProductSummaryDTO {
   image: string | null
   description?: string | null
   priceCents: Cents
+  // Set while a price rule (happy hour, clearance) lowers priceCents.
+  listPriceCents?: Cents | null
+  priceRuleId?: string | null
   isActive: boolean
+  // A menu's include those of all its slot options.
+  allergens?: Allergen[]
+  dietaryTags?: DietaryTag[]
+  // False outside the availability windows of the product or its category;
+  // nextAvailableAt is when it can next be ordered.
+  availableNow?: boolean
+  nextAvailableAt?: string | null
   availableQuantity?: number | null
   isAvailable?: boolean
   isLowStock?: boolean
   jeton?: Jeton
+  variants?: ProductVariant[]
 }
 
 export interface ProductDTO extends ProductSummaryDTO {
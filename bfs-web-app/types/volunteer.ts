@@ -10,6 +10,7 @@ export interface VolunteerCampaignSummary {
   status: VolunteerCampaignStatus
   maxRedemptions: number
   redemptionCount: number
+  logoUrl?: string | null
   createdAt: string
   updatedAt: string
 }
@@ -32,6 +33,27 @@ export interface VolunteerCampaignDetail extends VolunteerCampaignSummary {
   redemptions: VolunteerCampaignRedemptionItem[]
 }
 
+export interface VolunteerChoiceOption {
+  productId: string
+  productName: string
+  productImage?: string | null
+}
+
+// "Pick one of these" group: each redemption gets `quantity` of the product
+// the station picks.
+export interface VolunteerChoiceGroup {
+  id: string
+  name: string
+  quantity: number
+  options: VolunteerChoiceOption[]
+}
+
+export interface VolunteerChoiceGroupInput {
+  name: string
+  quantity: number
+  productIds: string[]
+}
+
 export interface ClaimCampaignPublic {
   name: string
   validFrom?: string | null
@@ -39,8 +61,68 @@ export interface ClaimCampaignPublic {
   status: VolunteerCampaignStatus
 }
 
+// Meal window in Europe/Zurich wall-clock time. Weekdays are ISO numbers
+// (1 = Monday … 7 = Sunday).
+export interface VolunteerWindow {
+  id?: string
+  label: string
+  weekdays: number[]
+  start: string
+  end: string
+  maxRedemptions?: number | null
+  maxPerPerson?: number | null
+}
+
+export interface VolunteerSchedule {
+  maxRedemptionsPerDay?: number | null
+  maxPerPersonPerDay?: number | null
+  windows: VolunteerWindow[]
+}
+
+export interface ClaimWindowOccurrence {
+  label: string
+  start: string
+  end: string
+  active: boolean
+}
+
 export interface ClaimCampaignResponse {
   campaign: ClaimCampaignPublic
   products: VolunteerCampaignProductItem[]
+  choiceGroups: VolunteerChoiceGroup[]
   qrPayload: string
+  windows: VolunteerWindow[]
+  nextWindow?: ClaimWindowOccurrence | null
+}
+
+export interface VolunteerToken {
+  id: string
+  name: string
+  email?: string | null
+  token: string
+  maxRedemptions: number
+  redemptionCount: number
+  createdAt: string
+}
+
+export interface VolunteerImportError {
+  line: number
+  message: string
+}
+
+export interface VolunteerImportResult {
+  imported: VolunteerToken[]
+  skipped: number
+  errors: VolunteerImportError[]
+}
+
+export interface VolunteerSlipSummary {
+  issued: number
+  redeemed: number
+  lastSerial: number
+}
+
+export interface IssueVolunteerSlipsResponse extends VolunteerSlipSummary {
+  from: number
+  to: number
 }